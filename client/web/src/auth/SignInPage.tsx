@@ -1,6 +1,6 @@
 import React, { useEffect, useState } from 'react'
 
-import { mdiGithub, mdiGitlab } from '@mdi/js'
+import { mdiBitbucket, mdiGithub, mdiGitlab } from '@mdi/js'
 import classNames from 'classnames'
 import { partition } from 'lodash'
 import { Navigate, useLocation } from 'react-router-dom-v5-compat'
@@ -89,6 +89,11 @@ export const SignInPage: React.FunctionComponent<React.PropsWithChildren<SignInP
                                         <Icon aria-hidden={true} svgPath={mdiGitlab} />{' '}
                                     </>
                                 )}
+                                {provider.serviceType === 'bitbucketCloud' && (
+                                    <>
+                                        <Icon aria-hidden={true} svgPath={mdiBitbucket} />{' '}
+                                    </>
+                                )}
                                 Continue with {provider.displayName}
                             </Button>
                         </div>
@@ -16,14 +16,17 @@ import { PhabricatorIcon } from '@sourcegraph/shared/src/components/icons'
 import { Link, Code, Text } from '@sourcegraph/wildcard'
 
 import awsCodeCommitSchemaJSON from '../../../../../schema/aws_codecommit.schema.json'
+import azureDevOpsSchemaJSON from '../../../../../schema/azuredevops.schema.json'
 import bitbucketCloudSchemaJSON from '../../../../../schema/bitbucket_cloud.schema.json'
 import bitbucketServerSchemaJSON from '../../../../../schema/bitbucket_server.schema.json'
 import gerritSchemaJSON from '../../../../../schema/gerrit.schema.json'
+import giteaSchemaJSON from '../../../../../schema/gitea.schema.json'
 import githubSchemaJSON from '../../../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../../../schema/gitolite.schema.json'
 import goModulesSchemaJSON from '../../../../../schema/go-modules.schema.json'
 import jvmPackagesSchemaJSON from '../../../../../schema/jvm-packages.schema.json'
+import mercurialSchemaJSON from '../../../../../schema/mercurial.schema.json'
 import npmPackagesSchemaJSON from '../../../../../schema/npm-packages.schema.json'
 import otherExternalServiceSchemaJSON from '../../../../../schema/other_external_service.schema.json'
 import pagureSchemaJSON from '../../../../../schema/pagure.schema.json'
@@ -31,6 +34,7 @@ import perforceSchemaJSON from '../../../../../schema/perforce.schema.json'
 import phabricatorSchemaJSON from '../../../../../schema/phabricator.schema.json'
 import pythonPackagesJSON from '../../../../../schema/python-packages.schema.json'
 import rustPackagesJSON from '../../../../../schema/rust-packages.schema.json'
+import subversionSchemaJSON from '../../../../../schema/subversion.schema.json'
 import { ExternalRepositoryFields, ExternalServiceKind } from '../../graphql-operations'
 import { EditorAction } from '../../site-admin/configHelpers'
 import { PerforceIcon } from '../PerforceIcon'
@@ -1256,6 +1260,60 @@ const PAGURE: AddExternalServiceOptions = {
     editorActions: [],
 }
 
+const MERCURIAL: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.MERCURIAL,
+    title: 'Mercurial',
+    icon: GitIcon,
+    jsonSchema: mercurialSchemaJSON,
+    defaultDisplayName: 'Mercurial',
+    defaultConfig: `{
+  "url": "https://hg.example.com",
+  "repos": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to the base URL of your Mercurial repositories.
+                </li>
+                <li>
+                    Add the paths of the repositories relative to <Field>url</Field> to <Field>repos</Field>.
+                </li>
+            </ol>
+            <Text>Sourcegraph converts the history of Mercurial repositories to Git.</Text>
+        </div>
+    ),
+    editorActions: [],
+}
+
+const SUBVERSION: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.SUBVERSION,
+    title: 'Subversion',
+    icon: GitIcon,
+    jsonSchema: subversionSchemaJSON,
+    defaultDisplayName: 'Subversion',
+    defaultConfig: `{
+  "url": "https://svn.example.com/repos",
+  "username": "<username>",
+  "password": "<password>"
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to the base URL of your Subversion repositories.
+                </li>
+                <li>
+                    Optionally, add the paths of the repositories relative to <Field>url</Field> to{' '}
+                    <Field>repos</Field>. By default, every directory directly below <Field>url</Field> is synced.
+                </li>
+            </ol>
+            <Text>Sourcegraph imports the history of Subversion repositories into Git with git svn.</Text>
+        </div>
+    ),
+    editorActions: [],
+}
+
 const GERRIT: AddExternalServiceOptions = {
     kind: ExternalServiceKind.GERRIT,
     title: 'Gerrit',
@@ -1277,6 +1335,68 @@ const GERRIT: AddExternalServiceOptions = {
     editorActions: [],
 }
 
+const GITEA: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.GITEA,
+    title: 'Gitea',
+    icon: GitIcon,
+    jsonSchema: giteaSchemaJSON,
+    defaultDisplayName: 'Gitea',
+    defaultConfig: `{
+  "url": "https://gitea.example.com",
+  "token": "<access token>",
+  "orgs": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to the URL of your Gitea or Forgejo instance.
+                </li>
+                <li>
+                    Create an access token in the user settings of Gitea and set <Field>token</Field> to it.
+                </li>
+                <li>
+                    Add the organizations and users whose repositories to sync to <Field>orgs</Field> and{' '}
+                    <Field>users</Field>.
+                </li>
+            </ol>
+        </div>
+    ),
+    editorActions: [],
+}
+
+const AZURE_DEV_OPS: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.AZUREDEVOPS,
+    title: 'Azure DevOps',
+    icon: GitIcon,
+    jsonSchema: azureDevOpsSchemaJSON,
+    defaultDisplayName: 'Azure DevOps',
+    defaultConfig: `{
+  "url": "https://dev.azure.com",
+  "token": "<personal access token>",
+  "orgs": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to <Code>https://dev.azure.com</Code> for Azure
+                    DevOps Services, or to the URL of your Azure DevOps Server.
+                </li>
+                <li>
+                    Create a personal access token with the <Code>Code (Read)</Code> and{' '}
+                    <Code>Project and Team (Read)</Code> scopes and set <Field>token</Field> to it.
+                </li>
+                <li>
+                    Add the organizations whose repositories to sync to <Field>orgs</Field>, or individual projects to{' '}
+                    <Field>projects</Field>.
+                </li>
+            </ol>
+        </div>
+    ),
+    editorActions: [],
+}
+
 const NPM_PACKAGES: AddExternalServiceOptions = {
     kind: ExternalServiceKind.NPMPACKAGES,
     title: 'npm Dependencies',
@@ -1410,6 +1530,10 @@ export const codeHostExternalServices: Record<string, AddExternalServiceOptions>
     srcservegit: SRC_SERVE_GIT,
     gitolite: GITOLITE,
     git: GENERIC_GIT,
+    gitea: GITEA,
+    azuredevops: AZURE_DEV_OPS,
+    mercurial: MERCURIAL,
+    subversion: SUBVERSION,
     ...(window.context?.experimentalFeatures?.pythonPackages === 'enabled' ? { pythonPackages: PYTHON_PACKAGES } : {}),
     ...(window.context?.experimentalFeatures?.rustPackages === 'enabled' ? { rustPackages: RUST_PACKAGES } : {}),
     ...(window.context?.experimentalFeatures?.goPackages === 'enabled' ? { goModules: GO_MODULES } : {}),
@@ -1440,7 +1564,11 @@ export const defaultExternalServices: Record<ExternalServiceKind, AddExternalSer
     [ExternalServiceKind.AWSCODECOMMIT]: AWS_CODE_COMMIT,
     [ExternalServiceKind.PERFORCE]: PERFORCE,
     [ExternalServiceKind.GERRIT]: GERRIT,
+    [ExternalServiceKind.GITEA]: GITEA,
+    [ExternalServiceKind.AZUREDEVOPS]: AZURE_DEV_OPS,
     [ExternalServiceKind.PAGURE]: PAGURE,
+    [ExternalServiceKind.MERCURIAL]: MERCURIAL,
+    [ExternalServiceKind.SUBVERSION]: SUBVERSION,
     [ExternalServiceKind.GOMODULES]: GO_MODULES,
     [ExternalServiceKind.JVMPACKAGES]: JVM_PACKAGES,
     [ExternalServiceKind.NPMPACKAGES]: NPM_PACKAGES,
@@ -53,14 +53,31 @@ const scopeRequirements: Record<ExternalServiceKind, JSX.Element> = {
             <Code>pipeline:read</Code> permissions.
         </span>
     ),
+    [ExternalServiceKind.GITEA]: (
+        <span>
+            with <Code>write:repository</Code> and <Code>read:user</Code> scopes.
+        </span>
+    ),
+    [ExternalServiceKind.AZUREDEVOPS]: (
+        <span>
+            with <Code>Code (Read &amp; write)</Code> and <Code>Code (Status)</Code> scopes.
+        </span>
+    ),
+    [ExternalServiceKind.GERRIT]: (
+        <span>
+            of an account with the <Code>Push</Code>, <Code>Create Change</Code>, and <Code>Submit</Code> permissions
+            on the projects.
+        </span>
+    ),
 
     // These are just for type completeness and serve as placeholders for a bright future.
-    [ExternalServiceKind.GERRIT]: <span>Unsupported</span>,
     [ExternalServiceKind.GITOLITE]: <span>Unsupported</span>,
     [ExternalServiceKind.GOMODULES]: <span>Unsupported</span>,
     [ExternalServiceKind.PYTHONPACKAGES]: <span>Unsupported</span>,
     [ExternalServiceKind.RUSTPACKAGES]: <span>Unsupported</span>,
+    [ExternalServiceKind.SUBVERSION]: <span>Unsupported</span>,
     [ExternalServiceKind.JVMPACKAGES]: <span>Unsupported</span>,
+    [ExternalServiceKind.MERCURIAL]: <span>Unsupported</span>,
     [ExternalServiceKind.NPMPACKAGES]: <span>Unsupported</span>,
     [ExternalServiceKind.PERFORCE]: <span>Unsupported</span>,
     [ExternalServiceKind.PHABRICATOR]: <span>Unsupported</span>,
@@ -136,7 +153,11 @@ export const AddCredentialModal: React.FunctionComponent<React.PropsWithChildren
     )
 
     const patLabel =
-        externalServiceKind === ExternalServiceKind.BITBUCKETCLOUD ? 'App password' : 'Personal access token'
+        externalServiceKind === ExternalServiceKind.BITBUCKETCLOUD
+            ? 'App password'
+            : externalServiceKind === ExternalServiceKind.GERRIT
+            ? 'HTTP password'
+            : 'Personal access token'
 
     return (
         <Modal onDismiss={onCancel} aria-labelledby={labelId}>
@@ -128,6 +128,7 @@ func AllowAnonymousRequest(req *http.Request) bool {
 		"/.api/gitlab-webhooks",
 		"/.api/bitbucket-server-webhooks",
 		"/.api/bitbucket-cloud-webhooks",
+		"/.api/azure-devops-webhooks",
 	} {
 		if strings.HasPrefix(req.URL.Path, prefix) {
 			return true
@@ -20,6 +20,7 @@ type Services struct {
 	GitLabWebhook             http.Handler
 	BitbucketServerWebhook    http.Handler
 	BitbucketCloudWebhook     http.Handler
+	AzureDevOpsWebhook        http.Handler
 	NewCodeIntelUploadHandler NewCodeIntelUploadHandler
 	NewExecutorProxyHandler   NewExecutorProxyHandler
 	NewGitHubAppSetupHandler  NewGitHubAppSetupHandler
@@ -59,6 +60,7 @@ func DefaultServices() Services {
 		GitLabWebhook:             makeNotFoundHandler("gitlab webhook"),
 		BitbucketServerWebhook:    makeNotFoundHandler("bitbucket server webhook"),
 		BitbucketCloudWebhook:     makeNotFoundHandler("bitbucket cloud webhook"),
+		AzureDevOpsWebhook:        makeNotFoundHandler("azure devops webhook"),
 		NewCodeIntelUploadHandler: func(_ bool) http.Handler { return makeNotFoundHandler("code intel upload") },
 		NewExecutorProxyHandler:   func() http.Handler { return makeNotFoundHandler("executor proxy") },
 		NewGitHubAppSetupHandler:  func() http.Handler { return makeNotFoundHandler("Sourcegraph GitHub App setup") },
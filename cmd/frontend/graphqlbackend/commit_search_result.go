@@ -97,3 +97,9 @@ func (r *CommitSearchResultResolver) ToFileMatch() (*FileMatchResolver, bool)
 func (r *CommitSearchResultResolver) ToCommitSearchResult() (*CommitSearchResultResolver, bool) {
 	return r, true
 }
+func (r *CommitSearchResultResolver) ToOwnerSearchResult() (*OwnerSearchResultResolver, bool) {
+	return nil, false
+}
+func (r *CommitSearchResultResolver) ToCompareSearchResult() (*CompareSearchResultResolver, bool) {
+	return nil, false
+}
@@ -0,0 +1,58 @@
+package graphqlbackend
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+// CompareSearchResultResolver is a resolver for the GraphQL type
+// `CompareSearchResult`, a file whose matched lines differ between the two
+// revisions of a search with rev:base...head.
+type CompareSearchResultResolver struct {
+	result.CompareMatch
+
+	RepoResolver *RepositoryResolver
+}
+
+func (r *CompareSearchResultResolver) Repository() *RepositoryResolver { return r.RepoResolver }
+
+func (r *CompareSearchResultResolver) Path() string { return r.CompareMatch.Path }
+
+func (r *CompareSearchResultResolver) BaseRevision() string { return r.CompareMatch.BaseRev }
+
+func (r *CompareSearchResultResolver) HeadRevision() string { return r.CompareMatch.HeadRev }
+
+func (r *CompareSearchResultResolver) Added() []compareLineResolver {
+	return toCompareLineResolvers(r.CompareMatch.Added)
+}
+
+func (r *CompareSearchResultResolver) Removed() []compareLineResolver {
+	return toCompareLineResolvers(r.CompareMatch.Removed)
+}
+
+func (r *CompareSearchResultResolver) ToRepository() (*RepositoryResolver, bool) { return nil, false }
+func (r *CompareSearchResultResolver) ToFileMatch() (*FileMatchResolver, bool)   { return nil, false }
+func (r *CompareSearchResultResolver) ToCommitSearchResult() (*CommitSearchResultResolver, bool) {
+	return nil, false
+}
+func (r *CompareSearchResultResolver) ToOwnerSearchResult() (*OwnerSearchResultResolver, bool) {
+	return nil, false
+}
+func (r *CompareSearchResultResolver) ToCompareSearchResult() (*CompareSearchResultResolver, bool) {
+	return r, true
+}
+
+func toCompareLineResolvers(lines []result.CompareLine) []compareLineResolver {
+	r := make([]compareLineResolver, 0, len(lines))
+	for _, l := range lines {
+		r = append(r, compareLineResolver{l})
+	}
+	return r
+}
+
+type compareLineResolver struct {
+	result.CompareLine
+}
+
+func (l compareLineResolver) LineNumber() int32 { return int32(l.CompareLine.LineNumber) }
+
+func (l compareLineResolver) Content() string { return l.CompareLine.Content }
@@ -81,6 +81,12 @@ func (fm *FileMatchResolver) ToFileMatch() (*FileMatchResolver, bool)   { return
 func (fm *FileMatchResolver) ToCommitSearchResult() (*CommitSearchResultResolver, bool) {
 	return nil, false
 }
+func (fm *FileMatchResolver) ToOwnerSearchResult() (*OwnerSearchResultResolver, bool) {
+	return nil, false
+}
+func (fm *FileMatchResolver) ToCompareSearchResult() (*CompareSearchResultResolver, bool) {
+	return nil, false
+}
 
 type lineMatchResolver struct {
 	*result.LineMatch
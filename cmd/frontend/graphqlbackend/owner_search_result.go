@@ -0,0 +1,30 @@
+package graphqlbackend
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+// OwnerSearchResultResolver is a resolver for the GraphQL type
+// `OwnerSearchResult`, a code owner of files matched by a search with
+// select:file.owners.
+type OwnerSearchResultResolver struct {
+	result.OwnerMatch
+}
+
+func (r *OwnerSearchResultResolver) Handle() string { return r.OwnerMatch.Handle }
+
+func (r *OwnerSearchResultResolver) OwnerType() string { return r.OwnerMatch.Type }
+
+func (r *OwnerSearchResultResolver) FileCount() int32 { return int32(r.OwnerMatch.FileCount) }
+
+func (r *OwnerSearchResultResolver) ToRepository() (*RepositoryResolver, bool) { return nil, false }
+func (r *OwnerSearchResultResolver) ToFileMatch() (*FileMatchResolver, bool)   { return nil, false }
+func (r *OwnerSearchResultResolver) ToCommitSearchResult() (*CommitSearchResultResolver, bool) {
+	return nil, false
+}
+func (r *OwnerSearchResultResolver) ToOwnerSearchResult() (*OwnerSearchResultResolver, bool) {
+	return r, true
+}
+func (r *OwnerSearchResultResolver) ToCompareSearchResult() (*CompareSearchResultResolver, bool) {
+	return nil, false
+}
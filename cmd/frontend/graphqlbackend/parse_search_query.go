@@ -2,13 +2,23 @@ package graphqlbackend
 
 import (
 	"context"
+	"encoding/json"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/client"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
+	"github.com/sourcegraph/sourcegraph/internal/search/limits"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func (r *schemaResolver) ParseSearchQuery(ctx context.Context, args *struct {
-	Query       string
-	PatternType string
+	Query        string
+	PatternType  string
+	EstimateCost bool
 }) (*JSONValue, error) {
 	var searchType query.SearchType
 	switch args.PatternType {
@@ -18,11 +28,18 @@ func (r *schemaResolver) ParseSearchQuery(ctx context.Context, args *struct {
 		searchType = query.SearchTypeStructural
 	case "regexp", "regex":
 		searchType = query.SearchTypeRegex
+	case "pcre":
+		searchType = query.SearchTypePCRE
 	default:
 		searchType = query.SearchTypeLiteral
 	}
 
-	plan, err := query.Pipeline(query.Init(args.Query, searchType))
+	settings, err := DecodedViewerFinalSettings(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := query.Pipeline(query.InitWithMacros(args.Query, searchType, settings.SearchMacros))
 	if err != nil {
 		return nil, err
 	}
@@ -31,5 +48,56 @@ func (r *schemaResolver) ParseSearchQuery(ctx context.Context, args *struct {
 	if err != nil {
 		return nil, err
 	}
-	return &JSONValue{Value: jsonString}, nil
+	if !args.EstimateCost {
+		return &JSONValue{Value: jsonString}, nil
+	}
+
+	cost, budget, err := r.estimateSearchCost(ctx, args.Query, searchType, settings)
+	if err != nil {
+		return nil, err
+	}
+	withCost, err := json.Marshal(struct {
+		Query  json.RawMessage `json:"query"`
+		Cost   jobutil.Cost    `json:"cost"`
+		Budget int             `json:"budget"`
+	}{
+		Query:  json.RawMessage(jsonString),
+		Cost:   cost,
+		Budget: budget,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &JSONValue{Value: string(withCost)}, nil
+}
+
+// estimateSearchCost returns the estimated cost of searching for searchQuery
+// and the cost budget of the current user, which is zero if unlimited.
+func (r *schemaResolver) estimateSearchCost(ctx context.Context, searchQuery string, searchType query.SearchType, settings *schema.Settings) (jobutil.Cost, int, error) {
+	cli := client.NewSearchClient(r.logger, r.db, search.Indexed(), search.SearcherURLs())
+	patternType := searchType.String()
+	if searchType == query.SearchTypeRegex {
+		// The search client only accepts the "regexp" spelling.
+		patternType = "regexp"
+	}
+	inputs, err := cli.Plan(ctx, "V3", &patternType, searchQuery, search.Batch, settings, envvar.SourcegraphDotComMode())
+	if err != nil {
+		return jobutil.Cost{}, 0, err
+	}
+	planJob, err := jobutil.NewPlanJob(inputs, inputs.Plan)
+	if err != nil {
+		return jobutil.Cost{}, 0, err
+	}
+	cost, err := jobutil.EstimateCost(ctx, cli.JobClients(), planJob)
+	if err != nil {
+		return jobutil.Cost{}, 0, err
+	}
+
+	var username string
+	if a := actor.FromContext(ctx); a.IsAuthenticated() {
+		if user, err := a.User(ctx, r.db.Users()); err == nil && user != nil {
+			username = user.Username
+		}
+	}
+	return cost, limits.CostBudget(limits.SearchLimits(conf.Get()), username), nil
 }
@@ -299,6 +299,12 @@ func (r *RepositoryResolver) ToFileMatch() (*FileMatchResolver, bool)   { return
 func (r *RepositoryResolver) ToCommitSearchResult() (*CommitSearchResultResolver, bool) {
 	return nil, false
 }
+func (r *RepositoryResolver) ToOwnerSearchResult() (*OwnerSearchResultResolver, bool) {
+	return nil, false
+}
+func (r *RepositoryResolver) ToCompareSearchResult() (*CompareSearchResultResolver, bool) {
+	return nil, false
+}
 
 func (r *RepositoryResolver) Type(ctx context.Context) (*types.Repo, error) {
 	return r.repo(ctx)
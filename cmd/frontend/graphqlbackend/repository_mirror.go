@@ -145,6 +145,44 @@ func (r *repositoryMirrorInfoResolver) LastError(ctx context.Context) (*string,
 	return strptr(info.LastError), nil
 }
 
+func (r *repositoryMirrorInfoResolver) PushMirrorLastPushedAt(ctx context.Context) (*DateTime, error) {
+	// 🚨 SECURITY: Only site admins may know about push mirrors, which are
+	// internal infrastructure.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	info, err := r.computeGitserverRepo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.PushMirrorLastPushedAt.IsZero() {
+		return nil, nil
+	}
+
+	return &DateTime{Time: info.PushMirrorLastPushedAt}, nil
+}
+
+func (r *repositoryMirrorInfoResolver) PushMirrorLastError(ctx context.Context) (*string, error) {
+	// 🚨 SECURITY: Only site admins may know about push mirrors, which are
+	// internal infrastructure.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	info, err := r.computeGitserverRepo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.PushMirrorLastError == "" {
+		return nil, nil
+	}
+
+	return &info.PushMirrorLastError, nil
+}
+
 func (r *repositoryMirrorInfoResolver) UpdatedAt(ctx context.Context) (*DateTime, error) {
 	info, err := r.computeGitserverRepo(ctx)
 	if err != nil {
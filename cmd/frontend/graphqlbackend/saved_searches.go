@@ -10,6 +10,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -168,7 +169,11 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 		return nil, errors.New("failed to create saved search: no Org ID or User ID associated with saved search")
 	}
 
-	if !queryHasPatternType(args.Query) {
+	settings, err := DecodedViewerFinalSettings(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	if !queryHasPatternType(args.Query, settings.SearchMacros) {
 		return nil, errMissingPatternType
 	}
 
@@ -219,7 +224,11 @@ func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
 		return nil, errors.New("failed to update saved search: no Org ID or User ID associated with saved search")
 	}
 
-	if !queryHasPatternType(args.Query) {
+	settings, err := DecodedViewerFinalSettings(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	if !queryHasPatternType(args.Query, settings.SearchMacros) {
 		return nil, errMissingPatternType
 	}
 
@@ -271,8 +280,17 @@ func (r *schemaResolver) DeleteSavedSearch(ctx context.Context, args *struct {
 
 var patternType = lazyregexp.New(`(?i)\bpatternType:(literal|regexp|structural|standard)\b`)
 
-func queryHasPatternType(query string) bool {
-	return patternType.Match([]byte(query))
+// queryHasPatternType reports whether q, or one of the query macros it refers
+// to, sets the pattern type.
+func queryHasPatternType(q string, macros map[string]string) bool {
+	if patternType.MatchString(q) {
+		return true
+	}
+	nodes, err := query.ParseWithMacros(q, query.SearchTypeStandard, macros)
+	if err != nil {
+		return false
+	}
+	return patternType.MatchString(query.StringHuman(nodes))
 }
 
 var errMissingPatternType = errors.New("a `patternType:` filter is required in the query for all saved searches. `patternType` can be \"standard\", \"literal\", \"regexp\" or \"structural\"")
@@ -14,6 +14,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestSavedSearches(t *testing.T) {
@@ -140,6 +141,11 @@ func TestCreateSavedSearch(t *testing.T) {
 	ctx := context.Background()
 	key := int32(1)
 
+	MockDecodedViewerFinalSettings = &schema.Settings{
+		SearchMacros: map[string]string{"regexp-diffs": "type:diff patternType:regexp"},
+	}
+	defer func() { MockDecodedViewerFinalSettings = nil }()
+
 	users := database.NewMockUserStore()
 	users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{SiteAdmin: true, ID: key}, nil)
 
@@ -200,11 +206,27 @@ func TestCreateSavedSearch(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error for createSavedSearch when query does not provide a patternType: field.")
 	}
+
+	// Ensure a patternType: field provided by a query macro is accepted.
+	_, err = newSchemaResolver(db).CreateSavedSearch(ctx, &struct {
+		Description string
+		Query       string
+		NotifyOwner bool
+		NotifySlack bool
+		OrgID       *graphql.ID
+		UserID      *graphql.ID
+	}{Description: "test query", Query: "test @regexp-diffs", NotifyOwner: true, NotifySlack: false, OrgID: nil, UserID: &userID})
+	if err != nil {
+		t.Errorf("Expected no error for createSavedSearch when a query macro provides a patternType: field, got %v", err)
+	}
 }
 
 func TestUpdateSavedSearch(t *testing.T) {
 	ctx := context.Background()
 
+	MockDecodedViewerFinalSettings = &schema.Settings{}
+	defer func() { MockDecodedViewerFinalSettings = nil }()
+
 	key := int32(1)
 	users := database.NewMockUserStore()
 	users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{SiteAdmin: true, ID: key}, nil)
@@ -318,6 +340,9 @@ func TestUpdateSavedSearchPermissions(t *testing.T) {
 		errIs:    nil,
 	}}
 
+	MockDecodedViewerFinalSettings = &schema.Settings{}
+	defer func() { MockDecodedViewerFinalSettings = nil }()
+
 	for _, tt := range cases {
 		t.Run("", func(t *testing.T) {
 			ctx := actor.WithActor(context.Background(), actor.FromUser(tt.execUser.ID))
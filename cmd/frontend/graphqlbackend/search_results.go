@@ -165,6 +165,15 @@ func matchesToResolvers(db database.DB, matches []result.Match) []SearchResultRe
 				db:          db,
 				CommitMatch: *v,
 			})
+		case *result.OwnerMatch:
+			resolvers = append(resolvers, &OwnerSearchResultResolver{
+				OwnerMatch: *v,
+			})
+		case *result.CompareMatch:
+			resolvers = append(resolvers, &CompareSearchResultResolver{
+				CompareMatch: *v,
+				RepoResolver: getRepoResolver(v.Repo, ""),
+			})
 		}
 	}
 	return resolvers
@@ -301,8 +310,9 @@ loop:
 	for _, r := range sr.Matches {
 		r := r // shadow so it doesn't change in the goroutine
 		switch m := r.(type) {
-		case *result.RepoMatch:
-			// We don't care about repo results here.
+		case *result.RepoMatch, *result.OwnerMatch, *result.CompareMatch:
+			// We don't care about repo, owner or revision compare results
+			// here.
 			continue
 		case *result.CommitMatch:
 			// Diff searches are cheap, because we implicitly have author date info.
@@ -377,7 +387,7 @@ func LogSearchLatency(ctx context.Context, db database.DB, si *search.Inputs, du
 				types = append(types, "structural")
 			case si.PatternType == query.SearchTypeLiteral:
 				types = append(types, "literal")
-			case si.PatternType == query.SearchTypeRegex:
+			case si.PatternType == query.SearchTypeRegex, si.PatternType == query.SearchTypePCRE:
 				types = append(types, "regexp")
 			case si.PatternType == query.SearchTypeLucky:
 				types = append(types, "lucky")
@@ -658,13 +668,17 @@ func (r *searchResolver) Stats(ctx context.Context) (stats *searchResultsStats,
 //
 // Supported types:
 //
-//   - *RepositoryResolver         // repo name match
-//   - *fileMatchResolver          // text match
-//   - *commitSearchResultResolver // diff or commit match
+//   - *RepositoryResolver          // repo name match
+//   - *fileMatchResolver           // text match
+//   - *commitSearchResultResolver  // diff or commit match
+//   - *OwnerSearchResultResolver   // code owner match
+//   - *CompareSearchResultResolver // revision compare match
 //
 // Note: Any new result types added here also need to be handled properly in search_results.go:301 (sparklines)
 type SearchResultResolver interface {
 	ToRepository() (*RepositoryResolver, bool)
 	ToFileMatch() (*FileMatchResolver, bool)
 	ToCommitSearchResult() (*CommitSearchResultResolver, bool)
+	ToOwnerSearchResult() (*OwnerSearchResultResolver, bool)
+	ToCompareSearchResult() (*CompareSearchResultResolver, bool)
 }
@@ -139,6 +139,7 @@ func (r *settingsCascade) Merged(ctx context.Context) (_ *configurationResolver,
 
 var settingsFieldMergeDepths = map[string]int{
 	"SearchScopes":           1,
+	"SearchMacros":           1,
 	"SearchSavedQueries":     1,
 	"SearchRepositoryGroups": 1,
 	"InsightsDashboards":     1,
@@ -146,6 +146,27 @@ func TestMergeSettings(t *testing.T) {
 				"test3": {"merged", 4},
 			},
 		},
+	}, {
+		name: "deep merge search macros",
+		left: &schema.Settings{
+			SearchMacros: map[string]string{
+				"vendor": "-file:vendor/",
+				"tests":  "-file:_test.go$",
+			},
+		},
+		right: &schema.Settings{
+			SearchMacros: map[string]string{
+				"tests": "-file:test",
+				"go":    "lang:go",
+			},
+		},
+		expected: &schema.Settings{
+			SearchMacros: map[string]string{
+				"vendor": "-file:vendor/",
+				"tests":  "-file:test",
+				"go":     "lang:go",
+			},
+		},
 	}, {
 		name: "deep merge insightsDashboards",
 		left: &schema.Settings{
@@ -79,6 +79,7 @@ const (
 	routeEmbed                   = "embed"
 
 	routeSearchStream  = "search.stream"
+	routeSearchExport  = "search.export"
 	routeSearchConsole = "search.console"
 	routeNotebooks     = "search.notebook"
 
@@ -135,6 +136,7 @@ func newRouter() *mux.Router {
 	r.Path("/search").Methods("GET").Name(routeSearch)
 	r.Path("/search/badge").Methods("GET").Name(routeSearchBadge)
 	r.Path("/search/stream").Methods("GET").Name(routeSearchStream)
+	r.Path("/search/export").Methods("GET").Name(routeSearchExport)
 	r.Path("/search/console").Methods("GET").Name(routeSearchConsole)
 	r.Path("/sign-in").Methods("GET").Name(uirouter.RouteSignIn)
 	r.Path("/sign-up").Methods("GET").Name(uirouter.RouteSignUp)
@@ -306,6 +308,9 @@ func initRouter(db database.DB, router *mux.Router, codeIntelResolver graphqlbac
 	// streaming search
 	router.Get(routeSearchStream).Handler(search.StreamHandler(db))
 
+	// search export
+	router.Get(routeSearchExport).Handler(search.ExportHandler(db))
+
 	// search badge
 	router.Get(routeSearchBadge).Handler(searchBadgeHandler())
 
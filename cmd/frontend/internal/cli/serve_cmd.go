@@ -341,6 +341,7 @@ func makeExternalAPI(db database.DB, schema *graphql.Schema, enterprise enterpri
 			GitLabWebhook:             enterprise.GitLabWebhook,
 			BitbucketServerWebhook:    enterprise.BitbucketServerWebhook,
 			BitbucketCloudWebhook:     enterprise.BitbucketCloudWebhook,
+			AzureDevOpsWebhook:        enterprise.AzureDevOpsWebhook,
 			NewCodeIntelUploadHandler: enterprise.NewCodeIntelUploadHandler,
 			NewComputeStreamHandler:   enterprise.NewComputeStreamHandler,
 		},
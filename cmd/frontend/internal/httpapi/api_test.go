@@ -35,6 +35,7 @@ func newTest(t *testing.T) *httptestutil.Client {
 			GitLabWebhook:             enterpriseServices.GitLabWebhook,
 			BitbucketServerWebhook:    enterpriseServices.BitbucketServerWebhook,
 			BitbucketCloudWebhook:     enterpriseServices.BitbucketCloudWebhook,
+			AzureDevOpsWebhook:        enterpriseServices.AzureDevOpsWebhook,
 			NewCodeIntelUploadHandler: enterpriseServices.NewCodeIntelUploadHandler,
 			NewComputeStreamHandler:   enterpriseServices.NewComputeStreamHandler,
 		},
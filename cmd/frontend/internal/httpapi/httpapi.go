@@ -46,6 +46,7 @@ type Handlers struct {
 	GitLabWebhook             http.Handler
 	BitbucketServerWebhook    http.Handler
 	BitbucketCloudWebhook     http.Handler
+	AzureDevOpsWebhook        http.Handler
 	NewCodeIntelUploadHandler enterprise.NewCodeIntelUploadHandler
 	NewComputeStreamHandler   enterprise.NewComputeStreamHandler
 }
@@ -93,16 +94,32 @@ func NewHandler(
 
 	handlers.GitHubWebhook.Register(&gh)
 
+	// Events the repos handlers below don't register for are passed on to the
+	// Batch Changes webhook handlers.
+	gl := webhooks.NewGitLabWebhook(db.ExternalServices(), handlers.GitLabWebhook)
+	bbs := webhooks.NewBitbucketServerWebhook(db.ExternalServices(), handlers.BitbucketServerWebhook)
+	bbc := webhooks.NewBitbucketCloudWebhook(db.ExternalServices(), handlers.BitbucketCloudWebhook)
+
 	m.Get(apirouter.GitHubWebhooks).Handler(trace.Route(webhookMiddleware.Logger(&gh)))
-	m.Get(apirouter.GitLabWebhooks).Handler(trace.Route(webhookMiddleware.Logger(handlers.GitLabWebhook)))
-	m.Get(apirouter.BitbucketServerWebhooks).Handler(trace.Route(webhookMiddleware.Logger(handlers.BitbucketServerWebhook)))
-	m.Get(apirouter.BitbucketCloudWebhooks).Handler(trace.Route(webhookMiddleware.Logger(handlers.BitbucketCloudWebhook)))
+	m.Get(apirouter.GitLabWebhooks).Handler(trace.Route(webhookMiddleware.Logger(gl)))
+	m.Get(apirouter.BitbucketServerWebhooks).Handler(trace.Route(webhookMiddleware.Logger(bbs)))
+	m.Get(apirouter.BitbucketCloudWebhooks).Handler(trace.Route(webhookMiddleware.Logger(bbc)))
+	m.Get(apirouter.AzureDevOpsWebhooks).Handler(trace.Route(webhookMiddleware.Logger(handlers.AzureDevOpsWebhook)))
 	m.Get(apirouter.LSIFUpload).Handler(trace.Route(handlers.NewCodeIntelUploadHandler(false)))
 	m.Get(apirouter.ComputeStream).Handler(trace.Route(handlers.NewComputeStreamHandler()))
 
 	ghSync := repos.GitHubWebhookHandler{}
 	ghSync.Register(&gh)
 
+	glSync := repos.GitLabWebhookHandler{Repos: db.Repos()}
+	glSync.Register(gl)
+
+	bbsSync := repos.BitbucketServerWebhookHandler{Repos: db.Repos()}
+	bbsSync.Register(bbs)
+
+	bbcSync := repos.BitbucketCloudWebhookHandler{Repos: db.Repos()}
+	bbcSync.Register(bbc)
+
 	if envvar.SourcegraphDotComMode() {
 		m.Path("/updates").Methods("GET", "POST").Name("updatecheck").Handler(trace.Route(http.HandlerFunc(updatecheck.HandlerWithLog(logger))))
 	}
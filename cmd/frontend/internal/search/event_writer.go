@@ -52,6 +52,10 @@ func (e *eventWriter) Error(err error) error {
 }
 
 func (e *eventWriter) Alert(alert *search.Alert) error {
+	return e.inner.Event("alert", fromAlert(alert))
+}
+
+func fromAlert(alert *search.Alert) streamhttp.EventAlert {
 	var pqs []streamhttp.QueryDescription
 	for _, pq := range alert.ProposedQueries {
 		annotations := make([]streamhttp.Annotation, 0, len(pq.Annotations))
@@ -65,10 +69,10 @@ func (e *eventWriter) Alert(alert *search.Alert) error {
 			Annotations: annotations,
 		})
 	}
-	return e.inner.Event("alert", streamhttp.EventAlert{
+	return streamhttp.EventAlert{
 		Title:           alert.Title,
 		Description:     alert.Description,
 		Kind:            alert.Kind,
 		ProposedQueries: pqs,
-	})
+	}
 }
@@ -0,0 +1,223 @@
+package search
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/client"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	streamclient "github.com/sourcegraph/sourcegraph/internal/search/streaming/client"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ExportHandler is an http handler which runs a search and writes all of its
+// results as JSONL or CSV. It accepts the same parameters as StreamHandler,
+// plus "format", but ignores the display limit.
+func ExportHandler(db database.DB) http.Handler {
+	logger := log.Scoped("searchExportHandler", "")
+	return &exportHandler{
+		logger:       logger,
+		db:           db,
+		searchClient: client.NewSearchClient(logger, db, search.Indexed(), search.SearcherURLs()),
+	}
+}
+
+type exportHandler struct {
+	logger       log.Logger
+	db           database.DB
+	searchClient client.SearchClient
+}
+
+func (h *exportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tr, ctx := trace.New(r.Context(), "search.ServeExport", "")
+	defer tr.Finish()
+	r = r.WithContext(ctx)
+
+	args, err := parseURLQuery(r.URL.Query())
+	if err != nil {
+		tr.SetError(err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := streamhttp.ExportJSONL
+	if f := r.URL.Query().Get("format"); f != "" {
+		format, err = streamhttp.ParseExportFormat(f)
+		if err != nil {
+			tr.SetError(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	tr.TagFields(
+		otlog.String("query", args.Query),
+		otlog.String("version", args.Version),
+		otlog.String("pattern_type", args.PatternType),
+		otlog.String("format", string(format)),
+	)
+
+	exportWriter, err := streamhttp.NewExportWriter(w, format)
+	if err != nil {
+		tr.SetError(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Once the writer exists the status code is committed, so errors are
+	// reported in the trailer instead.
+	trailer, err := h.serveHTTP(r, args, exportWriter)
+	if err != nil {
+		tr.SetError(err)
+		trailer.Error = err.Error()
+	}
+	if err := exportWriter.Trailer(trailer); err != nil {
+		h.logger.Warn("failed to write search export trailer", log.Error(err))
+	}
+}
+
+func (h *exportHandler) serveHTTP(r *http.Request, args *args, exportWriter *streamhttp.ExportWriter) (_ streamhttp.EventExportTrailer, err error) {
+	ctx := r.Context()
+	start := time.Now()
+
+	settings, err := graphqlbackend.DecodedViewerFinalSettings(ctx, h.db)
+	if err != nil {
+		return streamhttp.EventExportTrailer{}, err
+	}
+
+	inputs, err := h.searchClient.Plan(ctx, args.Version, strPtr(args.PatternType), args.Query, search.Export, settings, envvar.SourcegraphDotComMode())
+	if err != nil {
+		var queryErr *client.QueryError
+		if errors.As(err, &queryErr) {
+			alert := fromAlert(search.AlertForQuery(queryErr.Query, queryErr.Err))
+			return streamhttp.EventExportTrailer{Alert: &alert}, nil
+		}
+		return streamhttp.EventExportTrailer{}, err
+	}
+
+	// Exports contain every result we find, so the display limit is always
+	// the result limit of the query. Unless the query sets count:, the limit
+	// is the default export limit rather than the one of streaming search.
+	limit := inputs.MaxResults()
+	progress := &streamclient.ProgressAggregator{
+		Start:        start,
+		Limit:        limit,
+		Trace:        trace.URL(trace.ID(ctx), conf.DefaultClient()),
+		DisplayLimit: limit,
+		RepoNamer:    streamclient.RepoNamer(ctx, h.db),
+	}
+
+	sender := &exportSender{
+		ctx:          ctx,
+		logger:       h.logger,
+		db:           h.db,
+		exportWriter: exportWriter,
+		progress:     progress,
+	}
+	batchedStream := streaming.NewBatchingStream(50*time.Millisecond, sender)
+	alert, err := h.searchClient.Execute(ctx, batchedStream, inputs)
+	batchedStream.Done()
+
+	logSearch(ctx, h.logger, alert, err, start, inputs.OriginalQuery, progress)
+
+	trailer := streamhttp.EventExportTrailer{Progress: progress.Final()}
+	if alert != nil {
+		eventAlert := fromAlert(alert)
+		trailer.Alert = &eventAlert
+	}
+	if err == nil {
+		err = sender.Err()
+	}
+	return trailer, err
+}
+
+// exportSender is a streaming.Sender which writes every match it receives to
+// an ExportWriter.
+type exportSender struct {
+	ctx    context.Context
+	logger log.Logger
+	db     database.DB
+
+	mu           sync.Mutex
+	exportWriter *streamhttp.ExportWriter
+	progress     *streamclient.ProgressAggregator
+
+	// writeErr is the first error returned by exportWriter. Once set we stop
+	// writing matches.
+	writeErr error
+}
+
+func (s *exportSender) Send(event streaming.SearchEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.progress.Update(event)
+	if s.writeErr != nil {
+		return
+	}
+
+	repoMetadata, err := getEventRepoMetadata(s.ctx, s.db, event)
+	if err != nil {
+		// Unlike the stream we don't drop the whole event, since exports are
+		// expected to be complete. Looking up every repository on its own
+		// only drops the matches of repositories which still fail below.
+		s.logger.Warn("failed to get repo metadata, looking up repositories individually", log.Error(err))
+		repoMetadata = s.getRepoMetadataIndividually(event)
+	}
+
+	for _, match := range event.Results {
+		repo := match.RepoName()
+
+		// Don't export matches which we cannot map to a repo the actor has
+		// access to. See eventHandler.Send.
+		if md, ok := repoMetadata[repo.ID]; !isRepoless(match) && (!ok || md.Name != repo.Name) {
+			continue
+		}
+
+		if s.writeErr = s.exportWriter.Match(fromMatch(match, repoMetadata, false)); s.writeErr != nil {
+			return
+		}
+	}
+
+	if len(event.Results) > 0 {
+		s.writeErr = s.exportWriter.Flush()
+	}
+}
+
+// getRepoMetadataIndividually returns the metadata of every repository in
+// event which can be looked up. Repositories which fail are omitted.
+func (s *exportSender) getRepoMetadataIndividually(event streaming.SearchEvent) map[api.RepoID]*types.SearchedRepo {
+	ids := repoIDs(event.Results)
+	repoMetadata := make(map[api.RepoID]*types.SearchedRepo, len(ids))
+	for _, id := range ids {
+		metadataList, err := s.db.Repos().Metadata(s.ctx, id)
+		if err != nil {
+			s.logger.Error("failed to get repo metadata, skipping its matches", log.Int32("repoID", int32(id)), log.Error(err))
+			continue
+		}
+		for _, repo := range metadataList {
+			repoMetadata[repo.ID] = repo
+		}
+	}
+	return repoMetadata
+}
+
+// Err returns the first error encountered while writing matches.
+func (s *exportSender) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.writeErr
+}
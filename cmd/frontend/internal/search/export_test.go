@@ -0,0 +1,179 @@
+package search
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	api2 "github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/client"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestServeExport(t *testing.T) {
+	graphqlbackend.MockDecodedViewerFinalSettings = &schema.Settings{}
+	t.Cleanup(func() { graphqlbackend.MockDecodedViewerFinalSettings = nil })
+
+	mock := client.NewMockSearchClient()
+	mock.PlanFunc.SetDefaultReturn(&search.Inputs{Query: query.Q{query.Parameter{Field: "count", Value: "4"}}}, nil)
+	mock.ExecuteFunc.SetDefaultHook(func(_ context.Context, s streaming.Sender, _ *search.Inputs) (*search.Alert, error) {
+		s.Send(streaming.SearchEvent{
+			Results: result.Matches{
+				&result.FileMatch{File: result.File{Path: "a", Repo: types.MinimalRepo{ID: 1, Name: "visible"}}},
+				&result.FileMatch{File: result.File{Path: "b", Repo: types.MinimalRepo{ID: 2, Name: "hidden"}}},
+				&result.RepoMatch{ID: 1, Name: "visible"},
+				&result.OwnerMatch{Handle: "alice", Type: result.OwnerTypeUsername, FileCount: 1},
+			},
+		})
+		return nil, nil
+	})
+
+	// Only repository 1 is visible to the actor.
+	mockRepos := database.NewMockRepoStore()
+	mockRepos.MetadataFunc.SetDefaultHook(func(_ context.Context, ids ...api2.RepoID) ([]*types.SearchedRepo, error) {
+		out := make([]*types.SearchedRepo, 0, len(ids))
+		for _, id := range ids {
+			if id == 1 {
+				out = append(out, &types.SearchedRepo{ID: id, Name: "visible"})
+			}
+		}
+		return out, nil
+	})
+	db := database.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(mockRepos)
+
+	ts := httptest.NewServer(&exportHandler{
+		logger:       logtest.Scoped(t),
+		db:           db,
+		searchClient: mock,
+	})
+	defer ts.Close()
+
+	t.Run("jsonl", func(t *testing.T) {
+		res, err := http.Get(ts.URL + "?q=test&display=1")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, "application/x-ndjson", res.Header.Get("Content-Type"))
+
+		var recordTypes []string
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			var record struct {
+				Type     string `json:"type"`
+				Progress struct {
+					MatchCount int `json:"matchCount"`
+				} `json:"progress"`
+			}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+			recordTypes = append(recordTypes, record.Type)
+			if record.Type == "trailer" {
+				require.Equal(t, 4, record.Progress.MatchCount)
+			}
+		}
+		require.NoError(t, scanner.Err())
+
+		// The display limit is ignored and the match in the hidden
+		// repository is dropped. The owner match is not tied to a
+		// repository, so it is kept.
+		require.Equal(t, []string{"path", "repo", "owner", "trailer"}, recordTypes)
+	})
+
+	t.Run("export limit", func(t *testing.T) {
+		res, err := http.Get(ts.URL + "?q=test")
+		require.NoError(t, err)
+		res.Body.Close()
+
+		history := mock.PlanFunc.History()
+		require.Equal(t, search.Export, history[len(history)-1].Arg4)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		res, err := http.Get(ts.URL + "?q=test&format=csv")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, "text/csv; charset=utf-8", res.Header.Get("Content-Type"))
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		res, err := http.Get(ts.URL + "?q=test&format=xml")
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func TestServeExport_metadataError(t *testing.T) {
+	graphqlbackend.MockDecodedViewerFinalSettings = &schema.Settings{}
+	t.Cleanup(func() { graphqlbackend.MockDecodedViewerFinalSettings = nil })
+
+	mock := client.NewMockSearchClient()
+	mock.PlanFunc.SetDefaultReturn(&search.Inputs{}, nil)
+	mock.ExecuteFunc.SetDefaultHook(func(_ context.Context, s streaming.Sender, _ *search.Inputs) (*search.Alert, error) {
+		s.Send(streaming.SearchEvent{
+			Results: result.Matches{
+				&result.FileMatch{File: result.File{Path: "a", Repo: types.MinimalRepo{ID: 1, Name: "ok"}}},
+				&result.FileMatch{File: result.File{Path: "b", Repo: types.MinimalRepo{ID: 2, Name: "broken"}}},
+				&result.OwnerMatch{Handle: "alice", Type: result.OwnerTypeUsername, FileCount: 1},
+			},
+		})
+		return nil, nil
+	})
+
+	// Looking up repository 2 fails, which fails the lookup of the whole
+	// event.
+	mockRepos := database.NewMockRepoStore()
+	mockRepos.MetadataFunc.SetDefaultHook(func(_ context.Context, ids ...api2.RepoID) ([]*types.SearchedRepo, error) {
+		out := make([]*types.SearchedRepo, 0, len(ids))
+		for _, id := range ids {
+			if id == 2 {
+				return nil, errors.New("boom")
+			}
+			out = append(out, &types.SearchedRepo{ID: id, Name: "ok"})
+		}
+		return out, nil
+	})
+	db := database.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(mockRepos)
+
+	ts := httptest.NewServer(&exportHandler{
+		logger:       logtest.Scoped(t),
+		db:           db,
+		searchClient: mock,
+	})
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "?q=test")
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	var paths []string
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		var record struct {
+			Type string `json:"type"`
+			Path string `json:"path"`
+		}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		paths = append(paths, record.Type+":"+record.Path)
+	}
+	require.NoError(t, scanner.Err())
+
+	// Only the match in the repository which failed is dropped.
+	require.Equal(t, []string{"path:a", "owner:", "trailer:"}, paths)
+}
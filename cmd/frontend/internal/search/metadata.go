@@ -5,6 +5,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -28,3 +29,11 @@ func getEventRepoMetadata(ctx context.Context, db database.DB, event streaming.S
 	}
 	return repoMetadata, nil
 }
+
+// isRepoless reports whether match is not part of a single repository, like
+// code owners of files in any number of repositories. Such matches have no
+// repo metadata and are not checked against it.
+func isRepoless(match result.Match) bool {
+	_, ok := match.(*result.OwnerMatch)
+	return ok
+}
@@ -294,6 +294,10 @@ func fromMatch(match result.Match, repoCache map[api.RepoID]*types.SearchedRepo,
 		return fromRepository(v, repoCache)
 	case *result.CommitMatch:
 		return fromCommit(v, repoCache)
+	case *result.CompareMatch:
+		return fromCompareMatch(v, repoCache)
+	case *result.OwnerMatch:
+		return fromOwnerMatch(v)
 	default:
 		panic(fmt.Sprintf("unknown match type %T", v))
 	}
@@ -513,6 +517,48 @@ func fromCommit(commit *result.CommitMatch, repoCache map[api.RepoID]*types.Sear
 	return commitEvent
 }
 
+func fromOwnerMatch(om *result.OwnerMatch) *streamhttp.EventOwnerMatch {
+	return &streamhttp.EventOwnerMatch{
+		Type:      streamhttp.OwnerMatchType,
+		Handle:    om.Handle,
+		OwnerType: om.Type,
+		FileCount: om.FileCount,
+	}
+}
+
+func fromCompareMatch(cm *result.CompareMatch, repoCache map[api.RepoID]*types.SearchedRepo) *streamhttp.EventCompareMatch {
+	lines := func(cls []result.CompareLine) []streamhttp.EventLineMatch {
+		res := make([]streamhttp.EventLineMatch, 0, len(cls))
+		for _, cl := range cls {
+			res = append(res, streamhttp.EventLineMatch{
+				Line:       cl.Content,
+				LineNumber: int32(cl.LineNumber),
+			})
+		}
+		return res
+	}
+
+	compareEvent := &streamhttp.EventCompareMatch{
+		Type:         streamhttp.CompareMatchType,
+		Path:         cm.Path,
+		Repository:   string(cm.Repo.Name),
+		RepositoryID: int32(cm.Repo.ID),
+		Base:         cm.BaseRev,
+		Head:         cm.HeadRev,
+		BaseCommit:   string(cm.BaseCommit),
+		HeadCommit:   string(cm.HeadCommit),
+		Added:        lines(cm.Added),
+		Removed:      lines(cm.Removed),
+	}
+
+	if r, ok := repoCache[cm.Repo.ID]; ok {
+		compareEvent.RepoStars = r.Stars
+		compareEvent.RepoLastFetched = r.LastFetched
+	}
+
+	return compareEvent
+}
+
 // eventStreamOTHook returns a StatHook which logs to log.
 func eventStreamOTHook(log func(...otlog.Field)) func(streamhttp.WriterStat) {
 	return func(stat streamhttp.WriterStat) {
@@ -564,6 +610,9 @@ func GuessSource(r *http.Request) trace.SourceType {
 func repoIDs(results []result.Match) []api.RepoID {
 	ids := make(map[api.RepoID]struct{}, 5)
 	for _, result := range results {
+		if isRepoless(result) {
+			continue
+		}
 		ids[result.RepoName().ID] = struct{}{}
 	}
 
@@ -672,7 +721,7 @@ func (h *eventHandler) Send(event streaming.SearchEvent) {
 		// Don't send matches which we cannot map to a repo the actor has access to. This
 		// check is expected to always pass. Missing metadata is a sign that we have
 		// searched repos that user shouldn't have access to.
-		if md, ok := repoMetadata[repo.ID]; !ok || md.Name != repo.Name {
+		if md, ok := repoMetadata[repo.ID]; !isRepoless(match) && (!ok || md.Name != repo.Name) {
 			continue
 		}
 
@@ -128,6 +128,63 @@ func TestServeStream_chunkMatches(t *testing.T) {
 	require.Len(t, chunkMatches[0].Ranges, 1)
 }
 
+func TestServeStream_ownerMatches(t *testing.T) {
+	graphqlbackend.MockDecodedViewerFinalSettings = &schema.Settings{}
+	t.Cleanup(func() { graphqlbackend.MockDecodedViewerFinalSettings = nil })
+
+	mock := client.NewMockSearchClient()
+	mock.PlanFunc.SetDefaultReturn(&search.Inputs{Query: query.Q{query.Parameter{Field: "count", Value: "1000"}}}, nil)
+	mock.ExecuteFunc.SetDefaultHook(func(_ context.Context, s streaming.Sender, _ *search.Inputs) (*search.Alert, error) {
+		s.Send(streaming.SearchEvent{
+			Results: result.Matches{
+				&result.OwnerMatch{Handle: "sourcegraph/search", Type: result.OwnerTypeTeam, FileCount: 3},
+				&result.FileMatch{File: result.File{Path: "a", Repo: types.MinimalRepo{ID: 2, Name: "hidden"}}},
+			},
+		})
+		return nil, nil
+	})
+
+	// No repository is visible to the actor.
+	mockRepos := database.NewMockRepoStore()
+	mockRepos.MetadataFunc.SetDefaultReturn(nil, nil)
+	db := database.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(mockRepos)
+
+	ts := httptest.NewServer(&streamHandler{
+		logger:              logtest.Scoped(t),
+		db:                  db,
+		flushTickerInternal: 1 * time.Millisecond,
+		pingTickerInterval:  1 * time.Millisecond,
+		searchClient:        mock,
+	})
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "?q=test&display=1000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	var matches []streamhttp.EventMatch
+	decoder := streamhttp.FrontendStreamDecoder{
+		OnMatches: func(ev []streamhttp.EventMatch) {
+			matches = append(matches, ev...)
+		},
+	}
+	if err := decoder.ReadAll(res.Body); err != nil {
+		t.Fatal(err)
+	}
+
+	// The owner match is not tied to a repository, so it is sent even though
+	// the match in the hidden repository is dropped.
+	require.Equal(t, []streamhttp.EventMatch{&streamhttp.EventOwnerMatch{
+		Type:      streamhttp.OwnerMatchType,
+		Handle:    "sourcegraph/search",
+		OwnerType: result.OwnerTypeTeam,
+		FileCount: 3,
+	}}, matches)
+}
+
 func TestDisplayLimit(t *testing.T) {
 	cases := []struct {
 		queryString         string
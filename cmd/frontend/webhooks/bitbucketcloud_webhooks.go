@@ -0,0 +1,41 @@
+package webhooks
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// BitbucketCloudWebhook routes Bitbucket Cloud webhook events to the
+// registered handlers by their event key, e.g. "repo:push".
+type BitbucketCloudWebhook struct {
+	*codeHostWebhook
+}
+
+// NewBitbucketCloudWebhook returns a BitbucketCloudWebhook that passes events
+// without registered handlers on to next.
+func NewBitbucketCloudWebhook(externalServices database.ExternalServiceStore, next http.Handler) *BitbucketCloudWebhook {
+	return &BitbucketCloudWebhook{&codeHostWebhook{
+		ExternalServices: externalServices,
+		Next:             next,
+		kind:             extsvc.KindBitbucketCloud,
+		eventType: func(r *http.Request, _ []byte) string {
+			return r.Header.Get("X-Event-Key")
+		},
+		authenticate: func(r *http.Request, _ []byte, config any) bool {
+			c, ok := config.(*schema.BitbucketCloudConnection)
+			if !ok {
+				return false
+			}
+			// Bitbucket Cloud doesn't sign payloads, so the secret is part of
+			// the webhook URL.
+			secret := r.URL.Query().Get("secret")
+			return c.WebhookSecret != "" && subtle.ConstantTimeCompare([]byte(c.WebhookSecret), []byte(secret)) == 1
+		},
+		parse: bitbucketcloud.ParseWebhookEvent,
+	}}
+}
@@ -0,0 +1,41 @@
+package webhooks
+
+import (
+	"net/http"
+
+	gh "github.com/google/go-github/v43/github"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// BitbucketServerWebhook routes Bitbucket Server webhook events to the
+// registered handlers by their event key, e.g. "repo:refs_changed".
+type BitbucketServerWebhook struct {
+	*codeHostWebhook
+}
+
+// NewBitbucketServerWebhook returns a BitbucketServerWebhook that passes events
+// without registered handlers on to next.
+func NewBitbucketServerWebhook(externalServices database.ExternalServiceStore, next http.Handler) *BitbucketServerWebhook {
+	return &BitbucketServerWebhook{&codeHostWebhook{
+		ExternalServices: externalServices,
+		Next:             next,
+		kind:             extsvc.KindBitbucketServer,
+		eventType: func(r *http.Request, _ []byte) string {
+			return bitbucketserver.WebhookEventType(r)
+		},
+		authenticate: func(r *http.Request, body []byte, config any) bool {
+			c, ok := config.(*schema.BitbucketServerConnection)
+			if !ok {
+				return false
+			}
+			// Bitbucket Server signs payloads the same way GitHub does.
+			secret := c.WebhookSecret()
+			return secret != "" && gh.ValidateSignature(r.Header.Get("X-Hub-Signature"), body, []byte(secret)) == nil
+		},
+		parse: bitbucketserver.ParseWebhookEvent,
+	}}
+}
@@ -0,0 +1,128 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// codeHostWebhook is responsible for handling incoming http requests for the
+// webhooks of a code host other than GitHub and routing them to any registered
+// WebhookHandlers by their event type.
+//
+// Requests for events without registered handlers are passed on to Next
+// untouched, so that the webhook handlers of Batch Changes, which predate this
+// router, can keep sharing the webhook URL of the code host.
+type codeHostWebhook struct {
+	ExternalServices database.ExternalServiceStore
+
+	// Next handles requests for events without registered handlers. If nil,
+	// these events are ignored.
+	Next http.Handler
+
+	router
+
+	kind string
+	// eventType returns the event type of the request.
+	eventType func(r *http.Request, body []byte) string
+	// authenticate reports whether the request is authenticated by a shared
+	// secret in the given external service configuration.
+	authenticate func(r *http.Request, body []byte, config any) bool
+	// parse parses the payload of an event of the given type.
+	parse func(eventType string, body []byte) (any, error)
+}
+
+func (h *codeHostWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		http.Error(w, "missing request body", http.StatusBadRequest)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log15.Error("Error reading webhook event", "kind", h.kind, "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Next reads the body again.
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	eventType := h.eventType(r, body)
+	if !h.handles(eventType) {
+		if h.Next != nil {
+			h.Next.ServeHTTP(w, r)
+		} else {
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return
+	}
+
+	// get external service and validate the shared secret
+	extSvc, err := h.getExternalService(r.Context(), r, body)
+	if err != nil {
+		log15.Error("Could not find valid external service for webhook", "kind", h.kind, "error", err)
+		http.Error(w, "External service not found", http.StatusUnauthorized)
+		return
+	}
+
+	SetExternalServiceID(r.Context(), extSvc.ID)
+
+	// 🚨 SECURITY: now that the shared secret has been validated, we can use an
+	// internal actor on the context.
+	ctx := actor.WithInternalActor(r.Context())
+
+	e, err := h.parse(eventType, body)
+	if err != nil {
+		log15.Error("Error parsing webhook event", "kind", h.kind, "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Dispatch(ctx, eventType, extSvc, e); err != nil {
+		log15.Error("Error handling webhook event", "kind", h.kind, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getExternalService returns the external service the webhook was configured
+// for, provided the request is authenticated by one of its secrets.
+func (h *codeHostWebhook) getExternalService(ctx context.Context, r *http.Request, body []byte) (*types.ExternalService, error) {
+	rawID := r.URL.Query().Get(extsvc.IDParam)
+	if rawID == "" {
+		return nil, errors.Errorf("missing %s parameter", extsvc.IDParam)
+	}
+	externalServiceID, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid external service id")
+	}
+
+	e, err := h.ExternalServices.GetByID(ctx, externalServiceID)
+	if err != nil {
+		return nil, err
+	}
+	if e.Kind != h.kind {
+		return nil, errors.Errorf("received %s webhook for external service %d of kind %s", h.kind, externalServiceID, e.Kind)
+	}
+	c, err := e.Configuration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only accept requests authenticated with one of the secrets
+	// of the external service.
+	if !h.authenticate(r, body, c) {
+		return nil, errors.Errorf("webhook for external service %d is not authenticated", externalServiceID)
+	}
+	return e, nil
+}
@@ -0,0 +1,181 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	gitlabwebhooks "github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestGitLabWebhook(t *testing.T) {
+	externalServices := database.NewMockExternalServiceStore()
+	externalServices.GetByIDFunc.SetDefaultReturn(&types.ExternalService{
+		ID:     1,
+		Kind:   extsvc.KindGitLab,
+		Config: extsvc.NewUnencryptedConfig(`{"url": "https://gitlab.com", "token": "abc", "projectQuery": ["none"], "webhooks": [{"secret": "secret"}]}`),
+	}, nil)
+
+	var nextCalled bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextCalled = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	h := NewGitLabWebhook(externalServices, next)
+	var received any
+	h.Register(func(ctx context.Context, svc *types.ExternalService, payload any) error {
+		received = payload
+		return nil
+	}, "push")
+
+	push := []byte(`{"object_kind": "push", "project": {"id": 42}, "ref": "refs/heads/main"}`)
+
+	for name, tc := range map[string]struct {
+		body       []byte
+		token      string
+		wantStatus int
+		wantNext   bool
+		wantEvent  bool
+	}{
+		"dispatched": {
+			body:       push,
+			token:      "secret",
+			wantStatus: http.StatusNoContent,
+			wantEvent:  true,
+		},
+		"wrong secret": {
+			body:       push,
+			token:      "wrong",
+			wantStatus: http.StatusUnauthorized,
+		},
+		"unregistered event": {
+			body:       []byte(`{"object_kind": "merge_request"}`),
+			wantStatus: http.StatusNoContent,
+			wantNext:   true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			nextCalled, received = false, nil
+
+			req := httptest.NewRequest("POST", "/.api/gitlab-webhooks?"+extsvc.IDParam+"=1", bytes.NewReader(tc.body))
+			req.Header.Set(gitlabwebhooks.TokenHeaderName, tc.token)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("unexpected status code: have %d; want %d", rec.Code, tc.wantStatus)
+			}
+			if nextCalled != tc.wantNext {
+				t.Errorf("unexpected call of next handler: have %v; want %v", nextCalled, tc.wantNext)
+			}
+			if tc.wantEvent {
+				if event, ok := received.(*gitlabwebhooks.PushEvent); !ok || event.Project.ID != 42 {
+					t.Errorf("unexpected event: %#v", received)
+				}
+			} else if received != nil {
+				t.Errorf("unexpected event: %#v", received)
+			}
+		})
+	}
+}
+
+func TestBitbucketServerWebhook(t *testing.T) {
+	externalServices := database.NewMockExternalServiceStore()
+	externalServices.GetByIDFunc.SetDefaultReturn(&types.ExternalService{
+		ID:     1,
+		Kind:   extsvc.KindBitbucketServer,
+		Config: extsvc.NewUnencryptedConfig(`{"url": "https://bitbucket.example.com", "token": "abc", "repositoryQuery": ["none"], "webhooks": {"secret": "secret"}}`),
+	}, nil)
+
+	h := NewBitbucketServerWebhook(externalServices, nil)
+	var received any
+	h.Register(func(ctx context.Context, svc *types.ExternalService, payload any) error {
+		received = payload
+		return nil
+	}, "repo:refs_changed")
+
+	body := []byte(`{"repository": {"id": 42}, "changes": [{"refId": "refs/heads/main"}]}`)
+	sign := func(secret string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	for name, tc := range map[string]struct {
+		signature  string
+		wantStatus int
+	}{
+		"valid signature":   {signature: sign("secret"), wantStatus: http.StatusNoContent},
+		"invalid signature": {signature: sign("wrong"), wantStatus: http.StatusUnauthorized},
+	} {
+		t.Run(name, func(t *testing.T) {
+			received = nil
+
+			req := httptest.NewRequest("POST", "/.api/bitbucket-server-webhooks?"+extsvc.IDParam+"=1", bytes.NewReader(body))
+			req.Header.Set("X-Event-Key", "repo:refs_changed")
+			req.Header.Set("X-Hub-Signature", tc.signature)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("unexpected status code: have %d; want %d", rec.Code, tc.wantStatus)
+			}
+			if event, ok := received.(*bitbucketserver.PushEvent); (tc.wantStatus == http.StatusNoContent) != (ok && event.Repository.ID == 42) {
+				t.Errorf("unexpected event: %#v", received)
+			}
+		})
+	}
+}
+
+func TestBitbucketCloudWebhook(t *testing.T) {
+	externalServices := database.NewMockExternalServiceStore()
+	externalServices.GetByIDFunc.SetDefaultReturn(&types.ExternalService{
+		ID:     1,
+		Kind:   extsvc.KindBitbucketCloud,
+		Config: extsvc.NewUnencryptedConfig(`{"url": "https://bitbucket.org", "username": "user", "appPassword": "pass", "webhookSecret": "secret"}`),
+	}, nil)
+
+	h := NewBitbucketCloudWebhook(externalServices, nil)
+	var received any
+	h.Register(func(ctx context.Context, svc *types.ExternalService, payload any) error {
+		received = payload
+		return nil
+	}, "repo:push")
+
+	body := []byte(`{"repository": {"uuid": "{repo}"}, "push": {"changes": []}}`)
+
+	for name, tc := range map[string]struct {
+		secret     string
+		wantStatus int
+	}{
+		"valid secret":   {secret: "secret", wantStatus: http.StatusNoContent},
+		"invalid secret": {secret: "wrong", wantStatus: http.StatusUnauthorized},
+	} {
+		t.Run(name, func(t *testing.T) {
+			received = nil
+
+			req := httptest.NewRequest("POST", "/.api/bitbucket-cloud-webhooks?"+extsvc.IDParam+"=1&secret="+tc.secret, bytes.NewReader(body))
+			req.Header.Set("X-Event-Key", "repo:push")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("unexpected status code: have %d; want %d", rec.Code, tc.wantStatus)
+			}
+			if event, ok := received.(*bitbucketcloud.PushEvent); (tc.wantStatus == http.StatusNoContent) != (ok && event.Repository.UUID == "{repo}") {
+				t.Errorf("unexpected event: %#v", received)
+			}
+		})
+	}
+}
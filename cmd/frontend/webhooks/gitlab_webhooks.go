@@ -0,0 +1,54 @@
+package webhooks
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	gitlabwebhooks "github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab/webhooks"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// GitLabWebhook routes GitLab webhook events to the registered handlers by
+// their object kind, e.g. "push".
+type GitLabWebhook struct {
+	*codeHostWebhook
+}
+
+// NewGitLabWebhook returns a GitLabWebhook that passes events without
+// registered handlers on to next.
+func NewGitLabWebhook(externalServices database.ExternalServiceStore, next http.Handler) *GitLabWebhook {
+	return &GitLabWebhook{&codeHostWebhook{
+		ExternalServices: externalServices,
+		Next:             next,
+		kind:             extsvc.KindGitLab,
+		eventType: func(_ *http.Request, body []byte) string {
+			var event struct {
+				ObjectKind string `json:"object_kind"`
+			}
+			_ = json.Unmarshal(body, &event)
+			return event.ObjectKind
+		},
+		authenticate: func(r *http.Request, _ []byte, config any) bool {
+			c, ok := config.(*schema.GitLabConnection)
+			if !ok {
+				return false
+			}
+			token := r.Header.Get(gitlabwebhooks.TokenHeaderName)
+			if token == "" {
+				return false
+			}
+			for _, hook := range c.Webhooks {
+				if hook.Secret != "" && subtle.ConstantTimeCompare([]byte(hook.Secret), []byte(token)) == 1 {
+					return true
+				}
+			}
+			return false
+		},
+		parse: func(_ string, body []byte) (any, error) {
+			return gitlabwebhooks.UnmarshalEvent(body)
+		},
+	}}
+}
@@ -0,0 +1,54 @@
+package webhooks
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// router routes webhook events to the WebhookHandlers registered for their
+// event type.
+type router struct {
+	mu       sync.RWMutex
+	handlers map[string][]WebhookHandler
+}
+
+// Dispatch accepts an event for a particular event type and dispatches it
+// to the appropriate stack of handlers, if any are configured.
+func (h *router) Dispatch(ctx context.Context, eventType string, extSvc *types.ExternalService, e any) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	g := errgroup.Group{}
+	for _, handler := range h.handlers[eventType] {
+		// capture the handler variable within this loop
+		handler := handler
+		g.Go(func() error {
+			return handler(ctx, extSvc, e)
+		})
+	}
+	return g.Wait()
+}
+
+// Register associates a given event type(s) with the specified handler.
+// Handlers are organized into a stack and executed sequentially, so the order in
+// which they are provided is significant.
+func (h *router) Register(handler WebhookHandler, eventTypes ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.handlers == nil {
+		h.handlers = make(map[string][]WebhookHandler)
+	}
+	for _, eventType := range eventTypes {
+		h.handlers[eventType] = append(h.handlers[eventType], handler)
+	}
+}
+
+// handles reports whether any handlers are registered for the event type.
+func (h *router) handles(eventType string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.handlers[eventType]) > 0
+}
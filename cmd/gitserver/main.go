@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -80,6 +81,9 @@ var (
 func main() {
 	ctx := context.Background()
 
+	offloadConf := &offloadConfig{}
+	offloadConf.Load()
+
 	env.Lock()
 	env.HandleHelpFlag()
 	logging.Init()
@@ -159,6 +163,14 @@ func main() {
 	}
 	gitserver.RegisterMetrics(db, observationContext)
 
+	if err := offloadConf.Validate(); err != nil {
+		logger.Fatal("invalid repository offloading configuration", log.Error(err))
+	}
+	gitserver.OffloadStore, err = newOffloadStore(ctx, offloadConf, observationContext)
+	if err != nil {
+		logger.Fatal("failed to create offload store", log.Error(err))
+	}
+
 	if tmpDir, err := gitserver.SetupAndClearTmp(); err != nil {
 		logger.Fatal("failed to setup temporary directory", log.Error(err))
 	} else if err := os.Setenv("TMP_DIR", tmpDir); err != nil {
@@ -464,6 +476,8 @@ func getVCSSyncer(
 	}
 
 	switch r.ExternalRepo.ServiceType {
+	case extsvc.TypeMercurial:
+		return &server.MercurialRepoSyncer{}, nil
 	case extsvc.TypePerforce:
 		var c schema.PerforceConnection
 		if _, err := extractOptions(&c); err != nil {
@@ -474,6 +488,15 @@ func getVCSSyncer(
 			Client:       c.P4Client,
 			FusionConfig: configureFusionClient(c),
 		}, nil
+	case extsvc.TypeSubversion:
+		var c schema.SubversionConnection
+		if _, err := extractOptions(&c); err != nil {
+			return nil, err
+		}
+		return &server.SubversionRepoSyncer{
+			Layout:  c.Layout,
+			Authors: c.Authors,
+		}, nil
 	case extsvc.TypeJVMPackages:
 		var c schema.JVMPackagesConnection
 		if _, err := extractOptions(&c); err != nil {
@@ -513,7 +536,34 @@ func getVCSSyncer(
 		cli := crates.NewClient(urn, httpcli.ExternalDoer)
 		return server.NewRustPackagesSyncer(&c, depsSvc, cli), nil
 	}
-	return &server.GitRepoSyncer{}, nil
+
+	// Code hosts serving Git repositories share the partialClone and mirrorTo
+	// options.
+	var c struct {
+		PartialClone *struct {
+			BlobSizeLimit *int64 `json:"blobSizeLimit"`
+			SparseSpec    string `json:"sparseSpec"`
+		} `json:"partialClone"`
+		MirrorTo *struct {
+			URL string `json:"url"`
+		} `json:"mirrorTo"`
+	}
+	if len(r.Sources) > 0 {
+		if _, err := extractOptions(&c); err != nil {
+			return nil, err
+		}
+	}
+	syncer := &server.GitRepoSyncer{}
+	if c.PartialClone != nil {
+		syncer.PartialClone = &server.PartialCloneOptions{
+			BlobSizeLimit: c.PartialClone.BlobSizeLimit,
+			SparseSpec:    c.PartialClone.SparseSpec,
+		}
+	}
+	if c.MirrorTo != nil {
+		syncer.PushMirrorURL = strings.ReplaceAll(c.MirrorTo.URL, "{repo}", string(repo))
+	}
+	return syncer, nil
 }
 
 func syncSiteLevelExternalServiceRateLimiters(ctx context.Context, store database.ExternalServiceStore) error {
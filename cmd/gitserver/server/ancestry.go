@@ -0,0 +1,427 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server/internal/accesslog"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// The ancestry endpoints answer questions about the commit graph of a
+// repository, so that clients don't need to fetch and walk the whole graph.
+// git uses the generation numbers in the commit-graph file to answer them
+// without walking all of history, so the file is written in the background
+// for repositories which don't have one yet.
+
+func (s *Server) handleIsAncestor(w http.ResponseWriter, r *http.Request) {
+	var req protocol.IsAncestorRequest
+	if !decodeAncestryRequest(w, r, &req) {
+		return
+	}
+	req.Repo = protocol.NormalizeRepo(req.Repo)
+
+	s.serveAncestry(w, r, req.Repo, []api.CommitID{req.Ancestor, req.Descendant}, func(ctx context.Context, dir GitDir) (interface{}, error) {
+		isAncestor, err := isAncestor(ctx, req.Repo, dir, req.Ancestor, req.Descendant)
+		return protocol.IsAncestorResponse{IsAncestor: isAncestor}, err
+	})
+}
+
+func (s *Server) handleNearestAncestorsIn(w http.ResponseWriter, r *http.Request) {
+	var req protocol.NearestAncestorsInRequest
+	if !decodeAncestryRequest(w, r, &req) {
+		return
+	}
+	req.Repo = protocol.NormalizeRepo(req.Repo)
+
+	s.serveAncestry(w, r, req.Repo, []api.CommitID{req.Commit}, func(ctx context.Context, dir GitDir) (interface{}, error) {
+		ancestors, err := nearestAncestorsIn(ctx, req.Repo, dir, req.Commit, req.Candidates)
+		return protocol.NearestAncestorsInResponse{Ancestors: ancestors}, err
+	})
+}
+
+func (s *Server) handleCommitsBetween(w http.ResponseWriter, r *http.Request) {
+	var req protocol.CommitsBetweenRequest
+	if !decodeAncestryRequest(w, r, &req) {
+		return
+	}
+	req.Repo = protocol.NormalizeRepo(req.Repo)
+
+	commits := []api.CommitID{req.Head}
+	if req.Base != "" {
+		commits = append(commits, req.Base)
+	}
+	s.serveAncestry(w, r, req.Repo, commits, func(ctx context.Context, dir GitDir) (interface{}, error) {
+		between, err := commitsBetween(ctx, req.Repo, dir, req.Base, req.Head, req.Limit)
+		return protocol.CommitsBetweenResponse{Commits: between}, err
+	})
+}
+
+func decodeAncestryRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	// 🚨 SECURITY: Only allow POST requests.
+	if strings.ToUpper(r.Method) != http.MethodPost {
+		http.Error(w, "", http.StatusMethodNotAllowed)
+		return false
+	}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// serveAncestry responds with the result of fn for the repository repo once
+// it is cloned and has the given commits. A commit which does not exist
+// results in a 422 status with a protocol.RevisionNotFoundPayload.
+func (s *Server) serveAncestry(w http.ResponseWriter, r *http.Request, repo api.RepoName, commits []api.CommitID, fn func(context.Context, GitDir) (interface{}, error)) {
+	// Log which actor is accessing the repo.
+	accesslog.Record(r.Context(), string(repo), nil)
+
+	ctx, cancel := context.WithTimeout(r.Context(), shortGitCommandTimeout([]string{"rev-list"}))
+	defer cancel()
+
+	dir := s.dir(repo)
+	if !repoCloned(dir) && !s.restoreOffloadedRepo(ctx, repo) {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(s.cloneOnDemand(ctx, repo, dir))
+		return
+	}
+
+	if !conf.Get().DisableAutoGitUpdates {
+		for _, c := range commits {
+			if !strings.HasPrefix(string(c), "-") && s.ensureRevision(ctx, repo, string(c), dir) {
+				break
+			}
+		}
+	}
+	s.ensureCommitGraph(repo, dir)
+
+	res, err := fn(ctx, dir)
+	if err != nil {
+		var notFound *gitdomain.RevisionNotFoundError
+		if errors.As(err, &notFound) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_ = json.NewEncoder(w).Encode(protocol.RevisionNotFoundPayload{Spec: notFound.Spec})
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// ensureCommitGraph writes the commit-graph file of the repository in dir in
+// the background if it doesn't have one. The janitor keeps it up to date once
+// it exists.
+func (s *Server) ensureCommitGraph(repo api.RepoName, dir GitDir) {
+	if ok, err := hasCommitGraph(dir); ok || err != nil {
+		return
+	}
+
+	ctx, cancel := s.serverContext()
+	go func() {
+		defer cancel()
+
+		// The commit-graph must not be written while the janitor runs git gc
+		// or our maintenance script, which write it too.
+		err, unlock := lockRepoForGC(dir)
+		if err != nil {
+			return
+		}
+		defer func() {
+			if err := unlock(); err != nil {
+				s.Logger.Warn("failed to unlock repository", log.String("repo", string(repo)), log.Error(err))
+			}
+		}()
+
+		cmd := exec.CommandContext(ctx, "git", "commit-graph", "write", "--reachable", "--changed-paths")
+		dir.Set(cmd)
+		if output, err := runWith(ctx, cmd, false, nil); err != nil {
+			s.Logger.Warn("failed to write commit-graph", log.String("repo", string(repo)), log.Error(err), log.String("output", string(output)))
+		}
+	}()
+}
+
+// resolveCommits returns the object names of the given commits in the
+// repository in dir, or an empty string for commits which don't exist.
+func resolveCommits(ctx context.Context, dir GitDir, commits []api.CommitID) ([]api.CommitID, error) {
+	var stdin bytes.Buffer
+	for _, c := range commits {
+		if !validCommitName(c) {
+			c = ""
+		}
+		stdin.WriteString(string(c) + "^{commit}\n")
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "cat-file", "--batch-check=%(objectname)")
+	dir.Set(cmd)
+	cmd.Stdin = &stdin
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, wrapCmdError(cmd, err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	if len(lines) != len(commits) {
+		return nil, errors.Errorf("unexpected output of git cat-file: %q", out)
+	}
+	resolved := make([]api.CommitID, len(commits))
+	for i, line := range lines {
+		// Missing or ambiguous objects are reported as "<name> missing" or
+		// "<name> ambiguous".
+		if strings.ContainsRune(line, ' ') || !validCommitName(commits[i]) {
+			continue
+		}
+		resolved[i] = api.CommitID(line)
+	}
+	return resolved, nil
+}
+
+// validCommitName reports whether commit can be resolved with git cat-file,
+// which reads one name per line.
+func validCommitName(commit api.CommitID) bool {
+	return commit != "" && !strings.ContainsAny(string(commit), "\r\n")
+}
+
+// resolveCommit returns the object name of commit, or a RevisionNotFoundError
+// if it does not exist.
+func resolveCommit(ctx context.Context, repo api.RepoName, dir GitDir, commit api.CommitID) (api.CommitID, error) {
+	resolved, err := resolveCommits(ctx, dir, []api.CommitID{commit})
+	if err != nil {
+		return "", err
+	}
+	if resolved[0] == "" {
+		return "", &gitdomain.RevisionNotFoundError{Repo: repo, Spec: string(commit)}
+	}
+	return resolved[0], nil
+}
+
+// isAncestor reports whether ancestor is an ancestor of descendant. Every
+// commit is an ancestor of itself.
+func isAncestor(ctx context.Context, repo api.RepoName, dir GitDir, ancestor, descendant api.CommitID) (bool, error) {
+	a, err := resolveCommit(ctx, repo, dir, ancestor)
+	if err != nil {
+		return false, err
+	}
+	d, err := resolveCommit(ctx, repo, dir, descendant)
+	if err != nil {
+		return false, err
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "merge-base", "--is-ancestor", string(a), string(d))
+	dir.Set(cmd)
+	err = cmd.Run()
+	if err == nil {
+		return true, nil
+	}
+	var e *exec.ExitError
+	if errors.As(err, &e) && e.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, wrapCmdError(cmd, err)
+}
+
+// nearestAncestorsIn returns the candidates which are ancestors of commit (or
+// commit itself) and not ancestors of any other such candidate, along with
+// their distance from commit. Candidates which don't exist are ignored.
+func nearestAncestorsIn(ctx context.Context, repo api.RepoName, dir GitDir, commit api.CommitID, candidates []api.CommitID) ([]gitdomain.Ancestor, error) {
+	c, err := resolveCommit(ctx, repo, dir, commit)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	resolved, err := resolveCommits(ctx, dir, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	// We respond with the candidates as given, which may be abbreviated.
+	byName := make(map[api.CommitID]api.CommitID, len(candidates))
+	for i, r := range resolved {
+		if r == "" {
+			continue
+		}
+		if r == c {
+			return []gitdomain.Ancestor{{Commit: candidates[i]}}, nil
+		}
+		if _, ok := byName[r]; !ok {
+			byName[r] = candidates[i]
+		}
+	}
+	if len(byName) == 0 {
+		return nil, nil
+	}
+
+	// The candidates reachable from any candidate but not from commit are
+	// exactly the ones which aren't ancestors of commit. git stops walking
+	// once it reaches commits which are known to be ancestors of commit, so
+	// this only lists the commits the candidates are ahead of commit by.
+	var stdin bytes.Buffer
+	for r := range byName {
+		stdin.WriteString(string(r) + "\n")
+	}
+	stdin.WriteString("^" + string(c) + "\n")
+
+	ancestors := make(map[api.CommitID]struct{}, len(byName))
+	for r := range byName {
+		ancestors[r] = struct{}{}
+	}
+	cmd := exec.CommandContext(ctx, "git", "rev-list", "--stdin")
+	dir.Set(cmd)
+	cmd.Stdin = &stdin
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	sc := bufio.NewScanner(stdout)
+	for sc.Scan() {
+		delete(ancestors, api.CommitID(sc.Text()))
+	}
+	if err := sc.Err(); err != nil {
+		_ = cmd.Wait()
+		return nil, err
+	}
+	if err := cmd.Wait(); err != nil {
+		return nil, wrapCmdError(cmd, err)
+	}
+
+	if len(ancestors) == 0 {
+		return nil, nil
+	}
+	nearest := make([]api.CommitID, 0, len(ancestors))
+	for r := range ancestors {
+		nearest = append(nearest, r)
+	}
+	if len(nearest) > 1 {
+		args := []string{"merge-base", "--independent"}
+		for _, r := range nearest {
+			args = append(args, string(r))
+		}
+		cmd := exec.CommandContext(ctx, "git", args...)
+		dir.Set(cmd)
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, wrapCmdError(cmd, err)
+		}
+		nearest = nearest[:0]
+		for _, line := range strings.Fields(string(out)) {
+			nearest = append(nearest, api.CommitID(line))
+		}
+	}
+
+	distances, err := ancestorDistances(ctx, dir, c, nearest)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]gitdomain.Ancestor, 0, len(nearest))
+	for _, r := range nearest {
+		res = append(res, gitdomain.Ancestor{Commit: byName[r], Distance: distances[r]})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Commit < res[j].Commit })
+	return res, nil
+}
+
+// ancestorDistances returns the length of the shortest path from commit to
+// each of ancestors, which must be ancestors of commit but not of each other.
+// The commits on these paths are exactly the commits reachable from commit but
+// not from any of ancestors, so only they are walked.
+func ancestorDistances(ctx context.Context, dir GitDir, commit api.CommitID, ancestors []api.CommitID) (map[api.CommitID]int, error) {
+	args := []string{"rev-list", "--parents", string(commit), "--not"}
+	for _, a := range ancestors {
+		args = append(args, string(a))
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	dir.Set(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, wrapCmdError(cmd, err)
+	}
+	parents := make(map[api.CommitID][]api.CommitID)
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		for _, p := range fields[1:] {
+			parents[api.CommitID(fields[0])] = append(parents[api.CommitID(fields[0])], api.CommitID(p))
+		}
+	}
+
+	// Walk breadth-first, so that each commit is first reached on a shortest
+	// path.
+	distances := map[api.CommitID]int{commit: 0}
+	queue := []api.CommitID{commit}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		for _, p := range parents[c] {
+			if _, ok := distances[p]; !ok {
+				distances[p] = distances[c] + 1
+				queue = append(queue, p)
+			}
+		}
+	}
+
+	res := make(map[api.CommitID]int, len(ancestors))
+	for _, a := range ancestors {
+		d, ok := distances[a]
+		if !ok {
+			return nil, errors.Errorf("%s is not an ancestor of %s", a, commit)
+		}
+		res[a] = d
+	}
+	return res, nil
+}
+
+// commitsBetween returns the commits reachable from head but not from base,
+// newest first. All commits reachable from head are returned if base is empty.
+// At most limit commits are returned if it is positive.
+func commitsBetween(ctx context.Context, repo api.RepoName, dir GitDir, base, head api.CommitID, limit int) ([]api.CommitID, error) {
+	h, err := resolveCommit(ctx, repo, dir, head)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"rev-list", "--topo-order"}
+	if limit > 0 {
+		args = append(args, "--max-count="+strconv.Itoa(limit))
+	}
+	args = append(args, string(h))
+	if base != "" {
+		b, err := resolveCommit(ctx, repo, dir, base)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "^"+string(b))
+	}
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	dir.Set(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, wrapCmdError(cmd, err)
+	}
+	lines := strings.Fields(string(out))
+	commits := make([]api.CommitID, 0, len(lines))
+	for _, line := range lines {
+		commits = append(commits, api.CommitID(line))
+	}
+	return commits, nil
+}
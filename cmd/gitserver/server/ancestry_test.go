@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestAncestry(t *testing.T) {
+	ctx := context.Background()
+
+	reposDir := t.TempDir()
+	repo := api.RepoName("example.com/foo/bar")
+	s := &Server{
+		Logger:   logtest.Scoped(t),
+		ReposDir: reposDir,
+		ctx:      ctx,
+	}
+	dir := s.dir(repo)
+	cmd := func(name string, arg ...string) api.CommitID {
+		t.Helper()
+		return api.CommitID(strings.TrimSpace(runCmd(t, filepath.Dir(string(dir)), name, arg...)))
+	}
+	commit := func(msg string) api.CommitID {
+		t.Helper()
+		cmd("git", "commit", "--allow-empty", "-m", msg)
+		return cmd("git", "rev-parse", "HEAD")
+	}
+
+	// c1 - c2 - c3 ------ m
+	//        \           /
+	//         b1 -------
+	if err := os.MkdirAll(filepath.Dir(string(dir)), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	cmd("git", "init", "--initial-branch=main", ".")
+	c1 := commit("c1")
+	c2 := commit("c2")
+	c3 := commit("c3")
+	cmd("git", "checkout", "-b", "branch", string(c2))
+	b1 := commit("b1")
+	cmd("git", "checkout", "main")
+	cmd("git", "merge", "--no-ff", "-m", "m", "branch")
+	m := cmd("git", "rev-parse", "HEAD")
+
+	// Writes the commit-graph in the background.
+	if ok, _ := hasCommitGraph(dir); ok {
+		t.Fatal("unexpected commit-graph")
+	}
+	s.ensureCommitGraph(repo, dir)
+	s.wg.Wait()
+	if ok, err := hasCommitGraph(dir); !ok {
+		t.Fatalf("expected commit-graph to be written: %v", err)
+	}
+
+	isAncestorTests := []struct {
+		ancestor, descendant api.CommitID
+		want                 bool
+	}{
+		{c1, m, true},
+		{b1, m, true},
+		{c3, c3, true},
+		{b1, c3, false},
+		{m, c1, false},
+		{c2[:10], "HEAD", true},
+	}
+	for _, test := range isAncestorTests {
+		have, err := isAncestor(ctx, repo, dir, test.ancestor, test.descendant)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have != test.want {
+			t.Errorf("isAncestor(%s, %s): have %v, want %v", test.ancestor, test.descendant, have, test.want)
+		}
+	}
+
+	nearestTests := []struct {
+		name       string
+		commit     api.CommitID
+		candidates []api.CommitID
+		want       []gitdomain.Ancestor
+	}{
+		{
+			name:       "commit is a candidate",
+			commit:     c3,
+			candidates: []api.CommitID{c1, c3},
+			want:       []gitdomain.Ancestor{{Commit: c3}},
+		},
+		{
+			name:       "nearest ancestor",
+			commit:     c3,
+			candidates: []api.CommitID{c1, c2, b1, "missing"},
+			want:       []gitdomain.Ancestor{{Commit: c2, Distance: 1}},
+		},
+		{
+			// b1 is only reachable from c1, not c3, through their common
+			// ancestor c2.
+			name:       "ancestor behind another candidate's history",
+			commit:     c3,
+			candidates: []api.CommitID{c1, b1},
+			want:       []gitdomain.Ancestor{{Commit: c1, Distance: 2}},
+		},
+		{
+			name:       "several nearest ancestors",
+			commit:     m,
+			candidates: []api.CommitID{c1, c2, c3, b1},
+			want:       sortedAncestors(gitdomain.Ancestor{Commit: b1, Distance: 1}, gitdomain.Ancestor{Commit: c3, Distance: 1}),
+		},
+		{
+			// The distance is the one of the shortest path, through either
+			// c3 or b1.
+			name:       "distance through merge",
+			commit:     m,
+			candidates: []api.CommitID{c1},
+			want:       []gitdomain.Ancestor{{Commit: c1, Distance: 3}},
+		},
+		{
+			name:       "abbreviated candidates",
+			commit:     m,
+			candidates: []api.CommitID{c2[:10]},
+			want:       []gitdomain.Ancestor{{Commit: c2[:10], Distance: 2}},
+		},
+		{
+			name:       "no ancestors",
+			commit:     c2,
+			candidates: []api.CommitID{c3, b1, m},
+			want:       nil,
+		},
+	}
+	for _, test := range nearestTests {
+		have, err := nearestAncestorsIn(ctx, repo, dir, test.commit, test.candidates)
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		if diff := cmp.Diff(test.want, have); diff != "" {
+			t.Errorf("%s: unexpected nearest ancestors (-want +got):\n%s", test.name, diff)
+		}
+	}
+
+	between, err := commitsBetween(ctx, repo, dir, c2, m, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(between) != 3 || between[0] != m || !containsCommits(between, c3, b1) {
+		t.Errorf("unexpected commits between c2 and m: %v", between)
+	}
+	if between, err := commitsBetween(ctx, repo, dir, "", c2, 0); err != nil || !cmp.Equal(between, []api.CommitID{c2, c1}) {
+		t.Errorf("unexpected commits reachable from c2: %v, %v", between, err)
+	}
+	if between, err := commitsBetween(ctx, repo, dir, c1, m, 1); err != nil || !cmp.Equal(between, []api.CommitID{m}) {
+		t.Errorf("unexpected limited commits between c1 and m: %v, %v", between, err)
+	}
+
+	// Missing commits are reported as such.
+	for _, missing := range []api.CommitID{"", "missing", "-x", "HEAD\nHEAD"} {
+		_, err := isAncestor(ctx, repo, dir, c1, missing)
+		var notFound *gitdomain.RevisionNotFoundError
+		if !errors.As(err, &notFound) {
+			t.Errorf("expected revision %q not to be found, got %v", missing, err)
+		}
+	}
+}
+
+func sortedAncestors(ancestors ...gitdomain.Ancestor) []gitdomain.Ancestor {
+	sort.Slice(ancestors, func(i, j int) bool { return ancestors[i].Commit < ancestors[j].Commit })
+	return ancestors
+}
+
+func containsCommits(commits []api.CommitID, want ...api.CommitID) bool {
+	for _, w := range want {
+		found := false
+		for _, c := range commits {
+			found = found || c == w
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,702 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server/internal/accesslog"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// blameCacheTTL is how long cached blames are kept after they were last used.
+var blameCacheTTL = env.MustGetDuration("SRC_BLAME_CACHE_TTL", 7*24*time.Hour, "how long blames of files are cached after their last use, 0 disables the blame cache")
+
+const (
+	// blameCacheDirName is the directory in the git dir of a repository which
+	// blames are cached in. It has a directory for each blamed path, named
+	// after the hash of the path, with a file for each commit the path was
+	// blamed at.
+	blameCacheDirName = "sg_blame"
+
+	// blameCacheMaxDistance is the maximum number of commits between a commit
+	// and the ancestor its blame is derived from.
+	blameCacheMaxDistance = 1000
+)
+
+// blameEntry is the blame of a file at a commit, as stored in the blame cache.
+type blameEntry struct {
+	// Commits are the commits lines of the file are attributed to.
+	Commits []blameCommit
+	// Hunks are the ranges of lines attributed to the same commit, ordered by
+	// line number.
+	Hunks []blameEntryHunk
+}
+
+// blameCommit is a commit lines are attributed to.
+type blameCommit struct {
+	ID       api.CommitID
+	Author   gitdomain.Signature
+	Summary  string
+	Filename string // path of the file in the commit
+}
+
+// blameEntryHunk is a range of consecutive lines attributed to consecutive
+// lines of the same commit.
+type blameEntryHunk struct {
+	Commit    int // index of the commit in blameEntry.Commits
+	StartLine int // 1-indexed line number of the first line
+	OrigLine  int // 1-indexed line number of the first line in the commit
+	Lines     int
+}
+
+// lines returns the attribution of each line of the file.
+func (e *blameEntry) lines() []blameLine {
+	var lines []blameLine
+	for _, h := range e.Hunks {
+		for i := 0; i < h.Lines; i++ {
+			lines = append(lines, blameLine{commit: h.Commit, origLine: h.OrigLine + i})
+		}
+	}
+	return lines
+}
+
+// hunks returns the hunks for the lines from startLine to endLine, which
+// default to the first and last line of the file. Byte offsets are relative to
+// the start of startLine in content, the blamed file.
+func (e *blameEntry) hunks(content []byte, startLine, endLine int) []protocol.BlameHunk {
+	if startLine < 1 {
+		startLine = 1
+	}
+	if endLine < 1 {
+		endLine = math.MaxInt
+	}
+
+	// Like the offsets computed from the output of git blame, each line
+	// counts one byte for its line terminator.
+	lines := bytes.Split(content, []byte("\n"))
+	if len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	var hunks []protocol.BlameHunk
+	offset := 0
+	for _, h := range e.Hunks {
+		start, end := h.StartLine, h.StartLine+h.Lines
+		if start < startLine {
+			start = startLine
+		}
+		if end-1 > endLine {
+			end = endLine + 1
+		}
+		if start >= end {
+			continue
+		}
+
+		c := e.Commits[h.Commit]
+		hunk := protocol.BlameHunk{
+			StartLine: start,
+			EndLine:   end,
+			StartByte: offset,
+			CommitID:  c.ID,
+			Author:    c.Author,
+			Message:   c.Summary,
+			Filename:  c.Filename,
+		}
+		for line := start; line < end && line <= len(lines); line++ {
+			offset += len(lines[line-1]) + 1
+		}
+		hunk.EndByte = offset
+		hunks = append(hunks, hunk)
+	}
+	return hunks
+}
+
+// blameLine is the attribution of a single line.
+type blameLine struct {
+	commit   int    // index of the commit in blameBuilder.commits
+	origLine int    // 1-indexed line number in the commit
+	filename string // path of the file in the commit, only set by parsePorcelain
+}
+
+// blameBuilder assembles a blameEntry line by line.
+type blameBuilder struct {
+	commits []blameCommit
+	index   map[api.CommitID]int
+	lines   []blameLine
+
+	// boundary are the indexes of the commits which git blame reported as
+	// boundaries of the blamed range of commits.
+	boundary map[int]bool
+}
+
+func newBlameBuilder() *blameBuilder {
+	return &blameBuilder{index: make(map[api.CommitID]int), boundary: make(map[int]bool)}
+}
+
+// commit returns the index of c in b.commits, adding it if it's new.
+func (b *blameBuilder) commit(c blameCommit) int {
+	if i, ok := b.index[c.ID]; ok {
+		return i
+	}
+	b.index[c.ID] = len(b.commits)
+	b.commits = append(b.commits, c)
+	return len(b.commits) - 1
+}
+
+// set sets the attribution of the 1-indexed line.
+func (b *blameBuilder) set(line int, l blameLine) {
+	for len(b.lines) < line {
+		b.lines = append(b.lines, blameLine{commit: -1})
+	}
+	b.lines[line-1] = l
+}
+
+// entry returns the blame of the lines of b. Like git blame, lines attributed
+// to consecutive lines of the same commit are grouped into a hunk.
+func (b *blameBuilder) entry() (*blameEntry, error) {
+	e := &blameEntry{}
+	// Commits no line is attributed to anymore are dropped.
+	index := make(map[int]int)
+	for i, l := range b.lines {
+		if l.commit < 0 {
+			return nil, errors.Errorf("line %d was not blamed", i+1)
+		}
+		commit, ok := index[l.commit]
+		if !ok {
+			commit = len(e.Commits)
+			index[l.commit] = commit
+			e.Commits = append(e.Commits, b.commits[l.commit])
+		}
+
+		if n := len(e.Hunks); n > 0 {
+			h := &e.Hunks[n-1]
+			if h.Commit == commit && h.OrigLine+h.Lines == l.origLine {
+				h.Lines++
+				continue
+			}
+		}
+		e.Hunks = append(e.Hunks, blameEntryHunk{
+			Commit:    commit,
+			StartLine: i + 1,
+			OrigLine:  l.origLine,
+			Lines:     1,
+		})
+	}
+	return e, nil
+}
+
+// parsePorcelain adds the lines in out, the output of git blame --porcelain,
+// to b.
+func (b *blameBuilder) parsePorcelain(out []byte) error {
+	commit := -1
+	var origLine, finalLine int
+	var filename string
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "\t") {
+			if commit < 0 {
+				return errors.New("blame output has content before the first header")
+			}
+			b.set(finalLine, blameLine{commit: commit, origLine: origLine, filename: filename})
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		if isAbsoluteRevision(key) {
+			// <commit> <orig line> <final line> [<lines in group>]
+			fields := strings.Fields(value)
+			if len(fields) < 2 {
+				return errors.Errorf("malformed blame header %q", line)
+			}
+			var err error
+			if origLine, err = strconv.Atoi(fields[0]); err != nil {
+				return errors.Errorf("malformed blame header %q", line)
+			}
+			if finalLine, err = strconv.Atoi(fields[1]); err != nil || finalLine < 1 {
+				return errors.Errorf("malformed blame header %q", line)
+			}
+			commit = b.commit(blameCommit{ID: api.CommitID(key)})
+			continue
+		}
+		if commit < 0 {
+			continue
+		}
+
+		c := &b.commits[commit]
+		switch key {
+		case "author":
+			c.Author.Name = value
+		case "author-mail":
+			if len(value) >= 2 && value[0] == '<' && value[len(value)-1] == '>' {
+				value = value[1 : len(value)-1]
+			}
+			c.Author.Email = value
+		case "author-time":
+			t, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return errors.Errorf("failed to parse author-time %q", value)
+			}
+			c.Author.Date = time.Unix(t, 0).UTC()
+		case "summary":
+			c.Summary = value
+		case "boundary":
+			b.boundary[commit] = true
+		case "filename":
+			filename = value
+			// Commits touching several paths repeat their filename for
+			// each path. We keep the first, like the gitserver client.
+			if c.Filename == "" {
+				c.Filename = value
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleBlame(w http.ResponseWriter, r *http.Request) {
+	// 🚨 SECURITY: Only allow POST requests.
+	if strings.ToUpper(r.Method) != http.MethodPost {
+		http.Error(w, "", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req protocol.BlameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// 🚨 SECURITY: The commit must not be interpreted as an option of git.
+	if strings.HasPrefix(req.Commit, "-") {
+		http.Error(w, "invalid commit", http.StatusBadRequest)
+		return
+	}
+	req.Repo = protocol.NormalizeRepo(req.Repo)
+
+	// Log which actor is accessing the repo.
+	accesslog.Record(r.Context(), string(req.Repo), map[string]string{
+		"commit": req.Commit,
+		"path":   req.Path,
+	})
+
+	ctx, cancel := context.WithTimeout(r.Context(), shortGitCommandTimeout([]string{"blame"}))
+	defer cancel()
+
+	dir := s.dir(req.Repo)
+	if !repoCloned(dir) && !s.restoreOffloadedRepo(ctx, req.Repo) {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(s.cloneOnDemand(ctx, req.Repo, dir))
+		return
+	}
+
+	if !conf.Get().DisableAutoGitUpdates {
+		s.ensureRevision(ctx, req.Repo, req.Commit, dir)
+	}
+
+	hunks, err := s.blame(ctx, req.Repo, dir, req.Commit, req.Path, req.StartLine, req.EndLine)
+	if err != nil {
+		var notFound *gitdomain.RevisionNotFoundError
+		if errors.As(err, &notFound) {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(protocol.RevisionNotFoundPayload{Spec: notFound.Spec})
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(protocol.BlameResponse{Hunks: hunks})
+}
+
+// cloneOnDemand starts cloning repo into dir unless cloning on demand is
+// disabled, and returns the payload to respond with while the repository is
+// not cloned.
+func (s *Server) cloneOnDemand(ctx context.Context, repo api.RepoName, dir GitDir) *protocol.NotFoundPayload {
+	if conf.Get().DisableAutoGitUpdates || s.ownedByOtherShard(ctx, repo) {
+		return &protocol.NotFoundPayload{}
+	}
+	if progress, inProgress := s.locker.Status(dir); inProgress {
+		return &protocol.NotFoundPayload{CloneInProgress: true, CloneProgress: progress}
+	}
+	progress, err := s.cloneRepo(ctx, repo, nil)
+	if err != nil {
+		s.Logger.Debug("error starting repo clone", log.String("repo", string(repo)), log.Error(err))
+		return &protocol.NotFoundPayload{}
+	}
+	return &protocol.NotFoundPayload{CloneInProgress: true, CloneProgress: progress}
+}
+
+// blame returns the hunks of the blame of path at rev in the repository in
+// dir, restricted to the lines from startLine to endLine if set.
+func (s *Server) blame(ctx context.Context, repo api.RepoName, dir GitDir, rev, path string, startLine, endLine int) ([]protocol.BlameHunk, error) {
+	if rev == "" {
+		rev = "HEAD"
+	}
+	out, err := s.blameGitOutput(ctx, repo, dir, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return nil, &gitdomain.RevisionNotFoundError{Repo: repo, Spec: rev}
+	}
+	commit := api.CommitID(bytes.TrimSpace(out))
+
+	content, err := s.blameGitOutput(ctx, repo, dir, "cat-file", "blob", string(commit)+":"+path)
+	if err != nil {
+		return nil, err
+	}
+	e, err := s.blameFile(ctx, repo, dir, commit, path)
+	if err != nil {
+		return nil, err
+	}
+	return e.hunks(content, startLine, endLine), nil
+}
+
+// blameFile returns the blame of path at commit. Blames are cached, and are
+// derived from the cached blame of a recent ancestor of commit if there is
+// one.
+func (s *Server) blameFile(ctx context.Context, repo api.RepoName, dir GitDir, commit api.CommitID, path string) (*blameEntry, error) {
+	if blameCacheTTL <= 0 {
+		return s.gitBlame(ctx, repo, dir, commit, path)
+	}
+
+	logger := s.Logger.With(log.String("repo", string(repo)), log.String("commit", string(commit)), log.String("path", path))
+	cacheDir := blameCacheDir(dir, path)
+	e, err := readBlameEntry(cacheDir, commit)
+	if err == nil {
+		blameCacheRequests.WithLabelValues("hit").Inc()
+		return e, nil
+	} else if !os.IsNotExist(err) {
+		logger.Warn("failed to read cached blame", log.Error(err))
+	}
+
+	result := "miss"
+	if ancestor, ancestorEntry := cachedBlameAncestor(ctx, dir, cacheDir, commit); ancestorEntry != nil {
+		e, err = s.deriveBlame(ctx, repo, dir, ancestor, ancestorEntry, commit, path)
+		if err != nil {
+			logger.Warn("failed to derive blame from ancestor", log.String("ancestor", string(ancestor)), log.Error(err))
+		} else if e != nil {
+			result = "derived"
+		}
+	}
+	if e == nil {
+		if e, err = s.gitBlame(ctx, repo, dir, commit, path); err != nil {
+			return nil, err
+		}
+	}
+	blameCacheRequests.WithLabelValues(result).Inc()
+
+	if err := writeBlameEntry(cacheDir, commit, e); err != nil {
+		logger.Warn("failed to cache blame", log.Error(err))
+	}
+	return e, nil
+}
+
+// gitBlame blames all lines of path at commit with git blame.
+func (s *Server) gitBlame(ctx context.Context, repo api.RepoName, dir GitDir, commit api.CommitID, path string) (*blameEntry, error) {
+	b := newBlameBuilder()
+	if err := s.gitBlameLines(ctx, repo, dir, commit, path, nil, b); err != nil {
+		return nil, err
+	}
+	return b.entry()
+}
+
+// gitBlameLines blames the given ranges of lines of path at commit, or all
+// lines if there are none, with git blame and adds them to b.
+func (s *Server) gitBlameLines(ctx context.Context, repo api.RepoName, dir GitDir, commit api.CommitID, path string, ranges []string, b *blameBuilder) error {
+	args := []string{"blame", "-w", "--porcelain"}
+	for _, r := range ranges {
+		args = append(args, "-L"+r)
+	}
+	args = append(args, string(commit), "--", path)
+
+	out, err := s.blameGitOutput(ctx, repo, dir, args...)
+	if err != nil {
+		return err
+	}
+	return b.parsePorcelain(out)
+}
+
+// deriveBlame derives the blame of path at commit from its blame at ancestor.
+// Only the commits after ancestor are blamed, and lines which git blame
+// attributes to ancestor keep their attribution there. Like git blame, lines
+// which were changed after ancestor and later restored are attributed to the
+// commit which restored them.
+//
+// Lines attributed to other boundaries of the range, which are brought in by
+// merges of branches older than ancestor, are blamed again in full. So are
+// lines attributed to ancestor in another file than path, which git blame
+// finds by following renames, since the cached blame only covers path. It
+// returns nil if too many lines need this for deriving to be worthwhile.
+func (s *Server) deriveBlame(ctx context.Context, repo api.RepoName, dir GitDir, ancestor api.CommitID, ancestorEntry *blameEntry, commit api.CommitID, path string) (*blameEntry, error) {
+	out, err := s.blameGitOutput(ctx, repo, dir, "blame", "-w", "--porcelain", string(ancestor)+".."+string(commit), "--", path)
+	if err != nil {
+		return nil, err
+	}
+	recent := newBlameBuilder()
+	if err := recent.parsePorcelain(out); err != nil {
+		return nil, err
+	}
+
+	old := ancestorEntry.lines()
+	b := newBlameBuilder()
+	var ranges []string
+	reblamed, rangeStart := 0, 0
+	for i, l := range recent.lines {
+		line := i + 1
+		if l.commit < 0 {
+			return nil, errors.Errorf("line %d was not blamed", line)
+		}
+		c := recent.commits[l.commit]
+
+		cached := recent.boundary[l.commit] && c.ID == ancestor && l.filename == path
+		if !recent.boundary[l.commit] || cached {
+			if rangeStart > 0 {
+				ranges = append(ranges, fmt.Sprintf("%d,%d", rangeStart, line-1))
+				rangeStart = 0
+			}
+			if !recent.boundary[l.commit] {
+				b.set(line, blameLine{commit: b.commit(c), origLine: l.origLine})
+				continue
+			}
+			if l.origLine < 1 || l.origLine > len(old) {
+				return nil, errors.New("blame does not match the cached blame of the ancestor")
+			}
+			o := old[l.origLine-1]
+			b.set(line, blameLine{commit: b.commit(ancestorEntry.Commits[o.commit]), origLine: o.origLine})
+			continue
+		}
+
+		b.set(line, blameLine{commit: -1})
+		if rangeStart == 0 {
+			rangeStart = line
+		}
+		reblamed++
+	}
+	if rangeStart > 0 {
+		ranges = append(ranges, fmt.Sprintf("%d,%d", rangeStart, len(recent.lines)))
+	}
+
+	if reblamed*2 > len(b.lines) {
+		return nil, nil
+	}
+	if len(ranges) > 0 {
+		if err := s.gitBlameLines(ctx, repo, dir, commit, path, ranges, b); err != nil {
+			return nil, err
+		}
+	}
+	return b.entry()
+}
+
+// blameGitOutput runs git with args in the repository in dir and returns its
+// output.
+func (s *Server) blameGitOutput(ctx context.Context, repo api.RepoName, dir GitDir, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	dir.Set(cmd)
+	// Blaming partial clones may need blobs which are fetched on demand.
+	s.configurePartialCloneCommand(ctx, repo, dir, cmd)
+	out, err := cmd.Output()
+	return out, wrapCmdError(cmd, err)
+}
+
+// cachedBlameAncestor returns the closest of the recent ancestors of commit
+// whose blame is cached in cacheDir, if any.
+func cachedBlameAncestor(ctx context.Context, dir GitDir, cacheDir string, commit api.CommitID) (api.CommitID, *blameEntry) {
+	files, err := os.ReadDir(cacheDir)
+	if err != nil || len(files) == 0 {
+		return "", nil
+	}
+	cached := make(map[string]struct{}, len(files))
+	for _, f := range files {
+		if strings.HasSuffix(f.Name(), ".json") {
+			cached[strings.TrimSuffix(f.Name(), ".json")] = struct{}{}
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "rev-list", "--max-count="+strconv.Itoa(blameCacheMaxDistance), string(commit))
+	dir.Set(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", nil
+	}
+	for _, id := range strings.Fields(string(out)) {
+		if _, ok := cached[id]; !ok {
+			continue
+		}
+		if e, err := readBlameEntry(cacheDir, api.CommitID(id)); err == nil {
+			return api.CommitID(id), e
+		}
+	}
+	return "", nil
+}
+
+// blameCacheDir returns the directory the blames of path are cached in.
+func blameCacheDir(dir GitDir, path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return dir.Path(blameCacheDirName, hex.EncodeToString(sum[:]))
+}
+
+// readBlameEntry reads the cached blame at commit from cacheDir. Reading an
+// entry counts as using it.
+func readBlameEntry(cacheDir string, commit api.CommitID) (*blameEntry, error) {
+	path := filepath.Join(cacheDir, string(commit)+".json")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var e blameEntry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, err
+	}
+	for _, h := range e.Hunks {
+		if h.Commit < 0 || h.Commit >= len(e.Commits) {
+			return nil, errors.Errorf("cached blame %s is corrupt", path)
+		}
+	}
+
+	// The janitor evicts entries by their modification time.
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return &e, nil
+}
+
+// writeBlameEntry atomically writes the blame at commit to cacheDir.
+func writeBlameEntry(cacheDir string, commit api.CommitID, e *blameEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cacheDir, os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(cacheDir, "tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(cacheDir, string(commit)+".json"))
+}
+
+// evictBlameCache removes the blames cached in dir which were not used within
+// blameCacheTTL, and returns the size of the remaining cached blames.
+func evictBlameCache(dir GitDir) (int64, error) {
+	root := dir.Path(blameCacheDirName)
+	pathDirs, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-blameCacheTTL)
+	var size int64
+	for _, pathDir := range pathDirs {
+		pathDirName := filepath.Join(root, pathDir.Name())
+		files, err := os.ReadDir(pathDirName)
+		if err != nil {
+			return size, err
+		}
+		for _, f := range files {
+			fi, err := f.Info()
+			if err != nil {
+				continue
+			}
+			if fi.ModTime().Before(cutoff) {
+				if err := os.Remove(filepath.Join(pathDirName, f.Name())); err != nil {
+					return size, err
+				}
+				blameCacheEvictions.Inc()
+				continue
+			}
+			size += fi.Size()
+		}
+		// Only removes directories which are empty now.
+		_ = os.Remove(pathDirName)
+	}
+	_ = os.Remove(root)
+	return size, nil
+}
+
+// evictBlameCacheLRU removes the blames cached in gitDirs, in order from least
+// to most recently used, until it has freed howManyBytesToFree. It returns the
+// number of bytes freed, which is less than howManyBytesToFree if the caches
+// were removed entirely.
+func evictBlameCacheLRU(gitDirs []GitDir, howManyBytesToFree int64) (int64, error) {
+	type cachedBlame struct {
+		path    string
+		modTime time.Time
+		size    int64
+	}
+	var blames []cachedBlame
+	for _, dir := range gitDirs {
+		root := dir.Path(blameCacheDirName)
+		pathDirs, err := os.ReadDir(root)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return 0, err
+		}
+		for _, pathDir := range pathDirs {
+			pathDirName := filepath.Join(root, pathDir.Name())
+			files, err := os.ReadDir(pathDirName)
+			if err != nil {
+				return 0, err
+			}
+			for _, f := range files {
+				fi, err := f.Info()
+				if err != nil {
+					continue
+				}
+				blames = append(blames, cachedBlame{
+					path:    filepath.Join(pathDirName, f.Name()),
+					modTime: fi.ModTime(),
+					size:    fi.Size(),
+				})
+			}
+		}
+	}
+
+	sort.Slice(blames, func(i, j int) bool {
+		return blames[i].modTime.Before(blames[j].modTime)
+	})
+
+	var freed int64
+	for _, b := range blames {
+		if freed >= howManyBytesToFree {
+			break
+		}
+		if err := os.Remove(b.path); err != nil {
+			return freed, err
+		}
+		blameCacheEvictions.Inc()
+		freed += b.size
+
+		// Only removes directories which are empty now.
+		pathDirName := filepath.Dir(b.path)
+		if os.Remove(pathDirName) == nil {
+			_ = os.Remove(filepath.Dir(pathDirName))
+		}
+	}
+	return freed, nil
+}
@@ -0,0 +1,276 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func TestBlameCache(t *testing.T) {
+	ctx := context.Background()
+
+	reposDir := t.TempDir()
+	repo := api.RepoName("example.com/foo/bar")
+	s := &Server{
+		Logger:   logtest.Scoped(t),
+		ReposDir: reposDir,
+	}
+	dir := s.dir(repo)
+	if err := os.MkdirAll(filepath.Dir(string(dir)), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return strings.TrimSpace(runCmd(t, filepath.Dir(string(dir)), name, arg...))
+	}
+	commit := func(content string) api.CommitID {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(filepath.Dir(string(dir)), "f"), []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+		cmd("git", "add", "f")
+		cmd("git", "commit", "-m", "change f")
+		return api.CommitID(cmd("git", "rev-parse", "HEAD"))
+	}
+	cmd("git", "init", ".")
+
+	commits := []api.CommitID{
+		commit("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"),
+		// Changes, inserts and deletes lines.
+		commit("1\n2\nthree\n4\n5\n5.1\n5.2\n6\n7\n9\n10\n"),
+		// Changes whitespace, and appends a line without a trailing newline.
+		commit(" 1\n2\nthree\n4\n5\n5.1\n5.2\n6\n7\n9\n10\n11"),
+		// Moves a line.
+		commit("2\nthree\n4\n5\n5.1\n5.2\n6\n7\n9\n10\n11\n 1\n"),
+	}
+
+	for i := 1; i < len(commits); i++ {
+		ancestor, err := s.gitBlame(ctx, repo, dir, commits[i-1], "f")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := s.gitBlame(ctx, repo, dir, commits[i], "f")
+		if err != nil {
+			t.Fatal(err)
+		}
+		have, err := s.deriveBlame(ctx, repo, dir, commits[i-1], ancestor, commits[i], "f")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Errorf("commit %d: derived blame differs from git blame (-want +got):\n%s", i, diff)
+		}
+	}
+
+	// Lines which were changed after the ancestor and later restored are
+	// attributed to the commit which restored them, like git blame does.
+	last := commits[len(commits)-1]
+	commit("2\nTHREE\n4\n5\n5.1\n5.2\n6\n7\n9\n10\n11\n 1\n")
+	restored := commit("2\nthree\n4\n5\n5.1\n5.2\n6\n7\n9\n10\n11\n 1\n")
+	ancestor, err := s.gitBlame(ctx, repo, dir, last, "f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := s.gitBlame(ctx, repo, dir, restored, "f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, err := s.deriveBlame(ctx, repo, dir, last, ancestor, restored, "f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Errorf("derived blame of restored line differs from git blame (-want +got):\n%s", diff)
+	}
+
+	// Blames are cached, and derived from the closest cached ancestor.
+	for _, c := range []api.CommitID{commits[0], commits[2]} {
+		if _, err := s.blameFile(ctx, repo, dir, c, "f"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cacheDir := blameCacheDir(dir, "f")
+	if have, _ := cachedBlameAncestor(ctx, dir, cacheDir, commits[3]); have != commits[2] {
+		t.Errorf("unexpected closest cached ancestor %q, want %q", have, commits[2])
+	}
+	have, err = s.blameFile(ctx, repo, dir, commits[3], "f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err = s.gitBlame(ctx, repo, dir, commits[3], "f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Errorf("cached blame differs from git blame (-want +got):\n%s", diff)
+	}
+
+	setBlameCacheTTL := func(ttl time.Duration) {
+		old := blameCacheTTL
+		blameCacheTTL = ttl
+		t.Cleanup(func() { blameCacheTTL = old })
+	}
+
+	// The janitor evicts blames which were not used recently.
+	setBlameCacheTTL(time.Hour)
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(cacheDir, string(commits[0])+".json"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	size, err := evictBlameCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size == 0 {
+		t.Error("expected size of remaining cached blames to be reported")
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, string(commits[0])+".json")); !os.IsNotExist(err) {
+		t.Errorf("expected unused blame to be evicted: %v", err)
+	}
+	if _, err := readBlameEntry(cacheDir, commits[3]); err != nil {
+		t.Errorf("expected recently used blame to be kept: %v", err)
+	}
+
+	// Disabling the cache evicts all blames.
+	setBlameCacheTTL(0)
+	if size, err := evictBlameCache(dir); err != nil || size != 0 {
+		t.Errorf("unexpected eviction result %d, %v", size, err)
+	}
+	if _, err := os.Stat(dir.Path(blameCacheDirName)); !os.IsNotExist(err) {
+		t.Errorf("expected blame cache to be removed: %v", err)
+	}
+}
+
+func TestBlameCacheRename(t *testing.T) {
+	ctx := context.Background()
+
+	repo := api.RepoName("example.com/foo/bar")
+	s := &Server{
+		Logger:   logtest.Scoped(t),
+		ReposDir: t.TempDir(),
+	}
+	dir := s.dir(repo)
+	if err := os.MkdirAll(filepath.Dir(string(dir)), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return strings.TrimSpace(runCmd(t, filepath.Dir(string(dir)), name, arg...))
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(filepath.Dir(string(dir)), name), []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit := func() api.CommitID {
+		t.Helper()
+		cmd("git", "add", "-A")
+		cmd("git", "commit", "-m", "change")
+		return api.CommitID(cmd("git", "rev-parse", "HEAD"))
+	}
+	cmd("git", "init", ".")
+
+	write("f", "1\n2\n3\n")
+	commit()
+	g := "this line was first added to g, number 1\nthis line was first added to g, number 2\nthis line was first added to g, number 3\n"
+	write("g", g)
+	ancestor := commit()
+	cmd("git", "rm", "f")
+	commit()
+	// Renames g to f and edits it, so that git blame follows f into g,
+	// whose lines are attributed to ancestor.
+	cmd("git", "mv", "g", "f")
+	write("f", g+"a\nb\nc\nd\n")
+	renamed := commit()
+
+	ancestorEntry, err := s.gitBlame(ctx, repo, dir, ancestor, "f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := s.gitBlame(ctx, repo, dir, renamed, "f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, err := s.deriveBlame(ctx, repo, dir, ancestor, ancestorEntry, renamed, "f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have == nil {
+		t.Fatal("expected blame to be derived")
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Errorf("derived blame of renamed file differs from git blame (-want +got):\n%s", diff)
+	}
+}
+
+func TestHandleBlame(t *testing.T) {
+	reposDir := t.TempDir()
+	repo := api.RepoName("example.com/foo/bar")
+	db := database.NewMockDB()
+	db.GitserverReposFunc.SetDefaultReturn(database.NewMockGitserverRepoStore())
+	s := &Server{
+		Logger:            logtest.Scoped(t),
+		ReposDir:          reposDir,
+		skipCloneForTests: true,
+		DB:                db,
+	}
+	h := s.Handler()
+
+	dir := s.dir(repo)
+	if err := os.MkdirAll(filepath.Dir(string(dir)), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	runCmd(t, filepath.Dir(string(dir)), "git", "init", ".")
+	runCmd(t, filepath.Dir(string(dir)), "sh", "-c", "echo hello > f && git add f && git commit -m hello")
+
+	blame := func(commit string) *http.Response {
+		t.Helper()
+		body, err := json.Marshal(protocol.BlameRequest{Repo: repo, Commit: commit, Path: "f"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "/blame", bytes.NewReader(body)))
+		return w.Result()
+	}
+
+	res := blame("HEAD")
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d", res.StatusCode)
+	}
+	var resp protocol.BlameResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Hunks) != 1 || resp.Hunks[0].Message != "hello" {
+		t.Errorf("unexpected hunks %+v", resp.Hunks)
+	}
+
+	// Revisions which don't exist are reported as not found.
+	res = blame("missing")
+	if res.StatusCode != http.StatusNotFound {
+		t.Fatalf("unexpected status %d", res.StatusCode)
+	}
+	var payload protocol.RevisionNotFoundPayload
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Spec != "missing" {
+		t.Errorf("unexpected revision %q", payload.Spec)
+	}
+}
@@ -172,6 +172,8 @@ const reposStatsName = "repos-stats.json"
 // 10. Perform sg-maintenance
 // 11. Git prune
 // 12. Only during first run: Set sizes of repos which don't have it in a database.
+// 13. Link forks to their fork pool, and remove unused fork pools.
+// 14. Evict unused blames from the blame cache.
 func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 	janitorRunning.Set(1)
 	janitorStart := time.Now()
@@ -220,11 +222,24 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 		size := dirSize(dir.Path("."))
 		stats.GitDirBytes += size
 		name := s.name(dir)
+
+		addr, err := s.addrForRepo(bCtx, name, gitServerAddrs)
+		if replicaOf(dir) != "" {
+			if s.hostnameMatch(addr) {
+				s.promoteReplica(bCtx, name, dir)
+			} else {
+				// The size of replicas is recorded by their primary.
+				stats.Replicas++
+				stats.ReplicaGitDirBytes += size
+				if s.isReplicaTarget(name, addr, gitServerAddrs.Addresses) {
+					return false, nil
+				}
+			}
+		}
 		repoToSize[name] = size
 
 		// Record the number and disk usage used of repos that should
 		// not belong on this instance and remove up to SRC_WRONG_SHARD_DELETE_LIMIT in a single Janitor run.
-		addr, err := s.addrForRepo(bCtx, name, gitServerAddrs)
 		if !s.hostnameMatch(addr) {
 			wrongShardRepoCount++
 			wrongShardRepoSize += size
@@ -347,10 +362,11 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 			}
 		}
 
-		// We believe converting a Perforce depot to a Git repository is generally a
-		// very expensive operation, therefore we do not try to re-clone/redo the
-		// conversion only because it is old or slow to do "git gc".
-		if repoType == "perforce" && reason != maybeCorrupt {
+		// We believe converting a Perforce depot, Subversion repository or
+		// Mercurial repository to a Git repository is generally a very expensive
+		// operation, therefore we do not try to re-clone/redo the conversion only
+		// because it is old or slow to do "git gc".
+		if (repoType == "perforce" || repoType == "subversion" || repoType == "mercurial") && reason != maybeCorrupt {
 			reason = ""
 		}
 
@@ -358,6 +374,12 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 			return false, nil
 		}
 
+		// Replicas are cloned again by the next sync from their primary.
+		if replicaOf(dir) != "" {
+			cleanupLogger.Info("removing expired replica", log.String("repo", string(dir)), log.String("reason", reason))
+			return true, s.removeRepoDirectory(dir, false)
+		}
+
 		ctx, cancel := context.WithTimeout(bCtx, conf.GitLongCommandTimeout())
 		defer cancel()
 
@@ -437,6 +459,17 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 		return false, multi
 	}
 
+	performForkPoolUpdate := func(dir GitDir) (done bool, err error) {
+		return false, s.updateForkPool(bCtx, dir)
+	}
+
+	var blameCacheBytes int64
+	performBlameCacheEviction := func(dir GitDir) (done bool, err error) {
+		size, err := evictBlameCache(dir)
+		blameCacheBytes += size
+		return false, err
+	}
+
 	performGC := func(dir GitDir) (done bool, err error) {
 		return false, gitGC(dir)
 	}
@@ -473,6 +506,13 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 		// happen if several git-gc operations are running at the same time.
 		// We only disable if sg is managing gc.
 		{"auto gc config", ensureAutoGC},
+		// Link forks to the pool shared with the other forks of their
+		// upstream, or detach them if fork pools are disabled. This runs
+		// before garbage collection, which drops the objects shared with
+		// the pool from the fork.
+		{"update fork pool", performForkPoolUpdate},
+		// Remove cached blames which were not used recently.
+		{"evict blame cache", performBlameCacheEviction},
 	}
 
 	if gitGCMode == gitGCModeJanitorAutoGC {
@@ -537,6 +577,11 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 		cleanupLogger.Error("error iterating over repositories", log.Error(err))
 	}
 
+	// Pools are cleaned up after all forks are visited, so forks detached by
+	// this run are no longer members.
+	stats.GitDirBytes += s.cleanupForkPools(bCtx)
+	blameCacheSize.Set(float64(blameCacheBytes))
+
 	if b, err := json.Marshal(stats); err != nil {
 		cleanupLogger.Error("failed to marshal periodic stats", log.Error(err))
 	} else if err = os.WriteFile(filepath.Join(s.ReposDir, reposStatsName), b, 0666); err != nil {
@@ -684,8 +729,10 @@ func (s *StatDiskSizer) DiskSizeBytes(mountPoint string) (uint64, error) {
 	return free, nil
 }
 
-// freeUpSpace removes git directories under ReposDir, in order from least
-// recently to most recently used, until it has freed howManyBytesToFree.
+// freeUpSpace removes cached blames and then git directories under ReposDir,
+// in order from least recently to most recently used, until it has freed
+// howManyBytesToFree. If OffloadStore is set, repositories are offloaded to it
+// before they are removed.
 func (s *Server) freeUpSpace(howManyBytesToFree int64) error {
 	if howManyBytesToFree <= 0 {
 		return nil
@@ -698,6 +745,19 @@ func (s *Server) freeUpSpace(howManyBytesToFree int64) error {
 	if err != nil {
 		return errors.Wrap(err, "finding git dirs")
 	}
+
+	// Cached blames can be recomputed, so evict them before removing any repo.
+	blameFreed, err := evictBlameCacheLRU(gitDirs, howManyBytesToFree)
+	if err != nil {
+		return errors.Wrap(err, "evicting cached blames")
+	}
+	if blameFreed >= howManyBytesToFree {
+		logger.Warn("evicted least recently used cached blames",
+			log.Int64("space freed in bytes", blameFreed),
+			log.Int64("how much space to free in bytes", howManyBytesToFree))
+		return nil
+	}
+
 	dirModTimes := make(map[GitDir]time.Time, len(gitDirs))
 	for _, d := range gitDirs {
 		mt, err := gitDirModTime(d)
@@ -712,22 +772,42 @@ func (s *Server) freeUpSpace(howManyBytesToFree int64) error {
 		return dirModTimes[gitDirs[i]].Before(dirModTimes[gitDirs[j]])
 	})
 
-	// Remove repos until howManyBytesToFree is met or exceeded.
-	var spaceFreed int64
 	diskSizeBytes, err := s.DiskSizer.DiskSizeBytes(s.ReposDir)
 	if err != nil {
 		return errors.Wrap(err, "getting disk size")
 	}
+
+	// Pick repos until howManyBytesToFree is met or exceeded.
+	var toRemove []GitDir
+	dirSizes := make(map[GitDir]int64)
+	toFree := blameFreed
 	for _, d := range gitDirs {
-		if spaceFreed >= howManyBytesToFree {
-			return nil
+		if toFree >= howManyBytesToFree {
+			break
 		}
-		delta := dirSize(d.Path("."))
+		dirSizes[d] = dirSize(d.Path("."))
+		toFree += dirSizes[d]
+		toRemove = append(toRemove, d)
+	}
+
+	// Offload them all at once before removing them, so that slow uploads
+	// don't delay freeing up space one repo at a time.
+	var offloaded map[GitDir]bool
+	if s.OffloadStore != nil {
+		offloaded = s.offloadRepos(toRemove)
+	}
+
+	spaceFreed := blameFreed
+	for _, d := range toRemove {
+		delta := dirSizes[d]
 		if err := s.removeRepoDirectory(d, true); err != nil {
 			return errors.Wrap(err, "removing repo directory")
 		}
 		spaceFreed += delta
 		reposRemovedDiskPressure.Inc()
+		if offloaded[d] {
+			reposOffloaded.Inc()
+		}
 
 		// Report the new disk usage situation after removing this repo.
 		actualFreeBytes, err := s.DiskSizer.BytesFreeOnDisk(s.ReposDir)
@@ -738,6 +818,7 @@ func (s *Server) freeUpSpace(howManyBytesToFree int64) error {
 
 		logger.Warn("removed least recently used repo",
 			log.String("repo", string(d)),
+			log.Bool("offloaded", offloaded[d]),
 			log.Duration("how old", time.Since(dirModTimes[d])),
 			log.Float64("free space in GiB", float64(actualFreeBytes)/G),
 			log.Float64("actual percent of disk space free", float64(actualFreeBytes)/float64(diskSizeBytes)*100.0),
@@ -814,6 +895,9 @@ func (s *Server) removeRepoDirectory(gitDir GitDir, updateCloneStatus bool) erro
 		return nil
 	}
 
+	// The clone status of replicas belongs to their primary.
+	replica := replicaOf(gitDir) != ""
+
 	// Rename out of the location so we can atomically stop using the repo.
 	tmp, err := s.tempDir("delete-repo")
 	if err != nil {
@@ -828,7 +912,9 @@ func (s *Server) removeRepoDirectory(gitDir GitDir, updateCloneStatus bool) erro
 	// should not be returned, just logged.
 
 	// Set as not_cloned in the database.
-	if updateCloneStatus {
+	if replica {
+		s.setReplicaStatusNonFatal(ctx, s.name(gitDir), false)
+	} else if updateCloneStatus {
 		s.setCloneStatusNonFatal(ctx, s.name(gitDir), types.CloneStatusNotCloned)
 	}
 
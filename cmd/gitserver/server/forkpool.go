@@ -0,0 +1,477 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/fileutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Forks of the same upstream repository share most of their objects. When fork
+// pools are enabled, the janitor moves the objects of forks into an object pool
+// per upstream, which the forks borrow from via objects/info/alternates.
+//
+// A pool is a bare repository in forkPoolsDirName, named after the root commit
+// of its members. The refs of each member are mirrored into the pool under
+// refs/members/<member id>/, so that the objects the member borrows are always
+// reachable in the pool. Pools never drop objects, because a member may still
+// borrow objects that are no longer referenced by its refs in the pool.
+//
+// A fork is detached from its pool, by copying the objects it borrows back into
+// the fork, before the pool is removed.
+var forkPoolsEnabled, _ = strconv.ParseBool(env.Get("SRC_ENABLE_FORK_POOLS", "false", "Share the objects of forks of the same repository in object pools"))
+
+const (
+	// forkPoolsDirName is the name of the directory under ReposDir containing
+	// the fork pools.
+	forkPoolsDirName = ".fork-pools"
+
+	// gitConfigForkPool is the key we add to the git config of a fork linked
+	// to a pool. Its value is the key of the pool.
+	gitConfigForkPool = "sourcegraph.forkPool"
+
+	// forkPoolMembersDir is the directory in a pool containing a file per
+	// member. The file is named after the member id and contains the name of
+	// the member.
+	forkPoolMembersDir = "members"
+)
+
+var (
+	forkPoolsLinked = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_fork_pools_linked",
+		Help: "number of forks linked to an object pool",
+	})
+	forkPoolsDetached = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_fork_pools_detached",
+		Help: "number of forks detached from their object pool",
+	})
+	forkPoolsRemoved = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_fork_pools_removed",
+		Help: "number of object pools removed because they had no members left",
+	})
+)
+
+// forkPoolDir returns the directory of the pool with the given key.
+func (s *Server) forkPoolDir(key string) GitDir {
+	return GitDir(filepath.Join(s.ReposDir, forkPoolsDirName, key))
+}
+
+// forkPoolMemberID returns the id of repo in a pool. It is used as the name of
+// the member's ref namespace in the pool.
+func forkPoolMemberID(repo api.RepoName) string {
+	h := sha256.Sum256([]byte(repo))
+	return hex.EncodeToString(h[:])
+}
+
+// forkPoolOf returns the key of the pool the repository in dir is linked to,
+// or an empty string if it isn't linked to a pool.
+func forkPoolOf(dir GitDir) string {
+	key, _ := gitConfigGet(dir, gitConfigForkPool)
+	return key
+}
+
+// forkPoolKey returns the key of the pool the repository in dir belongs to,
+// which is its root commit. If HEAD has several root commits, the smallest
+// one is used. An empty key is returned for empty repositories.
+func forkPoolKey(ctx context.Context, dir GitDir) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-list", "--max-parents=0", "HEAD")
+	dir.Set(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		// HEAD does not resolve in empty repositories.
+		if empty, _ := isEmptyRepo(dir); empty {
+			return "", nil
+		}
+		return "", errors.Wrapf(wrapCmdError(cmd, err), "failed to list root commits")
+	}
+	roots := strings.Fields(string(out))
+	if len(roots) == 0 {
+		return "", nil
+	}
+	sort.Strings(roots)
+	return roots[0], nil
+}
+
+// isEmptyRepo reports whether the repository in dir has no refs.
+func isEmptyRepo(dir GitDir) (bool, error) {
+	cmd := exec.Command("git", "for-each-ref", "--count=1")
+	dir.Set(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return false, wrapCmdError(cmd, err)
+	}
+	return len(bytes.TrimSpace(out)) == 0, nil
+}
+
+// updateForkPool links the fork in dir to its pool, or refreshes its refs in
+// the pool if it's already linked. If fork pools are disabled, linked forks
+// are detached from their pool instead.
+//
+// It is run by the janitor before garbage collection, so that objects the
+// fork shares with the pool are dropped from the fork when it is repacked.
+func (s *Server) updateForkPool(ctx context.Context, dir GitDir) error {
+	if key := forkPoolOf(dir); key != "" {
+		if !forkPoolsEnabled {
+			return s.detachForkPool(ctx, dir, key)
+		}
+
+		pool := s.forkPoolDir(key)
+		if _, err := os.Stat(string(pool)); os.IsNotExist(err) {
+			// The fork is missing the objects it borrowed from the pool, so
+			// it's marked to be re-cloned by the janitor.
+			if err := gitConfigSet(dir, gitConfigMaybeCorrupt, strconv.FormatInt(time.Now().Unix(), 10)); err != nil {
+				return err
+			}
+			return errors.Errorf("fork pool %s is missing", key)
+		} else if err != nil {
+			return err
+		}
+		return fetchForkPoolMember(ctx, pool, s.name(dir), dir)
+	}
+
+	if !forkPoolsEnabled {
+		return nil
+	}
+
+	// Repositories borrowing objects from somewhere else are left alone, as
+	// are partial clones, whose missing objects can't be fetched into a pool.
+	if fi, err := os.Stat(dir.Path("objects", "info", "alternates")); err == nil && fi.Size() > 0 {
+		return nil
+	}
+	if isPartialClone(dir) {
+		return nil
+	}
+
+	repo, err := s.DB.Repos().GetByName(ctx, s.name(dir))
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !repo.Fork {
+		return nil
+	}
+
+	key, err := forkPoolKey(ctx, dir)
+	if err != nil || key == "" {
+		return err
+	}
+	return s.linkForkPool(ctx, dir, key)
+}
+
+// linkForkPool links the fork in dir to the pool with the given key, creating
+// the pool if it doesn't exist yet. The objects of the fork which are in the
+// pool are removed from the fork.
+func (s *Server) linkForkPool(ctx context.Context, dir GitDir, key string) error {
+	err, unlock := lockRepoForGC(dir)
+	if err != nil {
+		s.Logger.Debug("could not lock repository for linking fork pool", log.String("dir", string(dir)), log.Error(err))
+		return nil
+	}
+	defer unlock()
+
+	pool := s.forkPoolDir(key)
+	if _, err := os.Stat(string(pool)); os.IsNotExist(err) {
+		if err := s.createForkPool(ctx, pool); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	repo := s.name(dir)
+	if err := fetchForkPoolMember(ctx, pool, repo, dir); err != nil {
+		return err
+	}
+
+	// The fork is recorded as linked before it starts borrowing objects, so
+	// the pool is never removed while the fork depends on it.
+	if err := gitConfigSet(dir, gitConfigForkPool, key); err != nil {
+		return err
+	}
+	// The path is relative to the objects directory of the fork, so that
+	// ReposDir can be moved.
+	objects := dir.Path("objects")
+	rel, err := filepath.Rel(objects, pool.Path("objects"))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(objects, "info"), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(objects, "info", "alternates"), []byte(rel+"\n"), 0644); err != nil {
+		return err
+	}
+
+	// -l leaves out all objects which are in the pool.
+	if err := repackForkPool(ctx, dir, "-a", "-d", "-l"); err != nil {
+		return err
+	}
+	forkPoolsLinked.Inc()
+	s.Logger.Info("linked fork to pool", log.String("repo", string(repo)), log.String("pool", key))
+	return nil
+}
+
+// detachForkPool copies the objects the fork in dir borrows from the pool with
+// the given key back into the fork, and removes the fork from the pool.
+func (s *Server) detachForkPool(ctx context.Context, dir GitDir, key string) error {
+	err, unlock := lockRepoForGC(dir)
+	if err != nil {
+		s.Logger.Debug("could not lock repository for detaching fork pool", log.String("dir", string(dir)), log.Error(err))
+		return nil
+	}
+	defer unlock()
+
+	alternates := dir.Path("objects", "info", "alternates")
+	if _, err := os.Stat(alternates); err == nil {
+		// Without -l all objects are packed, including the objects borrowed
+		// from the pool.
+		if err := repackForkPool(ctx, dir, "-a", "-d"); err != nil {
+			return err
+		}
+		if err := os.Remove(alternates); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if err := gitConfigUnset(dir, gitConfigForkPool); err != nil {
+		return err
+	}
+
+	repo := s.name(dir)
+	if pool := s.forkPoolDir(key); repoCloned(pool) {
+		if err := removeForkPoolMember(ctx, pool, forkPoolMemberID(repo)); err != nil {
+			return err
+		}
+	}
+	forkPoolsDetached.Inc()
+	s.Logger.Info("detached fork from pool", log.String("repo", string(repo)), log.String("pool", key))
+	return nil
+}
+
+// createForkPool creates an empty pool at pool.
+func (s *Server) createForkPool(ctx context.Context, pool GitDir) error {
+	tmpPath, err := s.tempDir("fork-pool-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpPath)
+	tmp := GitDir(filepath.Join(tmpPath, "pool"))
+
+	cmd := exec.CommandContext(ctx, "git", "init", "--bare", string(tmp))
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "fork pool setup failed with output %q", string(output))
+	}
+	// The pool is only maintained by the janitor, which never prunes it.
+	for _, kv := range [][2]string{{"gc.auto", "0"}, {"gc.pruneExpire", "never"}, {"core.logAllRefUpdates", "false"}} {
+		if err := gitConfigSet(tmp, kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(tmp.Path(forkPoolMembersDir), os.ModePerm); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(string(pool)), os.ModePerm); err != nil {
+		return err
+	}
+	return fileutil.RenameAndSync(string(tmp), string(pool))
+}
+
+// fetchForkPoolMember mirrors the refs of the fork in dir into its namespace in
+// pool, and records the fork as a member of pool.
+func fetchForkPoolMember(ctx context.Context, pool GitDir, repo api.RepoName, dir GitDir) error {
+	ctx, cancel := context.WithTimeout(ctx, conf.GitLongCommandTimeout())
+	defer cancel()
+
+	id := forkPoolMemberID(repo)
+	// The member is recorded first, so a failed fetch doesn't leave behind
+	// refs which are never cleaned up.
+	if err := os.WriteFile(pool.Path(forkPoolMembersDir, id), []byte(repo), 0644); err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "fetch", "--no-auto-gc", "--no-tags", "--prune", string(dir), "+refs/*:refs/members/"+id+"/*")
+	pool.Set(cmd)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "failed to fetch fork into pool with output %q", string(output))
+	}
+	return nil
+}
+
+// removeForkPoolMember deletes the refs of the member with the given id from
+// pool. The objects of the member are kept.
+func removeForkPoolMember(ctx context.Context, pool GitDir, id string) error {
+	cmd := exec.CommandContext(ctx, "git", "for-each-ref", "--format=delete %(refname)", "refs/members/"+id+"/")
+	pool.Set(cmd)
+	deletes, err := cmd.Output()
+	if err != nil {
+		return errors.Wrapf(wrapCmdError(cmd, err), "failed to list refs of fork pool member")
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "update-ref", "--stdin")
+	pool.Set(cmd)
+	cmd.Stdin = bytes.NewReader(deletes)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "failed to delete refs of fork pool member with output %q", string(output))
+	}
+
+	if err := os.Remove(pool.Path(forkPoolMembersDir, id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func repackForkPool(ctx context.Context, dir GitDir, args ...string) error {
+	ctx, cancel := context.WithTimeout(ctx, conf.GitLongCommandTimeout())
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", append([]string{"repack"}, args...)...)
+	dir.Set(cmd)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "failed to repack with output %q", string(output))
+	}
+	return nil
+}
+
+// cleanupForkPools removes members which are no longer linked from all pools,
+// and removes pools without members. The remaining pools are repacked if
+// needed. It returns the disk usage of the remaining pools.
+func (s *Server) cleanupForkPools(ctx context.Context) (size int64) {
+	logger := s.Logger.Scoped("cleanupForkPools", "removes unused fork pools")
+
+	entries, err := os.ReadDir(filepath.Join(s.ReposDir, forkPoolsDirName))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Error("failed to list fork pools", log.Error(err))
+		}
+		return 0
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		key := e.Name()
+		pool := s.forkPoolDir(key)
+		logger := logger.With(log.String("pool", key))
+
+		members, err := s.forkPoolMembers(ctx, pool, key)
+		if err != nil {
+			logger.Error("failed to clean up fork pool members", log.Error(err))
+			size += dirSize(string(pool))
+			continue
+		}
+
+		if members == 0 {
+			logger.Info("removing fork pool without members")
+			if err := s.removeForkPool(pool); err != nil {
+				logger.Error("failed to remove fork pool", log.Error(err))
+			} else {
+				forkPoolsRemoved.Inc()
+				continue
+			}
+		} else if err := maintainForkPool(ctx, pool); err != nil {
+			logger.Error("failed to maintain fork pool", log.Error(err))
+		}
+		size += dirSize(string(pool))
+	}
+	return size
+}
+
+// forkPoolMembers returns the number of forks linked to pool, and removes the
+// members from pool which are no longer linked to it.
+func (s *Server) forkPoolMembers(ctx context.Context, pool GitDir, key string) (int, error) {
+	entries, err := os.ReadDir(pool.Path(forkPoolMembersDir))
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	members := 0
+	for _, e := range entries {
+		b, err := os.ReadFile(pool.Path(forkPoolMembersDir, e.Name()))
+		if err != nil {
+			return 0, err
+		}
+		dir := s.dir(api.RepoName(b))
+		if _, err := os.Stat(string(dir)); err == nil {
+			linked, err := gitConfigGet(dir, gitConfigForkPool)
+			if err != nil {
+				// Assume the fork is still linked if we can't tell.
+				return 0, err
+			}
+			if linked == key {
+				members++
+				continue
+			}
+		} else if !os.IsNotExist(err) {
+			return 0, err
+		}
+
+		if err := removeForkPoolMember(ctx, pool, e.Name()); err != nil {
+			return 0, err
+		}
+	}
+	return members, nil
+}
+
+// removeForkPool atomically removes pool. It must only be called for pools
+// without members.
+func (s *Server) removeForkPool(pool GitDir) error {
+	tmp, err := s.tempDir("delete-fork-pool")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	return fileutil.RenameAndSync(string(pool), filepath.Join(tmp, "pool"))
+}
+
+// maintainForkPool repacks pool if it has too many packfiles or loose objects.
+// Unlike git gc, it keeps unreachable objects, since members may still borrow
+// them.
+func maintainForkPool(ctx context.Context, pool GitDir) error {
+	tooManyPacks, err := tooManyPackfiles(pool, autoPackLimit)
+	if err != nil {
+		return err
+	}
+	tooManyLoose, err := tooManyLooseObjects(pool, looseObjectsLimit)
+	if err != nil {
+		return err
+	}
+	if !tooManyPacks && !tooManyLoose {
+		return nil
+	}
+
+	err, unlock := lockRepoForGC(pool)
+	if err != nil {
+		return nil
+	}
+	defer unlock()
+
+	cmd := exec.CommandContext(ctx, "git", "pack-refs", "--all", "--prune")
+	pool.Set(cmd)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "failed to pack refs with output %q", string(output))
+	}
+	return repackForkPool(ctx, pool, "-a", "-d", "--keep-unreachable")
+}
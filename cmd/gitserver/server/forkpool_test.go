@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestForkPools(t *testing.T) {
+	ctx := context.Background()
+
+	upstream := t.TempDir()
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, upstream, name, arg...)
+	}
+	makeSingleCommitRepo(cmd)
+	root := strings.TrimSpace(cmd("git", "rev-list", "--max-parents=0", "HEAD"))
+
+	reposDir := t.TempDir()
+	forks := []api.RepoName{"example.com/a/repo", "example.com/b/repo"}
+	for _, fork := range forks {
+		runCmd(t, reposDir, "git", "clone", "--mirror", upstream, filepath.Join(reposDir, string(fork), ".git"))
+	}
+	// The first fork has a commit the upstream doesn't have.
+	cmd("sh", "-c", "echo more >> hello.txt")
+	addCommitToRepo(cmd)
+	runCmd(t, reposDir, "git", "-C", filepath.Join(reposDir, string(forks[0]), ".git"), "fetch", upstream, "+refs/heads/*:refs/heads/*")
+
+	repos := database.NewMockRepoStore()
+	repos.GetByNameFunc.SetDefaultHook(func(_ context.Context, name api.RepoName) (*types.Repo, error) {
+		return &types.Repo{Name: name, Fork: true}, nil
+	})
+	db := database.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(repos)
+
+	s := &Server{
+		Logger:   logtest.Scoped(t),
+		ReposDir: reposDir,
+		DB:       db,
+	}
+
+	setForkPoolsEnabled := func(enabled bool) {
+		old := forkPoolsEnabled
+		forkPoolsEnabled = enabled
+		t.Cleanup(func() { forkPoolsEnabled = old })
+	}
+	git := func(repo api.RepoName, arg ...string) string {
+		t.Helper()
+		return strings.TrimSpace(runCmd(t, string(s.dir(repo)), "git", arg...))
+	}
+	// packedObjects returns the number of objects in the packs of repo,
+	// excluding the objects it borrows from its pool.
+	packedObjects := func(repo api.RepoName) string {
+		t.Helper()
+		git(repo, "repack", "-a", "-d", "-l")
+		for _, line := range strings.Split(git(repo, "count-objects", "-v"), "\n") {
+			if strings.HasPrefix(line, "in-pack: ") {
+				return strings.TrimPrefix(line, "in-pack: ")
+			}
+		}
+		t.Fatal("count-objects did not report packed objects")
+		return ""
+	}
+	updateForkPools := func() {
+		t.Helper()
+		for _, fork := range forks {
+			if err := s.updateForkPool(ctx, s.dir(fork)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	pool := s.forkPoolDir(root)
+
+	// Linking moves all objects the forks share into the pool.
+	setForkPoolsEnabled(true)
+	updateForkPools()
+	for _, fork := range forks {
+		if have := forkPoolOf(s.dir(fork)); have != root {
+			t.Errorf("%s: unexpected fork pool %q", fork, have)
+		}
+		git(fork, "fsck", "--connectivity-only")
+	}
+	if have := packedObjects(forks[1]); have != "0" {
+		t.Errorf("expected all objects of the second fork to be borrowed, have %s", have)
+	}
+	if have := packedObjects(forks[0]); have != "0" {
+		t.Errorf("expected all objects of the first fork to be borrowed, have %s", have)
+	}
+	if have, want := runCmd(t, string(pool), "git", "for-each-ref", "--format=%(refname)", "refs/members/"+forkPoolMemberID(forks[0])+"/heads/"), "refs/members/"+forkPoolMemberID(forks[0])+"/heads/master\n"; have != want {
+		t.Errorf("unexpected refs of member in pool %q, want %q", have, want)
+	}
+
+	// Pools with members are kept.
+	if size := s.cleanupForkPools(ctx); size == 0 {
+		t.Error("expected size of pool to be reported")
+	}
+	if !repoCloned(pool) {
+		t.Fatal("expected pool to be kept")
+	}
+
+	// Removed forks are no longer members.
+	if err := s.removeRepoDirectory(s.dir(forks[0]), false); err != nil {
+		t.Fatal(err)
+	}
+	s.cleanupForkPools(ctx)
+	if have := runCmd(t, string(pool), "git", "for-each-ref", "refs/members/"+forkPoolMemberID(forks[0])+"/"); have != "" {
+		t.Errorf("expected refs of removed fork to be deleted, have %q", have)
+	}
+
+	// Disabling fork pools detaches the forks, after which the pool is removed.
+	forks = forks[1:]
+	setForkPoolsEnabled(false)
+	updateForkPools()
+	if _, err := os.Stat(s.dir(forks[0]).Path("objects", "info", "alternates")); !os.IsNotExist(err) {
+		t.Errorf("expected alternates to be removed: %v", err)
+	}
+	if have := forkPoolOf(s.dir(forks[0])); have != "" {
+		t.Errorf("unexpected fork pool %q", have)
+	}
+	if have := packedObjects(forks[0]); have == "0" {
+		t.Error("expected objects to be copied back into the fork")
+	}
+	s.cleanupForkPools(ctx)
+	if _, err := os.Stat(string(pool)); !os.IsNotExist(err) {
+		t.Fatalf("expected pool to be removed: %v", err)
+	}
+	git(forks[0], "fsck", "--connectivity-only")
+}
@@ -0,0 +1,334 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/fileutil"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var (
+	reposOffloaded = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_repos_offloaded",
+		Help: "number of repos offloaded to object storage due to not enough disk space",
+	})
+	reposRestored = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_repos_restored",
+		Help: "number of repos restored from object storage",
+	})
+)
+
+const (
+	// offloadedDirName is the name of the directory under ReposDir containing
+	// a marker file per offloaded repository, so that accessing a repository
+	// which is not cloned only looks up its offload key in the database if it
+	// was offloaded by this gitserver.
+	offloadedDirName = ".offloaded"
+
+	// offloadConcurrency is the number of repositories offloaded at once by
+	// freeUpSpace.
+	offloadConcurrency = 4
+
+	// offloadTimeout bounds the time freeUpSpace spends offloading
+	// repositories, so that disk space is freed up in time. Repositories which
+	// are not offloaded by then are removed anyway.
+	offloadTimeout = 10 * time.Minute
+)
+
+// offloadKey returns the key of the bundle the repository is offloaded to.
+func offloadKey(name api.RepoName) string {
+	return string(protocol.NormalizeRepo(name)) + ".bundle"
+}
+
+// offloadMarker returns the path of the marker file of an offloaded
+// repository.
+func (s *Server) offloadMarker(name api.RepoName) string {
+	return filepath.Join(s.ReposDir, offloadedDirName, offloadKey(name))
+}
+
+// offloadRepos offloads the repositories in dirs concurrently and returns the
+// ones which were offloaded. Replicas are cloned again by the next sync from
+// their primary, so they are not offloaded.
+func (s *Server) offloadRepos(dirs []GitDir) map[GitDir]bool {
+	ctx, cancel := context.WithTimeout(context.Background(), offloadTimeout)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		offloaded = make(map[GitDir]bool, len(dirs))
+		wg        sync.WaitGroup
+		sem       = make(chan struct{}, offloadConcurrency)
+	)
+	for _, d := range dirs {
+		if replicaOf(d) != "" {
+			continue
+		}
+		d := d
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			// If offloading fails, the repo is still removed and will be
+			// recloned from the code host when it's accessed again.
+			if err := s.offloadRepo(ctx, d); err != nil {
+				s.Logger.Warn("failed to offload repo", log.String("repo", string(d)), log.Error(err))
+				return
+			}
+			mu.Lock()
+			offloaded[d] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return offloaded
+}
+
+// offloadRepo streams a bundle of all refs of the repository in dir to
+// OffloadStore, marks the repository as offloaded and records the key of the
+// bundle in the database. The repository directory is left untouched; it's up
+// to the caller to remove it.
+func (s *Server) offloadRepo(ctx context.Context, dir GitDir) (err error) {
+	// Repositories imported from other VCSs keep the state of the import in
+	// the Git directory, which is not part of the bundle.
+	typ, err := getRepositoryType(dir)
+	if err != nil {
+		return errors.Wrap(err, "getting repository type")
+	}
+	switch typ {
+	case "perforce", "subversion", "mercurial":
+		return errors.Errorf("repositories of type %q cannot be offloaded", typ)
+	}
+	// Bundles must contain all objects, including the objects missing from
+	// partial clones.
+	if isPartialClone(dir) {
+		return errors.New("partial clones cannot be offloaded")
+	}
+
+	name := s.name(dir)
+	key := offloadKey(name)
+	marker := s.offloadMarker(name)
+	defer func() {
+		if err != nil {
+			// Remove what we may have stored, the repo will be recloned.
+			_ = os.Remove(marker)
+			if deleteErr := s.OffloadStore.Delete(context.Background(), key); deleteErr != nil {
+				s.Logger.Warn("failed to delete offloaded bundle", log.String("key", key), log.Error(deleteErr))
+			}
+		}
+	}()
+
+	// The bundle is streamed to the store instead of being written to disk
+	// first, because we are offloading to free up space on that disk.
+	bundleCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	cmd := exec.CommandContext(bundleCtx, "git", "bundle", "create", "-", "--all")
+	dir.Set(cmd)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return errors.Wrap(err, "creating bundle")
+	}
+	_, uploadErr := s.OffloadStore.Upload(ctx, key, stdout)
+	if uploadErr != nil {
+		// Stop git, which would otherwise block writing the rest of the
+		// bundle.
+		cancel()
+	}
+	if err := cmd.Wait(); err != nil && uploadErr == nil {
+		return errors.Wrapf(err, "creating bundle failed with output %q", stderr.String())
+	}
+	if uploadErr != nil {
+		return errors.Wrap(uploadErr, "uploading bundle")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(marker), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.WriteFile(marker, nil, 0o600); err != nil {
+		return err
+	}
+	return s.DB.GitserverRepos().SetOffloadKey(ctx, name, key)
+}
+
+// restoreOffloadedRepo restores repo from the bundle it was offloaded to, if
+// any, and reports whether it was restored. Failures are logged, so that
+// callers can fall back to cloning the repository.
+func (s *Server) restoreOffloadedRepo(ctx context.Context, repo api.RepoName) bool {
+	if s.OffloadStore == nil {
+		return false
+	}
+	// Only look up repos offloaded by this gitserver in the database.
+	marker := s.offloadMarker(repo)
+	if _, err := os.Stat(marker); err != nil {
+		return false
+	}
+
+	dir := s.dir(repo)
+	lock, ok := s.locker.TryAcquire(dir, "restoring offloaded repo")
+	if !ok {
+		return false
+	}
+	defer lock.Release()
+
+	return s.restoreOffloadedRepoLocked(ctx, repo, dir)
+}
+
+// restoreOffloadedRepoLocked is like restoreOffloadedRepo, but the caller must
+// hold the lock of dir.
+func (s *Server) restoreOffloadedRepoLocked(ctx context.Context, repo api.RepoName, dir GitDir) bool {
+	if s.OffloadStore == nil {
+		return false
+	}
+	marker := s.offloadMarker(repo)
+	if _, err := os.Stat(marker); err != nil {
+		return false
+	}
+
+	gr, err := s.DB.GitserverRepos().GetByName(ctx, repo)
+	if err != nil {
+		return false
+	}
+	if gr.OffloadKey == "" {
+		_ = os.Remove(marker)
+		return false
+	}
+
+	logger := s.Logger.With(log.String("repo", string(repo)), log.String("key", gr.OffloadKey))
+	if err := s.doRestore(ctx, repo, dir, gr.OffloadKey); err != nil {
+		logger.Warn("failed to restore offloaded repo", log.Error(err))
+		return false
+	}
+	reposRestored.Inc()
+	logger.Info("restored offloaded repo")
+
+	s.forgetOffloadedRepo(ctx, repo)
+	return true
+}
+
+// forgetOffloadedRepo deletes the bundle repo was offloaded to, if any. It is
+// called once repo is on disk again, because the bundle is outdated as soon as
+// the repository is fetched.
+func (s *Server) forgetOffloadedRepo(ctx context.Context, repo api.RepoName) {
+	if s.OffloadStore == nil {
+		return
+	}
+	marker := s.offloadMarker(repo)
+	if _, err := os.Stat(marker); err != nil {
+		return
+	}
+
+	logger := s.Logger.With(log.String("repo", string(repo)))
+	key := offloadKey(repo)
+	if err := s.DB.GitserverRepos().SetOffloadKey(ctx, repo, ""); err != nil {
+		logger.Warn("failed to clear offload key", log.Error(err))
+		return
+	}
+	if err := s.OffloadStore.Delete(ctx, key); err != nil {
+		logger.Warn("failed to delete offloaded bundle", log.String("key", key), log.Error(err))
+	}
+	if err := os.Remove(marker); err != nil {
+		logger.Warn("failed to remove offload marker", log.Error(err))
+	}
+}
+
+// doRestore downloads the bundle at key and clones it into dir. The caller
+// must hold the lock of dir.
+func (s *Server) doRestore(ctx context.Context, repo api.RepoName, dir GitDir, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, conf.GitLongCommandTimeout())
+	defer cancel()
+
+	tmpPath, err := s.tempDir("restore-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpPath)
+
+	bundle := filepath.Join(tmpPath, "repo.bundle")
+	if err := s.downloadBundle(ctx, key, bundle); err != nil {
+		return errors.Wrap(err, "downloading bundle")
+	}
+
+	tmp := GitDir(filepath.Join(tmpPath, ".git"))
+	// git clone picks the branch HEAD of the bundle points to as HEAD.
+	for _, args := range [][]string{
+		{"clone", "--mirror", bundle, string(tmp)},
+		// The bundle is removed below, so it must not be kept as remote.
+		{"--git-dir", string(tmp), "config", "--remove-section", "remote.origin"},
+	} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		if output, err := runWith(ctx, cmd, false, nil); err != nil {
+			return errors.Wrapf(err, "restoring bundle failed with output %q", string(output))
+		}
+	}
+
+	syncer, err := s.GetVCSSyncer(ctx, repo)
+	if err != nil {
+		return errors.Wrap(err, "get VCS syncer")
+	}
+	if err := setRepositoryType(tmp, syncer.Type()); err != nil {
+		return errors.Wrap(err, `git config set "sourcegraph.type"`)
+	}
+	if err := setLastChanged(tmp); err != nil {
+		return errors.Wrapf(err, "failed to update last changed time")
+	}
+	if err := setGitAttributes(tmp); err != nil {
+		return err
+	}
+	if err := gitSetAutoGC(tmp); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(string(dir)), os.ModePerm); err != nil {
+		return err
+	}
+	if err := fileutil.RenameAndSync(string(tmp), string(dir)); err != nil {
+		return err
+	}
+
+	s.setCloneStatusNonFatal(ctx, repo, types.CloneStatusCloned)
+	if err := s.setRepoSize(ctx, repo); err != nil {
+		s.Logger.Warn("failed setting repo size", log.String("repo", string(repo)), log.Error(err))
+	}
+	return nil
+}
+
+// downloadBundle writes the object at key to the file at path.
+func (s *Server) downloadBundle(ctx context.Context, key, path string) error {
+	rc, err := s.OffloadStore.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, rc); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,306 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// memoryStore is an in-memory uploadstore.Store standing in for S3-compatible
+// object storage.
+type memoryStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+var _ uploadstore.Store = &memoryStore{}
+
+func (s *memoryStore) Init(context.Context) error { return nil }
+
+func (s *memoryStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.objects[key]
+	if !ok {
+		return nil, errors.Errorf("no such key %q", key)
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (s *memoryStore) Upload(_ context.Context, key string, r io.Reader) (int64, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.objects == nil {
+		s.objects = map[string][]byte{}
+	}
+	s.objects[key] = b
+	return int64(len(b)), nil
+}
+
+func (s *memoryStore) Compose(context.Context, string, ...string) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (s *memoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
+
+func TestOffloadAndRestore(t *testing.T) {
+	ctx := context.Background()
+
+	remote := t.TempDir()
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, remote, name, arg...)
+	}
+	makeSingleCommitRepo(cmd)
+	cmd("git", "branch", "-m", "main")
+	cmd("git", "tag", "v1")
+	wantCommit := strings.TrimSpace(cmd("git", "rev-parse", "HEAD"))
+
+	reposDir := t.TempDir()
+	repo := api.RepoName("example.com/foo/bar")
+	gitDir := filepath.Join(reposDir, string(repo), ".git")
+	runCmd(t, reposDir, "git", "clone", "--mirror", remote, gitDir)
+	// Make the repo large enough to free up the requested space.
+	if err := os.WriteFile(filepath.Join(gitDir, "space_eater"), make([]byte, 1000), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	var offloadKey string
+	db := database.NewMockDB()
+	gr := database.NewMockGitserverRepoStore()
+	gr.SetOffloadKeyFunc.SetDefaultHook(func(_ context.Context, name api.RepoName, key string) error {
+		if name != repo {
+			t.Errorf("unexpected repo %q", name)
+		}
+		offloadKey = key
+		return nil
+	})
+	gr.GetByNameFunc.SetDefaultHook(func(context.Context, api.RepoName) (*types.GitserverRepo, error) {
+		return &types.GitserverRepo{OffloadKey: offloadKey}, nil
+	})
+	db.GitserverReposFunc.SetDefaultReturn(gr)
+
+	store := &memoryStore{}
+	s := &Server{
+		Logger:    logtest.Scoped(t),
+		ReposDir:  reposDir,
+		DiskSizer: &fakeDiskSizer{},
+		DB:        db,
+		GetVCSSyncer: func(context.Context, api.RepoName) (VCSSyncer, error) {
+			return &GitRepoSyncer{}, nil
+		},
+		OffloadStore: store,
+		locker:       &RepositoryLocker{},
+	}
+
+	if err := s.freeUpSpace(1000); err != nil {
+		t.Fatal(err)
+	}
+	if repoCloned(GitDir(gitDir)) {
+		t.Fatal("expected repo to be removed from disk")
+	}
+	if offloadKey != "example.com/foo/bar.bundle" {
+		t.Fatalf("unexpected offload key %q", offloadKey)
+	}
+	if _, ok := store.objects[offloadKey]; !ok {
+		t.Fatal("expected bundle to be uploaded")
+	}
+	if _, err := os.Stat(s.offloadMarker(repo)); err != nil {
+		t.Fatalf("expected repo to be marked as offloaded: %s", err)
+	}
+
+	// Offloaded repos are scheduled to be cloned again, which restores them
+	// from their bundle instead of cloning them from the code host.
+	lock, ok := s.locker.TryAcquire(GitDir(gitDir), "cloning")
+	if !ok {
+		t.Fatal("failed to acquire lock")
+	}
+	unreachable, _ := vcs.ParseURL("https://unreachable.invalid/foo/bar")
+	if err := s.doClone(ctx, repo, GitDir(gitDir), &GitRepoSyncer{}, lock, unreachable, nil); err != nil {
+		t.Fatalf("expected repo to be restored: %s", err)
+	}
+	git := func(arg ...string) string {
+		t.Helper()
+		return strings.TrimSpace(runCmd(t, gitDir, "git", arg...))
+	}
+	if have := git("symbolic-ref", "HEAD"); have != "refs/heads/main" {
+		t.Errorf("unexpected HEAD %q", have)
+	}
+	for _, ref := range []string{"HEAD", "v1"} {
+		if have := git("rev-parse", ref); have != wantCommit {
+			t.Errorf("%s is %q, want %q", ref, have, wantCommit)
+		}
+	}
+	if have := git("remote"); have != "" {
+		t.Errorf("unexpected remotes %q", have)
+	}
+
+	// The bundle is removed once the repo has been restored.
+	if offloadKey != "" {
+		t.Errorf("expected offload key to be cleared, have %q", offloadKey)
+	}
+	if len(store.objects) != 0 {
+		t.Errorf("expected bundle to be deleted, have %d objects", len(store.objects))
+	}
+	if _, err := os.Stat(s.offloadMarker(repo)); !os.IsNotExist(err) {
+		t.Errorf("expected offload marker to be removed, have %v", err)
+	}
+	if history := gr.SetCloneStatusFunc.History(); len(history) == 0 || history[len(history)-1].Arg2 != types.CloneStatusCloned {
+		t.Error("expected repo to be marked as cloned")
+	}
+}
+
+func TestOffloadRepo_uploadFails(t *testing.T) {
+	remote := t.TempDir()
+	makeSingleCommitRepo(func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, remote, name, arg...)
+	})
+
+	reposDir := t.TempDir()
+	repo := api.RepoName("example.com/foo/bar")
+	gitDir := filepath.Join(reposDir, string(repo), ".git")
+	runCmd(t, reposDir, "git", "clone", "--mirror", remote, gitDir)
+
+	db := database.NewMockDB()
+	gr := database.NewMockGitserverRepoStore()
+	db.GitserverReposFunc.SetDefaultReturn(gr)
+
+	s := &Server{
+		Logger:       logtest.Scoped(t),
+		ReposDir:     reposDir,
+		DB:           db,
+		OffloadStore: &failingStore{},
+	}
+	if err := s.offloadRepo(context.Background(), GitDir(gitDir)); err == nil {
+		t.Fatal("expected offloading to fail")
+	}
+	if len(gr.SetOffloadKeyFunc.History()) != 0 {
+		t.Fatal("expected no offload key to be set")
+	}
+	if _, err := os.Stat(s.offloadMarker(repo)); !os.IsNotExist(err) {
+		t.Errorf("expected no offload marker, have %v", err)
+	}
+}
+
+// failingStore fails uploads after reading part of the object.
+type failingStore struct {
+	memoryStore
+}
+
+func (s *failingStore) Upload(_ context.Context, _ string, r io.Reader) (int64, error) {
+	n, _ := io.ReadFull(r, make([]byte, 4))
+	return int64(n), errors.New("connection reset")
+}
+
+func TestRestoreOffloadedRepo_missingBundle(t *testing.T) {
+	db := database.NewMockDB()
+	gr := database.NewMockGitserverRepoStore()
+	gr.GetByNameFunc.SetDefaultReturn(&types.GitserverRepo{OffloadKey: "example.com/foo/bar.bundle"}, nil)
+	db.GitserverReposFunc.SetDefaultReturn(gr)
+
+	reposDir := t.TempDir()
+	s := &Server{
+		Logger:       logtest.Scoped(t),
+		ReposDir:     reposDir,
+		DB:           db,
+		OffloadStore: &memoryStore{},
+		locker:       &RepositoryLocker{},
+	}
+	writeOffloadMarker(t, s, "example.com/foo/bar")
+	if s.restoreOffloadedRepo(context.Background(), "example.com/foo/bar") {
+		t.Fatal("expected restore to fail")
+	}
+	if len(gr.SetOffloadKeyFunc.History()) != 0 {
+		t.Fatal("expected offload key to be kept")
+	}
+	assertPaths(t, reposDir, ".offloaded/example.com/foo/bar.bundle", ".tmp")
+}
+
+func TestRestoreOffloadedRepo_notOffloaded(t *testing.T) {
+	db := database.NewMockDB()
+	gr := database.NewMockGitserverRepoStore()
+	db.GitserverReposFunc.SetDefaultReturn(gr)
+
+	s := &Server{
+		Logger:       logtest.Scoped(t),
+		ReposDir:     t.TempDir(),
+		DB:           db,
+		OffloadStore: &memoryStore{},
+		locker:       &RepositoryLocker{},
+	}
+	if s.restoreOffloadedRepo(context.Background(), "example.com/foo/bar") {
+		t.Fatal("expected repo not to be restored")
+	}
+	// Repos without an offload marker are not looked up in the database.
+	if len(gr.GetByNameFunc.History()) != 0 {
+		t.Fatal("expected no database lookup")
+	}
+}
+
+func TestForgetOffloadedRepo(t *testing.T) {
+	db := database.NewMockDB()
+	gr := database.NewMockGitserverRepoStore()
+	db.GitserverReposFunc.SetDefaultReturn(gr)
+
+	repo := api.RepoName("example.com/foo/bar")
+	store := &memoryStore{objects: map[string][]byte{offloadKey(repo): []byte("bundle")}}
+	s := &Server{
+		Logger:       logtest.Scoped(t),
+		ReposDir:     t.TempDir(),
+		DB:           db,
+		OffloadStore: store,
+	}
+	writeOffloadMarker(t, s, repo)
+
+	s.forgetOffloadedRepo(context.Background(), repo)
+	if history := gr.SetOffloadKeyFunc.History(); len(history) != 1 || history[0].Arg2 != "" {
+		t.Fatalf("expected offload key to be cleared, have %v", history)
+	}
+	if len(store.objects) != 0 {
+		t.Errorf("expected bundle to be deleted, have %d objects", len(store.objects))
+	}
+	if _, err := os.Stat(s.offloadMarker(repo)); !os.IsNotExist(err) {
+		t.Errorf("expected offload marker to be removed, have %v", err)
+	}
+
+	// Repos which were not offloaded don't touch the database.
+	s.forgetOffloadedRepo(context.Background(), "example.com/foo/baz")
+	if len(gr.SetOffloadKeyFunc.History()) != 1 {
+		t.Fatal("expected no database update")
+	}
+}
+
+func writeOffloadMarker(t *testing.T, s *Server, repo api.RepoName) {
+	t.Helper()
+	marker := s.offloadMarker(repo)
+	if err := os.MkdirAll(filepath.Dir(marker), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(marker, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/groupcache/lru"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+// partialCloneRemote is the name of the remote partial clones fetch missing
+// objects from. Only its name is stored in the git config of the repository.
+// Its URL, which may contain credentials, is passed to each git command via
+// the environment.
+const partialCloneRemote = "promisor"
+
+// PartialCloneOptions configures GitRepoSyncer to clone repositories
+// partially. Blobs omitted from the clone are fetched from the code host when
+// a git command needs them.
+type PartialCloneOptions struct {
+	// BlobSizeLimit omits blobs larger than this number of bytes, if set.
+	BlobSizeLimit *int64
+	// SparseSpec omits the blobs of paths which don't match the
+	// sparse-checkout patterns in this blob of the remote repository, if set.
+	SparseSpec string
+}
+
+// filterArgs returns the git fetch arguments for the object filters of o.
+// Several filters are combined by git.
+func (o *PartialCloneOptions) filterArgs() []string {
+	var args []string
+	if o.BlobSizeLimit != nil {
+		args = append(args, "--filter=blob:limit="+strconv.FormatInt(*o.BlobSizeLimit, 10))
+	}
+	if o.SparseSpec != "" {
+		args = append(args, "--filter=sparse:oid="+o.SparseSpec)
+	}
+	return args
+}
+
+// partialCloneRemoteEnv returns the environment variables which configure
+// remoteURL as the URL of partialCloneRemote.
+func partialCloneRemoteEnv(remoteURL *vcs.URL) []string {
+	return []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=remote." + partialCloneRemote + ".url",
+		"GIT_CONFIG_VALUE_0=" + remoteURL.String(),
+	}
+}
+
+// isPartialClone reports whether the repository in dir is a partial clone,
+// i.e. whether it has a section for partialCloneRemote in its git config. Like
+// quickRevParseHead, it reads the file directly instead of executing git,
+// since it's called for each exec request.
+func isPartialClone(dir GitDir) bool {
+	f, err := os.Open(dir.Path("config"))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	want := []byte(`[remote "` + partialCloneRemote + `"]`)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if bytes.Equal(bytes.TrimSpace(sc.Bytes()), want) {
+			return true
+		}
+	}
+	return false
+}
+
+// partialCloneRemoteURLTTL is how long the remote URL of a partial clone is
+// cached. It is short, because the URL may contain credentials which expire.
+const partialCloneRemoteURLTTL = time.Minute
+
+type cachedRemoteURL struct {
+	url     *vcs.URL
+	expires time.Time
+}
+
+// partialCloneRemoteURL returns the remote URL of the partial clone of repo.
+// It is cached, since it's needed for each exec request.
+func (s *Server) partialCloneRemoteURL(ctx context.Context, repo api.RepoName) (*vcs.URL, error) {
+	s.partialCloneURLsMu.Lock()
+	if s.partialCloneURLs == nil {
+		s.partialCloneURLs = lru.New(1000)
+	}
+	v, ok := s.partialCloneURLs.Get(repo)
+	s.partialCloneURLsMu.Unlock()
+	if ok {
+		if cached := v.(cachedRemoteURL); time.Now().Before(cached.expires) {
+			return cached.url, nil
+		}
+	}
+
+	remoteURL, err := s.getRemoteURL(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	s.partialCloneURLsMu.Lock()
+	s.partialCloneURLs.Add(repo, cachedRemoteURL{url: remoteURL, expires: time.Now().Add(partialCloneRemoteURLTTL)})
+	s.partialCloneURLsMu.Unlock()
+	return remoteURL, nil
+}
+
+// forgetPartialCloneRemoteURL removes the remote URL of the partial clone of
+// repo from the cache.
+func (s *Server) forgetPartialCloneRemoteURL(repo api.RepoName) {
+	s.partialCloneURLsMu.Lock()
+	defer s.partialCloneURLsMu.Unlock()
+	if s.partialCloneURLs != nil {
+		s.partialCloneURLs.Remove(repo)
+	}
+}
+
+// partialCloneEnv returns the environment variables to add to git commands
+// run in the partial clone of repo, which allow git to fetch missing objects
+// from the code host.
+func (s *Server) partialCloneEnv(ctx context.Context, repo api.RepoName) ([]string, error) {
+	remoteURL, err := s.partialCloneRemoteURL(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Missing objects are fetched by a git fetch child process of the
+	// command, which inherits the remote options from the environment.
+	cmd := exec.Command("git")
+	cmd.Env = partialCloneRemoteEnv(remoteURL)
+	configureRemoteGitCommand(cmd, tlsExternal())
+	return cmd.Env, nil
+}
+
+// configurePartialCloneCommand adds to the environment of cmd, which runs in
+// the repository in dir, if it is a partial clone. Errors are only logged,
+// since the command still succeeds if it doesn't need missing objects.
+func (s *Server) configurePartialCloneCommand(ctx context.Context, repo api.RepoName, dir GitDir, cmd *exec.Cmd) (partial bool) {
+	if !isPartialClone(dir) {
+		return false
+	}
+	env, err := s.partialCloneEnv(ctx, repo)
+	if err != nil {
+		s.Logger.Warn("failed to configure fetching missing objects of partial clone", log.String("repo", string(repo)), log.Error(err))
+		return true
+	}
+	if cmd.Env == nil {
+		// Do not strip out existing env when adding to it.
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, env...)
+	return true
+}
+
+// stdErrIndicatesMissingObject returns true if the stderr output of a git
+// command run in a partial clone indicates a missing object could not be
+// fetched from the code host.
+func stdErrIndicatesMissingObject(stderr string) bool {
+	return strings.Contains(stderr, "from promisor remote")
+}
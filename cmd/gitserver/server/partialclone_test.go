@@ -0,0 +1,143 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+func TestPartialClone(t *testing.T) {
+	ctx := context.Background()
+
+	remote := t.TempDir()
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, remote, name, arg...)
+	}
+	cmd("git", "init", ".")
+	cmd("git", "config", "uploadpack.allowFilter", "true")
+	cmd("git", "config", "uploadpack.allowAnySHA1InWant", "true")
+	for _, name := range []string{"large-a", "large-b"} {
+		if err := os.WriteFile(filepath.Join(remote, name), bytes.Repeat([]byte(name), 1000), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd("sh", "-c", "echo hello world > hello.txt")
+	cmd("git", "add", ".")
+	cmd("git", "commit", "-m", "hello")
+
+	reposDir := t.TempDir()
+	repo := api.RepoName("example.com/foo/bar")
+	remoteURL := "file://" + remote
+
+	db := database.NewMockDB()
+	db.GitserverReposFunc.SetDefaultReturn(database.NewMockGitserverRepoStore())
+	s := &Server{
+		Logger:            logtest.Scoped(t),
+		ReposDir:          reposDir,
+		skipCloneForTests: true,
+		GetRemoteURLFunc: func(context.Context, api.RepoName) (string, error) {
+			return remoteURL, nil
+		},
+		DB: db,
+	}
+	h := s.Handler()
+
+	// Clone the repository without large blobs.
+	blobSizeLimit := int64(100)
+	syncer := &GitRepoSyncer{PartialClone: &PartialCloneOptions{BlobSizeLimit: &blobSizeLimit}}
+	u, err := vcs.ParseURL(remoteURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := s.dir(repo)
+	clone, err := syncer.CloneCommand(ctx, u, string(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output, err := runWith(ctx, clone, true, nil); err != nil {
+		t.Fatalf("clone failed: %s: %s", err, output)
+	}
+	if !isPartialClone(dir) {
+		t.Fatal("expected a partial clone")
+	}
+	if config, _ := os.ReadFile(dir.Path("config")); bytes.Contains(config, []byte(remote)) {
+		t.Fatalf("remote URL must not be stored in the git config:\n%s", config)
+	}
+	missing := runCmd(t, string(dir), "git", "rev-list", "--objects", "--missing=print", "--all")
+	if have := strings.Count(missing, "\n?"); have != 2 {
+		t.Fatalf("expected 2 missing blobs, have %d:\n%s", have, missing)
+	}
+
+	// Fetches only fetch small blobs too.
+	if err := syncer.Fetch(ctx, u, dir, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	exec := func(path string) (stdout string, trailer func(string) string) {
+		t.Helper()
+		body, err := json.Marshal(protocol.ExecRequest{Repo: repo, Args: []string{"show", "HEAD:" + path}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "/exec", bytes.NewReader(body)))
+		res := w.Result()
+		return w.Body.String(), res.Trailer.Get
+	}
+
+	// Missing blobs are fetched on demand.
+	stdout, trailer := exec("large-a")
+	if trailer("X-Exec-Exit-Status") != "0" {
+		t.Fatalf("exec failed: %s", trailer("X-Exec-Stderr"))
+	}
+	if want := strings.Repeat("large-a", 1000); stdout != want {
+		t.Errorf("unexpected content of length %d", len(stdout))
+	}
+
+	// Blobs which can't be fetched are reported as missing objects.
+	remoteURL = "file://" + filepath.Join(reposDir, "gone")
+	s.forgetPartialCloneRemoteURL(repo)
+	_, trailer = exec("large-b")
+	if have := trailer("X-Exec-Error"); !strings.Contains(have, gitdomain.MissingObjectErrorMessage) {
+		t.Errorf("unexpected exec error %q", have)
+	}
+	if _, trailer = exec("large-a"); trailer("X-Exec-Exit-Status") != "0" {
+		t.Errorf("expected fetched blob to be available: %s", trailer("X-Exec-Stderr"))
+	}
+}
+
+func TestPartialCloneRefspecOverrides(t *testing.T) {
+	orig := refspecOverrides
+	refspecOverrides = []string{"+refs/heads/main:refs/heads/main"}
+	t.Cleanup(func() { refspecOverrides = orig })
+
+	blobSizeLimit := int64(100)
+	syncer := &GitRepoSyncer{PartialClone: &PartialCloneOptions{BlobSizeLimit: &blobSizeLimit}}
+	u, err := vcs.ParseURL("https://example.com/foo/bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, configRemoteOpts := syncer.fetchCommand(context.Background(), u)
+	if !configRemoteOpts {
+		t.Fatal("expected remote options to be configured")
+	}
+	want := "git fetch --no-auto-gc --progress --prune --filter=blob:limit=100 promisor +refs/heads/main:refs/heads/main"
+	if got := strings.Join(cmd.Args, " "); got != want {
+		t.Fatalf("unexpected fetch command:\ngot:  %s\nwant: %s", got, want)
+	}
+}
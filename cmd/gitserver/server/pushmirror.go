@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// pushMirrorMaxAttempts is how many times a failed push to a push mirror is
+// attempted before its error is recorded.
+const pushMirrorMaxAttempts = 5
+
+// pushMirrorRefspecs are the refs pushed to push mirrors. Refs which no longer
+// exist are deleted from the mirror.
+var pushMirrorRefspecs = []string{"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*"}
+
+// pushMirrorBackoff is the delay before the first retry of a failed push to a
+// push mirror. It doubles with every attempt.
+var pushMirrorBackoff = 5 * time.Second
+
+var pushMirrorPushes = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_gitserver_push_mirror_pushes_total",
+	Help: "number of pushes of repositories to their push mirror",
+}, []string{"success"})
+
+// pushToMirror pushes the branches and tags of repo to the push mirror
+// configured for its code host, if any. The push is retried with backoff in
+// the background, and its status is recorded in the database. Pushes
+// requested while one is running are coalesced into one push once it is done.
+func (s *Server) pushToMirror(ctx context.Context, repo api.RepoName) {
+	dir := s.dir(repo)
+	// Replicas are pushed by their primary.
+	if s.GetVCSSyncer == nil || !repoCloned(dir) || replicaOf(dir) != "" {
+		return
+	}
+	syncer, err := s.GetVCSSyncer(actor.WithInternalActor(ctx), repo)
+	if err != nil {
+		s.Logger.Warn("failed to get VCS syncer for push mirror", log.String("repo", string(repo)), log.Error(err))
+		return
+	}
+	gitSyncer, ok := syncer.(*GitRepoSyncer)
+	if !ok || gitSyncer.PushMirrorURL == "" {
+		return
+	}
+	mirrorURL, err := vcs.ParseURL(gitSyncer.PushMirrorURL)
+	if err != nil {
+		// 🚨 SECURITY: The error may contain the credentials in the URL.
+		s.setPushMirrorStatusNonFatal(ctx, repo, errors.New("invalid push mirror URL"))
+		return
+	}
+
+	s.pushMirrorsMu.Lock()
+	if _, running := s.pushMirrors[repo]; running {
+		s.pushMirrors[repo] = true
+		s.pushMirrorsMu.Unlock()
+		return
+	}
+	if s.pushMirrors == nil {
+		s.pushMirrors = make(map[api.RepoName]bool)
+	}
+	s.pushMirrors[repo] = false
+	s.pushMirrorsMu.Unlock()
+
+	ctx, cancel := s.serverContext()
+	go func() {
+		defer cancel()
+		for {
+			err := s.pushMirrorWithRetries(ctx, repo, dir, mirrorURL)
+			pushMirrorPushes.WithLabelValues(strconv.FormatBool(err == nil)).Inc()
+			if err != nil {
+				s.Logger.Warn("failed to push to push mirror", log.String("repo", string(repo)), log.Error(err))
+			}
+			// Use a background context to ensure we still update the DB even if we time out.
+			s.setPushMirrorStatusNonFatal(context.Background(), repo, err)
+
+			s.pushMirrorsMu.Lock()
+			again := s.pushMirrors[repo] && ctx.Err() == nil
+			if !again {
+				delete(s.pushMirrors, repo)
+			} else {
+				s.pushMirrors[repo] = false
+			}
+			s.pushMirrorsMu.Unlock()
+			if !again {
+				return
+			}
+		}
+	}()
+}
+
+// pushMirrorWithRetries pushes the repository in dir to mirrorURL, and retries
+// failed pushes with exponential backoff.
+func (s *Server) pushMirrorWithRetries(ctx context.Context, repo api.RepoName, dir GitDir, mirrorURL *vcs.URL) error {
+	// Pushing a partial clone would fetch every missing object from the code
+	// host first.
+	if isPartialClone(dir) {
+		return errors.New("partial clones can't be pushed to a push mirror")
+	}
+
+	backoff := pushMirrorBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = pushMirror(ctx, dir, mirrorURL); err == nil || attempt == pushMirrorMaxAttempts {
+			return err
+		}
+		s.Logger.Debug("retrying push to push mirror", log.String("repo", string(repo)), log.Int("attempt", attempt), log.Error(err))
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// pushMirror pushes the branches and tags of the repository in dir to
+// mirrorURL, and deletes the ones which no longer exist from it.
+func pushMirror(ctx context.Context, dir GitDir, mirrorURL *vcs.URL) error {
+	ctx, cancel := context.WithTimeout(ctx, conf.GitLongCommandTimeout())
+	defer cancel()
+
+	args := []string{"push", "--porcelain", "--prune", mirrorURL.String()}
+	args = append(args, pushMirrorRefspecs...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	dir.Set(cmd)
+	if output, err := runWith(ctx, cmd, true, nil); err != nil {
+		return errors.Wrapf(err, "failed to push with output %q", newURLRedactor(mirrorURL).redact(string(output)))
+	}
+	return nil
+}
+
+func (s *Server) setPushMirrorStatusNonFatal(ctx context.Context, repo api.RepoName, pushErr error) {
+	var pushError string
+	if pushErr != nil {
+		pushError = pushErr.Error()
+	}
+	if err := s.DB.GitserverRepos().SetPushMirrorStatus(ctx, repo, pushError); err != nil {
+		s.Logger.Warn("Setting push mirror status in DB", log.Error(err))
+	}
+}
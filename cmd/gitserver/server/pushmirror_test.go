@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+func TestPushToMirror(t *testing.T) {
+	ctx := context.Background()
+
+	reposDir := t.TempDir()
+	repo := api.RepoName("example.com/foo/bar")
+	mirror := t.TempDir()
+	runCmd(t, mirror, "git", "init", "--bare", ".")
+
+	gitserverRepos := database.NewMockGitserverRepoStore()
+	db := database.NewMockDB()
+	db.GitserverReposFunc.SetDefaultReturn(gitserverRepos)
+	syncer := &GitRepoSyncer{PushMirrorURL: "file://" + mirror}
+	s := &Server{
+		Logger:   logtest.Scoped(t),
+		ReposDir: reposDir,
+		GetVCSSyncer: func(context.Context, api.RepoName) (VCSSyncer, error) {
+			return syncer, nil
+		},
+		DB:  db,
+		ctx: ctx,
+	}
+
+	dir := s.dir(repo)
+	if err := os.MkdirAll(filepath.Dir(string(dir)), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return strings.TrimSpace(runCmd(t, filepath.Dir(string(dir)), name, arg...))
+	}
+	cmd("git", "init", "--initial-branch=main", ".")
+	cmd("git", "commit", "--allow-empty", "-m", "c1")
+	cmd("git", "tag", "v1")
+	cmd("git", "branch", "stale")
+	head := cmd("git", "rev-parse", "HEAD")
+
+	// Branches and tags are pushed, and the success is recorded.
+	s.pushToMirror(ctx, repo)
+	s.wg.Wait()
+	refs := runCmd(t, mirror, "git", "for-each-ref", "--format=%(refname) %(objectname)")
+	for _, want := range []string{"refs/heads/main " + head, "refs/heads/stale " + head, "refs/tags/v1 " + head} {
+		if !strings.Contains(refs, want) {
+			t.Errorf("expected mirror to contain %q, got:\n%s", want, refs)
+		}
+	}
+	if calls := gitserverRepos.SetPushMirrorStatusFunc.History(); len(calls) != 1 || calls[0].Arg2 != "" {
+		t.Fatalf("expected a successful push to be recorded, got %+v", calls)
+	}
+
+	// Deleted branches are pruned from the mirror.
+	cmd("git", "branch", "-D", "stale")
+	s.pushToMirror(ctx, repo)
+	s.wg.Wait()
+	if refs := runCmd(t, mirror, "git", "for-each-ref", "--format=%(refname)"); strings.Contains(refs, "refs/heads/stale") {
+		t.Errorf("expected stale branch to be pruned from mirror, got:\n%s", refs)
+	}
+
+	// Failed pushes are retried before their error is recorded.
+	defer func(backoff time.Duration) { pushMirrorBackoff = backoff }(pushMirrorBackoff)
+	pushMirrorBackoff = time.Millisecond
+	syncer.PushMirrorURL = "file://" + filepath.Join(mirror, "missing")
+	s.pushToMirror(ctx, repo)
+	s.wg.Wait()
+	calls := gitserverRepos.SetPushMirrorStatusFunc.History()
+	if last := calls[len(calls)-1]; last.Arg2 == "" {
+		t.Fatal("expected a failed push to be recorded")
+	}
+}
@@ -0,0 +1,270 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/fileutil"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// gitConfigReplicaOf marks a repository as a replica. Its value is the address
+// of the gitserver owning the repository.
+const gitConfigReplicaOf = "sourcegraph.replicaOf"
+
+var replicaSyncs = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_gitserver_replica_syncs_total",
+	Help: "number of replicas synced from their primary gitserver",
+}, []string{"success"})
+
+// replicaOf returns the address of the gitserver owning the repository in dir
+// if it is a replica, or an empty string otherwise.
+func replicaOf(dir GitDir) string {
+	addr, _ := gitConfigGet(dir, gitConfigReplicaOf)
+	return addr
+}
+
+// isReplica reports whether the repository in dir is a replica of a repository
+// owned by another gitserver. Replicas of repositories now owned by this
+// instance, e.g. because their gitserver was removed, are promoted to regular
+// repositories.
+func (s *Server) isReplica(ctx context.Context, repo api.RepoName, dir GitDir) bool {
+	if replicaOf(dir) == "" {
+		return false
+	}
+	addr, err := s.addrForRepo(ctx, repo, currentGitserverAddresses())
+	if err != nil || !s.hostnameMatch(addr) {
+		return true
+	}
+	s.promoteReplica(ctx, repo, dir)
+	return false
+}
+
+// promoteReplica turns the replica in dir into a regular repository owned by
+// this instance.
+func (s *Server) promoteReplica(ctx context.Context, repo api.RepoName, dir GitDir) {
+	logger := s.Logger.With(log.String("repo", string(repo)))
+	if err := gitConfigUnset(dir, gitConfigReplicaOf); err != nil {
+		logger.Warn("failed to promote replica", log.Error(err))
+		return
+	}
+	logger.Info("promoted replica")
+
+	s.setReplicaStatusNonFatal(ctx, repo, false)
+	// Records this instance as the shard of the repository.
+	if err := s.setLastFetched(ctx, repo); err != nil {
+		logger.Warn("failed setting last fetch in DB", log.Error(err))
+	}
+	if err := s.setRepoSize(ctx, repo); err != nil {
+		logger.Warn("failed setting repo size", log.Error(err))
+	}
+}
+
+// isReplicaTarget reports whether this instance keeps a replica of repo, which
+// is owned by the gitserver at primary.
+func (s *Server) isReplicaTarget(repo api.RepoName, primary string, addrs []string) bool {
+	for _, addr := range gitserver.ReplicaAddrsForRepo(repo, primary, addrs, gitserver.ReplicationFactor()) {
+		if s.hostnameMatch(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ownedByOtherShard reports whether replication is enabled and repo is owned
+// by another gitserver. Such repositories are only synced from their primary,
+// and never cloned from the code host.
+func (s *Server) ownedByOtherShard(ctx context.Context, repo api.RepoName) bool {
+	if gitserver.ReplicationFactor() <= 1 {
+		return false
+	}
+	addr, err := s.addrForRepo(ctx, repo, currentGitserverAddresses())
+	return err == nil && !s.hostnameMatch(addr)
+}
+
+// syncReplicas asks the gitservers keeping replicas of repo to sync them from
+// this instance, if it owns repo. The replicas are synced in the background.
+func (s *Server) syncReplicas(ctx context.Context, repo api.RepoName) {
+	factor := gitserver.ReplicationFactor()
+	if factor <= 1 || !canReplicate(s.dir(repo)) {
+		return
+	}
+
+	gitServerAddrs := currentGitserverAddresses()
+	primary, err := s.addrForRepo(ctx, repo, gitServerAddrs)
+	if err != nil || !s.hostnameMatch(primary) {
+		return
+	}
+	replicas := gitserver.ReplicaAddrsForRepo(repo, primary, gitServerAddrs.Addresses, factor)
+	if len(replicas) == 0 {
+		return
+	}
+
+	ctx, cancel := s.serverContext()
+	go func() {
+		defer cancel()
+		for _, addr := range replicas {
+			if err := requestReplicaSync(ctx, addr, primary, repo); err != nil {
+				s.Logger.Warn("failed to sync replica",
+					log.String("repo", string(repo)),
+					log.String("replica", addr),
+					log.Error(err),
+				)
+			}
+		}
+	}()
+}
+
+// canReplicate reports whether the repository in dir can be replicated.
+// Replicas only mirror the refs of their primary.
+func canReplicate(dir GitDir) bool {
+	// Replicas are fetched from the primary, which can't serve the objects
+	// missing from partial clones.
+	if isPartialClone(dir) {
+		return false
+	}
+	// Repositories imported from Subversion and Mercurial keep the state of
+	// the import in the Git directory, so a promoted replica couldn't continue
+	// the import. They are cloned again by their new gitserver instead.
+	typ, _ := getRepositoryType(dir)
+	return typ != "subversion" && typ != "mercurial"
+}
+
+// requestReplicaSync asks the gitserver at addr to sync its replica of repo
+// from the gitserver at primary.
+func requestReplicaSync(ctx context.Context, addr, primary string, repo api.RepoName) error {
+	body, err := json.Marshal(&protocol.RepoUpdateRequest{
+		Repo:               repo,
+		ReplicateFromShard: primary,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "http://"+addr+"/repo-update", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpcli.InternalDoer.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return errors.Errorf("http status %d: %s", resp.StatusCode, string(b))
+	}
+
+	var info protocol.RepoUpdateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return err
+	}
+	if info.Error != "" {
+		return errors.New(info.Error)
+	}
+	return nil
+}
+
+// syncReplica clones or fetches the replica of repo from the gitserver at
+// primary, and records in the database whether the replica is in sync.
+func (s *Server) syncReplica(ctx context.Context, repo api.RepoName, primary string) (err error) {
+	if s.hostnameMatch(primary) {
+		return errors.New("cannot replicate from the same gitserver instance")
+	}
+
+	dir := s.dir(repo)
+	lock, ok := s.locker.TryAcquire(dir, "syncing replica")
+	if !ok {
+		return errors.New("another operation is already in progress")
+	}
+	defer lock.Release()
+
+	defer func() {
+		replicaSyncs.WithLabelValues(strconv.FormatBool(err == nil)).Inc()
+		// Use a background context to ensure we still update the DB even if we time out.
+		s.setReplicaStatusNonFatal(context.Background(), repo, err == nil)
+	}()
+
+	remoteURL, err := vcs.ParseURL("http://" + primary + "/git/" + string(repo))
+	if err != nil {
+		return err
+	}
+
+	if repoCloned(dir) {
+		return s.fetchReplica(ctx, repo, dir, remoteURL, primary)
+	}
+
+	tmpPath, err := s.tempDir("replica-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpPath)
+	tmp := GitDir(filepath.Join(tmpPath, ".git"))
+
+	cmd := exec.CommandContext(ctx, "git", "init", "--bare", string(tmp))
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "replica setup failed with output %q", string(output))
+	}
+	if err := s.fetchReplica(ctx, repo, tmp, remoteURL, primary); err != nil {
+		return err
+	}
+	if err := setGitAttributes(tmp); err != nil {
+		return err
+	}
+	if err := gitSetAutoGC(tmp); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(string(dir)), os.ModePerm); err != nil {
+		return err
+	}
+	return fileutil.RenameAndSync(string(tmp), string(dir))
+}
+
+// fetchReplica updates all refs and HEAD of the replica in dir to match the
+// repository at remoteURL, on the gitserver at primary.
+func (s *Server) fetchReplica(ctx context.Context, repo api.RepoName, dir GitDir, remoteURL *vcs.URL, primary string) error {
+	ctx, cancel := context.WithTimeout(ctx, conf.GitLongCommandTimeout())
+	defer cancel()
+
+	// Replicas mirror all refs, including the refs of imports from other VCSs.
+	cmd := exec.CommandContext(ctx, "git", "fetch", "--no-auto-gc", "--prune", remoteURL.String(), "+refs/*:refs/*")
+	dir.Set(cmd)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "failed to fetch replica with output %q", string(output))
+	}
+
+	if err := setHEAD(ctx, dir, &GitRepoSyncer{}, repo, remoteURL); err != nil {
+		return errors.Wrap(err, "failed to ensure HEAD exists")
+	}
+	if err := setLastChanged(dir); err != nil {
+		return errors.Wrap(err, "failed to update last changed time")
+	}
+	return gitConfigSet(dir, gitConfigReplicaOf, primary)
+}
+
+func (s *Server) setReplicaStatusNonFatal(ctx context.Context, name api.RepoName, inSync bool) {
+	if err := s.DB.GitserverRepos().SetReplicaStatus(ctx, name, s.Hostname, inSync); err != nil {
+		s.Logger.Warn("Setting replica status in DB", log.Error(err))
+	}
+}
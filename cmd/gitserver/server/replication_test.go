@@ -0,0 +1,148 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func TestHandleRepoUpdateReplica(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	repoName := api.RepoName("example.com/foo/bar")
+
+	// The primary serves the repository in its repos dir.
+	reposDirPrimary := t.TempDir()
+	remote := filepath.Join(reposDirPrimary, string(repoName))
+	if err := os.MkdirAll(remote, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, remote, name, arg...)
+	}
+	makeSingleCommitRepo(cmd)
+	cmd("git", "branch", "-m", "main")
+	cmd("git", "tag", "v1")
+
+	srv := httptest.NewServer(makeTestServer(ctx, t, reposDirPrimary, remote, nil).Handler())
+	defer srv.Close()
+	primary := strings.TrimPrefix(srv.URL, "http://")
+
+	db := database.NewMockDB()
+	gr := database.NewMockGitserverRepoStore()
+	db.GitserverReposFunc.SetDefaultReturn(gr)
+	s := makeTestServer(ctx, t, t.TempDir(), "", db)
+	s.Hostname = "gitserver-replica"
+	// We need some of the side effects here
+	_ = s.Handler()
+
+	sync := func() {
+		t.Helper()
+		body, err := json.Marshal(protocol.RepoUpdateRequest{
+			Repo:               repoName,
+			ReplicateFromShard: primary,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		s.handleRepoUpdate(rr, httptest.NewRequest("POST", "/repo-update", bytes.NewReader(body)))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("unexpected status code: %d", rr.Code)
+		}
+		var resp protocol.RepoUpdateResponse
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Error != "" {
+			t.Fatalf("unexpected error: %s", resp.Error)
+		}
+	}
+	dir := s.dir(repoName)
+	git := func(arg ...string) string {
+		t.Helper()
+		return strings.TrimSpace(runCmd(t, string(dir), "git", arg...))
+	}
+	assertInSync := func() {
+		t.Helper()
+		for _, ref := range []string{"main", "v1"} {
+			if have, want := git("rev-parse", ref), strings.TrimSpace(cmd("git", "rev-parse", ref)); have != want {
+				t.Errorf("%s is %q, want %q", ref, have, want)
+			}
+		}
+		history := gr.SetReplicaStatusFunc.History()
+		if len(history) == 0 {
+			t.Fatal("expected replica status to be set")
+		}
+		if call := history[len(history)-1]; call.Arg2 != s.Hostname || !call.Arg3 {
+			t.Errorf("unexpected replica status %q %v", call.Arg2, call.Arg3)
+		}
+	}
+
+	// The first sync clones the replica.
+	sync()
+	assertInSync()
+	if have := git("symbolic-ref", "HEAD"); have != "refs/heads/main" {
+		t.Errorf("unexpected HEAD %q", have)
+	}
+	if have := replicaOf(dir); have != primary {
+		t.Errorf("unexpected primary %q", have)
+	}
+
+	// Later syncs fetch the changes of the primary.
+	cmd("sh", "-c", "echo more >> hello.txt")
+	addCommitToRepo(cmd)
+	cmd("git", "tag", "-d", "v1")
+	cmd("git", "tag", "v1")
+	sync()
+	assertInSync()
+
+	// The clone status belongs to the primary, so it's never changed by the
+	// replica.
+	if err := s.removeRepoDirectory(dir, true); err != nil {
+		t.Fatal(err)
+	}
+	if history := gr.SetReplicaStatusFunc.History(); history[len(history)-1].Arg3 {
+		t.Error("expected replica to be marked as not in sync")
+	}
+	if len(gr.SetCloneStatusFunc.History()) != 0 {
+		t.Error("expected clone status to be unchanged")
+	}
+	if len(gr.SetLastFetchedFunc.History()) != 0 {
+		t.Error("expected last fetched to be unchanged")
+	}
+}
+
+func TestCanReplicate(t *testing.T) {
+	for typ, want := range map[string]bool{
+		"":           true,
+		"perforce":   true,
+		"subversion": false,
+		"mercurial":  false,
+	} {
+		t.Run(typ, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), ".git")
+			runCmd(t, t.TempDir(), "git", "init", "--bare", dir)
+			if typ != "" {
+				if err := setRepositoryType(GitDir(dir), typ); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if have := canReplicate(GitDir(dir)); have != want {
+				t.Errorf("canReplicate is %v, want %v", have, want)
+			}
+		})
+	}
+}
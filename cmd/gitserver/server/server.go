@@ -25,6 +25,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/golang/groupcache/lru"
 	"github.com/opentracing/opentracing-go/ext"
 	otlog "github.com/opentracing/opentracing-go/log"
 	"github.com/prometheus/client_golang/prometheus"
@@ -57,6 +58,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
 	"github.com/sourcegraph/sourcegraph/internal/vcs"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -304,6 +306,12 @@ type Server struct {
 	// shared db handle
 	DB database.DB
 
+	// OffloadStore is the object storage repositories are offloaded to when
+	// they are removed from disk to free up space. Offloaded repositories are
+	// restored from it instead of being recloned from the code host. If nil,
+	// repositories are deleted.
+	OffloadStore uploadstore.Store
+
 	// CloneQueue is a threadsafe queue used by DoBackgroundClones to process incoming clone
 	// requests asynchronously.
 	CloneQueue *cloneQueue
@@ -335,6 +343,16 @@ type Server struct {
 	repoUpdateLocksMu sync.Mutex // protects the map below and also updates to locks.once
 	repoUpdateLocks   map[api.RepoName]*locks
 
+	pushMirrorsMu sync.Mutex // protects the map below
+	// pushMirrors tracks the repositories being pushed to their push mirror. A
+	// true value requests another push once the running one is done.
+	pushMirrors map[api.RepoName]bool
+
+	partialCloneURLsMu sync.Mutex // protects the cache below
+	// partialCloneURLs caches the remote URLs of partial clones, which are
+	// needed by every git command run in them. It is created lazily.
+	partialCloneURLs *lru.Cache
+
 	// GlobalBatchLogSemaphore is a semaphore shared between all requests to ensure that a
 	// maximum number of Git subprocesses are active for all /batch-log requests combined.
 	GlobalBatchLogSemaphore *semaphore.Weighted
@@ -451,6 +469,11 @@ func (s *Server) Handler() http.Handler {
 		s.handleExec,
 	)))
 	mux.HandleFunc("/search", trace.WithRouteName("search", s.handleSearch))
+	mux.HandleFunc("/blame", trace.WithRouteName("blame", accesslog.HTTPMiddleware(
+		s.Logger.Scoped("blame.accesslog", "blame endpoint access log"),
+		conf.DefaultClient(),
+		s.handleBlame,
+	)))
 	mux.HandleFunc("/batch-log", trace.WithRouteName("batch-log", s.handleBatchLog))
 	mux.HandleFunc("/p4-exec", trace.WithRouteName("p4-exec", accesslog.HTTPMiddleware(
 		s.Logger.Scoped("p4-exec.accesslog", "p4-exec endpoint access log"),
@@ -500,6 +523,24 @@ func (s *Server) Handler() http.Handler {
 			conf.DefaultClient(),
 			handleGetObject(getObjectFunc),
 		)))
+	mux.HandleFunc("/commands/is-ancestor", trace.WithRouteName("commands/is-ancestor",
+		accesslog.HTTPMiddleware(
+			s.Logger.Scoped("commands/is-ancestor.accesslog", "commands/is-ancestor endpoint access log"),
+			conf.DefaultClient(),
+			s.handleIsAncestor,
+		)))
+	mux.HandleFunc("/commands/nearest-ancestors-in", trace.WithRouteName("commands/nearest-ancestors-in",
+		accesslog.HTTPMiddleware(
+			s.Logger.Scoped("commands/nearest-ancestors-in.accesslog", "commands/nearest-ancestors-in endpoint access log"),
+			conf.DefaultClient(),
+			s.handleNearestAncestorsIn,
+		)))
+	mux.HandleFunc("/commands/commits-between", trace.WithRouteName("commands/commits-between",
+		accesslog.HTTPMiddleware(
+			s.Logger.Scoped("commands/commits-between.accesslog", "commands/commits-between endpoint access log"),
+			conf.DefaultClient(),
+			s.handleCommitsBetween,
+		)))
 
 	return mux
 }
@@ -882,11 +923,13 @@ func (s *Server) tempDir(prefix string) (name string, err error) {
 }
 
 func (s *Server) ignorePath(path string) bool {
-	// We ignore any path which starts with .tmp in ReposDir
+	// We ignore any path which starts with .tmp in ReposDir, and the fork
+	// pools and offload markers which are not repositories themselves.
 	if filepath.Dir(path) != s.ReposDir {
 		return false
 	}
-	return strings.HasPrefix(filepath.Base(path), tempDirName)
+	base := filepath.Base(path)
+	return strings.HasPrefix(base, tempDirName) || base == forkPoolsDirName || base == offloadedDirName
 }
 
 func (s *Server) handleIsRepoCloneable(w http.ResponseWriter, r *http.Request) {
@@ -960,7 +1003,12 @@ func (s *Server) handleRepoUpdate(w http.ResponseWriter, r *http.Request) {
 	defer cancel1()
 	ctx, cancel2 := context.WithTimeout(ctx, conf.GitLongCommandTimeout())
 	defer cancel2()
-	if !repoCloned(dir) && !s.skipCloneForTests {
+	if req.ReplicateFromShard != "" {
+		if err := s.syncReplica(ctx, req.Repo, req.ReplicateFromShard); err != nil {
+			logger.Warn("error syncing replica", log.String("repo", string(req.Repo)), log.Error(err))
+			resp.Error = err.Error()
+		}
+	} else if !repoCloned(dir) && !s.skipCloneForTests {
 		// We do not need to check if req.CloneFromShard is non-zero here since that has no effect on
 		// the code path at this point. Since the repo is already not cloned at this point, either
 		// this request was received for a repo migration or a regular clone - for both of which we
@@ -971,12 +1019,19 @@ func (s *Server) handleRepoUpdate(w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			logger.Warn("error cloning repo", log.String("repo", string(req.Repo)), log.Error(err))
 			resp.Error = err.Error()
+		} else {
+			s.syncReplicas(ctx, req.Repo)
+			s.pushToMirror(ctx, req.Repo)
 		}
 	} else {
 		var statusErr, updateErr error
 
 		if debounce(req.Repo, req.Since) {
 			updateErr = s.doRepoUpdate(ctx, req.Repo, "")
+			if updateErr == nil {
+				s.syncReplicas(ctx, req.Repo)
+				s.pushToMirror(ctx, req.Repo)
+			}
 		}
 
 		// attempts to acquire these values are not contingent on the success of
@@ -1203,6 +1258,17 @@ func (s *Server) search(ctx context.Context, args *protocol.SearchRequest, match
 		}
 	}
 
+	// Diffs of partial clones may need blobs which are fetched on demand.
+	var env []string
+	if isPartialClone(dir) {
+		partialEnv, err := s.partialCloneEnv(ctx, args.Repo)
+		if err != nil {
+			s.Logger.Warn("failed to configure fetching missing objects of partial clone", log.String("repo", string(args.Repo)), log.Error(err))
+		} else {
+			env = append(os.Environ(), partialEnv...)
+		}
+	}
+
 	g, ctx := errgroup.WithContext(ctx)
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -1222,6 +1288,7 @@ func (s *Server) search(ctx context.Context, args *protocol.SearchRequest, match
 			Logger:               s.Logger,
 			RepoName:             args.Repo,
 			RepoDir:              dir.Path(),
+			Env:                  env,
 			Revisions:            args.Revisions,
 			Query:                mt,
 			IncludeDiff:          args.IncludeDiff,
@@ -1591,7 +1658,7 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 	}
 
 	dir := s.dir(req.Repo)
-	if !repoCloned(dir) {
+	if !repoCloned(dir) && !s.restoreOffloadedRepo(ctx, req.Repo) {
 		if conf.Get().DisableAutoGitUpdates {
 			s.Logger.Debug("not cloning on demand as DisableAutoGitUpdates is set")
 			status = "repo-not-found"
@@ -1600,6 +1667,16 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 			return
 		}
 
+		// Reads fail over to replicas when the primary is unreachable. If
+		// the replica is missing, we don't clone a copy no gitserver owns.
+		if s.ownedByOtherShard(ctx, req.Repo) {
+			s.Logger.Debug("not cloning on demand as the repo is owned by another shard", log.String("repo", string(req.Repo)))
+			status = "repo-not-found"
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{})
+			return
+		}
+
 		cloneProgress, cloneInProgress := s.locker.Status(dir)
 		if cloneInProgress {
 			status = "clone-in-progress"
@@ -1678,6 +1755,7 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 	dir.Set(cmd)
 	cmd.Stdout = stdoutW
 	cmd.Stderr = stderrW
+	partialClone := s.configurePartialCloneCommand(ctx, req.Repo, dir, cmd)
 
 	exitStatus, execErr = runCommand(ctx, cmd)
 
@@ -1687,6 +1765,13 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 
 	stderr := stderrBuf.String()
 	checkMaybeCorruptRepo(req.Repo, dir, stderr)
+	if partialClone && execErr != nil && stdErrIndicatesMissingObject(stderr) {
+		// The cached remote URL may be outdated, e.g. if its credentials
+		// expired, so resolve it again for the next command.
+		s.forgetPartialCloneRemoteURL(req.Repo)
+		// Clients recognize this message, see gitdomain.MissingObjectError.
+		execErr = errors.Wrap(execErr, gitdomain.MissingObjectErrorMessage)
+	}
 
 	// write trailer
 	w.Header().Set("X-Exec-Error", errorString(execErr))
@@ -2069,11 +2154,6 @@ func (s *Server) doClone(ctx context.Context, repo api.RepoName, dir GitDir, syn
 			repoCloneFailedCounter.Inc()
 		}
 	}()
-	if err := s.rpsLimiter.Wait(ctx); err != nil {
-		return err
-	}
-	ctx, cancel2 := context.WithTimeout(ctx, conf.GitLongCommandTimeout())
-	defer cancel2()
 
 	dstPath := string(dir)
 	overwrite := opts != nil && opts.Overwrite
@@ -2087,7 +2167,20 @@ func (s *Server) doClone(ctx context.Context, repo api.RepoName, dir GitDir, syn
 				Err:  os.ErrExist,
 			}
 		}
+
+		// Offloaded repos are marked as not cloned, so they are scheduled to
+		// be cloned again. Restore them from their bundle rather than cloning
+		// them from the code host; the next update fetches what's missing.
+		if s.restoreOffloadedRepoLocked(ctx, repo, dir) {
+			return nil
+		}
+	}
+
+	if err := s.rpsLimiter.Wait(ctx); err != nil {
+		return err
 	}
+	ctx, cancel2 := context.WithTimeout(ctx, conf.GitLongCommandTimeout())
+	defer cancel2()
 
 	tmpPath, err := s.tempDir("clone-")
 	if err != nil {
@@ -2183,6 +2276,9 @@ func (s *Server) doClone(ctx context.Context, repo api.RepoName, dir GitDir, syn
 		s.Logger.Warn("failed setting repo size", log.String("repo", string(repo)), log.Error(err))
 	}
 
+	// The repo may have been offloaded before, the bundle is outdated now.
+	s.forgetOffloadedRepo(ctx, repo)
+
 	s.Logger.Info("repo cloned", log.String("repo", string(repo)))
 	repoClonedCounter.Inc()
 
@@ -2393,6 +2489,11 @@ func (s *Server) doRepoUpdate(ctx context.Context, repo api.RepoName, revspec st
 		return nil
 	}
 
+	if s.isReplica(ctx, repo, s.dir(repo)) {
+		// Replicas are only updated by syncs from their primary.
+		return nil
+	}
+
 	s.repoUpdateLocksMu.Lock()
 	l, ok := s.repoUpdateLocks[repo]
 	if !ok {
@@ -14,6 +14,21 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 )
 
+var (
+	blameCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_gitserver_blame_cache_requests_total",
+		Help: "Number of blames served from the blame cache (hit), derived from the cached blame of an ancestor (derived), or computed by git blame (miss).",
+	}, []string{"result"})
+	blameCacheEvictions = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_blame_cache_evictions_total",
+		Help: "Number of cached blames evicted by the janitor.",
+	})
+	blameCacheSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "src_gitserver_blame_cache_size_bytes",
+		Help: "Size of the blame cache of all repositories, as of the last janitor run.",
+	})
+)
+
 func (s *Server) RegisterMetrics(db dbutil.DB, observationContext *observation.Context) {
 	// test the latency of exec, which may increase under certain memory
 	// conditions
@@ -34,6 +49,8 @@ func (s *Server) RegisterMetrics(db dbutil.DB, observationContext *observation.C
 		}
 	}(s)
 
+	prometheus.MustRegister(blameCacheRequests, blameCacheEvictions, blameCacheSize)
+
 	// report the size of the repos dir
 	if s.ReposDir == "" {
 		s.Logger.Error("ReposDir is not set, cannot export disk_space_available metric.")
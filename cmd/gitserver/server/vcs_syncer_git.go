@@ -4,6 +4,9 @@ import (
 	"context"
 	"os"
 	"os/exec"
+	"path"
+
+	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/vcs"
@@ -11,7 +14,15 @@ import (
 )
 
 // GitRepoSyncer is a syncer for Git repositories.
-type GitRepoSyncer struct{}
+type GitRepoSyncer struct {
+	// PartialClone, if set, makes the syncer clone and fetch repositories
+	// partially.
+	PartialClone *PartialCloneOptions
+
+	// PushMirrorURL, if set, is the URL of the remote all branches and tags
+	// of repositories are pushed to after they were fetched.
+	PushMirrorURL string
+}
 
 func (s *GitRepoSyncer) Type() string {
 	return "git"
@@ -77,18 +88,36 @@ func (s *GitRepoSyncer) RemoteShowCommand(ctx context.Context, remoteURL *vcs.UR
 }
 
 func (s *GitRepoSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL) (cmd *exec.Cmd, configRemoteOpts bool) {
-	configRemoteOpts = true
 	if customCmd := customFetchCmd(ctx, remoteURL); customCmd != nil {
-		cmd = customCmd
-		configRemoteOpts = false
-	} else if useRefspecOverrides() {
-		cmd = refspecOverridesFetchCmd(ctx, remoteURL)
+		if s.PartialClone != nil {
+			// Custom fetch commands are run as configured, so we can't add
+			// the object filters of partial clones to them.
+			log.Scoped("fetchCommand", "returns the command to fetch a Git repository").
+				Warn("partial clones are not supported for repositories with a custom git fetch command, fetching all objects",
+					log.String("repo", path.Join(remoteURL.Host, remoteURL.Path)))
+		}
+		return customCmd, false
+	}
+
+	args := []string{
+		"fetch",
+		// We already have janitor jobs that run git gc. We disable git gc here to avoid
+		// a possible corruption of repositories by competing gc processes.
+		"--no-auto-gc",
+		"--progress", "--prune",
+	}
+	remote := remoteURL.String()
+	if s.PartialClone != nil {
+		// Partial clones must be fetched from the named promisor remote,
+		// whose URL is only passed via the environment.
+		args = append(args, s.PartialClone.filterArgs()...)
+		remote = partialCloneRemote
+	}
+	args = append(args, remote)
+	if useRefspecOverrides() {
+		args = append(args, refspecOverrides...)
 	} else {
-		cmd = exec.CommandContext(ctx, "git", "fetch",
-			// We already have janitor jobs that run git gc. We disable git gc here to avoid
-			// a possible corruption of repositories by competing gc processes.
-			"--no-auto-gc",
-			"--progress", "--prune", remoteURL.String(),
+		args = append(args,
 			// Normal git refs
 			"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*",
 			// GitHub pull requests
@@ -102,5 +131,9 @@ func (s *GitRepoSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL) (c
 			// Possibly deprecated refs for sourcegraph zap experiment?
 			"+refs/sourcegraph/*:refs/sourcegraph/*")
 	}
-	return cmd, configRemoteOpts
+	cmd = exec.CommandContext(ctx, "git", args...)
+	if s.PartialClone != nil {
+		cmd.Env = append(os.Environ(), partialCloneRemoteEnv(remoteURL)...)
+	}
+	return cmd, true
 }
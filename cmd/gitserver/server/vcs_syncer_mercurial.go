@@ -0,0 +1,575 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	// mercurialDir is the directory inside the Git directory which holds the
+	// Mercurial clone that is converted to Git.
+	mercurialDir = "hg"
+
+	// mercurialMarksFile is the file inside the Git directory which maps
+	// Mercurial revision numbers to Git commits. It is read and written by
+	// git fast-import, with the mark of a changeset being its revision
+	// number plus one.
+	mercurialMarksFile = "hg-marks"
+
+	// mercurialImportRef is the ref git fast-import commits changesets to.
+	// It is deleted once the import has finished.
+	mercurialImportRef = "refs/hg/import"
+)
+
+// MercurialRepoSyncer is a syncer for Mercurial repositories. It keeps a
+// Mercurial clone of the remote inside the Git directory, and converts new
+// changesets to Git commits with git fast-import on every fetch. Changesets
+// are always converted the same way, so their commit IDs are stable across
+// fetches and re-clones.
+type MercurialRepoSyncer struct{}
+
+var _ VCSSyncer = &MercurialRepoSyncer{}
+
+func (s *MercurialRepoSyncer) Type() string {
+	return "mercurial"
+}
+
+// IsCloneable checks to see if the Mercurial remote URL is cloneable.
+func (s *MercurialRepoSyncer) IsCloneable(ctx context.Context, remoteURL *vcs.URL) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	cmd := hgCommand(ctx, "identify", "--id", "--", remoteURL.String())
+	out, err := runWith(ctx, cmd, false, nil)
+	if err != nil {
+		if ctxerr := ctx.Err(); ctxerr != nil {
+			err = ctxerr
+		}
+		if len(out) > 0 {
+			err = errors.Errorf("%s (output follows)\n\n%s", err, newURLRedactor(remoteURL).redact(string(out)))
+		}
+		return err
+	}
+	return nil
+}
+
+// CloneCommand returns the command to be executed for cloning a Mercurial
+// repository as a Git repository.
+func (s *MercurialRepoSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, tmpPath string) (*exec.Cmd, error) {
+	if err := os.MkdirAll(tmpPath, os.ModePerm); err != nil {
+		return nil, errors.Wrapf(err, "clone failed to create tmp dir")
+	}
+
+	// The default Mercurial branch is converted to master.
+	for _, args := range [][]string{
+		{"init", "--bare", "."},
+		{"symbolic-ref", "HEAD", "refs/heads/master"},
+	} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = tmpPath
+		if output, err := runWith(ctx, cmd, false, nil); err != nil {
+			return nil, errors.Wrapf(err, "clone setup failed with output %q", string(output))
+		}
+	}
+
+	if err := s.Fetch(ctx, remoteURL, GitDir(tmpPath), ""); err != nil {
+		return nil, err
+	}
+
+	// The clone is done by Fetch, so we return a no-op command to satisfy
+	// the VCSSyncer interface.
+	return exec.CommandContext(ctx, "git", "--version"), nil
+}
+
+// Fetch pulls new changesets from the Mercurial remote and converts them to
+// Git commits.
+func (s *MercurialRepoSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir GitDir, _ string) error {
+	hgDir := dir.Path(mercurialDir)
+
+	var cmd *exec.Cmd
+	if _, err := os.Stat(hgDir); os.IsNotExist(err) {
+		cmd = hgCommand(ctx, "clone", "--noupdate", "--", remoteURL.String(), hgDir)
+	} else {
+		cmd = hgCommand(ctx, "pull", "--repository", hgDir, "--", remoteURL.String())
+	}
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "failed to update with output %q", newURLRedactor(remoteURL).redact(string(output)))
+	}
+
+	if err := convertMercurial(ctx, dir, hgDir); err != nil {
+		return errors.Wrap(err, "failed to convert to git")
+	}
+	return nil
+}
+
+// RemoteShowCommand returns the command to be executed for showing the Git
+// remote of a Mercurial repository.
+func (s *MercurialRepoSyncer) RemoteShowCommand(ctx context.Context, remoteURL *vcs.URL) (*exec.Cmd, error) {
+	// The converted repository has no Git remote.
+	return exec.CommandContext(ctx, "git", "remote", "show", "./"), nil
+}
+
+// hgCommand returns an hg command whose output does not depend on the user's
+// configuration.
+func hgCommand(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "hg", append([]string{"--noninteractive"}, args...)...)
+	cmd.Env = append(os.Environ(), "HGPLAIN=1", "HGENCODING=utf-8")
+	return cmd
+}
+
+// hgChangeset is the metadata of a Mercurial changeset needed to convert it to
+// a Git commit.
+type hgChangeset struct {
+	Rev    int
+	P1, P2 int // -1 if the changeset has fewer parents
+	Author string
+	Date   string // "<unix seconds> <seconds west of UTC>", as formatted by hgdate
+	Branch string
+	Desc   string
+
+	// Added, Modified and Removed are the files changed compared to P1.
+	Added, Modified, Removed []string
+}
+
+// hgFile is a file in a Mercurial changeset.
+type hgFile struct {
+	Path string
+	// Flags is "x" for executable files, "l" for symlinks and empty otherwise.
+	Flags string
+	// Data is the content of the file, or the target of a symlink.
+	Data []byte
+}
+
+// convertMercurial converts the changesets of the Mercurial repository hgDir
+// that have not been converted yet, and updates the branches and tags of dir.
+func convertMercurial(ctx context.Context, dir GitDir, hgDir string) error {
+	last, err := lastConvertedRev(dir.Path(mercurialMarksFile))
+	if err != nil {
+		return err
+	}
+
+	changesets, err := hgLog(ctx, hgDir, last+1)
+	if err != nil {
+		return err
+	}
+
+	refs, err := hgRefs(ctx, hgDir)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp("", "hg-cat-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	readFiles := func(cs hgChangeset) ([]hgFile, error) {
+		return hgReadFiles(ctx, hgDir, cs.Rev, append(cs.Added, cs.Modified...), tmp)
+	}
+	return importChangesets(ctx, dir, changesets, readFiles, refs)
+}
+
+// lastConvertedRev returns the highest revision number in the marks file, or
+// -1 if no changeset has been converted yet.
+func lastConvertedRev(marksPath string) (int, error) {
+	f, err := os.Open(marksPath)
+	if os.IsNotExist(err) {
+		return -1, nil
+	} else if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	last := -1
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Lines have the form ":<mark> <commit>".
+		mark, _, _ := strings.Cut(strings.TrimPrefix(scanner.Text(), ":"), " ")
+		n, err := strconv.Atoi(mark)
+		if err != nil {
+			return 0, errors.Wrapf(err, "invalid line in %s", marksPath)
+		}
+		if n-1 > last {
+			last = n - 1
+		}
+	}
+	return last, scanner.Err()
+}
+
+// hgLogTemplate formats the fields of a changeset followed by a NUL byte.
+// Mercurial does not allow newlines in file names, so we use them to separate
+// the files in a list.
+const hgLogTemplate = `{rev}\0{p1rev}\0{p2rev}\0{author}\0{date|hgdate}\0{branch}\0` +
+	`{join(file_adds, "\n")}\0{join(file_mods, "\n")}\0{join(file_dels, "\n")}\0{desc}\0`
+
+const hgLogFields = 10
+
+// hgLog returns the changesets of the Mercurial repository hgDir with a
+// revision number of at least from, in order.
+func hgLog(ctx context.Context, hgDir string, from int) ([]hgChangeset, error) {
+	cmd := hgCommand(ctx, "--hidden", "--repository", hgDir, "log", "--rev", "tip", "--template", "{rev}")
+	out, err := runWith(ctx, cmd, false, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "hg log failed with output %q", string(out))
+	}
+	tip, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil || tip < from {
+		// An empty repository has no tip, so there is nothing to convert.
+		return nil, nil
+	}
+
+	cmd = hgCommand(ctx, "--hidden", "--repository", hgDir, "log", "--rev", fmt.Sprintf("%d:%d", from, tip), "--template", hgLogTemplate)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "hg log failed with output %q", stderr.String())
+	}
+	return parseHgLog(stdout.Bytes())
+}
+
+// parseHgLog parses the output of hg log formatted with hgLogTemplate.
+func parseHgLog(out []byte) ([]hgChangeset, error) {
+	fields := strings.Split(string(out), "\x00")
+	// Every field is terminated by a NUL byte, so the last element is empty.
+	fields = fields[:len(fields)-1]
+	if len(fields)%hgLogFields != 0 {
+		return nil, errors.Errorf("unexpected hg log output with %d fields", len(fields))
+	}
+
+	lines := func(s string) []string {
+		if s == "" {
+			return nil
+		}
+		return strings.Split(s, "\n")
+	}
+
+	changesets := make([]hgChangeset, 0, len(fields)/hgLogFields)
+	for i := 0; i < len(fields); i += hgLogFields {
+		f := fields[i : i+hgLogFields]
+		var revs [3]int
+		for j := range revs {
+			rev, err := strconv.Atoi(f[j])
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid revision in hg log output")
+			}
+			revs[j] = rev
+		}
+		changesets = append(changesets, hgChangeset{
+			Rev:      revs[0],
+			P1:       revs[1],
+			P2:       revs[2],
+			Author:   f[3],
+			Date:     f[4],
+			Branch:   f[5],
+			Added:    lines(f[6]),
+			Modified: lines(f[7]),
+			Removed:  lines(f[8]),
+			Desc:     f[9],
+		})
+	}
+	return changesets, nil
+}
+
+// hgRefs returns the Git refs for the branches and tags of the Mercurial
+// repository hgDir, mapped to the revision they point to. Branches and tags
+// whose name is not a valid Git ref name are skipped.
+func hgRefs(ctx context.Context, hgDir string) (map[string]int, error) {
+	refs := map[string]int{}
+	for _, c := range []struct {
+		args   []string
+		prefix string
+	}{
+		{[]string{"branches", "--closed", "--template", `{branch}\0{rev}\0`}, "refs/heads/"},
+		{[]string{"tags", "--template", `{tag}\0{rev}\0`}, "refs/tags/"},
+	} {
+		cmd := hgCommand(ctx, append([]string{"--repository", hgDir}, c.args...)...)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		if err := cmd.Run(); err != nil {
+			return nil, errors.Wrapf(err, "hg %s failed with output %q", c.args[0], stderr.String())
+		}
+
+		fields := strings.Split(stdout.String(), "\x00")
+		for i := 0; i+1 < len(fields); i += 2 {
+			name := fields[i]
+			rev, err := strconv.Atoi(fields[i+1])
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid revision in hg %s output", c.args[0])
+			}
+			switch {
+			case c.prefix == "refs/heads/" && name == "default":
+				name = "master"
+			case c.prefix == "refs/tags/" && name == "tip":
+				continue
+			}
+			if !isValidRefName(name) {
+				continue
+			}
+			refs[c.prefix+name] = rev
+		}
+	}
+	return refs, nil
+}
+
+// isValidRefName reports whether name is a valid name for a Git branch or tag,
+// following the rules of git check-ref-format.
+func isValidRefName(name string) bool {
+	if name == "" || name == "@" || strings.HasPrefix(name, "-") {
+		return false
+	}
+	if strings.Contains(name, "..") || strings.Contains(name, "@{") || strings.Contains(name, "//") {
+		return false
+	}
+	if strings.HasSuffix(name, "/") || strings.HasSuffix(name, ".") || strings.HasSuffix(name, ".lock") {
+		return false
+	}
+	for _, component := range strings.Split(name, "/") {
+		if strings.HasPrefix(component, ".") {
+			return false
+		}
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(" ~^:?*[\\", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// hgReadFiles returns the flags and contents of paths in revision rev of the
+// Mercurial repository hgDir. It uses tmp to write the file contents to.
+func hgReadFiles(ctx context.Context, hgDir string, rev int, paths []string, tmp string) ([]hgFile, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	if err := os.RemoveAll(tmp); err != nil {
+		return nil, err
+	}
+	out := filepath.Join(tmp, "out")
+	for _, p := range paths {
+		if err := os.MkdirAll(filepath.Join(out, filepath.Dir(p)), os.ModePerm); err != nil {
+			return nil, err
+		}
+	}
+
+	// The paths are passed in a list file rather than as arguments, so that
+	// a single hg command reads all files of a changeset, however many there
+	// are. "path:" patterns match file names literally.
+	var list bytes.Buffer
+	for _, p := range paths {
+		list.WriteString("path:" + p)
+		list.WriteByte(0)
+	}
+	listFile := filepath.Join(tmp, "files")
+	if err := os.WriteFile(listFile, list.Bytes(), 0o600); err != nil {
+		return nil, err
+	}
+	pattern := "listfile0:" + listFile
+	revArgs := []string{"--hidden", "--repository", hgDir}
+
+	// Get the flags of the files.
+	args := append(revArgs, "files", "--verbose", "--rev", strconv.Itoa(rev), "--template", `{flags}\0{path}\0`, "--", pattern)
+	cmd := hgCommand(ctx, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "hg files failed with output %q", stderr.String())
+	}
+	flags := map[string]string{}
+	fields := strings.Split(stdout.String(), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		flags[fields[i+1]] = fields[i]
+	}
+
+	// Write the contents of the files to out.
+	args = append(revArgs, "cat", "--rev", strconv.Itoa(rev), "--output", filepath.Join(out, "%p"), "--", pattern)
+	cmd = hgCommand(ctx, args...)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return nil, errors.Wrapf(err, "hg cat failed with output %q", string(output))
+	}
+
+	files := make([]hgFile, 0, len(paths))
+	for _, p := range paths {
+		data, err := os.ReadFile(filepath.Join(out, p))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, hgFile{Path: p, Flags: flags[p], Data: data})
+	}
+	return files, nil
+}
+
+// importChangesets converts changesets to Git commits with git fast-import,
+// reading the added and modified files of a changeset with readFiles. Parents
+// of changesets must have been converted before, either in an earlier call or
+// earlier in changesets. Afterwards, the branches and tags of dir are set to
+// refs, deleting all others.
+func importChangesets(ctx context.Context, dir GitDir, changesets []hgChangeset, readFiles func(hgChangeset) ([]hgFile, error), refs map[string]int) error {
+	marks := dir.Path(mercurialMarksFile)
+	cmd := exec.CommandContext(ctx, "git", "fast-import", "--quiet", "--force",
+		"--import-marks-if-exists="+marks, "--export-marks="+marks)
+	dir.Set(cmd)
+	var output bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &output, &output
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(stdin)
+	writeErr := func() error {
+		for _, cs := range changesets {
+			files, err := readFiles(cs)
+			if err != nil {
+				return err
+			}
+			writeFastImportCommit(w, cs, files)
+		}
+
+		names := make([]string, 0, len(refs))
+		for name := range refs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(w, "reset %s\nfrom :%d\n\n", name, refs[name]+1)
+		}
+		return w.Flush()
+	}()
+	stdin.Close()
+
+	if err := cmd.Wait(); err != nil {
+		return errors.Wrapf(err, "git fast-import failed with output %q", output.String())
+	}
+	if writeErr != nil {
+		return writeErr
+	}
+
+	return deleteStaleRefs(ctx, dir, refs)
+}
+
+// writeFastImportCommit writes the git fast-import commands to create the
+// commit for cs to w. The mark of the commit is the revision number of cs plus
+// one.
+func writeFastImportCommit(w io.Writer, cs hgChangeset, files []hgFile) {
+	p1, p2 := cs.P1, cs.P2
+	if p1 < 0 {
+		p1, p2 = p2, -1
+	}
+	if p1 < 0 {
+		// Without a "from" command, fast-import would use the current
+		// commit of the ref as the parent.
+		fmt.Fprintf(w, "reset %s\n\n", mercurialImportRef)
+	}
+
+	ident := fastImportIdent(cs.Author, cs.Date)
+	msg := cs.Desc + "\n"
+	fmt.Fprintf(w, "commit %s\nmark :%d\nauthor %s\ncommitter %s\ndata %d\n%s\n", mercurialImportRef, cs.Rev+1, ident, ident, len(msg), msg)
+	if p1 >= 0 {
+		fmt.Fprintf(w, "from :%d\n", p1+1)
+	}
+	if p2 >= 0 {
+		fmt.Fprintf(w, "merge :%d\n", p2+1)
+	}
+
+	for _, path := range cs.Removed {
+		fmt.Fprintf(w, "D %s\n", fastImportPath(path))
+	}
+	for _, f := range files {
+		mode := "100644"
+		switch f.Flags {
+		case "x":
+			mode = "100755"
+		case "l":
+			mode = "120000"
+		}
+		fmt.Fprintf(w, "M %s inline %s\ndata %d\n", mode, fastImportPath(f.Path), len(f.Data))
+		w.Write(f.Data)
+		io.WriteString(w, "\n")
+	}
+	io.WriteString(w, "\n")
+}
+
+// fastImportIdent returns the git fast-import identity for a Mercurial author
+// and date.
+func fastImportIdent(author, date string) string {
+	name, email := author, ""
+	if start, end := strings.LastIndex(author, "<"), strings.LastIndex(author, ">"); start >= 0 && end > start {
+		name, email = strings.TrimSpace(author[:start]), author[start+1:end]
+	} else if strings.Contains(author, "@") && !strings.Contains(author, " ") {
+		name, email = "", author
+	}
+	clean := strings.NewReplacer("<", "", ">", "", "\n", " ")
+	name, email = clean.Replace(name), clean.Replace(email)
+
+	seconds, offset, _ := strings.Cut(date, " ")
+	// Mercurial offsets are in seconds west of UTC, Git's are east of UTC.
+	west, _ := strconv.Atoi(offset)
+	sign := '+'
+	if west > 0 {
+		sign = '-'
+	} else {
+		west = -west
+	}
+	tz := fmt.Sprintf("%c%02d%02d", sign, west/3600, west%3600/60)
+
+	if name == "" {
+		return fmt.Sprintf("<%s> %s %s", email, seconds, tz)
+	}
+	return fmt.Sprintf("%s <%s> %s %s", name, email, seconds, tz)
+}
+
+// fastImportPath quotes path if git fast-import requires it.
+func fastImportPath(path string) string {
+	if !strings.HasPrefix(path, `"`) {
+		return path
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(path) + `"`
+}
+
+// deleteStaleRefs deletes the branches and tags of dir which are not in refs,
+// as well as the ref used by importChangesets.
+func deleteStaleRefs(ctx context.Context, dir GitDir, refs map[string]int) error {
+	cmd := exec.CommandContext(ctx, "git", "for-each-ref", "--format=%(refname)", "refs/heads/", "refs/tags/", mercurialImportRef)
+	dir.Set(cmd)
+	out, err := runWith(ctx, cmd, false, nil)
+	if err != nil {
+		return errors.Wrapf(err, "git for-each-ref failed with output %q", string(out))
+	}
+
+	var stdin strings.Builder
+	for _, ref := range strings.Fields(string(out)) {
+		if _, ok := refs[ref]; !ok {
+			fmt.Fprintf(&stdin, "delete %s\n", ref)
+		}
+	}
+	if stdin.Len() == 0 {
+		return nil
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "update-ref", "--stdin")
+	dir.Set(cmd)
+	cmd.Stdin = strings.NewReader(stdin.String())
+	if out, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "git update-ref failed with output %q", string(out))
+	}
+	return nil
+}
@@ -0,0 +1,318 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+func TestParseHgLog(t *testing.T) {
+	out := "0\x00-1\x00-1\x00Alice <alice@example.com>\x001600000000 -7200\x00default\x00a.txt\nb/c.txt\x00\x00\x00first\x00" +
+		"1\x000\x00-1\x00bob\x001600000100 0\x00stable\x00\x00a.txt\x00b/c.txt\x00multi\nline\x00"
+
+	have, err := parseHgLog([]byte(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []hgChangeset{{
+		Rev:    0,
+		P1:     -1,
+		P2:     -1,
+		Author: "Alice <alice@example.com>",
+		Date:   "1600000000 -7200",
+		Branch: "default",
+		Desc:   "first",
+		Added:  []string{"a.txt", "b/c.txt"},
+	}, {
+		Rev:      1,
+		P1:       0,
+		P2:       -1,
+		Author:   "bob",
+		Date:     "1600000100 0",
+		Branch:   "stable",
+		Desc:     "multi\nline",
+		Modified: []string{"a.txt"},
+		Removed:  []string{"b/c.txt"},
+	}}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatalf("unexpected changesets (-want +have):\n%s", diff)
+	}
+
+	if _, err := parseHgLog([]byte("0\x00-1\x00")); err == nil {
+		t.Fatal("expected error for truncated output")
+	}
+}
+
+func TestFastImportIdent(t *testing.T) {
+	for _, tc := range []struct {
+		author, date string
+		want         string
+	}{
+		{"Alice <alice@example.com>", "1600000000 -7200", "Alice <alice@example.com> 1600000000 +0200"},
+		{"Bob <bob@example.com>", "1600000000 18000", "Bob <bob@example.com> 1600000000 -0500"},
+		{"carol@example.com", "1600000000 0", "<carol@example.com> 1600000000 +0000"},
+		{"dave", "1600000000 -19800", "dave <> 1600000000 +0530"},
+		{"Eve <eve@example.com", "1600000000 0", "Eve eve@example.com <> 1600000000 +0000"},
+	} {
+		if have := fastImportIdent(tc.author, tc.date); have != tc.want {
+			t.Errorf("fastImportIdent(%q, %q): have %q, want %q", tc.author, tc.date, have, tc.want)
+		}
+	}
+}
+
+func TestIsValidRefName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"master":        true,
+		"feature/x":     true,
+		"v1.0":          true,
+		"":              false,
+		"has space":     false,
+		"a..b":          false,
+		"a.lock":        false,
+		"-dash":         false,
+		"dir/.hidden":   false,
+		"trailing/":     false,
+		"colon:name":    false,
+		"reflog@{1}":    false,
+		"double//slash": false,
+	} {
+		if have := isValidRefName(name); have != want {
+			t.Errorf("isValidRefName(%q): have %v, want %v", name, have, want)
+		}
+	}
+}
+
+func TestImportChangesets(t *testing.T) {
+	contents := map[int][]hgFile{
+		0: {{Path: "README", Data: []byte("hello\n")}, {Path: "run.sh", Flags: "x", Data: []byte("#!/bin/sh\n")}},
+		1: {{Path: "README", Data: []byte("hello world\n")}, {Path: "link", Flags: "l", Data: []byte("README")}},
+		2: {{Path: `"quoted"`, Data: []byte("quoted\n")}},
+		3: {{Path: `"quoted"`, Data: []byte("quoted\n")}, {Path: "README", Data: []byte("merged\n")}},
+	}
+	changesets := []hgChangeset{
+		{Rev: 0, P1: -1, P2: -1, Author: "Alice <alice@example.com>", Date: "1600000000 -3600", Desc: "initial", Added: []string{"README", "run.sh"}},
+		{Rev: 1, P1: 0, P2: -1, Author: "bob", Date: "1600000100 0", Desc: "link", Added: []string{"link"}, Modified: []string{"README"}},
+		{Rev: 2, P1: 0, P2: -1, Author: "carol@example.com", Date: "1600000200 0", Desc: "branch", Added: []string{`"quoted"`}, Removed: []string{"run.sh"}},
+		{Rev: 3, P1: 1, P2: 2, Author: "Alice <alice@example.com>", Date: "1600000300 0", Desc: "merge", Added: []string{`"quoted"`}, Modified: []string{"README"}, Removed: []string{"run.sh"}},
+	}
+	readFiles := func(cs hgChangeset) ([]hgFile, error) {
+		return contents[cs.Rev], nil
+	}
+
+	ctx := context.Background()
+	newGitDir := func() GitDir {
+		dir := GitDir(t.TempDir())
+		cmd := exec.Command("git", "init", "--bare", ".")
+		cmd.Dir = string(dir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git init failed: %s: %s", err, out)
+		}
+		return dir
+	}
+	git := func(dir GitDir, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		dir.Set(cmd)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %s: %s", strings.Join(args, " "), err, out)
+		}
+		return string(out)
+	}
+
+	// Import everything at once.
+	full := newGitDir()
+	refs := map[string]int{"refs/heads/master": 3, "refs/heads/stable": 2, "refs/tags/v1": 1}
+	if err := importChangesets(ctx, full, changesets, readFiles, refs); err != nil {
+		t.Fatal(err)
+	}
+
+	// Import in two steps, with a stale branch in between.
+	incremental := newGitDir()
+	if err := importChangesets(ctx, incremental, changesets[:2], readFiles, map[string]int{"refs/heads/master": 1, "refs/heads/old": 0}); err != nil {
+		t.Fatal(err)
+	}
+	if last, err := lastConvertedRev(incremental.Path(mercurialMarksFile)); err != nil || last != 1 {
+		t.Fatalf("lastConvertedRev: have %d (err %v), want 1", last, err)
+	}
+	if err := importChangesets(ctx, incremental, changesets[2:], readFiles, refs); err != nil {
+		t.Fatal(err)
+	}
+
+	showRefs := func(dir GitDir) string {
+		return git(dir, "for-each-ref", "--format=%(objectname) %(refname)")
+	}
+	if diff := cmp.Diff(showRefs(full), showRefs(incremental)); diff != "" {
+		t.Fatalf("incremental import produced different commits (-full +incremental):\n%s", diff)
+	}
+
+	wantLog := strings.Join([]string{
+		"merge|Alice|alice@example.com|1600000300 +0000|2",
+		"branch||carol@example.com|1600000200 +0000|1",
+		"link|bob||1600000100 +0000|1",
+		"initial|Alice|alice@example.com|1600000000 +0100|0",
+	}, "\n") + "\n"
+	// We only compare the number of parents, since their IDs are not stable
+	// across git versions.
+	log := git(full, "log", "--format=%s|%an|%ae|%ad|%p", "--date=raw", "refs/heads/master")
+	var haveLog strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(log), "\n") {
+		i := strings.LastIndex(line, "|")
+		fmt.Fprintf(&haveLog, "%s|%d\n", line[:i], len(strings.Fields(line[i+1:])))
+	}
+	if diff := cmp.Diff(wantLog, haveLog.String()); diff != "" {
+		t.Fatalf("unexpected log (-want +have):\n%s", diff)
+	}
+
+	wantTree := strings.Join([]string{
+		`100644 blob "\"quoted\""`,
+		"100644 blob README",
+		"120000 blob link",
+	}, "\n") + "\n"
+	tree := git(full, "ls-tree", "refs/heads/master")
+	var haveTree strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(tree), "\n") {
+		meta, path, _ := strings.Cut(line, "\t")
+		fields := strings.Fields(meta)
+		fmt.Fprintf(&haveTree, "%s %s %s\n", fields[0], fields[1], path)
+	}
+	if diff := cmp.Diff(wantTree, haveTree.String()); diff != "" {
+		t.Fatalf("unexpected tree (-want +have):\n%s", diff)
+	}
+
+	if have := git(full, "show", "refs/heads/master:README"); have != "merged\n" {
+		t.Fatalf("unexpected README: %q", have)
+	}
+	if have := git(full, "ls-tree", "refs/tags/v1", "run.sh"); !strings.HasPrefix(have, "100755 ") {
+		t.Fatalf("expected run.sh to be executable, got %q", have)
+	}
+}
+
+func TestMercurialRepoSyncer(t *testing.T) {
+	if _, err := exec.LookPath("hg"); err != nil {
+		t.Skip("hg not found in PATH")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	root := t.TempDir()
+	hgRepo := filepath.Join(root, "remote")
+	hg := func(args ...string) {
+		t.Helper()
+		cmd := hgCommand(ctx, append([]string{"--repository", hgRepo}, args...)...)
+		cmd.Env = append(cmd.Env, "HGUSER=Alice <alice@example.com>")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("hg %s failed: %s: %s", strings.Join(args, " "), err, out)
+		}
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(hgRepo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if out, err := hgCommand(ctx, "init", hgRepo).CombinedOutput(); err != nil {
+		t.Fatalf("hg init failed: %s: %s", err, out)
+	}
+	writeFile("README", "hello\n")
+	hg("add", "README")
+	hg("commit", "--message", "initial", "--date", "1600000000 0")
+	hg("tag", "--date", "1600000100 0", "v1")
+
+	// Serve the repository on a free port.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	serve := hgCommand(ctx, "--repository", hgRepo, "serve", "--address", "127.0.0.1", "--port", strings.Split(addr, ":")[1])
+	if err := serve.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		serve.Process.Kill()
+		serve.Wait()
+	})
+
+	remoteURL, err := vcs.ParseURL("http://" + addr + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &MercurialRepoSyncer{}
+	for {
+		if err := s.IsCloneable(ctx, remoteURL); err == nil {
+			break
+		} else if ctx.Err() != nil {
+			t.Fatalf("hg serve did not start: %s", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	dir := GitDir(filepath.Join(root, "git"))
+	cmd, err := s.CloneCommand(ctx, remoteURL, string(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("clone command failed: %s: %s", err, out)
+	}
+
+	showRefs := func() string {
+		cmd := exec.Command("git", "for-each-ref", "--format=%(objectname) %(refname)")
+		dir.Set(cmd)
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(out)
+	}
+	before := showRefs()
+	if !strings.Contains(before, " refs/heads/master\n") || !strings.Contains(before, " refs/tags/v1\n") {
+		t.Fatalf("missing refs after clone:\n%s", before)
+	}
+
+	writeFile("README", "hello world\n")
+	hg("commit", "--message", "second", "--date", "1600000200 0")
+	if err := s.Fetch(ctx, remoteURL, dir, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	after := showRefs()
+	tag := func(refs string) string {
+		for _, line := range strings.Split(refs, "\n") {
+			if strings.HasSuffix(line, " refs/tags/v1") {
+				return line
+			}
+		}
+		return ""
+	}
+	if tag(before) != tag(after) {
+		t.Fatalf("commit ID of unchanged tag changed after fetch: %q != %q", tag(before), tag(after))
+	}
+	if before == after {
+		t.Fatal("fetch did not update master")
+	}
+
+	// A fresh clone converts to the same commits.
+	fresh := GitDir(filepath.Join(root, "fresh"))
+	if _, err := s.CloneCommand(ctx, remoteURL, string(fresh)); err != nil {
+		t.Fatal(err)
+	}
+	dir = fresh
+	if diff := cmp.Diff(after, showRefs()); diff != "" {
+		t.Fatalf("fresh clone produced different commits (-fetched +fresh):\n%s", diff)
+	}
+}
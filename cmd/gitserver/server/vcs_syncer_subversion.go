@@ -0,0 +1,314 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const (
+	// subversionRemotePrefix is the prefix of the refs git svn fetches into,
+	// below refs/remotes/.
+	subversionRemotePrefix = "svn/"
+
+	// subversionAuthorsFile is the file inside the Git directory which maps
+	// Subversion usernames to Git authors.
+	subversionAuthorsFile = "svn-authors"
+
+	// subversionAuthorsProg is the script inside the Git directory which is
+	// used for Subversion usernames missing from subversionAuthorsFile.
+	subversionAuthorsProg = "svn-authors-prog"
+
+	// subversionAskPass is the script inside the Git directory which git svn
+	// runs to read the password of the Subversion user.
+	subversionAskPass = "svn-askpass"
+)
+
+// SubversionRepoSyncer is a syncer for Subversion repositories. Like
+// PerforceDepotSyncer, it imports the history with a Git extension, git svn,
+// which persists the mapping of Subversion revisions to Git commits in the Git
+// directory. Fetches only import the revisions which have been committed since
+// the previous import.
+type SubversionRepoSyncer struct {
+	// Layout is the layout of trunk, branches and tags in the repository. If
+	// nil, the standard layout is used.
+	Layout *schema.SubversionLayout
+
+	// Authors maps Subversion usernames to Git authors of the form
+	// "Name <email>".
+	Authors map[string]string
+}
+
+var _ VCSSyncer = &SubversionRepoSyncer{}
+
+func (s *SubversionRepoSyncer) Type() string {
+	return "subversion"
+}
+
+// IsCloneable checks to see if the Subversion remote URL is cloneable.
+func (s *SubversionRepoSyncer) IsCloneable(ctx context.Context, remoteURL *vcs.URL) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	username, password, svnURL := decomposeSubversionRemoteURL(remoteURL)
+	args := []string{"info", "--non-interactive", "--no-auth-cache"}
+	if username != "" {
+		args = append(args, "--username", username)
+	}
+	if password != "" {
+		args = append(args, "--password-from-stdin")
+	}
+	cmd := exec.CommandContext(ctx, "svn", append(args, "--", svnURL)...)
+	cmd.Stdin = strings.NewReader(password)
+
+	out, err := runWith(ctx, cmd, false, nil)
+	if err != nil {
+		if ctxerr := ctx.Err(); ctxerr != nil {
+			err = ctxerr
+		}
+		if len(out) > 0 {
+			err = errors.Errorf("%s (output follows)\n\n%s", err, newURLRedactor(remoteURL).redact(string(out)))
+		}
+		return err
+	}
+	return nil
+}
+
+// CloneCommand returns the command to be executed for cloning a Subversion
+// repository as a Git repository.
+func (s *SubversionRepoSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, tmpPath string) (*exec.Cmd, error) {
+	if err := os.MkdirAll(tmpPath, os.ModePerm); err != nil {
+		return nil, errors.Wrapf(err, "clone failed to create tmp dir")
+	}
+
+	username, _, svnURL := decomposeSubversionRemoteURL(remoteURL)
+	// Example: git svn init --prefix=svn/ --stdlayout --username=alice -- https://svn.example.com/repo
+	initArgs := append([]string{"svn", "init", "--prefix=" + subversionRemotePrefix}, s.layoutOptions()...)
+	if username != "" {
+		initArgs = append(initArgs, "--username="+username)
+	}
+	initArgs = append(initArgs, "--", svnURL)
+
+	// Trunk, or the whole repository if there is no layout, is imported as
+	// master.
+	for _, args := range [][]string{
+		{"init", "--bare", "."},
+		{"symbolic-ref", "HEAD", "refs/heads/master"},
+		initArgs,
+	} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		GitDir(tmpPath).Set(cmd)
+		if output, err := runWith(ctx, cmd, false, nil); err != nil {
+			return nil, errors.Wrapf(err, "clone setup failed with output %q", newURLRedactor(remoteURL).redact(string(output)))
+		}
+	}
+
+	if err := s.Fetch(ctx, remoteURL, GitDir(tmpPath), ""); err != nil {
+		return nil, err
+	}
+
+	// The clone is done by Fetch, so we return a no-op command to satisfy
+	// the VCSSyncer interface.
+	return exec.CommandContext(ctx, "git", "--version"), nil
+}
+
+// Fetch imports the Subversion revisions committed since the last fetch and
+// updates the branches and tags of dir.
+func (s *SubversionRepoSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir GitDir, _ string) error {
+	if err := s.writeAuthors(dir); err != nil {
+		return errors.Wrap(err, "failed to write authors")
+	}
+
+	username, password, _ := decomposeSubversionRemoteURL(remoteURL)
+	// Example: git svn fetch --authors-file=svn-authors --authors-prog=svn-authors-prog --no-auth-cache --username=alice
+	args := []string{
+		"svn", "fetch",
+		"--authors-file=" + dir.Path(subversionAuthorsFile),
+		"--authors-prog=" + dir.Path(subversionAuthorsProg),
+		"--no-auth-cache",
+	}
+	if username != "" {
+		args = append(args, "--username="+username)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	// git svn reads the password from GIT_ASKPASS.
+	cmd.Env = append(os.Environ(),
+		"GIT_ASKPASS="+dir.Path(subversionAskPass),
+		"SRC_SVN_PASSWORD="+password,
+	)
+	dir.Set(cmd)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "failed to update with output %q", newURLRedactor(remoteURL).redact(string(output)))
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "for-each-ref", "--format=%(objectname) %(refname)",
+		"refs/remotes/"+subversionRemotePrefix, "refs/heads/", "refs/tags/")
+	dir.Set(cmd)
+	out, err := runWith(ctx, cmd, false, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list refs with output %q", string(out))
+	}
+
+	updates := subversionRefUpdates(string(out))
+	if updates == "" {
+		return nil
+	}
+	cmd = exec.CommandContext(ctx, "git", "update-ref", "--stdin")
+	cmd.Stdin = strings.NewReader(updates)
+	dir.Set(cmd)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "failed to update refs with output %q", string(output))
+	}
+	return nil
+}
+
+// RemoteShowCommand returns the command to be executed for showing the Git
+// remote of a Subversion repository.
+func (s *SubversionRepoSyncer) RemoteShowCommand(ctx context.Context, remoteURL *vcs.URL) (*exec.Cmd, error) {
+	// The imported repository has no Git remote.
+	return exec.CommandContext(ctx, "git", "remote", "show", "./"), nil
+}
+
+// layoutOptions returns the git svn init options for the layout of s.
+func (s *SubversionRepoSyncer) layoutOptions() []string {
+	if s.Layout == nil {
+		return []string{"--stdlayout"}
+	}
+	var opts []string
+	if s.Layout.Trunk != "" {
+		opts = append(opts, "--trunk="+s.Layout.Trunk)
+	}
+	if s.Layout.Branches != "" {
+		opts = append(opts, "--branches="+s.Layout.Branches)
+	}
+	if s.Layout.Tags != "" {
+		opts = append(opts, "--tags="+s.Layout.Tags)
+	}
+	return opts
+}
+
+// writeAuthors writes the authors file and the scripts used by git svn fetch
+// to dir. They are rewritten on every fetch, so changes to the authors of the
+// connection apply to revisions imported afterwards.
+func (s *SubversionRepoSyncer) writeAuthors(dir GitDir) error {
+	if err := os.WriteFile(dir.Path(subversionAuthorsFile), []byte(subversionAuthorsFileContent(s.Authors)), 0600); err != nil {
+		return err
+	}
+	// Users missing from the authors file are attributed to "username
+	// <username>", as git svn fails otherwise.
+	if err := os.WriteFile(dir.Path(subversionAuthorsProg), []byte("#!/bin/sh\necho \"$1 <$1>\"\n"), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(dir.Path(subversionAskPass), []byte("#!/bin/sh\necho \"$SRC_SVN_PASSWORD\"\n"), 0700)
+}
+
+// subversionAuthorsFileContent returns authors in the format of the git svn
+// authors file.
+func subversionAuthorsFileContent(authors map[string]string) string {
+	users := make([]string, 0, len(authors))
+	for user := range authors {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+
+	var b strings.Builder
+	for _, user := range users {
+		fmt.Fprintf(&b, "%s = %s\n", user, authors[user])
+	}
+	return b.String()
+}
+
+// subversionRefUpdates returns the input for git update-ref --stdin to point
+// the branches and tags of the repository to the refs git svn fetched into.
+// refs is the output of git for-each-ref with the format
+// "%(objectname) %(refname)".
+//
+// Trunk (or the whole repository without a layout) becomes master, tags
+// become tags and other branches become branches of the same name. Branches
+// and tags which no longer exist in Subversion are deleted.
+func subversionRefUpdates(refs string) string {
+	remotePrefix := "refs/remotes/" + subversionRemotePrefix
+
+	want := map[string]string{}
+	have := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(refs), "\n") {
+		commit, ref, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		name := strings.TrimPrefix(ref, remotePrefix)
+		if name == ref {
+			have[ref] = commit
+			continue
+		}
+
+		var target string
+		switch {
+		case name == "trunk" || name == "git-svn":
+			target = "refs/heads/master"
+		case strings.HasPrefix(name, "tags/"):
+			name = strings.TrimPrefix(name, "tags/")
+			target = "refs/tags/" + name
+		case name == "master":
+			// Trunk is imported as master.
+			continue
+		default:
+			target = "refs/heads/" + name
+		}
+		// git svn keeps deleted branches which were recreated as
+		// "name@revision".
+		if strings.Contains(name, "@") || !isValidRefName(name) {
+			continue
+		}
+		want[target] = commit
+	}
+
+	targets := make([]string, 0, len(want)+len(have))
+	for ref := range want {
+		targets = append(targets, ref)
+	}
+	for ref := range have {
+		if _, ok := want[ref]; !ok {
+			targets = append(targets, ref)
+		}
+	}
+	sort.Strings(targets)
+
+	var b strings.Builder
+	for _, ref := range targets {
+		commit, ok := want[ref]
+		switch {
+		case !ok:
+			fmt.Fprintf(&b, "delete %s\n", ref)
+		case commit != have[ref]:
+			fmt.Fprintf(&b, "update %s %s\n", ref, commit)
+		}
+	}
+	return b.String()
+}
+
+// decomposeSubversionRemoteURL returns the credentials encoded in the clone
+// URL of a Subversion repository, and the URL without them. The username is
+// kept in svn+ssh URLs, where it is the SSH user.
+func decomposeSubversionRemoteURL(remoteURL *vcs.URL) (username, password, svnURL string) {
+	u := remoteURL.URL
+	if u.User != nil {
+		username = u.User.Username()
+		password, _ = u.User.Password()
+		if u.Scheme == "svn+ssh" {
+			u.User = url.User(username)
+		} else {
+			u.User = nil
+		}
+	}
+	return username, password, u.String()
+}
@@ -53,6 +53,14 @@ type Service struct {
 	//
 	// TODO Git client should be exposing a better API here.
 	GitDiffSymbols func(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) ([]byte, error)
+
+	// PCREMatchTimeout is the time limit for matching a PCRE pattern against
+	// a single file. Files which take longer are skipped.
+	PCREMatchTimeout time.Duration
+
+	// PCREMaxFileSize is the limit on the size in bytes of files matched
+	// against a PCRE pattern. Larger files are skipped.
+	PCREMaxFileSize int
 }
 
 // ServeHTTP handles HTTP based search requests
@@ -117,7 +125,8 @@ func (s *Service) streamSearch(ctx context.Context, w http.ResponseWriter, p pro
 
 	err = s.search(ctx, &p, stream)
 	doneEvent := searcher.EventDone{
-		LimitHit: stream.LimitHit(),
+		LimitHit:     stream.LimitHit(),
+		SkippedFiles: stream.SkippedFiles(),
 	}
 	if err != nil {
 		doneEvent.Error = err.Error()
@@ -144,6 +153,7 @@ func (s *Service) search(ctx context.Context, p *protocol.Request, sender matchS
 		attribute.String("commit", string(p.Commit)),
 		attribute.String("pattern", p.Pattern),
 		attribute.Bool("isRegExp", p.IsRegExp),
+		attribute.Bool("isPCRE", p.IsPCRE),
 		attribute.StringSlice("languages", p.Languages),
 		attribute.Bool("isWordMatch", p.IsWordMatch),
 		attribute.Bool("isCaseSensitive", p.IsCaseSensitive),
@@ -172,7 +182,7 @@ func (s *Service) search(ctx context.Context, p *protocol.Request, sender matchS
 				code = "500"
 			}
 		}
-		tr.LazyPrintf("code=%s matches=%d limitHit=%v", code, sender.SentCount(), sender.LimitHit())
+		tr.LazyPrintf("code=%s matches=%d limitHit=%v skippedFiles=%d", code, sender.SentCount(), sender.LimitHit(), sender.SkippedFiles())
 		metricRequestTotal.WithLabelValues(code).Inc()
 		tr.AddEvent("matches", attribute.Int("matches.len", sender.SentCount()))
 		tr.SetAttributes(attribute.Bool("limitHit", sender.LimitHit()))
@@ -201,8 +211,16 @@ func (s *Service) search(ctx context.Context, p *protocol.Request, sender matchS
 	}
 
 	// Compile pattern before fetching from store incase it is bad.
-	var rg *readerGrep
-	if !p.IsStructuralPat {
+	var (
+		rg *readerGrep
+		pg *pcreGrep
+	)
+	if p.IsPCRE && p.Pattern != "" {
+		pg, err = compilePCRE(&p.PatternInfo, s.PCREMatchTimeout, s.PCREMaxFileSize)
+		if err != nil {
+			return badRequestError{err.Error()}
+		}
+	} else if !p.IsStructuralPat {
 		rg, err = compile(&p.PatternInfo)
 		if err != nil {
 			return badRequestError{err.Error()}
@@ -228,7 +246,8 @@ func (s *Service) search(ctx context.Context, p *protocol.Request, sender matchS
 		return path, zf, err
 	}
 
-	hybrid := !p.IsStructuralPat && p.FeatHybrid
+	// Zoekt cannot evaluate PCRE patterns, so they are never searched hybrid.
+	hybrid := !p.IsStructuralPat && pg == nil && p.FeatHybrid
 	if hybrid {
 		unsearched, ok, err := s.hybrid(ctx, p, sender)
 		if err != nil {
@@ -276,6 +295,8 @@ func (s *Service) search(ctx context.Context, p *protocol.Request, sender matchS
 
 	if p.IsStructuralPat {
 		return filteredStructuralSearch(ctx, zipPath, zf, &p.PatternInfo, p.Repo, sender)
+	} else if pg != nil {
+		return pcreSearch(ctx, pg, zf, p.PatternMatchesContent, p.PatternMatchesPath, p.IsNegated, sender)
 	} else {
 		return regexSearch(ctx, rg, zf, p.PatternMatchesContent, p.PatternMatchesPath, p.IsNegated, sender)
 	}
@@ -0,0 +1,239 @@
+package search
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dlclark/regexp2"
+	"github.com/opentracing/opentracing-go/ext"
+	otlog "github.com/opentracing/opentracing-go/log"
+	"go.uber.org/atomic"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/pathmatch"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+const (
+	// defaultPCREMatchTimeout is the default time limit for matching a PCRE
+	// pattern against a single file.
+	defaultPCREMatchTimeout = 500 * time.Millisecond
+
+	// defaultPCREMaxFileSize is the default limit on the size in bytes of a
+	// file matched against a PCRE pattern. The backtracking engine matches
+	// runes, so matching a file uses about 8 times its size in memory.
+	defaultPCREMaxFileSize = 1 << 20
+)
+
+// pcreGrep finds matches of a PCRE pattern. Unlike readerGrep, it uses a
+// backtracking engine which supports lookarounds and backreferences, but can
+// take exponential time. To bound the work done per file, files larger than
+// maxFileSize or which take longer than timeout to match are skipped.
+type pcreGrep struct {
+	re *regexp2.Regexp
+
+	// matchPath is compiled from the include/exclude path patterns and reports
+	// whether a file path matches (and should be searched).
+	matchPath pathmatch.PathMatcher
+
+	timeout     time.Duration
+	maxFileSize int
+}
+
+// compilePCRE returns a pcreGrep for matching p, which must be a PCRE
+// pattern. A zero timeout or maxFileSize uses the default limit.
+func compilePCRE(p *protocol.PatternInfo, timeout time.Duration, maxFileSize int) (*pcreGrep, error) {
+	if timeout <= 0 {
+		timeout = defaultPCREMatchTimeout
+	}
+	if maxFileSize <= 0 {
+		maxFileSize = defaultPCREMaxFileSize
+	}
+
+	expr := p.Pattern
+	if p.IsWordMatch {
+		expr = `\b(?:` + expr + `)\b`
+	}
+	// Like readerGrep, we match whole files, so anchors (^$) match at
+	// newlines.
+	opts := query.PCREOptions
+	if !p.IsCaseSensitive {
+		opts |= regexp2.IgnoreCase
+	}
+	re, err := regexp2.Compile(expr, opts)
+	if err != nil {
+		return nil, err
+	}
+	re.MatchTimeout = timeout
+
+	pathOptions := pathmatch.CompileOptions{
+		RegExp:        p.PathPatternsAreRegExps,
+		CaseSensitive: p.PathPatternsAreCaseSensitive,
+	}
+	matchPath, err := pathmatch.CompilePathPatterns(p.IncludePatterns, p.ExcludePattern, pathOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pcreGrep{
+		re:          re,
+		matchPath:   matchPath,
+		timeout:     timeout,
+		maxFileSize: maxFileSize,
+	}, nil
+}
+
+// matchString returns whether pg's pattern matches s. It is intended to be
+// used to match file paths, so it does not report timeouts.
+func (pg *pcreGrep) matchString(s string) bool {
+	ok, err := pg.re.MatchString(s)
+	return err == nil && ok
+}
+
+// Find returns the chunks of f which match pg, up to limit matches. ok is
+// false if f was skipped because it exceeded the size or time limits of pg.
+func (pg *pcreGrep) Find(zf *zipFile, f *srcFile, limit int) (matches []protocol.ChunkMatch, ok bool) {
+	buf := zf.DataFor(f)
+	if len(buf) > pg.maxFileSize {
+		return nil, false
+	}
+
+	// regexp2 matches runes and reports rune offsets, so we record the byte
+	// offset of every rune to map matches back into buf.
+	runes := make([]rune, 0, len(buf))
+	offsets := make([]int32, 0, len(buf)+1)
+	for i := 0; i < len(buf); {
+		r, size := utf8.DecodeRune(buf[i:])
+		runes = append(runes, r)
+		offsets = append(offsets, int32(i))
+		i += size
+	}
+	offsets = append(offsets, int32(len(buf)))
+
+	// MatchTimeout bounds every call to the engine, and the deadline bounds
+	// the sum over all matches in the file.
+	deadline := time.Now().Add(pg.timeout)
+
+	var locs [][]int
+	m, err := pg.re.FindRunesMatch(runes)
+	for m != nil && err == nil && len(locs) < limit {
+		start, end := offsets[m.Index], offsets[m.Index+m.Length]
+		locs = append(locs, []int{int(start), int(end)})
+		if time.Now().After(deadline) {
+			return nil, false
+		}
+		m, err = pg.re.FindNextMatch(m)
+	}
+	if err != nil {
+		// regexp2 only fails to match when it times out.
+		return nil, false
+	}
+	if len(locs) == 0 {
+		return nil, true
+	}
+
+	ranges := locsToRanges(buf, locs)
+	chunks := chunkRanges(ranges, 0)
+	return chunksToMatches(buf, chunks), true
+}
+
+// pcreSearch concurrently searches files in zf looking for matches using pg.
+// Files skipped because they exceed the limits of pg are recorded with
+// sender.SkipFile.
+func pcreSearch(ctx context.Context, pg *pcreGrep, zf *zipFile, patternMatchesContent, patternMatchesPaths bool, isPatternNegated bool, sender matchSender) error {
+	var err error
+	span, ctx := ot.StartSpanFromContext(ctx, "PCRESearch")
+	ext.Component.Set(span, "pcre_search")
+	span.SetTag("re", pg.re.String())
+	span.SetTag("path", pg.matchPath.String())
+	defer func() {
+		if err != nil {
+			ext.Error.Set(span, true)
+			span.SetTag("err", err.Error())
+		}
+		span.Finish()
+	}()
+
+	if !patternMatchesContent && !patternMatchesPaths {
+		patternMatchesContent = true
+	}
+
+	// If we reach limit we use cancel to stop the search
+	var cancel context.CancelFunc
+	if deadline, ok := ctx.Deadline(); ok {
+		// If a deadline is set, try to finish before the deadline expires.
+		timeout := time.Duration(0.9 * float64(time.Until(deadline)))
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	files := zf.Files
+
+	if patternMatchesPaths && !patternMatchesContent {
+		for _, f := range files {
+			if match := pg.matchPath.MatchPath(f.Name) && pg.matchString(f.Name); match == !isPatternNegated {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				sender.Send(protocol.FileMatch{Path: f.Name})
+			}
+		}
+		return nil
+	}
+
+	var (
+		lastFileIdx   = atomic.NewInt32(-1)
+		filesSearched atomic.Uint32
+	)
+
+	g, ctx := errgroup.WithContext(ctx)
+	for i := 0; i < numWorkers; i++ {
+		g.Go(func() error {
+			for ctx.Err() == nil {
+				idx := int(lastFileIdx.Inc())
+				if idx >= len(files) {
+					return nil
+				}
+
+				f := &files[idx]
+				if !pg.matchPath.MatchPath(f.Name) {
+					continue
+				}
+				filesSearched.Inc()
+
+				cms, ok := pg.Find(zf, f, sender.Remaining())
+				if !ok {
+					sender.SkipFile()
+					continue
+				}
+				fm := protocol.FileMatch{Path: f.Name, ChunkMatches: cms}
+				match := len(cms) > 0
+				if !match && patternMatchesPaths {
+					match = pg.matchString(f.Name)
+				}
+				if match == !isPatternNegated {
+					sender.Send(fm)
+				}
+			}
+			return nil
+		})
+	}
+
+	err = g.Wait()
+	if err == nil && ctx.Err() == context.DeadlineExceeded {
+		// We stopped early because we were about to hit the deadline.
+		err = ctx.Err()
+	}
+
+	span.LogFields(
+		otlog.Int("filesSearched", int(filesSearched.Load())),
+		otlog.Int("filesSkipped", sender.SkippedFiles()),
+	)
+
+	return err
+}
@@ -0,0 +1,107 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+)
+
+func TestPCRESearch(t *testing.T) {
+	zipData, err := createZip(map[string]string{
+		"lookahead.go": "foo := bar\nfoo := baz\n",
+		"multiline.go": "func a() {\n\treturn\n}\n",
+		"backref.txt":  "hello hello world\n",
+		"slow.txt":     strings.Repeat("a", 40) + "!",
+		"large.txt":    strings.Repeat("foo ", 100),
+	})
+	require.NoError(t, err)
+	zf, err := mockZipFile(zipData)
+	require.NoError(t, err)
+
+	// search returns the matched files with the offsets of their matches, and
+	// the number of skipped files.
+	search := func(pattern string, timeout time.Duration, maxFileSize int) (map[string][]int32, int) {
+		pg, err := compilePCRE(&protocol.PatternInfo{
+			Pattern:         pattern,
+			IsRegExp:        true,
+			IsPCRE:          true,
+			IsCaseSensitive: true,
+		}, timeout, maxFileSize)
+		require.NoError(t, err)
+
+		ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 100)
+		defer cancel()
+		require.NoError(t, pcreSearch(ctx, pg, zf, true, false, false, sender))
+
+		got := map[string][]int32{}
+		for _, fm := range sender.collected {
+			for _, cm := range fm.ChunkMatches {
+				for _, r := range cm.Ranges {
+					got[fm.Path] = append(got[fm.Path], r.Start.Offset, r.End.Offset)
+				}
+			}
+		}
+		for _, offsets := range got {
+			sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+		}
+		return got, sender.SkippedFiles()
+	}
+
+	t.Run("lookahead", func(t *testing.T) {
+		got, skipped := search(`foo(?= := baz)`, 0, 0)
+		require.Equal(t, map[string][]int32{"lookahead.go": {11, 14}}, got)
+		require.Zero(t, skipped)
+	})
+
+	t.Run("multiline", func(t *testing.T) {
+		got, _ := search(`(?s)func a\(\) \{.*?^\}`, 0, 0)
+		require.Equal(t, map[string][]int32{"multiline.go": {0, 20}}, got)
+	})
+
+	t.Run("backreference", func(t *testing.T) {
+		got, _ := search(`(\w+) \1 world`, 0, 0)
+		require.Equal(t, map[string][]int32{"backref.txt": {0, 17}}, got)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		got, skipped := search(`(a+)+$`, 10*time.Millisecond, 0)
+		require.Empty(t, got)
+		require.Equal(t, 1, skipped)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		pg, err := compilePCRE(&protocol.PatternInfo{
+			Pattern:         `foo`,
+			IsRegExp:        true,
+			IsPCRE:          true,
+			IsCaseSensitive: true,
+		}, 0, 0)
+		require.NoError(t, err)
+
+		for i := range zf.Files {
+			if zf.Files[i].Name != "large.txt" {
+				continue
+			}
+			cms, ok := pg.Find(zf, &zf.Files[i], 3)
+			require.True(t, ok)
+
+			matches := 0
+			for _, cm := range cms {
+				matches += len(cm.Ranges)
+			}
+			require.Equal(t, 3, matches)
+		}
+	})
+
+	t.Run("max file size", func(t *testing.T) {
+		got, skipped := search(`foo foo`, 0, 100)
+		require.Empty(t, got)
+		require.Equal(t, 1, skipped)
+	})
+}
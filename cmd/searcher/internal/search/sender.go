@@ -14,6 +14,11 @@ type matchSender interface {
 	SentCount() int
 	Remaining() int
 	LimitHit() bool
+
+	// SkipFile records that a file was not searched because matching it
+	// exceeded the per-file limits of the pattern.
+	SkipFile()
+	SkippedFiles() int
 }
 
 type limitedStream struct {
@@ -21,6 +26,7 @@ type limitedStream struct {
 	limit     int
 	remaining *atomic.Int64
 	limitHit  *atomic.Bool
+	skipped   *atomic.Int64
 	cancel    context.CancelFunc
 }
 
@@ -36,6 +42,7 @@ func newLimitedStream(ctx context.Context, limit int, cb func(protocol.FileMatch
 		limit:     limit,
 		remaining: atomic.NewInt64(int64(limit)),
 		limitHit:  atomic.NewBool(false),
+		skipped:   atomic.NewInt64(0),
 	}
 	return ctx, cancel, s
 }
@@ -87,6 +94,14 @@ func (m *limitedStream) LimitHit() bool {
 	return m.limitHit.Load()
 }
 
+func (m *limitedStream) SkipFile() {
+	m.skipped.Inc()
+}
+
+func (m *limitedStream) SkippedFiles() int {
+	return int(m.skipped.Load())
+}
+
 type limitedStreamCollector struct {
 	collected []protocol.FileMatch
 	mux       sync.Mutex
@@ -49,6 +49,9 @@ import (
 var (
 	cacheDir    = env.Get("CACHE_DIR", "/tmp", "directory to store cached archives.")
 	cacheSizeMB = env.Get("SEARCHER_CACHE_SIZE_MB", "100000", "maximum size of the on disk cache in megabytes")
+
+	pcreMatchTimeout  = env.MustGetDuration("SEARCHER_PCRE_FILE_TIMEOUT", 500*time.Millisecond, "maximum time spent matching a patterntype:pcre pattern against a single file")
+	pcreMaxFileSizeKB = env.MustGetInt("SEARCHER_PCRE_MAX_FILE_SIZE_KB", 1024, "maximum size of files matched against a patterntype:pcre pattern in kilobytes")
 )
 
 const port = "3181"
@@ -175,8 +178,10 @@ func run(logger log.Logger) error {
 			ObservationContext: storeObservationContext,
 			DB:                 db,
 		},
-		GitDiffSymbols: git.DiffSymbols,
-		Log:            logger,
+		GitDiffSymbols:   git.DiffSymbols,
+		Log:              logger,
+		PCREMatchTimeout: pcreMatchTimeout,
+		PCREMaxFileSize:  pcreMaxFileSizeKB * 1024,
 	}
 	service.Store.Start()
 
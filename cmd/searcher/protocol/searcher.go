@@ -76,6 +76,11 @@ type PatternInfo struct {
 	// IsStructuralPat if true will treat the pattern as a Comby structural search pattern.
 	IsStructuralPat bool
 
+	// IsPCRE if true will treat the pattern as a regular expression with
+	// PCRE-like syntax, matched by a backtracking engine. IsRegExp is also
+	// true for these patterns.
+	IsPCRE bool
+
 	// IsWordMatch if true will only match the pattern at word boundaries.
 	IsWordMatch bool
 
@@ -138,6 +143,9 @@ func (p *PatternInfo) String() string {
 	if p.IsRegExp {
 		args = append(args, "re")
 	}
+	if p.IsPCRE {
+		args = append(args, "pcre")
+	}
 	if p.IsStructuralPat {
 		if p.CombyRule != "" {
 			args = append(args, fmt.Sprintf("comby:%s", p.CombyRule))
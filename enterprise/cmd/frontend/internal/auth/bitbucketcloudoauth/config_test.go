@@ -0,0 +1,101 @@
+package bitbucketcloudoauth
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"golang.org/x/oauth2"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/oauth"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestParseConfig(t *testing.T) {
+	db := database.NewMockDB()
+
+	type wantProvider struct {
+		serviceID    string
+		oauth2Config oauth2.Config
+	}
+	tests := []struct {
+		name          string
+		cfg           *conf.Unified
+		wantProviders []wantProvider
+		wantProblems  []string
+	}{
+		{
+			name: "No configs",
+			cfg:  &conf.Unified{},
+		},
+		{
+			name: "1 Bitbucket Cloud config",
+			cfg: &conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				ExternalURL: "https://sourcegraph.example.com",
+				AuthProviders: []schema.AuthProviders{{
+					Bitbucketcloud: &schema.BitbucketCloudAuthProvider{
+						ClientKey:    "my-client-key",
+						ClientSecret: "my-client-secret",
+						DisplayName:  "Bitbucket Cloud",
+						Type:         "bitbucketcloud",
+					},
+				}},
+			}},
+			wantProviders: []wantProvider{{
+				serviceID: "https://bitbucket.org/",
+				oauth2Config: oauth2.Config{
+					RedirectURL:  "https://sourcegraph.example.com/.auth/bitbucketcloud/callback",
+					ClientID:     "my-client-key",
+					ClientSecret: "my-client-secret",
+					Endpoint: oauth2.Endpoint{
+						AuthURL:  "https://bitbucket.org/site/oauth2/authorize",
+						TokenURL: "https://bitbucket.org/site/oauth2/access_token",
+					},
+					Scopes: []string{"account", "email", "repository", "team"},
+				},
+			}},
+		},
+		{
+			name: "No external URL",
+			cfg: &conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				AuthProviders: []schema.AuthProviders{{
+					Bitbucketcloud: &schema.BitbucketCloudAuthProvider{
+						ClientKey:    "my-client-key",
+						ClientSecret: "my-client-secret",
+						Type:         "bitbucketcloud",
+					},
+				}},
+			}},
+			wantProblems: []string{"`externalURL` was empty and it is needed to determine the OAuth callback URL."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotProviders, gotProblems := parseConfig(tt.cfg, db)
+
+			if len(gotProviders) != len(tt.wantProviders) {
+				t.Fatalf("parseConfig() got %d providers, want %d", len(gotProviders), len(tt.wantProviders))
+			}
+			for i, p := range gotProviders {
+				want := tt.wantProviders[i]
+				op := p.Provider.(*oauth.Provider)
+				if op.ServiceID != want.serviceID || op.ServiceType != extsvc.TypeBitbucketCloud {
+					t.Errorf("parseConfig() got provider %s %s, want %s %s", op.ServiceType, op.ServiceID, extsvc.TypeBitbucketCloud, want.serviceID)
+				}
+				if got := op.OAuth2Config(); !reflect.DeepEqual(got, want.oauth2Config) {
+					dmp := diffmatchpatch.New()
+					t.Errorf("parseConfig() OAuth2Config != want, diff:\n%s",
+						dmp.DiffPrettyText(dmp.DiffMain(spew.Sdump(want.oauth2Config), spew.Sdump(got), false)),
+					)
+				}
+			}
+			if !reflect.DeepEqual(gotProblems.Messages(), tt.wantProblems) {
+				t.Errorf("parseConfig() gotProblems = %v, want %v", gotProblems, tt.wantProblems)
+			}
+		})
+	}
+}
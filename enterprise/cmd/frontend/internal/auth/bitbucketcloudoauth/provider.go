@@ -0,0 +1,80 @@
+package bitbucketcloudoauth
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/dghubble/gologin"
+	"golang.org/x/oauth2"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/oauth"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const sessionKey = "bitbucketcloudoauth@0"
+
+// requestedScopes are the scopes requested from Bitbucket Cloud. Besides
+// reading the user and their email addresses, the repository and team scopes
+// allow syncing the repository permissions of the user.
+var requestedScopes = []string{"account", "email", "repository", "team"}
+
+func parseProvider(db database.DB, callbackURL string, p *schema.BitbucketCloudAuthProvider, sourceCfg schema.AuthProviders) (provider *oauth.Provider, messages []string) {
+	rawURL := p.Url
+	if rawURL == "" {
+		rawURL = "https://bitbucket.org/"
+	}
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		messages = append(messages, fmt.Sprintf("Could not parse Bitbucket Cloud URL %q. You will not be able to login via Bitbucket Cloud.", rawURL))
+		return nil, messages
+	}
+	codeHost := extsvc.NewCodeHost(parsedURL, extsvc.TypeBitbucketCloud)
+
+	return oauth.NewProvider(oauth.ProviderOp{
+		AuthPrefix: authPrefix,
+		OAuth2Config: func(extraScopes ...string) oauth2.Config {
+			return oauth2.Config{
+				RedirectURL:  callbackURL,
+				ClientID:     p.ClientKey,
+				ClientSecret: p.ClientSecret,
+				Scopes:       append(append([]string{}, requestedScopes...), extraScopes...),
+				Endpoint:     bitbucketcloud.OAuthEndpoint(codeHost.BaseURL),
+			}
+		},
+		SourceConfig: sourceCfg,
+		StateConfig:  getStateConfig(),
+		ServiceID:    codeHost.ServiceID,
+		ServiceType:  codeHost.ServiceType,
+		Login: func(oauth2Cfg oauth2.Config) http.Handler {
+			return LoginHandler(&oauth2Cfg, nil)
+		},
+		Callback: func(oauth2Cfg oauth2.Config) http.Handler {
+			return CallbackHandler(
+				&oauth2Cfg,
+				oauth.SessionIssuer(db, &sessionIssuerHelper{
+					db:          db,
+					CodeHost:    codeHost,
+					clientKey:   p.ClientKey,
+					allowSignup: p.AllowSignup,
+				}, sessionKey),
+				nil,
+			)
+		},
+	}), messages
+}
+
+func getStateConfig() gologin.CookieConfig {
+	cfg := gologin.CookieConfig{
+		Name:     "bitbucketcloud-state-cookie",
+		Path:     "/",
+		MaxAge:   900, // 15 minutes
+		HTTPOnly: true,
+		Secure:   conf.IsExternalURLSecure(),
+	}
+	return cfg
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/external/app"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/bitbucketcloudoauth"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/githuboauth"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/gitlaboauth"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/httpheader"
@@ -29,6 +30,7 @@ func Init(db database.DB) {
 	httpheader.Init()
 	githuboauth.Init(db)
 	gitlaboauth.Init(db)
+	bitbucketcloudoauth.Init(db)
 
 	// Register enterprise auth middleware
 	auth.RegisterMiddlewares(
@@ -37,6 +39,7 @@ func Init(db database.DB) {
 		httpheader.Middleware(db),
 		githuboauth.Middleware(db),
 		gitlaboauth.Middleware(db),
+		bitbucketcloudoauth.Middleware(db),
 	)
 	// Register app-level sign-out handler
 	app.RegisterSSOSignOutHandler(ssoSignOutHandler)
@@ -66,6 +69,8 @@ func Init(db database.DB) {
 				name = "GitHub OAuth"
 			case p.Gitlab != nil:
 				name = "GitLab OAuth"
+			case p.Bitbucketcloud != nil:
+				name = "Bitbucket Cloud OAuth"
 			case p.HttpHeader != nil:
 				name = "HTTP header"
 			case p.Openidconnect != nil:
@@ -36,6 +36,7 @@ func Init(ctx context.Context, db database.DB, _ conftypes.UnifiedWatchable, ent
 	enterpriseServices.GitHubWebhook = webhooks.NewGitHubWebhook(bstore)
 	enterpriseServices.BitbucketServerWebhook = webhooks.NewBitbucketServerWebhook(bstore)
 	enterpriseServices.BitbucketCloudWebhook = webhooks.NewBitbucketCloudWebhook(bstore)
+	enterpriseServices.AzureDevOpsWebhook = webhooks.NewAzureDevOpsWebhook(bstore)
 	enterpriseServices.GitLabWebhook = webhooks.NewGitLabWebhook(bstore)
 
 	return nil
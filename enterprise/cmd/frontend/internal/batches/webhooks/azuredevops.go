@@ -0,0 +1,167 @@
+package webhooks
+
+import (
+	"crypto/subtle"
+	"io"
+	"net/http"
+	"strconv"
+
+	fewebhooks "github.com/sourcegraph/sourcegraph/cmd/frontend/webhooks"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type AzureDevOpsWebhook struct {
+	*Webhook
+}
+
+func NewAzureDevOpsWebhook(store *store.Store) *AzureDevOpsWebhook {
+	return &AzureDevOpsWebhook{
+		Webhook: &Webhook{store, extsvc.TypeAzureDevOps},
+	}
+}
+
+func (h *AzureDevOpsWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e, extSvc, hErr := h.parseEvent(r)
+	if hErr != nil {
+		respond(w, hErr.code, hErr)
+		return
+	} else if e == nil {
+		// Unknown type, so we'll just ignore it.
+		respond(w, http.StatusNoContent, nil)
+		return
+	}
+
+	fewebhooks.SetExternalServiceID(r.Context(), extSvc.ID)
+
+	// 🚨 SECURITY: now that the shared secret has been validated, we can use an
+	// internal actor on the context.
+	ctx := actor.WithInternalActor(r.Context())
+
+	externalServiceID, err := extractExternalServiceID(ctx, extSvc)
+	if err != nil {
+		respond(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	pr, ev, err := h.convertEvent(e)
+	if err != nil {
+		respond(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := h.upsertChangesetEvent(ctx, externalServiceID, pr, ev); err != nil {
+		respond(w, http.StatusInternalServerError, err)
+	} else {
+		respond(w, http.StatusNoContent, nil)
+	}
+}
+
+func (h *AzureDevOpsWebhook) parseEvent(r *http.Request) (any, *types.ExternalService, *httpError) {
+	if r.Body == nil {
+		return nil, nil, &httpError{http.StatusBadRequest, errors.New("nil request body")}
+	}
+
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, nil, &httpError{http.StatusInternalServerError, err}
+	}
+
+	rawID := r.FormValue(extsvc.IDParam)
+	var externalServiceID int64
+	if rawID != "" {
+		externalServiceID, err = strconv.ParseInt(rawID, 10, 64)
+		if err != nil {
+			return nil, nil, &httpError{http.StatusBadRequest, errors.Wrap(err, "invalid external service id")}
+		}
+	}
+
+	args := database.ExternalServicesListOptions{Kinds: []string{extsvc.KindAzureDevOps}}
+	if externalServiceID != 0 {
+		args.IDs = append(args.IDs, externalServiceID)
+	}
+	es, err := h.Store.ExternalServices().List(r.Context(), args)
+	if err != nil {
+		return nil, nil, &httpError{http.StatusInternalServerError, err}
+	}
+
+	var extSvc *types.ExternalService
+	for _, e := range es {
+		if externalServiceID != 0 && e.ID != externalServiceID {
+			continue
+		}
+
+		c, _ := e.Configuration(r.Context())
+		con, ok := c.(*schema.AzureDevOpsConnection)
+		if !ok {
+			continue
+		}
+
+		// 🚨 SECURITY: Verify the shared secret against the Azure DevOps
+		// external service configuration.
+		if validateAzureDevOpsSecret(r, con) {
+			extSvc = e
+			break
+		}
+	}
+
+	if extSvc == nil || err != nil {
+		return nil, nil, &httpError{http.StatusUnauthorized, err}
+	}
+
+	// Service hooks can be subscribed to any event, so events we don't handle
+	// aren't an error.
+	e, err := azuredevops.ParseWebhookEvent(payload)
+	if errors.HasType(err, azuredevops.UnknownWebhookEventType("")) {
+		return nil, extSvc, nil
+	} else if err != nil {
+		return nil, nil, &httpError{http.StatusBadRequest, errors.Wrap(err, "parsing webhook")}
+	}
+
+	return e, extSvc, nil
+}
+
+// validateAzureDevOpsSecret validates that the basic authentication password
+// of the request matches the webhook secret of the given configuration. Azure
+// DevOps service hooks can't sign their payloads, but they can send basic
+// authentication credentials; the username is ignored.
+func validateAzureDevOpsSecret(r *http.Request, con *schema.AzureDevOpsConnection) bool {
+	// An empty secret never succeeds.
+	if con.WebhookSecret == "" {
+		return false
+	}
+
+	_, password, ok := r.BasicAuth()
+	if !ok || password == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(password), []byte(con.WebhookSecret)) == 1
+}
+
+func (h *AzureDevOpsWebhook) convertEvent(theirs any) (PR, keyer, error) {
+	switch e := theirs.(type) {
+	case *azuredevops.PullRequestUpdatedEvent:
+		return azureDevOpsPullRequestPR(&e.PullRequest), e, nil
+	case *azuredevops.PullRequestMergedEvent:
+		return azureDevOpsPullRequestPR(&e.PullRequest), e, nil
+	case *azuredevops.PullRequestCommentEvent:
+		return azureDevOpsPullRequestPR(&e.Resource.PullRequest), e, nil
+	default:
+		return PR{}, nil, errors.Newf("unknown event type: %T", theirs)
+	}
+}
+
+func azureDevOpsPullRequestPR(pr *azuredevops.PullRequest) PR {
+	var repoID string
+	if pr.Repository != nil {
+		repoID = pr.Repository.ID
+	}
+	return PR{ID: pr.PullRequestID, RepoExternalID: repoID}
+}
@@ -0,0 +1,93 @@
+package webhooks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestValidateAzureDevOpsSecret(t *testing.T) {
+	con := &schema.AzureDevOpsConnection{WebhookSecret: "secret"}
+
+	for name, tc := range map[string]struct {
+		con    *schema.AzureDevOpsConnection
+		modify func(r *http.Request)
+		want   bool
+	}{
+		"valid secret": {
+			con:    con,
+			modify: func(r *http.Request) { r.SetBasicAuth("sourcegraph", "secret") },
+			want:   true,
+		},
+		"incorrect secret": {
+			con:    con,
+			modify: func(r *http.Request) { r.SetBasicAuth("sourcegraph", "not correct") },
+			want:   false,
+		},
+		"missing credentials": {
+			con:    con,
+			modify: func(r *http.Request) {},
+			want:   false,
+		},
+		"secret in the query": {
+			con: con,
+			modify: func(r *http.Request) {
+				r.URL.RawQuery = "secret=secret"
+			},
+			want: false,
+		},
+		"no secret configured": {
+			con:    &schema.AzureDevOpsConnection{},
+			modify: func(r *http.Request) { r.SetBasicAuth("sourcegraph", "") },
+			want:   false,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/.api/azure-devops-webhooks", nil)
+			tc.modify(r)
+			assert.Equal(t, tc.want, validateAzureDevOpsSecret(r, tc.con))
+		})
+	}
+}
+
+func TestAzureDevOpsWebhook_convertEvent(t *testing.T) {
+	h := NewAzureDevOpsWebhook(nil)
+
+	for name, tc := range map[string]struct {
+		payload string
+		wantKey string
+	}{
+		"updated": {
+			payload: `{"id": "e1", "eventType": "git.pullrequest.updated", "resource": {"pullRequestId": 2, "repository": {"id": "r1"}}}`,
+			wantKey: "r1:2:updated:e1",
+		},
+		"merged": {
+			payload: `{"id": "e2", "eventType": "git.pullrequest.merged", "resource": {"pullRequestId": 2, "repository": {"id": "r1"}}}`,
+			wantKey: "r1:2:merged:e2",
+		},
+		"comment": {
+			payload: `{"id": "e3", "eventType": "ms.vss-code.git-pullrequest-comment-event", "resource": {"comment": {"id": 5}, "pullRequest": {"pullRequestId": 2, "repository": {"id": "r1"}}}}`,
+			wantKey: "r1:2:comment:5",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			e, err := azuredevops.ParseWebhookEvent([]byte(tc.payload))
+			assert.Nil(t, err)
+
+			pr, ev, err := h.convertEvent(e)
+			assert.Nil(t, err)
+			assert.Equal(t, PR{ID: 2, RepoExternalID: "r1"}, pr)
+			assert.Equal(t, tc.wantKey, ev.Key())
+		})
+	}
+
+	t.Run("unknown event", func(t *testing.T) {
+		_, _, err := h.convertEvent(&azuredevops.Event{})
+		assert.NotNil(t, err)
+	})
+}
@@ -76,6 +76,8 @@ func extractExternalServiceID(ctx context.Context, extSvc *types.ExternalService
 		serviceID = c.Url
 	case *schema.BitbucketCloudConnection:
 		serviceID = c.Url
+	case *schema.AzureDevOpsConnection:
+		serviceID = c.Url
 	}
 	if serviceID == "" {
 		return "", errors.New("could not determine service id")
@@ -222,7 +222,12 @@ func toResultResolverList(ctx context.Context, cmd compute.Command, matches []re
 // NewBatchComputeImplementer is a function that abstracts away the need to have a
 // handle on (*schemaResolver) Compute.
 func NewBatchComputeImplementer(ctx context.Context, logger log.Logger, db database.DB, args *gql.ComputeArgs) ([]gql.ComputeResultResolver, error) {
-	computeQuery, err := compute.Parse(args.Query)
+	settings, err := gql.DecodedViewerFinalSettings(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	computeQuery, err := compute.Parse(args.Query, settings.SearchMacros)
 	if err != nil {
 		return nil, err
 	}
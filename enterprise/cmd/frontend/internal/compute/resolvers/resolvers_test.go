@@ -13,7 +13,7 @@ import (
 
 func TestToResultResolverList(t *testing.T) {
 	test := func(input string, matches []result.Match) string {
-		computeQuery, _ := compute.Parse(input)
+		computeQuery, _ := compute.Parse(input, nil)
 		resolvers, _ := toResultResolverList(
 			context.Background(),
 			computeQuery.Command,
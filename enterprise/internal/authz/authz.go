@@ -9,7 +9,10 @@ import (
 
 	"github.com/inconshreveable/log15"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/authz/azuredevops"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/authz/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/authz/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/authz/gitea"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/authz/github"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/authz/gitlab"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/authz/perforce"
@@ -58,7 +61,10 @@ func ProvidersFromConfig(
 			extsvc.KindGitHub,
 			extsvc.KindGitLab,
 			extsvc.KindBitbucketServer,
+			extsvc.KindBitbucketCloud,
 			extsvc.KindPerforce,
+			extsvc.KindGitea,
+			extsvc.KindAzureDevOps,
 		},
 		LimitOffset: &database.LimitOffset{
 			Limit: 500, // The number is randomly chosen
@@ -69,7 +75,10 @@ func ProvidersFromConfig(
 		gitHubConns          []*github.ExternalConnection
 		gitLabConns          []*types.GitLabConnection
 		bitbucketServerConns []*types.BitbucketServerConnection
+		bitbucketCloudConns  []*types.BitbucketCloudConnection
 		perforceConns        []*types.PerforceConnection
+		giteaConns           []*types.GiteaConnection
+		azureDevOpsConns     []*types.AzureDevOpsConnection
 	)
 	for {
 		svcs, err := store.List(ctx, opt)
@@ -114,11 +123,26 @@ func ProvidersFromConfig(
 					URN:                       svc.URN(),
 					BitbucketServerConnection: c,
 				})
+			case *schema.BitbucketCloudConnection:
+				bitbucketCloudConns = append(bitbucketCloudConns, &types.BitbucketCloudConnection{
+					URN:                      svc.URN(),
+					BitbucketCloudConnection: c,
+				})
 			case *schema.PerforceConnection:
 				perforceConns = append(perforceConns, &types.PerforceConnection{
 					URN:                svc.URN(),
 					PerforceConnection: c,
 				})
+			case *schema.GiteaConnection:
+				giteaConns = append(giteaConns, &types.GiteaConnection{
+					URN:             svc.URN(),
+					GiteaConnection: c,
+				})
+			case *schema.AzureDevOpsConnection:
+				azureDevOpsConns = append(azureDevOpsConns, &types.AzureDevOpsConnection{
+					URN:                   svc.URN(),
+					AzureDevOpsConnection: c,
+				})
 			default:
 				log15.Error("ProvidersFromConfig", "error", errors.Errorf("unexpected connection type: %T", cfg))
 				continue
@@ -157,6 +181,13 @@ func ProvidersFromConfig(
 		warnings = append(warnings, bbsWarnings...)
 	}
 
+	if len(bitbucketCloudConns) > 0 {
+		bbcProviders, bbcProblems, bbcWarnings := bitbucketcloud.NewAuthzProviders(db, bitbucketCloudConns, cfg.SiteConfig().AuthProviders)
+		providers = append(providers, bbcProviders...)
+		seriousProblems = append(seriousProblems, bbcProblems...)
+		warnings = append(warnings, bbcWarnings...)
+	}
+
 	if len(perforceConns) > 0 {
 		pfProviders, pfProblems, pfWarnings := perforce.NewAuthzProviders(perforceConns, db)
 		providers = append(providers, pfProviders...)
@@ -164,6 +195,20 @@ func ProvidersFromConfig(
 		warnings = append(warnings, pfWarnings...)
 	}
 
+	if len(giteaConns) > 0 {
+		gtProviders, gtProblems, gtWarnings := gitea.NewAuthzProviders(giteaConns)
+		providers = append(providers, gtProviders...)
+		seriousProblems = append(seriousProblems, gtProblems...)
+		warnings = append(warnings, gtWarnings...)
+	}
+
+	if len(azureDevOpsConns) > 0 {
+		adoProviders, adoProblems, adoWarnings := azuredevops.NewAuthzProviders(azureDevOpsConns)
+		providers = append(providers, adoProviders...)
+		seriousProblems = append(seriousProblems, adoProblems...)
+		warnings = append(warnings, adoWarnings...)
+	}
+
 	// 🚨 SECURITY: Warn the admin when both code host authz provider and the permissions user mapping are configured.
 	if cfg.SiteConfig().PermissionsUserMapping != nil &&
 		cfg.SiteConfig().PermissionsUserMapping.Enabled {
@@ -255,6 +300,17 @@ func ProviderFromExternalService(
 				},
 			},
 		)
+	case *schema.BitbucketCloudConnection:
+		providers, problems, _ = bitbucketcloud.NewAuthzProviders(
+			db,
+			[]*types.BitbucketCloudConnection{
+				{
+					URN:                      svc.URN(),
+					BitbucketCloudConnection: c,
+				},
+			},
+			siteConfig.AuthProviders,
+		)
 	case *schema.PerforceConnection:
 		providers, problems, _ = perforce.NewAuthzProviders(
 			[]*types.PerforceConnection{
@@ -265,6 +321,24 @@ func ProviderFromExternalService(
 			},
 			db,
 		)
+	case *schema.GiteaConnection:
+		providers, problems, _ = gitea.NewAuthzProviders(
+			[]*types.GiteaConnection{
+				{
+					URN:             svc.URN(),
+					GiteaConnection: c,
+				},
+			},
+		)
+	case *schema.AzureDevOpsConnection:
+		providers, problems, _ = azuredevops.NewAuthzProviders(
+			[]*types.AzureDevOpsConnection{
+				{
+					URN:                   svc.URN(),
+					AzureDevOpsConnection: c,
+				},
+			},
+		)
 	default:
 		return nil, errors.Errorf("unsupported connection type %T", cfg)
 	}
@@ -84,6 +84,9 @@ func TestAuthzProvidersFromConfig(t *testing.T) {
 		cfg                          conf.Unified
 		gitlabConnections            []*schema.GitLabConnection
 		bitbucketServerConnections   []*schema.BitbucketServerConnection
+		bitbucketCloudConnections    []*schema.BitbucketCloudConnection
+		giteaConnections             []*schema.GiteaConnection
+		azureDevOpsConnections       []*schema.AzureDevOpsConnection
 		expAuthzAllowAccessByDefault bool
 		expAuthzProviders            func(*testing.T, []authz.Provider)
 		expSeriousProblems           []string
@@ -395,6 +398,118 @@ func TestAuthzProvidersFromConfig(t *testing.T) {
 				}
 			},
 		},
+		{
+			description: "1 Gitea connection with authz disabled",
+			giteaConnections: []*schema.GiteaConnection{
+				{
+					Url:   "https://gitea.mycorp.org",
+					Token: "secret-token",
+				},
+			},
+			expAuthzAllowAccessByDefault: true,
+			expAuthzProviders:            providersEqual(),
+		},
+		{
+			description: "1 Gitea connection with authz enabled",
+			giteaConnections: []*schema.GiteaConnection{
+				{
+					Authorization: &schema.GiteaAuthorization{},
+					Url:           "https://gitea.mycorp.org",
+					Token:         "secret-token",
+				},
+			},
+			expAuthzAllowAccessByDefault: true,
+			expAuthzProviders: func(t *testing.T, have []authz.Provider) {
+				if len(have) != 1 {
+					t.Fatalf("want 1 provider, got %d", len(have))
+				}
+
+				if have[0].ServiceType() != extsvc.TypeGitea || have[0].ServiceID() != "https://gitea.mycorp.org/" {
+					t.Fatalf("unexpected provider %s %s", have[0].ServiceType(), have[0].ServiceID())
+				}
+			},
+		},
+		{
+			description: "1 Bitbucket Cloud connection with authz enabled, 1 Bitbucket Cloud matching auth provider",
+			cfg: conf.Unified{
+				SiteConfiguration: schema.SiteConfiguration{
+					AuthProviders: []schema.AuthProviders{{
+						Bitbucketcloud: &schema.BitbucketCloudAuthProvider{
+							ClientKey:    "clientKey",
+							ClientSecret: "clientSecret",
+							Type:         "bitbucketcloud",
+						},
+					}},
+				},
+			},
+			bitbucketCloudConnections: []*schema.BitbucketCloudConnection{
+				{
+					Authorization: &schema.BitbucketCloudAuthorization{},
+					Url:           "https://bitbucket.org",
+					Username:      "admin",
+					AppPassword:   "secret-password",
+				},
+			},
+			expAuthzAllowAccessByDefault: true,
+			expAuthzProviders: func(t *testing.T, have []authz.Provider) {
+				if len(have) != 1 {
+					t.Fatalf("want 1 provider, got %d", len(have))
+				}
+
+				if have[0].ServiceType() != extsvc.TypeBitbucketCloud || have[0].ServiceID() != "https://bitbucket.org/" {
+					t.Fatalf("unexpected provider %s %s", have[0].ServiceType(), have[0].ServiceID())
+				}
+			},
+		},
+		{
+			description: "1 Bitbucket Cloud connection with authz enabled, no matching auth provider",
+			bitbucketCloudConnections: []*schema.BitbucketCloudConnection{
+				{
+					Authorization: &schema.BitbucketCloudAuthorization{},
+					Url:           "https://bitbucket.org",
+					Username:      "admin",
+					AppPassword:   "secret-password",
+				},
+			},
+			expAuthzAllowAccessByDefault: false,
+			expAuthzProviders:            providersEqual(),
+			expSeriousProblems: []string{
+				"Bitbucket Cloud config for https://bitbucket.org has `authorization` enabled, but no authentication provider matching \"https://bitbucket.org\" was found. Check the [**site configuration**](/site-admin/configuration) to verify an entry in [`auth.providers`](https://docs.sourcegraph.com/admin/auth) exists for https://bitbucket.org.",
+			},
+		},
+		{
+			description: "1 Azure DevOps connection with authz disabled",
+			azureDevOpsConnections: []*schema.AzureDevOpsConnection{
+				{
+					Url:   "https://dev.azure.com",
+					Token: "secret-token",
+					Orgs:  []string{"mycorp"},
+				},
+			},
+			expAuthzAllowAccessByDefault: true,
+			expAuthzProviders:            providersEqual(),
+		},
+		{
+			description: "1 Azure DevOps connection with authz enabled",
+			azureDevOpsConnections: []*schema.AzureDevOpsConnection{
+				{
+					Authorization: &schema.AzureDevOpsAuthorization{},
+					Url:           "https://dev.azure.com",
+					Token:         "secret-token",
+					Orgs:          []string{"mycorp"},
+				},
+			},
+			expAuthzAllowAccessByDefault: true,
+			expAuthzProviders: func(t *testing.T, have []authz.Provider) {
+				if len(have) != 1 {
+					t.Fatalf("want 1 provider, got %d", len(have))
+				}
+
+				if have[0].ServiceType() != extsvc.TypeAzureDevOps || have[0].ServiceID() != "https://dev.azure.com/" {
+					t.Fatalf("unexpected provider %s %s", have[0].ServiceType(), have[0].ServiceID())
+				}
+			},
+		},
 
 		// For Sourcegraph authz provider
 		{
@@ -488,6 +603,27 @@ func TestAuthzProvidersFromConfig(t *testing.T) {
 								Config: extsvc.NewUnencryptedConfig(mustMarshalJSONString(bbs)),
 							})
 						}
+					case extsvc.KindBitbucketCloud:
+						for _, bbc := range test.bitbucketCloudConnections {
+							svcs = append(svcs, &types.ExternalService{
+								Kind:   kind,
+								Config: extsvc.NewUnencryptedConfig(mustMarshalJSONString(bbc)),
+							})
+						}
+					case extsvc.KindGitea:
+						for _, gt := range test.giteaConnections {
+							svcs = append(svcs, &types.ExternalService{
+								Kind:   kind,
+								Config: extsvc.NewUnencryptedConfig(mustMarshalJSONString(gt)),
+							})
+						}
+					case extsvc.KindAzureDevOps:
+						for _, ado := range test.azureDevOpsConnections {
+							svcs = append(svcs, &types.ExternalService{
+								Kind:   kind,
+								Config: extsvc.NewUnencryptedConfig(mustMarshalJSONString(ado)),
+							})
+						}
 					case extsvc.KindGitHub, extsvc.KindPerforce:
 					default:
 						return nil, errors.Errorf("unexpected kind: %s", kind)
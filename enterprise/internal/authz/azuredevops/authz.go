@@ -0,0 +1,37 @@
+package azuredevops
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// NewAuthzProviders returns the set of Azure DevOps authz providers derived from the connections.
+//
+// It also returns any simple validation problems with the config, separating these into "serious problems"
+// and "warnings". "Serious problems" are those that should make Sourcegraph set authz.allowAccessByDefault
+// to false. "Warnings" are all other validation problems.
+//
+// This constructor does not and should not directly check connectivity to external services - if
+// desired, callers should use `(*Provider).ValidateConnection` directly to get warnings related
+// to connection issues.
+func NewAuthzProviders(conns []*types.AzureDevOpsConnection) (ps []authz.Provider, problems []string, warnings []string) {
+	for _, c := range conns {
+		if c.Authorization == nil {
+			continue
+		}
+
+		cli, err := azuredevops.NewClient(c.URN, c.AzureDevOpsConnection, nil)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+
+		p := NewProvider(c.URN, cli, c.Orgs, c.Projects)
+		if len(p.orgs) == 0 {
+			warnings = append(warnings, "Azure DevOps connection "+c.URN+" enforces permissions but has no \"orgs\" or \"projects\" configured")
+		}
+		ps = append(ps, p)
+	}
+	return ps, problems, warnings
+}
@@ -0,0 +1,229 @@
+// Package azuredevops contains an authorization provider for Azure DevOps.
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// client is the subset of the Azure DevOps API client used by the Provider.
+type client interface {
+	ListProjects(ctx context.Context, org string, opts azuredevops.ListOptions) ([]*azuredevops.Project, string, error)
+	ListRepositories(ctx context.Context, org, project string) ([]*azuredevops.Repository, error)
+	SearchIdentities(ctx context.Context, org, searchFilter, filterValue, queryMembership string) ([]*azuredevops.Identity, error)
+}
+
+// Provider is an implementation of AuthzProvider that provides repository
+// permissions as determined from the Azure DevOps API. Sourcegraph users are
+// mapped to the Azure DevOps identity with the same verified email address,
+// and can read the repositories of the projects whose "Project Valid Users"
+// group they are a member of.
+type Provider struct {
+	urn      string
+	client   client
+	codeHost *extsvc.CodeHost
+	// orgs are the organizations whose projects are checked for permissions.
+	orgs []string
+}
+
+var _ authz.Provider = (*Provider)(nil)
+
+// accountData is the data stored in the external account of a user.
+type accountData struct {
+	Email    string                `json:"email"`
+	Identity *azuredevops.Identity `json:"identity"`
+}
+
+// NewProvider returns a new Azure DevOps authorization provider that uses the
+// given azuredevops.Client, which must be allowed to read the identities of
+// the given organizations. The organizations of projects, given in the form
+// "org/project", are checked as well.
+func NewProvider(urn string, cli *azuredevops.Client, orgs, projects []string) *Provider {
+	seen := make(map[string]bool)
+	var allOrgs []string
+	for _, org := range orgs {
+		if !seen[org] {
+			seen[org] = true
+			allOrgs = append(allOrgs, org)
+		}
+	}
+	for _, p := range projects {
+		org, _, _ := strings.Cut(p, "/")
+		if !seen[org] {
+			seen[org] = true
+			allOrgs = append(allOrgs, org)
+		}
+	}
+
+	return &Provider{
+		urn:      urn,
+		client:   cli,
+		codeHost: extsvc.NewCodeHost(cli.URL, extsvc.TypeAzureDevOps),
+		orgs:     allOrgs,
+	}
+}
+
+func (p *Provider) URN() string {
+	return p.urn
+}
+
+// ServiceID returns the absolute URL that identifies the Azure DevOps instance
+// this provider is configured with.
+func (p *Provider) ServiceID() string { return p.codeHost.ServiceID }
+
+// ServiceType returns the type of this Provider, namely, "azuredevops".
+func (p *Provider) ServiceType() string { return p.codeHost.ServiceType }
+
+// ValidateConnection validates that the Provider has access to the projects of
+// the configured organizations.
+func (p *Provider) ValidateConnection(ctx context.Context) []string {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var problems []string
+	for _, org := range p.orgs {
+		if _, _, err := p.client.ListProjects(ctx, org, azuredevops.ListOptions{Top: 1}); err != nil {
+			problems = append(problems, errors.Wrapf(err, "listing projects of organization %q", org).Error())
+		}
+	}
+	return problems
+}
+
+// FetchAccount returns the Azure DevOps identity with one of the verified email
+// addresses of the given user, or nil if there is none.
+func (p *Provider) FetchAccount(ctx context.Context, user *types.User, _ []*extsvc.Account, verifiedEmails []string) (*extsvc.Account, error) {
+	if user == nil {
+		return nil, nil
+	}
+
+	for _, email := range verifiedEmails {
+		for _, org := range p.orgs {
+			identities, err := p.client.SearchIdentities(ctx, org, azuredevops.IdentitySearchFilterMailAddress, email, azuredevops.QueryMembershipNone)
+			if err != nil {
+				return nil, err
+			}
+			if len(identities) == 0 {
+				continue
+			}
+
+			data, err := json.Marshal(accountData{Email: email, Identity: identities[0]})
+			if err != nil {
+				return nil, err
+			}
+
+			return &extsvc.Account{
+				UserID: user.ID,
+				AccountSpec: extsvc.AccountSpec{
+					ServiceType: p.codeHost.ServiceType,
+					ServiceID:   p.codeHost.ServiceID,
+					AccountID:   identities[0].ID,
+				},
+				AccountData: extsvc.AccountData{
+					Data: extsvc.NewUnencryptedData(data),
+				},
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// FetchUserPerms returns a list of repository IDs (on code host) that the given account
+// has read access on the code host. The repository ID has the same value as it would be
+// used as api.ExternalRepoSpec.ID. The returned list only includes private repository IDs.
+//
+// This method may return partial but valid results in case of error, and it is up to
+// callers to decide whether to discard.
+func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account, opts authz.FetchPermsOptions) (*authz.ExternalUserPermissions, error) {
+	switch {
+	case account == nil:
+		return nil, errors.New("no account provided")
+	case account.Data == nil:
+		return nil, errors.New("no account data provided")
+	case !extsvc.IsHostOfAccount(p.codeHost, account):
+		return nil, errors.Errorf("not a code host of the account: want %q but have %q",
+			p.codeHost.ServiceID, account.AccountSpec.ServiceID)
+	}
+
+	var data accountData
+	if err := encryption.DecryptJSON(ctx, account.Data, &data); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling account data")
+	}
+
+	perms := &authz.ExternalUserPermissions{}
+	for _, org := range p.orgs {
+		if err := p.fetchOrgPerms(ctx, org, data.Email, perms); err != nil {
+			return perms, errors.Wrapf(err, "fetching permissions in organization %q", org)
+		}
+	}
+	return perms, nil
+}
+
+// fetchOrgPerms adds the private repositories of the given organization that
+// the identity with the given email can read to perms.
+func (p *Provider) fetchOrgPerms(ctx context.Context, org, email string, perms *authz.ExternalUserPermissions) error {
+	// Identities are scoped to organizations, so we look the user up again in
+	// every organization, together with all the groups they're a member of.
+	identities, err := p.client.SearchIdentities(ctx, org, azuredevops.IdentitySearchFilterMailAddress, email, azuredevops.QueryMembershipExpanded)
+	if err != nil || len(identities) == 0 {
+		return err
+	}
+	memberOf := make(map[string]bool, len(identities[0].MemberOf))
+	for _, group := range identities[0].MemberOf {
+		memberOf[group] = true
+	}
+
+	opts := azuredevops.ListOptions{}
+	for {
+		projects, continuationToken, err := p.client.ListProjects(ctx, org, opts)
+		if err != nil {
+			return err
+		}
+
+		for _, project := range projects {
+			if project.Visibility == azuredevops.ProjectVisibilityPublic {
+				continue
+			}
+
+			groups, err := p.client.SearchIdentities(ctx, org, azuredevops.IdentitySearchFilterGeneral, "["+project.Name+"]\\Project Valid Users", azuredevops.QueryMembershipNone)
+			if err != nil {
+				return err
+			}
+			if len(groups) == 0 || !memberOf[groups[0].Descriptor] {
+				continue
+			}
+
+			repos, err := p.client.ListRepositories(ctx, org, project.ID)
+			if err != nil {
+				return err
+			}
+			for _, r := range repos {
+				perms.Exacts = append(perms.Exacts, extsvc.RepoID(r.ID))
+			}
+		}
+
+		if continuationToken == "" {
+			return nil
+		}
+		opts.ContinuationToken = continuationToken
+	}
+}
+
+// FetchRepoPerms is not implemented for Azure DevOps, since permissions are
+// fetched per user.
+func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository, opts authz.FetchPermsOptions) ([]extsvc.AccountID, error) {
+	return nil, &authz.ErrUnimplemented{Feature: "azuredevops.FetchRepoPerms"}
+}
+
+// FetchUserPermsByToken is not implemented for Azure DevOps.
+func (p *Provider) FetchUserPermsByToken(ctx context.Context, token string, opts authz.FetchPermsOptions) (*authz.ExternalUserPermissions, error) {
+	return nil, &authz.ErrUnimplemented{Feature: "azuredevops.FetchUserPermsByToken"}
+}
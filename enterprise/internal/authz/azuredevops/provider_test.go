@@ -0,0 +1,201 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type mockClient struct {
+	mockListProjects     func(ctx context.Context, org string, opts azuredevops.ListOptions) ([]*azuredevops.Project, string, error)
+	mockListRepositories func(ctx context.Context, org, project string) ([]*azuredevops.Repository, error)
+	mockSearchIdentities func(ctx context.Context, org, searchFilter, filterValue, queryMembership string) ([]*azuredevops.Identity, error)
+}
+
+func (m *mockClient) ListProjects(ctx context.Context, org string, opts azuredevops.ListOptions) ([]*azuredevops.Project, string, error) {
+	return m.mockListProjects(ctx, org, opts)
+}
+
+func (m *mockClient) ListRepositories(ctx context.Context, org, project string) ([]*azuredevops.Repository, error) {
+	return m.mockListRepositories(ctx, org, project)
+}
+
+func (m *mockClient) SearchIdentities(ctx context.Context, org, searchFilter, filterValue, queryMembership string) ([]*azuredevops.Identity, error) {
+	return m.mockSearchIdentities(ctx, org, searchFilter, filterValue, queryMembership)
+}
+
+func newTestProvider(cli client) *Provider {
+	baseURL, _ := url.Parse("https://dev.azure.com/")
+	return &Provider{
+		urn:      "extsvc:azuredevops:1",
+		client:   cli,
+		codeHost: extsvc.NewCodeHost(baseURL, extsvc.TypeAzureDevOps),
+		orgs:     []string{"myorg"},
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	cli, err := azuredevops.NewClient("Test", &schema.AzureDevOpsConnection{Url: "https://dev.azure.com", Token: "secret"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewProvider("extsvc:azuredevops:1", cli, []string{"a", "b"}, []string{"b/x", "c/y"})
+	if diff := cmp.Diff([]string{"a", "b", "c"}, p.orgs); diff != "" {
+		t.Fatalf("orgs mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestProvider_FetchAccount(t *testing.T) {
+	ctx := context.Background()
+	user := &types.User{ID: 42, Username: "alice"}
+
+	p := newTestProvider(&mockClient{
+		mockSearchIdentities: func(_ context.Context, org, searchFilter, filterValue, _ string) ([]*azuredevops.Identity, error) {
+			if org != "myorg" || searchFilter != azuredevops.IdentitySearchFilterMailAddress {
+				return nil, errors.Errorf("unexpected search %s %s", org, searchFilter)
+			}
+			if filterValue == "alice@example.com" {
+				return []*azuredevops.Identity{{ID: "i1", Descriptor: "d1"}}, nil
+			}
+			return nil, nil
+		},
+	})
+
+	t.Run("identity exists", func(t *testing.T) {
+		acct, err := p.FetchAccount(ctx, user, nil, []string{"alice@personal.example", "alice@example.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := extsvc.AccountSpec{
+			ServiceType: extsvc.TypeAzureDevOps,
+			ServiceID:   "https://dev.azure.com/",
+			AccountID:   "i1",
+		}
+		if diff := cmp.Diff(want, acct.AccountSpec); diff != "" {
+			t.Fatalf("AccountSpec mismatch (-want +got):\n%s", diff)
+		}
+		if acct.UserID != user.ID {
+			t.Fatalf("UserID: want %d, got %d", user.ID, acct.UserID)
+		}
+	})
+
+	t.Run("no such identity", func(t *testing.T) {
+		acct, err := p.FetchAccount(ctx, user, nil, []string{"alice@personal.example"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if acct != nil {
+			t.Fatalf("want no account, got %+v", acct)
+		}
+	})
+}
+
+func TestProvider_FetchUserPerms(t *testing.T) {
+	ctx := context.Background()
+
+	p := newTestProvider(&mockClient{
+		mockSearchIdentities: func(_ context.Context, _, searchFilter, filterValue, _ string) ([]*azuredevops.Identity, error) {
+			switch searchFilter {
+			case azuredevops.IdentitySearchFilterMailAddress:
+				return []*azuredevops.Identity{{ID: "i1", MemberOf: []string{"valid-backend", "valid-docs"}}}, nil
+			case azuredevops.IdentitySearchFilterGeneral:
+				switch filterValue {
+				case `[backend]\Project Valid Users`:
+					return []*azuredevops.Identity{{Descriptor: "valid-backend"}}, nil
+				case `[secret]\Project Valid Users`:
+					return []*azuredevops.Identity{{Descriptor: "valid-secret"}}, nil
+				}
+			}
+			return nil, errors.Errorf("unexpected search %s %s", searchFilter, filterValue)
+		},
+		mockListProjects: func(_ context.Context, _ string, opts azuredevops.ListOptions) ([]*azuredevops.Project, string, error) {
+			switch opts.ContinuationToken {
+			case "":
+				return []*azuredevops.Project{{ID: "p1", Name: "backend"}, {ID: "p2", Name: "docs", Visibility: azuredevops.ProjectVisibilityPublic}}, "next", nil
+			case "next":
+				return []*azuredevops.Project{{ID: "p3", Name: "secret"}}, "", nil
+			}
+			return nil, "", errors.Errorf("unexpected continuation token %q", opts.ContinuationToken)
+		},
+		mockListRepositories: func(_ context.Context, _, project string) ([]*azuredevops.Repository, error) {
+			if project != "p1" {
+				return nil, errors.Errorf("unexpected project %q", project)
+			}
+			return []*azuredevops.Repository{{ID: "r1"}, {ID: "r2"}}, nil
+		},
+	})
+
+	data, err := json.Marshal(accountData{Email: "alice@example.com", Identity: &azuredevops.Identity{ID: "i1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := &extsvc.Account{
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: extsvc.TypeAzureDevOps,
+			ServiceID:   "https://dev.azure.com/",
+			AccountID:   "i1",
+		},
+		AccountData: extsvc.AccountData{
+			Data: extsvc.NewUnencryptedData(data),
+		},
+	}
+
+	perms, err := p.FetchUserPerms(ctx, account, authz.FetchPermsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]extsvc.RepoID{"r1", "r2"}, perms.Exacts); diff != "" {
+		t.Fatalf("Exacts mismatch (-want +got):\n%s", diff)
+	}
+
+	t.Run("account of other code host", func(t *testing.T) {
+		other := *account
+		other.ServiceID = "https://azuredevops.example.com/"
+		if _, err := p.FetchUserPerms(ctx, &other, authz.FetchPermsOptions{}); err == nil {
+			t.Fatal("want error, got none")
+		}
+	})
+}
+
+func TestProvider_ValidateConnection(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		err      error
+		warnings []string
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:     "request fails",
+			err:      errors.New("boom"),
+			warnings: []string{`listing projects of organization "myorg": boom`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newTestProvider(&mockClient{
+				mockListProjects: func(context.Context, string, azuredevops.ListOptions) ([]*azuredevops.Project, string, error) {
+					return nil, "", tc.err
+				},
+			})
+
+			warnings := p.ValidateConnection(context.Background())
+			if diff := cmp.Diff(tc.warnings, warnings); diff != "" {
+				t.Fatalf("warnings mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
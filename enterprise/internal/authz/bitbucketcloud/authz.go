@@ -0,0 +1,81 @@
+package bitbucketcloud
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/oauthutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// NewAuthzProviders returns the set of Bitbucket Cloud authz providers derived from the connections.
+//
+// It also returns any simple validation problems with the config, separating these into "serious problems"
+// and "warnings". "Serious problems" are those that should make Sourcegraph set authz.allowAccessByDefault
+// to false. "Warnings" are all other validation problems.
+//
+// This constructor does not and should not directly check connectivity to external services - if
+// desired, callers should use `(*Provider).ValidateConnection` directly to get warnings related
+// to connection issues.
+func NewAuthzProviders(db database.DB, conns []*types.BitbucketCloudConnection, authProviders []schema.AuthProviders) (ps []authz.Provider, problems []string, warnings []string) {
+	// Auth providers (i.e. login mechanisms), keyed by their normalized URL.
+	bbcAuthProviders := make(map[string]*schema.BitbucketCloudAuthProvider)
+	for _, p := range authProviders {
+		if p.Bitbucketcloud == nil {
+			continue
+		}
+		rawURL := p.Bitbucketcloud.Url
+		if rawURL == "" {
+			rawURL = "https://bitbucket.org/"
+		}
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			// Error reporting for this happens in the auth provider.
+			continue
+		}
+		bbcAuthProviders[extsvc.NormalizeBaseURL(u).String()] = p.Bitbucketcloud
+	}
+
+	for _, c := range conns {
+		if c.Authorization == nil {
+			continue
+		}
+
+		baseURL, err := url.Parse(c.Url)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("Could not parse URL for Bitbucket Cloud connection %q: %s", c.Url, err))
+			continue
+		}
+		baseURL = extsvc.NormalizeBaseURL(baseURL)
+
+		// Permissions are synced with the OAuth tokens of users, so they
+		// require a corresponding Bitbucket Cloud auth provider.
+		authProvider, ok := bbcAuthProviders[baseURL.String()]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("Bitbucket Cloud config for %[1]s has `authorization` enabled, "+
+				"but no authentication provider matching %[1]q was found. "+
+				"Check the [**site configuration**](/site-admin/configuration) to "+
+				"verify an entry in [`auth.providers`](https://docs.sourcegraph.com/admin/auth) exists for %[1]s.",
+				c.Url))
+			continue
+		}
+
+		cli, err := bitbucketcloud.NewClient(c.URN, c.BitbucketCloudConnection, nil)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+
+		ps = append(ps, NewProvider(db, c.URN, baseURL, cli, &oauthutil.OAuthContext{
+			ClientID:     authProvider.ClientKey,
+			ClientSecret: authProvider.ClientSecret,
+			Endpoint:     bitbucketcloud.OAuthEndpoint(baseURL),
+		}))
+	}
+	return ps, problems, warnings
+}
@@ -0,0 +1,285 @@
+// Package bitbucketcloud contains an authorization provider for Bitbucket Cloud.
+package bitbucketcloud
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/oauthutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// client is the subset of the Bitbucket Cloud API client used by the Provider.
+type client interface {
+	CurrentUser(ctx context.Context) (*bitbucketcloud.User, error)
+	CurrentUserRepos(ctx context.Context, pageToken *bitbucketcloud.PageToken, role string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error)
+	CurrentUserWorkspacePermissions(ctx context.Context, pageToken *bitbucketcloud.PageToken) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error)
+	Repos(ctx context.Context, pageToken *bitbucketcloud.PageToken, accountName string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error)
+	WorkspacePermissions(ctx context.Context, pageToken *bitbucketcloud.PageToken, workspace string) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error)
+	RepoUserPermissions(ctx context.Context, pageToken *bitbucketcloud.PageToken, workspace, slug string) ([]*bitbucketcloud.RepoPermission, *bitbucketcloud.PageToken, error)
+	// WithAuthenticator returns a client which authenticates with the given
+	// authenticator.
+	WithAuthenticator(a auth.Authenticator) client
+}
+
+// clientAdapter adapts a bitbucketcloud.Client to the client interface.
+type clientAdapter struct {
+	bitbucketcloud.Client
+}
+
+func (c clientAdapter) WithAuthenticator(a auth.Authenticator) client {
+	return clientAdapter{c.Client.WithAuthenticator(a)}
+}
+
+// Provider is an implementation of AuthzProvider that provides repository
+// permissions as determined from Bitbucket Cloud.
+//
+// Permissions of a user are fetched with the OAuth token of their Bitbucket
+// Cloud account, which is linked to their Sourcegraph user when they sign in
+// through a Bitbucket Cloud auth provider. Permissions of a repository are
+// fetched with the app password of the connection, which must belong to an
+// administrator of the workspace of the repository.
+type Provider struct {
+	urn      string
+	client   client
+	codeHost *extsvc.CodeHost
+	pageLen  int // Page length to use in paginated requests.
+
+	// oauthCtx is the configuration of the OAuth consumer used to refresh
+	// expired tokens of user accounts.
+	oauthCtx oauthutil.OAuthContext
+	// tokenRefresher returns the function used to refresh the expired token of
+	// the given account and save it to the database.
+	tokenRefresher func(account *extsvc.Account, refreshToken string) oauthutil.TokenRefresher
+}
+
+var _ authz.Provider = (*Provider)(nil)
+
+// NewProvider returns a new Bitbucket Cloud authorization provider that uses
+// the given bitbucketcloud.Client, which must be authenticated with the app
+// password of the connection, and refreshes expired user tokens with the given
+// OAuth consumer configuration.
+func NewProvider(db database.DB, urn string, baseURL *url.URL, cli bitbucketcloud.Client, oauthCtx *oauthutil.OAuthContext) *Provider {
+	return &Provider{
+		urn:      urn,
+		client:   clientAdapter{cli},
+		codeHost: extsvc.NewCodeHost(baseURL, extsvc.TypeBitbucketCloud),
+		pageLen:  100,
+		oauthCtx: *oauthCtx,
+		tokenRefresher: func(account *extsvc.Account, refreshToken string) oauthutil.TokenRefresher {
+			return database.ExternalAccountTokenRefresher(db, account.ID, refreshToken)
+		},
+	}
+}
+
+func (p *Provider) URN() string {
+	return p.urn
+}
+
+// ServiceID returns the absolute URL that identifies the Bitbucket Cloud
+// instance this provider is configured with.
+func (p *Provider) ServiceID() string { return p.codeHost.ServiceID }
+
+// ServiceType returns the type of this Provider, namely, "bitbucketCloud".
+func (p *Provider) ServiceType() string { return p.codeHost.ServiceType }
+
+// ValidateConnection validates that the Provider has access to the Bitbucket
+// Cloud API with the app password of the connection.
+func (p *Provider) ValidateConnection(ctx context.Context) []string {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := p.client.CurrentUser(ctx); err != nil {
+		return []string{err.Error()}
+	}
+	return nil
+}
+
+// FetchAccount always returns nil, because Bitbucket Cloud accounts are linked
+// to Sourcegraph users when they sign in through a Bitbucket Cloud auth
+// provider.
+func (p *Provider) FetchAccount(context.Context, *types.User, []*extsvc.Account, []string) (*extsvc.Account, error) {
+	return nil, nil
+}
+
+// FetchUserPerms returns a list of repository IDs (on code host) that the given account
+// has read access on the code host. The repository ID has the same value as it would be
+// used as api.ExternalRepoSpec.ID. The returned list only includes private repository IDs.
+//
+// Besides the repositories the user has been granted access to, directly or
+// through groups, this includes all repositories of the workspaces the user
+// is an owner of.
+//
+// This method may return partial but valid results in case of error, and it is up to
+// callers to decide whether to discard.
+func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account, opts authz.FetchPermsOptions) (*authz.ExternalUserPermissions, error) {
+	switch {
+	case account == nil:
+		return nil, errors.New("no account provided")
+	case !extsvc.IsHostOfAccount(p.codeHost, account):
+		return nil, errors.Errorf("not a code host of the account: want %q but have %q",
+			p.codeHost.ServiceID, account.AccountSpec.ServiceID)
+	}
+
+	_, tok, err := bitbucketcloud.GetExternalAccountData(ctx, &account.AccountData)
+	if err != nil {
+		return nil, errors.Wrap(err, "get external account data")
+	} else if tok == nil {
+		return nil, errors.New("no token found in the external account data")
+	}
+
+	// Bitbucket Cloud access tokens expire after two hours, so we usually
+	// have to refresh the token first.
+	accessToken := tok.AccessToken
+	if !tok.Valid() && tok.RefreshToken != "" {
+		accessToken, err = p.tokenRefresher(account, tok.RefreshToken)(ctx, httpcli.ExternalDoer, p.oauthCtx)
+		if err != nil {
+			return nil, errors.Wrap(err, "refresh token")
+		}
+	}
+
+	cli := p.client.WithAuthenticator(&auth.OAuthBearerToken{Token: accessToken})
+	perms := &authz.ExternalUserPermissions{}
+	seen := make(map[string]struct{})
+	addRepo := func(r *bitbucketcloud.Repo) {
+		if _, ok := seen[r.UUID]; ok || !r.IsPrivate {
+			return
+		}
+		seen[r.UUID] = struct{}{}
+		perms.Exacts = append(perms.Exacts, extsvc.RepoID(r.UUID))
+	}
+
+	err = p.forEachPage(func(page *bitbucketcloud.PageToken) (*bitbucketcloud.PageToken, error) {
+		repos, next, err := cli.CurrentUserRepos(ctx, page, "member")
+		for _, r := range repos {
+			addRepo(r)
+		}
+		return next, err
+	})
+	if err != nil {
+		return perms, errors.Wrap(err, "list repositories of user")
+	}
+
+	// Owners of a workspace have access to all of its repositories.
+	var ownedWorkspaces []string
+	err = p.forEachPage(func(page *bitbucketcloud.PageToken) (*bitbucketcloud.PageToken, error) {
+		wsPerms, next, err := cli.CurrentUserWorkspacePermissions(ctx, page)
+		for _, wp := range wsPerms {
+			if wp.Permission == bitbucketcloud.PermissionOwner && wp.Workspace != nil {
+				ownedWorkspaces = append(ownedWorkspaces, wp.Workspace.Slug)
+			}
+		}
+		return next, err
+	})
+	if err != nil {
+		return perms, errors.Wrap(err, "list workspaces of user")
+	}
+
+	for _, ws := range ownedWorkspaces {
+		err = p.forEachPage(func(page *bitbucketcloud.PageToken) (*bitbucketcloud.PageToken, error) {
+			repos, next, err := cli.Repos(ctx, page, ws)
+			for _, r := range repos {
+				addRepo(r)
+			}
+			return next, err
+		})
+		if err != nil {
+			return perms, errors.Wrapf(err, "list repositories of workspace %q", ws)
+		}
+	}
+
+	return perms, nil
+}
+
+// FetchRepoPerms returns a list of user IDs (on code host) who have read access to
+// the given repository on the code host. The user ID has the same value as it would
+// be used as extsvc.Account.AccountID. The returned list includes users who have been
+// granted access directly or through groups, and the owners of the workspace.
+//
+// This method may return partial but valid results in case of error, and it is up to
+// callers to decide whether to discard.
+func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository, opts authz.FetchPermsOptions) ([]extsvc.AccountID, error) {
+	if repo == nil {
+		return nil, errors.New("no repository provided")
+	} else if !extsvc.IsHostOfRepo(p.codeHost, &repo.ExternalRepoSpec) {
+		return nil, errors.Errorf("not a code host of the repository: want %q but have %q",
+			repo.ServiceID, p.codeHost.ServiceID)
+	}
+
+	// NOTE: We do not store port or scheme in our URI, so stripping the hostname alone is enough.
+	fullName := strings.TrimPrefix(repo.URI, p.codeHost.BaseURL.Hostname())
+	fullName = strings.TrimPrefix(fullName, "/")
+	workspace, slug, ok := strings.Cut(fullName, "/")
+	if !ok {
+		return nil, errors.Errorf("invalid repository name %q", repo.URI)
+	}
+
+	var accountIDs []extsvc.AccountID
+	seen := make(map[string]struct{})
+	addUser := func(u *bitbucketcloud.Account) {
+		if u == nil {
+			return
+		}
+		if _, ok := seen[u.UUID]; ok {
+			return
+		}
+		seen[u.UUID] = struct{}{}
+		accountIDs = append(accountIDs, extsvc.AccountID(u.UUID))
+	}
+
+	err := p.forEachPage(func(page *bitbucketcloud.PageToken) (*bitbucketcloud.PageToken, error) {
+		repoPerms, next, err := p.client.RepoUserPermissions(ctx, page, workspace, slug)
+		for _, rp := range repoPerms {
+			addUser(rp.User)
+		}
+		return next, err
+	})
+	if err != nil {
+		return accountIDs, errors.Wrap(err, "list users of repository")
+	}
+
+	err = p.forEachPage(func(page *bitbucketcloud.PageToken) (*bitbucketcloud.PageToken, error) {
+		wsPerms, next, err := p.client.WorkspacePermissions(ctx, page, workspace)
+		for _, wp := range wsPerms {
+			if wp.Permission == bitbucketcloud.PermissionOwner {
+				addUser(wp.User)
+			}
+		}
+		return next, err
+	})
+	if err != nil {
+		return accountIDs, errors.Wrap(err, "list members of workspace")
+	}
+
+	return accountIDs, nil
+}
+
+// FetchUserPermsByToken is not implemented for Bitbucket Cloud.
+func (p *Provider) FetchUserPermsByToken(ctx context.Context, token string, opts authz.FetchPermsOptions) (*authz.ExternalUserPermissions, error) {
+	return nil, &authz.ErrUnimplemented{Feature: "bitbucketcloud.FetchUserPermsByToken"}
+}
+
+// forEachPage calls fetch with successive page tokens until there are no
+// more pages or fetch returns an error.
+func (p *Provider) forEachPage(fetch func(page *bitbucketcloud.PageToken) (*bitbucketcloud.PageToken, error)) error {
+	page := &bitbucketcloud.PageToken{Pagelen: p.pageLen}
+	for {
+		next, err := fetch(page)
+		if err != nil {
+			return err
+		}
+		if !next.HasMore() {
+			return nil
+		}
+		page = next
+	}
+}
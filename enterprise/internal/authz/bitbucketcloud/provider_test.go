@@ -0,0 +1,305 @@
+package bitbucketcloud
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/oauth2"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/oauthutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type mockClient struct {
+	token string
+
+	mockCurrentUser                     func(ctx context.Context) (*bitbucketcloud.User, error)
+	mockCurrentUserRepos                func(ctx context.Context, token string, pageToken *bitbucketcloud.PageToken, role string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error)
+	mockCurrentUserWorkspacePermissions func(ctx context.Context, token string, pageToken *bitbucketcloud.PageToken) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error)
+	mockRepos                           func(ctx context.Context, token string, pageToken *bitbucketcloud.PageToken, accountName string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error)
+	mockWorkspacePermissions            func(ctx context.Context, pageToken *bitbucketcloud.PageToken, workspace string) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error)
+	mockRepoUserPermissions             func(ctx context.Context, pageToken *bitbucketcloud.PageToken, workspace, slug string) ([]*bitbucketcloud.RepoPermission, *bitbucketcloud.PageToken, error)
+}
+
+func (m *mockClient) CurrentUser(ctx context.Context) (*bitbucketcloud.User, error) {
+	return m.mockCurrentUser(ctx)
+}
+
+func (m *mockClient) CurrentUserRepos(ctx context.Context, pageToken *bitbucketcloud.PageToken, role string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error) {
+	return m.mockCurrentUserRepos(ctx, m.token, pageToken, role)
+}
+
+func (m *mockClient) CurrentUserWorkspacePermissions(ctx context.Context, pageToken *bitbucketcloud.PageToken) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error) {
+	return m.mockCurrentUserWorkspacePermissions(ctx, m.token, pageToken)
+}
+
+func (m *mockClient) Repos(ctx context.Context, pageToken *bitbucketcloud.PageToken, accountName string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error) {
+	return m.mockRepos(ctx, m.token, pageToken, accountName)
+}
+
+func (m *mockClient) WorkspacePermissions(ctx context.Context, pageToken *bitbucketcloud.PageToken, workspace string) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error) {
+	return m.mockWorkspacePermissions(ctx, pageToken, workspace)
+}
+
+func (m *mockClient) RepoUserPermissions(ctx context.Context, pageToken *bitbucketcloud.PageToken, workspace, slug string) ([]*bitbucketcloud.RepoPermission, *bitbucketcloud.PageToken, error) {
+	return m.mockRepoUserPermissions(ctx, pageToken, workspace, slug)
+}
+
+func (m *mockClient) WithAuthenticator(a auth.Authenticator) client {
+	mm := *m
+	mm.token = a.(*auth.OAuthBearerToken).Token
+	return &mm
+}
+
+func newTestProvider(cli client) *Provider {
+	baseURL, _ := url.Parse("https://bitbucket.org/")
+	return &Provider{
+		urn:      "extsvc:bitbucketCloud:1",
+		client:   cli,
+		codeHost: extsvc.NewCodeHost(baseURL, extsvc.TypeBitbucketCloud),
+		pageLen:  2,
+		tokenRefresher: func(*extsvc.Account, string) oauthutil.TokenRefresher {
+			return func(context.Context, httpcli.Doer, oauthutil.OAuthContext) (string, error) {
+				return "", errors.New("unexpected token refresh")
+			}
+		},
+	}
+}
+
+func newTestAccount(t *testing.T, tok *oauth2.Token) *extsvc.Account {
+	t.Helper()
+
+	var data extsvc.AccountData
+	if err := bitbucketcloud.SetExternalAccountData(&data, &bitbucketcloud.User{Account: bitbucketcloud.Account{UUID: "{alice}"}}, tok); err != nil {
+		t.Fatal(err)
+	}
+	return &extsvc.Account{
+		ID: 1,
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: extsvc.TypeBitbucketCloud,
+			ServiceID:   "https://bitbucket.org/",
+			AccountID:   "{alice}",
+		},
+		AccountData: data,
+	}
+}
+
+// paginate returns the page of items requested by the given page token, along
+// with the token of the next page. Page tokens of subsequent pages carry the
+// page number in their Next URL.
+func paginate[T any](pageToken *bitbucketcloud.PageToken, items []T) ([]T, *bitbucketcloud.PageToken) {
+	page := 1
+	if pageToken.HasMore() {
+		page = int(pageToken.Next[0] - '0')
+	}
+	start, end := (page-1)*pageToken.Pagelen, page*pageToken.Pagelen
+	if end >= len(items) {
+		return items[start:], &bitbucketcloud.PageToken{Page: page, Pagelen: pageToken.Pagelen}
+	}
+	return items[start:end], &bitbucketcloud.PageToken{Page: page, Pagelen: pageToken.Pagelen, Next: string(rune('0' + page + 1))}
+}
+
+func TestProvider_FetchUserPerms(t *testing.T) {
+	ctx := context.Background()
+
+	var tokens []string
+	cli := &mockClient{
+		mockCurrentUserRepos: func(_ context.Context, token string, pageToken *bitbucketcloud.PageToken, role string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error) {
+			tokens = append(tokens, token)
+			if role != "member" {
+				return nil, nil, errors.Errorf("unexpected role %q", role)
+			}
+			repos, next := paginate(pageToken, []*bitbucketcloud.Repo{
+				{UUID: "{1}", IsPrivate: true},
+				{UUID: "{2}"},
+				{UUID: "{3}", IsPrivate: true},
+			})
+			return repos, next, nil
+		},
+		mockCurrentUserWorkspacePermissions: func(_ context.Context, token string, pageToken *bitbucketcloud.PageToken) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error) {
+			tokens = append(tokens, token)
+			perms, next := paginate(pageToken, []*bitbucketcloud.WorkspacePermission{
+				{Permission: bitbucketcloud.PermissionMember, Workspace: &bitbucketcloud.Workspace{Slug: "member-ws"}},
+				{Permission: bitbucketcloud.PermissionOwner, Workspace: &bitbucketcloud.Workspace{Slug: "owned-ws"}},
+			})
+			return perms, next, nil
+		},
+		mockRepos: func(_ context.Context, token string, pageToken *bitbucketcloud.PageToken, accountName string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error) {
+			tokens = append(tokens, token)
+			if accountName != "owned-ws" {
+				return nil, nil, errors.Errorf("unexpected workspace %q", accountName)
+			}
+			repos, next := paginate(pageToken, []*bitbucketcloud.Repo{
+				{UUID: "{3}", IsPrivate: true},
+				{UUID: "{4}", IsPrivate: true},
+			})
+			return repos, next, nil
+		},
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		tokens = nil
+		p := newTestProvider(cli)
+		account := newTestAccount(t, &oauth2.Token{AccessToken: "access-token"})
+
+		perms, err := p.FetchUserPerms(ctx, account, authz.FetchPermsOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if diff := cmp.Diff([]extsvc.RepoID{"{1}", "{3}", "{4}"}, perms.Exacts); diff != "" {
+			t.Fatalf("Exacts mismatch (-want +got):\n%s", diff)
+		}
+		for _, token := range tokens {
+			if token != "access-token" {
+				t.Fatalf("unexpected token %q", token)
+			}
+		}
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		tokens = nil
+		p := newTestProvider(cli)
+		p.oauthCtx = oauthutil.OAuthContext{ClientID: "client-key"}
+		var refreshed bool
+		p.tokenRefresher = func(account *extsvc.Account, refreshToken string) oauthutil.TokenRefresher {
+			return func(_ context.Context, _ httpcli.Doer, oauthCtx oauthutil.OAuthContext) (string, error) {
+				if account.ID != 1 || refreshToken != "refresh-token" || oauthCtx.ClientID != "client-key" {
+					return "", errors.New("unexpected refresh")
+				}
+				refreshed = true
+				return "refreshed-token", nil
+			}
+		}
+		account := newTestAccount(t, &oauth2.Token{
+			AccessToken:  "access-token",
+			RefreshToken: "refresh-token",
+			Expiry:       time.Now().Add(-time.Hour),
+		})
+
+		if _, err := p.FetchUserPerms(ctx, account, authz.FetchPermsOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if !refreshed {
+			t.Fatal("token was not refreshed")
+		}
+		for _, token := range tokens {
+			if token != "refreshed-token" {
+				t.Fatalf("unexpected token %q", token)
+			}
+		}
+	})
+
+	t.Run("account of other code host", func(t *testing.T) {
+		p := newTestProvider(cli)
+		account := newTestAccount(t, &oauth2.Token{AccessToken: "access-token"})
+		account.ServiceID = "https://bitbucket.example.com/"
+		if _, err := p.FetchUserPerms(ctx, account, authz.FetchPermsOptions{}); err == nil {
+			t.Fatal("want error, got none")
+		}
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		p := newTestProvider(cli)
+		account := newTestAccount(t, nil)
+		account.AuthData = nil
+		if _, err := p.FetchUserPerms(ctx, account, authz.FetchPermsOptions{}); err == nil {
+			t.Fatal("want error, got none")
+		}
+	})
+}
+
+func TestProvider_FetchRepoPerms(t *testing.T) {
+	ctx := context.Background()
+
+	p := newTestProvider(&mockClient{
+		mockRepoUserPermissions: func(_ context.Context, pageToken *bitbucketcloud.PageToken, workspace, slug string) ([]*bitbucketcloud.RepoPermission, *bitbucketcloud.PageToken, error) {
+			if workspace != "ws" || slug != "repo" {
+				return nil, nil, errors.Errorf("unexpected repository %s/%s", workspace, slug)
+			}
+			perms, next := paginate(pageToken, []*bitbucketcloud.RepoPermission{
+				{Permission: bitbucketcloud.PermissionRead, User: &bitbucketcloud.Account{UUID: "{alice}"}},
+				{Permission: bitbucketcloud.PermissionWrite, User: &bitbucketcloud.Account{UUID: "{bob}"}},
+				{Permission: bitbucketcloud.PermissionAdmin, User: &bitbucketcloud.Account{UUID: "{carol}"}},
+			})
+			return perms, next, nil
+		},
+		mockWorkspacePermissions: func(_ context.Context, pageToken *bitbucketcloud.PageToken, workspace string) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error) {
+			if workspace != "ws" {
+				return nil, nil, errors.Errorf("unexpected workspace %q", workspace)
+			}
+			perms, next := paginate(pageToken, []*bitbucketcloud.WorkspacePermission{
+				{Permission: bitbucketcloud.PermissionOwner, User: &bitbucketcloud.Account{UUID: "{carol}"}},
+				{Permission: bitbucketcloud.PermissionMember, User: &bitbucketcloud.Account{UUID: "{dave}"}},
+				{Permission: bitbucketcloud.PermissionOwner, User: &bitbucketcloud.Account{UUID: "{erin}"}},
+			})
+			return perms, next, nil
+		},
+	})
+
+	repo := &extsvc.Repository{
+		URI: "bitbucket.org/ws/repo",
+		ExternalRepoSpec: api.ExternalRepoSpec{
+			ID:          "{repo}",
+			ServiceType: extsvc.TypeBitbucketCloud,
+			ServiceID:   "https://bitbucket.org/",
+		},
+	}
+
+	accountIDs, err := p.FetchRepoPerms(ctx, repo, authz.FetchPermsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []extsvc.AccountID{"{alice}", "{bob}", "{carol}", "{erin}"}
+	if diff := cmp.Diff(want, accountIDs); diff != "" {
+		t.Fatalf("account IDs mismatch (-want +got):\n%s", diff)
+	}
+
+	t.Run("repository of other code host", func(t *testing.T) {
+		other := *repo
+		other.ServiceID = "https://bitbucket.example.com/"
+		if _, err := p.FetchRepoPerms(ctx, &other, authz.FetchPermsOptions{}); err == nil {
+			t.Fatal("want error, got none")
+		}
+	})
+}
+
+func TestProvider_ValidateConnection(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		err      error
+		warnings []string
+	}{
+		{
+			name: "valid credentials",
+		},
+		{
+			name:     "request fails",
+			err:      errors.New("boom"),
+			warnings: []string{"boom"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newTestProvider(&mockClient{
+				mockCurrentUser: func(context.Context) (*bitbucketcloud.User, error) {
+					return &bitbucketcloud.User{}, tc.err
+				},
+			})
+
+			warnings := p.ValidateConnection(context.Background())
+			if diff := cmp.Diff(tc.warnings, warnings); diff != "" {
+				t.Fatalf("warnings mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package gitea
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// NewAuthzProviders returns the set of Gitea authz providers derived from the connections.
+//
+// It also returns any simple validation problems with the config, separating these into "serious problems"
+// and "warnings". "Serious problems" are those that should make Sourcegraph set authz.allowAccessByDefault
+// to false. "Warnings" are all other validation problems.
+//
+// This constructor does not and should not directly check connectivity to external services - if
+// desired, callers should use `(*Provider).ValidateConnection` directly to get warnings related
+// to connection issues.
+func NewAuthzProviders(conns []*types.GiteaConnection) (ps []authz.Provider, problems []string, warnings []string) {
+	for _, c := range conns {
+		if c.Authorization == nil {
+			continue
+		}
+
+		cli, err := gitea.NewClient(c.URN, c.GiteaConnection, nil)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		ps = append(ps, NewProvider(c.URN, cli))
+	}
+	return ps, problems, warnings
+}
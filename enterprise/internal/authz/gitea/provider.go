@@ -0,0 +1,175 @@
+// Package gitea contains an authorization provider for Gitea.
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// client is the subset of the Gitea API client used by the Provider.
+type client interface {
+	GetAuthenticatedUser(ctx context.Context) (*gitea.User, error)
+	GetUser(ctx context.Context, username string) (*gitea.User, error)
+	ListAccessibleRepos(ctx context.Context, opts gitea.ListOptions) ([]*gitea.Repository, bool, error)
+	// WithSudo returns a client which impersonates the given user.
+	WithSudo(username string) client
+}
+
+// clientAdapter adapts a *gitea.Client to the client interface.
+type clientAdapter struct {
+	*gitea.Client
+}
+
+func (c clientAdapter) WithSudo(username string) client {
+	return clientAdapter{c.Client.WithSudo(username)}
+}
+
+// Provider is an implementation of AuthzProvider that provides repository
+// permissions as determined from a Gitea instance API. It assumes usernames of
+// Sourcegraph accounts match 1-1 with usernames of Gitea users, and fetches
+// the permissions of a user by impersonating them with the site admin token of
+// the connection.
+type Provider struct {
+	urn      string
+	client   client
+	codeHost *extsvc.CodeHost
+	pageSize int // Page size to use in paginated requests.
+}
+
+var _ authz.Provider = (*Provider)(nil)
+
+// NewProvider returns a new Gitea authorization provider that uses the given
+// gitea.Client, which must be authenticated as a site admin.
+func NewProvider(urn string, cli *gitea.Client) *Provider {
+	return &Provider{
+		urn:      urn,
+		client:   clientAdapter{cli},
+		codeHost: extsvc.NewCodeHost(cli.URL, extsvc.TypeGitea),
+		pageSize: 50,
+	}
+}
+
+func (p *Provider) URN() string {
+	return p.urn
+}
+
+// ServiceID returns the absolute URL that identifies the Gitea instance this
+// provider is configured with.
+func (p *Provider) ServiceID() string { return p.codeHost.ServiceID }
+
+// ServiceType returns the type of this Provider, namely, "gitea".
+func (p *Provider) ServiceType() string { return p.codeHost.ServiceType }
+
+// ValidateConnection validates that the Provider has access to the Gitea API
+// as a site admin, which is required to impersonate other users.
+func (p *Provider) ValidateConnection(ctx context.Context) []string {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	user, err := p.client.GetAuthenticatedUser(ctx)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if !user.IsAdmin {
+		return []string{"Gitea token must belong to a site admin to enforce permissions"}
+	}
+	return nil
+}
+
+// FetchAccount returns the Gitea account of the user with the same username as
+// the given Sourcegraph user, or nil if there is none.
+func (p *Provider) FetchAccount(ctx context.Context, user *types.User, _ []*extsvc.Account, _ []string) (*extsvc.Account, error) {
+	if user == nil {
+		return nil, nil
+	}
+
+	giteaUser, err := p.client.GetUser(ctx, user.Username)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	accountData, err := json.Marshal(giteaUser)
+	if err != nil {
+		return nil, err
+	}
+
+	return &extsvc.Account{
+		UserID: user.ID,
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: p.codeHost.ServiceType,
+			ServiceID:   p.codeHost.ServiceID,
+			AccountID:   strconv.FormatInt(giteaUser.ID, 10),
+		},
+		AccountData: extsvc.AccountData{
+			Data: extsvc.NewUnencryptedData(accountData),
+		},
+	}, nil
+}
+
+// FetchUserPerms returns a list of repository IDs (on code host) that the given account
+// has read access on the code host. The repository ID has the same value as it would be
+// used as api.ExternalRepoSpec.ID. The returned list only includes private repository IDs.
+//
+// This method may return partial but valid results in case of error, and it is up to
+// callers to decide whether to discard.
+func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account, opts authz.FetchPermsOptions) (*authz.ExternalUserPermissions, error) {
+	switch {
+	case account == nil:
+		return nil, errors.New("no account provided")
+	case account.Data == nil:
+		return nil, errors.New("no account data provided")
+	case !extsvc.IsHostOfAccount(p.codeHost, account):
+		return nil, errors.Errorf("not a code host of the account: want %q but have %q",
+			p.codeHost.ServiceID, account.AccountSpec.ServiceID)
+	}
+
+	var user gitea.User
+	if err := encryption.DecryptJSON(ctx, account.Data, &user); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling account data")
+	}
+
+	cli := p.client.WithSudo(user.Login)
+	perms := &authz.ExternalUserPermissions{}
+	listOpts := gitea.ListOptions{Page: 1, Limit: p.pageSize}
+	for {
+		repos, hasNextPage, err := cli.ListAccessibleRepos(ctx, listOpts)
+		if err != nil {
+			return perms, err
+		}
+
+		for _, r := range repos {
+			if r.Private {
+				perms.Exacts = append(perms.Exacts, extsvc.RepoID(strconv.FormatInt(r.ID, 10)))
+			}
+		}
+
+		if !hasNextPage {
+			return perms, nil
+		}
+		listOpts.Page++
+	}
+}
+
+// FetchRepoPerms is not implemented for Gitea, since permissions are fetched
+// per user.
+func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository, opts authz.FetchPermsOptions) ([]extsvc.AccountID, error) {
+	return nil, &authz.ErrUnimplemented{Feature: "gitea.FetchRepoPerms"}
+}
+
+// FetchUserPermsByToken is not implemented for Gitea.
+func (p *Provider) FetchUserPermsByToken(ctx context.Context, token string, opts authz.FetchPermsOptions) (*authz.ExternalUserPermissions, error) {
+	return nil, &authz.ErrUnimplemented{Feature: "gitea.FetchUserPermsByToken"}
+}
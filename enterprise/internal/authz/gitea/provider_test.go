@@ -0,0 +1,193 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type mockClient struct {
+	sudo string
+
+	mockGetAuthenticatedUser func(ctx context.Context) (*gitea.User, error)
+	mockGetUser              func(ctx context.Context, username string) (*gitea.User, error)
+	mockListAccessibleRepos  func(ctx context.Context, sudo string, opts gitea.ListOptions) ([]*gitea.Repository, bool, error)
+}
+
+func (m *mockClient) GetAuthenticatedUser(ctx context.Context) (*gitea.User, error) {
+	return m.mockGetAuthenticatedUser(ctx)
+}
+
+func (m *mockClient) GetUser(ctx context.Context, username string) (*gitea.User, error) {
+	return m.mockGetUser(ctx, username)
+}
+
+func (m *mockClient) ListAccessibleRepos(ctx context.Context, opts gitea.ListOptions) ([]*gitea.Repository, bool, error) {
+	return m.mockListAccessibleRepos(ctx, m.sudo, opts)
+}
+
+func (m *mockClient) WithSudo(username string) client {
+	mm := *m
+	mm.sudo = username
+	return &mm
+}
+
+type notFoundError struct{}
+
+func (notFoundError) Error() string  { return "not found" }
+func (notFoundError) NotFound() bool { return true }
+
+func newTestProvider(cli client) *Provider {
+	baseURL, _ := url.Parse("https://gitea.example.com/")
+	return &Provider{
+		urn:      "extsvc:gitea:1",
+		client:   cli,
+		codeHost: extsvc.NewCodeHost(baseURL, extsvc.TypeGitea),
+		pageSize: 2,
+	}
+}
+
+func TestProvider_FetchAccount(t *testing.T) {
+	ctx := context.Background()
+	user := &types.User{ID: 42, Username: "alice"}
+
+	t.Run("user exists", func(t *testing.T) {
+		p := newTestProvider(&mockClient{
+			mockGetUser: func(_ context.Context, username string) (*gitea.User, error) {
+				return &gitea.User{ID: 7, Login: username}, nil
+			},
+		})
+
+		acct, err := p.FetchAccount(ctx, user, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := extsvc.AccountSpec{
+			ServiceType: extsvc.TypeGitea,
+			ServiceID:   "https://gitea.example.com/",
+			AccountID:   "7",
+		}
+		if diff := cmp.Diff(want, acct.AccountSpec); diff != "" {
+			t.Fatalf("AccountSpec mismatch (-want +got):\n%s", diff)
+		}
+		if acct.UserID != user.ID {
+			t.Fatalf("UserID: want %d, got %d", user.ID, acct.UserID)
+		}
+	})
+
+	t.Run("no such user", func(t *testing.T) {
+		p := newTestProvider(&mockClient{
+			mockGetUser: func(context.Context, string) (*gitea.User, error) {
+				return nil, errors.WithStack(notFoundError{})
+			},
+		})
+
+		acct, err := p.FetchAccount(ctx, user, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if acct != nil {
+			t.Fatalf("want no account, got %+v", acct)
+		}
+	})
+}
+
+func TestProvider_FetchUserPerms(t *testing.T) {
+	ctx := context.Background()
+
+	var sudos []string
+	p := newTestProvider(&mockClient{
+		mockListAccessibleRepos: func(_ context.Context, sudo string, opts gitea.ListOptions) ([]*gitea.Repository, bool, error) {
+			sudos = append(sudos, sudo)
+			switch opts.Page {
+			case 1:
+				return []*gitea.Repository{{ID: 1, Private: true}, {ID: 2}}, true, nil
+			case 2:
+				return []*gitea.Repository{{ID: 3, Private: true}}, false, nil
+			}
+			return nil, false, errors.Errorf("unexpected page %d", opts.Page)
+		},
+	})
+
+	data, err := json.Marshal(gitea.User{ID: 7, Login: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := &extsvc.Account{
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: extsvc.TypeGitea,
+			ServiceID:   "https://gitea.example.com/",
+			AccountID:   "7",
+		},
+		AccountData: extsvc.AccountData{
+			Data: extsvc.NewUnencryptedData(data),
+		},
+	}
+
+	perms, err := p.FetchUserPerms(ctx, account, authz.FetchPermsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]extsvc.RepoID{"1", "3"}, perms.Exacts); diff != "" {
+		t.Fatalf("Exacts mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"alice", "alice"}, sudos); diff != "" {
+		t.Fatalf("impersonated users mismatch (-want +got):\n%s", diff)
+	}
+
+	t.Run("account of other code host", func(t *testing.T) {
+		other := *account
+		other.ServiceID = "https://gitea.com/"
+		if _, err := p.FetchUserPerms(ctx, &other, authz.FetchPermsOptions{}); err == nil {
+			t.Fatal("want error, got none")
+		}
+	})
+}
+
+func TestProvider_ValidateConnection(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		user     *gitea.User
+		err      error
+		warnings []string
+	}{
+		{
+			name: "site admin",
+			user: &gitea.User{Login: "admin", IsAdmin: true},
+		},
+		{
+			name:     "not a site admin",
+			user:     &gitea.User{Login: "alice"},
+			warnings: []string{"Gitea token must belong to a site admin to enforce permissions"},
+		},
+		{
+			name:     "request fails",
+			err:      errors.New("boom"),
+			warnings: []string{"boom"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newTestProvider(&mockClient{
+				mockGetAuthenticatedUser: func(context.Context) (*gitea.User, error) {
+					return tc.user, tc.err
+				},
+			})
+
+			warnings := p.ValidateConnection(context.Background())
+			if diff := cmp.Diff(tc.warnings, warnings); diff != "" {
+				t.Fatalf("warnings mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api/internalapi"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/repos"
 	"github.com/sourcegraph/sourcegraph/internal/types"
@@ -192,10 +193,23 @@ func (e *executor) pushChangesetPatch(ctx context.Context) (err error) {
 	if err != nil {
 		return err
 	}
+	if e.ch.ExternalServiceType == extsvc.TypeGerrit {
+		// Gerrit creates a change, or a new patch set of an existing change,
+		// for commits pushed to a magic ref. The change is identified by the
+		// Change-Id trailer of the commit message.
+		opts.CommitInfo.Message = sources.GerritCommitMessage(opts.CommitInfo.Message, sources.GerritChangeID(e.ch))
+		opts.PushRef = sources.GerritPushRef(e.spec.BaseRef, e.spec.HeadRef)
+	}
 
 	err = e.pushCommit(ctx, opts)
 	var pce pushCommitError
 	if errors.As(err, &pce) {
+		// Commits are created deterministically from the changeset spec, so
+		// pushing the same spec to Gerrit again is rejected because the
+		// change already has this patch set.
+		if e.ch.ExternalServiceType == extsvc.TypeGerrit && strings.Contains(pce.CombinedOutput, "no new changes") {
+			return nil
+		}
 		if acss, ok := css.(sources.ArchivableChangesetSource); ok {
 			if acss.IsArchivedPushError(pce.CombinedOutput) {
 				if err := e.handleArchivedRepo(ctx); err != nil {
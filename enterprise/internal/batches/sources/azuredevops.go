@@ -0,0 +1,289 @@
+package sources
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	adocs "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type AzureDevOpsSource struct {
+	client *azuredevops.Client
+	// org is the organization used to validate authenticators.
+	org string
+}
+
+var (
+	_ ChangesetSource      = AzureDevOpsSource{}
+	_ DraftChangesetSource = AzureDevOpsSource{}
+)
+
+func NewAzureDevOpsSource(ctx context.Context, svc *types.ExternalService, cf *httpcli.Factory) (*AzureDevOpsSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.AzureDevOpsConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d", svc.ID)
+	}
+
+	if cf == nil {
+		cf = httpcli.ExternalClientFactory
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, errors.Wrap(err, "creating external client")
+	}
+
+	client, err := azuredevops.NewClient(svc.URN(), &c, cli)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating Azure DevOps client")
+	}
+
+	var org string
+	if len(c.Orgs) > 0 {
+		org = c.Orgs[0]
+	} else if len(c.Projects) > 0 {
+		org, _, _ = strings.Cut(c.Projects[0], "/")
+	}
+
+	return &AzureDevOpsSource{client: client, org: org}, nil
+}
+
+// GitserverPushConfig returns an authenticated push config used for pushing
+// commits to the code host.
+func (s AzureDevOpsSource) GitserverPushConfig(ctx context.Context, store database.ExternalServiceStore, repo *types.Repo) (*protocol.PushConfig, error) {
+	return GitserverPushConfig(ctx, store, repo, s.client.Auth)
+}
+
+// WithAuthenticator returns a copy of the original Source configured to use the
+// given authenticator, provided that authenticator type is supported by the
+// code host.
+func (s AzureDevOpsSource) WithAuthenticator(a auth.Authenticator) (ChangesetSource, error) {
+	switch a.(type) {
+	case *auth.BasicAuth,
+		*auth.BasicAuthWithSSH:
+		break
+
+	default:
+		return nil, newUnsupportedAuthenticatorError("AzureDevOpsSource", a)
+	}
+
+	return &AzureDevOpsSource{client: s.client.WithAuthenticator(a), org: s.org}, nil
+}
+
+// ValidateAuthenticator validates the currently set authenticator is usable.
+// Returns an error, when validating the Authenticator yielded an error.
+func (s AzureDevOpsSource) ValidateAuthenticator(ctx context.Context) error {
+	if s.org == "" {
+		return errors.New("no organization configured to validate the credential against")
+	}
+	_, err := s.client.GetConnectionData(ctx, s.org)
+	return err
+}
+
+// LoadChangeset loads the given Changeset from the source and updates it. If
+// the Changeset could not be found on the source, a ChangesetNotFoundError is
+// returned.
+func (s AzureDevOpsSource) LoadChangeset(ctx context.Context, cs *Changeset) error {
+	repo := cs.TargetRepo.Metadata.(*azuredevops.Repository)
+	id, err := strconv.ParseInt(cs.ExternalID, 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "converting external ID %q", cs.ExternalID)
+	}
+
+	pr, err := s.client.GetPullRequest(ctx, repo, id)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return ChangesetNotFoundError{Changeset: cs}
+		}
+		return errors.Wrap(err, "getting pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, pr, cs)
+}
+
+// CreateChangeset will create the Changeset on the source. If it already
+// exists, *Changeset will be populated and the return value will be true.
+func (s AzureDevOpsSource) CreateChangeset(ctx context.Context, cs *Changeset) (bool, error) {
+	return s.createChangeset(ctx, cs, false)
+}
+
+// CreateDraftChangeset creates the given changeset on the code host in draft
+// mode.
+func (s AzureDevOpsSource) CreateDraftChangeset(ctx context.Context, cs *Changeset) (bool, error) {
+	return s.createChangeset(ctx, cs, true)
+}
+
+func (s AzureDevOpsSource) createChangeset(ctx context.Context, cs *Changeset, draft bool) (bool, error) {
+	repo := cs.TargetRepo.Metadata.(*azuredevops.Repository)
+
+	exists := false
+	pr, err := s.client.CreatePullRequest(ctx, repo, azuredevops.CreatePullRequestInput{
+		SourceRefName: cs.HeadRef,
+		TargetRefName: cs.BaseRef,
+		Title:         cs.Title,
+		Description:   cs.Body,
+		IsDraft:       draft,
+	})
+	if err != nil {
+		if !azuredevops.IsConflict(err) {
+			return false, errors.Wrap(err, "creating pull request")
+		}
+
+		// Azure DevOps refuses to create a second active pull request for the
+		// same branches, so we look up the existing one instead.
+		prs, err := s.client.ListPullRequests(ctx, repo, azuredevops.PullRequestSearchCriteria{
+			SourceRefName: cs.HeadRef,
+			TargetRefName: cs.BaseRef,
+			Status:        azuredevops.PullRequestStatusActive,
+		})
+		if err != nil {
+			return false, errors.Wrap(err, "fetching existing pull request")
+		}
+		if len(prs) == 0 {
+			return false, errors.Newf("no active pull request from %q into %q found", cs.HeadRef, cs.BaseRef)
+		}
+		pr = prs[0]
+		exists = true
+	}
+
+	if err := s.setChangesetMetadata(ctx, repo, pr, cs); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// UndraftChangeset will update the Changeset on the source to be not in draft
+// mode anymore.
+func (s AzureDevOpsSource) UndraftChangeset(ctx context.Context, cs *Changeset) error {
+	isDraft := false
+	return s.updatePullRequest(ctx, cs, azuredevops.UpdatePullRequestInput{IsDraft: &isDraft})
+}
+
+// CloseChangeset will close the Changeset on the source, where "close"
+// means the appropriate final state on the codehost (e.g. "declined" on
+// Bitbucket Server).
+func (s AzureDevOpsSource) CloseChangeset(ctx context.Context, cs *Changeset) error {
+	return s.updatePullRequest(ctx, cs, azuredevops.UpdatePullRequestInput{Status: azuredevops.PullRequestStatusAbandoned})
+}
+
+// UpdateChangeset can update Changesets.
+func (s AzureDevOpsSource) UpdateChangeset(ctx context.Context, cs *Changeset) error {
+	pr := cs.Metadata.(*adocs.AnnotatedPullRequest)
+
+	input := azuredevops.UpdatePullRequestInput{
+		Title:       &cs.Title,
+		Description: &cs.Body,
+	}
+	// Azure DevOps rejects updates which set the target branch to the current
+	// one.
+	if pr.TargetRefName != cs.BaseRef {
+		input.TargetRefName = cs.BaseRef
+	}
+	return s.updatePullRequest(ctx, cs, input)
+}
+
+// ReopenChangeset will reopen the Changeset on the source, if it's closed.
+// If not, it's a noop.
+func (s AzureDevOpsSource) ReopenChangeset(ctx context.Context, cs *Changeset) error {
+	return s.updatePullRequest(ctx, cs, azuredevops.UpdatePullRequestInput{Status: azuredevops.PullRequestStatusActive})
+}
+
+// CreateComment posts a comment on the Changeset.
+func (s AzureDevOpsSource) CreateComment(ctx context.Context, cs *Changeset, comment string) error {
+	repo := cs.TargetRepo.Metadata.(*azuredevops.Repository)
+	pr := cs.Metadata.(*adocs.AnnotatedPullRequest)
+
+	return s.client.CreatePullRequestComment(ctx, repo, pr.PullRequestID, comment)
+}
+
+// MergeChangeset merges a Changeset on the code host, if in a mergeable state.
+// If squash is true, and the code host supports squash merges, the source
+// must attempt a squash merge. Otherwise, it is expected to perform a regular
+// merge. If the changeset cannot be merged, because it is in an unmergeable
+// state, ChangesetNotMergeableError must be returned.
+func (s AzureDevOpsSource) MergeChangeset(ctx context.Context, cs *Changeset, squash bool) error {
+	repo := cs.TargetRepo.Metadata.(*azuredevops.Repository)
+	pr := cs.Metadata.(*adocs.AnnotatedPullRequest)
+
+	switch pr.MergeStatus {
+	case azuredevops.MergeStatusConflicts, azuredevops.MergeStatusRejectedByPolicy, azuredevops.MergeStatusFailure:
+		return ChangesetNotMergeableError{ErrorMsg: "pull request merge status is " + string(pr.MergeStatus)}
+	}
+
+	strategy := azuredevops.MergeStrategyNoFastForward
+	if squash {
+		strategy = azuredevops.MergeStrategySquash
+	}
+
+	// Pull requests are merged by completing them, which requires the commit
+	// we expect to be merged.
+	updated, err := s.client.UpdatePullRequest(ctx, repo, pr.PullRequestID, azuredevops.UpdatePullRequestInput{
+		Status:                azuredevops.PullRequestStatusCompleted,
+		LastMergeSourceCommit: pr.LastMergeSourceCommit,
+		CompletionOptions:     &azuredevops.CompletionOptions{MergeStrategy: strategy},
+	})
+	if err != nil {
+		if azuredevops.IsConflict(err) {
+			return ChangesetNotMergeableError{ErrorMsg: err.Error()}
+		}
+		return errors.Wrap(err, "merging pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, updated, cs)
+}
+
+func (s AzureDevOpsSource) updatePullRequest(ctx context.Context, cs *Changeset, input azuredevops.UpdatePullRequestInput) error {
+	repo := cs.TargetRepo.Metadata.(*azuredevops.Repository)
+	pr := cs.Metadata.(*adocs.AnnotatedPullRequest)
+
+	updated, err := s.client.UpdatePullRequest(ctx, repo, pr.PullRequestID, input)
+	if err != nil {
+		return errors.Wrap(err, "updating pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, updated, cs)
+}
+
+func (s AzureDevOpsSource) annotatePullRequest(ctx context.Context, repo *azuredevops.Repository, pr *azuredevops.PullRequest) (*adocs.AnnotatedPullRequest, error) {
+	statuses, err := s.client.ListPullRequestStatuses(ctx, repo, pr.PullRequestID)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing pull request statuses")
+	}
+	if statuses == nil {
+		statuses = []*azuredevops.Status{}
+	}
+
+	return &adocs.AnnotatedPullRequest{
+		PullRequest: pr,
+		Statuses:    statuses,
+		WebURL:      repo.WebURL + "/pullrequest/" + strconv.FormatInt(pr.PullRequestID, 10),
+	}, nil
+}
+
+func (s AzureDevOpsSource) setChangesetMetadata(ctx context.Context, repo *azuredevops.Repository, pr *azuredevops.PullRequest, cs *Changeset) error {
+	apr, err := s.annotatePullRequest(ctx, repo, pr)
+	if err != nil {
+		return errors.Wrap(err, "annotating pull request")
+	}
+
+	if err := cs.SetMetadata(apr); err != nil {
+		return errors.Wrap(err, "setting changeset metadata")
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package azuredevops
+
+import (
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+)
+
+// AnnotatedPullRequest adds metadata we need that lives outside the main
+// PullRequest type returned by the Azure DevOps API alongside the pull
+// request. This type is used as the primary metadata type for Azure DevOps
+// changesets.
+type AnnotatedPullRequest struct {
+	*azuredevops.PullRequest
+	Statuses []*azuredevops.Status `json:"statuses"`
+	// WebURL is the URL of the pull request in the web interface, which isn't
+	// returned by the API.
+	WebURL string `json:"webURL"`
+}
+
+// UpdatedAt returns the last time the pull request or one of its statuses
+// changed, as far as the API tells us. Azure DevOps doesn't return when a pull
+// request was last updated, so this is an approximation.
+func (pr *AnnotatedPullRequest) UpdatedAt() time.Time {
+	t := pr.CreationDate
+	if pr.ClosedDate.After(t) {
+		t = pr.ClosedDate
+	}
+	for _, s := range pr.Statuses {
+		if s.UpdatedDate.After(t) {
+			t = s.UpdatedDate
+		}
+	}
+	return t
+}
@@ -0,0 +1,294 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	adocs "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/azuredevops"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestNewAzureDevOpsSource(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		for name, input := range map[string]string{
+			"invalid JSON":   "invalid JSON",
+			"invalid schema": `{"token": ["not a string"]}`,
+			"bad URL":        `{"url": "http://[::1]:namedport"}`,
+		} {
+			t.Run(name, func(t *testing.T) {
+				ctx := context.Background()
+				s, err := NewAzureDevOpsSource(ctx, &types.ExternalService{
+					Config: extsvc.NewUnencryptedConfig(input),
+				}, nil)
+				assert.Nil(t, s)
+				assert.NotNil(t, err)
+			})
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		ctx := context.Background()
+		s, err := NewAzureDevOpsSource(ctx, &types.ExternalService{
+			Config: extsvc.NewUnencryptedConfig(`{"url": "https://dev.azure.com", "username": "admin", "token": "secret", "projects": ["org/project"]}`),
+		}, nil)
+		assert.NotNil(t, s)
+		assert.Nil(t, err)
+		assert.Equal(t, "org", s.org)
+	})
+}
+
+func TestAzureDevOpsSource_WithAuthenticator(t *testing.T) {
+	s, _ := newFakeAzureDevOpsSource(t)
+
+	t.Run("supported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"BasicAuth":        &auth.BasicAuth{Username: "user", Password: "token"},
+			"BasicAuthWithSSH": &auth.BasicAuthWithSSH{BasicAuth: auth.BasicAuth{Username: "user", Password: "token"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				src, err := s.WithAuthenticator(tc)
+				assert.Nil(t, err)
+				assert.Same(t, tc, src.(*AzureDevOpsSource).client.Auth)
+			})
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"nil":              nil,
+			"OAuthBearerToken": &auth.OAuthBearerToken{},
+		} {
+			t.Run(name, func(t *testing.T) {
+				src, err := s.WithAuthenticator(tc)
+				assert.Nil(t, src)
+				assert.ErrorAs(t, err, &UnsupportedAuthenticatorError{})
+			})
+		}
+	})
+}
+
+func TestAzureDevOpsSource_LoadChangeset(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		s, _ := newFakeAzureDevOpsSource(t)
+		cs := azureDevOpsTestChangeset("404")
+
+		err := s.LoadChangeset(ctx, cs)
+		target := ChangesetNotFoundError{}
+		assert.ErrorAs(t, err, &target)
+		assert.Same(t, target.Changeset, cs)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		s, _ := newFakeAzureDevOpsSource(t)
+		cs := azureDevOpsTestChangeset("1")
+
+		assert.Nil(t, s.LoadChangeset(ctx, cs))
+
+		apr := cs.Metadata.(*adocs.AnnotatedPullRequest)
+		assert.EqualValues(t, 1, apr.PullRequestID)
+		assert.Len(t, apr.Statuses, 1)
+		assert.Equal(t, "https://dev.azure.com/org/project/_git/repo/pullrequest/1", apr.WebURL)
+		assert.Equal(t, "1", cs.ExternalID)
+		assert.Equal(t, extsvc.TypeAzureDevOps, cs.ExternalServiceType)
+		assert.Equal(t, "refs/heads/branch", cs.ExternalBranch)
+	})
+}
+
+func TestAzureDevOpsSource_CreateChangeset(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("new pull request", func(t *testing.T) {
+		s, fake := newFakeAzureDevOpsSource(t)
+		cs := azureDevOpsTestChangeset("")
+
+		exists, err := s.CreateChangeset(ctx, cs)
+		assert.Nil(t, err)
+		assert.False(t, exists)
+		assert.Equal(t, "1", cs.ExternalID)
+		assert.Equal(t, "refs/heads/branch", fake.created.SourceRefName)
+		assert.Equal(t, "refs/heads/main", fake.created.TargetRefName)
+		assert.False(t, fake.created.IsDraft)
+	})
+
+	t.Run("draft pull request", func(t *testing.T) {
+		s, fake := newFakeAzureDevOpsSource(t)
+		cs := azureDevOpsTestChangeset("")
+
+		_, err := s.CreateDraftChangeset(ctx, cs)
+		assert.Nil(t, err)
+		assert.True(t, fake.created.IsDraft)
+	})
+
+	t.Run("pull request already exists", func(t *testing.T) {
+		s, fake := newFakeAzureDevOpsSource(t)
+		fake.conflict = true
+		cs := azureDevOpsTestChangeset("")
+
+		exists, err := s.CreateChangeset(ctx, cs)
+		assert.Nil(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, "1", cs.ExternalID)
+	})
+}
+
+func TestAzureDevOpsSource_CloseChangeset(t *testing.T) {
+	ctx := context.Background()
+	s, fake := newFakeAzureDevOpsSource(t)
+	cs := azureDevOpsTestChangeset("1")
+	assert.Nil(t, s.LoadChangeset(ctx, cs))
+
+	assert.Nil(t, s.CloseChangeset(ctx, cs))
+	assert.Equal(t, azuredevops.PullRequestStatusAbandoned, fake.updated.Status)
+	assert.Equal(t, azuredevops.PullRequestStatusAbandoned, cs.Metadata.(*adocs.AnnotatedPullRequest).Status)
+}
+
+func TestAzureDevOpsSource_MergeChangeset(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("conflicts", func(t *testing.T) {
+		s, fake := newFakeAzureDevOpsSource(t)
+		fake.mergeStatus = azuredevops.MergeStatusConflicts
+		cs := azureDevOpsTestChangeset("1")
+		assert.Nil(t, s.LoadChangeset(ctx, cs))
+
+		err := s.MergeChangeset(ctx, cs, false)
+		assert.ErrorAs(t, err, &ChangesetNotMergeableError{})
+	})
+
+	t.Run("success", func(t *testing.T) {
+		s, fake := newFakeAzureDevOpsSource(t)
+		cs := azureDevOpsTestChangeset("1")
+		assert.Nil(t, s.LoadChangeset(ctx, cs))
+
+		assert.Nil(t, s.MergeChangeset(ctx, cs, true))
+		assert.Equal(t, azuredevops.PullRequestStatusCompleted, fake.updated.Status)
+		assert.Equal(t, azuredevops.MergeStrategySquash, fake.updated.CompletionOptions.MergeStrategy)
+		assert.Equal(t, "head-sha", fake.updated.LastMergeSourceCommit.CommitID)
+		assert.Equal(t, azuredevops.PullRequestStatusCompleted, cs.Metadata.(*adocs.AnnotatedPullRequest).Status)
+	})
+}
+
+// fakeAzureDevOps implements just enough of the Azure DevOps API to exercise
+// AzureDevOpsSource.
+type fakeAzureDevOps struct {
+	conflict    bool
+	mergeStatus azuredevops.MergeStatus
+
+	created azuredevops.CreatePullRequestInput
+	updated azuredevops.UpdatePullRequestInput
+}
+
+func newFakeAzureDevOpsSource(t *testing.T) (*AzureDevOpsSource, *fakeAzureDevOps) {
+	t.Helper()
+
+	fake := &fakeAzureDevOps{}
+	srv := httptest.NewServer(fake.handler())
+	t.Cleanup(srv.Close)
+
+	client, err := azuredevops.NewClient("Test", &schema.AzureDevOpsConnection{Url: srv.URL, Username: "admin", Token: "secret"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &AzureDevOpsSource{client: client, org: "org"}, fake
+}
+
+func (f *fakeAzureDevOps) handler() http.Handler {
+	respond := func(w http.ResponseWriter, status int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+
+	const prefix = "/org/project-id/_apis/git/repositories/repo-id/pullrequests"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(prefix, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			if f.conflict {
+				respond(w, http.StatusConflict, map[string]string{"message": "an active pull request already exists"})
+				return
+			}
+			if err := json.NewDecoder(r.Body).Decode(&f.created); err != nil {
+				respond(w, http.StatusBadRequest, nil)
+				return
+			}
+			respond(w, http.StatusCreated, f.pullRequest())
+		default:
+			respond(w, http.StatusOK, map[string]any{"count": 1, "value": []*azuredevops.PullRequest{f.pullRequest()}})
+		}
+	})
+	mux.HandleFunc(prefix+"/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			if err := json.NewDecoder(r.Body).Decode(&f.updated); err != nil {
+				respond(w, http.StatusBadRequest, nil)
+				return
+			}
+		}
+		respond(w, http.StatusOK, f.pullRequest())
+	})
+	mux.HandleFunc(prefix+"/1/statuses", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, map[string]any{"count": 1, "value": []*azuredevops.Status{
+			{ID: 1, State: azuredevops.StatusStateSucceeded, Context: azuredevops.StatusContext{Genre: "ci", Name: "build"}},
+		}})
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusNotFound, map[string]string{"message": "not found"})
+	})
+	return mux
+}
+
+func (f *fakeAzureDevOps) pullRequest() *azuredevops.PullRequest {
+	pr := &azuredevops.PullRequest{
+		PullRequestID:         1,
+		Repository:            azureDevOpsTestRepo(),
+		Status:                azuredevops.PullRequestStatusActive,
+		CreatedBy:             azuredevops.IdentityRef{DisplayName: "Author", UniqueName: "author@example.com"},
+		Title:                 "title",
+		SourceRefName:         "refs/heads/branch",
+		TargetRefName:         "refs/heads/main",
+		MergeStatus:           f.mergeStatus,
+		LastMergeSourceCommit: &azuredevops.CommitRef{CommitID: "head-sha"},
+		LastMergeTargetCommit: &azuredevops.CommitRef{CommitID: "base-sha"},
+	}
+	if f.updated.Status != "" {
+		pr.Status = f.updated.Status
+	}
+	return pr
+}
+
+func azureDevOpsTestRepo() *azuredevops.Repository {
+	return &azuredevops.Repository{
+		ID:      "repo-id",
+		Name:    "repo",
+		Project: azuredevops.Project{ID: "project-id", Name: "project"},
+		WebURL:  "https://dev.azure.com/org/project/_git/repo",
+		Org:     "org",
+	}
+}
+
+func azureDevOpsTestChangeset(externalID string) *Changeset {
+	repo := &types.Repo{Metadata: azureDevOpsTestRepo()}
+	cs := &Changeset{
+		Title:      "title",
+		Body:       "body",
+		HeadRef:    "refs/heads/branch",
+		BaseRef:    "refs/heads/main",
+		TargetRepo: repo,
+		RemoteRepo: repo,
+	}
+	cs.Changeset = &btypes.Changeset{ExternalID: externalID}
+	return cs
+}
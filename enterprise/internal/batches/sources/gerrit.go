@@ -0,0 +1,272 @@
+package sources
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"strings"
+
+	gerritbatches "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/gerrit"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// GerritSource is the ChangesetSource of Gerrit. Gerrit has no pull requests:
+// a change is created, or a new patch set uploaded to it, by pushing a commit
+// to refs/for/<branch>, and the Change-Id trailer of the commit message
+// identifies the change. The reconciler takes care of this with
+// GerritCommitMessage and GerritPushRef, so the ChangesetSource only has to look
+// up the change afterwards.
+type GerritSource struct {
+	client *gerrit.Client
+}
+
+var (
+	_ ChangesetSource      = GerritSource{}
+	_ DraftChangesetSource = GerritSource{}
+)
+
+func NewGerritSource(ctx context.Context, svc *types.ExternalService, cf *httpcli.Factory) (*GerritSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.GerritConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d", svc.ID)
+	}
+
+	if cf == nil {
+		cf = httpcli.ExternalClientFactory
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, errors.Wrap(err, "creating external client")
+	}
+
+	client, err := gerrit.NewClient(svc.URN(), &c, cli)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating Gerrit client")
+	}
+
+	return &GerritSource{client: client}, nil
+}
+
+// GerritChangeID returns the Change-Id of the Gerrit change of the given
+// changeset. It's derived from the changeset ID until the change has been
+// created, so that every patch set pushed for the changeset updates the same
+// change.
+func GerritChangeID(c *btypes.Changeset) string {
+	if c.ExternalID != "" {
+		return c.ExternalID
+	}
+	sum := sha1.Sum([]byte("sourcegraph-changeset:" + strconv.FormatInt(c.ID, 10)))
+	return "I" + hex.EncodeToString(sum[:])
+}
+
+// GerritPushRef returns the magic ref that commits for changes into the given
+// base ref are pushed to. Gerrit changes don't have a head branch, so the head
+// ref is recorded as the topic of the change instead.
+func GerritPushRef(baseRef, headRef string) string {
+	return "refs/for/" + gitdomain.AbbreviateRef(baseRef) + "%topic=" + gitdomain.AbbreviateRef(headRef)
+}
+
+// GerritCommitMessage returns the given commit message with a Change-Id
+// trailer, unless it already has one.
+func GerritCommitMessage(message, changeID string) string {
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, "Change-Id:") {
+			return message
+		}
+	}
+	return strings.TrimRight(message, "\n") + "\n\nChange-Id: " + changeID + "\n"
+}
+
+// GitserverPushConfig returns an authenticated push config used for pushing
+// commits to the code host.
+func (s GerritSource) GitserverPushConfig(ctx context.Context, store database.ExternalServiceStore, repo *types.Repo) (*protocol.PushConfig, error) {
+	return GitserverPushConfig(ctx, store, repo, s.client.Auth)
+}
+
+// WithAuthenticator returns a copy of the original Source configured to use the
+// given authenticator, provided that authenticator type is supported by the
+// code host.
+func (s GerritSource) WithAuthenticator(a auth.Authenticator) (ChangesetSource, error) {
+	switch a.(type) {
+	case *auth.BasicAuth,
+		*auth.BasicAuthWithSSH:
+		break
+
+	default:
+		return nil, newUnsupportedAuthenticatorError("GerritSource", a)
+	}
+
+	return &GerritSource{client: s.client.WithAuthenticator(a)}, nil
+}
+
+// ValidateAuthenticator validates the currently set authenticator is usable.
+// Returns an error, when validating the Authenticator yielded an error.
+func (s GerritSource) ValidateAuthenticator(ctx context.Context) error {
+	_, err := s.client.GetAuthenticatedUserAccount(ctx)
+	return err
+}
+
+// LoadChangeset loads the given Changeset from the source and updates it. If
+// the Changeset could not be found on the source, a ChangesetNotFoundError is
+// returned.
+func (s GerritSource) LoadChangeset(ctx context.Context, cs *Changeset) error {
+	change, err := s.client.GetChange(ctx, gerritProject(cs.TargetRepo), cs.ExternalID)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return ChangesetNotFoundError{Changeset: cs}
+		}
+		return errors.Wrap(err, "getting change")
+	}
+
+	return s.setChangesetMetadata(change, cs)
+}
+
+// CreateChangeset will create the Changeset on the source. If it already
+// exists, *Changeset will be populated and the return value will be true.
+//
+// The change was already created when its commit was pushed, so this only
+// loads it.
+func (s GerritSource) CreateChangeset(ctx context.Context, cs *Changeset) (bool, error) {
+	change, err := s.client.GetChange(ctx, gerritProject(cs.TargetRepo), GerritChangeID(cs.Changeset))
+	if err != nil {
+		return false, errors.Wrap(err, "getting pushed change")
+	}
+
+	if err := s.setChangesetMetadata(change, cs); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// CreateDraftChangeset creates the given changeset on the code host in draft
+// mode, which is called work in progress by Gerrit.
+func (s GerritSource) CreateDraftChangeset(ctx context.Context, cs *Changeset) (bool, error) {
+	exists, err := s.CreateChangeset(ctx, cs)
+	if err != nil {
+		return exists, err
+	}
+
+	change := cs.Metadata.(*gerritbatches.AnnotatedChange).Change
+	if change.WorkInProgress {
+		return exists, nil
+	}
+	if err := s.client.SetWorkInProgress(ctx, change); err != nil {
+		return exists, errors.Wrap(err, "marking change as work in progress")
+	}
+	return exists, s.reloadChangeset(ctx, cs)
+}
+
+// UndraftChangeset will update the Changeset on the source to be not in draft
+// mode anymore.
+func (s GerritSource) UndraftChangeset(ctx context.Context, cs *Changeset) error {
+	change := cs.Metadata.(*gerritbatches.AnnotatedChange).Change
+	if err := s.client.SetReadyForReview(ctx, change); err != nil {
+		return errors.Wrap(err, "marking change as ready for review")
+	}
+	return s.reloadChangeset(ctx, cs)
+}
+
+// CloseChangeset will close the Changeset on the source, where "close"
+// means the appropriate final state on the codehost (e.g. "abandoned" on
+// Gerrit).
+func (s GerritSource) CloseChangeset(ctx context.Context, cs *Changeset) error {
+	change := cs.Metadata.(*gerritbatches.AnnotatedChange).Change
+	if err := s.client.AbandonChange(ctx, change); err != nil {
+		return errors.Wrap(err, "abandoning change")
+	}
+	return s.reloadChangeset(ctx, cs)
+}
+
+// UpdateChangeset can update Changesets.
+//
+// The title and body of a change are its commit message, which is updated
+// by pushing a new patch set, so there is nothing left to update.
+func (s GerritSource) UpdateChangeset(ctx context.Context, cs *Changeset) error {
+	return s.reloadChangeset(ctx, cs)
+}
+
+// ReopenChangeset will reopen the Changeset on the source, if it's closed.
+// If not, it's a noop.
+func (s GerritSource) ReopenChangeset(ctx context.Context, cs *Changeset) error {
+	change := cs.Metadata.(*gerritbatches.AnnotatedChange).Change
+	if change.Status != gerrit.ChangeStatusAbandoned {
+		return nil
+	}
+	if err := s.client.RestoreChange(ctx, change); err != nil {
+		return errors.Wrap(err, "restoring change")
+	}
+	return s.reloadChangeset(ctx, cs)
+}
+
+// CreateComment posts a comment on the Changeset.
+func (s GerritSource) CreateComment(ctx context.Context, cs *Changeset, comment string) error {
+	change := cs.Metadata.(*gerritbatches.AnnotatedChange).Change
+	return s.client.PostReviewComment(ctx, change, comment)
+}
+
+// MergeChangeset merges a Changeset on the code host, if in a mergeable state.
+// Gerrit decides how a change is merged by the submit type of the project, so
+// squash is ignored. If the changeset cannot be merged, because it is in an
+// unmergeable state, ChangesetNotMergeableError is returned.
+func (s GerritSource) MergeChangeset(ctx context.Context, cs *Changeset, squash bool) error {
+	change := cs.Metadata.(*gerritbatches.AnnotatedChange).Change
+	if err := s.client.SubmitChange(ctx, change); err != nil {
+		if gerrit.IsConflict(err) {
+			return ChangesetNotMergeableError{ErrorMsg: err.Error()}
+		}
+		return errors.Wrap(err, "submitting change")
+	}
+	return s.reloadChangeset(ctx, cs)
+}
+
+// reloadChangeset loads the current state of the change, since most
+// endpoints that modify changes don't return them.
+func (s GerritSource) reloadChangeset(ctx context.Context, cs *Changeset) error {
+	change := cs.Metadata.(*gerritbatches.AnnotatedChange).Change
+	updated, err := s.client.GetChange(ctx, change.Project, change.ChangeID)
+	if err != nil {
+		return errors.Wrap(err, "getting change")
+	}
+	return s.setChangesetMetadata(updated, cs)
+}
+
+func (s GerritSource) setChangesetMetadata(change *gerrit.Change, cs *Changeset) error {
+	if err := cs.SetMetadata(&gerritbatches.AnnotatedChange{
+		Change:      change,
+		CodeHostURL: s.client.URL.String(),
+	}); err != nil {
+		return errors.Wrap(err, "setting changeset metadata")
+	}
+	return nil
+}
+
+// gerritProject returns the name of the Gerrit project of the given
+// repository. The ID of Gerrit projects is their URL encoded name.
+func gerritProject(repo *types.Repo) string {
+	project := repo.Metadata.(*gerrit.Project)
+	if project.Name != "" {
+		return project.Name
+	}
+	if name, err := url.PathUnescape(project.ID); err == nil {
+		return name
+	}
+	return project.ID
+}
@@ -0,0 +1,57 @@
+package gerrit
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+)
+
+// AnnotatedChange adds metadata we need that lives outside the main Change
+// type returned by the Gerrit API alongside the change. This type is used as
+// the primary metadata type for Gerrit changesets.
+type AnnotatedChange struct {
+	*gerrit.Change
+	// CodeHostURL is the base URL of the Gerrit instance, which is needed to
+	// build the URL of the change.
+	CodeHostURL string `json:"codeHostURL"`
+}
+
+// URL returns the URL of the change in the Gerrit web UI.
+func (c *AnnotatedChange) URL() string {
+	return strings.TrimSuffix(c.CodeHostURL, "/") + "/c/" + c.Project + "/+/" + strconv.FormatInt(c.Number, 10)
+}
+
+// HeadRef returns the ref the change was pushed from. Gerrit changes have no
+// head branch, so batch changes record it as the topic of the change; other
+// changes fall back to the ref of their current patch set.
+func (c *AnnotatedChange) HeadRef() string {
+	if c.Topic != "" {
+		return gitdomain.EnsureRefPrefix(c.Topic)
+	}
+	if rev := c.CurrentRevisionInfo(); rev != nil {
+		return rev.Ref
+	}
+	return ""
+}
+
+// Body returns the commit message of the current patch set without its
+// subject line, which is the description of the change.
+func (c *AnnotatedChange) Body() string {
+	rev := c.CurrentRevisionInfo()
+	if rev == nil || rev.Commit == nil {
+		return ""
+	}
+	_, body, _ := strings.Cut(rev.Commit.Message, "\n")
+	return strings.TrimSpace(body)
+}
+
+// BaseRefOid returns the parent commit of the current patch set.
+func (c *AnnotatedChange) BaseRefOid() string {
+	rev := c.CurrentRevisionInfo()
+	if rev == nil || rev.Commit == nil || len(rev.Commit.Parents) == 0 {
+		return ""
+	}
+	return rev.Commit.Parents[0].Commit
+}
@@ -0,0 +1,322 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	gerritbatches "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/gerrit"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestNewGerritSource(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		for name, input := range map[string]string{
+			"invalid JSON":   "invalid JSON",
+			"invalid schema": `{"username": ["not a string"]}`,
+			"bad URL":        `{"url": "http://[::1]:namedport"}`,
+		} {
+			t.Run(name, func(t *testing.T) {
+				ctx := context.Background()
+				s, err := NewGerritSource(ctx, &types.ExternalService{
+					Config: extsvc.NewUnencryptedConfig(input),
+				}, nil)
+				assert.Nil(t, s)
+				assert.NotNil(t, err)
+			})
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		ctx := context.Background()
+		s, err := NewGerritSource(ctx, &types.ExternalService{
+			Config: extsvc.NewUnencryptedConfig(`{"url": "https://gerrit.example.com/", "username": "admin", "password": "secret"}`),
+		}, nil)
+		assert.NotNil(t, s)
+		assert.Nil(t, err)
+	})
+}
+
+func TestGerritSource_WithAuthenticator(t *testing.T) {
+	s, _ := newFakeGerritSource(t)
+
+	t.Run("supported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"BasicAuth":        &auth.BasicAuth{Username: "user", Password: "pass"},
+			"BasicAuthWithSSH": &auth.BasicAuthWithSSH{BasicAuth: auth.BasicAuth{Username: "user", Password: "pass"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				src, err := s.WithAuthenticator(tc)
+				assert.Nil(t, err)
+				assert.Same(t, tc, src.(*GerritSource).client.Auth)
+			})
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"nil":              nil,
+			"OAuthBearerToken": &auth.OAuthBearerToken{},
+		} {
+			t.Run(name, func(t *testing.T) {
+				src, err := s.WithAuthenticator(tc)
+				assert.Nil(t, src)
+				assert.ErrorAs(t, err, &UnsupportedAuthenticatorError{})
+			})
+		}
+	})
+}
+
+func TestGerritSource_LoadChangeset(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		s, _ := newFakeGerritSource(t)
+		cs := gerritTestChangeset("Iunknown")
+
+		err := s.LoadChangeset(ctx, cs)
+		target := ChangesetNotFoundError{}
+		assert.ErrorAs(t, err, &target)
+		assert.Same(t, target.Changeset, cs)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		s, fake := newFakeGerritSource(t)
+		cs := gerritTestChangeset(fake.changeID)
+
+		assert.Nil(t, s.LoadChangeset(ctx, cs))
+		assert.Equal(t, `project:"org/repo" change:`+fake.changeID, fake.query)
+
+		change := cs.Metadata.(*gerritbatches.AnnotatedChange)
+		assert.EqualValues(t, 7, change.Number)
+		assert.Equal(t, fake.changeID, cs.ExternalID)
+		assert.Equal(t, extsvc.TypeGerrit, cs.ExternalServiceType)
+		assert.Equal(t, "refs/heads/branch", cs.ExternalBranch)
+
+		url, err := cs.URL()
+		assert.Nil(t, err)
+		assert.Equal(t, strings.TrimSuffix(fake.url, "/")+"/c/org/repo/+/7", url)
+
+		body, err := cs.Changeset.Body()
+		assert.Nil(t, err)
+		assert.Equal(t, "body\n\nChange-Id: "+fake.changeID, body)
+	})
+}
+
+func TestGerritSource_CreateChangeset(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pushed change", func(t *testing.T) {
+		s, fake := newFakeGerritSource(t)
+		cs := gerritTestChangeset("")
+		fake.changeID = GerritChangeID(cs.Changeset)
+
+		exists, err := s.CreateChangeset(ctx, cs)
+		assert.Nil(t, err)
+		assert.False(t, exists)
+		assert.Equal(t, fake.changeID, cs.ExternalID)
+		assert.Empty(t, fake.posted)
+	})
+
+	t.Run("draft", func(t *testing.T) {
+		s, fake := newFakeGerritSource(t)
+		cs := gerritTestChangeset("")
+		fake.changeID = GerritChangeID(cs.Changeset)
+
+		_, err := s.CreateDraftChangeset(ctx, cs)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"/a/changes/org%2Frepo~7/wip"}, fake.posted)
+		assert.True(t, cs.Metadata.(*gerritbatches.AnnotatedChange).WorkInProgress)
+	})
+
+	t.Run("change wasn't pushed", func(t *testing.T) {
+		s, _ := newFakeGerritSource(t)
+		cs := gerritTestChangeset("")
+
+		_, err := s.CreateChangeset(ctx, cs)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestGerritSource_CloseChangeset(t *testing.T) {
+	ctx := context.Background()
+	s, fake := newFakeGerritSource(t)
+	cs := gerritTestChangeset(fake.changeID)
+	assert.Nil(t, s.LoadChangeset(ctx, cs))
+
+	assert.Nil(t, s.CloseChangeset(ctx, cs))
+	assert.Equal(t, []string{"/a/changes/org%2Frepo~7/abandon"}, fake.posted)
+	assert.Equal(t, gerrit.ChangeStatusAbandoned, cs.Metadata.(*gerritbatches.AnnotatedChange).Status)
+
+	assert.Nil(t, s.ReopenChangeset(ctx, cs))
+	assert.Equal(t, gerrit.ChangeStatusNew, cs.Metadata.(*gerritbatches.AnnotatedChange).Status)
+}
+
+func TestGerritSource_MergeChangeset(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("conflict", func(t *testing.T) {
+		s, fake := newFakeGerritSource(t)
+		fake.conflict = true
+		cs := gerritTestChangeset(fake.changeID)
+		assert.Nil(t, s.LoadChangeset(ctx, cs))
+
+		err := s.MergeChangeset(ctx, cs, false)
+		assert.ErrorAs(t, err, &ChangesetNotMergeableError{})
+	})
+
+	t.Run("success", func(t *testing.T) {
+		s, fake := newFakeGerritSource(t)
+		cs := gerritTestChangeset(fake.changeID)
+		assert.Nil(t, s.LoadChangeset(ctx, cs))
+
+		assert.Nil(t, s.MergeChangeset(ctx, cs, true))
+		assert.Equal(t, gerrit.ChangeStatusMerged, cs.Metadata.(*gerritbatches.AnnotatedChange).Status)
+	})
+}
+
+func TestGerritCommitMessage(t *testing.T) {
+	assert.Equal(t, "title\n\nbody\n\nChange-Id: I123\n", GerritCommitMessage("title\n\nbody\n", "I123"))
+	assert.Equal(t, "title\n\nChange-Id: I456\n", GerritCommitMessage("title\n\nChange-Id: I456\n", "I123"))
+}
+
+func TestGerritPushRef(t *testing.T) {
+	assert.Equal(t, "refs/for/main%topic=batch/change", GerritPushRef("refs/heads/main", "refs/heads/batch/change"))
+}
+
+// fakeGerrit implements just enough of the Gerrit API to exercise
+// GerritSource.
+type fakeGerrit struct {
+	url      string
+	changeID string
+	conflict bool
+
+	status gerrit.ChangeStatus
+	wip    bool
+	query  string
+	posted []string
+}
+
+func newFakeGerritSource(t *testing.T) (*GerritSource, *fakeGerrit) {
+	t.Helper()
+
+	fake := &fakeGerrit{changeID: "I0123456789abcdef0123456789abcdef01234567"}
+	srv := httptest.NewServer(fake.handler())
+	t.Cleanup(srv.Close)
+	fake.url = srv.URL + "/"
+
+	client, err := gerrit.NewClient("Test", &schema.GerritConnection{Url: fake.url, Username: "admin", Password: "secret"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &GerritSource{client: client}, fake
+}
+
+func (f *fakeGerrit) handler() http.Handler {
+	respond := func(w http.ResponseWriter, status int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		// Gerrit prefixes JSON responses to prevent XSSI.
+		_, _ = w.Write([]byte(")]}'\n"))
+		_ = json.NewEncoder(w).Encode(v)
+	}
+
+	const changePath = "/a/changes/org%2Frepo~7"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a/changes/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/a/changes/" {
+			f.query = r.URL.Query().Get("q")
+			if !strings.HasSuffix(f.query, " change:"+f.changeID) {
+				respond(w, http.StatusOK, []*gerrit.Change{})
+				return
+			}
+			respond(w, http.StatusOK, []*gerrit.Change{f.change()})
+			return
+		}
+
+		if r.Method != http.MethodPost || !strings.HasPrefix(r.URL.EscapedPath(), changePath+"/") {
+			respond(w, http.StatusNotFound, nil)
+			return
+		}
+		f.posted = append(f.posted, r.URL.EscapedPath())
+
+		switch strings.TrimPrefix(r.URL.EscapedPath(), changePath+"/") {
+		case "abandon":
+			f.status = gerrit.ChangeStatusAbandoned
+		case "restore":
+			f.status = gerrit.ChangeStatusNew
+		case "submit":
+			if f.conflict {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte("change is new but cannot be merged"))
+				return
+			}
+			f.status = gerrit.ChangeStatusMerged
+		case "wip":
+			f.wip = true
+		case "ready":
+			f.wip = false
+		}
+		respond(w, http.StatusOK, f.change())
+	})
+	return mux
+}
+
+func (f *fakeGerrit) change() *gerrit.Change {
+	status := f.status
+	if status == "" {
+		status = gerrit.ChangeStatusNew
+	}
+	created := gerrit.Timestamp{Time: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)}
+	return &gerrit.Change{
+		ID:              "org%2Frepo~main~" + f.changeID,
+		Project:         "org/repo",
+		Branch:          "main",
+		Topic:           "branch",
+		ChangeID:        f.changeID,
+		Subject:         "title",
+		Status:          status,
+		Created:         created,
+		Updated:         created,
+		WorkInProgress:  f.wip,
+		Number:          7,
+		Owner:           gerrit.Account{ID: 1, Name: "Author", Email: "author@example.com", Username: "author"},
+		CurrentRevision: "head-sha",
+		Revisions: map[string]*gerrit.RevisionInfo{
+			"head-sha": {
+				Number: 1,
+				Ref:    "refs/changes/07/7/1",
+				Commit: &gerrit.CommitInfo{
+					Subject: "title",
+					Message: "title\n\nbody\n\nChange-Id: " + f.changeID + "\n",
+				},
+			},
+		},
+	}
+}
+
+func gerritTestChangeset(externalID string) *Changeset {
+	repo := &types.Repo{Metadata: &gerrit.Project{ID: "org%2Frepo"}}
+	cs := &Changeset{
+		Title:      "title",
+		Body:       "body",
+		HeadRef:    "refs/heads/branch",
+		BaseRef:    "refs/heads/main",
+		TargetRepo: repo,
+		RemoteRepo: repo,
+	}
+	cs.Changeset = &btypes.Changeset{ID: 42, ExternalID: externalID}
+	return cs
+}
@@ -0,0 +1,348 @@
+package sources
+
+import (
+	"context"
+	"strconv"
+
+	gtcs "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type GiteaSource struct {
+	client *gitea.Client
+}
+
+var (
+	_ ForkableChangesetSource = GiteaSource{}
+)
+
+func NewGiteaSource(ctx context.Context, svc *types.ExternalService, cf *httpcli.Factory) (*GiteaSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.GiteaConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d", svc.ID)
+	}
+
+	if cf == nil {
+		cf = httpcli.ExternalClientFactory
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, errors.Wrap(err, "creating external client")
+	}
+
+	client, err := gitea.NewClient(svc.URN(), &c, cli)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating Gitea client")
+	}
+
+	return &GiteaSource{client: client}, nil
+}
+
+// GitserverPushConfig returns an authenticated push config used for pushing
+// commits to the code host.
+func (s GiteaSource) GitserverPushConfig(ctx context.Context, store database.ExternalServiceStore, repo *types.Repo) (*protocol.PushConfig, error) {
+	return GitserverPushConfig(ctx, store, repo, s.client.Auth)
+}
+
+// WithAuthenticator returns a copy of the original Source configured to use the
+// given authenticator, provided that authenticator type is supported by the
+// code host.
+func (s GiteaSource) WithAuthenticator(a auth.Authenticator) (ChangesetSource, error) {
+	switch a.(type) {
+	case *auth.OAuthBearerToken,
+		*auth.OAuthBearerTokenWithSSH:
+		break
+
+	default:
+		return nil, newUnsupportedAuthenticatorError("GiteaSource", a)
+	}
+
+	return &GiteaSource{client: s.client.WithAuthenticator(a)}, nil
+}
+
+// ValidateAuthenticator validates the currently set authenticator is usable.
+// Returns an error, when validating the Authenticator yielded an error.
+func (s GiteaSource) ValidateAuthenticator(ctx context.Context) error {
+	_, err := s.client.GetAuthenticatedUser(ctx)
+	return err
+}
+
+// LoadChangeset loads the given Changeset from the source and updates it. If
+// the Changeset could not be found on the source, a ChangesetNotFoundError is
+// returned.
+func (s GiteaSource) LoadChangeset(ctx context.Context, cs *Changeset) error {
+	repo := cs.TargetRepo.Metadata.(*gitea.Repository)
+	number, err := strconv.ParseInt(cs.ExternalID, 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "converting external ID %q", cs.ExternalID)
+	}
+
+	pr, err := s.client.GetPullRequest(ctx, repo.Owner.Login, repo.Name, number)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return ChangesetNotFoundError{Changeset: cs}
+		}
+		return errors.Wrap(err, "getting pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, pr, cs)
+}
+
+// CreateChangeset will create the Changeset on the source. If it already
+// exists, *Changeset will be populated and the return value will be true.
+func (s GiteaSource) CreateChangeset(ctx context.Context, cs *Changeset) (bool, error) {
+	repo := cs.TargetRepo.Metadata.(*gitea.Repository)
+
+	exists := false
+	pr, err := s.client.CreatePullRequest(ctx, repo.Owner.Login, repo.Name, gitea.CreatePullRequestInput{
+		Head:  s.headLabel(cs),
+		Base:  gitdomain.AbbreviateRef(cs.BaseRef),
+		Title: cs.Title,
+		Body:  cs.Body,
+	})
+	if err != nil {
+		if !gitea.IsConflict(err) {
+			return false, errors.Wrap(err, "creating pull request")
+		}
+
+		// Gitea refuses to create a second open pull request for the same
+		// branches, so we look up the existing one instead.
+		pr, err = s.findOpenPullRequest(ctx, repo, cs)
+		if err != nil {
+			return false, errors.Wrap(err, "fetching existing pull request")
+		}
+		exists = true
+	}
+
+	if err := s.setChangesetMetadata(ctx, repo, pr, cs); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// CloseChangeset will close the Changeset on the source, where "close"
+// means the appropriate final state on the codehost (e.g. "declined" on
+// Bitbucket Server).
+func (s GiteaSource) CloseChangeset(ctx context.Context, cs *Changeset) error {
+	return s.editPullRequest(ctx, cs, gitea.EditPullRequestInput{State: gitea.PullRequestStateClosed})
+}
+
+// UpdateChangeset can update Changesets.
+func (s GiteaSource) UpdateChangeset(ctx context.Context, cs *Changeset) error {
+	return s.editPullRequest(ctx, cs, gitea.EditPullRequestInput{
+		Title: cs.Title,
+		Body:  &cs.Body,
+		Base:  gitdomain.AbbreviateRef(cs.BaseRef),
+	})
+}
+
+// ReopenChangeset will reopen the Changeset on the source, if it's closed.
+// If not, it's a noop.
+func (s GiteaSource) ReopenChangeset(ctx context.Context, cs *Changeset) error {
+	return s.editPullRequest(ctx, cs, gitea.EditPullRequestInput{State: gitea.PullRequestStateOpen})
+}
+
+// CreateComment posts a comment on the Changeset.
+func (s GiteaSource) CreateComment(ctx context.Context, cs *Changeset, comment string) error {
+	repo := cs.TargetRepo.Metadata.(*gitea.Repository)
+	pr := cs.Metadata.(*gtcs.AnnotatedPullRequest)
+
+	return s.client.CreatePullRequestComment(ctx, repo.Owner.Login, repo.Name, pr.Number, comment)
+}
+
+// MergeChangeset merges a Changeset on the code host, if in a mergeable state.
+// If squash is true, and the code host supports squash merges, the source
+// must attempt a squash merge. Otherwise, it is expected to perform a regular
+// merge. If the changeset cannot be merged, because it is in an unmergeable
+// state, ChangesetNotMergeableError must be returned.
+func (s GiteaSource) MergeChangeset(ctx context.Context, cs *Changeset, squash bool) error {
+	repo := cs.TargetRepo.Metadata.(*gitea.Repository)
+	pr := cs.Metadata.(*gtcs.AnnotatedPullRequest)
+
+	style := gitea.MergeStyleMerge
+	if squash {
+		style = gitea.MergeStyleSquash
+	}
+
+	if err := s.client.MergePullRequest(ctx, repo.Owner.Login, repo.Name, pr.Number, style); err != nil {
+		if gitea.IsNotMergeable(err) {
+			return ChangesetNotMergeableError{ErrorMsg: err.Error()}
+		}
+		return errors.Wrap(err, "merging pull request")
+	}
+
+	// The merge endpoint doesn't return the pull request, so we have to fetch
+	// its new state.
+	updated, err := s.client.GetPullRequest(ctx, repo.Owner.Login, repo.Name, pr.Number)
+	if err != nil {
+		return errors.Wrap(err, "getting pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, updated, cs)
+}
+
+// GetNamespaceFork returns a repo pointing to a fork of the given repo in
+// the given namespace, ensuring that the fork exists and is a fork of the
+// target repo.
+func (s GiteaSource) GetNamespaceFork(ctx context.Context, targetRepo *types.Repo, namespace string) (*types.Repo, error) {
+	return s.getFork(ctx, targetRepo, namespace, namespace)
+}
+
+// GetUserFork returns a repo pointing to a fork of the given repo in the
+// currently authenticated user's namespace.
+func (s GiteaSource) GetUserFork(ctx context.Context, targetRepo *types.Repo) (*types.Repo, error) {
+	user, err := s.client.GetAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting the current user")
+	}
+
+	// Forks into the namespace of the authenticated user are requested
+	// without an organization.
+	return s.getFork(ctx, targetRepo, user.Login, "")
+}
+
+// getFork returns the fork of the target repo in the given namespace, forking
+// it into the given organization first if it doesn't exist yet.
+func (s GiteaSource) getFork(ctx context.Context, targetRepo *types.Repo, namespace, org string) (*types.Repo, error) {
+	upstream := targetRepo.Metadata.(*gitea.Repository)
+
+	fork, err := s.client.GetRepo(ctx, namespace, upstream.Name)
+	if err == nil {
+		if fork.Parent == nil || fork.Parent.ID != upstream.ID {
+			return nil, errors.Newf("repository %s is not a fork of %s", fork.FullName, upstream.FullName)
+		}
+		return s.createRemoteRepo(targetRepo, fork), nil
+	} else if !errcode.IsNotFound(err) {
+		return nil, errors.Wrap(err, "checking for fork existence")
+	}
+
+	fork, err = s.client.ForkRepo(ctx, upstream.Owner.Login, upstream.Name, org)
+	if err != nil {
+		return nil, errors.Wrap(err, "forking repository")
+	}
+
+	return s.createRemoteRepo(targetRepo, fork), nil
+}
+
+func (GiteaSource) createRemoteRepo(targetRepo *types.Repo, fork *gitea.Repository) *types.Repo {
+	// This needs to be good enough to get the right values out of
+	// giteaCloneURL(), which only looks at the metadata, so we can just copy
+	// it in over the top of the targetRepo.
+	remoteRepo := *targetRepo
+	remoteRepo.Metadata = fork
+
+	return &remoteRepo
+}
+
+// headLabel returns the head of the pull request for the given changeset,
+// which is prefixed with the owner of the fork if the changeset is pushed to
+// one.
+func (GiteaSource) headLabel(cs *Changeset) string {
+	branch := gitdomain.AbbreviateRef(cs.HeadRef)
+	if cs.RemoteRepo == cs.TargetRepo {
+		return branch
+	}
+	fork := cs.RemoteRepo.Metadata.(*gitea.Repository)
+	return fork.Owner.Login + ":" + branch
+}
+
+// findOpenPullRequest returns the open pull request for the head and base of
+// the given changeset.
+func (s GiteaSource) findOpenPullRequest(ctx context.Context, repo *gitea.Repository, cs *Changeset) (*gitea.PullRequest, error) {
+	head := gitdomain.AbbreviateRef(cs.HeadRef)
+	base := gitdomain.AbbreviateRef(cs.BaseRef)
+	remote := cs.RemoteRepo.Metadata.(*gitea.Repository)
+
+	opts := gitea.ListOptions{Page: 1, Limit: 50}
+	for {
+		prs, hasNextPage, err := s.client.ListPullRequests(ctx, repo.Owner.Login, repo.Name, string(gitea.PullRequestStateOpen), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pr := range prs {
+			if pr.Head == nil || pr.Base == nil {
+				continue
+			}
+			if pr.Head.Ref == head && pr.Base.Ref == base && pr.Head.RepoID == remote.ID {
+				return pr, nil
+			}
+		}
+
+		if !hasNextPage {
+			return nil, errors.Newf("no open pull request from %q into %q found", head, base)
+		}
+		opts.Page++
+	}
+}
+
+func (s GiteaSource) editPullRequest(ctx context.Context, cs *Changeset, input gitea.EditPullRequestInput) error {
+	repo := cs.TargetRepo.Metadata.(*gitea.Repository)
+	pr := cs.Metadata.(*gtcs.AnnotatedPullRequest)
+
+	updated, err := s.client.EditPullRequest(ctx, repo.Owner.Login, repo.Name, pr.Number, input)
+	if err != nil {
+		return errors.Wrap(err, "updating pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, updated, cs)
+}
+
+func (s GiteaSource) annotatePullRequest(ctx context.Context, repo *gitea.Repository, pr *gitea.PullRequest) (*gtcs.AnnotatedPullRequest, error) {
+	apr := &gtcs.AnnotatedPullRequest{
+		PullRequest: pr,
+		Reviews:     []*gitea.PullReview{},
+	}
+
+	opts := gitea.ListOptions{Page: 1, Limit: 50}
+	for {
+		reviews, hasNextPage, err := s.client.ListPullReviews(ctx, repo.Owner.Login, repo.Name, pr.Number, opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "listing pull request reviews")
+		}
+		apr.Reviews = append(apr.Reviews, reviews...)
+
+		if !hasNextPage {
+			break
+		}
+		opts.Page++
+	}
+
+	if pr.Head != nil && pr.Head.Sha != "" {
+		status, err := s.client.GetCombinedStatus(ctx, repo.Owner.Login, repo.Name, pr.Head.Sha)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting commit status")
+		}
+		apr.Status = status
+	}
+
+	return apr, nil
+}
+
+func (s GiteaSource) setChangesetMetadata(ctx context.Context, repo *gitea.Repository, pr *gitea.PullRequest, cs *Changeset) error {
+	apr, err := s.annotatePullRequest(ctx, repo, pr)
+	if err != nil {
+		return errors.Wrap(err, "annotating pull request")
+	}
+
+	if err := cs.SetMetadata(apr); err != nil {
+		return errors.Wrap(err, "setting changeset metadata")
+	}
+
+	return nil
+}
@@ -0,0 +1,357 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	gtcs "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/gitea"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestNewGiteaSource(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		for name, input := range map[string]string{
+			"invalid JSON":   "invalid JSON",
+			"invalid schema": `{"token": ["not a string"]}`,
+			"bad URL":        `{"url": "http://[::1]:namedport"}`,
+		} {
+			t.Run(name, func(t *testing.T) {
+				ctx := context.Background()
+				s, err := NewGiteaSource(ctx, &types.ExternalService{
+					Config: extsvc.NewUnencryptedConfig(input),
+				}, nil)
+				assert.Nil(t, s)
+				assert.NotNil(t, err)
+			})
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		ctx := context.Background()
+		s, err := NewGiteaSource(ctx, &types.ExternalService{
+			Config: extsvc.NewUnencryptedConfig(`{"url": "https://gitea.example.com", "token": "secret"}`),
+		}, nil)
+		assert.NotNil(t, s)
+		assert.Nil(t, err)
+	})
+}
+
+func TestGiteaSource_WithAuthenticator(t *testing.T) {
+	s, _ := newFakeGiteaSource(t)
+
+	t.Run("supported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"OAuthBearerToken":        &auth.OAuthBearerToken{Token: "token"},
+			"OAuthBearerTokenWithSSH": &auth.OAuthBearerTokenWithSSH{OAuthBearerToken: auth.OAuthBearerToken{Token: "token"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				src, err := s.WithAuthenticator(tc)
+				assert.Nil(t, err)
+				assert.Same(t, tc, src.(*GiteaSource).client.Auth)
+			})
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"nil":       nil,
+			"BasicAuth": &auth.BasicAuth{},
+		} {
+			t.Run(name, func(t *testing.T) {
+				src, err := s.WithAuthenticator(tc)
+				assert.Nil(t, src)
+				assert.ErrorAs(t, err, &UnsupportedAuthenticatorError{})
+			})
+		}
+	})
+}
+
+func TestGiteaSource_LoadChangeset(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		s, _ := newFakeGiteaSource(t)
+		cs := giteaTestChangeset("404")
+
+		err := s.LoadChangeset(ctx, cs)
+		target := ChangesetNotFoundError{}
+		assert.ErrorAs(t, err, &target)
+		assert.Same(t, target.Changeset, cs)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		s, fake := newFakeGiteaSource(t)
+		fake.reviews = []*gitea.PullReview{
+			{ID: 1, User: &gitea.User{ID: 2, Login: "reviewer"}, State: gitea.ReviewStateApproved},
+		}
+		cs := giteaTestChangeset("1")
+
+		assert.Nil(t, s.LoadChangeset(ctx, cs))
+
+		apr := cs.Metadata.(*gtcs.AnnotatedPullRequest)
+		assert.EqualValues(t, 1, apr.Number)
+		assert.Len(t, apr.Reviews, 1)
+		assert.Equal(t, gitea.CommitStatusSuccess, apr.Status.State)
+		assert.Equal(t, "1", cs.ExternalID)
+		assert.Equal(t, extsvc.TypeGitea, cs.ExternalServiceType)
+		assert.Equal(t, "refs/heads/branch", cs.ExternalBranch)
+		assert.Equal(t, "", cs.ExternalForkNamespace)
+	})
+}
+
+func TestGiteaSource_CreateChangeset(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("new pull request", func(t *testing.T) {
+		s, fake := newFakeGiteaSource(t)
+		cs := giteaTestChangeset("")
+
+		exists, err := s.CreateChangeset(ctx, cs)
+		assert.Nil(t, err)
+		assert.False(t, exists)
+		assert.Equal(t, "1", cs.ExternalID)
+		assert.Equal(t, "branch", fake.created.Head)
+		assert.Equal(t, "main", fake.created.Base)
+	})
+
+	t.Run("pull request from fork", func(t *testing.T) {
+		s, fake := newFakeGiteaSource(t)
+		cs := giteaTestChangeset("")
+		cs.RemoteRepo = &types.Repo{Metadata: giteaTestFork()}
+
+		_, err := s.CreateChangeset(ctx, cs)
+		assert.Nil(t, err)
+		assert.Equal(t, "fork:branch", fake.created.Head)
+	})
+
+	t.Run("pull request already exists", func(t *testing.T) {
+		s, fake := newFakeGiteaSource(t)
+		fake.conflict = true
+		cs := giteaTestChangeset("")
+
+		exists, err := s.CreateChangeset(ctx, cs)
+		assert.Nil(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, "1", cs.ExternalID)
+	})
+}
+
+func TestGiteaSource_MergeChangeset(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not mergeable", func(t *testing.T) {
+		s, fake := newFakeGiteaSource(t)
+		fake.unmergeable = true
+		cs := giteaTestChangeset("1")
+		assert.Nil(t, s.LoadChangeset(ctx, cs))
+
+		err := s.MergeChangeset(ctx, cs, false)
+		assert.ErrorAs(t, err, &ChangesetNotMergeableError{})
+	})
+
+	t.Run("success", func(t *testing.T) {
+		s, fake := newFakeGiteaSource(t)
+		cs := giteaTestChangeset("1")
+		assert.Nil(t, s.LoadChangeset(ctx, cs))
+
+		assert.Nil(t, s.MergeChangeset(ctx, cs, true))
+		assert.Equal(t, gitea.MergeStyleSquash, fake.mergeStyle)
+		assert.True(t, cs.Metadata.(*gtcs.AnnotatedPullRequest).Merged)
+	})
+}
+
+func TestGiteaSource_GetNamespaceFork(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("existing fork", func(t *testing.T) {
+		s, fake := newFakeGiteaSource(t)
+		fake.fork = giteaTestFork()
+
+		repo, err := s.GetNamespaceFork(ctx, giteaTestChangeset("").TargetRepo, "fork")
+		assert.Nil(t, err)
+		assert.Equal(t, "fork/repo", repo.Metadata.(*gitea.Repository).FullName)
+		assert.False(t, fake.forked)
+	})
+
+	t.Run("not a fork", func(t *testing.T) {
+		s, fake := newFakeGiteaSource(t)
+		fake.fork = giteaTestFork()
+		fake.fork.Parent = nil
+
+		repo, err := s.GetNamespaceFork(ctx, giteaTestChangeset("").TargetRepo, "fork")
+		assert.Nil(t, repo)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("new fork", func(t *testing.T) {
+		s, fake := newFakeGiteaSource(t)
+
+		repo, err := s.GetNamespaceFork(ctx, giteaTestChangeset("").TargetRepo, "fork")
+		assert.Nil(t, err)
+		assert.Equal(t, "fork/repo", repo.Metadata.(*gitea.Repository).FullName)
+		assert.True(t, fake.forked)
+	})
+}
+
+// fakeGitea implements just enough of the Gitea API to exercise GiteaSource.
+type fakeGitea struct {
+	reviews     []*gitea.PullReview
+	fork        *gitea.Repository
+	conflict    bool
+	unmergeable bool
+
+	created    gitea.CreatePullRequestInput
+	mergeStyle gitea.MergeStyle
+	merged     bool
+	forked     bool
+}
+
+func newFakeGiteaSource(t *testing.T) (*GiteaSource, *fakeGitea) {
+	t.Helper()
+
+	fake := &fakeGitea{}
+	srv := httptest.NewServer(fake.handler())
+	t.Cleanup(srv.Close)
+
+	client, err := gitea.NewClient("Test", &schema.GiteaConnection{Url: srv.URL, Token: "secret"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &GiteaSource{client: client}, fake
+}
+
+func (f *fakeGitea) handler() http.Handler {
+	respond := func(w http.ResponseWriter, status int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/user", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, &gitea.User{ID: 3, Login: "fork"})
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			if f.conflict {
+				respond(w, http.StatusConflict, map[string]string{"message": "pull request already exists"})
+				return
+			}
+			if err := json.NewDecoder(r.Body).Decode(&f.created); err != nil {
+				respond(w, http.StatusBadRequest, nil)
+				return
+			}
+			respond(w, http.StatusCreated, f.pullRequest())
+		default:
+			respond(w, http.StatusOK, []*gitea.PullRequest{f.pullRequest()})
+		}
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, f.pullRequest())
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/pulls/1/merge", func(w http.ResponseWriter, r *http.Request) {
+		if f.unmergeable {
+			respond(w, http.StatusMethodNotAllowed, map[string]string{"message": "not mergeable"})
+			return
+		}
+		var payload struct {
+			Do gitea.MergeStyle `json:"Do"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		f.mergeStyle = payload.Do
+		f.merged = true
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		reviews := f.reviews
+		if reviews == nil {
+			reviews = []*gitea.PullReview{}
+		}
+		respond(w, http.StatusOK, reviews)
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/commits/head-sha/status", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, &gitea.CombinedStatus{
+			State:    gitea.CommitStatusSuccess,
+			SHA:      "head-sha",
+			Statuses: []*gitea.CommitStatus{{State: gitea.CommitStatusSuccess}},
+		})
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/forks", func(w http.ResponseWriter, r *http.Request) {
+		f.forked = true
+		respond(w, http.StatusAccepted, giteaTestFork())
+	})
+	mux.HandleFunc("/api/v1/repos/fork/repo", func(w http.ResponseWriter, r *http.Request) {
+		if f.fork == nil {
+			respond(w, http.StatusNotFound, map[string]string{"message": "not found"})
+			return
+		}
+		respond(w, http.StatusOK, f.fork)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusNotFound, map[string]string{"message": "not found"})
+	})
+	return mux
+}
+
+func (f *fakeGitea) pullRequest() *gitea.PullRequest {
+	target := giteaTestRepo()
+	pr := &gitea.PullRequest{
+		ID:     100,
+		Number: 1,
+		User:   &gitea.User{ID: 1, Login: "author"},
+		Title:  "title",
+		State:  gitea.PullRequestStateOpen,
+		Base:   &gitea.PRBranchInfo{Ref: "main", Sha: "base-sha", RepoID: target.ID, Repo: target},
+		Head:   &gitea.PRBranchInfo{Ref: "branch", Sha: "head-sha", RepoID: target.ID, Repo: target},
+	}
+	if f.merged {
+		pr.State = gitea.PullRequestStateClosed
+		pr.Merged = true
+	}
+	return pr
+}
+
+func giteaTestRepo() *gitea.Repository {
+	return &gitea.Repository{
+		ID:       1,
+		Owner:    &gitea.User{ID: 1, Login: "owner"},
+		Name:     "repo",
+		FullName: "owner/repo",
+	}
+}
+
+func giteaTestFork() *gitea.Repository {
+	return &gitea.Repository{
+		ID:       2,
+		Owner:    &gitea.User{ID: 3, Login: "fork"},
+		Name:     "repo",
+		FullName: "fork/repo",
+		Fork:     true,
+		Parent:   giteaTestRepo(),
+	}
+}
+
+func giteaTestChangeset(externalID string) *Changeset {
+	repo := &types.Repo{Metadata: giteaTestRepo()}
+	cs := &Changeset{
+		Title:      "title",
+		Body:       "body",
+		HeadRef:    "refs/heads/branch",
+		BaseRef:    "refs/heads/main",
+		TargetRepo: repo,
+		RemoteRepo: repo,
+	}
+	cs.Changeset = &btypes.Changeset{ExternalID: externalID}
+	return cs
+}
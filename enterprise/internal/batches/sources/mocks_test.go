@@ -3728,6 +3728,16 @@ type MockBitbucketCloudClient struct {
 	// CurrentUserFunc is an instance of a mock function object controlling
 	// the behavior of the method CurrentUser.
 	CurrentUserFunc *BitbucketCloudClientCurrentUserFunc
+	// CurrentUserEmailsFunc is an instance of a mock function object
+	// controlling the behavior of the method CurrentUserEmails.
+	CurrentUserEmailsFunc *BitbucketCloudClientCurrentUserEmailsFunc
+	// CurrentUserReposFunc is an instance of a mock function object
+	// controlling the behavior of the method CurrentUserRepos.
+	CurrentUserReposFunc *BitbucketCloudClientCurrentUserReposFunc
+	// CurrentUserWorkspacePermissionsFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// CurrentUserWorkspacePermissions.
+	CurrentUserWorkspacePermissionsFunc *BitbucketCloudClientCurrentUserWorkspacePermissionsFunc
 	// DeclinePullRequestFunc is an instance of a mock function object
 	// controlling the behavior of the method DeclinePullRequest.
 	DeclinePullRequestFunc *BitbucketCloudClientDeclinePullRequestFunc
@@ -3749,6 +3759,9 @@ type MockBitbucketCloudClient struct {
 	// RepoFunc is an instance of a mock function object controlling the
 	// behavior of the method Repo.
 	RepoFunc *BitbucketCloudClientRepoFunc
+	// RepoUserPermissionsFunc is an instance of a mock function object
+	// controlling the behavior of the method RepoUserPermissions.
+	RepoUserPermissionsFunc *BitbucketCloudClientRepoUserPermissionsFunc
 	// ReposFunc is an instance of a mock function object controlling the
 	// behavior of the method Repos.
 	ReposFunc *BitbucketCloudClientReposFunc
@@ -3758,6 +3771,9 @@ type MockBitbucketCloudClient struct {
 	// WithAuthenticatorFunc is an instance of a mock function object
 	// controlling the behavior of the method WithAuthenticator.
 	WithAuthenticatorFunc *BitbucketCloudClientWithAuthenticatorFunc
+	// WorkspacePermissionsFunc is an instance of a mock function object
+	// controlling the behavior of the method WorkspacePermissions.
+	WorkspacePermissionsFunc *BitbucketCloudClientWorkspacePermissionsFunc
 }
 
 // NewMockBitbucketCloudClient creates a new mock of the Client interface.
@@ -3784,6 +3800,21 @@ func NewMockBitbucketCloudClient() *MockBitbucketCloudClient {
 				return
 			},
 		},
+		CurrentUserEmailsFunc: &BitbucketCloudClientCurrentUserEmailsFunc{
+			defaultHook: func(context.Context, *bitbucketcloud.PageToken) (r0 []*bitbucketcloud.UserEmail, r1 *bitbucketcloud.PageToken, r2 error) {
+				return
+			},
+		},
+		CurrentUserReposFunc: &BitbucketCloudClientCurrentUserReposFunc{
+			defaultHook: func(context.Context, *bitbucketcloud.PageToken, string) (r0 []*bitbucketcloud.Repo, r1 *bitbucketcloud.PageToken, r2 error) {
+				return
+			},
+		},
+		CurrentUserWorkspacePermissionsFunc: &BitbucketCloudClientCurrentUserWorkspacePermissionsFunc{
+			defaultHook: func(context.Context, *bitbucketcloud.PageToken) (r0 []*bitbucketcloud.WorkspacePermission, r1 *bitbucketcloud.PageToken, r2 error) {
+				return
+			},
+		},
 		DeclinePullRequestFunc: &BitbucketCloudClientDeclinePullRequestFunc{
 			defaultHook: func(context.Context, *bitbucketcloud.Repo, int64) (r0 *bitbucketcloud.PullRequest, r1 error) {
 				return
@@ -3819,6 +3850,11 @@ func NewMockBitbucketCloudClient() *MockBitbucketCloudClient {
 				return
 			},
 		},
+		RepoUserPermissionsFunc: &BitbucketCloudClientRepoUserPermissionsFunc{
+			defaultHook: func(context.Context, *bitbucketcloud.PageToken, string, string) (r0 []*bitbucketcloud.RepoPermission, r1 *bitbucketcloud.PageToken, r2 error) {
+				return
+			},
+		},
 		ReposFunc: &BitbucketCloudClientReposFunc{
 			defaultHook: func(context.Context, *bitbucketcloud.PageToken, string) (r0 []*bitbucketcloud.Repo, r1 *bitbucketcloud.PageToken, r2 error) {
 				return
@@ -3834,6 +3870,11 @@ func NewMockBitbucketCloudClient() *MockBitbucketCloudClient {
 				return
 			},
 		},
+		WorkspacePermissionsFunc: &BitbucketCloudClientWorkspacePermissionsFunc{
+			defaultHook: func(context.Context, *bitbucketcloud.PageToken, string) (r0 []*bitbucketcloud.WorkspacePermission, r1 *bitbucketcloud.PageToken, r2 error) {
+				return
+			},
+		},
 	}
 }
 
@@ -3861,6 +3902,21 @@ func NewStrictMockBitbucketCloudClient() *MockBitbucketCloudClient {
 				panic("unexpected invocation of MockBitbucketCloudClient.CurrentUser")
 			},
 		},
+		CurrentUserEmailsFunc: &BitbucketCloudClientCurrentUserEmailsFunc{
+			defaultHook: func(context.Context, *bitbucketcloud.PageToken) ([]*bitbucketcloud.UserEmail, *bitbucketcloud.PageToken, error) {
+				panic("unexpected invocation of MockBitbucketCloudClient.CurrentUserEmails")
+			},
+		},
+		CurrentUserReposFunc: &BitbucketCloudClientCurrentUserReposFunc{
+			defaultHook: func(context.Context, *bitbucketcloud.PageToken, string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error) {
+				panic("unexpected invocation of MockBitbucketCloudClient.CurrentUserRepos")
+			},
+		},
+		CurrentUserWorkspacePermissionsFunc: &BitbucketCloudClientCurrentUserWorkspacePermissionsFunc{
+			defaultHook: func(context.Context, *bitbucketcloud.PageToken) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error) {
+				panic("unexpected invocation of MockBitbucketCloudClient.CurrentUserWorkspacePermissions")
+			},
+		},
 		DeclinePullRequestFunc: &BitbucketCloudClientDeclinePullRequestFunc{
 			defaultHook: func(context.Context, *bitbucketcloud.Repo, int64) (*bitbucketcloud.PullRequest, error) {
 				panic("unexpected invocation of MockBitbucketCloudClient.DeclinePullRequest")
@@ -3896,6 +3952,11 @@ func NewStrictMockBitbucketCloudClient() *MockBitbucketCloudClient {
 				panic("unexpected invocation of MockBitbucketCloudClient.Repo")
 			},
 		},
+		RepoUserPermissionsFunc: &BitbucketCloudClientRepoUserPermissionsFunc{
+			defaultHook: func(context.Context, *bitbucketcloud.PageToken, string, string) ([]*bitbucketcloud.RepoPermission, *bitbucketcloud.PageToken, error) {
+				panic("unexpected invocation of MockBitbucketCloudClient.RepoUserPermissions")
+			},
+		},
 		ReposFunc: &BitbucketCloudClientReposFunc{
 			defaultHook: func(context.Context, *bitbucketcloud.PageToken, string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error) {
 				panic("unexpected invocation of MockBitbucketCloudClient.Repos")
@@ -3911,6 +3972,11 @@ func NewStrictMockBitbucketCloudClient() *MockBitbucketCloudClient {
 				panic("unexpected invocation of MockBitbucketCloudClient.WithAuthenticator")
 			},
 		},
+		WorkspacePermissionsFunc: &BitbucketCloudClientWorkspacePermissionsFunc{
+			defaultHook: func(context.Context, *bitbucketcloud.PageToken, string) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error) {
+				panic("unexpected invocation of MockBitbucketCloudClient.WorkspacePermissions")
+			},
+		},
 	}
 }
 
@@ -3931,6 +3997,15 @@ func NewMockBitbucketCloudClientFrom(i bitbucketcloud.Client) *MockBitbucketClou
 		CurrentUserFunc: &BitbucketCloudClientCurrentUserFunc{
 			defaultHook: i.CurrentUser,
 		},
+		CurrentUserEmailsFunc: &BitbucketCloudClientCurrentUserEmailsFunc{
+			defaultHook: i.CurrentUserEmails,
+		},
+		CurrentUserReposFunc: &BitbucketCloudClientCurrentUserReposFunc{
+			defaultHook: i.CurrentUserRepos,
+		},
+		CurrentUserWorkspacePermissionsFunc: &BitbucketCloudClientCurrentUserWorkspacePermissionsFunc{
+			defaultHook: i.CurrentUserWorkspacePermissions,
+		},
 		DeclinePullRequestFunc: &BitbucketCloudClientDeclinePullRequestFunc{
 			defaultHook: i.DeclinePullRequest,
 		},
@@ -3952,6 +4027,9 @@ func NewMockBitbucketCloudClientFrom(i bitbucketcloud.Client) *MockBitbucketClou
 		RepoFunc: &BitbucketCloudClientRepoFunc{
 			defaultHook: i.Repo,
 		},
+		RepoUserPermissionsFunc: &BitbucketCloudClientRepoUserPermissionsFunc{
+			defaultHook: i.RepoUserPermissions,
+		},
 		ReposFunc: &BitbucketCloudClientReposFunc{
 			defaultHook: i.Repos,
 		},
@@ -3961,6 +4039,9 @@ func NewMockBitbucketCloudClientFrom(i bitbucketcloud.Client) *MockBitbucketClou
 		WithAuthenticatorFunc: &BitbucketCloudClientWithAuthenticatorFunc{
 			defaultHook: i.WithAuthenticator,
 		},
+		WorkspacePermissionsFunc: &BitbucketCloudClientWorkspacePermissionsFunc{
+			defaultHook: i.WorkspacePermissions,
+		},
 	}
 }
 
@@ -4407,6 +4488,354 @@ func (c BitbucketCloudClientCurrentUserFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// BitbucketCloudClientCurrentUserEmailsFunc describes the behavior when the
+// CurrentUserEmails method of the parent MockBitbucketCloudClient instance
+// is invoked.
+type BitbucketCloudClientCurrentUserEmailsFunc struct {
+	defaultHook func(context.Context, *bitbucketcloud.PageToken) ([]*bitbucketcloud.UserEmail, *bitbucketcloud.PageToken, error)
+	hooks       []func(context.Context, *bitbucketcloud.PageToken) ([]*bitbucketcloud.UserEmail, *bitbucketcloud.PageToken, error)
+	history     []BitbucketCloudClientCurrentUserEmailsFuncCall
+	mutex       sync.Mutex
+}
+
+// CurrentUserEmails delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockBitbucketCloudClient) CurrentUserEmails(v0 context.Context, v1 *bitbucketcloud.PageToken) ([]*bitbucketcloud.UserEmail, *bitbucketcloud.PageToken, error) {
+	r0, r1, r2 := m.CurrentUserEmailsFunc.nextHook()(v0, v1)
+	m.CurrentUserEmailsFunc.appendCall(BitbucketCloudClientCurrentUserEmailsFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the CurrentUserEmails
+// method of the parent MockBitbucketCloudClient instance is invoked and the
+// hook queue is empty.
+func (f *BitbucketCloudClientCurrentUserEmailsFunc) SetDefaultHook(hook func(context.Context, *bitbucketcloud.PageToken) ([]*bitbucketcloud.UserEmail, *bitbucketcloud.PageToken, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CurrentUserEmails method of the parent MockBitbucketCloudClient instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *BitbucketCloudClientCurrentUserEmailsFunc) PushHook(hook func(context.Context, *bitbucketcloud.PageToken) ([]*bitbucketcloud.UserEmail, *bitbucketcloud.PageToken, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *BitbucketCloudClientCurrentUserEmailsFunc) SetDefaultReturn(r0 []*bitbucketcloud.UserEmail, r1 *bitbucketcloud.PageToken, r2 error) {
+	f.SetDefaultHook(func(context.Context, *bitbucketcloud.PageToken) ([]*bitbucketcloud.UserEmail, *bitbucketcloud.PageToken, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *BitbucketCloudClientCurrentUserEmailsFunc) PushReturn(r0 []*bitbucketcloud.UserEmail, r1 *bitbucketcloud.PageToken, r2 error) {
+	f.PushHook(func(context.Context, *bitbucketcloud.PageToken) ([]*bitbucketcloud.UserEmail, *bitbucketcloud.PageToken, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *BitbucketCloudClientCurrentUserEmailsFunc) nextHook() func(context.Context, *bitbucketcloud.PageToken) ([]*bitbucketcloud.UserEmail, *bitbucketcloud.PageToken, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *BitbucketCloudClientCurrentUserEmailsFunc) appendCall(r0 BitbucketCloudClientCurrentUserEmailsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// BitbucketCloudClientCurrentUserEmailsFuncCall objects describing the
+// invocations of this function.
+func (f *BitbucketCloudClientCurrentUserEmailsFunc) History() []BitbucketCloudClientCurrentUserEmailsFuncCall {
+	f.mutex.Lock()
+	history := make([]BitbucketCloudClientCurrentUserEmailsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// BitbucketCloudClientCurrentUserEmailsFuncCall is an object that describes
+// an invocation of method CurrentUserEmails on an instance of
+// MockBitbucketCloudClient.
+type BitbucketCloudClientCurrentUserEmailsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 *bitbucketcloud.PageToken
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*bitbucketcloud.UserEmail
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 *bitbucketcloud.PageToken
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c BitbucketCloudClientCurrentUserEmailsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c BitbucketCloudClientCurrentUserEmailsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// BitbucketCloudClientCurrentUserReposFunc describes the behavior when the
+// CurrentUserRepos method of the parent MockBitbucketCloudClient instance
+// is invoked.
+type BitbucketCloudClientCurrentUserReposFunc struct {
+	defaultHook func(context.Context, *bitbucketcloud.PageToken, string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error)
+	hooks       []func(context.Context, *bitbucketcloud.PageToken, string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error)
+	history     []BitbucketCloudClientCurrentUserReposFuncCall
+	mutex       sync.Mutex
+}
+
+// CurrentUserRepos delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockBitbucketCloudClient) CurrentUserRepos(v0 context.Context, v1 *bitbucketcloud.PageToken, v2 string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error) {
+	r0, r1, r2 := m.CurrentUserReposFunc.nextHook()(v0, v1, v2)
+	m.CurrentUserReposFunc.appendCall(BitbucketCloudClientCurrentUserReposFuncCall{v0, v1, v2, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the CurrentUserRepos
+// method of the parent MockBitbucketCloudClient instance is invoked and the
+// hook queue is empty.
+func (f *BitbucketCloudClientCurrentUserReposFunc) SetDefaultHook(hook func(context.Context, *bitbucketcloud.PageToken, string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CurrentUserRepos method of the parent MockBitbucketCloudClient instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *BitbucketCloudClientCurrentUserReposFunc) PushHook(hook func(context.Context, *bitbucketcloud.PageToken, string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *BitbucketCloudClientCurrentUserReposFunc) SetDefaultReturn(r0 []*bitbucketcloud.Repo, r1 *bitbucketcloud.PageToken, r2 error) {
+	f.SetDefaultHook(func(context.Context, *bitbucketcloud.PageToken, string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *BitbucketCloudClientCurrentUserReposFunc) PushReturn(r0 []*bitbucketcloud.Repo, r1 *bitbucketcloud.PageToken, r2 error) {
+	f.PushHook(func(context.Context, *bitbucketcloud.PageToken, string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *BitbucketCloudClientCurrentUserReposFunc) nextHook() func(context.Context, *bitbucketcloud.PageToken, string) ([]*bitbucketcloud.Repo, *bitbucketcloud.PageToken, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *BitbucketCloudClientCurrentUserReposFunc) appendCall(r0 BitbucketCloudClientCurrentUserReposFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// BitbucketCloudClientCurrentUserReposFuncCall objects describing the
+// invocations of this function.
+func (f *BitbucketCloudClientCurrentUserReposFunc) History() []BitbucketCloudClientCurrentUserReposFuncCall {
+	f.mutex.Lock()
+	history := make([]BitbucketCloudClientCurrentUserReposFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// BitbucketCloudClientCurrentUserReposFuncCall is an object that describes
+// an invocation of method CurrentUserRepos on an instance of
+// MockBitbucketCloudClient.
+type BitbucketCloudClientCurrentUserReposFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 *bitbucketcloud.PageToken
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*bitbucketcloud.Repo
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 *bitbucketcloud.PageToken
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c BitbucketCloudClientCurrentUserReposFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c BitbucketCloudClientCurrentUserReposFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// BitbucketCloudClientCurrentUserWorkspacePermissionsFunc describes the
+// behavior when the CurrentUserWorkspacePermissions method of the parent
+// MockBitbucketCloudClient instance is invoked.
+type BitbucketCloudClientCurrentUserWorkspacePermissionsFunc struct {
+	defaultHook func(context.Context, *bitbucketcloud.PageToken) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error)
+	hooks       []func(context.Context, *bitbucketcloud.PageToken) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error)
+	history     []BitbucketCloudClientCurrentUserWorkspacePermissionsFuncCall
+	mutex       sync.Mutex
+}
+
+// CurrentUserWorkspacePermissions delegates to the next hook function in
+// the queue and stores the parameter and result values of this invocation.
+func (m *MockBitbucketCloudClient) CurrentUserWorkspacePermissions(v0 context.Context, v1 *bitbucketcloud.PageToken) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error) {
+	r0, r1, r2 := m.CurrentUserWorkspacePermissionsFunc.nextHook()(v0, v1)
+	m.CurrentUserWorkspacePermissionsFunc.appendCall(BitbucketCloudClientCurrentUserWorkspacePermissionsFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the
+// CurrentUserWorkspacePermissions method of the parent
+// MockBitbucketCloudClient instance is invoked and the hook queue is empty.
+func (f *BitbucketCloudClientCurrentUserWorkspacePermissionsFunc) SetDefaultHook(hook func(context.Context, *bitbucketcloud.PageToken) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CurrentUserWorkspacePermissions method of the parent
+// MockBitbucketCloudClient instance invokes the hook at the front of the
+// queue and discards it. After the queue is empty, the default hook
+// function is invoked for any future action.
+func (f *BitbucketCloudClientCurrentUserWorkspacePermissionsFunc) PushHook(hook func(context.Context, *bitbucketcloud.PageToken) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *BitbucketCloudClientCurrentUserWorkspacePermissionsFunc) SetDefaultReturn(r0 []*bitbucketcloud.WorkspacePermission, r1 *bitbucketcloud.PageToken, r2 error) {
+	f.SetDefaultHook(func(context.Context, *bitbucketcloud.PageToken) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *BitbucketCloudClientCurrentUserWorkspacePermissionsFunc) PushReturn(r0 []*bitbucketcloud.WorkspacePermission, r1 *bitbucketcloud.PageToken, r2 error) {
+	f.PushHook(func(context.Context, *bitbucketcloud.PageToken) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *BitbucketCloudClientCurrentUserWorkspacePermissionsFunc) nextHook() func(context.Context, *bitbucketcloud.PageToken) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *BitbucketCloudClientCurrentUserWorkspacePermissionsFunc) appendCall(r0 BitbucketCloudClientCurrentUserWorkspacePermissionsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// BitbucketCloudClientCurrentUserWorkspacePermissionsFuncCall objects
+// describing the invocations of this function.
+func (f *BitbucketCloudClientCurrentUserWorkspacePermissionsFunc) History() []BitbucketCloudClientCurrentUserWorkspacePermissionsFuncCall {
+	f.mutex.Lock()
+	history := make([]BitbucketCloudClientCurrentUserWorkspacePermissionsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// BitbucketCloudClientCurrentUserWorkspacePermissionsFuncCall is an object
+// that describes an invocation of method CurrentUserWorkspacePermissions on
+// an instance of MockBitbucketCloudClient.
+type BitbucketCloudClientCurrentUserWorkspacePermissionsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 *bitbucketcloud.PageToken
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*bitbucketcloud.WorkspacePermission
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 *bitbucketcloud.PageToken
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c BitbucketCloudClientCurrentUserWorkspacePermissionsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c BitbucketCloudClientCurrentUserWorkspacePermissionsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // BitbucketCloudClientDeclinePullRequestFunc describes the behavior when
 // the DeclinePullRequest method of the parent MockBitbucketCloudClient
 // instance is invoked.
@@ -5193,6 +5622,127 @@ func (c BitbucketCloudClientRepoFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// BitbucketCloudClientRepoUserPermissionsFunc describes the behavior when
+// the RepoUserPermissions method of the parent MockBitbucketCloudClient
+// instance is invoked.
+type BitbucketCloudClientRepoUserPermissionsFunc struct {
+	defaultHook func(context.Context, *bitbucketcloud.PageToken, string, string) ([]*bitbucketcloud.RepoPermission, *bitbucketcloud.PageToken, error)
+	hooks       []func(context.Context, *bitbucketcloud.PageToken, string, string) ([]*bitbucketcloud.RepoPermission, *bitbucketcloud.PageToken, error)
+	history     []BitbucketCloudClientRepoUserPermissionsFuncCall
+	mutex       sync.Mutex
+}
+
+// RepoUserPermissions delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockBitbucketCloudClient) RepoUserPermissions(v0 context.Context, v1 *bitbucketcloud.PageToken, v2 string, v3 string) ([]*bitbucketcloud.RepoPermission, *bitbucketcloud.PageToken, error) {
+	r0, r1, r2 := m.RepoUserPermissionsFunc.nextHook()(v0, v1, v2, v3)
+	m.RepoUserPermissionsFunc.appendCall(BitbucketCloudClientRepoUserPermissionsFuncCall{v0, v1, v2, v3, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the RepoUserPermissions
+// method of the parent MockBitbucketCloudClient instance is invoked and the
+// hook queue is empty.
+func (f *BitbucketCloudClientRepoUserPermissionsFunc) SetDefaultHook(hook func(context.Context, *bitbucketcloud.PageToken, string, string) ([]*bitbucketcloud.RepoPermission, *bitbucketcloud.PageToken, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// RepoUserPermissions method of the parent MockBitbucketCloudClient
+// instance invokes the hook at the front of the queue and discards it.
+// After the queue is empty, the default hook function is invoked for any
+// future action.
+func (f *BitbucketCloudClientRepoUserPermissionsFunc) PushHook(hook func(context.Context, *bitbucketcloud.PageToken, string, string) ([]*bitbucketcloud.RepoPermission, *bitbucketcloud.PageToken, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *BitbucketCloudClientRepoUserPermissionsFunc) SetDefaultReturn(r0 []*bitbucketcloud.RepoPermission, r1 *bitbucketcloud.PageToken, r2 error) {
+	f.SetDefaultHook(func(context.Context, *bitbucketcloud.PageToken, string, string) ([]*bitbucketcloud.RepoPermission, *bitbucketcloud.PageToken, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *BitbucketCloudClientRepoUserPermissionsFunc) PushReturn(r0 []*bitbucketcloud.RepoPermission, r1 *bitbucketcloud.PageToken, r2 error) {
+	f.PushHook(func(context.Context, *bitbucketcloud.PageToken, string, string) ([]*bitbucketcloud.RepoPermission, *bitbucketcloud.PageToken, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *BitbucketCloudClientRepoUserPermissionsFunc) nextHook() func(context.Context, *bitbucketcloud.PageToken, string, string) ([]*bitbucketcloud.RepoPermission, *bitbucketcloud.PageToken, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *BitbucketCloudClientRepoUserPermissionsFunc) appendCall(r0 BitbucketCloudClientRepoUserPermissionsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// BitbucketCloudClientRepoUserPermissionsFuncCall objects describing the
+// invocations of this function.
+func (f *BitbucketCloudClientRepoUserPermissionsFunc) History() []BitbucketCloudClientRepoUserPermissionsFuncCall {
+	f.mutex.Lock()
+	history := make([]BitbucketCloudClientRepoUserPermissionsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// BitbucketCloudClientRepoUserPermissionsFuncCall is an object that
+// describes an invocation of method RepoUserPermissions on an instance of
+// MockBitbucketCloudClient.
+type BitbucketCloudClientRepoUserPermissionsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 *bitbucketcloud.PageToken
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*bitbucketcloud.RepoPermission
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 *bitbucketcloud.PageToken
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c BitbucketCloudClientRepoUserPermissionsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c BitbucketCloudClientRepoUserPermissionsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // BitbucketCloudClientReposFunc describes the behavior when the Repos
 // method of the parent MockBitbucketCloudClient instance is invoked.
 type BitbucketCloudClientReposFunc struct {
@@ -5530,3 +6080,121 @@ func (c BitbucketCloudClientWithAuthenticatorFuncCall) Args() []interface{} {
 func (c BitbucketCloudClientWithAuthenticatorFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
+
+// BitbucketCloudClientWorkspacePermissionsFunc describes the behavior when
+// the WorkspacePermissions method of the parent MockBitbucketCloudClient
+// instance is invoked.
+type BitbucketCloudClientWorkspacePermissionsFunc struct {
+	defaultHook func(context.Context, *bitbucketcloud.PageToken, string) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error)
+	hooks       []func(context.Context, *bitbucketcloud.PageToken, string) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error)
+	history     []BitbucketCloudClientWorkspacePermissionsFuncCall
+	mutex       sync.Mutex
+}
+
+// WorkspacePermissions delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockBitbucketCloudClient) WorkspacePermissions(v0 context.Context, v1 *bitbucketcloud.PageToken, v2 string) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error) {
+	r0, r1, r2 := m.WorkspacePermissionsFunc.nextHook()(v0, v1, v2)
+	m.WorkspacePermissionsFunc.appendCall(BitbucketCloudClientWorkspacePermissionsFuncCall{v0, v1, v2, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the WorkspacePermissions
+// method of the parent MockBitbucketCloudClient instance is invoked and the
+// hook queue is empty.
+func (f *BitbucketCloudClientWorkspacePermissionsFunc) SetDefaultHook(hook func(context.Context, *bitbucketcloud.PageToken, string) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// WorkspacePermissions method of the parent MockBitbucketCloudClient
+// instance invokes the hook at the front of the queue and discards it.
+// After the queue is empty, the default hook function is invoked for any
+// future action.
+func (f *BitbucketCloudClientWorkspacePermissionsFunc) PushHook(hook func(context.Context, *bitbucketcloud.PageToken, string) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *BitbucketCloudClientWorkspacePermissionsFunc) SetDefaultReturn(r0 []*bitbucketcloud.WorkspacePermission, r1 *bitbucketcloud.PageToken, r2 error) {
+	f.SetDefaultHook(func(context.Context, *bitbucketcloud.PageToken, string) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *BitbucketCloudClientWorkspacePermissionsFunc) PushReturn(r0 []*bitbucketcloud.WorkspacePermission, r1 *bitbucketcloud.PageToken, r2 error) {
+	f.PushHook(func(context.Context, *bitbucketcloud.PageToken, string) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *BitbucketCloudClientWorkspacePermissionsFunc) nextHook() func(context.Context, *bitbucketcloud.PageToken, string) ([]*bitbucketcloud.WorkspacePermission, *bitbucketcloud.PageToken, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *BitbucketCloudClientWorkspacePermissionsFunc) appendCall(r0 BitbucketCloudClientWorkspacePermissionsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// BitbucketCloudClientWorkspacePermissionsFuncCall objects describing the
+// invocations of this function.
+func (f *BitbucketCloudClientWorkspacePermissionsFunc) History() []BitbucketCloudClientWorkspacePermissionsFuncCall {
+	f.mutex.Lock()
+	history := make([]BitbucketCloudClientWorkspacePermissionsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// BitbucketCloudClientWorkspacePermissionsFuncCall is an object that
+// describes an invocation of method WorkspacePermissions on an instance of
+// MockBitbucketCloudClient.
+type BitbucketCloudClientWorkspacePermissionsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 *bitbucketcloud.PageToken
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*bitbucketcloud.WorkspacePermission
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 *bitbucketcloud.PageToken
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c BitbucketCloudClientWorkspacePermissionsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c BitbucketCloudClientWorkspacePermissionsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
@@ -326,6 +326,18 @@ func loadExternalService(ctx context.Context, s database.ExternalServiceStore, o
 			if cfg.AppPassword != "" {
 				return e, nil
 			}
+		case *schema.GiteaConnection:
+			if cfg.Token != "" {
+				return e, nil
+			}
+		case *schema.AzureDevOpsConnection:
+			if cfg.Token != "" {
+				return e, nil
+			}
+		case *schema.GerritConnection:
+			if cfg.Password != "" {
+				return e, nil
+			}
 		}
 	}
 
@@ -345,6 +357,12 @@ func buildChangesetSource(ctx context.Context, cf *httpcli.Factory, externalServ
 		return NewBitbucketServerSource(ctx, externalService, cf)
 	case extsvc.KindBitbucketCloud:
 		return NewBitbucketCloudSource(ctx, externalService, cf)
+	case extsvc.KindGitea:
+		return NewGiteaSource(ctx, externalService, cf)
+	case extsvc.KindAzureDevOps:
+		return NewAzureDevOpsSource(ctx, externalService, cf)
+	case extsvc.KindGerrit:
+		return NewGerritSource(ctx, externalService, cf)
 	default:
 		return nil, errors.Errorf("unsupported external service type %q", extsvc.KindToType(externalService.Kind))
 	}
@@ -388,7 +406,7 @@ func loadSiteCredential(ctx context.Context, s SourcerStore, repo *types.Repo) (
 // with the specific quirks per code host.
 func setOAuthTokenAuth(u *vcs.URL, extSvcType, token string) error {
 	switch extSvcType {
-	case extsvc.TypeGitHub:
+	case extsvc.TypeGitHub, extsvc.TypeGitea:
 		u.User = url.User(token)
 
 	case extsvc.TypeGitLab:
@@ -397,6 +415,12 @@ func setOAuthTokenAuth(u *vcs.URL, extSvcType, token string) error {
 	case extsvc.TypeBitbucketServer:
 		return errors.New("require username/token to push commits to BitbucketServer")
 
+	case extsvc.TypeAzureDevOps:
+		return errors.New("require username/token to push commits to Azure DevOps")
+
+	case extsvc.TypeGerrit:
+		return errors.New("require username/password to push commits to Gerrit")
+
 	default:
 		panic(fmt.Sprintf("setOAuthTokenAuth: invalid external service type %q", extSvcType))
 	}
@@ -407,10 +431,19 @@ func setOAuthTokenAuth(u *vcs.URL, extSvcType, token string) error {
 // password combination, with the specific quirks per code host.
 func setBasicAuth(u *vcs.URL, extSvcType, username, password string) error {
 	switch extSvcType {
-	case extsvc.TypeGitHub, extsvc.TypeGitLab:
+	case extsvc.TypeGitHub, extsvc.TypeGitLab, extsvc.TypeGitea:
 		return errors.New("need token to push commits to " + extSvcType)
 
-	case extsvc.TypeBitbucketServer, extsvc.TypeBitbucketCloud:
+	case extsvc.TypeBitbucketServer, extsvc.TypeBitbucketCloud, extsvc.TypeGerrit:
+		u.User = url.UserPassword(username, password)
+
+	case extsvc.TypeAzureDevOps:
+		// Azure DevOps ignores the username, and clone URLs of Azure DevOps
+		// Services have the organization in its place, so we keep it if no
+		// username is given.
+		if username == "" && u.User != nil {
+			username = u.User.Username()
+		}
 		u.User = url.UserPassword(username, password)
 
 	default:
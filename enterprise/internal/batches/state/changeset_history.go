@@ -7,6 +7,7 @@ import (
 	"github.com/inconshreveable/log15"
 
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -88,6 +89,10 @@ var RequiredEventTypesForHistory = []btypes.ChangesetEventKind{
 	btypes.ChangesetEventKindBitbucketServerUnapproved,
 	btypes.ChangesetEventKindBitbucketServerDismissed,
 	btypes.ChangesetEventKindGitLabUnapproved,
+
+	// Snapshots of the complete state.
+	btypes.ChangesetEventKindAzureDevOpsPullRequestMerged,
+	btypes.ChangesetEventKindAzureDevOpsPullRequestUpdated,
 }
 
 type changesetStatesAtTime struct {
@@ -283,6 +288,38 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 				currentReviewState = newReviewState
 				pushStates(et)
 			}
+
+		case btypes.ChangesetEventKindAzureDevOpsPullRequestMerged,
+			btypes.ChangesetEventKindAzureDevOpsPullRequestUpdated:
+			// Azure DevOps events contain the complete pull request, so each
+			// of them determines both states on its own.
+			var pr *azuredevops.PullRequest
+			switch m := e.Metadata.(type) {
+			case *azuredevops.PullRequestMergedEvent:
+				pr = &m.PullRequest
+			case *azuredevops.PullRequestUpdatedEvent:
+				pr = &m.PullRequest
+			default:
+				return nil, errors.Errorf("unexpected metadata %T for changeset event of kind %s", e.Metadata, e.Kind)
+			}
+
+			extState := currentExtState
+			// Merged and ReadOnly are final states. We can ignore everything after.
+			if currentExtState != btypes.ChangesetExternalStateMerged &&
+				currentExtState != btypes.ChangesetExternalStateReadOnly {
+				s, err := computeAzureDevOpsExternalState(pr)
+				if err != nil {
+					return nil, err
+				}
+				extState = s
+			}
+			reviewState := computeAzureDevOpsReviewState(pr.Reviewers)
+
+			if extState != currentExtState || reviewState != currentReviewState {
+				currentExtState = extState
+				currentReviewState = reviewState
+				pushStates(et)
+			}
 		}
 	}
 
@@ -11,15 +11,21 @@ import (
 
 	"github.com/sourcegraph/log"
 
+	adocs "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/azuredevops"
 	bbcs "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/bitbucketcloud"
+	gerritbatches "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/gerrit"
+	gtcs "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/gitea"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
@@ -113,6 +119,15 @@ func computeCheckState(c *btypes.Changeset, events ChangesetEvents) btypes.Chang
 
 	case *bbcs.AnnotatedPullRequest:
 		return computeBitbucketCloudBuildState(c.UpdatedAt, m, events)
+
+	case *gtcs.AnnotatedPullRequest:
+		return computeGiteaCheckState(m)
+
+	case *adocs.AnnotatedPullRequest:
+		return computeAzureDevOpsCheckState(m)
+
+	case *gerritbatches.AnnotatedChange:
+		return computeGerritCheckState(m.Change)
 	}
 
 	return btypes.ChangesetCheckStateUnknown
@@ -252,6 +267,95 @@ func parseBitbucketCloudBuildState(s bitbucketcloud.PullRequestStatusState) btyp
 	}
 }
 
+func computeGiteaCheckState(apr *gtcs.AnnotatedPullRequest) btypes.ChangesetCheckState {
+	// We don't receive webhook events from Gitea, so the statuses from the
+	// last sync are all we have.
+	if apr.Status == nil {
+		return btypes.ChangesetCheckStateUnknown
+	}
+
+	states := make([]btypes.ChangesetCheckState, 0, len(apr.Status.Statuses))
+	for _, status := range apr.Status.Statuses {
+		states = append(states, parseGiteaCommitStatusState(status.State))
+	}
+
+	return combineCheckStates(states)
+}
+
+func parseGiteaCommitStatusState(s gitea.CommitStatusState) btypes.ChangesetCheckState {
+	switch s {
+	case gitea.CommitStatusError, gitea.CommitStatusFailure:
+		return btypes.ChangesetCheckStateFailed
+	case gitea.CommitStatusPending:
+		return btypes.ChangesetCheckStatePending
+	case gitea.CommitStatusSuccess, gitea.CommitStatusWarning:
+		return btypes.ChangesetCheckStatePassed
+	default:
+		return btypes.ChangesetCheckStateUnknown
+	}
+}
+
+func computeAzureDevOpsCheckState(apr *adocs.AnnotatedPullRequest) btypes.ChangesetCheckState {
+	// Statuses are never removed from a pull request, so only the most recent
+	// status posted by each service counts.
+	latest := make(map[string]*azuredevops.Status)
+	for _, status := range apr.Statuses {
+		if prev, ok := latest[status.Key()]; !ok || prev.ID < status.ID {
+			latest[status.Key()] = status
+		}
+	}
+
+	states := make([]btypes.ChangesetCheckState, 0, len(latest))
+	for _, status := range latest {
+		states = append(states, parseAzureDevOpsStatusState(status.State))
+	}
+
+	return combineCheckStates(states)
+}
+
+func parseAzureDevOpsStatusState(s azuredevops.StatusState) btypes.ChangesetCheckState {
+	switch s {
+	case azuredevops.StatusStateError, azuredevops.StatusStateFailed:
+		return btypes.ChangesetCheckStateFailed
+	case azuredevops.StatusStateNotSet, azuredevops.StatusStatePending:
+		return btypes.ChangesetCheckStatePending
+	case azuredevops.StatusStateSucceeded, azuredevops.StatusStateNotApplicable:
+		return btypes.ChangesetCheckStatePassed
+	default:
+		return btypes.ChangesetCheckStateUnknown
+	}
+}
+
+// computeGerritCheckState returns the check state of the given change based on
+// its Verified label, which is what CI systems conventionally vote on.
+func computeGerritCheckState(change *gerrit.Change) btypes.ChangesetCheckState {
+	label, ok := change.Labels[gerrit.LabelVerified]
+	if !ok {
+		return btypes.ChangesetCheckStateUnknown
+	}
+
+	if label.Rejected != nil {
+		return btypes.ChangesetCheckStateFailed
+	}
+	if label.Approved != nil {
+		return btypes.ChangesetCheckStatePassed
+	}
+
+	states := []btypes.ChangesetCheckState{}
+	for _, vote := range label.All {
+		switch {
+		case vote.Value < 0:
+			states = append(states, btypes.ChangesetCheckStateFailed)
+		case vote.Value > 0:
+			states = append(states, btypes.ChangesetCheckStatePassed)
+		}
+	}
+	if len(states) == 0 {
+		return btypes.ChangesetCheckStatePending
+	}
+	return combineCheckStates(states)
+}
+
 func computeGitHubCheckState(lastSynced time.Time, pr *github.PullRequest, events []*btypes.ChangesetEvent) btypes.ChangesetCheckState {
 	// We should only consider the latest commit. This could be from a sync or a webhook that
 	// has occurred later
@@ -516,6 +620,27 @@ func computeSingleChangesetExternalState(c *btypes.Changeset) (s btypes.Changese
 		default:
 			return "", errors.Errorf("unknown Bitbucket Cloud pull request state: %s", m.State)
 		}
+	case *gtcs.AnnotatedPullRequest:
+		switch m.State {
+		case gitea.PullRequestStateClosed:
+			if m.Merged {
+				s = btypes.ChangesetExternalStateMerged
+			} else {
+				s = btypes.ChangesetExternalStateClosed
+			}
+		case gitea.PullRequestStateOpen:
+			s = btypes.ChangesetExternalStateOpen
+		default:
+			return "", errors.Errorf("unknown Gitea pull request state: %s", m.State)
+		}
+	case *adocs.AnnotatedPullRequest:
+		if s, err = computeAzureDevOpsExternalState(m.PullRequest); err != nil {
+			return "", err
+		}
+	case *gerritbatches.AnnotatedChange:
+		if s, err = computeGerritExternalState(m.Change); err != nil {
+			return "", err
+		}
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -585,6 +710,41 @@ func computeSingleChangesetReviewState(c *btypes.Changeset) (s btypes.ChangesetR
 			}
 		}
 
+	case *gtcs.AnnotatedPullRequest:
+		// Gitea returns every review ever submitted, so only the most recent
+		// review of each reviewer counts. Comments and reviews that haven't
+		// been submitted yet don't change the review state.
+		latest := map[int64]*gitea.PullReview{}
+		for _, r := range m.Reviews {
+			if r.User == nil || r.Dismissed {
+				continue
+			}
+			switch r.State {
+			case gitea.ReviewStateComment, gitea.ReviewStatePending:
+				continue
+			}
+			if prev, ok := latest[r.User.ID]; !ok || prev.SubmittedAt.Before(r.SubmittedAt) {
+				latest[r.User.ID] = r
+			}
+		}
+
+		for _, r := range latest {
+			switch r.State {
+			case gitea.ReviewStateApproved:
+				states[btypes.ChangesetReviewStateApproved] = true
+			case gitea.ReviewStateRequestChanges:
+				states[btypes.ChangesetReviewStateChangesRequested] = true
+			default:
+				states[btypes.ChangesetReviewStatePending] = true
+			}
+		}
+
+	case *adocs.AnnotatedPullRequest:
+		return computeAzureDevOpsReviewState(m.Reviewers), nil
+
+	case *gerritbatches.AnnotatedChange:
+		return computeGerritReviewState(m.Change), nil
+
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -592,6 +752,81 @@ func computeSingleChangesetReviewState(c *btypes.Changeset) (s btypes.ChangesetR
 	return selectReviewState(states), nil
 }
 
+// computeAzureDevOpsExternalState returns the external state of the given
+// Azure DevOps pull request.
+func computeAzureDevOpsExternalState(pr *azuredevops.PullRequest) (btypes.ChangesetExternalState, error) {
+	switch pr.Status {
+	case azuredevops.PullRequestStatusAbandoned:
+		return btypes.ChangesetExternalStateClosed, nil
+	case azuredevops.PullRequestStatusCompleted:
+		return btypes.ChangesetExternalStateMerged, nil
+	case azuredevops.PullRequestStatusActive:
+		if pr.IsDraft {
+			return btypes.ChangesetExternalStateDraft, nil
+		}
+		return btypes.ChangesetExternalStateOpen, nil
+	default:
+		return "", errors.Errorf("unknown Azure DevOps pull request status: %s", pr.Status)
+	}
+}
+
+// computeAzureDevOpsReviewState returns the review state of an Azure DevOps
+// pull request with the given reviewers. The vote of each reviewer is their
+// current review.
+func computeAzureDevOpsReviewState(reviewers []azuredevops.Reviewer) btypes.ChangesetReviewState {
+	states := map[btypes.ChangesetReviewState]bool{}
+	for _, r := range reviewers {
+		switch {
+		case r.Vote > azuredevops.VoteNoVote:
+			states[btypes.ChangesetReviewStateApproved] = true
+		case r.Vote < azuredevops.VoteNoVote:
+			states[btypes.ChangesetReviewStateChangesRequested] = true
+		default:
+			states[btypes.ChangesetReviewStatePending] = true
+		}
+	}
+	return selectReviewState(states)
+}
+
+// computeGerritExternalState returns the external state of the given Gerrit
+// change.
+func computeGerritExternalState(change *gerrit.Change) (btypes.ChangesetExternalState, error) {
+	switch change.Status {
+	case gerrit.ChangeStatusAbandoned:
+		return btypes.ChangesetExternalStateClosed, nil
+	case gerrit.ChangeStatusMerged:
+		return btypes.ChangesetExternalStateMerged, nil
+	case gerrit.ChangeStatusNew:
+		if change.WorkInProgress {
+			return btypes.ChangesetExternalStateDraft, nil
+		}
+		return btypes.ChangesetExternalStateOpen, nil
+	default:
+		return "", errors.Errorf("unknown Gerrit change status: %s", change.Status)
+	}
+}
+
+// computeGerritReviewState returns the review state of the given Gerrit change
+// based on the votes on its Code-Review label. Only the maximum vote approves
+// a change, lesser positive votes are recommendations.
+func computeGerritReviewState(change *gerrit.Change) btypes.ChangesetReviewState {
+	label := change.Labels[gerrit.LabelCodeReview]
+
+	states := map[btypes.ChangesetReviewState]bool{
+		btypes.ChangesetReviewStateApproved:         label.Approved != nil,
+		btypes.ChangesetReviewStateChangesRequested: label.Rejected != nil,
+	}
+	for _, vote := range label.All {
+		switch {
+		case vote.Value < 0:
+			states[btypes.ChangesetReviewStateChangesRequested] = true
+		default:
+			states[btypes.ChangesetReviewStatePending] = true
+		}
+	}
+	return selectReviewState(states)
+}
+
 // selectReviewState computes the single review state for a given set of
 // ChangesetReviewStates. Since a pull request, for example, can have multiple
 // reviews with different states, we need a function to determine what the
@@ -7,9 +7,15 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 
+	adocs "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/azuredevops"
+	gerritbatches "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/gerrit"
+	gtcs "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/gitea"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
@@ -407,6 +413,147 @@ func TestComputeGitLabCheckState(t *testing.T) {
 	})
 }
 
+func TestComputeGiteaCheckState(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		status *gitea.CombinedStatus
+		want   btypes.ChangesetCheckState
+	}{
+		"no status": {
+			status: nil,
+			want:   btypes.ChangesetCheckStateUnknown,
+		},
+		"no statuses": {
+			status: &gitea.CombinedStatus{},
+			want:   btypes.ChangesetCheckStateUnknown,
+		},
+		"all successful": {
+			status: &gitea.CombinedStatus{Statuses: []*gitea.CommitStatus{
+				{State: gitea.CommitStatusSuccess},
+				{State: gitea.CommitStatusWarning},
+			}},
+			want: btypes.ChangesetCheckStatePassed,
+		},
+		"one pending": {
+			status: &gitea.CombinedStatus{Statuses: []*gitea.CommitStatus{
+				{State: gitea.CommitStatusSuccess},
+				{State: gitea.CommitStatusPending},
+			}},
+			want: btypes.ChangesetCheckStatePending,
+		},
+		"one failed": {
+			status: &gitea.CombinedStatus{Statuses: []*gitea.CommitStatus{
+				{State: gitea.CommitStatusSuccess},
+				{State: gitea.CommitStatusError},
+			}},
+			want: btypes.ChangesetCheckStateFailed,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			have := computeGiteaCheckState(&gtcs.AnnotatedPullRequest{
+				PullRequest: &gitea.PullRequest{},
+				Status:      tc.status,
+			})
+			if have != tc.want {
+				t.Errorf("unexpected check state: have %s; want %s", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestComputeAzureDevOpsCheckState(t *testing.T) {
+	t.Parallel()
+
+	build := azuredevops.StatusContext{Genre: "ci", Name: "build"}
+	lint := azuredevops.StatusContext{Genre: "ci", Name: "lint"}
+
+	for name, tc := range map[string]struct {
+		statuses []*azuredevops.Status
+		want     btypes.ChangesetCheckState
+	}{
+		"no statuses": {
+			statuses: []*azuredevops.Status{},
+			want:     btypes.ChangesetCheckStateUnknown,
+		},
+		"all successful": {
+			statuses: []*azuredevops.Status{
+				{ID: 1, Context: build, State: azuredevops.StatusStateSucceeded},
+				{ID: 2, Context: lint, State: azuredevops.StatusStateNotApplicable},
+			},
+			want: btypes.ChangesetCheckStatePassed,
+		},
+		"one pending": {
+			statuses: []*azuredevops.Status{
+				{ID: 1, Context: build, State: azuredevops.StatusStateSucceeded},
+				{ID: 2, Context: lint, State: azuredevops.StatusStatePending},
+			},
+			want: btypes.ChangesetCheckStatePending,
+		},
+		"one failed": {
+			statuses: []*azuredevops.Status{
+				{ID: 1, Context: build, State: azuredevops.StatusStateSucceeded},
+				{ID: 2, Context: lint, State: azuredevops.StatusStateError},
+			},
+			want: btypes.ChangesetCheckStateFailed,
+		},
+		"failure superseded": {
+			statuses: []*azuredevops.Status{
+				{ID: 3, Context: build, State: azuredevops.StatusStateSucceeded},
+				{ID: 1, Context: build, State: azuredevops.StatusStateFailed},
+			},
+			want: btypes.ChangesetCheckStatePassed,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			have := computeAzureDevOpsCheckState(&adocs.AnnotatedPullRequest{
+				PullRequest: &azuredevops.PullRequest{},
+				Statuses:    tc.statuses,
+			})
+			if have != tc.want {
+				t.Errorf("unexpected check state: have %s; want %s", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestComputeGerritCheckState(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		labels map[string]gerrit.LabelInfo
+		want   btypes.ChangesetCheckState
+	}{
+		"no Verified label": {
+			labels: map[string]gerrit.LabelInfo{gerrit.LabelCodeReview: {}},
+			want:   btypes.ChangesetCheckStateUnknown,
+		},
+		"no votes": {
+			labels: map[string]gerrit.LabelInfo{gerrit.LabelVerified: {All: []gerrit.ApprovalInfo{{Value: 0}}}},
+			want:   btypes.ChangesetCheckStatePending,
+		},
+		"approved": {
+			labels: map[string]gerrit.LabelInfo{gerrit.LabelVerified: {Approved: &gerrit.Account{ID: 1}}},
+			want:   btypes.ChangesetCheckStatePassed,
+		},
+		"rejected": {
+			labels: map[string]gerrit.LabelInfo{gerrit.LabelVerified: {Rejected: &gerrit.Account{ID: 1}}},
+			want:   btypes.ChangesetCheckStateFailed,
+		},
+		"mixed votes": {
+			labels: map[string]gerrit.LabelInfo{gerrit.LabelVerified: {All: []gerrit.ApprovalInfo{{Value: 1}, {Value: -1}}}},
+			want:   btypes.ChangesetCheckStateFailed,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			have := computeGerritCheckState(&gerrit.Change{Labels: tc.labels})
+			if have != tc.want {
+				t.Errorf("unexpected check state: have %s; want %s", have, tc.want)
+			}
+		})
+	}
+}
+
 func TestComputeReviewState(t *testing.T) {
 	t.Parallel()
 
@@ -536,6 +683,91 @@ func TestComputeReviewState(t *testing.T) {
 			},
 			want: btypes.ChangesetReviewStateChangesRequested,
 		},
+		{
+			name:      "gitea - no events, no reviews",
+			changeset: giteaChangeset(daysAgo(0), gitea.PullRequestStateOpen, false, nil),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetReviewStatePending,
+		},
+		{
+			name: "gitea - no events, changes requested then approved",
+			changeset: giteaChangeset(daysAgo(0), gitea.PullRequestStateOpen, false, []*gitea.PullReview{
+				{User: &gitea.User{ID: 1}, State: gitea.ReviewStateRequestChanges, SubmittedAt: daysAgo(3)},
+				{User: &gitea.User{ID: 1}, State: gitea.ReviewStateApproved, SubmittedAt: daysAgo(2)},
+				{User: &gitea.User{ID: 2}, State: gitea.ReviewStateComment, SubmittedAt: daysAgo(1)},
+			}),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStateApproved,
+		},
+		{
+			name: "gitea - no events, approved and changes requested",
+			changeset: giteaChangeset(daysAgo(0), gitea.PullRequestStateOpen, false, []*gitea.PullReview{
+				{User: &gitea.User{ID: 1}, State: gitea.ReviewStateApproved, SubmittedAt: daysAgo(2)},
+				{User: &gitea.User{ID: 2}, State: gitea.ReviewStateRequestChanges, SubmittedAt: daysAgo(1)},
+			}),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStateChangesRequested,
+		},
+		{
+			name: "gitea - no events, dismissed review",
+			changeset: giteaChangeset(daysAgo(0), gitea.PullRequestStateOpen, false, []*gitea.PullReview{
+				{User: &gitea.User{ID: 1}, State: gitea.ReviewStateRequestChanges, SubmittedAt: daysAgo(1), Dismissed: true},
+			}),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStatePending,
+		},
+		{
+			name:      "azure devops - no events, no votes",
+			changeset: azureDevOpsChangeset(daysAgo(0), azuredevops.PullRequestStatusActive, false, []azuredevops.Reviewer{{Vote: azuredevops.VoteNoVote}}),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetReviewStatePending,
+		},
+		{
+			name: "azure devops - no events, approved",
+			changeset: azureDevOpsChangeset(daysAgo(0), azuredevops.PullRequestStatusActive, false, []azuredevops.Reviewer{
+				{Vote: azuredevops.VoteApprovedWithSuggestions},
+				{Vote: azuredevops.VoteNoVote},
+			}),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStateApproved,
+		},
+		{
+			name: "azure devops - no events, approved and waiting for author",
+			changeset: azureDevOpsChangeset(daysAgo(0), azuredevops.PullRequestStatusActive, false, []azuredevops.Reviewer{
+				{Vote: azuredevops.VoteApproved},
+				{Vote: azuredevops.VoteWaitingForAuthor},
+			}),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStateChangesRequested,
+		},
+		{
+			name: "gerrit - no events, recommended",
+			changeset: gerritChangeset(daysAgo(0), gerrit.ChangeStatusNew, false, gerrit.LabelInfo{
+				Recommended: &gerrit.Account{ID: 1},
+				All:         []gerrit.ApprovalInfo{{Value: 1}},
+			}),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStatePending,
+		},
+		{
+			name: "gerrit - no events, approved",
+			changeset: gerritChangeset(daysAgo(0), gerrit.ChangeStatusNew, false, gerrit.LabelInfo{
+				Approved: &gerrit.Account{ID: 1},
+				All:      []gerrit.ApprovalInfo{{Value: 2}, {Value: 0}},
+			}),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStateApproved,
+		},
+		{
+			name: "gerrit - no events, approved and disliked",
+			changeset: gerritChangeset(daysAgo(0), gerrit.ChangeStatusNew, false, gerrit.LabelInfo{
+				Approved: &gerrit.Account{ID: 1},
+				Disliked: &gerrit.Account{ID: 2},
+				All:      []gerrit.ApprovalInfo{{Value: 2}, {Value: -1}},
+			}),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStateChangesRequested,
+		},
 	}
 
 	for i, tc := range tests {
@@ -744,6 +976,72 @@ func TestComputeExternalState(t *testing.T) {
 			},
 			want: btypes.ChangesetExternalStateReadOnly,
 		},
+		{
+			name:      "gitea - open",
+			changeset: giteaChangeset(daysAgo(10), gitea.PullRequestStateOpen, false, nil),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateOpen,
+		},
+		{
+			name:      "gitea - closed",
+			changeset: giteaChangeset(daysAgo(10), gitea.PullRequestStateClosed, false, nil),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateClosed,
+		},
+		{
+			name:      "gitea - merged",
+			changeset: giteaChangeset(daysAgo(10), gitea.PullRequestStateClosed, true, nil),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateMerged,
+		},
+		{
+			name:      "azure devops - active",
+			changeset: azureDevOpsChangeset(daysAgo(10), azuredevops.PullRequestStatusActive, false, nil),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateOpen,
+		},
+		{
+			name:      "azure devops - draft",
+			changeset: azureDevOpsChangeset(daysAgo(10), azuredevops.PullRequestStatusActive, true, nil),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateDraft,
+		},
+		{
+			name:      "azure devops - abandoned",
+			changeset: azureDevOpsChangeset(daysAgo(10), azuredevops.PullRequestStatusAbandoned, false, nil),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateClosed,
+		},
+		{
+			name:      "azure devops - completed",
+			changeset: azureDevOpsChangeset(daysAgo(10), azuredevops.PullRequestStatusCompleted, false, nil),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateMerged,
+		},
+		{
+			name:      "gerrit - new",
+			changeset: gerritChangeset(daysAgo(10), gerrit.ChangeStatusNew, false, gerrit.LabelInfo{}),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateOpen,
+		},
+		{
+			name:      "gerrit - work in progress",
+			changeset: gerritChangeset(daysAgo(10), gerrit.ChangeStatusNew, true, gerrit.LabelInfo{}),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateDraft,
+		},
+		{
+			name:      "gerrit - abandoned",
+			changeset: gerritChangeset(daysAgo(10), gerrit.ChangeStatusAbandoned, false, gerrit.LabelInfo{}),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateClosed,
+		},
+		{
+			name:      "gerrit - merged",
+			changeset: gerritChangeset(daysAgo(10), gerrit.ChangeStatusMerged, false, gerrit.LabelInfo{}),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateMerged,
+		},
 	}
 
 	for i, tc := range tests {
@@ -900,6 +1198,48 @@ func gitLabChangeset(updatedAt time.Time, state gitlab.MergeRequestState, notes
 	}
 }
 
+func giteaChangeset(updatedAt time.Time, state gitea.PullRequestState, merged bool, reviews []*gitea.PullReview) *btypes.Changeset {
+	return &btypes.Changeset{
+		ExternalServiceType: extsvc.TypeGitea,
+		UpdatedAt:           updatedAt,
+		Metadata: &gtcs.AnnotatedPullRequest{
+			PullRequest: &gitea.PullRequest{
+				State:  state,
+				Merged: merged,
+			},
+			Reviews: reviews,
+		},
+	}
+}
+
+func azureDevOpsChangeset(updatedAt time.Time, status azuredevops.PullRequestStatus, draft bool, reviewers []azuredevops.Reviewer) *btypes.Changeset {
+	return &btypes.Changeset{
+		ExternalServiceType: extsvc.TypeAzureDevOps,
+		UpdatedAt:           updatedAt,
+		Metadata: &adocs.AnnotatedPullRequest{
+			PullRequest: &azuredevops.PullRequest{
+				Status:    status,
+				IsDraft:   draft,
+				Reviewers: reviewers,
+			},
+		},
+	}
+}
+
+func gerritChangeset(updatedAt time.Time, status gerrit.ChangeStatus, wip bool, codeReview gerrit.LabelInfo) *btypes.Changeset {
+	return &btypes.Changeset{
+		ExternalServiceType: extsvc.TypeGerrit,
+		UpdatedAt:           updatedAt,
+		Metadata: &gerritbatches.AnnotatedChange{
+			Change: &gerrit.Change{
+				Status:         status,
+				WorkInProgress: wip,
+				Labels:         map[string]gerrit.LabelInfo{gerrit.LabelCodeReview: codeReview},
+			},
+		},
+	}
+}
+
 func setDeletedAt(c *btypes.Changeset, deletedAt time.Time) *btypes.Changeset {
 	c.ExternalDeletedAt = deletedAt
 	return c
@@ -8,9 +8,13 @@ import (
 	"github.com/inconshreveable/log15"
 	"github.com/sourcegraph/go-diff/diff"
 
+	adocs "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/azuredevops"
 	bbcs "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/bitbucketcloud"
+	gerritbatches "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/gerrit"
+	gtcs "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
@@ -426,6 +430,32 @@ func (c *Changeset) SetMetadata(meta any) error {
 		} else {
 			c.ExternalForkNamespace = ""
 		}
+	case *gtcs.AnnotatedPullRequest:
+		c.Metadata = pr
+		c.ExternalID = strconv.FormatInt(pr.Number, 10)
+		c.ExternalServiceType = extsvc.TypeGitea
+		c.ExternalBranch = gitdomain.EnsureRefPrefix(pr.Head.Ref)
+		c.ExternalUpdatedAt = pr.UpdatedAt
+
+		if pr.Head.RepoID != pr.Base.RepoID && pr.Head.Repo != nil {
+			c.ExternalForkNamespace = pr.Head.Repo.Owner.Login
+		} else {
+			c.ExternalForkNamespace = ""
+		}
+	case *adocs.AnnotatedPullRequest:
+		c.Metadata = pr
+		c.ExternalID = strconv.FormatInt(pr.PullRequestID, 10)
+		c.ExternalServiceType = extsvc.TypeAzureDevOps
+		c.ExternalBranch = gitdomain.EnsureRefPrefix(pr.SourceRefName)
+		c.ExternalUpdatedAt = pr.UpdatedAt()
+		c.ExternalForkNamespace = ""
+	case *gerritbatches.AnnotatedChange:
+		c.Metadata = pr
+		c.ExternalID = pr.ChangeID
+		c.ExternalServiceType = extsvc.TypeGerrit
+		c.ExternalBranch = pr.HeadRef()
+		c.ExternalUpdatedAt = pr.Updated.Time
+		c.ExternalForkNamespace = ""
 	default:
 		return errors.New("unknown changeset type")
 	}
@@ -453,6 +483,12 @@ func (c *Changeset) Title() (string, error) {
 		return m.Title, nil
 	case *bbcs.AnnotatedPullRequest:
 		return m.Title, nil
+	case *gtcs.AnnotatedPullRequest:
+		return m.Title, nil
+	case *adocs.AnnotatedPullRequest:
+		return m.Title, nil
+	case *gerritbatches.AnnotatedChange:
+		return m.Subject, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -472,6 +508,18 @@ func (c *Changeset) AuthorName() (string, error) {
 		return m.Author.Username, nil
 	case *bbcs.AnnotatedPullRequest:
 		return m.Author.Username, nil
+	case *gtcs.AnnotatedPullRequest:
+		if m.User == nil {
+			return "", nil
+		}
+		return m.User.Login, nil
+	case *adocs.AnnotatedPullRequest:
+		return m.CreatedBy.DisplayName, nil
+	case *gerritbatches.AnnotatedChange:
+		if m.Owner.Username != "" {
+			return m.Owner.Username, nil
+		}
+		return m.Owner.Name, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -499,6 +547,19 @@ func (c *Changeset) AuthorEmail() (string, error) {
 		// Bitbucket Cloud does not provide the e-mail of the author under any
 		// circumstances.
 		return "", nil
+	case *gtcs.AnnotatedPullRequest:
+		if m.User == nil {
+			return "", nil
+		}
+		return m.User.Email, nil
+	case *adocs.AnnotatedPullRequest:
+		// The unique name is only an email address on Azure DevOps Services.
+		if strings.Contains(m.CreatedBy.UniqueName, "@") {
+			return m.CreatedBy.UniqueName, nil
+		}
+		return "", nil
+	case *gerritbatches.AnnotatedChange:
+		return m.Owner.Email, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -517,6 +578,12 @@ func (c *Changeset) ExternalCreatedAt() time.Time {
 		return m.CreatedAt.Time
 	case *bbcs.AnnotatedPullRequest:
 		return m.CreatedOn
+	case *gtcs.AnnotatedPullRequest:
+		return m.CreatedAt
+	case *adocs.AnnotatedPullRequest:
+		return m.CreationDate
+	case *gerritbatches.AnnotatedChange:
+		return m.Created.Time
 	default:
 		return time.Time{}
 	}
@@ -533,6 +600,12 @@ func (c *Changeset) Body() (string, error) {
 		return m.Description, nil
 	case *bbcs.AnnotatedPullRequest:
 		return m.Rendered.Description.Raw, nil
+	case *gtcs.AnnotatedPullRequest:
+		return m.Body, nil
+	case *adocs.AnnotatedPullRequest:
+		return m.Description, nil
+	case *gerritbatches.AnnotatedChange:
+		return m.Body(), nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -578,6 +651,12 @@ func (c *Changeset) URL() (s string, err error) {
 		// pull request ID, but since the link _should_ be there, we'll error
 		// instead.
 		return "", errors.New("Bitbucket Cloud pull request does not have a html link")
+	case *gtcs.AnnotatedPullRequest:
+		return m.HTMLURL, nil
+	case *adocs.AnnotatedPullRequest:
+		return m.WebURL, nil
+	case *gerritbatches.AnnotatedChange:
+		return m.URL(), nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -767,6 +846,15 @@ func (c *Changeset) HeadRefOid() (string, error) {
 		return m.DiffRefs.HeadSHA, nil
 	case *bbcs.AnnotatedPullRequest:
 		return m.Source.Commit.Hash, nil
+	case *gtcs.AnnotatedPullRequest:
+		return m.Head.Sha, nil
+	case *adocs.AnnotatedPullRequest:
+		if m.LastMergeSourceCommit == nil {
+			return "", nil
+		}
+		return m.LastMergeSourceCommit.CommitID, nil
+	case *gerritbatches.AnnotatedChange:
+		return m.CurrentRevision, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -784,6 +872,12 @@ func (c *Changeset) HeadRef() (string, error) {
 		return "refs/heads/" + m.SourceBranch, nil
 	case *bbcs.AnnotatedPullRequest:
 		return "refs/heads/" + m.Source.Branch.Name, nil
+	case *gtcs.AnnotatedPullRequest:
+		return "refs/heads/" + m.Head.Ref, nil
+	case *adocs.AnnotatedPullRequest:
+		return m.SourceRefName, nil
+	case *gerritbatches.AnnotatedChange:
+		return m.HeadRef(), nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -802,6 +896,15 @@ func (c *Changeset) BaseRefOid() (string, error) {
 		return m.DiffRefs.BaseSHA, nil
 	case *bbcs.AnnotatedPullRequest:
 		return m.Destination.Commit.Hash, nil
+	case *gtcs.AnnotatedPullRequest:
+		return m.Base.Sha, nil
+	case *adocs.AnnotatedPullRequest:
+		if m.LastMergeTargetCommit == nil {
+			return "", nil
+		}
+		return m.LastMergeTargetCommit.CommitID, nil
+	case *gerritbatches.AnnotatedChange:
+		return m.BaseRefOid(), nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -819,6 +922,12 @@ func (c *Changeset) BaseRef() (string, error) {
 		return "refs/heads/" + m.TargetBranch, nil
 	case *bbcs.AnnotatedPullRequest:
 		return "refs/heads/" + m.Destination.Branch.Name, nil
+	case *gtcs.AnnotatedPullRequest:
+		return "refs/heads/" + m.Base.Ref, nil
+	case *adocs.AnnotatedPullRequest:
+		return m.TargetRefName, nil
+	case *gerritbatches.AnnotatedChange:
+		return "refs/heads/" + m.Branch, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -1136,6 +1245,13 @@ func ChangesetEventKindFor(e any) (ChangesetEventKind, error) {
 		return ChangesetEventKindBitbucketCloudRepoCommitStatusCreated, nil
 	case *bitbucketcloud.RepoCommitStatusUpdatedEvent:
 		return ChangesetEventKindBitbucketCloudRepoCommitStatusUpdated, nil
+
+	case *azuredevops.PullRequestUpdatedEvent:
+		return ChangesetEventKindAzureDevOpsPullRequestUpdated, nil
+	case *azuredevops.PullRequestMergedEvent:
+		return ChangesetEventKindAzureDevOpsPullRequestMerged, nil
+	case *azuredevops.PullRequestCommentEvent:
+		return ChangesetEventKindAzureDevOpsPullRequestCommented, nil
 	}
 
 	return ChangesetEventKindInvalid, errors.Errorf("unknown changeset event kind for %T", e)
@@ -1145,6 +1261,15 @@ func ChangesetEventKindFor(e any) (ChangesetEventKind, error) {
 // ChangesetEventKind.
 func NewChangesetEventMetadata(k ChangesetEventKind) (any, error) {
 	switch {
+	case strings.HasPrefix(string(k), "azuredevops"):
+		switch k {
+		case ChangesetEventKindAzureDevOpsPullRequestUpdated:
+			return new(azuredevops.PullRequestUpdatedEvent), nil
+		case ChangesetEventKindAzureDevOpsPullRequestMerged:
+			return new(azuredevops.PullRequestMergedEvent), nil
+		case ChangesetEventKindAzureDevOpsPullRequestCommented:
+			return new(azuredevops.PullRequestCommentEvent), nil
+		}
 	case strings.HasPrefix(string(k), "bitbucketcloud"):
 		switch k {
 		case ChangesetEventKindBitbucketCloudApproved,
@@ -8,6 +8,7 @@ import (
 
 	"github.com/inconshreveable/log15"
 
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
@@ -102,6 +103,13 @@ const (
 	ChangesetEventKindBitbucketCloudRepoCommitStatusCreated          ChangesetEventKind = "bitbucketcloud:repo:commit_status_created"          // RepoCommitStatusCreatedEvent
 	ChangesetEventKindBitbucketCloudRepoCommitStatusUpdated          ChangesetEventKind = "bitbucketcloud:repo:commit_status_updated"          // RepoCommitStatusUpdatedEvent
 
+	// These changeset events are created in response to webhooks received from
+	// Azure DevOps. The pull request events contain the complete state of the
+	// pull request at the time of the event.
+	ChangesetEventKindAzureDevOpsPullRequestUpdated   ChangesetEventKind = "azuredevops:pullrequest:updated"   // PullRequestUpdatedEvent
+	ChangesetEventKindAzureDevOpsPullRequestMerged    ChangesetEventKind = "azuredevops:pullrequest:merged"    // PullRequestMergedEvent
+	ChangesetEventKindAzureDevOpsPullRequestCommented ChangesetEventKind = "azuredevops:pullrequest:commented" // PullRequestCommentEvent
+
 	ChangesetEventKindInvalid ChangesetEventKind = "invalid"
 )
 
@@ -316,6 +324,12 @@ func (e *ChangesetEvent) Timestamp() time.Time {
 		t = ev.CommitStatus.CreatedOn
 	case *bitbucketcloud.RepoCommitStatusUpdatedEvent:
 		t = ev.CommitStatus.UpdatedOn
+	case *azuredevops.PullRequestUpdatedEvent:
+		t = ev.CreatedDate
+	case *azuredevops.PullRequestMergedEvent:
+		t = ev.CreatedDate
+	case *azuredevops.PullRequestCommentEvent:
+		t = ev.Resource.Comment.LastUpdatedDate
 	}
 
 	return t
@@ -854,6 +868,18 @@ func (e *ChangesetEvent) Update(o *ChangesetEvent) error {
 		o := o.Metadata.(*bitbucketcloud.RepoCommitStatusUpdatedEvent)
 		*e = *o
 
+	case *azuredevops.PullRequestUpdatedEvent:
+		o := o.Metadata.(*azuredevops.PullRequestUpdatedEvent)
+		*e = *o
+
+	case *azuredevops.PullRequestMergedEvent:
+		o := o.Metadata.(*azuredevops.PullRequestMergedEvent)
+		*e = *o
+
+	case *azuredevops.PullRequestCommentEvent:
+		o := o.Metadata.(*azuredevops.PullRequestCommentEvent)
+		*e = *o
+
 	default:
 		return errors.Errorf("unknown changeset event metadata %T", e)
 	}
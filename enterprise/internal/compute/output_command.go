@@ -84,6 +84,22 @@ func resultChunks(r result.Match, kind string, onlyPath bool) []string {
 		}
 
 		return chunks
+	case *result.CompareMatch:
+		if onlyPath {
+			return []string{m.Path}
+		}
+
+		// Compute over the lines which differ between the revisions.
+		chunks := make([]string, 0, len(m.Added)+len(m.Removed))
+		for _, l := range m.Added {
+			chunks = append(chunks, l.Content)
+		}
+		for _, l := range m.Removed {
+			chunks = append(chunks, l.Content)
+		}
+		return chunks
+	case *result.OwnerMatch:
+		return []string{m.String()}
 	case *result.CommitDiffMatch:
 		var sb strings.Builder
 		for _, h := range m.Hunks {
@@ -85,7 +85,7 @@ func commitMatch(content string) result.Match {
 func TestRun(t *testing.T) {
 	test := func(q string, m result.Match) string {
 		defer gitserver.ResetMocks()
-		computeQuery, _ := Parse(q)
+		computeQuery, _ := Parse(q, nil)
 		res, err := computeQuery.Command.Run(context.Background(), database.NewMockDB(), m)
 		if err != nil {
 			return err.Error()
@@ -126,6 +126,36 @@ func TestRun(t *testing.T) {
 		"test\nstring\n").
 		Equal(t, test(`content:output((\b\w+\b) -> $1)`, fileMatch("test", "string")))
 
+	autogold.Want(
+		"template substitution regexp with compare match",
+		"my/awesome/path.ml: (2)\nmy/awesome/path.ml: (1)\n").
+		Equal(t, test(`content:output((\d) -> $path: ($1))`, &result.CompareMatch{
+			Repo:    types.MinimalRepo{Name: "my/awesome/repo"},
+			Path:    "my/awesome/path.ml",
+			Added:   []result.CompareLine{{LineNumber: 1, Content: "b 2"}},
+			Removed: []result.CompareLine{{LineNumber: 1, Content: "a 1"}},
+		}))
+
+	autogold.Want(
+		"owner match",
+		"@sourcegraph/search\n").
+		Equal(t, test(`content:output((.+) -> $1)`, &result.OwnerMatch{Handle: "sourcegraph/search", Type: result.OwnerTypeTeam}))
+
+	autogold.Want(
+		"template substitution regexp with compare match",
+		"my/awesome/path.ml: (2)\nmy/awesome/path.ml: (1)\n").
+		Equal(t, test(`content:output((\d) -> $path: ($1))`, &result.CompareMatch{
+			Repo:    types.MinimalRepo{Name: "my/awesome/repo"},
+			Path:    "my/awesome/path.ml",
+			Added:   []result.CompareLine{{LineNumber: 1, Content: "b 2"}},
+			Removed: []result.CompareLine{{LineNumber: 1, Content: "a 1"}},
+		}))
+
+	autogold.Want(
+		"owner match",
+		"@sourcegraph/search\n").
+		Equal(t, test(`content:output((.+) -> $1)`, &result.OwnerMatch{Handle: "sourcegraph/search", Type: result.OwnerTypeTeam}))
+
 	// If we are not on CI skip the test if comby is not installed.
 	if os.Getenv("CI") == "" && !comby.Exists() {
 		t.Skip("comby is not installed on the PATH. Try running 'bash <(curl -sL get.comby.dev)'.")
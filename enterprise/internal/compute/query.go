@@ -283,8 +283,10 @@ func toComputeQuery(plan query.Plan) (*Query, error) {
 	}, nil
 }
 
-func Parse(q string) (*Query, error) {
-	parseTree, err := query.ParseRegexp(q)
+// Parse parses a compute query, expanding references to the given query
+// macros in its search parameters.
+func Parse(q string, macros map[string]string) (*Query, error) {
+	parseTree, err := query.ParseWithMacros(q, query.SearchTypeRegex, macros)
 	if err != nil {
 		return nil, err
 	}
@@ -297,7 +299,7 @@ func Parse(q string) (*Query, error) {
 		return nil, errors.New("compute endpoint cannot currently support expressions in patterns containing 'and', 'or', 'not' (or negation) right now!")
 	}
 
-	plan, err := query.Pipeline(query.InitRegexp(q))
+	plan, err := query.Pipeline(query.InitWithMacros(q, query.SearchTypeRegex, macros))
 	if err != nil {
 		return nil, err
 	}
@@ -8,7 +8,7 @@ import (
 
 func TestParse(t *testing.T) {
 	test := func(input string) string {
-		q, err := Parse(input)
+		q, err := Parse(input, nil)
 		if err != nil {
 			return err.Error()
 		}
@@ -53,8 +53,10 @@ func TestParse(t *testing.T) {
 }
 
 func TestToSearchQuery(t *testing.T) {
+	macros := map[string]string{"scope": "repo:foo file:bar"}
+
 	test := func(input string) string {
-		q, err := Parse(input)
+		q, err := Parse(input, macros)
 		if err != nil {
 			return err.Error()
 		}
@@ -73,4 +75,8 @@ func TestToSearchQuery(t *testing.T) {
 	autogold.Want("allow expressions on search parameters (filters)",
 		"((repo:foo file:bar lang:go OR repo:foo file:bar lang:text) AND colarado)").
 		Equal(t, test("content:replace(colarado -> colorodo) repo:foo file:bar (lang:go or lang:text)"))
+
+	autogold.Want("expand macros in search parameters",
+		"(repo:foo file:bar AND carolado)").
+		Equal(t, test("@scope carolado"))
 }
@@ -278,6 +278,19 @@ func NewMetaEnvironment(r result.Match, content string) *MetaEnvironment {
 			Content: content,
 			Lang:    lang,
 		}
+	case *result.CompareMatch:
+		lang, _ := enry.GetLanguageByExtension(m.Path)
+		return &MetaEnvironment{
+			Repo:    string(m.Repo.Name),
+			Path:    m.Path,
+			Commit:  string(m.HeadCommit),
+			Content: content,
+			Lang:    lang,
+		}
+	case *result.OwnerMatch:
+		return &MetaEnvironment{
+			Content: content,
+		}
 	case *result.CommitMatch:
 		return &MetaEnvironment{
 			Repo:    string(m.Repo.Name),
@@ -141,8 +141,8 @@ func countAuthor(r result.Match) (map[MatchKey]int, error) {
 	return nil, nil
 }
 
-func countCaptureGroupsFunc(querystring string) (AggregationCountFunc, error) {
-	pattern, err := getCasedPattern(querystring)
+func countCaptureGroupsFunc(querystring string, macros map[string]string) (AggregationCountFunc, error) {
+	pattern, err := getCasedPattern(querystring, macros)
 	if err != nil {
 		return nil, errors.Wrap(err, "getCasedPattern")
 	}
@@ -174,7 +174,7 @@ func countCaptureGroupsFunc(querystring string) (AggregationCountFunc, error) {
 	}, nil
 }
 
-func GetCountFuncForMode(query, patternType string, mode types.SearchAggregationMode) (AggregationCountFunc, error) {
+func GetCountFuncForMode(query, patternType string, macros map[string]string, mode types.SearchAggregationMode) (AggregationCountFunc, error) {
 	modeCountTypes := map[types.SearchAggregationMode]AggregationCountFunc{
 		types.REPO_AGGREGATION_MODE:   countRepo,
 		types.PATH_AGGREGATION_MODE:   countPath,
@@ -182,7 +182,7 @@ func GetCountFuncForMode(query, patternType string, mode types.SearchAggregation
 	}
 
 	if mode == types.CAPTURE_GROUP_AGGREGATION_MODE {
-		captureGroupsCount, err := countCaptureGroupsFunc(query)
+		captureGroupsCount, err := countCaptureGroupsFunc(query, macros)
 		if err != nil {
 			return nil, err
 		}
@@ -249,8 +249,8 @@ func (r *searchAggregationResults) Send(event streaming.SearchEvent) {
 
 // Pulls the pattern out of the querystring
 // If the query contains a case:no field, we need to wrap the pattern in some additional regex.
-func getCasedPattern(querystring string) (MatchPattern, error) {
-	query, err := querybuilder.ParseQuery(querystring, "regexp")
+func getCasedPattern(querystring string, macros map[string]string) (MatchPattern, error) {
+	query, err := querybuilder.ParseQuery(querystring, "regexp", macros)
 	if err != nil {
 		return nil, errors.Wrap(err, "ParseQuery")
 	}
@@ -175,7 +175,7 @@ func TestRepoAggregation(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.want.Name(), func(t *testing.T) {
 			aggregator := testAggregator{results: make(map[string]int)}
-			countFunc, _ := GetCountFuncForMode("", "", tc.mode)
+			countFunc, _ := GetCountFuncForMode("", "", nil, tc.mode)
 			sra := newTestSearchResultsAggregator(aggregator.AddResult, countFunc)
 			sra.Send(tc.searchEvent)
 			tc.want.Equal(t, aggregator.results)
@@ -227,7 +227,7 @@ func TestAuthorAggregation(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.want.Name(), func(t *testing.T) {
 			aggregator := testAggregator{results: make(map[string]int)}
-			countFunc, _ := GetCountFuncForMode("", "", tc.mode)
+			countFunc, _ := GetCountFuncForMode("", "", nil, tc.mode)
 			sra := newTestSearchResultsAggregator(aggregator.AddResult, countFunc)
 			sra.Send(tc.searchEvent)
 			tc.want.Equal(t, aggregator.results)
@@ -324,7 +324,7 @@ func TestPathAggregation(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.want.Name(), func(t *testing.T) {
 			aggregator := testAggregator{results: make(map[string]int)}
-			countFunc, _ := GetCountFuncForMode("", "", tc.mode)
+			countFunc, _ := GetCountFuncForMode("", "", nil, tc.mode)
 			sra := newTestSearchResultsAggregator(aggregator.AddResult, countFunc)
 			sra.Send(tc.searchEvent)
 			tc.want.Equal(t, aggregator.results)
@@ -419,7 +419,7 @@ func TestCaptureGroupAggregation(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.want.Name(), func(t *testing.T) {
 			aggregator := testAggregator{results: make(map[string]int)}
-			countFunc, err := GetCountFuncForMode(tc.query, "regexp", tc.mode)
+			countFunc, err := GetCountFuncForMode(tc.query, "regexp", nil, tc.mode)
 			if err != nil {
 				t.Errorf("expected test not to error, got %v", err)
 				t.FailNow()
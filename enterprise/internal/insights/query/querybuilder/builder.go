@@ -188,7 +188,9 @@ const insightsComputeCommand = "output.extra"
 // ComputeInsightCommandQuery will convert a standard Sourcegraph search query into a compute "map type" insight query. This command type will group by
 // certain fields. The original search query semantic should be preserved, although any new limitations or restrictions in Compute will apply.
 func ComputeInsightCommandQuery(query BasicQuery, mapType MapType) (ComputeInsightQuery, error) {
-	q, err := ParseComputeQuery(string(query))
+	// Insight queries run in the background without a viewer, so there are no
+	// query macros to expand.
+	q, err := ParseComputeQuery(string(query), nil)
 	if err != nil {
 		return "", err
 	}
@@ -8,14 +8,17 @@ import (
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
-func DetectSearchType(rawQuery string, patternType string) (query.SearchType, error) {
+// DetectSearchType returns the search type of rawQuery, which is patternType
+// unless the query, with macros expanded, overrides it with a patternType:
+// filter.
+func DetectSearchType(rawQuery string, patternType string, macros map[string]string) (query.SearchType, error) {
 	searchType, err := client.SearchTypeFromString(patternType)
 	if err != nil {
 		return -1, errors.Wrap(err, "client.SearchTypeFromString")
 	}
-	q, err := query.Parse(rawQuery, searchType)
+	q, err := query.ParseWithMacros(rawQuery, searchType, macros)
 	if err != nil {
-		return -1, errors.Wrap(err, "query.Parse")
+		return -1, errors.Wrap(err, "query.ParseWithMacros")
 	}
 	q = query.LowercaseFieldNames(q)
 	query.VisitField(q, searchquery.FieldPatternType, func(value string, _ bool, _ query.Annotation) {
@@ -27,20 +30,22 @@ func DetectSearchType(rawQuery string, patternType string) (query.SearchType, er
 
 }
 
-func ParseQuery(q string, patternType string) (query.Plan, error) {
-	searchType, err := DetectSearchType(q, patternType)
+// ParseQuery parses q into a query plan, expanding references to the given
+// query macros.
+func ParseQuery(q string, patternType string, macros map[string]string) (query.Plan, error) {
+	searchType, err := DetectSearchType(q, patternType, macros)
 	if err != nil {
 		return nil, errors.Wrap(err, "overrideSearchType")
 	}
-	plan, err := query.Pipeline(query.Init(q, searchType))
+	plan, err := query.Pipeline(query.InitWithMacros(q, searchType, macros))
 	if err != nil {
 		return nil, errors.Wrap(err, "query.Pipeline")
 	}
 	return plan, nil
 }
 
-func ParseComputeQuery(q string) (*compute.Query, error) {
-	computeQuery, err := compute.Parse(q)
+func ParseComputeQuery(q string, macros map[string]string) (*compute.Query, error) {
+	computeQuery, err := compute.Parse(q, macros)
 	if err != nil {
 		return nil, errors.Wrap(err, "compute.Parse")
 	}
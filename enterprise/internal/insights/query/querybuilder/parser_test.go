@@ -39,7 +39,7 @@ func TestParseQuery(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			hasFailed := false
-			_, err := ParseQuery(tc.query, "literal")
+			_, err := ParseQuery(tc.query, "literal", nil)
 			if err != nil {
 				hasFailed = true
 			}
@@ -74,7 +74,7 @@ func TestParametersFromQueryPlan(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			plan, err := ParseQuery(tc.query, "literal")
+			plan, err := ParseQuery(tc.query, "literal", nil)
 			if err != nil {
 				t.Errorf("expected valid query, got error: %v", err)
 			}
@@ -115,10 +115,17 @@ func TestDetectSearchType(t *testing.T) {
 			"lucky",
 			query.SearchTypeRegex,
 		},
+		{
+			"submit literal with patterntype in macro",
+			"test @regexp",
+			"literal",
+			query.SearchTypeRegex,
+		},
 	}
+	macros := map[string]string{"regexp": "patterntype:regexp"}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			searchType, err := DetectSearchType(tc.query, tc.submittedType)
+			searchType, err := DetectSearchType(tc.query, tc.submittedType, macros)
 			if err != nil {
 				t.Errorf("expected %d, errored: %s", tc.searchType, err.Error())
 			}
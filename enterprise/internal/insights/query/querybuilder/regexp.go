@@ -158,8 +158,8 @@ var (
 	UnsupportedPatternTypeErr = errors.New("pattern replacement is only supported for regexp patterns")
 )
 
-func NewPatternReplacer(query BasicQuery, searchType searchquery.SearchType) (PatternReplacer, error) {
-	plan, err := searchquery.Pipeline(searchquery.Init(string(query), searchType))
+func NewPatternReplacer(query BasicQuery, searchType searchquery.SearchType, macros map[string]string) (PatternReplacer, error) {
+	plan, err := searchquery.Pipeline(searchquery.InitWithMacros(string(query), searchType, macros))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse search query")
 	}
@@ -218,7 +218,7 @@ func TestReplace_Valid(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.want.Name(), func(t *testing.T) {
-			replacer, err := NewPatternReplacer(BasicQuery(test.query), test.searchType)
+			replacer, err := NewPatternReplacer(BasicQuery(test.query), test.searchType, nil)
 			require.NoError(t, err)
 
 			got, err := replacer.Replace(test.replacement)
@@ -229,19 +229,19 @@ func TestReplace_Valid(t *testing.T) {
 
 func TestReplace_Invalid(t *testing.T) {
 	t.Run("multiple patterns", func(t *testing.T) {
-		_, err := NewPatternReplacer("/replace(me)/ or asdf", query.SearchTypeStandard)
+		_, err := NewPatternReplacer("/replace(me)/ or asdf", query.SearchTypeStandard, nil)
 		require.ErrorIs(t, err, MultiplePatternErr)
 	})
 	t.Run("literal pattern", func(t *testing.T) {
-		_, err := NewPatternReplacer("asdf", query.SearchTypeStandard)
+		_, err := NewPatternReplacer("asdf", query.SearchTypeStandard, nil)
 		require.ErrorIs(t, err, UnsupportedPatternTypeErr)
 	})
 	t.Run("no pattern", func(t *testing.T) {
-		_, err := NewPatternReplacer("", query.SearchTypeRegex)
+		_, err := NewPatternReplacer("", query.SearchTypeRegex, nil)
 		require.ErrorIs(t, err, UnsupportedPatternTypeErr)
 	})
 	t.Run("filters with no pattern", func(t *testing.T) {
-		_, err := NewPatternReplacer("repo:repoA rev:3.40.0", query.SearchTypeStandard)
+		_, err := NewPatternReplacer("repo:repoA rev:3.40.0", query.SearchTypeStandard, nil)
 		require.ErrorIs(t, err, UnsupportedPatternTypeErr)
 	})
 }
@@ -46,12 +46,15 @@ type searchAggregateResolver struct {
 	baseInsightResolver
 	searchQuery string
 	patternType string
+	// macros are the query macros of the viewer, which searchQuery may
+	// refer to.
+	macros map[string]string
 }
 
 func (r *searchAggregateResolver) ModeAvailability(ctx context.Context) []graphqlbackend.AggregationModeAvailabilityResolver {
 	resolvers := []graphqlbackend.AggregationModeAvailabilityResolver{}
 	for _, mode := range types.SearchAggregationModes {
-		resolvers = append(resolvers, newAggregationModeAvailabilityResolver(r.searchQuery, r.patternType, mode))
+		resolvers = append(resolvers, newAggregationModeAvailabilityResolver(r.searchQuery, r.patternType, r.macros, mode))
 	}
 	return resolvers
 }
@@ -66,12 +69,12 @@ func (r *searchAggregateResolver) Aggregations(ctx context.Context, args graphql
 	// 5 -  Generate correct resolver pass search results if valid
 	var aggregationMode types.SearchAggregationMode
 	if args.Mode == nil {
-		aggregationMode = getDefaultAggregationMode(r.searchQuery, r.patternType)
+		aggregationMode = getDefaultAggregationMode(r.searchQuery, r.patternType, r.macros)
 	} else {
 		aggregationMode = types.SearchAggregationMode(*args.Mode)
 	}
 
-	notAvailable, err := getNotAvailableReason(r.searchQuery, r.patternType, aggregationMode)
+	notAvailable, err := getNotAvailableReason(r.searchQuery, r.patternType, r.macros, aggregationMode)
 	if notAvailable != nil {
 		return &searchAggregationResultResolver{resolver: newSearchAggregationNotAvailableResolver(*notAvailable, aggregationMode)}, nil
 	}
@@ -108,7 +111,7 @@ func (r *searchAggregateResolver) Aggregations(ctx context.Context, args graphql
 		cappedAggregator.Add(amr.Key.Group, int32(amr.Count))
 	}
 
-	countingFunc, err := aggregation.GetCountFuncForMode(r.searchQuery, r.patternType, aggregationMode)
+	countingFunc, err := aggregation.GetCountFuncForMode(r.searchQuery, r.patternType, r.macros, aggregationMode)
 	if err != nil {
 		return &searchAggregationResultResolver{
 			resolver: newSearchAggregationNotAvailableResolver(
@@ -140,7 +143,7 @@ func (r *searchAggregateResolver) Aggregations(ctx context.Context, args graphql
 		return &searchAggregationResultResolver{resolver: newSearchAggregationNotAvailableResolver(failureReason, aggregationMode)}, nil
 	}
 
-	results := buildResults(cappedAggregator, int(args.Limit), aggregationMode, r.searchQuery, r.patternType)
+	results := buildResults(cappedAggregator, int(args.Limit), aggregationMode, r.searchQuery, r.patternType, r.macros)
 
 	return &searchAggregationResultResolver{resolver: &searchAggregationModeResultResolver{
 		baseInsightResolver: r.baseInsightResolver,
@@ -154,16 +157,16 @@ func (r *searchAggregateResolver) Aggregations(ctx context.Context, args graphql
 
 // getDefaultAggregationMode returns a default aggregation mode for a potential query
 // this function should not fail because any search can be aggregated by repo
-func getDefaultAggregationMode(searchQuery, patternType string) types.SearchAggregationMode {
-	captureGroup, _, _ := canAggregateByCaptureGroup(searchQuery, patternType)
+func getDefaultAggregationMode(searchQuery, patternType string, macros map[string]string) types.SearchAggregationMode {
+	captureGroup, _, _ := canAggregateByCaptureGroup(searchQuery, patternType, macros)
 	if captureGroup {
 		return types.CAPTURE_GROUP_AGGREGATION_MODE
 	}
-	author, _, _ := canAggregateByAuthor(searchQuery, patternType)
+	author, _, _ := canAggregateByAuthor(searchQuery, patternType, macros)
 	if author {
 		return types.AUTHOR_AGGREGATION_MODE
 	}
-	file, _, _ := canAggregateByPath(searchQuery, patternType)
+	file, _, _ := canAggregateByPath(searchQuery, patternType, macros)
 	// We ignore the error here as the function errors if the query has multiple query steps.
 	targetsSingleRepo, _ := querybuilder.IsSingleRepoQuery(querybuilder.BasicQuery(searchQuery))
 	if file && targetsSingleRepo {
@@ -208,7 +211,7 @@ func (r *AggregationGroup) Query() (*string, error) {
 	return r.query, nil
 }
 
-func buildResults(aggregator aggregation.LimitedAggregator, limit int, mode types.SearchAggregationMode, originalQuery string, patternType string) aggregationResults {
+func buildResults(aggregator aggregation.LimitedAggregator, limit int, mode types.SearchAggregationMode, originalQuery string, patternType string, macros map[string]string) aggregationResults {
 	sorted := aggregator.SortAggregate()
 	groups := make([]graphqlbackend.AggregationGroup, 0, limit)
 	otherResults := aggregator.OtherCounts().ResultCount
@@ -217,7 +220,7 @@ func buildResults(aggregator aggregation.LimitedAggregator, limit int, mode type
 	for i := 0; i < len(sorted); i++ {
 		if i < limit {
 			label := sorted[i].Label
-			drilldownQuery, err := buildDrilldownQuery(mode, originalQuery, label, patternType)
+			drilldownQuery, err := buildDrilldownQuery(mode, originalQuery, label, patternType, macros)
 			if err != nil {
 				// for some reason we couldn't generate a new query, so fallback to the original
 				drilldownQuery = originalQuery
@@ -240,13 +243,14 @@ func buildResults(aggregator aggregation.LimitedAggregator, limit int, mode type
 	}
 }
 
-func newAggregationModeAvailabilityResolver(searchQuery string, patternType string, mode types.SearchAggregationMode) graphqlbackend.AggregationModeAvailabilityResolver {
-	return &aggregationModeAvailabilityResolver{searchQuery: searchQuery, patternType: patternType, mode: mode}
+func newAggregationModeAvailabilityResolver(searchQuery string, patternType string, macros map[string]string, mode types.SearchAggregationMode) graphqlbackend.AggregationModeAvailabilityResolver {
+	return &aggregationModeAvailabilityResolver{searchQuery: searchQuery, patternType: patternType, macros: macros, mode: mode}
 }
 
 type aggregationModeAvailabilityResolver struct {
 	searchQuery string
 	patternType string
+	macros      map[string]string
 	mode        types.SearchAggregationMode
 }
 
@@ -259,12 +263,12 @@ func (r *aggregationModeAvailabilityResolver) Available() (bool, error) {
 	if canAggregateByFunc == nil {
 		return false, nil
 	}
-	available, _, err := canAggregateByFunc(r.searchQuery, r.patternType)
+	available, _, err := canAggregateByFunc(r.searchQuery, r.patternType, r.macros)
 	return available, err
 }
 
 func (r *aggregationModeAvailabilityResolver) ReasonUnavailable() (*string, error) {
-	notAvailable, err := getNotAvailableReason(r.searchQuery, r.patternType, r.mode)
+	notAvailable, err := getNotAvailableReason(r.searchQuery, r.patternType, r.macros, r.mode)
 	if err != nil {
 		return nil, err
 	}
@@ -275,13 +279,13 @@ func (r *aggregationModeAvailabilityResolver) ReasonUnavailable() (*string, erro
 
 }
 
-func getNotAvailableReason(query, patternType string, mode types.SearchAggregationMode) (*notAvailableReason, error) {
+func getNotAvailableReason(query, patternType string, macros map[string]string, mode types.SearchAggregationMode) (*notAvailableReason, error) {
 	canAggregateByFunc := getAggregateBy(mode)
 	if canAggregateByFunc == nil {
 		reason := fmt.Sprintf(`Grouping by "%v" is not supported.`, mode)
 		return &notAvailableReason{reason: reason, reasonType: types.ERROR_OCCURRED}, nil
 	}
-	_, reason, err := canAggregateByFunc(query, patternType)
+	_, reason, err := canAggregateByFunc(query, patternType, macros)
 	if reason != nil {
 		return reason, nil
 	}
@@ -308,10 +312,10 @@ type notAvailableReason struct {
 	reasonType types.AggregationNotAvailableReasonType
 }
 
-type canAggregateBy func(searchQuery, patternType string) (bool, *notAvailableReason, error)
+type canAggregateBy func(searchQuery, patternType string, macros map[string]string) (bool, *notAvailableReason, error)
 
-func canAggregateByRepo(searchQuery, patternType string) (bool, *notAvailableReason, error) {
-	_, err := querybuilder.ParseQuery(searchQuery, patternType)
+func canAggregateByRepo(searchQuery, patternType string, macros map[string]string) (bool, *notAvailableReason, error) {
+	_, err := querybuilder.ParseQuery(searchQuery, patternType, macros)
 	if err != nil {
 		return false, &notAvailableReason{reason: invalidQueryMsg, reasonType: types.INVALID_QUERY}, errors.Wrapf(err, "ParseQuery")
 	}
@@ -319,8 +323,8 @@ func canAggregateByRepo(searchQuery, patternType string) (bool, *notAvailableRea
 	return true, nil, nil
 }
 
-func canAggregateByPath(searchQuery, patternType string) (bool, *notAvailableReason, error) {
-	plan, err := querybuilder.ParseQuery(searchQuery, patternType)
+func canAggregateByPath(searchQuery, patternType string, macros map[string]string) (bool, *notAvailableReason, error) {
+	plan, err := querybuilder.ParseQuery(searchQuery, patternType, macros)
 	if err != nil {
 		return false, &notAvailableReason{reason: invalidQueryMsg, reasonType: types.INVALID_QUERY}, errors.Wrapf(err, "ParseQuery")
 	}
@@ -339,8 +343,8 @@ func canAggregateByPath(searchQuery, patternType string) (bool, *notAvailableRea
 	return true, nil, nil
 }
 
-func canAggregateByAuthor(searchQuery, patternType string) (bool, *notAvailableReason, error) {
-	plan, err := querybuilder.ParseQuery(searchQuery, patternType)
+func canAggregateByAuthor(searchQuery, patternType string, macros map[string]string) (bool, *notAvailableReason, error) {
+	plan, err := querybuilder.ParseQuery(searchQuery, patternType, macros)
 	if err != nil {
 		return false, &notAvailableReason{reason: invalidQueryMsg, reasonType: types.INVALID_QUERY}, errors.Wrapf(err, "ParseQuery")
 	}
@@ -357,14 +361,14 @@ func canAggregateByAuthor(searchQuery, patternType string) (bool, *notAvailableR
 	return false, &notAvailableReason{reason: authNotCommitDiffMsg, reasonType: types.INVALID_AGGREGATION_MODE_FOR_QUERY}, nil
 }
 
-func canAggregateByCaptureGroup(searchQuery, patternType string) (bool, *notAvailableReason, error) {
+func canAggregateByCaptureGroup(searchQuery, patternType string, macros map[string]string) (bool, *notAvailableReason, error) {
 
-	plan, err := querybuilder.ParseQuery(searchQuery, patternType)
+	plan, err := querybuilder.ParseQuery(searchQuery, patternType, macros)
 	if err != nil {
 		return false, &notAvailableReason{reason: invalidQueryMsg, reasonType: types.INVALID_QUERY}, errors.Wrapf(err, "ParseQuery")
 	}
 
-	searchType, err := querybuilder.DetectSearchType(searchQuery, patternType)
+	searchType, err := querybuilder.DetectSearchType(searchQuery, patternType, macros)
 	if err != nil {
 		return false, &notAvailableReason{reason: cgInvalidQueryMsg, reasonType: types.INVALID_AGGREGATION_MODE_FOR_QUERY}, err
 	}
@@ -374,7 +378,7 @@ func canAggregateByCaptureGroup(searchQuery, patternType string) (bool, *notAvai
 
 	// A query should contain at least a regexp pattern and capture group to allow capture group aggregation.
 	// Only the first capture group will be used for aggregation.
-	replacer, err := querybuilder.NewPatternReplacer(querybuilder.BasicQuery(searchQuery), searchType)
+	replacer, err := querybuilder.NewPatternReplacer(querybuilder.BasicQuery(searchQuery), searchType, macros)
 	if errors.Is(err, querybuilder.UnsupportedPatternTypeErr) {
 		return false, &notAvailableReason{reason: cgInvalidQueryMsg, reasonType: types.INVALID_AGGREGATION_MODE_FOR_QUERY}, nil
 	} else if errors.Is(err, querybuilder.MultiplePatternErr) {
@@ -497,7 +501,7 @@ func (r *searchAggregationModeResultResolver) Mode() (string, error) {
 	return string(r.mode), nil
 }
 
-func buildDrilldownQuery(mode types.SearchAggregationMode, originalQuery string, drilldown string, patternType string) (string, error) {
+func buildDrilldownQuery(mode types.SearchAggregationMode, originalQuery string, drilldown string, patternType string, macros map[string]string) (string, error) {
 	var modifierFunc func(querybuilder.BasicQuery, string) (querybuilder.BasicQuery, error)
 	switch mode {
 	case types.REPO_AGGREGATION_MODE:
@@ -511,7 +515,7 @@ func buildDrilldownQuery(mode types.SearchAggregationMode, originalQuery string,
 		if err != nil {
 			return "", err
 		}
-		replacer, err := querybuilder.NewPatternReplacer(querybuilder.BasicQuery(originalQuery), searchType)
+		replacer, err := querybuilder.NewPatternReplacer(querybuilder.BasicQuery(originalQuery), searchType, macros)
 		if err != nil {
 			return "", err
 		}
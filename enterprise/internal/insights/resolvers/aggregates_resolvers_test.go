@@ -14,6 +14,7 @@ type canAggregateTestCase struct {
 	name         string
 	query        string
 	patternType  string
+	macros       map[string]string
 	canAggregate bool
 	err          error
 	reason       string
@@ -38,7 +39,7 @@ func (suite *canAggregateBySuite) Test_canAggregateBy() {
 			if tc.patternType == "" {
 				tc.patternType = "literal"
 			}
-			canAggregate, reasonNA, err := suite.canAggregateByFunc(tc.query, tc.patternType)
+			canAggregate, reasonNA, err := suite.canAggregateByFunc(tc.query, tc.patternType, tc.macros)
 			errCheck := (err == nil && tc.err == nil) || (err != nil && tc.err != nil)
 			if !errCheck {
 				t.Errorf("expected error %v, got %v", tc.err, err)
@@ -122,6 +123,12 @@ func Test_canAggregateByPath(t *testing.T) {
 
 func Test_canAggregateByAuthor(t *testing.T) {
 	testCases := []canAggregateTestCase{
+		{
+			name:         "can aggregate for type:commit in query macro",
+			query:        "@commits fix",
+			macros:       map[string]string{"commits": "type:commit"},
+			canAggregate: true,
+		},
 		{
 			name:         "cannot aggregate for query without parameters",
 			query:        "func(t *testing.T)",
@@ -207,6 +214,13 @@ func Test_canAggregateByCaptureGroup(t *testing.T) {
 			patternType:  "regexp",
 			canAggregate: true,
 		},
+		{
+			name:         "can aggregate for capture group in query macro",
+			query:        "@go-funcs",
+			patternType:  "regexp",
+			macros:       map[string]string{"go-funcs": "lang:go func(\\w+)"},
+			canAggregate: true,
+		},
 		{
 			name:         "cannot aggregate for query with non-captured regexp pattern",
 			query:        "\\w+",
@@ -364,7 +378,7 @@ func Test_getDefaultAggregationMode(t *testing.T) {
 			if tc.patternType != "" {
 				pt = tc.patternType
 			}
-			mode := getDefaultAggregationMode(tc.query, pt)
+			mode := getDefaultAggregationMode(tc.query, pt, nil)
 
 			if mode != tc.want {
 				t.Errorf("expected mode %v, got %v", tc.want, mode)
@@ -998,12 +998,13 @@ func createAndAttachSeries(ctx context.Context, tx *store.InsightStore, scopedBa
 	var err error
 	var dynamic bool
 	// Validate the query before creating anything; we don't want faulty insights running pointlessly.
+	// Series run in the background without a viewer, so they are validated without query macros.
 	if series.GroupBy != nil || series.GeneratedFromCaptureGroups != nil {
-		if _, err := querybuilder.ParseComputeQuery(series.Query); err != nil {
+		if _, err := querybuilder.ParseComputeQuery(series.Query, nil); err != nil {
 			return nil, errors.Wrap(err, "query validation")
 		}
 	} else {
-		if _, err := querybuilder.ParseQuery(series.Query, "literal"); err != nil {
+		if _, err := querybuilder.ParseQuery(series.Query, "literal", nil); err != nil {
 			return nil, errors.Wrap(err, "query validation")
 		}
 	}
@@ -108,10 +108,15 @@ func (r *Resolver) InsightsDashboards(ctx context.Context, args *graphqlbackend.
 }
 
 func (r *Resolver) SearchQueryAggregate(ctx context.Context, args graphqlbackend.SearchQueryArgs) (graphqlbackend.SearchQueryAggregateResolver, error) {
+	settings, err := graphqlbackend.DecodedViewerFinalSettings(ctx, r.postgresDB)
+	if err != nil {
+		return nil, err
+	}
 	return &searchAggregateResolver{
 		baseInsightResolver: r.baseInsightResolver,
 		searchQuery:         args.Query,
 		patternType:         args.PatternType,
+		macros:              settings.SearchMacros,
 	}, nil
 }
 
@@ -180,6 +180,29 @@ func (c *Client) CommitDate(ctx context.Context, repositoryID int, commit string
 	return "", time.Time{}, false, errors.Wrap(err, "git.CommitDate")
 }
 
+// NearestAncestorsIn returns the commits in candidates which are ancestors of the given commit (or
+// the commit itself) and are not ancestors of another such candidate, along with their distance
+// from the commit. Candidates which don't exist are ignored.
+func (c *Client) NearestAncestorsIn(ctx context.Context, repositoryID int, commit string, candidates []string) (_ []gitdomain.Ancestor, err error) {
+	ctx, _, endObservation := c.operations.nearestAncestorsIn.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+		log.String("commit", commit),
+		log.Int("numCandidates", len(candidates)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	repo, err := c.repositoryIDToRepo(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	commitIDs := make([]api.CommitID, 0, len(candidates))
+	for _, candidate := range candidates {
+		commitIDs = append(commitIDs, api.CommitID(candidate))
+	}
+	return gitserver.NewClient(c.db).NearestAncestorsIn(ctx, repo, api.CommitID(commit), commitIDs)
+}
+
 // CommitGraph returns the commit graph for the given repository as a mapping from a commit
 // to its parents. If a commit is supplied, the returned graph will be rooted at the given
 // commit. If a non-zero limit is supplied, at most that many commits will be returned.
@@ -18,6 +18,7 @@ type operations struct {
 	directoryChildren     *observation.Operation
 	fileExists            *observation.Operation
 	head                  *observation.Operation
+	nearestAncestorsIn    *observation.Operation
 	listFiles             *observation.Operation
 	rawContents           *observation.Operation
 	refDescriptions       *observation.Operation
@@ -62,6 +63,7 @@ func newOperations(observationContext *observation.Context) *operations {
 		directoryChildren:     op("DirectoryChildren"),
 		fileExists:            op("FileExists"),
 		head:                  op("Head"),
+		nearestAncestorsIn:    op("NearestAncestorsIn"),
 		listFiles:             op("ListFiles"),
 		rawContents:           op("RawContents"),
 		refDescriptions:       op("RefDescriptions"),
@@ -50,6 +50,7 @@ type operations struct {
 
 	// Dumps
 	findClosestDumps                   *observation.Operation
+	findClosestDumpCandidates          *observation.Operation
 	findClosestDumpsFromGraphFragment  *observation.Operation
 	getDumpsWithDefinitionsForMonikers *observation.Operation
 	getDumpsByIDs                      *observation.Operation
@@ -124,6 +125,7 @@ func newOperations(observationContext *observation.Context) *operations {
 
 		// Dumps
 		findClosestDumps:                   op("FindClosestDumps"),
+		findClosestDumpCandidates:          op("FindClosestDumpCandidates"),
 		findClosestDumpsFromGraphFragment:  op("FindClosestDumpsFromGraphFragment"),
 		getDumpsWithDefinitionsForMonikers: op("GetUploadsWithDefinitionsForMonikers"),
 		getDumpsByIDs:                      op("GetDumpsByIDs"),
@@ -57,6 +57,7 @@ type Store interface {
 
 	// Dumps
 	FindClosestDumps(ctx context.Context, repositoryID int, commit, path string, rootMustEnclosePath bool, indexer string) (_ []shared.Dump, err error)
+	FindClosestDumpCandidates(ctx context.Context, repositoryID int, path string, rootMustEnclosePath bool, indexer string) (_ []shared.Dump, err error)
 	FindClosestDumpsFromGraphFragment(ctx context.Context, repositoryID int, commit, path string, rootMustEnclosePath bool, indexer string, commitGraph *gitdomain.CommitGraph) (_ []shared.Dump, err error)
 	GetDumpsWithDefinitionsForMonikers(ctx context.Context, monikers []precise.QualifiedMonikerData) (_ []shared.Dump, err error)
 	GetDumpsByIDs(ctx context.Context, ids []int) (_ []shared.Dump, err error)
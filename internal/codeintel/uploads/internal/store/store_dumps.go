@@ -185,6 +185,57 @@ FROM lsif_dumps_with_repository_name u
 WHERE u.id IN (%s) AND %s
 `
 
+// FindClosestDumpCandidates returns the dumps of the given repository that could answer queries for the
+// given path and optional indexer at any commit, so that the ones visible from a commit missing from the
+// commit graph can be determined with gitserver. See FindClosestDumps for additional details.
+func (s *store) FindClosestDumpCandidates(ctx context.Context, repositoryID int, path string, rootMustEnclosePath bool, indexer string) (_ []shared.Dump, err error) {
+	ctx, trace, endObservation := s.operations.findClosestDumpCandidates.With(ctx, &err, observation.Args{
+		LogFields: []log.Field{
+			log.Int("repositoryID", repositoryID),
+			log.String("path", path),
+			log.Bool("rootMustEnclosePath", rootMustEnclosePath),
+			log.String("indexer", indexer),
+		},
+	})
+	defer endObservation(1, observation.Args{})
+
+	conds := makeFindClosestDumpConditions(path, rootMustEnclosePath, indexer)
+	query := sqlf.Sprintf(findClosestDumpCandidatesQuery, repositoryID, sqlf.Join(conds, " AND "))
+
+	dumps, err := scanDumps(s.db.Query(ctx, query))
+	if err != nil {
+		return nil, err
+	}
+	trace.Log(log.Int("numDumps", len(dumps)))
+
+	return dumps, nil
+}
+
+const findClosestDumpCandidatesQuery = `
+-- source: internal/codeintel/uploads/internal/store/store_dumps.go:FindClosestDumpCandidates
+SELECT
+	u.id,
+	u.commit,
+	u.root,
+	EXISTS (` + visibleAtTipSubselectQuery + `) AS visible_at_tip,
+	u.uploaded_at,
+	u.state,
+	u.failure_message,
+	u.started_at,
+	u.finished_at,
+	u.process_after,
+	u.num_resets,
+	u.num_failures,
+	u.repository_id,
+	u.repository_name,
+	u.indexer,
+	u.indexer_version,
+	u.associated_index_id
+FROM lsif_dumps_with_repository_name u
+WHERE u.repository_id = %s AND %s
+ORDER BY u.finished_at DESC
+`
+
 // DefinitionDumpsLimit is the maximum number of records that can be returned from DefinitionDumps.
 var DefinitionDumpsLimit, _ = strconv.ParseInt(env.Get("PRECISE_CODE_INTEL_DEFINITION_DUMPS_LIMIT", "100", "The maximum number of dumps that can define the same package."), 10, 64)
 
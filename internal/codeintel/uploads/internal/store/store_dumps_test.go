@@ -91,6 +91,44 @@ func TestFindClosestDumps(t *testing.T) {
 	})
 }
 
+func TestFindClosestDumpCandidates(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	store := New(db, &observation.TestContext)
+
+	insertUploads(t, db,
+		shared.Upload{ID: 1, Root: ""},
+		shared.Upload{ID: 2, Root: "sub/"},
+		shared.Upload{ID: 3, Root: "other/"},
+		shared.Upload{ID: 4, Root: "", Indexer: "scip-go"},
+		shared.Upload{ID: 5, Root: "", State: "errored"},
+		shared.Upload{ID: 6, Root: "", RepositoryID: 51},
+	)
+
+	for _, testCase := range []struct {
+		indexer     string
+		expectedIDs []int
+	}{
+		{indexer: "lsif-go", expectedIDs: []int{1, 2}},
+		{indexer: "", expectedIDs: []int{1, 2, 4}},
+	} {
+		dumps, err := store.FindClosestDumpCandidates(context.Background(), 50, "sub/main.go", true, testCase.indexer)
+		if err != nil {
+			t.Fatalf("unexpected error finding closest dump candidates: %s", err)
+		}
+
+		var ids []int
+		for _, dump := range dumps {
+			ids = append(ids, dump.ID)
+		}
+		sort.Ints(ids)
+
+		if diff := cmp.Diff(testCase.expectedIDs, ids); diff != "" {
+			t.Errorf("unexpected dump candidates for indexer %q (-want +got):\n%s", testCase.indexer, diff)
+		}
+	}
+}
+
 func TestDefinitionDumps(t *testing.T) {
 	logger := logtest.Scoped(t)
 	db := database.NewDB(logger, dbtest.NewDB(logger, t))
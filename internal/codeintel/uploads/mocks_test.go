@@ -45,6 +45,10 @@ type MockStore struct {
 	// DoneFunc is an instance of a mock function object controlling the
 	// behavior of the method Done.
 	DoneFunc *StoreDoneFunc
+	// FindClosestDumpCandidatesFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// FindClosestDumpCandidates.
+	FindClosestDumpCandidatesFunc *StoreFindClosestDumpCandidatesFunc
 	// FindClosestDumpsFunc is an instance of a mock function object
 	// controlling the behavior of the method FindClosestDumps.
 	FindClosestDumpsFunc *StoreFindClosestDumpsFunc
@@ -189,6 +193,11 @@ func NewMockStore() *MockStore {
 				return
 			},
 		},
+		FindClosestDumpCandidatesFunc: &StoreFindClosestDumpCandidatesFunc{
+			defaultHook: func(context.Context, int, string, bool, string) (r0 []shared.Dump, r1 error) {
+				return
+			},
+		},
 		FindClosestDumpsFunc: &StoreFindClosestDumpsFunc{
 			defaultHook: func(context.Context, int, string, string, bool, string) (r0 []shared.Dump, r1 error) {
 				return
@@ -386,6 +395,11 @@ func NewStrictMockStore() *MockStore {
 				panic("unexpected invocation of MockStore.Done")
 			},
 		},
+		FindClosestDumpCandidatesFunc: &StoreFindClosestDumpCandidatesFunc{
+			defaultHook: func(context.Context, int, string, bool, string) ([]shared.Dump, error) {
+				panic("unexpected invocation of MockStore.FindClosestDumpCandidates")
+			},
+		},
 		FindClosestDumpsFunc: &StoreFindClosestDumpsFunc{
 			defaultHook: func(context.Context, int, string, string, bool, string) ([]shared.Dump, error) {
 				panic("unexpected invocation of MockStore.FindClosestDumps")
@@ -571,6 +585,9 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		DoneFunc: &StoreDoneFunc{
 			defaultHook: i.Done,
 		},
+		FindClosestDumpCandidatesFunc: &StoreFindClosestDumpCandidatesFunc{
+			defaultHook: i.FindClosestDumpCandidates,
+		},
 		FindClosestDumpsFunc: &StoreFindClosestDumpsFunc{
 			defaultHook: i.FindClosestDumps,
 		},
@@ -1332,6 +1349,125 @@ func (c StoreDoneFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// StoreFindClosestDumpCandidatesFunc describes the behavior when the
+// FindClosestDumpCandidates method of the parent MockStore instance is
+// invoked.
+type StoreFindClosestDumpCandidatesFunc struct {
+	defaultHook func(context.Context, int, string, bool, string) ([]shared.Dump, error)
+	hooks       []func(context.Context, int, string, bool, string) ([]shared.Dump, error)
+	history     []StoreFindClosestDumpCandidatesFuncCall
+	mutex       sync.Mutex
+}
+
+// FindClosestDumpCandidates delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockStore) FindClosestDumpCandidates(v0 context.Context, v1 int, v2 string, v3 bool, v4 string) ([]shared.Dump, error) {
+	r0, r1 := m.FindClosestDumpCandidatesFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.FindClosestDumpCandidatesFunc.appendCall(StoreFindClosestDumpCandidatesFuncCall{v0, v1, v2, v3, v4, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// FindClosestDumpCandidates method of the parent MockStore instance is
+// invoked and the hook queue is empty.
+func (f *StoreFindClosestDumpCandidatesFunc) SetDefaultHook(hook func(context.Context, int, string, bool, string) ([]shared.Dump, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// FindClosestDumpCandidates method of the parent MockStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *StoreFindClosestDumpCandidatesFunc) PushHook(hook func(context.Context, int, string, bool, string) ([]shared.Dump, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreFindClosestDumpCandidatesFunc) SetDefaultReturn(r0 []shared.Dump, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, bool, string) ([]shared.Dump, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreFindClosestDumpCandidatesFunc) PushReturn(r0 []shared.Dump, r1 error) {
+	f.PushHook(func(context.Context, int, string, bool, string) ([]shared.Dump, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreFindClosestDumpCandidatesFunc) nextHook() func(context.Context, int, string, bool, string) ([]shared.Dump, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreFindClosestDumpCandidatesFunc) appendCall(r0 StoreFindClosestDumpCandidatesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreFindClosestDumpCandidatesFuncCall
+// objects describing the invocations of this function.
+func (f *StoreFindClosestDumpCandidatesFunc) History() []StoreFindClosestDumpCandidatesFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreFindClosestDumpCandidatesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreFindClosestDumpCandidatesFuncCall is an object that describes an
+// invocation of method FindClosestDumpCandidates on an instance of
+// MockStore.
+type StoreFindClosestDumpCandidatesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 bool
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.Dump
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreFindClosestDumpCandidatesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreFindClosestDumpCandidatesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // StoreFindClosestDumpsFunc describes the behavior when the
 // FindClosestDumps method of the parent MockStore instance is invoked.
 type StoreFindClosestDumpsFunc struct {
@@ -4983,6 +5119,9 @@ type MockGitserverClient struct {
 	// CommitGraphFunc is an instance of a mock function object controlling
 	// the behavior of the method CommitGraph.
 	CommitGraphFunc *GitserverClientCommitGraphFunc
+	// NearestAncestorsInFunc is an instance of a mock function object
+	// controlling the behavior of the method NearestAncestorsIn.
+	NearestAncestorsInFunc *GitserverClientNearestAncestorsInFunc
 	// RefDescriptionsFunc is an instance of a mock function object
 	// controlling the behavior of the method RefDescriptions.
 	RefDescriptionsFunc *GitserverClientRefDescriptionsFunc
@@ -5003,6 +5142,11 @@ func NewMockGitserverClient() *MockGitserverClient {
 				return
 			},
 		},
+		NearestAncestorsInFunc: &GitserverClientNearestAncestorsInFunc{
+			defaultHook: func(context.Context, int, string, []string) (r0 []gitdomain.Ancestor, r1 error) {
+				return
+			},
+		},
 		RefDescriptionsFunc: &GitserverClientRefDescriptionsFunc{
 			defaultHook: func(context.Context, int, ...string) (r0 map[string][]gitdomain.RefDescription, r1 error) {
 				return
@@ -5025,6 +5169,11 @@ func NewStrictMockGitserverClient() *MockGitserverClient {
 				panic("unexpected invocation of MockGitserverClient.CommitGraph")
 			},
 		},
+		NearestAncestorsInFunc: &GitserverClientNearestAncestorsInFunc{
+			defaultHook: func(context.Context, int, string, []string) ([]gitdomain.Ancestor, error) {
+				panic("unexpected invocation of MockGitserverClient.NearestAncestorsIn")
+			},
+		},
 		RefDescriptionsFunc: &GitserverClientRefDescriptionsFunc{
 			defaultHook: func(context.Context, int, ...string) (map[string][]gitdomain.RefDescription, error) {
 				panic("unexpected invocation of MockGitserverClient.RefDescriptions")
@@ -5044,6 +5193,9 @@ func NewMockGitserverClientFrom(i shared.GitserverClient) *MockGitserverClient {
 		CommitGraphFunc: &GitserverClientCommitGraphFunc{
 			defaultHook: i.CommitGraph,
 		},
+		NearestAncestorsInFunc: &GitserverClientNearestAncestorsInFunc{
+			defaultHook: i.NearestAncestorsIn,
+		},
 		RefDescriptionsFunc: &GitserverClientRefDescriptionsFunc{
 			defaultHook: i.RefDescriptions,
 		},
@@ -5278,6 +5430,123 @@ func (c GitserverClientCommitGraphFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// GitserverClientNearestAncestorsInFunc describes the behavior when the
+// NearestAncestorsIn method of the parent MockGitserverClient instance is
+// invoked.
+type GitserverClientNearestAncestorsInFunc struct {
+	defaultHook func(context.Context, int, string, []string) ([]gitdomain.Ancestor, error)
+	hooks       []func(context.Context, int, string, []string) ([]gitdomain.Ancestor, error)
+	history     []GitserverClientNearestAncestorsInFuncCall
+	mutex       sync.Mutex
+}
+
+// NearestAncestorsIn delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockGitserverClient) NearestAncestorsIn(v0 context.Context, v1 int, v2 string, v3 []string) ([]gitdomain.Ancestor, error) {
+	r0, r1 := m.NearestAncestorsInFunc.nextHook()(v0, v1, v2, v3)
+	m.NearestAncestorsInFunc.appendCall(GitserverClientNearestAncestorsInFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the NearestAncestorsIn
+// method of the parent MockGitserverClient instance is invoked and the hook
+// queue is empty.
+func (f *GitserverClientNearestAncestorsInFunc) SetDefaultHook(hook func(context.Context, int, string, []string) ([]gitdomain.Ancestor, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// NearestAncestorsIn method of the parent MockGitserverClient instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *GitserverClientNearestAncestorsInFunc) PushHook(hook func(context.Context, int, string, []string) ([]gitdomain.Ancestor, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverClientNearestAncestorsInFunc) SetDefaultReturn(r0 []gitdomain.Ancestor, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, []string) ([]gitdomain.Ancestor, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverClientNearestAncestorsInFunc) PushReturn(r0 []gitdomain.Ancestor, r1 error) {
+	f.PushHook(func(context.Context, int, string, []string) ([]gitdomain.Ancestor, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverClientNearestAncestorsInFunc) nextHook() func(context.Context, int, string, []string) ([]gitdomain.Ancestor, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverClientNearestAncestorsInFunc) appendCall(r0 GitserverClientNearestAncestorsInFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverClientNearestAncestorsInFuncCall
+// objects describing the invocations of this function.
+func (f *GitserverClientNearestAncestorsInFunc) History() []GitserverClientNearestAncestorsInFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverClientNearestAncestorsInFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverClientNearestAncestorsInFuncCall is an object that describes an
+// invocation of method NearestAncestorsIn on an instance of
+// MockGitserverClient.
+type GitserverClientNearestAncestorsInFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 []string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []gitdomain.Ancestor
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverClientNearestAncestorsInFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverClientNearestAncestorsInFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // GitserverClientRefDescriptionsFunc describes the behavior when the
 // RefDescriptions method of the parent MockGitserverClient instance is
 // invoked.
@@ -14,7 +14,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/internal/lsifstore"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/internal/store"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
-	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	gitserverOptions "github.com/sourcegraph/sourcegraph/internal/gitserver"
 
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
@@ -457,25 +456,15 @@ func (s *Service) DeleteUploadsWithoutRepository(ctx context.Context, now time.T
 	return s.store.DeleteUploadsWithoutRepository(ctx, now)
 }
 
-// numAncestors is the number of ancestors to query from gitserver when trying to find the closest
-// ancestor we have data for. Setting this value too low (relative to a repository's commit rate)
-// will cause requests for an unknown commit return too few results; setting this value too high
-// will raise the latency of requests for an unknown commit.
-//
-// TODO(efritz) - make adjustable via site configuration
-const numAncestors = 100
-
 // inferClosestUploads will return the set of visible uploads for the given commit. If this commit is
 // newer than our last refresh of the lsif_nearest_uploads table for this repository, then we will mark
 // the repository as dirty and quickly approximate the correct set of visible uploads.
 //
 // Because updating the entire commit graph is a blocking, expensive, and lock-guarded process, we  want
-// to only do that in the background and do something chearp in latency-sensitive paths. To construct an
-// approximate result, we query gitserver for a (relatively small) set of ancestors for the given commit,
-// correlate that with the upload data we have for those commits, and re-run the visibility algorithm over
-// the graph. This will not always produce the full set of visible commits - some responses may not contain
-// all results while a subsequent request made after the lsif_nearest_uploads has been updated to include
-// this commit will.
+// to only do that in the background and do something chearp in latency-sensitive paths. To construct the
+// result, we ask gitserver which of the commits with uploads for each root and indexer are the nearest
+// ancestors of the given commit, and pick the upload on the closest one. gitserver answers this without
+// transferring the commit graph.
 //
 func (s *Service) InferClosestUploads(ctx context.Context, repositoryID int, commit, path string, exactPath bool, indexer string) (_ []shared.Dump, err error) {
 	ctx, _, endObservation := s.operations.inferClosestUploads.With(ctx, &err, observation.Args{
@@ -508,27 +497,81 @@ func (s *Service) InferClosestUploads(ctx context.Context, repositoryID int, com
 	}
 
 	// Otherwise, the repository has LSIF data but we don't know about the commit. This commit
-	// is probably newer than our last upload. Pull back a portion of the updated commit graph
-	// and try to link it with what we have in the database. Then mark the repository's commit
-	// graph as dirty so it's updated for subsequent requests.
-
-	graph, err := s.gitserverClient.CommitGraph(ctx, repositoryID, gitserver.CommitGraphOptions{
-		Commit: commit,
-		Limit:  numAncestors,
-	})
+	// is probably newer than our last upload. Ask gitserver which of the commits with uploads
+	// are its nearest ancestors instead of transferring the commit graph. Then mark the
+	// repository's commit graph as dirty so it's updated for subsequent requests.
+	dumps, err := s.findClosestDumpsFromAncestors(ctx, repositoryID, commit, path, exactPath, indexer)
 	if err != nil {
-		return nil, errors.Wrap(err, "gitserverClient.CommitGraph")
+		return nil, err
+	}
+
+	if err := s.store.SetRepositoryAsDirty(ctx, repositoryID); err != nil {
+		return nil, errors.Wrap(err, "dbstore.MarkRepositoryAsDirty")
 	}
 
-	dumps, err := s.store.FindClosestDumpsFromGraphFragment(ctx, repositoryID, commit, path, exactPath, indexer, graph)
+	return dumps, nil
+}
+
+// findClosestDumpsFromAncestors returns the dumps visible from the given commit, which must not be
+// part of the commit graph of the repository stored in the database. Like in the commit graph, the
+// dump visible for a root and indexer is the one on the nearest ancestor, and ties are broken by
+// the smallest upload identifier.
+func (s *Service) findClosestDumpsFromAncestors(ctx context.Context, repositoryID int, commit, path string, rootMustEnclosePath bool, indexer string) ([]shared.Dump, error) {
+	candidates, err := s.store.FindClosestDumpCandidates(ctx, repositoryID, path, rootMustEnclosePath, indexer)
 	if err != nil {
-		return nil, errors.Wrap(err, "dbstore.FindClosestDumpsFromGraphFragment")
+		return nil, errors.Wrap(err, "store.FindClosestDumpCandidates")
 	}
 
-	if err := s.store.SetRepositoryAsDirty(ctx, repositoryID); err != nil {
-		return nil, errors.Wrap(err, "dbstore.MarkRepositoryAsDirty")
+	// Dumps for distinct roots and indexers don't shadow each other, so the nearest ancestors
+	// are determined separately for each of them.
+	type token struct{ root, indexer string }
+	var tokens []token
+	dumpsByToken := map[token]map[string][]shared.Dump{}
+	for _, dump := range candidates {
+		t := token{dump.Root, dump.Indexer}
+		if _, ok := dumpsByToken[t]; !ok {
+			tokens = append(tokens, t)
+			dumpsByToken[t] = map[string][]shared.Dump{}
+		}
+		dumpsByToken[t][dump.Commit] = append(dumpsByToken[t][dump.Commit], dump)
 	}
 
+	visible := make(map[int]struct{}, len(tokens))
+	for _, t := range tokens {
+		commits := make([]string, 0, len(dumpsByToken[t]))
+		for c := range dumpsByToken[t] {
+			commits = append(commits, c)
+		}
+
+		ancestors, err := s.gitserverClient.NearestAncestorsIn(ctx, repositoryID, commit, commits)
+		if err != nil {
+			return nil, errors.Wrap(err, "gitserverClient.NearestAncestorsIn")
+		}
+
+		var (
+			closest  *shared.Dump
+			distance int
+		)
+		for _, ancestor := range ancestors {
+			for _, dump := range dumpsByToken[t][string(ancestor.Commit)] {
+				if closest == nil || ancestor.Distance < distance || (ancestor.Distance == distance && dump.ID < closest.ID) {
+					dump := dump
+					closest, distance = &dump, ancestor.Distance
+				}
+			}
+		}
+		if closest != nil {
+			visible[closest.ID] = struct{}{}
+		}
+	}
+
+	// Candidates are sorted in most-recently-finished order, which we keep.
+	dumps := make([]shared.Dump, 0, len(visible))
+	for _, dump := range candidates {
+		if _, ok := visible[dump.ID]; ok {
+			dumps = append(dumps, dump)
+		}
+	}
 	return dumps, nil
 }
 
@@ -8,7 +8,9 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 )
 
@@ -165,3 +167,61 @@ func TestBackfillCommittedAtBatchUnknownCommits(t *testing.T) {
 		t.Errorf("unexpected commit dates (-want +got):\n%s", diff)
 	}
 }
+
+func TestInferClosestUploads(t *testing.T) {
+	ctx := context.Background()
+	store := NewMockStore()
+	gitserverClient := NewMockGitserverClient()
+	svc := newService(store, nil, gitserverClient, nil, &observation.TestContext)
+
+	// The repository has uploads, but the commit is not in its commit graph yet.
+	store.HasRepositoryFunc.SetDefaultReturn(true, nil)
+	store.HasCommitFunc.SetDefaultReturn(false, nil)
+	store.FindClosestDumpCandidatesFunc.SetDefaultReturn([]shared.Dump{
+		{ID: 1, Commit: "a", Root: "", Indexer: "lsif-go"},
+		{ID: 2, Commit: "b", Root: "", Indexer: "lsif-go"},
+		{ID: 4, Commit: "d", Root: "sub/", Indexer: "lsif-go"},
+		{ID: 3, Commit: "c", Root: "sub/", Indexer: "lsif-go"},
+		{ID: 5, Commit: "e", Root: "", Indexer: "scip-typescript"},
+	}, nil)
+
+	// e is not an ancestor of the commit. Each root and indexer is asked for
+	// separately, so a and b are both nearest ancestors among their candidates.
+	distances := map[string]int{"a": 3, "b": 1, "c": 2, "d": 2}
+	gitserverClient.NearestAncestorsInFunc.SetDefaultHook(func(_ context.Context, _ int, commit string, candidates []string) ([]gitdomain.Ancestor, error) {
+		if commit != "deadbeef" {
+			t.Errorf("unexpected commit %q", commit)
+		}
+		var ancestors []gitdomain.Ancestor
+		for _, c := range candidates {
+			if d, ok := distances[c]; ok {
+				ancestors = append(ancestors, gitdomain.Ancestor{Commit: api.CommitID(c), Distance: d})
+			}
+		}
+		return ancestors, nil
+	})
+
+	dumps, err := svc.InferClosestUploads(ctx, 42, "deadbeef", "sub/main.go", true, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The closest upload of each root and indexer is visible, ties are broken
+	// by the smallest upload identifier.
+	var ids []int
+	for _, dump := range dumps {
+		ids = append(ids, dump.ID)
+	}
+	if diff := cmp.Diff([]int{2, 3}, ids); diff != "" {
+		t.Errorf("unexpected dumps (-want +got):\n%s", diff)
+	}
+	if n := len(gitserverClient.NearestAncestorsInFunc.History()); n != 3 {
+		t.Errorf("unexpected number of calls to NearestAncestorsIn: want 3, have %d", n)
+	}
+	if n := len(gitserverClient.CommitGraphFunc.History()); n != 0 {
+		t.Errorf("unexpected calls to CommitGraph: %d", n)
+	}
+	if n := len(store.SetRepositoryAsDirtyFunc.History()); n != 1 {
+		t.Errorf("expected repository to be marked as dirty, have %d calls", n)
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 type GitserverClient interface {
 	CommitGraph(ctx context.Context, repositoryID int, opts gitserver.CommitGraphOptions) (_ *gitdomain.CommitGraph, err error)
+	NearestAncestorsIn(ctx context.Context, repositoryID int, commit string, candidates []string) (_ []gitdomain.Ancestor, err error)
 	RefDescriptions(ctx context.Context, repositoryID int, pointedAt ...string) (_ map[string][]gitdomain.RefDescription, err error)
 	CommitDate(ctx context.Context, repositoryID int, commit string) (string, time.Time, bool, error)
 }
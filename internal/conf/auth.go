@@ -20,6 +20,8 @@ func AuthProviderType(p schema.AuthProviders) string {
 		return p.Github.Type
 	case p.Gitlab != nil:
 		return p.Gitlab.Type
+	case p.Bitbucketcloud != nil:
+		return p.Bitbucketcloud.Type
 	default:
 		return ""
 	}
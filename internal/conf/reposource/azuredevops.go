@@ -0,0 +1,64 @@
+package reposource
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type AzureDevOps struct {
+	*schema.AzureDevOpsConnection
+}
+
+var _ RepoSource = AzureDevOps{}
+
+func (c AzureDevOps) CloneURLToRepoName(cloneURL string) (repoName api.RepoName, err error) {
+	parsedCloneURL, baseURL, match, err := parseURLs(cloneURL, c.Url)
+	if err != nil {
+		return "", err
+	}
+	// Azure DevOps Services serves SSH clones from a separate host.
+	if !match && (baseURL == nil || hostname(parsedCloneURL) != "ssh."+hostname(baseURL)) {
+		return "", nil
+	}
+
+	path := strings.Trim(parsedCloneURL.Path, "/")
+	if basePath := strings.Trim(baseURL.Path, "/"); basePath != "" {
+		path = strings.TrimPrefix(path, basePath+"/")
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) != 4 {
+		return "", nil
+	}
+
+	var org, project, repo string
+	switch {
+	case parts[2] == "_git":
+		// HTTP(S) and Azure DevOps Server SSH clone URLs have the form
+		// "{org}/{project}/_git/{repo}".
+		org, project, repo = parts[0], parts[1], parts[3]
+	case parts[0] == "v3":
+		// Azure DevOps Services SSH clone URLs have the form
+		// "v3/{org}/{project}/{repo}".
+		org, project, repo = parts[1], parts[2], parts[3]
+	default:
+		return "", nil
+	}
+
+	return AzureDevOpsRepoName(c.RepositoryPathPattern, baseURL.Hostname(), org, project, strings.TrimSuffix(repo, ".git")), nil
+}
+
+func AzureDevOpsRepoName(repositoryPathPattern, host, org, project, repo string) api.RepoName {
+	if repositoryPathPattern == "" {
+		repositoryPathPattern = "{host}/{org}/{project}/{repo}"
+	}
+
+	return api.RepoName(strings.NewReplacer(
+		"{host}", host,
+		"{org}", org,
+		"{project}", project,
+		"{repo}", repo,
+	).Replace(repositoryPathPattern))
+}
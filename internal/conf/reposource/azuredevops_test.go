@@ -0,0 +1,53 @@
+package reposource
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestAzureDevOps_cloneURLToRepoName(t *testing.T) {
+	tests := []struct {
+		conn schema.AzureDevOpsConnection
+		urls []urlToRepoName
+	}{
+		{
+			conn: schema.AzureDevOpsConnection{
+				Url: "https://dev.azure.com",
+			},
+			urls: []urlToRepoName{
+				{"https://dev.azure.com/myorg/myproject/_git/myrepo", "dev.azure.com/myorg/myproject/myrepo"},
+				{"https://myorg@dev.azure.com/myorg/myproject/_git/myrepo", "dev.azure.com/myorg/myproject/myrepo"},
+				{"git@ssh.dev.azure.com:v3/myorg/myproject/myrepo", "dev.azure.com/myorg/myproject/myrepo"},
+
+				{"https://dev.azure.com/myorg/myproject/myrepo", ""},
+				{"https://asdf.com/myorg/myproject/_git/myrepo", ""},
+				{"git@ssh.asdf.com:v3/myorg/myproject/myrepo", ""},
+			},
+		},
+		{
+			conn: schema.AzureDevOpsConnection{
+				Url:                   "https://ado.example.org/tfs/",
+				RepositoryPathPattern: "ado/{org}/{project}/{repo}",
+			},
+			urls: []urlToRepoName{
+				{"https://ado.example.org/tfs/DefaultCollection/myproject/_git/myrepo", "ado/DefaultCollection/myproject/myrepo"},
+				{"ssh://ado.example.org:22/tfs/DefaultCollection/myproject/_git/myrepo", "ado/DefaultCollection/myproject/myrepo"},
+
+				{"https://dev.azure.com/myorg/myproject/_git/myrepo", ""},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		for _, u := range test.urls {
+			repoName, err := AzureDevOps{&test.conn}.CloneURLToRepoName(u.cloneURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u.repoName != string(repoName) {
+				t.Errorf("expected %q but got %q for clone URL %q (connection: %+v)", u.repoName, repoName, u.cloneURL, test.conn)
+			}
+		}
+	}
+}
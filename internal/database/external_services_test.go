@@ -2381,6 +2381,9 @@ func TestConfigurationHasWebhooks(t *testing.T) {
 					},
 				},
 			},
+			&schema.AzureDevOpsConnection{
+				WebhookSecret: "super secret",
+			},
 		} {
 			t.Run(fmt.Sprintf("%T", cfg), func(t *testing.T) {
 				assert.True(t, configurationHasWebhooks(cfg))
@@ -2393,6 +2396,7 @@ func TestConfigurationHasWebhooks(t *testing.T) {
 			&schema.GitHubConnection{},
 			&schema.GitLabConnection{},
 			&schema.BitbucketServerConnection{},
+			&schema.AzureDevOpsConnection{},
 		} {
 			t.Run(fmt.Sprintf("%T", cfg), func(t *testing.T) {
 				assert.False(t, configurationHasWebhooks(cfg))
@@ -47,6 +47,16 @@ type GitserverRepoStore interface {
 	// a matching row does not yet exist a new one will be created.
 	// If the size value hasn't changed, the row will not be updated.
 	SetRepoSize(ctx context.Context, name api.RepoName, size int64, shardID string) error
+	// SetOffloadKey will attempt to update ONLY the offload key of a GitServerRepo.
+	// An empty key marks the repo as not offloaded.
+	SetOffloadKey(ctx context.Context, name api.RepoName, key string) error
+	// SetReplicaStatus will attempt to update ONLY whether the gitserver
+	// shardID holds an in-sync replica of a GitServerRepo.
+	SetReplicaStatus(ctx context.Context, name api.RepoName, shardID string, inSync bool) error
+	// SetPushMirrorStatus will attempt to update ONLY the status of the last push
+	// of a GitServerRepo to its push mirror. An empty error records a successful
+	// push.
+	SetPushMirrorStatus(ctx context.Context, name api.RepoName, pushError string) error
 	// IterateWithNonemptyLastError iterates over repos w/ non-empty last_error field and calls the repoFn for these repos.
 	// note that this currently filters out any repos which do not have an associated external service where cloud_default = true.
 	IterateWithNonemptyLastError(ctx context.Context, repoFn func(repo api.RepoName) error) error
@@ -304,6 +314,10 @@ SELECT
 	gr.last_fetched,
 	gr.last_changed,
 	gr.repo_size_bytes,
+	gr.offload_key,
+	gr.replica_shard_ids,
+	gr.push_mirror_last_pushed_at,
+	gr.push_mirror_last_error,
 	gr.updated_at
 FROM gitserver_repos gr
 JOIN repo ON gr.repo_id = repo.id
@@ -332,6 +346,10 @@ SELECT
 	last_fetched,
 	last_changed,
 	repo_size_bytes,
+	offload_key,
+	replica_shard_ids,
+	push_mirror_last_pushed_at,
+	push_mirror_last_error,
 	updated_at
 FROM gitserver_repos
 WHERE repo_id = %s
@@ -359,6 +377,10 @@ SELECT
 	gr.last_fetched,
 	gr.last_changed,
 	gr.repo_size_bytes,
+	gr.offload_key,
+	gr.replica_shard_ids,
+	gr.push_mirror_last_pushed_at,
+	gr.push_mirror_last_error,
 	gr.updated_at
 FROM gitserver_repos gr
 JOIN repo r ON r.id = gr.repo_id
@@ -381,6 +403,10 @@ SELECT
 	gr.last_fetched,
 	gr.last_changed,
 	gr.repo_size_bytes,
+	gr.offload_key,
+	gr.replica_shard_ids,
+	gr.push_mirror_last_pushed_at,
+	gr.push_mirror_last_error,
 	gr.updated_at
 FROM gitserver_repos gr
 JOIN repo r on r.id = gr.repo_id
@@ -420,6 +446,10 @@ func scanGitserverRepo(scanner dbutil.Scanner) (*types.GitserverRepo, api.RepoNa
 		&gr.LastFetched,
 		&gr.LastChanged,
 		&dbutil.NullInt64{N: &gr.RepoSizeBytes},
+		&dbutil.NullString{S: &gr.OffloadKey},
+		pq.Array(&gr.ReplicaShardIDs),
+		&dbutil.NullTime{Time: &gr.PushMirrorLastPushedAt},
+		&dbutil.NullString{S: &gr.PushMirrorLastError},
 		&gr.UpdatedAt,
 	)
 	if err != nil {
@@ -493,6 +523,65 @@ WHERE
 	return nil
 }
 
+func (s *gitserverRepoStore) SetOffloadKey(ctx context.Context, name api.RepoName, key string) error {
+	err := s.Exec(ctx, sqlf.Sprintf(`
+-- source: internal/database/gitserver_repos.go:gitserverRepoStore.SetOffloadKey
+UPDATE gitserver_repos
+SET
+	offload_key = NULLIF(%s, ''),
+	updated_at = NOW()
+WHERE
+	repo_id = (SELECT id FROM repo WHERE name = %s)
+	AND
+	offload_key IS DISTINCT FROM NULLIF(%s, '')
+	`, key, name, key))
+	if err != nil {
+		return errors.Wrap(err, "setting offload key")
+	}
+
+	return nil
+}
+
+func (s *gitserverRepoStore) SetReplicaStatus(ctx context.Context, name api.RepoName, shardID string, inSync bool) error {
+	err := s.Exec(ctx, sqlf.Sprintf(`
+-- source: internal/database/gitserver_repos.go:gitserverRepoStore.SetReplicaStatus
+UPDATE gitserver_repos
+SET
+	replica_shard_ids = CASE WHEN %s
+		THEN array_append(array_remove(replica_shard_ids, %s), %s)
+		ELSE NULLIF(array_remove(replica_shard_ids, %s), '{}')
+	END,
+	updated_at = NOW()
+WHERE
+	repo_id = (SELECT id FROM repo WHERE name = %s)
+	AND
+	(%s = ANY(COALESCE(replica_shard_ids, '{}'))) IS DISTINCT FROM %s
+	`, inSync, shardID, shardID, shardID, name, shardID, inSync))
+	if err != nil {
+		return errors.Wrap(err, "setting replica status")
+	}
+
+	return nil
+}
+
+func (s *gitserverRepoStore) SetPushMirrorStatus(ctx context.Context, name api.RepoName, pushError string) error {
+	err := s.Exec(ctx, sqlf.Sprintf(`
+-- source: internal/database/gitserver_repos.go:gitserverRepoStore.SetPushMirrorStatus
+UPDATE gitserver_repos
+SET
+	push_mirror_last_pushed_at = CASE WHEN %s = '' THEN NOW() ELSE push_mirror_last_pushed_at END,
+	push_mirror_last_error = NULLIF(%s, ''),
+	updated_at = NOW()
+WHERE
+	repo_id = (SELECT id FROM repo WHERE name = %s)
+	`, pushError, pushError, name))
+	if err != nil {
+		return errors.Wrap(err, "setting push mirror status")
+	}
+
+	return nil
+}
+
 // GitserverFetchData is the metadata associated with a fetch operation on
 // gitserver.
 type GitserverFetchData struct {
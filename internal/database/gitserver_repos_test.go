@@ -656,6 +656,116 @@ func TestSetRepoSize(t *testing.T) {
 	}
 }
 
+func TestSetOffloadKey(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+
+	repo, gitserverRepo := createTestRepo(ctx, t, db, &createTestRepoPayload{
+		Name: "github.com/sourcegraph/repo",
+	})
+
+	for _, key := range []string{"github.com/sourcegraph/repo.bundle", ""} {
+		if err := db.GitserverRepos().SetOffloadKey(ctx, repo.Name, key); err != nil {
+			t.Fatal(err)
+		}
+
+		fromDB, err := db.GitserverRepos().GetByID(ctx, gitserverRepo.RepoID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gitserverRepo.OffloadKey = key
+		if diff := cmp.Diff(gitserverRepo, fromDB, cmpopts.IgnoreFields(types.GitserverRepo{}, "UpdatedAt")); diff != "" {
+			t.Fatal(diff)
+		}
+	}
+}
+
+func TestSetReplicaStatus(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+
+	repo, gitserverRepo := createTestRepo(ctx, t, db, &createTestRepoPayload{
+		Name: "github.com/sourcegraph/repo",
+	})
+
+	for _, tc := range []struct {
+		shardID string
+		inSync  bool
+		want    []string
+	}{
+		{"gitserver-1", true, []string{"gitserver-1"}},
+		{"gitserver-2", true, []string{"gitserver-1", "gitserver-2"}},
+		// Syncing a replica again doesn't duplicate it.
+		{"gitserver-1", true, []string{"gitserver-1", "gitserver-2"}},
+		{"gitserver-1", false, []string{"gitserver-2"}},
+		{"gitserver-2", false, nil},
+	} {
+		if err := db.GitserverRepos().SetReplicaStatus(ctx, repo.Name, tc.shardID, tc.inSync); err != nil {
+			t.Fatal(err)
+		}
+
+		fromDB, err := db.GitserverRepos().GetByID(ctx, gitserverRepo.RepoID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gitserverRepo.ReplicaShardIDs = tc.want
+		if diff := cmp.Diff(gitserverRepo, fromDB, cmpopts.IgnoreFields(types.GitserverRepo{}, "UpdatedAt")); diff != "" {
+			t.Fatal(diff)
+		}
+	}
+}
+
+func TestSetPushMirrorStatus(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+
+	repo, gitserverRepo := createTestRepo(ctx, t, db, &createTestRepoPayload{
+		Name: "github.com/sourcegraph/repo",
+	})
+
+	var lastPushedAt time.Time
+	for _, pushError := range []string{"", "push failed", ""} {
+		if err := db.GitserverRepos().SetPushMirrorStatus(ctx, repo.Name, pushError); err != nil {
+			t.Fatal(err)
+		}
+
+		fromDB, err := db.GitserverRepos().GetByID(ctx, gitserverRepo.RepoID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Failed pushes keep the time of the last successful push.
+		if pushError == "" {
+			if !fromDB.PushMirrorLastPushedAt.After(lastPushedAt) {
+				t.Fatalf("expected last pushed time to be updated, have %s", fromDB.PushMirrorLastPushedAt)
+			}
+			lastPushedAt = fromDB.PushMirrorLastPushedAt
+		}
+		gitserverRepo.PushMirrorLastPushedAt = lastPushedAt
+		gitserverRepo.PushMirrorLastError = pushError
+		if diff := cmp.Diff(gitserverRepo, fromDB, cmpopts.IgnoreFields(types.GitserverRepo{}, "UpdatedAt")); diff != "" {
+			t.Fatal(diff)
+		}
+	}
+}
+
 func TestGitserverRepo_Update(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -18422,6 +18422,15 @@ type MockGitserverRepoStore struct {
 	// SetLastFetchedFunc is an instance of a mock function object
 	// controlling the behavior of the method SetLastFetched.
 	SetLastFetchedFunc *GitserverRepoStoreSetLastFetchedFunc
+	// SetOffloadKeyFunc is an instance of a mock function object
+	// controlling the behavior of the method SetOffloadKey.
+	SetOffloadKeyFunc *GitserverRepoStoreSetOffloadKeyFunc
+	// SetPushMirrorStatusFunc is an instance of a mock function object
+	// controlling the behavior of the method SetPushMirrorStatus.
+	SetPushMirrorStatusFunc *GitserverRepoStoreSetPushMirrorStatusFunc
+	// SetReplicaStatusFunc is an instance of a mock function object
+	// controlling the behavior of the method SetReplicaStatus.
+	SetReplicaStatusFunc *GitserverRepoStoreSetReplicaStatusFunc
 	// SetRepoSizeFunc is an instance of a mock function object controlling
 	// the behavior of the method SetRepoSize.
 	SetRepoSizeFunc *GitserverRepoStoreSetRepoSizeFunc
@@ -18500,6 +18509,21 @@ func NewMockGitserverRepoStore() *MockGitserverRepoStore {
 				return
 			},
 		},
+		SetOffloadKeyFunc: &GitserverRepoStoreSetOffloadKeyFunc{
+			defaultHook: func(context.Context, api.RepoName, string) (r0 error) {
+				return
+			},
+		},
+		SetPushMirrorStatusFunc: &GitserverRepoStoreSetPushMirrorStatusFunc{
+			defaultHook: func(context.Context, api.RepoName, string) (r0 error) {
+				return
+			},
+		},
+		SetReplicaStatusFunc: &GitserverRepoStoreSetReplicaStatusFunc{
+			defaultHook: func(context.Context, api.RepoName, string, bool) (r0 error) {
+				return
+			},
+		},
 		SetRepoSizeFunc: &GitserverRepoStoreSetRepoSizeFunc{
 			defaultHook: func(context.Context, api.RepoName, int64, string) (r0 error) {
 				return
@@ -18588,6 +18612,21 @@ func NewStrictMockGitserverRepoStore() *MockGitserverRepoStore {
 				panic("unexpected invocation of MockGitserverRepoStore.SetLastFetched")
 			},
 		},
+		SetOffloadKeyFunc: &GitserverRepoStoreSetOffloadKeyFunc{
+			defaultHook: func(context.Context, api.RepoName, string) error {
+				panic("unexpected invocation of MockGitserverRepoStore.SetOffloadKey")
+			},
+		},
+		SetPushMirrorStatusFunc: &GitserverRepoStoreSetPushMirrorStatusFunc{
+			defaultHook: func(context.Context, api.RepoName, string) error {
+				panic("unexpected invocation of MockGitserverRepoStore.SetPushMirrorStatus")
+			},
+		},
+		SetReplicaStatusFunc: &GitserverRepoStoreSetReplicaStatusFunc{
+			defaultHook: func(context.Context, api.RepoName, string, bool) error {
+				panic("unexpected invocation of MockGitserverRepoStore.SetReplicaStatus")
+			},
+		},
 		SetRepoSizeFunc: &GitserverRepoStoreSetRepoSizeFunc{
 			defaultHook: func(context.Context, api.RepoName, int64, string) error {
 				panic("unexpected invocation of MockGitserverRepoStore.SetRepoSize")
@@ -18654,6 +18693,15 @@ func NewMockGitserverRepoStoreFrom(i GitserverRepoStore) *MockGitserverRepoStore
 		SetLastFetchedFunc: &GitserverRepoStoreSetLastFetchedFunc{
 			defaultHook: i.SetLastFetched,
 		},
+		SetOffloadKeyFunc: &GitserverRepoStoreSetOffloadKeyFunc{
+			defaultHook: i.SetOffloadKey,
+		},
+		SetPushMirrorStatusFunc: &GitserverRepoStoreSetPushMirrorStatusFunc{
+			defaultHook: i.SetPushMirrorStatus,
+		},
+		SetReplicaStatusFunc: &GitserverRepoStoreSetReplicaStatusFunc{
+			defaultHook: i.SetReplicaStatus,
+		},
 		SetRepoSizeFunc: &GitserverRepoStoreSetRepoSizeFunc{
 			defaultHook: i.SetRepoSize,
 		},
@@ -19883,6 +19931,343 @@ func (c GitserverRepoStoreSetLastFetchedFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// GitserverRepoStoreSetOffloadKeyFunc describes the behavior when the
+// SetOffloadKey method of the parent MockGitserverRepoStore instance is
+// invoked.
+type GitserverRepoStoreSetOffloadKeyFunc struct {
+	defaultHook func(context.Context, api.RepoName, string) error
+	hooks       []func(context.Context, api.RepoName, string) error
+	history     []GitserverRepoStoreSetOffloadKeyFuncCall
+	mutex       sync.Mutex
+}
+
+// SetOffloadKey delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockGitserverRepoStore) SetOffloadKey(v0 context.Context, v1 api.RepoName, v2 string) error {
+	r0 := m.SetOffloadKeyFunc.nextHook()(v0, v1, v2)
+	m.SetOffloadKeyFunc.appendCall(GitserverRepoStoreSetOffloadKeyFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the SetOffloadKey method
+// of the parent MockGitserverRepoStore instance is invoked and the hook
+// queue is empty.
+func (f *GitserverRepoStoreSetOffloadKeyFunc) SetDefaultHook(hook func(context.Context, api.RepoName, string) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SetOffloadKey method of the parent MockGitserverRepoStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *GitserverRepoStoreSetOffloadKeyFunc) PushHook(hook func(context.Context, api.RepoName, string) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverRepoStoreSetOffloadKeyFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, string) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverRepoStoreSetOffloadKeyFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, api.RepoName, string) error {
+		return r0
+	})
+}
+
+func (f *GitserverRepoStoreSetOffloadKeyFunc) nextHook() func(context.Context, api.RepoName, string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverRepoStoreSetOffloadKeyFunc) appendCall(r0 GitserverRepoStoreSetOffloadKeyFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverRepoStoreSetOffloadKeyFuncCall
+// objects describing the invocations of this function.
+func (f *GitserverRepoStoreSetOffloadKeyFunc) History() []GitserverRepoStoreSetOffloadKeyFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverRepoStoreSetOffloadKeyFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverRepoStoreSetOffloadKeyFuncCall is an object that describes an
+// invocation of method SetOffloadKey on an instance of
+// MockGitserverRepoStore.
+type GitserverRepoStoreSetOffloadKeyFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverRepoStoreSetOffloadKeyFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverRepoStoreSetOffloadKeyFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// GitserverRepoStoreSetPushMirrorStatusFunc describes the behavior when the
+// SetPushMirrorStatus method of the parent MockGitserverRepoStore instance
+// is invoked.
+type GitserverRepoStoreSetPushMirrorStatusFunc struct {
+	defaultHook func(context.Context, api.RepoName, string) error
+	hooks       []func(context.Context, api.RepoName, string) error
+	history     []GitserverRepoStoreSetPushMirrorStatusFuncCall
+	mutex       sync.Mutex
+}
+
+// SetPushMirrorStatus delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockGitserverRepoStore) SetPushMirrorStatus(v0 context.Context, v1 api.RepoName, v2 string) error {
+	r0 := m.SetPushMirrorStatusFunc.nextHook()(v0, v1, v2)
+	m.SetPushMirrorStatusFunc.appendCall(GitserverRepoStoreSetPushMirrorStatusFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the SetPushMirrorStatus
+// method of the parent MockGitserverRepoStore instance is invoked and the
+// hook queue is empty.
+func (f *GitserverRepoStoreSetPushMirrorStatusFunc) SetDefaultHook(hook func(context.Context, api.RepoName, string) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SetPushMirrorStatus method of the parent MockGitserverRepoStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *GitserverRepoStoreSetPushMirrorStatusFunc) PushHook(hook func(context.Context, api.RepoName, string) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverRepoStoreSetPushMirrorStatusFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, string) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverRepoStoreSetPushMirrorStatusFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, api.RepoName, string) error {
+		return r0
+	})
+}
+
+func (f *GitserverRepoStoreSetPushMirrorStatusFunc) nextHook() func(context.Context, api.RepoName, string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverRepoStoreSetPushMirrorStatusFunc) appendCall(r0 GitserverRepoStoreSetPushMirrorStatusFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// GitserverRepoStoreSetPushMirrorStatusFuncCall objects describing the
+// invocations of this function.
+func (f *GitserverRepoStoreSetPushMirrorStatusFunc) History() []GitserverRepoStoreSetPushMirrorStatusFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverRepoStoreSetPushMirrorStatusFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverRepoStoreSetPushMirrorStatusFuncCall is an object that describes
+// an invocation of method SetPushMirrorStatus on an instance of
+// MockGitserverRepoStore.
+type GitserverRepoStoreSetPushMirrorStatusFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverRepoStoreSetPushMirrorStatusFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverRepoStoreSetPushMirrorStatusFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// GitserverRepoStoreSetReplicaStatusFunc describes the behavior when the
+// SetReplicaStatus method of the parent MockGitserverRepoStore instance is
+// invoked.
+type GitserverRepoStoreSetReplicaStatusFunc struct {
+	defaultHook func(context.Context, api.RepoName, string, bool) error
+	hooks       []func(context.Context, api.RepoName, string, bool) error
+	history     []GitserverRepoStoreSetReplicaStatusFuncCall
+	mutex       sync.Mutex
+}
+
+// SetReplicaStatus delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockGitserverRepoStore) SetReplicaStatus(v0 context.Context, v1 api.RepoName, v2 string, v3 bool) error {
+	r0 := m.SetReplicaStatusFunc.nextHook()(v0, v1, v2, v3)
+	m.SetReplicaStatusFunc.appendCall(GitserverRepoStoreSetReplicaStatusFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the SetReplicaStatus
+// method of the parent MockGitserverRepoStore instance is invoked and the
+// hook queue is empty.
+func (f *GitserverRepoStoreSetReplicaStatusFunc) SetDefaultHook(hook func(context.Context, api.RepoName, string, bool) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SetReplicaStatus method of the parent MockGitserverRepoStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *GitserverRepoStoreSetReplicaStatusFunc) PushHook(hook func(context.Context, api.RepoName, string, bool) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverRepoStoreSetReplicaStatusFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, string, bool) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverRepoStoreSetReplicaStatusFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, api.RepoName, string, bool) error {
+		return r0
+	})
+}
+
+func (f *GitserverRepoStoreSetReplicaStatusFunc) nextHook() func(context.Context, api.RepoName, string, bool) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverRepoStoreSetReplicaStatusFunc) appendCall(r0 GitserverRepoStoreSetReplicaStatusFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverRepoStoreSetReplicaStatusFuncCall
+// objects describing the invocations of this function.
+func (f *GitserverRepoStoreSetReplicaStatusFunc) History() []GitserverRepoStoreSetReplicaStatusFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverRepoStoreSetReplicaStatusFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverRepoStoreSetReplicaStatusFuncCall is an object that describes an
+// invocation of method SetReplicaStatus on an instance of
+// MockGitserverRepoStore.
+type GitserverRepoStoreSetReplicaStatusFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 bool
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverRepoStoreSetReplicaStatusFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverRepoStoreSetReplicaStatusFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // GitserverRepoStoreSetRepoSizeFunc describes the behavior when the
 // SetRepoSize method of the parent MockGitserverRepoStore instance is
 // invoked.
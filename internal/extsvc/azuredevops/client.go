@@ -0,0 +1,248 @@
+// Package azuredevops implements an Azure DevOps API client. Azure DevOps
+// Services (dev.azure.com) and Azure DevOps Server serve the same REST API, so
+// both are supported by the same client.
+package azuredevops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// apiVersion is the version of the REST API we request. 6.0 is the newest
+// version supported by Azure DevOps Server 2020.
+const apiVersion = "6.0"
+
+// servicesHost is the host of Azure DevOps Services.
+const servicesHost = "dev.azure.com"
+
+// The metric generated here will be named as "src_azuredevops_requests_total".
+var requestCounter = metrics.NewRequestMeter("azuredevops", "Total number of requests sent to the Azure DevOps API.")
+
+// Client access Azure DevOps via the REST API.
+type Client struct {
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// URL is the base URL of Azure DevOps, without an organization or
+	// collection.
+	URL *url.URL
+
+	// Auth is the authentication method used when accessing the server.
+	// Azure DevOps accepts personal access tokens as the password of basic
+	// authentication.
+	Auth auth.Authenticator
+
+	// RateLimit is the self-imposed rate limiter. Azure DevOps delays requests
+	// instead of rejecting them once a client uses too many resources, so we
+	// make sure we don't get there.
+	rateLimit *ratelimit.InstrumentedLimiter
+}
+
+// NewClient returns an authenticated Azure DevOps API client with the
+// provided configuration. If a nil httpClient is provided,
+// httpcli.ExternalDoer will be used.
+func NewClient(urn string, config *schema.AzureDevOpsConnection, httpClient httpcli.Doer) (*Client, error) {
+	u, err := url.Parse(config.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	if httpClient == nil {
+		httpClient = httpcli.ExternalDoer
+	}
+
+	httpClient = requestCounter.Doer(httpClient, func(u *url.URL) string {
+		// The component of the path following "_apis" maps to the type of API
+		// request we are making.
+		parts := strings.Split(u.Path, "/")
+		for i, part := range parts {
+			if part == "_apis" && i+1 < len(parts) {
+				return parts[i+1]
+			}
+		}
+		return ""
+	})
+
+	return &Client{
+		httpClient: httpClient,
+		URL:        extsvc.NormalizeBaseURL(u),
+		Auth:       &auth.BasicAuth{Username: config.Username, Password: config.Token},
+		// Default limits are defined in extsvc.GetLimitFromConfig
+		rateLimit: ratelimit.DefaultRegistry.Get(urn),
+	}, nil
+}
+
+// WithAuthenticator returns a new Client that uses the same configuration,
+// HTTPClient, and RateLimiter as the current Client, except authenticated with
+// the given authenticator instance.
+func (c *Client) WithAuthenticator(a auth.Authenticator) *Client {
+	cc := *c
+	cc.Auth = a
+	return &cc
+}
+
+// ListOptions are the pagination options of list requests that are paginated
+// with continuation tokens.
+type ListOptions struct {
+	// Top is the maximum number of items returned.
+	Top int
+	// ContinuationToken is the token returned by the previous request, if
+	// any.
+	ContinuationToken string
+}
+
+func (o ListOptions) values() url.Values {
+	qs := make(url.Values)
+	if o.Top > 0 {
+		qs.Set("$top", fmt.Sprint(o.Top))
+	}
+	if o.ContinuationToken != "" {
+		qs.Set("continuationToken", o.ContinuationToken)
+	}
+	return qs
+}
+
+// listResponse is the envelope of responses containing a list of items.
+type listResponse[T any] struct {
+	Count int `json:"count"`
+	Value []T `json:"value"`
+}
+
+// list requests a list of items into result, and returns the continuation
+// token of the next page, if there is one.
+func list[T any](ctx context.Context, c *Client, path string, qs url.Values) (items []T, continuationToken string, err error) {
+	req, err := c.newRequest("GET", path, qs, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var resp listResponse[T]
+	header, err := c.do(ctx, req, &resp)
+	if err != nil {
+		return nil, "", err
+	}
+	return resp.Value, header.Get("X-Ms-Continuationtoken"), nil
+}
+
+func (c *Client) get(ctx context.Context, path string, qs url.Values, result any) error {
+	req, err := c.newRequest("GET", path, qs, nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.do(ctx, req, result)
+	return err
+}
+
+func (c *Client) send(ctx context.Context, method, path string, payload, result any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "marshalling request body")
+	}
+
+	req, err := c.newRequest(method, path, nil, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	_, err = c.do(ctx, req, result)
+	return err
+}
+
+// newRequest returns a request for the given path, which is relative to the
+// base URL and must start with the organization.
+func (c *Client) newRequest(method, path string, qs url.Values, body io.Reader) (*http.Request, error) {
+	if qs == nil {
+		qs = make(url.Values)
+	}
+	qs.Set("api-version", apiVersion)
+
+	u := c.URL.ResolveReference(&url.URL{Path: path, RawQuery: qs.Encode()})
+	return http.NewRequest(method, u.String(), body)
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, result any) (http.Header, error) {
+	req, ht := nethttp.TraceRequest(ot.GetTracer(ctx),
+		req.WithContext(ctx),
+		nethttp.OperationName("Azure DevOps"),
+		nethttp.ClientTrace(false))
+	defer ht.Finish()
+
+	if err := c.Auth.Authenticate(req); err != nil {
+		return nil, err
+	}
+
+	if err := c.rateLimit.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Azure DevOps Services responds to requests with invalid credentials
+	// with a sign-in page and a 203 status code instead of a 401.
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 || resp.StatusCode == http.StatusNonAuthoritativeInfo {
+		return nil, errors.WithStack(&httpError{
+			URL:        req.URL,
+			StatusCode: resp.StatusCode,
+			Body:       bs,
+		})
+	}
+
+	if result != nil && len(bs) > 0 {
+		if err := json.Unmarshal(bs, result); err != nil {
+			return nil, err
+		}
+	}
+	return resp.Header, nil
+}
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+	Body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("Azure DevOps API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusNonAuthoritativeInfo
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// IsConflict reports whether err is an API error for a request which
+// conflicts with the current state of a resource, such as an active pull
+// request for the same branches that already exists.
+func IsConflict(err error) bool {
+	var e *httpError
+	return errors.As(err, &e) && e.StatusCode == http.StatusConflict
+}
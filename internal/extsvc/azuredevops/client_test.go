@@ -0,0 +1,99 @@
+package azuredevops
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestClient_list(t *testing.T) {
+	var gotPath, gotQuery, gotUser, gotPassword string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+		gotUser, gotPassword, _ = r.BasicAuth()
+		if r.URL.Query().Get("continuationToken") == "" {
+			w.Header().Set("X-Ms-Continuationtoken", "next")
+		}
+		_, _ = w.Write([]byte(`{"count": 1, "value": [{"id": "p1", "name": "myproject"}]}`))
+	}))
+	defer srv.Close()
+
+	cli, err := NewClient("Test", &schema.AzureDevOpsConnection{Url: srv.URL + "/tfs", Username: "alice", Token: "secret"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	projects, token, err := cli.ListProjects(context.Background(), "myorg", ListOptions{Top: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 1 || projects[0].Name != "myproject" {
+		t.Errorf("unexpected projects: %+v", projects)
+	}
+	if have, want := token, "next"; have != want {
+		t.Errorf("wrong continuation token: have %q, want %q", have, want)
+	}
+	if have, want := gotPath, "/tfs/myorg/_apis/projects"; have != want {
+		t.Errorf("wrong path: have %q, want %q", have, want)
+	}
+	if have, want := gotQuery, "%24top=10&api-version=6.0"; have != want {
+		t.Errorf("wrong query: have %q, want %q", have, want)
+	}
+	if gotUser != "alice" || gotPassword != "secret" {
+		t.Errorf("wrong credentials: have %q:%q", gotUser, gotPassword)
+	}
+
+	_, token, err = cli.ListProjects(context.Background(), "myorg", ListOptions{ContinuationToken: token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		t.Errorf("expected no continuation token, have %q", token)
+	}
+}
+
+func TestClient_errors(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"message": "error"}`))
+	}))
+	defer srv.Close()
+
+	cli, err := NewClient("Test", &schema.AzureDevOpsConnection{Url: srv.URL, Token: "secret"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		status       int
+		notFound     bool
+		unauthorized bool
+		conflict     bool
+	}{
+		{status: http.StatusNonAuthoritativeInfo, unauthorized: true},
+		{status: http.StatusNotFound, notFound: true},
+		{status: http.StatusUnauthorized, unauthorized: true},
+		{status: http.StatusConflict, conflict: true},
+		{status: http.StatusInternalServerError},
+	} {
+		status = tc.status
+		_, err := cli.GetRepository(context.Background(), "myorg", "myproject", "myrepo")
+		if err == nil {
+			t.Fatalf("%d: expected an error", tc.status)
+		}
+		if have := errcode.IsNotFound(err); have != tc.notFound {
+			t.Errorf("%d: IsNotFound: have %v, want %v", tc.status, have, tc.notFound)
+		}
+		if have := errcode.IsUnauthorized(err); have != tc.unauthorized {
+			t.Errorf("%d: IsUnauthorized: have %v, want %v", tc.status, have, tc.unauthorized)
+		}
+		if have := IsConflict(err); have != tc.conflict {
+			t.Errorf("%d: IsConflict: have %v, want %v", tc.status, have, tc.conflict)
+		}
+	}
+}
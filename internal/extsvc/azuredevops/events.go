@@ -0,0 +1,117 @@
+package azuredevops
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ParseWebhookEvent parses the payload of a service hook event sent by Azure
+// DevOps. The returned value is one of the *Event types defined in this file.
+func ParseWebhookEvent(payload []byte) (any, error) {
+	var raw Event
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling event envelope")
+	}
+
+	var target any
+	switch raw.EventType {
+	case "git.pullrequest.created", "git.pullrequest.updated":
+		target = &PullRequestUpdatedEvent{}
+	case "git.pullrequest.merged":
+		target = &PullRequestMergedEvent{}
+	case "ms.vss-code.git-pullrequest-comment-event":
+		target = &PullRequestCommentEvent{}
+	default:
+		return nil, UnknownWebhookEventType(raw.EventType)
+	}
+
+	if err := json.Unmarshal(payload, target); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// Event is the envelope common to all service hook events.
+type Event struct {
+	ID          string    `json:"id"`
+	EventType   string    `json:"eventType"`
+	CreatedDate time.Time `json:"createdDate"`
+}
+
+// PullRequestEvent is an event whose resource is a pull request.
+type PullRequestEvent struct {
+	Event
+	PullRequest PullRequest `json:"resource"`
+}
+
+// PullRequestUpdatedEvent is sent when a pull request is created or updated,
+// including when it's abandoned or reactivated.
+type PullRequestUpdatedEvent struct {
+	PullRequestEvent
+}
+
+// PullRequestMergedEvent is sent when a merge of a pull request is attempted.
+// The status of the pull request is completed if the merge succeeded.
+type PullRequestMergedEvent struct {
+	PullRequestEvent
+}
+
+// PullRequestCommentEvent is sent when a comment is added to a pull request.
+type PullRequestCommentEvent struct {
+	Event
+	Resource struct {
+		Comment     Comment     `json:"comment"`
+		PullRequest PullRequest `json:"pullRequest"`
+	} `json:"resource"`
+}
+
+// UnknownWebhookEventType is returned by ParseWebhookEvent for events it
+// doesn't handle.
+type UnknownWebhookEventType string
+
+var _ error = UnknownWebhookEventType("")
+
+func (e UnknownWebhookEventType) Error() string {
+	return "unknown webhook event type: " + string(e)
+}
+
+// Widgetry to ensure all events are keyers. Every event has a unique ID, but
+// we include the pull request and the comment in the key to make them easier
+// to recognise.
+
+type keyer interface {
+	Key() string
+}
+
+var (
+	_ keyer = &PullRequestUpdatedEvent{}
+	_ keyer = &PullRequestMergedEvent{}
+	_ keyer = &PullRequestCommentEvent{}
+)
+
+func (e *PullRequestUpdatedEvent) Key() string {
+	return e.PullRequestEvent.key() + ":updated:" + e.ID
+}
+
+func (e *PullRequestMergedEvent) Key() string {
+	return e.PullRequestEvent.key() + ":merged:" + e.ID
+}
+
+func (e *PullRequestCommentEvent) Key() string {
+	return pullRequestKey(&e.Resource.PullRequest) + ":comment:" + strconv.FormatInt(e.Resource.Comment.ID, 10)
+}
+
+func (e *PullRequestEvent) key() string {
+	return pullRequestKey(&e.PullRequest)
+}
+
+func pullRequestKey(pr *PullRequest) string {
+	var repo string
+	if pr.Repository != nil {
+		repo = pr.Repository.ID
+	}
+	return repo + ":" + strconv.FormatInt(pr.PullRequestID, 10)
+}
@@ -0,0 +1,48 @@
+package azuredevops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWebhookEvent(t *testing.T) {
+	for eventType, wantType := range map[string]any{
+		"git.pullrequest.created":                   &PullRequestUpdatedEvent{},
+		"git.pullrequest.updated":                   &PullRequestUpdatedEvent{},
+		"git.pullrequest.merged":                    &PullRequestMergedEvent{},
+		"ms.vss-code.git-pullrequest-comment-event": &PullRequestCommentEvent{},
+	} {
+		t.Run(eventType, func(t *testing.T) {
+			have, err := ParseWebhookEvent([]byte(`{"id":"1","eventType":"` + eventType + `","resource":{}}`))
+			assert.Nil(t, err)
+			assert.IsType(t, wantType, have)
+		})
+	}
+
+	t.Run("pull request payload", func(t *testing.T) {
+		have, err := ParseWebhookEvent([]byte(`{
+			"id": "e1",
+			"eventType": "git.pullrequest.updated",
+			"resource": {
+				"pullRequestId": 7,
+				"status": "abandoned",
+				"repository": {"id": "r1", "name": "myrepo"}
+			}
+		}`))
+		assert.Nil(t, err)
+		e := have.(*PullRequestUpdatedEvent)
+		assert.Equal(t, PullRequestStatusAbandoned, e.PullRequest.Status)
+		assert.Equal(t, "r1:7:updated:e1", e.Key())
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := ParseWebhookEvent([]byte("invalid JSON"))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := ParseWebhookEvent([]byte(`{"eventType":"build.complete"}`))
+		assert.Equal(t, UnknownWebhookEventType("build.complete"), err)
+	})
+}
@@ -0,0 +1,134 @@
+package azuredevops
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// CreatePullRequestInput is the payload of CreatePullRequest.
+type CreatePullRequestInput struct {
+	SourceRefName string `json:"sourceRefName"`
+	TargetRefName string `json:"targetRefName"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	IsDraft       bool   `json:"isDraft,omitempty"`
+}
+
+// MergeStrategy is the way a pull request is merged when it's completed.
+type MergeStrategy string
+
+const (
+	MergeStrategyNoFastForward MergeStrategy = "noFastForward"
+	MergeStrategySquash        MergeStrategy = "squash"
+)
+
+// CompletionOptions are the options used when a pull request is completed.
+type CompletionOptions struct {
+	MergeStrategy      MergeStrategy `json:"mergeStrategy,omitempty"`
+	DeleteSourceBranch bool          `json:"deleteSourceBranch"`
+}
+
+// UpdatePullRequestInput is the payload of UpdatePullRequest. Only the set
+// fields are updated.
+type UpdatePullRequestInput struct {
+	Title         *string           `json:"title,omitempty"`
+	Description   *string           `json:"description,omitempty"`
+	TargetRefName string            `json:"targetRefName,omitempty"`
+	Status        PullRequestStatus `json:"status,omitempty"`
+	IsDraft       *bool             `json:"isDraft,omitempty"`
+	// LastMergeSourceCommit must be set to the current source commit to
+	// complete a pull request.
+	LastMergeSourceCommit *CommitRef         `json:"lastMergeSourceCommit,omitempty"`
+	CompletionOptions     *CompletionOptions `json:"completionOptions,omitempty"`
+}
+
+// PullRequestSearchCriteria restricts the pull requests returned by
+// ListPullRequests.
+type PullRequestSearchCriteria struct {
+	SourceRefName string
+	TargetRefName string
+	Status        PullRequestStatus
+}
+
+// CreatePullRequest opens a new pull request in the given repository. If an
+// active pull request for the same branches already exists, an error for
+// which IsConflict returns true is returned.
+func (c *Client) CreatePullRequest(ctx context.Context, repo *Repository, input CreatePullRequestInput) (*PullRequest, error) {
+	var pr PullRequest
+	if err := c.send(ctx, "POST", pullRequestsPath(repo), input, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// ListPullRequests lists the pull requests of the given repository matching
+// the given criteria.
+func (c *Client) ListPullRequests(ctx context.Context, repo *Repository, criteria PullRequestSearchCriteria) ([]*PullRequest, error) {
+	qs := make(url.Values)
+	if criteria.SourceRefName != "" {
+		qs.Set("searchCriteria.sourceRefName", criteria.SourceRefName)
+	}
+	if criteria.TargetRefName != "" {
+		qs.Set("searchCriteria.targetRefName", criteria.TargetRefName)
+	}
+	if criteria.Status != "" {
+		qs.Set("searchCriteria.status", string(criteria.Status))
+	}
+
+	prs, _, err := list[*PullRequest](ctx, c, pullRequestsPath(repo), qs)
+	return prs, err
+}
+
+// GetPullRequest returns the pull request with the given ID.
+func (c *Client) GetPullRequest(ctx context.Context, repo *Repository, id int64) (*PullRequest, error) {
+	var pr PullRequest
+	if err := c.get(ctx, pullRequestPath(repo, id), nil, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// UpdatePullRequest updates the pull request with the given ID, and returns
+// the updated pull request.
+func (c *Client) UpdatePullRequest(ctx context.Context, repo *Repository, id int64, input UpdatePullRequestInput) (*PullRequest, error) {
+	var pr PullRequest
+	if err := c.send(ctx, "PATCH", pullRequestPath(repo, id), input, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// CreatePullRequestComment starts a new thread with the given comment on the
+// pull request with the given ID.
+func (c *Client) CreatePullRequestComment(ctx context.Context, repo *Repository, id int64, content string) error {
+	type comment struct {
+		Content     string `json:"content"`
+		CommentType int    `json:"commentType"`
+	}
+	payload := struct {
+		Comments []comment `json:"comments"`
+		Status   int       `json:"status"`
+	}{
+		// Comment type 1 is a text comment, and thread status 1 an active
+		// thread.
+		Comments: []comment{{Content: content, CommentType: 1}},
+		Status:   1,
+	}
+	return c.send(ctx, "POST", pullRequestPath(repo, id)+"/threads", payload, nil)
+}
+
+// ListPullRequestStatuses lists the statuses posted to the pull request with
+// the given ID.
+func (c *Client) ListPullRequestStatuses(ctx context.Context, repo *Repository, id int64) ([]*Status, error) {
+	statuses, _, err := list[*Status](ctx, c, pullRequestPath(repo, id)+"/statuses", nil)
+	return statuses, err
+}
+
+func pullRequestsPath(repo *Repository) string {
+	return repo.Org + "/" + repo.Project.ID + "/_apis/git/repositories/" + repo.ID + "/pullrequests"
+}
+
+func pullRequestPath(repo *Repository, id int64) string {
+	return pullRequestsPath(repo) + "/" + strconv.FormatInt(id, 10)
+}
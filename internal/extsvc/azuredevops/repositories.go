@@ -0,0 +1,88 @@
+package azuredevops
+
+import (
+	"context"
+	"net/url"
+)
+
+// ListProjects lists a page of the projects of the given organization visible
+// to the authenticated user.
+func (c *Client) ListProjects(ctx context.Context, org string, opts ListOptions) (projects []*Project, continuationToken string, err error) {
+	return list[*Project](ctx, c, org+"/_apis/projects", opts.values())
+}
+
+// ListRepositories lists the repositories of the given project visible to the
+// authenticated user. If project is empty, the repositories of all projects
+// of the organization are listed.
+func (c *Client) ListRepositories(ctx context.Context, org, project string) ([]*Repository, error) {
+	path := org + "/_apis/git/repositories"
+	if project != "" {
+		path = org + "/" + project + "/_apis/git/repositories"
+	}
+
+	repos, _, err := list[*Repository](ctx, c, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range repos {
+		r.Org = org
+	}
+	return repos, nil
+}
+
+// GetRepository returns the repository with the given name or ID in the given
+// project.
+func (c *Client) GetRepository(ctx context.Context, org, project, repo string) (*Repository, error) {
+	var r Repository
+	if err := c.get(ctx, org+"/"+project+"/_apis/git/repositories/"+repo, nil, &r); err != nil {
+		return nil, err
+	}
+	r.Org = org
+	return &r, nil
+}
+
+// GetConnectionData returns the identity the client is authenticated as in
+// the given organization.
+func (c *Client) GetConnectionData(ctx context.Context, org string) (*ConnectionData, error) {
+	var data ConnectionData
+	if err := c.get(ctx, org+"/_apis/connectionData", nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Identity search filters.
+const (
+	IdentitySearchFilterGeneral     = "General"
+	IdentitySearchFilterMailAddress = "MailAddress"
+	IdentitySearchFilterAccountName = "AccountName"
+)
+
+// Identity membership query options.
+const (
+	QueryMembershipNone     = "None"
+	QueryMembershipDirect   = "Direct"
+	QueryMembershipExpanded = "Expanded"
+)
+
+// SearchIdentities returns the identities of the given organization matching
+// the given search filter and value. The membership of the identities is
+// expanded as requested by queryMembership.
+func (c *Client) SearchIdentities(ctx context.Context, org, searchFilter, filterValue, queryMembership string) ([]*Identity, error) {
+	qs := url.Values{
+		"searchFilter":    {searchFilter},
+		"filterValue":     {filterValue},
+		"queryMembership": {queryMembership},
+	}
+
+	// Azure DevOps Services serves the identities API from a separate host.
+	cli := c
+	if c.URL.Host == servicesHost {
+		cc := *c
+		cc.URL = &url.URL{Scheme: c.URL.Scheme, Host: "vssps." + servicesHost, Path: "/"}
+		cli = &cc
+	}
+
+	identities, _, err := list[*Identity](ctx, cli, org+"/_apis/identities", qs)
+	return identities, err
+}
@@ -0,0 +1,180 @@
+package azuredevops
+
+import "time"
+
+// IdentityRef is a reference to a user or group.
+type IdentityRef struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	// UniqueName is the email address of users on Azure DevOps Services, and
+	// the domain and account name on Azure DevOps Server.
+	UniqueName string `json:"uniqueName"`
+	Descriptor string `json:"descriptor,omitempty"`
+}
+
+// Identity is a user or group, as returned by the identities API.
+type Identity struct {
+	ID                  string   `json:"id"`
+	Descriptor          string   `json:"descriptor"`
+	ProviderDisplayName string   `json:"providerDisplayName"`
+	IsActive            bool     `json:"isActive"`
+	IsContainer         bool     `json:"isContainer"`
+	Members             []string `json:"members,omitempty"`
+	MemberOf            []string `json:"memberOf,omitempty"`
+}
+
+// ConnectionData describes the identity the client is authenticated as.
+type ConnectionData struct {
+	AuthenticatedUser Identity `json:"authenticatedUser"`
+}
+
+// ProjectVisibility is the visibility of a project.
+type ProjectVisibility string
+
+const (
+	ProjectVisibilityPrivate ProjectVisibility = "private"
+	ProjectVisibilityPublic  ProjectVisibility = "public"
+)
+
+// Project is an Azure DevOps project.
+type Project struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	State       string            `json:"state,omitempty"`
+	Visibility  ProjectVisibility `json:"visibility,omitempty"`
+}
+
+// Repository is a Git repository in an Azure DevOps project.
+type Repository struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	Project       Project `json:"project"`
+	DefaultBranch string  `json:"defaultBranch,omitempty"`
+	Size          int64   `json:"size,omitempty"`
+	RemoteURL     string  `json:"remoteUrl"`
+	SSHURL        string  `json:"sshUrl"`
+	WebURL        string  `json:"webUrl"`
+	IsDisabled    bool    `json:"isDisabled,omitempty"`
+	IsFork        bool    `json:"isFork,omitempty"`
+
+	// Org is the organization, or collection on Azure DevOps Server, of the
+	// repository. It isn't part of the API response, the Client sets it to the
+	// organization it requested the repository from.
+	Org string `json:"org"`
+}
+
+// FullName returns the name of the repository in the form
+// "org/project/repo".
+func (r *Repository) FullName() string {
+	return r.Org + "/" + r.Project.Name + "/" + r.Name
+}
+
+// PullRequestStatus is the status of a pull request.
+type PullRequestStatus string
+
+const (
+	PullRequestStatusActive    PullRequestStatus = "active"
+	PullRequestStatusAbandoned PullRequestStatus = "abandoned"
+	PullRequestStatusCompleted PullRequestStatus = "completed"
+)
+
+// MergeStatus is the result of the last merge of the source branch of a pull
+// request into its target branch.
+type MergeStatus string
+
+const (
+	MergeStatusNotSet           MergeStatus = "notSet"
+	MergeStatusQueued           MergeStatus = "queued"
+	MergeStatusConflicts        MergeStatus = "conflicts"
+	MergeStatusSucceeded        MergeStatus = "succeeded"
+	MergeStatusRejectedByPolicy MergeStatus = "rejectedByPolicy"
+	MergeStatusFailure          MergeStatus = "failure"
+)
+
+// Votes of pull request reviewers.
+const (
+	VoteApproved                = 10
+	VoteApprovedWithSuggestions = 5
+	VoteNoVote                  = 0
+	VoteWaitingForAuthor        = -5
+	VoteRejected                = -10
+)
+
+// Reviewer is a reviewer of a pull request and their vote.
+type Reviewer struct {
+	IdentityRef
+	Vote       int  `json:"vote"`
+	IsRequired bool `json:"isRequired,omitempty"`
+}
+
+// CommitRef is a reference to a commit.
+type CommitRef struct {
+	CommitID string `json:"commitId"`
+}
+
+// PullRequest is an Azure DevOps pull request.
+type PullRequest struct {
+	PullRequestID         int64             `json:"pullRequestId"`
+	Repository            *Repository       `json:"repository,omitempty"`
+	Status                PullRequestStatus `json:"status"`
+	CreatedBy             IdentityRef       `json:"createdBy"`
+	CreationDate          time.Time         `json:"creationDate"`
+	ClosedDate            time.Time         `json:"closedDate,omitempty"`
+	Title                 string            `json:"title"`
+	Description           string            `json:"description"`
+	SourceRefName         string            `json:"sourceRefName"`
+	TargetRefName         string            `json:"targetRefName"`
+	MergeStatus           MergeStatus       `json:"mergeStatus,omitempty"`
+	IsDraft               bool              `json:"isDraft"`
+	LastMergeSourceCommit *CommitRef        `json:"lastMergeSourceCommit,omitempty"`
+	LastMergeTargetCommit *CommitRef        `json:"lastMergeTargetCommit,omitempty"`
+	Reviewers             []Reviewer        `json:"reviewers"`
+}
+
+// StatusState is the state of a pull request status.
+type StatusState string
+
+const (
+	StatusStateNotSet        StatusState = "notSet"
+	StatusStatePending       StatusState = "pending"
+	StatusStateSucceeded     StatusState = "succeeded"
+	StatusStateFailed        StatusState = "failed"
+	StatusStateError         StatusState = "error"
+	StatusStateNotApplicable StatusState = "notApplicable"
+)
+
+// StatusContext identifies the service that posted a status.
+type StatusContext struct {
+	Name  string `json:"name"`
+	Genre string `json:"genre,omitempty"`
+}
+
+// Status is a status posted to a pull request, such as the result of a build.
+type Status struct {
+	ID           int64         `json:"id"`
+	State        StatusState   `json:"state"`
+	Description  string        `json:"description,omitempty"`
+	Context      StatusContext `json:"context"`
+	CreationDate time.Time     `json:"creationDate"`
+	UpdatedDate  time.Time     `json:"updatedDate"`
+	TargetURL    string        `json:"targetUrl,omitempty"`
+}
+
+// Key returns the key of the status, which is the same for all statuses
+// posted by the same service.
+func (s *Status) Key() string {
+	if s.Context.Genre == "" {
+		return s.Context.Name
+	}
+	return s.Context.Genre + "/" + s.Context.Name
+}
+
+// Comment is a comment in a pull request thread.
+type Comment struct {
+	ID              int64       `json:"id"`
+	Author          IdentityRef `json:"author"`
+	Content         string      `json:"content"`
+	PublishedDate   time.Time   `json:"publishedDate"`
+	LastUpdatedDate time.Time   `json:"lastUpdatedDate"`
+}
@@ -45,6 +45,12 @@ type Client interface {
 	ForkRepository(ctx context.Context, upstream *Repo, input ForkInput) (*Repo, error)
 
 	CurrentUser(ctx context.Context) (*User, error)
+	CurrentUserEmails(ctx context.Context, pageToken *PageToken) ([]*UserEmail, *PageToken, error)
+	CurrentUserRepos(ctx context.Context, pageToken *PageToken, role string) ([]*Repo, *PageToken, error)
+	CurrentUserWorkspacePermissions(ctx context.Context, pageToken *PageToken) ([]*WorkspacePermission, *PageToken, error)
+
+	WorkspacePermissions(ctx context.Context, pageToken *PageToken, workspace string) ([]*WorkspacePermission, *PageToken, error)
+	RepoUserPermissions(ctx context.Context, pageToken *PageToken, workspace, slug string) ([]*RepoPermission, *PageToken, error)
 }
 
 // client access a Bitbucket Cloud via the REST API 2.0.
@@ -33,6 +33,8 @@ func ParseWebhookEvent(eventKey string, payload []byte) (any, error) {
 		target = &RepoCommitStatusCreatedEvent{}
 	case "repo:commit_status_updated":
 		target = &RepoCommitStatusUpdatedEvent{}
+	case "repo:push":
+		target = &PushEvent{}
 	default:
 		return nil, UnknownWebhookEventKey(eventKey)
 	}
@@ -119,6 +121,30 @@ type RepoEvent struct {
 	Repository Repo `json:"repository"`
 }
 
+// PushEvent is sent when branches or tags of a repository were pushed to.
+type PushEvent struct {
+	RepoEvent
+	Push struct {
+		Changes []PushChange `json:"changes"`
+	} `json:"push"`
+}
+
+// PushChange is a change of a single branch or tag in a PushEvent.
+type PushChange struct {
+	New     *PushChangeRef `json:"new"`
+	Old     *PushChangeRef `json:"old"`
+	Created bool           `json:"created"`
+	Closed  bool           `json:"closed"`
+	Forced  bool           `json:"forced"`
+}
+
+// PushChangeRef is the state of a branch or tag before or after a push.
+type PushChangeRef struct {
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Target Commit `json:"target"`
+}
+
 type RepoCommitStatusEvent struct {
 	RepoEvent
 	CommitStatus CommitStatus `json:"commit_status"`
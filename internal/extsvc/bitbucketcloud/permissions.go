@@ -0,0 +1,97 @@
+package bitbucketcloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Permission is the level of access a user has on a repository or in a
+// workspace.
+type Permission string
+
+const (
+	PermissionRead  Permission = "read"
+	PermissionWrite Permission = "write"
+	PermissionAdmin Permission = "admin"
+
+	// Workspace permissions.
+	PermissionOwner        Permission = "owner"
+	PermissionCollaborator Permission = "collaborator"
+	PermissionMember       Permission = "member"
+)
+
+// RepoPermission is the effective permission of a user on a repository,
+// including permissions granted through groups.
+type RepoPermission struct {
+	Permission Permission `json:"permission"`
+	User       *Account   `json:"user"`
+	Repository *Repo      `json:"repository"`
+}
+
+// WorkspacePermission is the membership of a user in a workspace.
+type WorkspacePermission struct {
+	Permission Permission `json:"permission"`
+	User       *Account   `json:"user"`
+	Workspace  *Workspace `json:"workspace"`
+}
+
+// CurrentUserRepos returns the repositories the authenticated user has at
+// least the given role on, which is one of "member", "contributor", "admin"
+// and "owner". Bitbucket Cloud includes repositories the user has access to
+// through groups and project permissions.
+func (c *client) CurrentUserRepos(ctx context.Context, pageToken *PageToken, role string) ([]*Repo, *PageToken, error) {
+	var repos []*Repo
+	var next *PageToken
+	var err error
+	if pageToken.HasMore() {
+		next, err = c.reqPage(ctx, pageToken.Next, &repos)
+	} else {
+		next, err = c.page(ctx, "/2.0/repositories", url.Values{"role": []string{role}}, pageToken, &repos)
+	}
+	return repos, next, err
+}
+
+// CurrentUserWorkspacePermissions returns the workspaces the authenticated
+// user is a member of, along with their permission in each workspace.
+func (c *client) CurrentUserWorkspacePermissions(ctx context.Context, pageToken *PageToken) ([]*WorkspacePermission, *PageToken, error) {
+	var perms []*WorkspacePermission
+	var next *PageToken
+	var err error
+	if pageToken.HasMore() {
+		next, err = c.reqPage(ctx, pageToken.Next, &perms)
+	} else {
+		next, err = c.page(ctx, "/2.0/user/permissions/workspaces", nil, pageToken, &perms)
+	}
+	return perms, next, err
+}
+
+// WorkspacePermissions returns the members of the given workspace, along with
+// their permission in the workspace. The authenticated user must be an
+// administrator of the workspace.
+func (c *client) WorkspacePermissions(ctx context.Context, pageToken *PageToken, workspace string) ([]*WorkspacePermission, *PageToken, error) {
+	var perms []*WorkspacePermission
+	var next *PageToken
+	var err error
+	if pageToken.HasMore() {
+		next, err = c.reqPage(ctx, pageToken.Next, &perms)
+	} else {
+		next, err = c.page(ctx, fmt.Sprintf("/2.0/workspaces/%s/permissions", workspace), nil, pageToken, &perms)
+	}
+	return perms, next, err
+}
+
+// RepoUserPermissions returns the effective permissions of the users who have
+// access to the given repository. The authenticated user must be an
+// administrator of the workspace.
+func (c *client) RepoUserPermissions(ctx context.Context, pageToken *PageToken, workspace, slug string) ([]*RepoPermission, *PageToken, error) {
+	var perms []*RepoPermission
+	var next *PageToken
+	var err error
+	if pageToken.HasMore() {
+		next, err = c.reqPage(ctx, pageToken.Next, &perms)
+	} else {
+		next, err = c.page(ctx, fmt.Sprintf("/2.0/workspaces/%s/permissions/repositories/%s", workspace, slug), nil, pageToken, &perms)
+	}
+	return perms, next, err
+}
@@ -2,8 +2,14 @@ package bitbucketcloud
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"net/url"
 
+	"golang.org/x/oauth2"
+
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -27,3 +33,72 @@ type User struct {
 	IsStaff   bool   `json:"is_staff"`
 	AccountID string `json:"account_id"`
 }
+
+// CurrentUserEmails returns the email addresses of the user associated with
+// the authenticator in use.
+func (c *client) CurrentUserEmails(ctx context.Context, pageToken *PageToken) ([]*UserEmail, *PageToken, error) {
+	var emails []*UserEmail
+	var next *PageToken
+	var err error
+	if pageToken.HasMore() {
+		next, err = c.reqPage(ctx, pageToken.Next, &emails)
+	} else {
+		next, err = c.page(ctx, "/2.0/user/emails", nil, pageToken, &emails)
+	}
+	return emails, next, err
+}
+
+type UserEmail struct {
+	Email       string `json:"email"`
+	IsConfirmed bool   `json:"is_confirmed"`
+	IsPrimary   bool   `json:"is_primary"`
+}
+
+// GetExternalAccountData returns the deserialized user and token from the external account data
+// JSON blob in a typesafe way.
+func GetExternalAccountData(ctx context.Context, data *extsvc.AccountData) (usr *User, tok *oauth2.Token, err error) {
+	if data.Data != nil {
+		var u User
+		if err := encryption.DecryptJSON(ctx, data.Data, &u); err != nil {
+			return nil, nil, err
+		}
+
+		usr = &u
+	}
+
+	if data.AuthData != nil {
+		var t oauth2.Token
+		if err := encryption.DecryptJSON(ctx, data.AuthData, &t); err != nil {
+			return nil, nil, err
+		}
+
+		tok = &t
+	}
+
+	return usr, tok, nil
+}
+
+// SetExternalAccountData sets the user and token into the external account data blob.
+func SetExternalAccountData(data *extsvc.AccountData, user *User, token *oauth2.Token) error {
+	serializedUser, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+	serializedToken, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	data.Data = extsvc.NewUnencryptedData(serializedUser)
+	data.AuthData = extsvc.NewUnencryptedData(serializedToken)
+	return nil
+}
+
+// OAuthEndpoint returns the OAuth endpoint of the Bitbucket Cloud instance with
+// the given base URL, such as https://bitbucket.org.
+func OAuthEndpoint(baseURL *url.URL) oauth2.Endpoint {
+	return oauth2.Endpoint{
+		AuthURL:  baseURL.ResolveReference(&url.URL{Path: "/site/oauth2/authorize"}).String(),
+		TokenURL: baseURL.ResolveReference(&url.URL{Path: "/site/oauth2/access_token"}).String(),
+	}
+}
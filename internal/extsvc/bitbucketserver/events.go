@@ -30,6 +30,9 @@ func ParseWebhookEvent(eventType string, payload []byte) (e any, err error) {
 	case "pr:participant:status":
 		e = &PullRequestParticipantStatusEvent{}
 		return e, json.Unmarshal(payload, e)
+	case "repo:refs_changed":
+		e = &PushEvent{}
+		return e, json.Unmarshal(payload, e)
 	default:
 		return nil, errors.Errorf("unknown webhook event type: %q", eventType)
 	}
@@ -37,6 +40,27 @@ func ParseWebhookEvent(eventType string, payload []byte) (e any, err error) {
 
 type PingEvent struct{}
 
+// PushEvent is sent when refs of a repository were pushed to.
+type PushEvent struct {
+	Date       time.Time   `json:"date"`
+	Actor      User        `json:"actor"`
+	Repository Repo        `json:"repository"`
+	Changes    []RefChange `json:"changes"`
+}
+
+// RefChange is a change of a single ref in a PushEvent.
+type RefChange struct {
+	Ref struct {
+		ID        string `json:"id"`
+		DisplayID string `json:"displayId"`
+		Type      string `json:"type"`
+	} `json:"ref"`
+	RefID    string `json:"refId"`
+	FromHash string `json:"fromHash"`
+	ToHash   string `json:"toHash"`
+	Type     string `json:"type"`
+}
+
 type PullRequestActivityEvent struct {
 	Date        time.Time      `json:"date"`
 	Actor       User           `json:"actor"`
@@ -0,0 +1,183 @@
+package gerrit
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ChangeStatus is the status of a change.
+type ChangeStatus string
+
+const (
+	ChangeStatusNew       ChangeStatus = "NEW"
+	ChangeStatusMerged    ChangeStatus = "MERGED"
+	ChangeStatusAbandoned ChangeStatus = "ABANDONED"
+)
+
+// Labels that have a special meaning for batch changes.
+const (
+	// LabelCodeReview is the label reviewers vote on.
+	LabelCodeReview = "Code-Review"
+	// LabelVerified is the label CI systems conventionally vote on.
+	LabelVerified = "Verified"
+)
+
+// Change is a Gerrit change, as returned by the changes endpoints with the
+// options requested by GetChange.
+type Change struct {
+	ID              string                   `json:"id"`
+	Project         string                   `json:"project"`
+	Branch          string                   `json:"branch"`
+	Topic           string                   `json:"topic,omitempty"`
+	ChangeID        string                   `json:"change_id"`
+	Subject         string                   `json:"subject"`
+	Status          ChangeStatus             `json:"status"`
+	Created         Timestamp                `json:"created"`
+	Updated         Timestamp                `json:"updated"`
+	Submitted       *Timestamp               `json:"submitted,omitempty"`
+	Mergeable       *bool                    `json:"mergeable,omitempty"`
+	WorkInProgress  bool                     `json:"work_in_progress,omitempty"`
+	Number          int64                    `json:"_number"`
+	Owner           Account                  `json:"owner"`
+	Labels          map[string]LabelInfo     `json:"labels,omitempty"`
+	CurrentRevision string                   `json:"current_revision,omitempty"`
+	Revisions       map[string]*RevisionInfo `json:"revisions,omitempty"`
+}
+
+// CurrentRevisionInfo returns the current revision of the change, if it was
+// included in the response.
+func (c *Change) CurrentRevisionInfo() *RevisionInfo {
+	return c.Revisions[c.CurrentRevision]
+}
+
+// LabelInfo describes the votes on a label of a change.
+type LabelInfo struct {
+	Approved    *Account       `json:"approved,omitempty"`
+	Rejected    *Account       `json:"rejected,omitempty"`
+	Recommended *Account       `json:"recommended,omitempty"`
+	Disliked    *Account       `json:"disliked,omitempty"`
+	All         []ApprovalInfo `json:"all,omitempty"`
+}
+
+// ApprovalInfo is the vote of a reviewer on a label.
+type ApprovalInfo struct {
+	Account
+	Value int       `json:"value"`
+	Date  Timestamp `json:"date"`
+}
+
+// RevisionInfo is a patch set of a change.
+type RevisionInfo struct {
+	Number int64       `json:"_number"`
+	Ref    string      `json:"ref"`
+	Commit *CommitInfo `json:"commit,omitempty"`
+}
+
+// CommitInfo is the commit of a patch set.
+type CommitInfo struct {
+	Parents []struct {
+		Commit string `json:"commit"`
+	} `json:"parents"`
+	Subject string `json:"subject"`
+	Message string `json:"message"`
+}
+
+// Timestamp is a timestamp in the format used by Gerrit, which is always in
+// UTC.
+type Timestamp struct {
+	time.Time
+}
+
+const timestampLayout = "2006-01-02 15:04:05.000000000"
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	s, err := strconv.Unquote(string(data))
+	if err != nil {
+		return err
+	}
+	if s == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+	t.Time, err = time.Parse(timestampLayout, s)
+	return err
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte(`""`), nil
+	}
+	return []byte(strconv.Quote(t.UTC().Format(timestampLayout))), nil
+}
+
+// changeOptions are the additional fields requested for changes.
+var changeOptions = []string{"DETAILED_LABELS", "DETAILED_ACCOUNTS", "CURRENT_REVISION", "CURRENT_COMMIT"}
+
+// GetChange returns the change with the given Change-Id or number in the
+// given project. If there is no such change, an error for which
+// errcode.IsNotFound returns true is returned.
+func (c *Client) GetChange(ctx context.Context, project, changeID string) (*Change, error) {
+	qs := make(url.Values)
+	qs.Set("q", "project:"+strconv.Quote(project)+" change:"+changeID)
+	for _, o := range changeOptions {
+		qs.Add("o", o)
+	}
+
+	req, err := http.NewRequest("GET", (&url.URL{Path: "a/changes/", RawQuery: qs.Encode()}).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []*Change
+	if _, err := c.do(ctx, req, &changes); err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, &httpError{URL: req.URL, StatusCode: http.StatusNotFound, Body: []byte("change not found")}
+	}
+	return changes[0], nil
+}
+
+// AbandonChange abandons the given change.
+func (c *Client) AbandonChange(ctx context.Context, change *Change) error {
+	return c.post(ctx, changePath(change)+"/abandon", nil, nil)
+}
+
+// RestoreChange restores the given abandoned change.
+func (c *Client) RestoreChange(ctx context.Context, change *Change) error {
+	return c.post(ctx, changePath(change)+"/restore", nil, nil)
+}
+
+// SubmitChange submits the given change. If the change can't be merged, an
+// error for which IsConflict returns true is returned.
+func (c *Client) SubmitChange(ctx context.Context, change *Change) error {
+	return c.post(ctx, changePath(change)+"/submit", nil, nil)
+}
+
+// SetWorkInProgress marks the given change as work in progress.
+func (c *Client) SetWorkInProgress(ctx context.Context, change *Change) error {
+	return c.post(ctx, changePath(change)+"/wip", nil, nil)
+}
+
+// SetReadyForReview marks the given change as ready for review.
+func (c *Client) SetReadyForReview(ctx context.Context, change *Change) error {
+	return c.post(ctx, changePath(change)+"/ready", nil, nil)
+}
+
+// PostReviewComment posts a review message on the current patch set of the
+// given change.
+func (c *Client) PostReviewComment(ctx context.Context, change *Change, message string) error {
+	payload := struct {
+		Message string `json:"message"`
+	}{Message: message}
+	return c.post(ctx, changePath(change)+"/revisions/current/review", payload, nil)
+}
+
+// changePath returns the escaped path of the given change. The ID of a change
+// has the form "project~number", where the project is URL encoded.
+func changePath(change *Change) string {
+	return "a/changes/" + url.PathEscape(change.Project) + "~" + strconv.FormatInt(change.Number, 10)
+}
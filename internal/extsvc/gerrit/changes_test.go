@@ -0,0 +1,36 @@
+package gerrit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestamp(t *testing.T) {
+	var change Change
+	if err := json.Unmarshal([]byte(`{"created": "2022-10-04 12:34:56.789000000", "updated": ""}`), &change); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := time.Date(2022, 10, 4, 12, 34, 56, 789000000, time.UTC); !change.Created.Equal(want) {
+		t.Errorf("unexpected created timestamp: have %s; want %s", change.Created, want)
+	}
+	if !change.Updated.IsZero() {
+		t.Errorf("unexpected updated timestamp: have %s; want zero", change.Updated)
+	}
+
+	data, err := json.Marshal(change.Created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := string(data), `"2022-10-04 12:34:56.789000000"`; have != want {
+		t.Errorf("unexpected marshalled timestamp: have %s; want %s", have, want)
+	}
+}
+
+func TestChangePath(t *testing.T) {
+	have := changePath(&Change{Project: "org/repo", Number: 42})
+	if want := "a/changes/org%2Frepo~42"; have != want {
+		t.Errorf("unexpected change path: have %s; want %s", have, want)
+	}
+}
@@ -2,16 +2,18 @@
 package gerrit
 
 import (
+	"bytes"
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -26,6 +28,10 @@ type Client struct {
 	// URL is the base URL of Gerrit.
 	URL *url.URL
 
+	// Auth is the authenticator used for requests. It defaults to the
+	// username and password of the connection config.
+	Auth auth.Authenticator
+
 	// RateLimit is the self-imposed rate limiter (since Gerrit does not have a concept
 	// of rate limiting in HTTP response headers).
 	rateLimit *ratelimit.InstrumentedLimiter
@@ -48,10 +54,19 @@ func NewClient(urn string, config *schema.GerritConnection, httpClient httpcli.D
 		httpClient: httpClient,
 		Config:     config,
 		URL:        u,
+		Auth:       &auth.BasicAuth{Username: config.Username, Password: config.Password},
 		rateLimit:  ratelimit.DefaultRegistry.Get(urn),
 	}, nil
 }
 
+// WithAuthenticator returns a copy of the Client authenticated as the given
+// authenticator.
+func (c *Client) WithAuthenticator(a auth.Authenticator) *Client {
+	cc := *c
+	cc.Auth = a
+	return &cc
+}
+
 type ListAccountsResponse []Account
 
 func (c *Client) ListAccountsByEmail(ctx context.Context, email string) (ListAccountsResponse, error) {
@@ -85,6 +100,21 @@ func (c *Client) listAccounts(ctx context.Context, qsAccounts url.Values) (ListA
 	return respAllAccts, nil
 }
 
+// GetAuthenticatedUserAccount returns the account the client is
+// authenticated as.
+func (c *Client) GetAuthenticatedUserAccount(ctx context.Context) (*Account, error) {
+	req, err := http.NewRequest("GET", "a/accounts/self", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var account Account
+	if _, err = c.do(ctx, req, &account); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
 func (c *Client) GetGroup(ctx context.Context, groupName string) (Group, error) {
 
 	urlGroup := url.URL{Path: fmt.Sprintf("a/groups/%s", groupName)}
@@ -166,12 +196,38 @@ func (c *Client) ListProjects(ctx context.Context, opts ListProjectsArgs) (proje
 	return &respCodeProjects, nextPage, nil
 }
 
+// post sends payload as JSON to the given escaped path, and decodes the
+// response into result unless it's nil.
+func (c *Client) post(ctx context.Context, path string, payload, result any) error {
+	var body io.Reader
+	if payload != nil {
+		bs, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(bs)
+	}
+
+	req, err := http.NewRequest("POST", path, body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	_, err = c.do(ctx, req, result)
+	return err
+}
+
 // nolint:unparam
 func (c *Client) do(ctx context.Context, req *http.Request, result any) (*http.Response, error) {
 	req.URL = c.URL.ResolveReference(req.URL)
 
 	// Add Basic Auth headers for authenticated requests.
-	req.Header.Add("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(c.Config.Username+":"+c.Config.Password)))
+	if err := c.Auth.Authenticate(req); err != nil {
+		return nil, err
+	}
 
 	if err := c.rateLimit.Wait(ctx); err != nil {
 		return nil, err
@@ -199,6 +255,12 @@ func (c *Client) do(ctx context.Context, req *http.Request, result any) (*http.R
 		}
 	}
 
+	// Some endpoints, such as the ones marking changes as work in progress,
+	// don't respond with JSON.
+	if result == nil {
+		return resp, nil
+	}
+
 	// The first 4 characters of the Gerrit API responses need to be stripped, see: https://gerrit-review.googlesource.com/Documentation/rest-api.html#output .
 	if len(bs) < 4 {
 		return nil, &httpError{
@@ -265,3 +327,11 @@ func (e *httpError) Unauthorized() bool {
 func (e *httpError) NotFound() bool {
 	return e.StatusCode == http.StatusNotFound
 }
+
+// IsConflict reports whether err is an API error for a request which
+// conflicts with the current state of a change, such as submitting a change
+// which can't be merged.
+func IsConflict(err error) bool {
+	var e *httpError
+	return errors.As(err, &e) && e.StatusCode == http.StatusConflict
+}
@@ -0,0 +1,258 @@
+// Package gitea implements a Gitea API client. Forgejo, a fork of Gitea,
+// serves the same API and is supported by the same client.
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// The metric generated here will be named as "src_gitea_requests_total".
+var requestCounter = metrics.NewRequestMeter("gitea", "Total number of requests sent to the Gitea API.")
+
+// Client access a Gitea instance via the REST API v1.
+type Client struct {
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// URL is the base URL of Gitea.
+	URL *url.URL
+
+	// Auth is the authentication method used when accessing the server.
+	// Gitea accepts access tokens as OAuth bearer tokens.
+	Auth auth.Authenticator
+
+	// sudo is the username of the user to impersonate in requests, if any.
+	// Only site admins can impersonate other users.
+	sudo string
+
+	// RateLimit is the self-imposed rate limiter (since Gitea does not have a concept
+	// of rate limiting in HTTP response headers).
+	rateLimit *ratelimit.InstrumentedLimiter
+}
+
+// NewClient returns an authenticated Gitea API client with the provided
+// configuration. If a nil httpClient is provided, httpcli.ExternalDoer will be
+// used.
+func NewClient(urn string, config *schema.GiteaConnection, httpClient httpcli.Doer) (*Client, error) {
+	u, err := url.Parse(config.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	if httpClient == nil {
+		httpClient = httpcli.ExternalDoer
+	}
+
+	httpClient = requestCounter.Doer(httpClient, func(u *url.URL) string {
+		// The fourth component of the path ("/api/v1/<category>/...") maps
+		// to the type of API request we are making.
+		var category string
+		if parts := strings.SplitN(u.Path, "/", 5); len(parts) > 3 {
+			category = parts[3]
+		}
+		return category
+	})
+
+	return &Client{
+		httpClient: httpClient,
+		URL:        extsvc.NormalizeBaseURL(u),
+		Auth:       &auth.OAuthBearerToken{Token: config.Token},
+		// Default limits are defined in extsvc.GetLimitFromConfig
+		rateLimit: ratelimit.DefaultRegistry.Get(urn),
+	}, nil
+}
+
+// WithAuthenticator returns a new Client that uses the same configuration,
+// HTTPClient, and RateLimiter as the current Client, except authenticated with
+// the given authenticator instance.
+func (c *Client) WithAuthenticator(a auth.Authenticator) *Client {
+	cc := *c
+	cc.Auth = a
+	return &cc
+}
+
+// WithSudo returns a new Client that performs all requests on behalf of the
+// user with the given username. The Client must be authenticated as a site
+// admin.
+func (c *Client) WithSudo(username string) *Client {
+	cc := *c
+	cc.sudo = username
+	return &cc
+}
+
+// ListOptions are the pagination options of list requests. Pages start at 1.
+type ListOptions struct {
+	Page  int
+	Limit int
+}
+
+func (o ListOptions) values() url.Values {
+	qs := make(url.Values)
+	if o.Page > 0 {
+		qs.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.Limit > 0 {
+		qs.Set("limit", strconv.Itoa(o.Limit))
+	}
+	return qs
+}
+
+// list requests a page of a paginated list into result, and reports whether
+// there is a next page.
+func (c *Client) list(ctx context.Context, path string, qs url.Values, opts ListOptions, result any) (hasNextPage bool, err error) {
+	if qs == nil {
+		qs = make(url.Values)
+	}
+	for k, vs := range opts.values() {
+		qs[k] = vs
+	}
+
+	u := url.URL{Path: path, RawQuery: qs.Encode()}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return false, err
+	}
+
+	header, err := c.do(ctx, req, result)
+	if err != nil {
+		return false, err
+	}
+	return hasNextLink(header.Get("Link")), nil
+}
+
+// hasNextLink reports whether the given Link header contains a link to the
+// next page.
+func hasNextLink(link string) bool {
+	for _, l := range strings.Split(link, ",") {
+		if strings.Contains(l, `rel="next"`) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) get(ctx context.Context, path string, result any) error {
+	req, err := http.NewRequest("GET", (&url.URL{Path: path}).String(), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.do(ctx, req, result)
+	return err
+}
+
+func (c *Client) send(ctx context.Context, method, path string, payload, result any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "marshalling request body")
+	}
+
+	req, err := http.NewRequest(method, (&url.URL{Path: path}).String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	_, err = c.do(ctx, req, result)
+	return err
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, result any) (http.Header, error) {
+	req.URL = c.URL.ResolveReference(&url.URL{
+		Path:     "/api/v1" + req.URL.Path,
+		RawQuery: req.URL.RawQuery,
+	})
+
+	req, ht := nethttp.TraceRequest(ot.GetTracer(ctx),
+		req.WithContext(ctx),
+		nethttp.OperationName("Gitea"),
+		nethttp.ClientTrace(false))
+	defer ht.Finish()
+
+	if err := c.Auth.Authenticate(req); err != nil {
+		return nil, err
+	}
+	if c.sudo != "" {
+		req.Header.Set("Sudo", c.sudo)
+	}
+
+	if err := c.rateLimit.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, errors.WithStack(&httpError{
+			URL:        req.URL,
+			StatusCode: resp.StatusCode,
+			Body:       bs,
+		})
+	}
+
+	if result != nil && len(bs) > 0 {
+		if err := json.Unmarshal(bs, result); err != nil {
+			return nil, err
+		}
+	}
+	return resp.Header, nil
+}
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+	Body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("Gitea API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// IsConflict reports whether err is an API error for a request which
+// conflicts with the current state of a resource, such as a pull request
+// which already exists.
+func IsConflict(err error) bool {
+	var e *httpError
+	return errors.As(err, &e) && e.StatusCode == http.StatusConflict
+}
+
+// IsNotMergeable reports whether err is an API error for a pull request
+// which can't be merged in its current state.
+func IsNotMergeable(err error) bool {
+	var e *httpError
+	return errors.As(err, &e) && (e.StatusCode == http.StatusMethodNotAllowed || e.StatusCode == http.StatusConflict)
+}
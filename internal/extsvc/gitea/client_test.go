@@ -0,0 +1,107 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestClient_list(t *testing.T) {
+	var gotPath, gotQuery, gotAuth, gotSudo string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+		gotAuth, gotSudo = r.Header.Get("Authorization"), r.Header.Get("Sudo")
+		if r.URL.Query().Get("page") == "1" {
+			w.Header().Set("Link", `<https://gitea.example.com/api/v1/orgs/myorg/repos?page=2>; rel="next",<https://gitea.example.com/api/v1/orgs/myorg/repos?page=2>; rel="last"`)
+		}
+		_, _ = w.Write([]byte(`[{"id": 1, "full_name": "myorg/myrepo"}]`))
+	}))
+	defer srv.Close()
+
+	cli, err := NewClient("Test", &schema.GiteaConnection{Url: srv.URL, Token: "secret"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli = cli.WithSudo("alice")
+
+	repos, hasNextPage, err := cli.ListOrgRepos(context.Background(), "myorg", ListOptions{Page: 1, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || repos[0].FullName != "myorg/myrepo" {
+		t.Errorf("unexpected repos: %+v", repos)
+	}
+	if !hasNextPage {
+		t.Error("expected a next page")
+	}
+	if have, want := gotPath, "/api/v1/orgs/myorg/repos"; have != want {
+		t.Errorf("wrong path: have %q, want %q", have, want)
+	}
+	if have, want := gotQuery, "limit=10&page=1"; have != want {
+		t.Errorf("wrong query: have %q, want %q", have, want)
+	}
+	if have, want := gotAuth, "Bearer secret"; have != want {
+		t.Errorf("wrong authorization: have %q, want %q", have, want)
+	}
+	if have, want := gotSudo, "alice"; have != want {
+		t.Errorf("wrong sudo: have %q, want %q", have, want)
+	}
+
+	_, hasNextPage, err = cli.ListOrgRepos(context.Background(), "myorg", ListOptions{Page: 2, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasNextPage {
+		t.Error("expected no next page")
+	}
+}
+
+func TestClient_errors(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"message": "error"}`))
+	}))
+	defer srv.Close()
+
+	cli, err := NewClient("Test", &schema.GiteaConnection{Url: srv.URL, Token: "secret"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		status       int
+		notFound     bool
+		unauthorized bool
+		conflict     bool
+		notMergeable bool
+	}{
+		{status: http.StatusNotFound, notFound: true},
+		{status: http.StatusUnauthorized, unauthorized: true},
+		{status: http.StatusConflict, conflict: true, notMergeable: true},
+		{status: http.StatusMethodNotAllowed, notMergeable: true},
+		{status: http.StatusInternalServerError},
+	} {
+		status = tc.status
+		_, err := cli.GetRepo(context.Background(), "myorg", "myrepo")
+		if err == nil {
+			t.Fatalf("%d: expected an error", tc.status)
+		}
+		if have := errcode.IsNotFound(err); have != tc.notFound {
+			t.Errorf("%d: IsNotFound: have %v, want %v", tc.status, have, tc.notFound)
+		}
+		if have := errcode.IsUnauthorized(err); have != tc.unauthorized {
+			t.Errorf("%d: IsUnauthorized: have %v, want %v", tc.status, have, tc.unauthorized)
+		}
+		if have := IsConflict(err); have != tc.conflict {
+			t.Errorf("%d: IsConflict: have %v, want %v", tc.status, have, tc.conflict)
+		}
+		if have := IsNotMergeable(err); have != tc.notMergeable {
+			t.Errorf("%d: IsNotMergeable: have %v, want %v", tc.status, have, tc.notMergeable)
+		}
+	}
+}
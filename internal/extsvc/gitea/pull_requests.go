@@ -0,0 +1,114 @@
+package gitea
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// CreatePullRequestInput are the fields of a new pull request. Head is the
+// name of the branch to merge, prefixed with "<owner>:" if it is in a fork.
+type CreatePullRequestInput struct {
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// EditPullRequestInput are the fields of a pull request to update. Empty
+// fields are left unchanged.
+type EditPullRequestInput struct {
+	Title string           `json:"title,omitempty"`
+	Body  *string          `json:"body,omitempty"`
+	Base  string           `json:"base,omitempty"`
+	State PullRequestState `json:"state,omitempty"`
+}
+
+// MergeStyle is the way a pull request is merged.
+type MergeStyle string
+
+const (
+	MergeStyleMerge  MergeStyle = "merge"
+	MergeStyleSquash MergeStyle = "squash"
+)
+
+// CreatePullRequest creates a pull request in the given repository. If an
+// open pull request for the same branches exists already, an error for which
+// IsConflict returns true is returned.
+func (c *Client) CreatePullRequest(ctx context.Context, owner, name string, input CreatePullRequestInput) (*PullRequest, error) {
+	var pr PullRequest
+	if err := c.send(ctx, "POST", repoPath(owner, name)+"/pulls", input, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// ListPullRequests lists the pull requests of the given repository in the
+// given state, which is one of "open", "closed" or "all".
+func (c *Client) ListPullRequests(ctx context.Context, owner, name, state string, opts ListOptions) (prs []*PullRequest, hasNextPage bool, err error) {
+	qs := make(url.Values)
+	if state != "" {
+		qs.Set("state", state)
+	}
+	hasNextPage, err = c.list(ctx, repoPath(owner, name)+"/pulls", qs, opts, &prs)
+	return prs, hasNextPage, err
+}
+
+// GetPullRequest returns the pull request with the given number.
+func (c *Client) GetPullRequest(ctx context.Context, owner, name string, number int64) (*PullRequest, error) {
+	var pr PullRequest
+	if err := c.get(ctx, pullPath(owner, name, number), &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// EditPullRequest updates the pull request with the given number, and
+// returns the updated pull request.
+func (c *Client) EditPullRequest(ctx context.Context, owner, name string, number int64, input EditPullRequestInput) (*PullRequest, error) {
+	var pr PullRequest
+	if err := c.send(ctx, "PATCH", pullPath(owner, name, number), input, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// MergePullRequest merges the pull request with the given number. If it can't
+// be merged in its current state, an error for which IsNotMergeable returns
+// true is returned.
+func (c *Client) MergePullRequest(ctx context.Context, owner, name string, number int64, style MergeStyle) error {
+	payload := struct {
+		Do MergeStyle `json:"Do"`
+	}{Do: style}
+	return c.send(ctx, "POST", pullPath(owner, name, number)+"/merge", payload, nil)
+}
+
+// CreatePullRequestComment posts a comment on the pull request with the given
+// number.
+func (c *Client) CreatePullRequestComment(ctx context.Context, owner, name string, number int64, body string) error {
+	payload := struct {
+		Body string `json:"body"`
+	}{Body: body}
+	// Pull requests share their numbers and comments with issues.
+	return c.send(ctx, "POST", repoPath(owner, name)+"/issues/"+strconv.FormatInt(number, 10)+"/comments", payload, nil)
+}
+
+// ListPullReviews lists the reviews of the pull request with the given number.
+func (c *Client) ListPullReviews(ctx context.Context, owner, name string, number int64, opts ListOptions) (reviews []*PullReview, hasNextPage bool, err error) {
+	hasNextPage, err = c.list(ctx, pullPath(owner, name, number)+"/reviews", nil, opts, &reviews)
+	return reviews, hasNextPage, err
+}
+
+// GetCombinedStatus returns the combined status of the given commit, branch
+// or tag.
+func (c *Client) GetCombinedStatus(ctx context.Context, owner, name, ref string) (*CombinedStatus, error) {
+	var status CombinedStatus
+	if err := c.get(ctx, repoPath(owner, name)+"/commits/"+ref+"/status", &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func pullPath(owner, name string, number int64) string {
+	return repoPath(owner, name) + "/pulls/" + strconv.FormatInt(number, 10)
+}
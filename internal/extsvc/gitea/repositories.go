@@ -0,0 +1,52 @@
+package gitea
+
+import "context"
+
+// ListOrgRepos lists the repositories of the given organization visible to
+// the authenticated user.
+func (c *Client) ListOrgRepos(ctx context.Context, org string, opts ListOptions) (repos []*Repository, hasNextPage bool, err error) {
+	hasNextPage, err = c.list(ctx, "/orgs/"+org+"/repos", nil, opts, &repos)
+	return repos, hasNextPage, err
+}
+
+// ListUserRepos lists the repositories owned by the given user visible to the
+// authenticated user.
+func (c *Client) ListUserRepos(ctx context.Context, username string, opts ListOptions) (repos []*Repository, hasNextPage bool, err error) {
+	hasNextPage, err = c.list(ctx, "/users/"+username+"/repos", nil, opts, &repos)
+	return repos, hasNextPage, err
+}
+
+// ListAccessibleRepos lists all repositories the authenticated user can read,
+// including the repositories of their organizations and the ones they
+// collaborate on.
+func (c *Client) ListAccessibleRepos(ctx context.Context, opts ListOptions) (repos []*Repository, hasNextPage bool, err error) {
+	hasNextPage, err = c.list(ctx, "/user/repos", nil, opts, &repos)
+	return repos, hasNextPage, err
+}
+
+// GetRepo returns the repository with the given owner and name.
+func (c *Client) GetRepo(ctx context.Context, owner, name string) (*Repository, error) {
+	var repo Repository
+	if err := c.get(ctx, repoPath(owner, name), &repo); err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+// ForkRepo forks the given repository into the given organization, or into the
+// namespace of the authenticated user if org is empty.
+func (c *Client) ForkRepo(ctx context.Context, owner, name, org string) (*Repository, error) {
+	payload := struct {
+		Organization string `json:"organization,omitempty"`
+	}{Organization: org}
+
+	var fork Repository
+	if err := c.send(ctx, "POST", repoPath(owner, name)+"/forks", payload, &fork); err != nil {
+		return nil, err
+	}
+	return &fork, nil
+}
+
+func repoPath(owner, name string) string {
+	return "/repos/" + owner + "/" + name
+}
@@ -0,0 +1,134 @@
+package gitea
+
+import "time"
+
+// User is a Gitea user or organization.
+type User struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	FullName  string `json:"full_name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+	IsAdmin   bool   `json:"is_admin"`
+}
+
+// Repository is a Gitea repository.
+type Repository struct {
+	ID            int64       `json:"id"`
+	Owner         *User       `json:"owner"`
+	Name          string      `json:"name"`
+	FullName      string      `json:"full_name"`
+	Description   string      `json:"description"`
+	Empty         bool        `json:"empty"`
+	Private       bool        `json:"private"`
+	Fork          bool        `json:"fork"`
+	Parent        *Repository `json:"parent,omitempty"`
+	Mirror        bool        `json:"mirror"`
+	Archived      bool        `json:"archived"`
+	HTMLURL       string      `json:"html_url"`
+	SSHURL        string      `json:"ssh_url"`
+	CloneURL      string      `json:"clone_url"`
+	DefaultBranch string      `json:"default_branch"`
+	StarsCount    int         `json:"stars_count"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	Permissions   *Permission `json:"permissions,omitempty"`
+}
+
+// Permission is the permission of the authenticated user on a repository.
+type Permission struct {
+	Admin bool `json:"admin"`
+	Push  bool `json:"push"`
+	Pull  bool `json:"pull"`
+}
+
+// PullRequestState is the state of a pull request. Merged pull requests are
+// closed and have Merged set.
+type PullRequestState string
+
+const (
+	PullRequestStateOpen   PullRequestState = "open"
+	PullRequestStateClosed PullRequestState = "closed"
+)
+
+// PullRequest is a Gitea pull request.
+type PullRequest struct {
+	ID        int64            `json:"id"`
+	Number    int64            `json:"number"`
+	User      *User            `json:"user"`
+	Title     string           `json:"title"`
+	Body      string           `json:"body"`
+	State     PullRequestState `json:"state"`
+	HTMLURL   string           `json:"html_url"`
+	Mergeable bool             `json:"mergeable"`
+	Merged    bool             `json:"merged"`
+	MergedAt  *time.Time       `json:"merged_at"`
+	Base      *PRBranchInfo    `json:"base"`
+	Head      *PRBranchInfo    `json:"head"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	ClosedAt  *time.Time       `json:"closed_at"`
+}
+
+// PRBranchInfo is the base or head branch of a pull request.
+type PRBranchInfo struct {
+	Label  string      `json:"label"`
+	Ref    string      `json:"ref"`
+	Sha    string      `json:"sha"`
+	RepoID int64       `json:"repo_id"`
+	Repo   *Repository `json:"repo"`
+}
+
+// ReviewState is the state of a pull request review.
+type ReviewState string
+
+const (
+	ReviewStateApproved       ReviewState = "APPROVED"
+	ReviewStatePending        ReviewState = "PENDING"
+	ReviewStateComment        ReviewState = "COMMENT"
+	ReviewStateRequestChanges ReviewState = "REQUEST_CHANGES"
+	ReviewStateRequestReview  ReviewState = "REQUEST_REVIEW"
+)
+
+// PullReview is a review of a pull request.
+type PullReview struct {
+	ID          int64       `json:"id"`
+	User        *User       `json:"user"`
+	State       ReviewState `json:"state"`
+	Body        string      `json:"body"`
+	CommitID    string      `json:"commit_id"`
+	Stale       bool        `json:"stale"`
+	Official    bool        `json:"official"`
+	Dismissed   bool        `json:"dismissed"`
+	SubmittedAt time.Time   `json:"submitted_at"`
+}
+
+// CommitStatusState is the state of a commit status.
+type CommitStatusState string
+
+const (
+	CommitStatusPending CommitStatusState = "pending"
+	CommitStatusSuccess CommitStatusState = "success"
+	CommitStatusError   CommitStatusState = "error"
+	CommitStatusFailure CommitStatusState = "failure"
+	CommitStatusWarning CommitStatusState = "warning"
+)
+
+// CommitStatus is the status of a commit reported by a CI system.
+type CommitStatus struct {
+	ID          int64             `json:"id"`
+	State       CommitStatusState `json:"status"`
+	TargetURL   string            `json:"target_url"`
+	Description string            `json:"description"`
+	Context     string            `json:"context"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// CombinedStatus is the combined state of the latest statuses of a commit per
+// context.
+type CombinedStatus struct {
+	State    CommitStatusState `json:"state"`
+	SHA      string            `json:"sha"`
+	Statuses []*CommitStatus   `json:"statuses"`
+}
@@ -0,0 +1,21 @@
+package gitea
+
+import "context"
+
+// GetAuthenticatedUser returns the user the client is authenticated as.
+func (c *Client) GetAuthenticatedUser(ctx context.Context) (*User, error) {
+	var user User
+	if err := c.get(ctx, "/user", &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUser returns the user with the given username.
+func (c *Client) GetUser(ctx context.Context, username string) (*User, error) {
+	var user User
+	if err := c.get(ctx, "/users/"+username, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -24,6 +24,17 @@ type PipelineEvent struct {
 	MergeRequest *gitlab.MergeRequest `json:"merge_request"`
 }
 
+// PushEvent is sent for pushes to branches (object kind "push") and tags
+// (object kind "tag_push").
+type PushEvent struct {
+	EventCommon
+
+	Before    string `json:"before"`
+	After     string `json:"after"`
+	Ref       string `json:"ref"`
+	ProjectID int    `json:"project_id"`
+}
+
 var ErrObjectKindUnknown = errors.New("unknown object kind")
 
 type downcaster interface {
@@ -31,7 +42,7 @@ type downcaster interface {
 }
 
 // UnmarshalEvent unmarshals the given JSON into an event type. Possible return
-// types are *MergeRequestEvent.
+// types are the merge request events, *PipelineEvent and *PushEvent.
 //
 // Errors caused by a valid payload being of an unknown type may be
 // distinguished from other errors by checking for ErrObjectKindUnknown in the
@@ -59,6 +70,8 @@ func UnmarshalEvent(data []byte) (any, error) {
 		typedEvent = &mergeRequestEvent{}
 	case "pipeline":
 		typedEvent = &PipelineEvent{}
+	case "push", "tag_push":
+		typedEvent = &PushEvent{}
 	default:
 		return nil, errors.Wrapf(ErrObjectKindUnknown, "kind: %s", event.ObjectKind)
 	}
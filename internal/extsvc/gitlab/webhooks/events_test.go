@@ -109,4 +109,31 @@ func TestUnmarshalEvent(t *testing.T) {
 			t.Errorf("unexpected IID: have %d; want %d", pe.Pipeline.ID, want)
 		}
 	})
+
+	t.Run("valid push", func(t *testing.T) {
+		for _, kind := range []string{"push", "tag_push"} {
+			event, err := UnmarshalEvent([]byte(`
+				{
+					"object_kind": "` + kind + `",
+					"ref": "refs/heads/main",
+					"project_id": 42,
+					"project": {
+						"id": 42,
+						"path_with_namespace": "group/project"
+					}
+				}
+			`))
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+
+			pe := event.(*PushEvent)
+			if want := 42; pe.ProjectID != want || pe.Project.ID != want {
+				t.Errorf("unexpected project ID: have %d and %d; want %d", pe.ProjectID, pe.Project.ID, want)
+			}
+			if want := "refs/heads/main"; pe.Ref != want {
+				t.Errorf("unexpected ref: have %s; want %s", pe.Ref, want)
+			}
+		}
+	})
 }
@@ -109,13 +109,17 @@ const (
 	// in preference to the Type values below.
 
 	KindAWSCodeCommit   = "AWSCODECOMMIT"
+	KindAzureDevOps     = "AZUREDEVOPS"
 	KindBitbucketServer = "BITBUCKETSERVER"
 	KindBitbucketCloud  = "BITBUCKETCLOUD"
 	KindGerrit          = "GERRIT"
+	KindGitea           = "GITEA"
 	KindGitHub          = "GITHUB"
 	KindGitLab          = "GITLAB"
 	KindGitolite        = "GITOLITE"
+	KindMercurial       = "MERCURIAL"
 	KindPerforce        = "PERFORCE"
+	KindSubversion      = "SUBVERSION"
 	KindPhabricator     = "PHABRICATOR"
 	KindGoPackages      = "GOMODULES"
 	KindJVMPackages     = "JVMPACKAGES"
@@ -142,9 +146,17 @@ const (
 	// ServiceID value is the base URL to the Bitbucket Cloud.
 	TypeBitbucketCloud = "bitbucketCloud"
 
+	// TypeAzureDevOps is the (api.ExternalRepoSpec).ServiceType value for Azure DevOps repositories.
+	// The ServiceID value is the base URL to Azure DevOps (https://dev.azure.com or the Azure DevOps Server URL).
+	TypeAzureDevOps = "azuredevops"
+
 	// TypeGerrit is the (api.ExternalRepoSpec).ServiceType value for Gerrit projects.
 	TypeGerrit = "gerrit"
 
+	// TypeGitea is the (api.ExternalRepoSpec).ServiceType value for Gitea (and Forgejo) repositories.
+	// The ServiceID value is the base URL to the Gitea instance.
+	TypeGitea = "gitea"
+
 	// TypeGitHub is the (api.ExternalRepoSpec).ServiceType value for GitHub repositories. The ServiceID value
 	// is the base URL to the GitHub instance (https://github.com or the GitHub Enterprise URL).
 	TypeGitHub = "github"
@@ -159,9 +171,17 @@ const (
 	// TypeGitolite is the (api.ExternalRepoSpec).ServiceType value for Gitolite projects.
 	TypeGitolite = "gitolite"
 
+	// TypeMercurial is the (api.ExternalRepoSpec).ServiceType value for Mercurial repositories. The
+	// ServiceID value is the base URL of the Mercurial server.
+	TypeMercurial = "mercurial"
+
 	// TypePerforce is the (api.ExternalRepoSpec).ServiceType value for Perforce projects.
 	TypePerforce = "perforce"
 
+	// TypeSubversion is the (api.ExternalRepoSpec).ServiceType value for Subversion repositories.
+	// The ServiceID value is the base URL of the Subversion server.
+	TypeSubversion = "subversion"
+
 	// TypePhabricator is the (api.ExternalRepoSpec).ServiceType value for Phabricator projects.
 	TypePhabricator = "phabricator"
 
@@ -199,16 +219,24 @@ func KindToType(kind string) string {
 		return TypeBitbucketCloud
 	case KindGerrit:
 		return TypeGerrit
+	case KindAzureDevOps:
+		return TypeAzureDevOps
+	case KindGitea:
+		return TypeGitea
 	case KindGitHub:
 		return TypeGitHub
 	case KindGitLab:
 		return TypeGitLab
 	case KindGitolite:
 		return TypeGitolite
+	case KindMercurial:
+		return TypeMercurial
 	case KindPhabricator:
 		return TypePhabricator
 	case KindPerforce:
 		return TypePerforce
+	case KindSubversion:
+		return TypeSubversion
 	case KindJVMPackages:
 		return TypeJVMPackages
 	case KindPythonPackages:
@@ -240,14 +268,22 @@ func TypeToKind(t string) string {
 		return KindBitbucketCloud
 	case TypeGerrit:
 		return KindGerrit
+	case TypeAzureDevOps:
+		return KindAzureDevOps
+	case TypeGitea:
+		return KindGitea
 	case TypeGitHub:
 		return KindGitHub
 	case TypeGitLab:
 		return KindGitLab
 	case TypeGitolite:
 		return KindGitolite
+	case TypeMercurial:
+		return KindMercurial
 	case TypePerforce:
 		return KindPerforce
+	case TypeSubversion:
+		return KindSubversion
 	case TypePhabricator:
 		return KindPhabricator
 	case TypeNpmPackages:
@@ -292,14 +328,22 @@ func ParseServiceType(s string) (string, bool) {
 		return TypeBitbucketCloud, true
 	case TypeGerrit:
 		return TypeGerrit, true
+	case TypeAzureDevOps:
+		return TypeAzureDevOps, true
+	case TypeGitea:
+		return TypeGitea, true
 	case TypeGitHub:
 		return TypeGitHub, true
 	case TypeGitLab:
 		return TypeGitLab, true
 	case TypeGitolite:
 		return TypeGitolite, true
+	case TypeMercurial:
+		return TypeMercurial, true
 	case TypePerforce:
 		return TypePerforce, true
+	case TypeSubversion:
+		return TypeSubversion, true
 	case TypePhabricator:
 		return TypePhabricator, true
 	case goLower:
@@ -333,14 +377,22 @@ func ParseServiceKind(s string) (string, bool) {
 		return KindBitbucketCloud, true
 	case KindGerrit:
 		return KindGerrit, true
+	case KindAzureDevOps:
+		return KindAzureDevOps, true
+	case KindGitea:
+		return KindGitea, true
 	case KindGitHub:
 		return KindGitHub, true
 	case KindGitLab:
 		return KindGitLab, true
 	case KindGitolite:
 		return KindGitolite, true
+	case KindMercurial:
+		return KindMercurial, true
 	case KindPerforce:
 		return KindPerforce, true
+	case KindSubversion:
+		return KindSubversion, true
 	case KindPhabricator:
 		return KindPhabricator, true
 	case KindGoPackages:
@@ -410,14 +462,22 @@ func getConfigPrototype(kind string) (any, error) {
 		return &schema.BitbucketCloudConnection{}, nil
 	case KindGerrit:
 		return &schema.GerritConnection{}, nil
+	case KindAzureDevOps:
+		return &schema.AzureDevOpsConnection{}, nil
+	case KindGitea:
+		return &schema.GiteaConnection{}, nil
 	case KindGitHub:
 		return &schema.GitHubConnection{}, nil
 	case KindGitLab:
 		return &schema.GitLabConnection{}, nil
 	case KindGitolite:
 		return &schema.GitoliteConnection{}, nil
+	case KindMercurial:
+		return &schema.MercurialConnection{}, nil
 	case KindPerforce:
 		return &schema.PerforceConnection{}, nil
+	case KindSubversion:
+		return &schema.SubversionConnection{}, nil
 	case KindPhabricator:
 		return &schema.PhabricatorConnection{}, nil
 	case KindGoPackages:
@@ -464,6 +524,10 @@ func WebhookURL(kind string, externalServiceID int64, cfg any, externalURL strin
 		default:
 			return "", errors.Newf("external service with id=%d claims to be a Bitbucket Cloud service, but the configuration is of type %T", cfg)
 		}
+	case KindAzureDevOps:
+		// Azure DevOps service hooks send the shared secret as the basic
+		// authentication password, so it isn't part of the URL.
+		path = "azure-devops-webhooks"
 	default:
 		// If not a supported kind, bail out.
 		return "", nil
@@ -503,6 +567,10 @@ func extractToken(parsed any, kind string) (string, error) {
 		return c.Token, nil
 	case *schema.PagureConnection:
 		return c.Token, nil
+	case *schema.GiteaConnection:
+		return c.Token, nil
+	case *schema.AzureDevOpsConnection:
+		return c.Token, nil
 	default:
 		return "", errors.Errorf("unable to extract token for service kind %q", kind)
 	}
@@ -586,6 +654,18 @@ func GetLimitFromConfig(kind string, config any) (rate.Limit, error) {
 		if c != nil && c.RateLimit != nil {
 			limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
 		}
+	case *schema.GiteaConnection:
+		// 8/s is the default limit we enforce, same as in gitea.schema.json
+		limit = rate.Limit(8)
+		if c != nil && c.RateLimit != nil {
+			limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+		}
+	case *schema.AzureDevOpsConnection:
+		// 4/s is the default limit we enforce, same as in azuredevops.schema.json
+		limit = rate.Limit(4)
+		if c != nil && c.RateLimit != nil {
+			limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+		}
 	case *schema.NpmPackagesConnection:
 		limit = rate.Limit(6000 / 3600.0) // Same as the default in npm-packages.schema.json
 		if c != nil && c.RateLimit != nil {
@@ -639,6 +719,8 @@ const (
 	URNGitHubOAuth = "GitHubOAuth"
 	URNGitLabOAuth = "GitLabOAuth"
 	URNCodeIntel   = "CodeIntel"
+
+	URNBitbucketCloudOAuth = "BitbucketCloudOAuth"
 )
 
 // URN returns a unique resource identifier of an external service by given kind and ID.
@@ -666,6 +748,19 @@ type OtherRepoMetadata struct {
 	RelativePath string
 }
 
+type MercurialRepoMetadata struct {
+	// RelativePath is the path of the repository relative to the base URL
+	// of its Mercurial connection. Resolving it against the base URL gives
+	// you the clone url.
+	RelativePath string
+}
+
+type SubversionRepoMetadata struct {
+	// RelativePath is the path of the repository relative to the base URL
+	// of its Subversion connection.
+	RelativePath string
+}
+
 func UniqueEncryptableCodeHostIdentifier(ctx context.Context, kind string, config *EncryptableConfig) (string, error) {
 	cfg, err := ParseEncryptableConfig(ctx, kind, config)
 	if err != nil {
@@ -709,10 +804,18 @@ func uniqueCodeHostIdentifier(kind string, cfg any) (string, error) {
 		rawURL = c.Url
 	case *schema.GerritConnection:
 		rawURL = c.Url
+	case *schema.GiteaConnection:
+		rawURL = c.Url
+	case *schema.AzureDevOpsConnection:
+		rawURL = c.Url
 	case *schema.PhabricatorConnection:
 		rawURL = c.Url
 	case *schema.OtherExternalServiceConnection:
 		rawURL = c.Url
+	case *schema.MercurialConnection:
+		rawURL = c.Url
+	case *schema.SubversionConnection:
+		rawURL = c.Url
 	case *schema.GitoliteConnection:
 		rawURL = c.Host
 	case *schema.AWSCodeCommitConnection:
@@ -353,6 +353,7 @@ func TestWebhookURL(t *testing.T) {
 			KindGitHub:          externalURL + "/.api/github-webhooks?externalServiceID=42",
 			KindBitbucketServer: externalURL + "/.api/bitbucket-server-webhooks?externalServiceID=42",
 			KindGitLab:          externalURL + "/.api/gitlab-webhooks?externalServiceID=42",
+			KindAzureDevOps:     externalURL + "/.api/azure-devops-webhooks?externalServiceID=42",
 		} {
 			t.Run(kind, func(t *testing.T) {
 				// Note the use of a nil configuration here: these kinds do not
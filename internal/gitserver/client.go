@@ -77,10 +77,11 @@ func NewClient(db database.DB) Client {
 		addrs: func() []string {
 			return conf.Get().ServiceConnections().GitServers
 		},
-		pinned:      pinnedReposFromConfig,
-		db:          db,
-		httpClient:  defaultDoer,
-		HTTPLimiter: defaultLimiter,
+		pinned:            pinnedReposFromConfig,
+		replicationFactor: ReplicationFactor,
+		db:                db,
+		httpClient:        defaultDoer,
+		HTTPLimiter:       defaultLimiter,
 		// Use the binary name for userAgent. This should effectively identify
 		// which service is making the request (excluding requests proxied via the
 		// frontend internal API)
@@ -97,9 +98,10 @@ func NewTestClient(cli httpcli.Doer, db database.DB, addrs []string) Client {
 		addrs: func() []string {
 			return addrs
 		},
-		pinned:      pinnedReposFromConfig,
-		httpClient:  cli,
-		HTTPLimiter: parallel.NewRun(500),
+		pinned:            pinnedReposFromConfig,
+		replicationFactor: ReplicationFactor,
+		httpClient:        cli,
+		HTTPLimiter:       parallel.NewRun(500),
 		// Use the binary name for userAgent. This should effectively identify
 		// which service is making the request (excluding requests proxied via the
 		// frontend internal API)
@@ -134,6 +136,10 @@ type clientImplementor struct {
 	// and sync the pinned map.
 	pinned func() map[string]string
 
+	// replicationFactor returns the number of gitserver instances each
+	// repository is stored on. Like pinned, it should read the current conf.
+	replicationFactor func() int
+
 	// db is a connection to the database
 	db database.DB
 
@@ -325,6 +331,23 @@ type Client interface {
 	// many commits will be returned.
 	CommitGraph(ctx context.Context, repo api.RepoName, opts CommitGraphOptions) (_ *gitdomain.CommitGraph, err error)
 
+	// IsAncestor returns whether ancestor is an ancestor of descendant. Every
+	// commit is an ancestor of itself. It is answered by gitserver without
+	// transferring the commit graph.
+	IsAncestor(ctx context.Context, repo api.RepoName, ancestor, descendant api.CommitID) (bool, error)
+
+	// NearestAncestorsIn returns the commits in candidates which are ancestors of
+	// commit (or commit itself), and are not ancestors of another such commit,
+	// along with their distance from commit. Candidates which don't exist are
+	// ignored.
+	NearestAncestorsIn(ctx context.Context, repo api.RepoName, commit api.CommitID, candidates []api.CommitID) ([]gitdomain.Ancestor, error)
+
+	// CommitsBetween returns the commits reachable from head but not from base,
+	// newest first. If base is empty, all commits reachable from head are
+	// returned. If a non-zero limit is supplied, at most that many commits will
+	// be returned.
+	CommitsBetween(ctx context.Context, repo api.RepoName, base, head api.CommitID, limit int) ([]api.CommitID, error)
+
 	// CommitsUniqueToBranch returns a map from commits that exist on a particular
 	// branch in the given repository to their committer date. This set of commits is
 	// determined by listing `{branchName} ^HEAD`, which is interpreted as: all
@@ -477,6 +500,10 @@ func (a *archiveReader) Read(p []byte) (int, error) {
 		if strings.Contains(err.Error(), "Not a valid object") {
 			return 0, &gitdomain.RevisionNotFoundError{Repo: a.repo, Spec: a.spec}
 		}
+		// or because an object missing from a partial clone couldn't be fetched
+		if strings.Contains(err.Error(), gitdomain.MissingObjectErrorMessage) {
+			return 0, &gitdomain.MissingObjectError{Repo: a.repo, Spec: a.spec}
+		}
 	}
 	return n, err
 }
@@ -588,13 +615,7 @@ func (c *clientImplementor) Search(ctx context.Context, args *protocol.SearchReq
 		return false, err
 	}
 
-	addrForRepo, err := c.AddrForRepo(ctx, repoName)
-	if err != nil {
-		return false, err
-	}
-
-	uri := "http://" + addrForRepo + "/search"
-	resp, err := c.do(ctx, repoName, "POST", uri, buf.Bytes())
+	resp, err := c.doWithFailover(ctx, repoName, "POST", "/search", buf.Bytes())
 	if err != nil {
 		return false, err
 	}
@@ -834,7 +855,7 @@ func (c *clientImplementor) gitCommand(repo api.RepoName, arg ...string) GitComm
 	}
 	return &RemoteGitCommand{
 		repo:   repo,
-		execFn: c.httpPost,
+		execFn: c.httpPostWithFailover,
 		args:   append([]string{git}, arg...),
 	}
 }
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -31,6 +32,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
@@ -404,6 +406,189 @@ func TestRendezvousAddrForRepo(t *testing.T) {
 	}
 }
 
+func TestReplicaAddrsForRepo(t *testing.T) {
+	addrs := []string{"gitserver-1", "gitserver-2", "gitserver-3"}
+
+	testCases := []struct {
+		name    string
+		repo    api.RepoName
+		primary string
+		factor  int
+		want    []string
+	}{
+		{
+			name:    "no replication",
+			repo:    api.RepoName("repo1"),
+			primary: "gitserver-1",
+			factor:  1,
+			want:    nil,
+		},
+		{
+			name:    "one replica",
+			repo:    api.RepoName("repo1"),
+			primary: "gitserver-1",
+			factor:  2,
+			want:    []string{"gitserver-3"},
+		},
+		{
+			name:    "check we normalise",
+			repo:    api.RepoName("repo1.git"),
+			primary: "gitserver-1",
+			factor:  2,
+			want:    []string{"gitserver-3"},
+		},
+		{
+			name:    "replicas in rendezvous order",
+			repo:    api.RepoName("gitlab.com/foo/bar"),
+			primary: "gitserver-2",
+			factor:  3,
+			want:    []string{"gitserver-1", "gitserver-3"},
+		},
+		{
+			name:    "pinned primary is skipped",
+			repo:    api.RepoName("github.com/sourcegraph/sourcegraph"),
+			primary: "gitserver-1",
+			factor:  2,
+			want:    []string{"gitserver-3"},
+		},
+		{
+			name:    "factor larger than number of instances",
+			repo:    api.RepoName("repo1"),
+			primary: "gitserver-1",
+			factor:  5,
+			want:    []string{"gitserver-3", "gitserver-2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := gitserver.ReplicaAddrsForRepo(tc.repo, tc.primary, addrs, tc.factor)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected replicas (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	// The first replica becomes the primary once the primary is removed.
+	repo := api.RepoName("github.com/sourcegraph/sourcegraph")
+	primary := gitserver.RendezvousAddrForRepo(repo, addrs)
+	replicas := gitserver.ReplicaAddrsForRepo(repo, primary, addrs, 2)
+	var remaining []string
+	for _, addr := range addrs {
+		if addr != primary {
+			remaining = append(remaining, addr)
+		}
+	}
+	if got := gitserver.RendezvousAddrForRepo(repo, remaining); got != replicas[0] {
+		t.Fatalf("want new primary %q, got %q", replicas[0], got)
+	}
+}
+
+func TestClient_ReadFailover(t *testing.T) {
+	repo := api.RepoName("github.com/sourcegraph/sourcegraph")
+	addrs := []string{"172.16.8.1:8080", "172.16.8.2:8080", "172.16.8.3:8080"}
+	primary := gitserver.RendezvousAddrForRepo(repo, addrs)
+	replicas := gitserver.ReplicaAddrsForRepo(repo, primary, addrs, 3)
+
+	setReplicationFactor := func(factor int) {
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+			ExperimentalFeatures: &schema.ExperimentalFeatures{
+				GitServerReplicationFactor: factor,
+			},
+		}})
+	}
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	var requested []string
+	cli := gitserver.NewTestClient(
+		httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			requested = append(requested, r.URL.Host)
+			// The primary and the first replica are down.
+			if r.URL.Host == primary || r.URL.Host == replicas[0] {
+				return nil, &url.Error{Op: "Post", URL: r.URL.String(), Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+			}
+			if r.URL.Path != "/archive" {
+				t.Fatalf("unexpected path %q", r.URL.Path)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString("archive")),
+				Trailer:    http.Header{"X-Exec-Exit-Status": {"0"}},
+			}, nil
+		}),
+		newMockDB(),
+		addrs,
+	)
+	migration.MigrationMocks.GetCursor = func(ctx context.Context, db dbutil.DB) (string, error) {
+		return "zzz", nil // Use rendezvous hashing for all repos.
+	}
+	t.Cleanup(migration.ResetMigrationMocks)
+
+	archive := func() (string, error) {
+		rc, err := cli.ArchiveReader(context.Background(), nil, repo, gitserver.ArchiveOptions{Treeish: "HEAD", Format: gitserver.ArchiveFormatZip})
+		if err != nil {
+			return "", err
+		}
+		defer rc.Close()
+		b, err := io.ReadAll(rc)
+		return string(b), err
+	}
+
+	setReplicationFactor(3)
+	got, err := archive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "archive" {
+		t.Fatalf("unexpected archive %q", got)
+	}
+	if diff := cmp.Diff([]string{primary, replicas[0], replicas[1]}, requested); diff != "" {
+		t.Fatalf("unexpected requests (-want +got):\n%s", diff)
+	}
+
+	// Without replicas, the error of the primary is returned.
+	requested = nil
+	setReplicationFactor(1)
+	if _, err := archive(); err == nil {
+		t.Fatal("expected error")
+	}
+	if diff := cmp.Diff([]string{primary}, requested); diff != "" {
+		t.Fatalf("unexpected requests (-want +got):\n%s", diff)
+	}
+}
+
+func TestClient_ArchiveReader_MissingObject(t *testing.T) {
+	repo := api.RepoName("github.com/sourcegraph/sourcegraph")
+	cli := gitserver.NewTestClient(
+		httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString("")),
+				Trailer: http.Header{
+					"X-Exec-Error":       {gitdomain.MissingObjectErrorMessage + ": exit status 128"},
+					"X-Exec-Exit-Status": {"128"},
+					"X-Exec-Stderr":      {"fatal: could not fetch 7852c2816d025586d199bc137635b88cc92e2e21 from promisor remote"},
+				},
+			}, nil
+		}),
+		newMockDB(),
+		[]string{"172.16.8.1:8080"},
+	)
+
+	rc, err := cli.ArchiveReader(context.Background(), nil, repo, gitserver.ArchiveOptions{Treeish: "HEAD", Format: gitserver.ArchiveFormatZip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	_, err = io.ReadAll(rc)
+	if !errors.HasType(err, &gitdomain.MissingObjectError{}) {
+		t.Fatalf("expected a MissingObjectError, got %v", err)
+	}
+	if !errcode.IsTemporary(err) {
+		t.Error("expected missing objects to be a temporary error")
+	}
+}
+
 func TestClient_P4Exec(t *testing.T) {
 	_ = gitserver.CreateRepoDir(t)
 	tests := []struct {
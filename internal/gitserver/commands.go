@@ -325,6 +325,90 @@ func (c *clientImplementor) CommitGraph(ctx context.Context, repo api.RepoName,
 	return gitdomain.ParseCommitGraph(strings.Split(string(out), "\n")), nil
 }
 
+// IsAncestor returns whether ancestor is an ancestor of descendant. Every
+// commit is an ancestor of itself.
+func (c *clientImplementor) IsAncestor(ctx context.Context, repo api.RepoName, ancestor, descendant api.CommitID) (bool, error) {
+	span, ctx := ot.StartSpanFromContext(ctx, "Git: IsAncestor")
+	span.SetTag("Ancestor", ancestor)
+	span.SetTag("Descendant", descendant)
+	defer span.Finish()
+
+	var res protocol.IsAncestorResponse
+	err := c.ancestryRequest(ctx, repo, "commands/is-ancestor", protocol.IsAncestorRequest{
+		Repo:       repo,
+		Ancestor:   ancestor,
+		Descendant: descendant,
+	}, &res)
+	return res.IsAncestor, err
+}
+
+// NearestAncestorsIn returns the commits in candidates which are ancestors of
+// commit (or commit itself), and are not ancestors of another such commit,
+// along with their distance from commit. Candidates which don't exist are
+// ignored.
+func (c *clientImplementor) NearestAncestorsIn(ctx context.Context, repo api.RepoName, commit api.CommitID, candidates []api.CommitID) ([]gitdomain.Ancestor, error) {
+	span, ctx := ot.StartSpanFromContext(ctx, "Git: NearestAncestorsIn")
+	span.SetTag("Commit", commit)
+	span.SetTag("Candidates", len(candidates))
+	defer span.Finish()
+
+	var res protocol.NearestAncestorsInResponse
+	err := c.ancestryRequest(ctx, repo, "commands/nearest-ancestors-in", protocol.NearestAncestorsInRequest{
+		Repo:       repo,
+		Commit:     commit,
+		Candidates: candidates,
+	}, &res)
+	return res.Ancestors, err
+}
+
+// CommitsBetween returns the commits reachable from head but not from base,
+// newest first. If base is empty, all commits reachable from head are returned.
+// If a non-zero limit is supplied, at most that many commits will be returned.
+func (c *clientImplementor) CommitsBetween(ctx context.Context, repo api.RepoName, base, head api.CommitID, limit int) ([]api.CommitID, error) {
+	span, ctx := ot.StartSpanFromContext(ctx, "Git: CommitsBetween")
+	span.SetTag("Base", base)
+	span.SetTag("Head", head)
+	defer span.Finish()
+
+	var res protocol.CommitsBetweenResponse
+	err := c.ancestryRequest(ctx, repo, "commands/commits-between", protocol.CommitsBetweenRequest{
+		Repo:  repo,
+		Base:  base,
+		Head:  head,
+		Limit: limit,
+	}, &res)
+	return res.Commits, err
+}
+
+// ancestryRequest sends req to the gitserver endpoint op answering questions
+// about the commit graph of repo, and decodes the response into res.
+func (c *clientImplementor) ancestryRequest(ctx context.Context, repo api.RepoName, op string, req, res interface{}) error {
+	resp, err := c.httpPostWithFailover(ctx, repo, op, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		var payload protocol.NotFoundPayload
+		if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+			return err
+		}
+		return &gitdomain.RepoNotExistError{Repo: repo, CloneInProgress: payload.CloneInProgress, CloneProgress: payload.CloneProgress}
+	case http.StatusUnprocessableEntity:
+		var payload protocol.RevisionNotFoundPayload
+		if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+			return err
+		}
+		return &gitdomain.RevisionNotFoundError{Repo: repo, Spec: payload.Spec}
+	default:
+		return errors.Errorf("%s failed: http status %d: %s", op, resp.StatusCode, readResponseBody(io.LimitReader(resp.Body, 1024)))
+	}
+	return json.NewDecoder(resp.Body).Decode(res)
+}
+
 // DevNullSHA 4b825dc642cb6eb9a060e54bf8d69288fbee4904 is `git hash-object -t
 // tree /dev/null`, which is used as the base when computing the `git diff` of
 // the root commit.
@@ -699,7 +783,79 @@ func (c *clientImplementor) BlameFile(ctx context.Context, checker authz.SubRepo
 	span.SetTag("path", path)
 	span.SetTag("opt", opt)
 	defer span.Finish()
-	return blameFileCmd(ctx, c.gitserverGitCommandFunc(repo), path, opt, repo, checker)
+	if ClientMocks.LocalGitserver {
+		return blameFileCmd(ctx, c.gitserverGitCommandFunc(repo), path, opt, repo, checker)
+	}
+	return c.blameFile(ctx, checker, repo, path, opt)
+}
+
+// blameFile asks gitserver to blame the file, which unlike running git blame
+// via exec uses the blame cache of gitserver.
+func (c *clientImplementor) blameFile(ctx context.Context, checker authz.SubRepoPermissionChecker, repo api.RepoName, path string, opt *BlameOptions) ([]*Hunk, error) {
+	a := actor.FromContext(ctx)
+	if hasAccess, err := authz.FilterActorPath(ctx, checker, a, repo, path); err != nil || !hasAccess {
+		return nil, err
+	}
+	if opt == nil {
+		opt = &BlameOptions{}
+	}
+	if err := checkSpecArgSafety(string(opt.NewestCommit)); err != nil {
+		return nil, err
+	}
+
+	req := protocol.BlameRequest{
+		Repo:      repo,
+		Commit:    string(opt.NewestCommit),
+		Path:      filepath.ToSlash(path),
+		StartLine: opt.StartLine,
+		EndLine:   opt.EndLine,
+	}
+	resp, err := c.httpPostWithFailover(ctx, repo, "blame", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		// Either the repository or the commit does not exist.
+		var payload struct {
+			protocol.NotFoundPayload
+			protocol.RevisionNotFoundPayload
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+			return nil, err
+		}
+		if payload.Spec != "" {
+			return nil, &gitdomain.RevisionNotFoundError{Repo: repo, Spec: payload.Spec}
+		}
+		return nil, &gitdomain.RepoNotExistError{Repo: repo, CloneInProgress: payload.CloneInProgress, CloneProgress: payload.CloneProgress}
+	default:
+		return nil, errors.Errorf("git blame of %q failed: http status %d: %s", path, resp.StatusCode, readResponseBody(io.LimitReader(resp.Body, 1024)))
+	}
+
+	var res protocol.BlameResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	if len(res.Hunks) == 0 {
+		return nil, nil
+	}
+	hunks := make([]*Hunk, 0, len(res.Hunks))
+	for _, h := range res.Hunks {
+		hunks = append(hunks, &Hunk{
+			StartLine: h.StartLine,
+			EndLine:   h.EndLine,
+			StartByte: h.StartByte,
+			EndByte:   h.EndByte,
+			CommitID:  h.CommitID,
+			Author:    h.Author,
+			Message:   h.Message,
+			Filename:  h.Filename,
+		})
+	}
+	return hunks, nil
 }
 
 func blameFileCmd(ctx context.Context, command gitCommandFunc, path string, opt *BlameOptions, repo api.RepoName, checker authz.SubRepoPermissionChecker) ([]*Hunk, error) {
@@ -1365,6 +1521,9 @@ func (br *blobReader) convertError(err error) error {
 	if strings.Contains(err.Error(), "exists on disk, but not in") || strings.Contains(err.Error(), "does not exist") {
 		return &os.PathError{Op: "open", Path: br.name, Err: os.ErrNotExist}
 	}
+	if strings.Contains(err.Error(), gitdomain.MissingObjectErrorMessage) {
+		return &gitdomain.MissingObjectError{Repo: br.repo, Spec: string(br.commit) + ":" + br.name}
+	}
 	if strings.Contains(err.Error(), "fatal: bad object ") {
 		// Could be a git submodule.
 		fi, err := br.c.Stat(br.ctx, authz.DefaultSubRepoPermsChecker, br.repo, br.commit, br.name)
@@ -2392,7 +2551,7 @@ func (c *clientImplementor) ArchiveReader(
 		return nil, err
 	}
 
-	resp, err := c.do(ctx, repo, "POST", u.String(), nil)
+	resp, err := c.doWithFailover(ctx, repo, "POST", u.RequestURI(), nil)
 	if err != nil {
 		return nil, err
 	}
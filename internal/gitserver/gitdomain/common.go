@@ -177,6 +177,14 @@ type Ref struct {
 	CommitID api.CommitID
 }
 
+// Ancestor is an ancestor of a commit.
+type Ancestor struct {
+	Commit api.CommitID
+	// Distance is the number of parent links on the shortest path from the
+	// commit to the ancestor. It is 0 for the commit itself.
+	Distance int
+}
+
 // BehindAhead is a set of behind/ahead counts.
 type BehindAhead struct {
 	Behind uint32 `json:"Behind,omitempty"`
@@ -25,6 +25,27 @@ func (e *RevisionNotFoundError) NotFound() bool {
 	return true
 }
 
+// MissingObjectErrorMessage is reported by gitserver when an object missing
+// from a partial clone can't be fetched from the code host.
+const MissingObjectErrorMessage = "object missing from partial clone could not be fetched from the code host"
+
+// MissingObjectError is an error that reports an object needed to read a
+// revision of a partially cloned repository could not be fetched from the
+// code host.
+type MissingObjectError struct {
+	Repo api.RepoName
+	Spec string
+}
+
+func (e *MissingObjectError) Error() string {
+	return fmt.Sprintf("%s: %s@%s", MissingObjectErrorMessage, e.Repo, e.Spec)
+}
+
+// Temporary is true since the code host may only be unreachable for a while.
+func (e *MissingObjectError) Temporary() bool {
+	return true
+}
+
 type BadCommitError struct {
 	Spec   string
 	Commit api.CommitID
@@ -0,0 +1,64 @@
+package inttests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestAncestry(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	repo := MakeGitRepository(t,
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m foo --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m bar --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m baz --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+	)
+	const (
+		foo api.CommitID = "ea167fe3d76b1e5fd3ed8ca44cbd2fe3897684f8"
+		bar api.CommitID = "ce89acd69db9a7ebbeb6c6db31d01e0c15969b9f"
+		baz api.CommitID = "666f933bd727e4c792cbe08a2ac30094b5031b34"
+	)
+
+	client := gitserver.NewTestClient(http.DefaultClient, database.NewMockDB(), gitserverAddresses)
+
+	between, err := client.CommitsBetween(ctx, repo, "", "HEAD", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]api.CommitID{baz, bar, foo}, between); diff != "" {
+		t.Errorf("unexpected commits (-want +got):\n%s", diff)
+	}
+	if between, err := client.CommitsBetween(ctx, repo, foo, baz, 1); err != nil || !cmp.Equal([]api.CommitID{baz}, between) {
+		t.Errorf("unexpected commits between %s and %s: %v, %v", foo, baz, between, err)
+	}
+
+	if ok, err := client.IsAncestor(ctx, repo, foo, baz); err != nil || !ok {
+		t.Errorf("expected %s to be an ancestor of %s: %v", foo, baz, err)
+	}
+	if ok, err := client.IsAncestor(ctx, repo, baz, foo); err != nil || ok {
+		t.Errorf("expected %s not to be an ancestor of %s: %v", baz, foo, err)
+	}
+
+	nearest, err := client.NearestAncestorsIn(ctx, repo, bar, []api.CommitID{foo, baz})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]gitdomain.Ancestor{{Commit: foo, Distance: 1}}, nearest); diff != "" {
+		t.Errorf("unexpected nearest ancestors (-want +got):\n%s", diff)
+	}
+
+	var notFound *gitdomain.RevisionNotFoundError
+	if _, err := client.IsAncestor(ctx, repo, foo, "missing"); !errors.As(err, &notFound) {
+		t.Errorf("expected a revision not found error, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package inttests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+)
+
+func TestBlameFile(t *testing.T) {
+	t.Parallel()
+
+	repo := MakeGitRepository(t,
+		"echo line1 > f",
+		"git add f",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m foo --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"echo line2 >> f",
+		"git add f",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m foo --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"git mv f f2",
+		"echo line3 >> f2",
+		"git add f2",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m foo --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+	)
+	author := gitdomain.Signature{Name: "a", Email: "a@a.com", Date: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)}
+	allHunks := []*gitserver.Hunk{
+		{StartLine: 1, EndLine: 2, StartByte: 0, EndByte: 6, CommitID: "e6093374dcf5725d8517db0dccbbf69df65dbde0", Message: "foo", Author: author, Filename: "f"},
+		{StartLine: 2, EndLine: 3, StartByte: 6, EndByte: 12, CommitID: "fad406f4fe02c358a09df0d03ec7a36c2c8a20f1", Message: "foo", Author: author, Filename: "f"},
+		{StartLine: 3, EndLine: 4, StartByte: 12, EndByte: 18, CommitID: "311d75a2b414a77f5158a0ed73ec476f5469b286", Message: "foo", Author: author, Filename: "f2"},
+	}
+
+	// The tests run in order, since the later ones are served from the cache.
+	tests := []struct {
+		name      string
+		opt       *gitserver.BlameOptions
+		wantHunks []*gitserver.Hunk
+	}{
+		{
+			name:      "all lines",
+			opt:       &gitserver.BlameOptions{NewestCommit: "master"},
+			wantHunks: allHunks,
+		},
+		{
+			name:      "cached",
+			opt:       &gitserver.BlameOptions{NewestCommit: "311d75a2b414a77f5158a0ed73ec476f5469b286"},
+			wantHunks: allHunks,
+		},
+		{
+			name: "line range",
+			opt:  &gitserver.BlameOptions{NewestCommit: "master", StartLine: 2, EndLine: 3},
+			wantHunks: []*gitserver.Hunk{
+				{StartLine: 2, EndLine: 3, StartByte: 0, EndByte: 6, CommitID: "fad406f4fe02c358a09df0d03ec7a36c2c8a20f1", Message: "foo", Author: author, Filename: "f"},
+				{StartLine: 3, EndLine: 4, StartByte: 6, EndByte: 12, CommitID: "311d75a2b414a77f5158a0ed73ec476f5469b286", Message: "foo", Author: author, Filename: "f2"},
+			},
+		},
+	}
+
+	client := gitserver.NewTestClient(http.DefaultClient, database.NewMockDB(), gitserverAddresses)
+	for _, test := range tests {
+		hunks, err := client.BlameFile(context.Background(), nil, repo, "f2", test.opt)
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		if diff := cmp.Diff(test.wantHunks, hunks); diff != "" {
+			t.Errorf("%s: unexpected hunks (-want +got):\n%s", test.name, diff)
+		}
+	}
+
+	if _, err := client.BlameFile(context.Background(), nil, repo, "f2", &gitserver.BlameOptions{NewestCommit: "missing"}); err == nil {
+		t.Error("expected an error for a missing revision")
+	}
+}
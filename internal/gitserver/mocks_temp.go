@@ -56,6 +56,9 @@ type MockClient struct {
 	// CommitsFunc is an instance of a mock function object controlling the
 	// behavior of the method Commits.
 	CommitsFunc *ClientCommitsFunc
+	// CommitsBetweenFunc is an instance of a mock function object
+	// controlling the behavior of the method CommitsBetween.
+	CommitsBetweenFunc *ClientCommitsBetweenFunc
 	// CommitsExistFunc is an instance of a mock function object controlling
 	// the behavior of the method CommitsExist.
 	CommitsExistFunc *ClientCommitsExistFunc
@@ -101,6 +104,9 @@ type MockClient struct {
 	// HeadFunc is an instance of a mock function object controlling the
 	// behavior of the method Head.
 	HeadFunc *ClientHeadFunc
+	// IsAncestorFunc is an instance of a mock function object controlling
+	// the behavior of the method IsAncestor.
+	IsAncestorFunc *ClientIsAncestorFunc
 	// IsRepoCloneableFunc is an instance of a mock function object
 	// controlling the behavior of the method IsRepoCloneable.
 	IsRepoCloneableFunc *ClientIsRepoCloneableFunc
@@ -128,6 +134,9 @@ type MockClient struct {
 	// MergeBaseFunc is an instance of a mock function object controlling
 	// the behavior of the method MergeBase.
 	MergeBaseFunc *ClientMergeBaseFunc
+	// NearestAncestorsInFunc is an instance of a mock function object
+	// controlling the behavior of the method NearestAncestorsIn.
+	NearestAncestorsInFunc *ClientNearestAncestorsInFunc
 	// NewFileReaderFunc is an instance of a mock function object
 	// controlling the behavior of the method NewFileReader.
 	NewFileReaderFunc *ClientNewFileReaderFunc
@@ -235,6 +244,11 @@ func NewMockClient() *MockClient {
 				return
 			},
 		},
+		CommitsBetweenFunc: &ClientCommitsBetweenFunc{
+			defaultHook: func(context.Context, api.RepoName, api.CommitID, api.CommitID, int) (r0 []api.CommitID, r1 error) {
+				return
+			},
+		},
 		CommitsExistFunc: &ClientCommitsExistFunc{
 			defaultHook: func(context.Context, []api.RepoCommit, authz.SubRepoPermissionChecker) (r0 []bool, r1 error) {
 				return
@@ -310,6 +324,11 @@ func NewMockClient() *MockClient {
 				return
 			},
 		},
+		IsAncestorFunc: &ClientIsAncestorFunc{
+			defaultHook: func(context.Context, api.RepoName, api.CommitID, api.CommitID) (r0 bool, r1 error) {
+				return
+			},
+		},
 		IsRepoCloneableFunc: &ClientIsRepoCloneableFunc{
 			defaultHook: func(context.Context, api.RepoName) (r0 error) {
 				return
@@ -355,6 +374,11 @@ func NewMockClient() *MockClient {
 				return
 			},
 		},
+		NearestAncestorsInFunc: &ClientNearestAncestorsInFunc{
+			defaultHook: func(context.Context, api.RepoName, api.CommitID, []api.CommitID) (r0 []gitdomain.Ancestor, r1 error) {
+				return
+			},
+		},
 		NewFileReaderFunc: &ClientNewFileReaderFunc{
 			defaultHook: func(context.Context, api.RepoName, api.CommitID, string, authz.SubRepoPermissionChecker) (r0 io.ReadCloser, r1 error) {
 				return
@@ -497,6 +521,11 @@ func NewStrictMockClient() *MockClient {
 				panic("unexpected invocation of MockClient.Commits")
 			},
 		},
+		CommitsBetweenFunc: &ClientCommitsBetweenFunc{
+			defaultHook: func(context.Context, api.RepoName, api.CommitID, api.CommitID, int) ([]api.CommitID, error) {
+				panic("unexpected invocation of MockClient.CommitsBetween")
+			},
+		},
 		CommitsExistFunc: &ClientCommitsExistFunc{
 			defaultHook: func(context.Context, []api.RepoCommit, authz.SubRepoPermissionChecker) ([]bool, error) {
 				panic("unexpected invocation of MockClient.CommitsExist")
@@ -572,6 +601,11 @@ func NewStrictMockClient() *MockClient {
 				panic("unexpected invocation of MockClient.Head")
 			},
 		},
+		IsAncestorFunc: &ClientIsAncestorFunc{
+			defaultHook: func(context.Context, api.RepoName, api.CommitID, api.CommitID) (bool, error) {
+				panic("unexpected invocation of MockClient.IsAncestor")
+			},
+		},
 		IsRepoCloneableFunc: &ClientIsRepoCloneableFunc{
 			defaultHook: func(context.Context, api.RepoName) error {
 				panic("unexpected invocation of MockClient.IsRepoCloneable")
@@ -617,6 +651,11 @@ func NewStrictMockClient() *MockClient {
 				panic("unexpected invocation of MockClient.MergeBase")
 			},
 		},
+		NearestAncestorsInFunc: &ClientNearestAncestorsInFunc{
+			defaultHook: func(context.Context, api.RepoName, api.CommitID, []api.CommitID) ([]gitdomain.Ancestor, error) {
+				panic("unexpected invocation of MockClient.NearestAncestorsIn")
+			},
+		},
 		NewFileReaderFunc: &ClientNewFileReaderFunc{
 			defaultHook: func(context.Context, api.RepoName, api.CommitID, string, authz.SubRepoPermissionChecker) (io.ReadCloser, error) {
 				panic("unexpected invocation of MockClient.NewFileReader")
@@ -739,6 +778,9 @@ func NewMockClientFrom(i Client) *MockClient {
 		CommitsFunc: &ClientCommitsFunc{
 			defaultHook: i.Commits,
 		},
+		CommitsBetweenFunc: &ClientCommitsBetweenFunc{
+			defaultHook: i.CommitsBetween,
+		},
 		CommitsExistFunc: &ClientCommitsExistFunc{
 			defaultHook: i.CommitsExist,
 		},
@@ -784,6 +826,9 @@ func NewMockClientFrom(i Client) *MockClient {
 		HeadFunc: &ClientHeadFunc{
 			defaultHook: i.Head,
 		},
+		IsAncestorFunc: &ClientIsAncestorFunc{
+			defaultHook: i.IsAncestor,
+		},
 		IsRepoCloneableFunc: &ClientIsRepoCloneableFunc{
 			defaultHook: i.IsRepoCloneable,
 		},
@@ -811,6 +856,9 @@ func NewMockClientFrom(i Client) *MockClient {
 		MergeBaseFunc: &ClientMergeBaseFunc{
 			defaultHook: i.MergeBase,
 		},
+		NearestAncestorsInFunc: &ClientNearestAncestorsInFunc{
+			defaultHook: i.NearestAncestorsIn,
+		},
 		NewFileReaderFunc: &ClientNewFileReaderFunc{
 			defaultHook: i.NewFileReader,
 		},
@@ -1975,6 +2023,123 @@ func (c ClientCommitsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// ClientCommitsBetweenFunc describes the behavior when the CommitsBetween
+// method of the parent MockClient instance is invoked.
+type ClientCommitsBetweenFunc struct {
+	defaultHook func(context.Context, api.RepoName, api.CommitID, api.CommitID, int) ([]api.CommitID, error)
+	hooks       []func(context.Context, api.RepoName, api.CommitID, api.CommitID, int) ([]api.CommitID, error)
+	history     []ClientCommitsBetweenFuncCall
+	mutex       sync.Mutex
+}
+
+// CommitsBetween delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockClient) CommitsBetween(v0 context.Context, v1 api.RepoName, v2 api.CommitID, v3 api.CommitID, v4 int) ([]api.CommitID, error) {
+	r0, r1 := m.CommitsBetweenFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.CommitsBetweenFunc.appendCall(ClientCommitsBetweenFuncCall{v0, v1, v2, v3, v4, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the CommitsBetween
+// method of the parent MockClient instance is invoked and the hook queue is
+// empty.
+func (f *ClientCommitsBetweenFunc) SetDefaultHook(hook func(context.Context, api.RepoName, api.CommitID, api.CommitID, int) ([]api.CommitID, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CommitsBetween method of the parent MockClient instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *ClientCommitsBetweenFunc) PushHook(hook func(context.Context, api.RepoName, api.CommitID, api.CommitID, int) ([]api.CommitID, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ClientCommitsBetweenFunc) SetDefaultReturn(r0 []api.CommitID, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, api.CommitID, api.CommitID, int) ([]api.CommitID, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ClientCommitsBetweenFunc) PushReturn(r0 []api.CommitID, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, api.CommitID, api.CommitID, int) ([]api.CommitID, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientCommitsBetweenFunc) nextHook() func(context.Context, api.RepoName, api.CommitID, api.CommitID, int) ([]api.CommitID, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientCommitsBetweenFunc) appendCall(r0 ClientCommitsBetweenFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientCommitsBetweenFuncCall objects
+// describing the invocations of this function.
+func (f *ClientCommitsBetweenFunc) History() []ClientCommitsBetweenFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientCommitsBetweenFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientCommitsBetweenFuncCall is an object that describes an invocation of
+// method CommitsBetween on an instance of MockClient.
+type ClientCommitsBetweenFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 api.CommitID
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 api.CommitID
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []api.CommitID
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientCommitsBetweenFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientCommitsBetweenFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ClientCommitsExistFunc describes the behavior when the CommitsExist
 // method of the parent MockClient instance is invoked.
 type ClientCommitsExistFunc struct {
@@ -3676,6 +3841,119 @@ func (c ClientHeadFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// ClientIsAncestorFunc describes the behavior when the IsAncestor method of
+// the parent MockClient instance is invoked.
+type ClientIsAncestorFunc struct {
+	defaultHook func(context.Context, api.RepoName, api.CommitID, api.CommitID) (bool, error)
+	hooks       []func(context.Context, api.RepoName, api.CommitID, api.CommitID) (bool, error)
+	history     []ClientIsAncestorFuncCall
+	mutex       sync.Mutex
+}
+
+// IsAncestor delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockClient) IsAncestor(v0 context.Context, v1 api.RepoName, v2 api.CommitID, v3 api.CommitID) (bool, error) {
+	r0, r1 := m.IsAncestorFunc.nextHook()(v0, v1, v2, v3)
+	m.IsAncestorFunc.appendCall(ClientIsAncestorFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the IsAncestor method of
+// the parent MockClient instance is invoked and the hook queue is empty.
+func (f *ClientIsAncestorFunc) SetDefaultHook(hook func(context.Context, api.RepoName, api.CommitID, api.CommitID) (bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// IsAncestor method of the parent MockClient instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *ClientIsAncestorFunc) PushHook(hook func(context.Context, api.RepoName, api.CommitID, api.CommitID) (bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ClientIsAncestorFunc) SetDefaultReturn(r0 bool, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, api.CommitID, api.CommitID) (bool, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ClientIsAncestorFunc) PushReturn(r0 bool, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, api.CommitID, api.CommitID) (bool, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientIsAncestorFunc) nextHook() func(context.Context, api.RepoName, api.CommitID, api.CommitID) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientIsAncestorFunc) appendCall(r0 ClientIsAncestorFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientIsAncestorFuncCall objects describing
+// the invocations of this function.
+func (f *ClientIsAncestorFunc) History() []ClientIsAncestorFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientIsAncestorFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientIsAncestorFuncCall is an object that describes an invocation of
+// method IsAncestor on an instance of MockClient.
+type ClientIsAncestorFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 api.CommitID
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 api.CommitID
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 bool
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientIsAncestorFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientIsAncestorFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ClientIsRepoCloneableFunc describes the behavior when the IsRepoCloneable
 // method of the parent MockClient instance is invoked.
 type ClientIsRepoCloneableFunc struct {
@@ -4699,6 +4977,120 @@ func (c ClientMergeBaseFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// ClientNearestAncestorsInFunc describes the behavior when the
+// NearestAncestorsIn method of the parent MockClient instance is invoked.
+type ClientNearestAncestorsInFunc struct {
+	defaultHook func(context.Context, api.RepoName, api.CommitID, []api.CommitID) ([]gitdomain.Ancestor, error)
+	hooks       []func(context.Context, api.RepoName, api.CommitID, []api.CommitID) ([]gitdomain.Ancestor, error)
+	history     []ClientNearestAncestorsInFuncCall
+	mutex       sync.Mutex
+}
+
+// NearestAncestorsIn delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockClient) NearestAncestorsIn(v0 context.Context, v1 api.RepoName, v2 api.CommitID, v3 []api.CommitID) ([]gitdomain.Ancestor, error) {
+	r0, r1 := m.NearestAncestorsInFunc.nextHook()(v0, v1, v2, v3)
+	m.NearestAncestorsInFunc.appendCall(ClientNearestAncestorsInFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the NearestAncestorsIn
+// method of the parent MockClient instance is invoked and the hook queue is
+// empty.
+func (f *ClientNearestAncestorsInFunc) SetDefaultHook(hook func(context.Context, api.RepoName, api.CommitID, []api.CommitID) ([]gitdomain.Ancestor, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// NearestAncestorsIn method of the parent MockClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *ClientNearestAncestorsInFunc) PushHook(hook func(context.Context, api.RepoName, api.CommitID, []api.CommitID) ([]gitdomain.Ancestor, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ClientNearestAncestorsInFunc) SetDefaultReturn(r0 []gitdomain.Ancestor, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, api.CommitID, []api.CommitID) ([]gitdomain.Ancestor, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ClientNearestAncestorsInFunc) PushReturn(r0 []gitdomain.Ancestor, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, api.CommitID, []api.CommitID) ([]gitdomain.Ancestor, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientNearestAncestorsInFunc) nextHook() func(context.Context, api.RepoName, api.CommitID, []api.CommitID) ([]gitdomain.Ancestor, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientNearestAncestorsInFunc) appendCall(r0 ClientNearestAncestorsInFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientNearestAncestorsInFuncCall objects
+// describing the invocations of this function.
+func (f *ClientNearestAncestorsInFunc) History() []ClientNearestAncestorsInFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientNearestAncestorsInFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientNearestAncestorsInFuncCall is an object that describes an
+// invocation of method NearestAncestorsIn on an instance of MockClient.
+type ClientNearestAncestorsInFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 api.CommitID
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 []api.CommitID
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []gitdomain.Ancestor
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientNearestAncestorsInFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientNearestAncestorsInFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ClientNewFileReaderFunc describes the behavior when the NewFileReader
 // method of the parent MockClient instance is invoked.
 type ClientNewFileReaderFunc struct {
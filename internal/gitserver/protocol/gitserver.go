@@ -169,6 +169,11 @@ type RepoUpdateRequest struct {
 	// repository. If this is set, then the RepoUpdateRequest is to migrate the repo from
 	// that gitserver instance to the new home of the repo.
 	CloneFromShard string `json:"cloneFromShard"`
+
+	// ReplicateFromShard is the address of the gitserver instance that owns the
+	// repository. If this is set, then the RepoUpdateRequest is sent by that
+	// instance to sync the replica of the repository kept by the receiver.
+	ReplicateFromShard string `json:"replicateFromShard"`
 }
 
 // RepoUpdateResponse returns meta information of the repo enqueued for update.
@@ -214,6 +219,14 @@ type ReposStats struct {
 
 	// GitDirBytes is the amount of bytes stored in .git directories.
 	GitDirBytes int64
+
+	// Replicas is the number of repositories stored as replicas of
+	// repositories owned by other gitservers.
+	Replicas int
+
+	// ReplicaGitDirBytes is the amount of bytes of GitDirBytes stored in the
+	// .git directories of replicas.
+	ReplicaGitDirBytes int64
 }
 
 // RepoCloneProgressRequest is a request for information about the clone progress of multiple
@@ -253,6 +266,10 @@ type CreateCommitFromPatchRequest struct {
 	// Push specifies whether the target ref will be pushed to the code host: if
 	// nil, no push will be attempted, if non-nil, a push will be attempted.
 	Push *PushConfig
+	// PushRef is the ref on the code host the commit will be pushed to, if it
+	// differs from TargetRef. This is needed for code hosts like Gerrit, where
+	// commits are pushed to magic refs such as refs/for/<branch>.
+	PushRef string
 	// GitApplyArgs are the arguments that will be passed to `git apply` along
 	// with `--cached`.
 	GitApplyArgs []string
@@ -335,3 +352,86 @@ type GetObjectRequest struct {
 type GetObjectResponse struct {
 	Object gitdomain.GitObject
 }
+
+// BlameRequest is a request to blame a file at a commit.
+type BlameRequest struct {
+	Repo api.RepoName
+	// Commit is the revision the file is blamed at. It defaults to HEAD.
+	Commit string
+	Path   string
+	// StartLine and EndLine restrict the blame to a 1-indexed range of lines
+	// if non-zero.
+	StartLine int `json:",omitempty"`
+	EndLine   int `json:",omitempty"`
+}
+
+// BlameResponse is the response to a BlameRequest.
+type BlameResponse struct {
+	Hunks []BlameHunk
+}
+
+// BlameHunk is a contiguous range of lines attributed to the same commit.
+type BlameHunk struct {
+	StartLine int // 1-indexed start line number
+	EndLine   int // 1-indexed end line number (exclusive)
+	StartByte int // 0-indexed start byte position (inclusive)
+	EndByte   int // 0-indexed end byte position (exclusive)
+	CommitID  api.CommitID
+	Author    gitdomain.Signature
+	Message   string
+	Filename  string
+}
+
+// IsAncestorRequest is a request to check whether a commit is an ancestor of
+// another.
+type IsAncestorRequest struct {
+	Repo       api.RepoName
+	Ancestor   api.CommitID
+	Descendant api.CommitID
+}
+
+// IsAncestorResponse is the response to an IsAncestorRequest.
+type IsAncestorResponse struct {
+	IsAncestor bool
+}
+
+// NearestAncestorsInRequest is a request for the nearest ancestors of a commit
+// among a set of candidate commits.
+type NearestAncestorsInRequest struct {
+	Repo       api.RepoName
+	Commit     api.CommitID
+	Candidates []api.CommitID
+}
+
+// NearestAncestorsInResponse is the response to a NearestAncestorsInRequest.
+type NearestAncestorsInResponse struct {
+	// Ancestors are the candidates which are ancestors of the commit (or the
+	// commit itself) and not ancestors of another such candidate, sorted by
+	// commit.
+	Ancestors []gitdomain.Ancestor
+}
+
+// CommitsBetweenRequest is a request for the commits reachable from Head but
+// not from Base.
+type CommitsBetweenRequest struct {
+	Repo api.RepoName
+	// Base is optional. If empty, all commits reachable from Head are
+	// returned.
+	Base api.CommitID `json:",omitempty"`
+	Head api.CommitID
+	// Limit is the maximum number of commits returned if non-zero.
+	Limit int `json:",omitempty"`
+}
+
+// CommitsBetweenResponse is the response to a CommitsBetweenRequest.
+type CommitsBetweenResponse struct {
+	// Commits are in reverse topological order, newest first.
+	Commits []api.CommitID
+}
+
+// RevisionNotFoundPayload is the payload returned with a 422 status by the
+// commit ancestry endpoints, and with a 404 status by the blame endpoint, when
+// a commit does not exist.
+type RevisionNotFoundPayload struct {
+	Spec string
+}
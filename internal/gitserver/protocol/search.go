@@ -85,6 +85,58 @@ func (d *DiffModifiesFile) String() string {
 	return fmt.Sprintf("%T(%s)", d, d.Expr)
 }
 
+// DiffRenamesFile is a predicate that matches if the commit renames any files
+// whose original or new path matches the given regex pattern.
+type DiffRenamesFile struct {
+	Expr       string
+	IgnoreCase bool
+}
+
+func (d *DiffRenamesFile) String() string {
+	return fmt.Sprintf("%T(%s)", d, d.Expr)
+}
+
+// DiffDeletesFile is a predicate that matches if the commit deletes any files
+// that match the given regex pattern.
+type DiffDeletesFile struct {
+	Expr       string
+	IgnoreCase bool
+}
+
+func (d *DiffDeletesFile) String() string {
+	return fmt.Sprintf("%T(%s)", d, d.Expr)
+}
+
+// DiffLinesAdded is a predicate that matches if the commit adds more than
+// GreaterThan lines across all files.
+type DiffLinesAdded struct {
+	GreaterThan int
+}
+
+func (d *DiffLinesAdded) String() string {
+	return fmt.Sprintf("%T(%d)", d, d.GreaterThan)
+}
+
+// DiffLinesRemoved is a predicate that matches if the commit removes more
+// than GreaterThan lines across all files.
+type DiffLinesRemoved struct {
+	GreaterThan int
+}
+
+func (d *DiffLinesRemoved) String() string {
+	return fmt.Sprintf("%T(%d)", d, d.GreaterThan)
+}
+
+// DiffFilesChanged is a predicate that matches if the commit changes more
+// than GreaterThan files.
+type DiffFilesChanged struct {
+	GreaterThan int
+}
+
+func (d *DiffFilesChanged) String() string {
+	return fmt.Sprintf("%T(%d)", d, d.GreaterThan)
+}
+
 // Boolean is a predicate that will either always match or never match
 type Boolean struct {
 	Value bool
@@ -219,6 +271,11 @@ func RegisterGob() {
 		gob.Register(&MessageMatches{})
 		gob.Register(&DiffMatches{})
 		gob.Register(&DiffModifiesFile{})
+		gob.Register(&DiffRenamesFile{})
+		gob.Register(&DiffDeletesFile{})
+		gob.Register(&DiffLinesAdded{})
+		gob.Register(&DiffLinesRemoved{})
+		gob.Register(&DiffFilesChanged{})
 		gob.Register(&Boolean{})
 		gob.Register(&Operator{})
 	})
@@ -220,6 +220,26 @@ func mergeOrRegexp(n Node) Node {
 			} else {
 				mergeable[key] = v
 			}
+		case *DiffRenamesFile:
+			key := DiffRenamesFile{IgnoreCase: v.IgnoreCase}
+			if prev, ok := mergeable[key]; ok {
+				mergeable[key] = &DiffRenamesFile{
+					Expr:       union(prev.(*DiffRenamesFile).Expr, v.Expr),
+					IgnoreCase: v.IgnoreCase,
+				}
+			} else {
+				mergeable[key] = v
+			}
+		case *DiffDeletesFile:
+			key := DiffDeletesFile{IgnoreCase: v.IgnoreCase}
+			if prev, ok := mergeable[key]; ok {
+				mergeable[key] = &DiffDeletesFile{
+					Expr:       union(prev.(*DiffDeletesFile).Expr, v.Expr),
+					IgnoreCase: v.IgnoreCase,
+				}
+			} else {
+				mergeable[key] = v
+			}
 		default:
 			unmergeable = append(unmergeable, operand)
 		}
@@ -252,8 +272,10 @@ func estimatedNodeCost(n Node) float64 {
 		return 5
 	case *MessageMatches:
 		return 10
-	case *DiffModifiesFile:
+	case *DiffModifiesFile, *DiffRenamesFile, *DiffDeletesFile:
 		return 1000
+	case *DiffLinesAdded, *DiffLinesRemoved, *DiffFilesChanged:
+		return 2000
 	case *DiffMatches:
 		return 10000
 	default:
@@ -0,0 +1,113 @@
+package gitserver
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	sglog "github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var readFailovers = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_gitserver_client_read_failovers_total",
+	Help: "Number of reads served by a replica because the primary gitserver was unreachable",
+}, []string{"user_agent"})
+
+// ReplicationFactor returns the number of gitserver instances each repository
+// is stored on, as configured in the site configuration.
+func ReplicationFactor() int {
+	cfg := conf.Get()
+	if cfg.ExperimentalFeatures != nil && cfg.ExperimentalFeatures.GitServerReplicationFactor > 1 {
+		return cfg.ExperimentalFeatures.GitServerReplicationFactor
+	}
+	return 1
+}
+
+// ReplicaAddrsForRepo returns the addresses of the gitserver instances which
+// keep a replica of repo, in the order they are tried when primary, the address
+// of the instance owning repo, is unreachable.
+//
+// Replicas are placed on the instances ranked next for repo by rendezvous
+// hashing, so that as few replicas as possible move when instances are added or
+// removed, and the first replica becomes the primary when primary is removed.
+func ReplicaAddrsForRepo(repo api.RepoName, primary string, addrs []string, factor int) []string {
+	remaining := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		if addr != primary {
+			remaining = append(remaining, addr)
+		}
+	}
+
+	var replicas []string
+	for len(replicas) < factor-1 && len(remaining) > 0 {
+		addr := RendezvousAddrForRepo(repo, remaining)
+		replicas = append(replicas, addr)
+		for i := range remaining {
+			if remaining[i] == addr {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				break
+			}
+		}
+	}
+	return replicas
+}
+
+// doWithFailover performs a request to the gitserver instance owning repo. If
+// the instance is unreachable, the request is retried against the replicas of
+// repo. uri is the path and query of the request.
+//
+// It must only be used for requests which don't modify the repository.
+func (c *clientImplementor) doWithFailover(ctx context.Context, repo api.RepoName, method, uri string, payload []byte) (*http.Response, error) {
+	primary, err := c.AddrForRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(ctx, repo, method, "http://"+primary+uri, payload)
+	if err == nil || !isUnreachable(err) {
+		return resp, err
+	}
+
+	for _, addr := range ReplicaAddrsForRepo(repo, primary, c.addrs(), c.replicationFactor()) {
+		if ctx.Err() != nil {
+			break
+		}
+		resp, replicaErr := c.do(ctx, repo, method, "http://"+addr+uri, payload)
+		if replicaErr == nil {
+			readFailovers.WithLabelValues(c.userAgent).Inc()
+			c.logger.Debug("primary gitserver unreachable, read from replica",
+				sglog.String("repo", string(repo)),
+				sglog.String("primary", primary),
+				sglog.String("replica", addr),
+			)
+			return resp, nil
+		}
+		if !isUnreachable(replicaErr) {
+			return nil, replicaErr
+		}
+	}
+	return nil, err
+}
+
+// httpPostWithFailover is like httpPost, but fails over to a replica of repo if
+// the primary gitserver is unreachable.
+func (c *clientImplementor) httpPostWithFailover(ctx context.Context, repo api.RepoName, op string, payload any) (*http.Response, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return c.doWithFailover(ctx, repo, "POST", "/"+op, b)
+}
+
+// isUnreachable reports whether err was returned because no connection to the
+// gitserver instance could be established.
+func isUnreachable(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -16,6 +16,14 @@ import (
 type DiffFetcher struct {
 	dir string
 
+	// DetectRenames makes git report renamed files as renames rather than as a
+	// deletion and an addition. It must be set before the first call to Fetch.
+	DetectRenames bool
+
+	// Env is the environment of the git subprocess, if set. It must be set
+	// before the first call to Fetch.
+	Env []string
+
 	startOnce sync.Once
 	stdin     io.Writer
 	stderr    io.Reader
@@ -42,15 +50,20 @@ func (d *DiffFetcher) start() (err error) {
 	d.startOnce.Do(func() {
 		ctx := context.Background()
 		ctx, d.cancel = context.WithCancel(ctx)
-		d.cmd = exec.CommandContext(ctx, "git",
+		args := []string{
 			"diff-tree",
 			"--stdin",          // Read commit hashes from stdin
 			"--no-prefix",      // Do not prefix file names with a/ and b/
 			"-p",               // Output in patch format
 			"--format=format:", // Output only the patch, not any other commit metadata
 			"--root",           // Treat the root commit as a big creation event (otherwise the diff would be empty)
-		)
+		}
+		if d.DetectRenames {
+			args = append(args, "-M") // Report renamed files as renames
+		}
+		d.cmd = exec.CommandContext(ctx, "git", args...)
 		d.cmd.Dir = d.dir
+		d.cmd.Env = d.Env
 
 		var stdoutReader io.ReadCloser
 		stdoutReader, err = d.cmd.StdoutPipe()
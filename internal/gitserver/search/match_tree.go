@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"unicode/utf8"
 
+	"github.com/sourcegraph/go-diff/diff"
+
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/search/casetransform"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
@@ -34,6 +36,18 @@ func ToMatchTree(q protocol.Node) (MatchTree, error) {
 	case *protocol.DiffModifiesFile:
 		re, err := casetransform.CompileRegexp(v.Expr, v.IgnoreCase)
 		return &DiffModifiesFile{re}, err
+	case *protocol.DiffRenamesFile:
+		re, err := casetransform.CompileRegexp(v.Expr, v.IgnoreCase)
+		return &DiffRenamesFile{re}, err
+	case *protocol.DiffDeletesFile:
+		re, err := casetransform.CompileRegexp(v.Expr, v.IgnoreCase)
+		return &DiffDeletesFile{re}, err
+	case *protocol.DiffLinesAdded:
+		return &DiffLinesAdded{*v}, nil
+	case *protocol.DiffLinesRemoved:
+		return &DiffLinesRemoved{*v}, nil
+	case *protocol.DiffFilesChanged:
+		return &DiffFilesChanged{*v}, nil
 	case *protocol.Boolean:
 		return &Constant{v.Value}, nil
 	case *protocol.Operator:
@@ -192,6 +206,46 @@ type DiffModifiesFile struct {
 }
 
 func (dmf *DiffModifiesFile) Match(lc *LazyCommit) (CommitFilterResult, MatchedCommit, error) {
+	return matchFileDiffNames(lc, dmf.Regexp, func(fileDiff *diff.FileDiff) (bool, bool) {
+		return true, true
+	})
+}
+
+// DiffRenamesFile is a predicate that matches if the commit renames any files
+// whose original or new path matches the given regex pattern. Renames are only
+// detected if the DiffFetcher has DetectRenames set.
+type DiffRenamesFile struct {
+	*casetransform.Regexp
+}
+
+func (drf *DiffRenamesFile) Match(lc *LazyCommit) (CommitFilterResult, MatchedCommit, error) {
+	return matchFileDiffNames(lc, drf.Regexp, func(fileDiff *diff.FileDiff) (bool, bool) {
+		renamed := fileDiff.OrigName != fileDiff.NewName && fileDiff.OrigName != devNull && fileDiff.NewName != devNull
+		return renamed, renamed
+	})
+}
+
+// DiffDeletesFile is a predicate that matches if the commit deletes any files
+// that match the given regex pattern.
+type DiffDeletesFile struct {
+	*casetransform.Regexp
+}
+
+func (ddf *DiffDeletesFile) Match(lc *LazyCommit) (CommitFilterResult, MatchedCommit, error) {
+	return matchFileDiffNames(lc, ddf.Regexp, func(fileDiff *diff.FileDiff) (bool, bool) {
+		// Only the original name is matched, since the new name of a deleted
+		// file is always /dev/null.
+		return fileDiff.NewName == devNull, false
+	})
+}
+
+// devNull is the name git uses for the missing side of an added or deleted file.
+const devNull = "/dev/null"
+
+// matchFileDiffNames matches re against the names of the single file diffs of
+// lc. matchNames reports, for each file diff, whether its original and new
+// names should be matched at all.
+func matchFileDiffNames(lc *LazyCommit, re *casetransform.Regexp, matchNames func(*diff.FileDiff) (matchOrig, matchNew bool)) (CommitFilterResult, MatchedCommit, error) {
 	diff, err := lc.Diff()
 	if err != nil {
 		return filterResult(false), MatchedCommit{}, err
@@ -200,8 +254,14 @@ func (dmf *DiffModifiesFile) Match(lc *LazyCommit) (CommitFilterResult, MatchedC
 	var fileDiffHighlights map[int]MatchedFileDiff
 	matchedFileDiffs := make(map[int]struct{})
 	for fileIdx, fileDiff := range diff {
-		oldFileMatches := dmf.FindAllIndex([]byte(fileDiff.OrigName), -1, &lc.LowerBuf)
-		newFileMatches := dmf.FindAllIndex([]byte(fileDiff.NewName), -1, &lc.LowerBuf)
+		matchOrig, matchNew := matchNames(fileDiff)
+		var oldFileMatches, newFileMatches [][]int
+		if matchOrig {
+			oldFileMatches = re.FindAllIndex([]byte(fileDiff.OrigName), -1, &lc.LowerBuf)
+		}
+		if matchNew {
+			newFileMatches = re.FindAllIndex([]byte(fileDiff.NewName), -1, &lc.LowerBuf)
+		}
 		if oldFileMatches != nil || newFileMatches != nil {
 			if fileDiffHighlights == nil {
 				fileDiffHighlights = make(map[int]MatchedFileDiff)
@@ -217,6 +277,89 @@ func (dmf *DiffModifiesFile) Match(lc *LazyCommit) (CommitFilterResult, MatchedC
 	return CommitFilterResult{MatchedFileDiffs: matchedFileDiffs}, MatchedCommit{Diff: fileDiffHighlights}, nil
 }
 
+// DiffLinesAdded is a predicate that matches if the commit adds more than
+// GreaterThan lines across all files.
+type DiffLinesAdded struct {
+	protocol.DiffLinesAdded
+}
+
+func (d *DiffLinesAdded) Match(lc *LazyCommit) (CommitFilterResult, MatchedCommit, error) {
+	diff, err := lc.Diff()
+	if err != nil {
+		return filterResult(false), MatchedCommit{}, err
+	}
+	added, _ := countChangedLines(diff)
+	return filterResult(added > d.GreaterThan), MatchedCommit{}, nil
+}
+
+// DiffLinesRemoved is a predicate that matches if the commit removes more
+// than GreaterThan lines across all files.
+type DiffLinesRemoved struct {
+	protocol.DiffLinesRemoved
+}
+
+func (d *DiffLinesRemoved) Match(lc *LazyCommit) (CommitFilterResult, MatchedCommit, error) {
+	diff, err := lc.Diff()
+	if err != nil {
+		return filterResult(false), MatchedCommit{}, err
+	}
+	_, removed := countChangedLines(diff)
+	return filterResult(removed > d.GreaterThan), MatchedCommit{}, nil
+}
+
+// DiffFilesChanged is a predicate that matches if the commit changes more
+// than GreaterThan files.
+type DiffFilesChanged struct {
+	protocol.DiffFilesChanged
+}
+
+func (d *DiffFilesChanged) Match(lc *LazyCommit) (CommitFilterResult, MatchedCommit, error) {
+	diff, err := lc.Diff()
+	if err != nil {
+		return filterResult(false), MatchedCommit{}, err
+	}
+	return filterResult(len(diff) > d.GreaterThan), MatchedCommit{}, nil
+}
+
+// countChangedLines returns the number of lines added and removed by the
+// hunks of fileDiffs.
+func countChangedLines(fileDiffs []*diff.FileDiff) (added, removed int) {
+	for _, fileDiff := range fileDiffs {
+		for _, hunk := range fileDiff.Hunks {
+			for _, line := range bytes.Split(hunk.Body, []byte("\n")) {
+				if len(line) == 0 {
+					continue
+				}
+				switch line[0] {
+				case '+':
+					added++
+				case '-':
+					removed++
+				}
+			}
+		}
+	}
+	return added, removed
+}
+
+// needsRenameDetection returns whether mt contains a predicate on the shape of
+// a change. These need git to detect renamed files, both to match renames at
+// all and so that, like in git log --stat, a renamed file counts as a single
+// changed file rather than a deleted and an added file.
+func needsRenameDetection(mt MatchTree) bool {
+	switch v := mt.(type) {
+	case *DiffRenamesFile, *DiffDeletesFile, *DiffLinesAdded, *DiffLinesRemoved, *DiffFilesChanged:
+		return true
+	case *Operator:
+		for _, operand := range v.Operands {
+			if needsRenameDetection(operand) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 type Constant struct {
 	Value bool
 }
@@ -90,6 +90,9 @@ type CommitSearcher struct {
 	IncludeDiff          bool
 	IncludeModifiedFiles bool
 	RepoName             api.RepoName
+
+	// Env is the environment of the git commands, if set.
+	Env []string
 }
 
 // Search runs a search for commits matching the given predicate across the revisions passed in as revisionArgs.
@@ -143,6 +146,7 @@ func (cs *CommitSearcher) feedBatches(ctx context.Context, jobs chan job, result
 	}
 	cmd := exec.CommandContext(ctx, "git", args...)
 	cmd.Dir = cs.RepoDir
+	cmd.Env = cs.Env
 	stdoutReader, err := cmd.StdoutPipe()
 	if err != nil {
 		return err
@@ -220,6 +224,8 @@ func (cs *CommitSearcher) runJobs(ctx context.Context, jobs chan job) error {
 	if err != nil {
 		return err
 	}
+	diffFetcher.DetectRenames = needsRenameDetection(cs.Query)
+	diffFetcher.Env = cs.Env
 	defer diffFetcher.Stop()
 
 	startBuf := make([]byte, 1024)
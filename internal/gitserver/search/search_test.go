@@ -215,6 +215,67 @@ func TestSearch(t *testing.T) {
 	})
 }
 
+func TestSearchDiffShape(t *testing.T) {
+	cmds := []string{
+		"printf 'a\\nb\\nc\\nd\\n' > file1",
+		"echo lorem ipsum > file2",
+		"git add -A",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_AUTHOR_NAME=a GIT_AUTHOR_EMAIL=a@a.com git commit -m commit1",
+		"mkdir dir",
+		"git mv file1 dir/file1",
+		"printf 'a\\nb\\nc\\n' > dir/file1",
+		"git rm file2",
+		"git add -A",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_AUTHOR_NAME=a GIT_AUTHOR_EMAIL=a@a.com git commit -m commit2",
+	}
+	dir := initGitRepository(t, cmds...)
+
+	search := func(t *testing.T, query protocol.Node) []string {
+		tree, err := ToMatchTree(query)
+		require.NoError(t, err)
+		searcher := &CommitSearcher{
+			RepoDir: dir,
+			Query:   tree,
+		}
+		var messages []string
+		err = searcher.Search(context.Background(), func(match *protocol.CommitMatch) {
+			messages = append(messages, strings.TrimSpace(match.Message.Content))
+		})
+		require.NoError(t, err)
+		return messages
+	}
+
+	t.Run("lines added", func(t *testing.T) {
+		require.Equal(t, []string{"commit1"}, search(t, &protocol.DiffLinesAdded{GreaterThan: 3}))
+		require.Empty(t, search(t, &protocol.DiffLinesAdded{GreaterThan: 5}))
+	})
+
+	t.Run("lines removed", func(t *testing.T) {
+		require.Equal(t, []string{"commit2"}, search(t, &protocol.DiffLinesRemoved{GreaterThan: 0}))
+		require.Empty(t, search(t, &protocol.DiffLinesRemoved{GreaterThan: 2}))
+	})
+
+	t.Run("files changed", func(t *testing.T) {
+		require.Equal(t, []string{"commit2", "commit1"}, search(t, &protocol.DiffFilesChanged{GreaterThan: 1}))
+		require.Empty(t, search(t, &protocol.DiffFilesChanged{GreaterThan: 2}))
+	})
+
+	t.Run("renamed from", func(t *testing.T) {
+		require.Equal(t, []string{"commit2"}, search(t, &protocol.DiffRenamesFile{Expr: "^file1$"}))
+	})
+
+	t.Run("renamed to", func(t *testing.T) {
+		require.Equal(t, []string{"commit2"}, search(t, &protocol.DiffRenamesFile{Expr: "^dir/"}))
+		require.Empty(t, search(t, &protocol.DiffRenamesFile{Expr: "file2"}))
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		require.Equal(t, []string{"commit2"}, search(t, &protocol.DiffDeletesFile{Expr: "file2"}))
+		require.Empty(t, search(t, &protocol.DiffDeletesFile{Expr: "file1"}))
+		require.Empty(t, search(t, &protocol.DiffDeletesFile{Expr: "null"}))
+	})
+}
+
 func TestCommitScanner(t *testing.T) {
 	cases := []struct {
 		input    []byte
@@ -0,0 +1,157 @@
+package repos
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// An AzureDevOpsSource yields repositories from a single Azure DevOps
+// connection configured in Sourcegraph via the external services
+// configuration.
+type AzureDevOpsSource struct {
+	svc       *types.ExternalService
+	config    *schema.AzureDevOpsConnection
+	exclude   excludeFunc
+	cli       *azuredevops.Client
+	serviceID string
+}
+
+// NewAzureDevOpsSource returns a new AzureDevOpsSource from the given external
+// service.
+func NewAzureDevOpsSource(ctx context.Context, svc *types.ExternalService, cf *httpcli.Factory) (*AzureDevOpsSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.AzureDevOpsConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+
+	if cf == nil {
+		cf = httpcli.ExternalClientFactory
+	}
+
+	httpCli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	var eb excludeBuilder
+	for _, r := range c.Exclude {
+		eb.Exact(r.Name)
+		eb.Exact(r.Id)
+		eb.Pattern(r.Pattern)
+	}
+	exclude, err := eb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := azuredevops.NewClient(svc.URN(), &c, httpCli)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AzureDevOpsSource{
+		svc:       svc,
+		config:    &c,
+		exclude:   exclude,
+		cli:       cli,
+		serviceID: cli.URL.String(),
+	}, nil
+}
+
+// ListRepos returns all repositories of the organizations and projects
+// configured with this AzureDevOpsSource's config.
+func (s *AzureDevOpsSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	seen := make(map[string]bool)
+	emit := func(repos []*azuredevops.Repository) {
+		for _, r := range repos {
+			if seen[r.ID] || r.IsDisabled || s.excludes(r) {
+				continue
+			}
+			seen[r.ID] = true
+			results <- SourceResult{Source: s, Repo: s.makeRepo(r)}
+		}
+	}
+
+	for _, org := range s.config.Orgs {
+		repos, err := s.cli.ListRepositories(ctx, org, "")
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "listing repositories of organization %q", org)}
+			continue
+		}
+		emit(repos)
+	}
+
+	for _, p := range s.config.Projects {
+		org, project, ok := strings.Cut(p, "/")
+		if !ok {
+			results <- SourceResult{Source: s, Err: errors.Errorf("invalid project %q: must be of the form \"org/project\"", p)}
+			continue
+		}
+
+		repos, err := s.cli.ListRepositories(ctx, org, project)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "listing repositories of project %q", p)}
+			continue
+		}
+		emit(repos)
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s *AzureDevOpsSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func (s *AzureDevOpsSource) excludes(r *azuredevops.Repository) bool {
+	return s.exclude(r.FullName()) || s.exclude(r.ID)
+}
+
+func (s *AzureDevOpsSource) makeRepo(r *azuredevops.Repository) *types.Repo {
+	urn := s.svc.URN()
+	host := s.cli.URL.Hostname()
+
+	return &types.Repo{
+		Name: reposource.AzureDevOpsRepoName(s.config.RepositoryPathPattern, host, r.Org, r.Project.Name, r.Name),
+		URI:  string(reposource.AzureDevOpsRepoName("", host, r.Org, r.Project.Name, r.Name)),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          r.ID,
+			ServiceType: extsvc.TypeAzureDevOps,
+			ServiceID:   s.serviceID,
+		},
+		Description: r.Project.Description,
+		Fork:        r.IsFork,
+		Private:     r.Project.Visibility != azuredevops.ProjectVisibilityPublic,
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: s.remoteURL(r),
+			},
+		},
+		Metadata: r,
+	}
+}
+
+// remoteURL returns the repository's Git remote URL
+//
+// note: this used to contain credentials but that is no longer the case
+// if you need to get an authenticated clone url use repos.CloneURL
+func (s *AzureDevOpsSource) remoteURL(r *azuredevops.Repository) string {
+	if s.config.GitURLType == "ssh" {
+		return r.SSHURL
+	}
+	return r.RemoteURL
+}
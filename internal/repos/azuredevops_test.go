@@ -0,0 +1,90 @@
+package repos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestAzureDevOpsSource_ListRepos(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/myorg/_apis/git/repositories":
+			_, _ = w.Write([]byte(`{"count": 3, "value": [
+				{"id": "r1", "name": "api", "project": {"id": "p1", "name": "backend", "visibility": "private"}, "remoteUrl": "https://dev.azure.com/myorg/backend/_git/api"},
+				{"id": "r2", "name": "old", "project": {"id": "p1", "name": "backend", "visibility": "private"}, "remoteUrl": "https://dev.azure.com/myorg/backend/_git/old"},
+				{"id": "r3", "name": "disabled", "project": {"id": "p1", "name": "backend"}, "isDisabled": true}
+			]}`))
+		case "/myorg/backend/_apis/git/repositories":
+			_, _ = w.Write([]byte(`{"count": 1, "value": [
+				{"id": "r1", "name": "api", "project": {"id": "p1", "name": "backend", "visibility": "private"}, "remoteUrl": "https://dev.azure.com/myorg/backend/_git/api"}
+			]}`))
+		case "/other/docs/_apis/git/repositories":
+			_, _ = w.Write([]byte(`{"count": 1, "value": [
+				{"id": "r4", "name": "site", "project": {"id": "p2", "name": "docs", "visibility": "public"}, "remoteUrl": "https://dev.azure.com/other/docs/_git/site", "sshUrl": "git@ssh.dev.azure.com:v3/other/docs/site"}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	conf := &schema.AzureDevOpsConnection{
+		Url:      srv.URL,
+		Token:    "secret",
+		Orgs:     []string{"myorg"},
+		Projects: []string{"myorg/backend", "other/docs"},
+		Exclude: []*schema.ExcludedAzureDevOpsRepo{
+			{Name: "myorg/backend/old"},
+		},
+		RepositoryPathPattern: "ado/{org}/{project}/{repo}",
+	}
+	svc := &types.ExternalService{
+		ID:     1,
+		Kind:   extsvc.KindAzureDevOps,
+		Config: extsvc.NewUnencryptedConfig(marshalJSON(t, conf)),
+	}
+
+	ctx := context.Background()
+	src, err := NewAzureDevOpsSource(ctx, svc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repos, err := listAll(ctx, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(repos, func(i, j int) bool { return repos[i].Name < repos[j].Name })
+
+	var have []api.RepoName
+	for _, r := range repos {
+		have = append(have, r.Name)
+	}
+	want := []api.RepoName{"ado/myorg/backend/api", "ado/other/docs/site"}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatalf("unexpected repos (-want +have):\n%s", diff)
+	}
+
+	if !repos[0].Private || repos[1].Private {
+		t.Errorf("wrong visibility: %v, %v", repos[0].Private, repos[1].Private)
+	}
+	if have, want := repos[1].URI, "127.0.0.1/other/docs/site"; have != want {
+		t.Errorf("wrong URI: have %q, want %q", have, want)
+	}
+	if have, want := repos[1].ExternalRepo.ID, "r4"; have != want {
+		t.Errorf("wrong external ID: have %q, want %q", have, want)
+	}
+	if have, want := repos[1].Sources[svc.URN()].CloneURL, "https://dev.azure.com/other/docs/_git/site"; have != want {
+		t.Errorf("wrong clone URL: have %q, want %q", have, want)
+	}
+}
@@ -0,0 +1,51 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// BitbucketCloudWebhookHandler enqueues an update of the repository of
+// Bitbucket Cloud push events.
+type BitbucketCloudWebhookHandler struct {
+	Repos  database.RepoStore
+	logger log.Logger
+}
+
+func (b *BitbucketCloudWebhookHandler) Register(router *webhooks.BitbucketCloudWebhook) {
+	b.logger = log.Scoped("repos.BitbucketCloudWebhookHandler", "bitbucket cloud webhook handler")
+	router.Register(b.handleBitbucketCloudWebhook, "repo:push")
+}
+
+func (b *BitbucketCloudWebhookHandler) handleBitbucketCloudWebhook(ctx context.Context, extSvc *types.ExternalService, payload any) error {
+	event, ok := payload.(*bitbucketcloud.PushEvent)
+	if !ok {
+		return errors.Newf("expected BitbucketCloud.PushEvent, got %T", payload)
+	}
+
+	c, err := extSvc.Configuration(ctx)
+	if err != nil {
+		return errors.Wrap(err, "handleBitbucketCloudWebhook: get configuration failed")
+	}
+	conn, ok := c.(*schema.BitbucketCloudConnection)
+	if !ok {
+		return errors.Newf("expected Bitbucket Cloud connection, got %T", c)
+	}
+
+	resp, err := enqueueRepoUpdateForExternalRepo(ctx, b.Repos, extsvc.TypeBitbucketCloud, conn.Url, event.Repository.UUID)
+	if err != nil {
+		return errors.Wrap(err, "handleBitbucketCloudWebhook: EnqueueRepoUpdate failed")
+	}
+
+	b.logger.Info("successfully updated", log.String("name", resp.Name))
+	return nil
+}
@@ -0,0 +1,52 @@
+package repos
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// BitbucketServerWebhookHandler enqueues an update of the repository of
+// Bitbucket Server push events.
+type BitbucketServerWebhookHandler struct {
+	Repos  database.RepoStore
+	logger log.Logger
+}
+
+func (b *BitbucketServerWebhookHandler) Register(router *webhooks.BitbucketServerWebhook) {
+	b.logger = log.Scoped("repos.BitbucketServerWebhookHandler", "bitbucket server webhook handler")
+	router.Register(b.handleBitbucketServerWebhook, "repo:refs_changed")
+}
+
+func (b *BitbucketServerWebhookHandler) handleBitbucketServerWebhook(ctx context.Context, extSvc *types.ExternalService, payload any) error {
+	event, ok := payload.(*bitbucketserver.PushEvent)
+	if !ok {
+		return errors.Newf("expected BitbucketServer.PushEvent, got %T", payload)
+	}
+
+	c, err := extSvc.Configuration(ctx)
+	if err != nil {
+		return errors.Wrap(err, "handleBitbucketServerWebhook: get configuration failed")
+	}
+	conn, ok := c.(*schema.BitbucketServerConnection)
+	if !ok {
+		return errors.Newf("expected Bitbucket Server connection, got %T", c)
+	}
+
+	resp, err := enqueueRepoUpdateForExternalRepo(ctx, b.Repos, extsvc.TypeBitbucketServer, conn.Url, strconv.Itoa(event.Repository.ID))
+	if err != nil {
+		return errors.Wrap(err, "handleBitbucketServerWebhook: EnqueueRepoUpdate failed")
+	}
+
+	b.logger.Info("successfully updated", log.String("name", resp.Name))
+	return nil
+}
@@ -11,9 +11,11 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
@@ -52,6 +54,10 @@ func cloneURL(parsed any, logger log.Logger, kind string, repo *types.Repo) (str
 		if r, ok := repo.Metadata.(*awscodecommit.Repository); ok {
 			return awsCodeCloneURL(logger, r, t), nil
 		}
+	case *schema.AzureDevOpsConnection:
+		if r, ok := repo.Metadata.(*azuredevops.Repository); ok {
+			return azureDevOpsCloneURL(r, t)
+		}
 	case *schema.BitbucketServerConnection:
 		if r, ok := repo.Metadata.(*bitbucketserver.Repo); ok {
 			return bitbucketServerCloneURL(r, t), nil
@@ -64,6 +70,10 @@ func cloneURL(parsed any, logger log.Logger, kind string, repo *types.Repo) (str
 		if r, ok := repo.Metadata.(*gerrit.Project); ok {
 			return gerritCloneURL(logger, r, t), nil
 		}
+	case *schema.GiteaConnection:
+		if r, ok := repo.Metadata.(*gitea.Repository); ok {
+			return giteaCloneURL(r, t)
+		}
 	case *schema.GitHubConnection:
 		if r, ok := repo.Metadata.(*github.Repository); ok {
 			return githubCloneURL(logger, r, t)
@@ -92,6 +102,18 @@ func cloneURL(parsed any, logger log.Logger, kind string, repo *types.Repo) (str
 		if r, ok := repo.Metadata.(*extsvc.OtherRepoMetadata); ok {
 			return otherCloneURL(repo, r), nil
 		}
+	case *schema.MercurialConnection:
+		if r, ok := repo.Metadata.(*extsvc.MercurialRepoMetadata); ok {
+			base, err := url.Parse(t.Url)
+			if err != nil {
+				return "", err
+			}
+			return mercurialCloneURL(base, r)
+		}
+	case *schema.SubversionConnection:
+		if r, ok := repo.Metadata.(*extsvc.SubversionRepoMetadata); ok {
+			return subversionCloneURL(t, r)
+		}
 	case *schema.GoModulesConnection:
 		return string(repo.Name), nil
 	case *schema.PythonPackagesConnection:
@@ -177,6 +199,46 @@ func bitbucketCloudCloneURL(logger log.Logger, repo *bitbucketcloud.Repo, cfg *s
 	return u.String()
 }
 
+// azureDevOpsCloneURL returns the URL to clone the repository with,
+// authenticated with the token of the connection unless SSH is used.
+func azureDevOpsCloneURL(r *azuredevops.Repository, cfg *schema.AzureDevOpsConnection) (string, error) {
+	if cfg.GitURLType == "ssh" {
+		return r.SSHURL, nil
+	}
+
+	u, err := url.Parse(r.RemoteURL)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing clone URL")
+	}
+	if cfg.Token != "" {
+		// Azure DevOps ignores the username, and Azure DevOps Services puts the
+		// organization there, so we only replace it if one is configured.
+		username := cfg.Username
+		if username == "" && u.User != nil {
+			username = u.User.Username()
+		}
+		u.User = url.UserPassword(username, cfg.Token)
+	}
+	return u.String(), nil
+}
+
+// giteaCloneURL returns the URL to clone the repository with, authenticated
+// with the token of the connection unless SSH is used.
+func giteaCloneURL(r *gitea.Repository, cfg *schema.GiteaConnection) (string, error) {
+	if cfg.GitURLType == "ssh" {
+		return r.SSHURL, nil
+	}
+
+	u, err := url.Parse(r.CloneURL)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing clone URL")
+	}
+	if cfg.Token != "" {
+		u.User = url.User(cfg.Token)
+	}
+	return u.String(), nil
+}
+
 func githubCloneURL(logger log.Logger, repo *github.Repository, cfg *schema.GitHubConnection) (string, error) {
 	if cfg.GitURLType == "ssh" {
 		baseURL, err := url.Parse(cfg.Url)
@@ -0,0 +1,177 @@
+package repos
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A GiteaSource yields repositories from a single Gitea (or Forgejo)
+// connection configured in Sourcegraph via the external services
+// configuration.
+type GiteaSource struct {
+	svc       *types.ExternalService
+	config    *schema.GiteaConnection
+	exclude   excludeFunc
+	cli       *gitea.Client
+	serviceID string
+	perPage   int
+}
+
+// NewGiteaSource returns a new GiteaSource from the given external service.
+func NewGiteaSource(ctx context.Context, svc *types.ExternalService, cf *httpcli.Factory) (*GiteaSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.GiteaConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+
+	if cf == nil {
+		cf = httpcli.ExternalClientFactory
+	}
+
+	httpCli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	var eb excludeBuilder
+	for _, r := range c.Exclude {
+		eb.Exact(r.Name)
+		if r.Id != 0 {
+			eb.Exact(strconv.Itoa(r.Id))
+		}
+		eb.Pattern(r.Pattern)
+	}
+	exclude, err := eb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := gitea.NewClient(svc.URN(), &c, httpCli)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GiteaSource{
+		svc:       svc,
+		config:    &c,
+		exclude:   exclude,
+		cli:       cli,
+		serviceID: cli.URL.String(),
+		perPage:   50,
+	}, nil
+}
+
+// ListRepos returns all repositories of the organizations and users
+// configured with this GiteaSource's config.
+func (s *GiteaSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	seen := make(map[int64]bool)
+	emit := func(repos []*gitea.Repository) {
+		for _, r := range repos {
+			if seen[r.ID] || s.excludes(r) {
+				continue
+			}
+			seen[r.ID] = true
+			results <- SourceResult{Source: s, Repo: s.makeRepo(r)}
+		}
+	}
+
+	for _, org := range s.config.Orgs {
+		err := s.listAll(ctx, func(opts gitea.ListOptions) ([]*gitea.Repository, bool, error) {
+			return s.cli.ListOrgRepos(ctx, org, opts)
+		}, emit)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "listing repositories of organization %q", org)}
+		}
+	}
+
+	for _, user := range s.config.Users {
+		err := s.listAll(ctx, func(opts gitea.ListOptions) ([]*gitea.Repository, bool, error) {
+			return s.cli.ListUserRepos(ctx, user, opts)
+		}, emit)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "listing repositories of user %q", user)}
+		}
+	}
+}
+
+// listAll calls list for every page of a paginated list of repositories, and
+// passes each page to emit.
+func (s *GiteaSource) listAll(ctx context.Context, list func(gitea.ListOptions) ([]*gitea.Repository, bool, error), emit func([]*gitea.Repository)) error {
+	opts := gitea.ListOptions{Page: 1, Limit: s.perPage}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		repos, hasNextPage, err := list(opts)
+		if err != nil {
+			return err
+		}
+		emit(repos)
+
+		if !hasNextPage {
+			return nil
+		}
+		opts.Page++
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s *GiteaSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func (s *GiteaSource) excludes(r *gitea.Repository) bool {
+	return s.exclude(r.FullName) || s.exclude(strconv.FormatInt(r.ID, 10))
+}
+
+func (s *GiteaSource) makeRepo(r *gitea.Repository) *types.Repo {
+	urn := s.svc.URN()
+	host := s.cli.URL.Hostname()
+
+	return &types.Repo{
+		Name: reposource.GiteaRepoName(s.config.RepositoryPathPattern, host, r.FullName),
+		URI:  string(reposource.GiteaRepoName("", host, r.FullName)),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          strconv.FormatInt(r.ID, 10),
+			ServiceType: extsvc.TypeGitea,
+			ServiceID:   s.serviceID,
+		},
+		Description: r.Description,
+		Fork:        r.Fork,
+		Archived:    r.Archived,
+		Private:     r.Private,
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: s.remoteURL(r),
+			},
+		},
+		Metadata: r,
+	}
+}
+
+// remoteURL returns the repository's Git remote URL
+//
+// note: this used to contain credentials but that is no longer the case
+// if you need to get an authenticated clone url use repos.CloneURL
+func (s *GiteaSource) remoteURL(r *gitea.Repository) string {
+	if s.config.GitURLType == "ssh" {
+		return r.SSHURL
+	}
+	return r.CloneURL
+}
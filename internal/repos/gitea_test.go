@@ -0,0 +1,47 @@
+package repos
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/testutil"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestGiteaSource_ListRepos(t *testing.T) {
+	conf := &schema.GiteaConnection{
+		Url:   "https://gitea.com",
+		Token: os.Getenv("GITEA_TOKEN"),
+		Orgs:  []string{"gitea"},
+		Users: []string{"lunny"},
+		Exclude: []*schema.ExcludedGiteaRepo{
+			{Name: "gitea/helm-chart"},
+		},
+	}
+	cf, save := newClientFactory(t, t.Name())
+	defer save(t)
+
+	svc := &types.ExternalService{
+		Kind:   extsvc.KindGitea,
+		Config: extsvc.NewUnencryptedConfig(marshalJSON(t, conf)),
+	}
+
+	ctx := context.Background()
+	src, err := NewGiteaSource(ctx, svc, cf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src.perPage = 2 // 2 pages for the 3 repositories of the organization
+
+	repos, err := listAll(context.Background(), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutil.AssertGolden(t, "testdata/sources/"+t.Name(), update(t.Name()), repos)
+}
@@ -0,0 +1,52 @@
+package repos
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	gitlabwebhooks "github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// GitLabWebhookHandler enqueues an update of the repository of GitLab push
+// events.
+type GitLabWebhookHandler struct {
+	Repos  database.RepoStore
+	logger log.Logger
+}
+
+func (g *GitLabWebhookHandler) Register(router *webhooks.GitLabWebhook) {
+	g.logger = log.Scoped("repos.GitLabWebhookHandler", "gitlab webhook handler")
+	router.Register(g.handleGitLabWebhook, "push", "tag_push")
+}
+
+func (g *GitLabWebhookHandler) handleGitLabWebhook(ctx context.Context, extSvc *types.ExternalService, payload any) error {
+	event, ok := payload.(*gitlabwebhooks.PushEvent)
+	if !ok {
+		return errors.Newf("expected GitLab.PushEvent, got %T", payload)
+	}
+
+	c, err := extSvc.Configuration(ctx)
+	if err != nil {
+		return errors.Wrap(err, "handleGitLabWebhook: get configuration failed")
+	}
+	conn, ok := c.(*schema.GitLabConnection)
+	if !ok {
+		return errors.Newf("expected GitLab connection, got %T", c)
+	}
+
+	resp, err := enqueueRepoUpdateForExternalRepo(ctx, g.Repos, extsvc.TypeGitLab, conn.Url, strconv.Itoa(event.Project.ID))
+	if err != nil {
+		return errors.Wrap(err, "handleGitLabWebhook: EnqueueRepoUpdate failed")
+	}
+
+	g.logger.Info("successfully updated", log.String("name", resp.Name))
+	return nil
+}
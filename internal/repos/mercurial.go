@@ -0,0 +1,109 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A MercurialSource yields repositories from a single Mercurial connection
+// configured in Sourcegraph via the external services configuration. The
+// repositories are cloned by gitserver, which converts them to Git.
+type MercurialSource struct {
+	svc  *types.ExternalService
+	conn *schema.MercurialConnection
+}
+
+// NewMercurialSource returns a new MercurialSource from the given external service.
+func NewMercurialSource(ctx context.Context, svc *types.ExternalService) (*MercurialSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.MercurialConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+	return &MercurialSource{svc: svc, conn: &c}, nil
+}
+
+// ListRepos returns all Mercurial repositories listed in the connection.
+func (s MercurialSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	base, err := url.Parse(s.conn.Url)
+	if err != nil {
+		results <- SourceResult{Source: s, Err: err}
+		return
+	}
+
+	urn := s.svc.URN()
+	for _, repo := range s.conn.Repos {
+		r, err := s.makeRepo(urn, base, repo)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+		results <- SourceResult{Source: s, Repo: r}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s MercurialSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func (s MercurialSource) makeRepo(urn string, base *url.URL, relativePath string) (*types.Repo, error) {
+	cloneURL, err := mercurialCloneURL(base, &extsvc.MercurialRepoMetadata{RelativePath: relativePath})
+	if err != nil {
+		return nil, err
+	}
+
+	// The service ID and repository name must not contain the credentials
+	// which may be part of the base URL.
+	serviceID := url.URL{Scheme: base.Scheme, Host: base.Host, Path: base.Path}
+	baseName := (&url.URL{Host: base.Host, Path: base.Path}).String()
+	name := reposource.OtherRepoName(s.conn.RepositoryPathPattern, baseName, relativePath)
+	uri := reposource.OtherRepoName(reposource.DefaultRepositoryPathPattern, baseName, relativePath)
+
+	return &types.Repo{
+		Name: api.RepoName(name),
+		URI:  uri,
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          strings.TrimSuffix(strings.TrimPrefix(relativePath, "/"), "/"),
+			ServiceType: extsvc.TypeMercurial,
+			ServiceID:   serviceID.String(),
+		},
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: cloneURL,
+			},
+		},
+		Metadata: &extsvc.MercurialRepoMetadata{
+			RelativePath: relativePath,
+		},
+	}, nil
+}
+
+// mercurialCloneURL returns the URL gitserver clones the repository from,
+// which includes the credentials of base.
+func mercurialCloneURL(base *url.URL, m *extsvc.MercurialRepoMetadata) (string, error) {
+	// Resolve the path relative to the base path, even if the base URL has
+	// no trailing slash.
+	dir := *base
+	if !strings.HasSuffix(dir.Path, "/") {
+		dir.Path += "/"
+	}
+	u, err := dir.Parse(strings.TrimPrefix(m.RelativePath, "/"))
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
@@ -63,6 +63,10 @@ func NewSource(ctx context.Context, logger log.Logger, db database.DB, svc *type
 		return NewAWSCodeCommitSource(ctx, svc, cf)
 	case extsvc.KindPerforce:
 		return NewPerforceSource(ctx, svc)
+	case extsvc.KindMercurial:
+		return NewMercurialSource(ctx, svc)
+	case extsvc.KindSubversion:
+		return NewSubversionSource(ctx, svc)
 	case extsvc.KindGoPackages:
 		return NewGoPackagesSource(ctx, svc, cf)
 	case extsvc.KindJVMPackages:
@@ -70,6 +74,10 @@ func NewSource(ctx context.Context, logger log.Logger, db database.DB, svc *type
 		return NewJVMPackagesSource(ctx, svc)
 	case extsvc.KindPagure:
 		return NewPagureSource(ctx, svc, cf)
+	case extsvc.KindGitea:
+		return NewGiteaSource(ctx, svc, cf)
+	case extsvc.KindAzureDevOps:
+		return NewAzureDevOpsSource(ctx, svc, cf)
 	case extsvc.KindNpmPackages:
 		return NewNpmPackagesSource(ctx, svc, cf)
 	case extsvc.KindPythonPackages:
@@ -0,0 +1,173 @@
+package repos
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A SubversionSource yields repositories from a single Subversion connection
+// configured in Sourcegraph via the external services configuration. The
+// repositories are imported into Git by gitserver.
+type SubversionSource struct {
+	svc  *types.ExternalService
+	conn *schema.SubversionConnection
+
+	// listDirs returns the directories directly below the base URL. It is
+	// used to discover repositories if none are configured.
+	listDirs func(ctx context.Context, conn *schema.SubversionConnection) ([]string, error)
+}
+
+// NewSubversionSource returns a new SubversionSource from the given external service.
+func NewSubversionSource(ctx context.Context, svc *types.ExternalService) (*SubversionSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.SubversionConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+	return &SubversionSource{svc: svc, conn: &c, listDirs: svnListDirs}, nil
+}
+
+// ListRepos returns the Subversion repositories listed in the connection, or
+// the directories directly below its base URL if none are listed.
+func (s SubversionSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	base, err := url.Parse(s.conn.Url)
+	if err != nil {
+		results <- SourceResult{Source: s, Err: err}
+		return
+	}
+
+	paths := s.conn.Repos
+	if len(paths) == 0 {
+		paths, err = s.listDirs(ctx, s.conn)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrap(err, "listing repositories")}
+			return
+		}
+	}
+
+	urn := s.svc.URN()
+	for _, p := range paths {
+		r, err := s.makeRepo(urn, base, p)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+		results <- SourceResult{Source: s, Repo: r}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s SubversionSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func (s SubversionSource) makeRepo(urn string, base *url.URL, relativePath string) (*types.Repo, error) {
+	metadata := &extsvc.SubversionRepoMetadata{RelativePath: relativePath}
+	cloneURL, err := subversionCloneURL(s.conn, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceID := url.URL{Scheme: base.Scheme, Host: base.Host, Path: base.Path}
+	baseName := (&url.URL{Host: base.Host, Path: base.Path}).String()
+	name := reposource.OtherRepoName(s.conn.RepositoryPathPattern, baseName, relativePath)
+	uri := reposource.OtherRepoName(reposource.DefaultRepositoryPathPattern, baseName, relativePath)
+
+	return &types.Repo{
+		Name: api.RepoName(name),
+		URI:  uri,
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          strings.Trim(relativePath, "/"),
+			ServiceType: extsvc.TypeSubversion,
+			ServiceID:   serviceID.String(),
+		},
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: cloneURL,
+			},
+		},
+		Metadata: metadata,
+	}, nil
+}
+
+// subversionCloneURL returns the URL gitserver imports the repository from.
+// The username and password of the connection are included in the URL, like
+// for Perforce depots.
+func subversionCloneURL(conn *schema.SubversionConnection, m *extsvc.SubversionRepoMetadata) (string, error) {
+	base, err := url.Parse(conn.Url)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	u, err := base.Parse(strings.Trim(m.RelativePath, "/"))
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case conn.Password != "":
+		u.User = url.UserPassword(conn.Username, conn.Password)
+	case conn.Username != "":
+		u.User = url.User(conn.Username)
+	}
+	return u.String(), nil
+}
+
+// svnListDirs returns the directories directly below the base URL of conn
+// using the svn CLI.
+func svnListDirs(ctx context.Context, conn *schema.SubversionConnection) ([]string, error) {
+	args := []string{"list", "--xml", "--non-interactive", "--no-auth-cache"}
+	if conn.Username != "" {
+		args = append(args, "--username", conn.Username)
+	}
+	if conn.Password != "" {
+		args = append(args, "--password-from-stdin")
+	}
+	cmd := exec.CommandContext(ctx, "svn", append(args, "--", conn.Url)...)
+	cmd.Stdin = strings.NewReader(conn.Password)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "svn list failed with output %q", stderr.String())
+	}
+	return parseSVNListDirs(stdout.Bytes())
+}
+
+// parseSVNListDirs returns the names of the directories in the output of
+// svn list --xml.
+func parseSVNListDirs(out []byte) ([]string, error) {
+	var v struct {
+		Entries []struct {
+			Kind string `xml:"kind,attr"`
+			Name string `xml:"name"`
+		} `xml:"list>entry"`
+	}
+	if err := xml.Unmarshal(out, &v); err != nil {
+		return nil, errors.Wrap(err, "parsing svn list output")
+	}
+
+	var dirs []string
+	for _, e := range v.Entries {
+		if e.Kind == "dir" {
+			dirs = append(dirs, e.Name)
+		}
+	}
+	return dirs, nil
+}
@@ -0,0 +1,40 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// enqueueRepoUpdateForExternalRepo enqueues an update of the repository with
+// the given external ID on the code host at baseURL. Push events of code hosts
+// other than GitHub don't include the URL the repository name is derived from,
+// so the repository is looked up by its external spec instead.
+func enqueueRepoUpdateForExternalRepo(ctx context.Context, repos database.RepoStore, serviceType, baseURL, id string) (*protocol.RepoUpdateResponse, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing code host URL")
+	}
+
+	rs, err := repos.List(ctx, database.ReposListOptions{
+		ExternalRepos: []api.ExternalRepoSpec{{
+			ID:          id,
+			ServiceType: serviceType,
+			ServiceID:   extsvc.NormalizeBaseURL(u).String(),
+		}},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing repos")
+	}
+	if len(rs) == 0 {
+		return nil, errors.Newf("no repo found for %s repository %q", serviceType, id)
+	}
+
+	return repoupdater.DefaultClient.EnqueueRepoUpdate(ctx, rs[0].Name)
+}
@@ -9,8 +9,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/types"
@@ -163,6 +165,34 @@ func NewRepoInfo(r *types.Repo) *RepoInfo {
 			Blob:   pathAppend(href, "/src/{rev}/{path}"),
 			Commit: pathAppend(href, "/commits/{commit}"),
 		}
+	case extsvc.TypeGitea:
+		repo := r.Metadata.(*gitea.Repository)
+		if repo.HTMLURL == "" {
+			break
+		}
+
+		// Gitea redirects "/src/{rev}" to the branch, tag or commit named rev.
+		info.Links = &RepoLinks{
+			Root:   repo.HTMLURL,
+			Tree:   pathAppend(repo.HTMLURL, "/src/{rev}/{path}"),
+			Blob:   pathAppend(repo.HTMLURL, "/src/{rev}/{path}"),
+			Commit: pathAppend(repo.HTMLURL, "/commit/{commit}"),
+		}
+	case extsvc.TypeAzureDevOps:
+		repo := r.Metadata.(*azuredevops.Repository)
+		if repo.WebURL == "" {
+			break
+		}
+
+		// Azure DevOps expects the type of the version to be part of it ("GB"
+		// for branches, "GC" for commits). Revisions are mostly branch names,
+		// so we link to those.
+		info.Links = &RepoLinks{
+			Root:   repo.WebURL,
+			Tree:   repo.WebURL + "?path=/{path}&version=GB{rev}",
+			Blob:   repo.WebURL + "?path=/{path}&version=GB{rev}",
+			Commit: pathAppend(repo.WebURL, "/commit/{commit}"),
+		}
 	case extsvc.TypeAWSCodeCommit:
 		repo := r.Metadata.(*awscodecommit.Repository)
 		if repo.ARN == "" {
@@ -91,8 +91,41 @@ func AlertForCappedAndExpression() *Alert {
 	}
 }
 
+// AlertForTruncatedExclusion is returned by searches with negated structural
+// patterns when the search for a negated pattern hit its result limit, so that
+// not all of its matches were excluded. The limit is fixed and not affected by
+// count:.
+func AlertForTruncatedExclusion() *Alert {
+	return &Alert{
+		PrometheusType: "exceed_exclusion_search_limit",
+		Title:          "Too many matches to exclude",
+		Description: "A negated structural pattern matched more results than can be excluded, so some of its matches may not be excluded from the results. " +
+			"Narrow the search with repo: or file: filters.",
+	}
+}
+
+// AlertForTruncatedCompare is returned by searches with rev:base...head when
+// the search at either revision hit the result limit, so that the comparison
+// is incomplete.
+func AlertForTruncatedCompare(base, head string) *Alert {
+	return &Alert{
+		PrometheusType: "exceed_compare_search_limit",
+		Title:          "Too many matches to compare revisions",
+		Description: fmt.Sprintf("The search at %s or %s hit the result limit, so some lines may be reported as added or removed although they are matched at both revisions. "+
+			"Use count: to raise the limit, or narrow the search with repo: or file: filters.", base, head),
+	}
+}
+
 // AlertForQuery converts errors in the query to search alerts.
 func AlertForQuery(queryString string, err error) *Alert {
+	var unsupported *query.UnsupportedError
+	if errors.As(err, &unsupported) && unsupported.Macro != "" {
+		return &Alert{
+			PrometheusType: "invalid_query_macro",
+			Title:          "Unable To Expand Query Macro",
+			Description:    capFirst(unsupported.Error()) + ". Query macros are defined in the `search.macros` setting.",
+		}
+	}
 	if errors.HasType(err, &query.UnsupportedError{}) || errors.HasType(err, &query.ExpectedOperand{}) {
 		return &Alert{
 			PrometheusType: "unsupported_and_or_query",
@@ -107,6 +140,32 @@ func AlertForQuery(queryString string, err error) *Alert {
 	}
 }
 
+// AlertForExpandedMacros returns an informational alert that shows the
+// effective query after query macros from settings were expanded. It returns
+// nil if no macros were expanded.
+func AlertForExpandedMacros(macros []string, expandedQuery query.Q, patternType query.SearchType) *Alert {
+	if len(macros) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(macros))
+	for _, name := range macros {
+		names = append(names, "`@"+name+"`")
+	}
+	return &Alert{
+		PrometheusType: "expanded_query_macros",
+		Title:          "Query macros expanded",
+		Description:    fmt.Sprintf("Your query uses the macros %s defined in the `search.macros` setting.", strings.Join(names, ", ")),
+		ProposedQueries: []*QueryDescription{
+			{
+				Description: "effective query",
+				Query:       query.StringHuman(expandedQuery),
+				PatternType: patternType,
+			},
+		},
+	}
+}
+
 func AlertForTimeout(usedTime time.Duration, suggestTime time.Duration, queryString string, patternType query.SearchType) *Alert {
 	q, err := query.ParseLiteral(queryString) // Invariant: query is already validated; guard against error anyway.
 	if err != nil {
@@ -144,6 +203,42 @@ func capFirst(s string) string {
 	}, s)
 }
 
+// AlertForCostBudget is returned instead of running a search whose estimated
+// cost exceeds the budget of the user.
+func AlertForCostBudget(cost, budget int, queryString string, patternType query.SearchType) *Alert {
+	alert := &Alert{
+		PrometheusType: "over_cost_budget",
+		Title:          "Search too expensive",
+		Description: fmt.Sprintf("This search is estimated to cost %d, which exceeds your budget of %d. "+
+			"The cost grows with the number of repositories and revisions searched without an index, and with the complexity of the search pattern. "+
+			"Try narrowing the search with repo: or file: filters, searching the default branch, or using a simpler pattern.", cost, budget),
+		Priority: 5,
+	}
+
+	// Indexed searches don't count towards the cost, so suggest restricting
+	// the search to indexed repositories.
+	if q, err := query.ParseLiteral(queryString); err == nil && patternType != query.SearchTypeStructural {
+		alert.ProposedQueries = []*QueryDescription{{
+			Description: "search only indexed repositories",
+			Query:       fmt.Sprintf("index:only %s", query.OmitField(q, query.FieldIndex)),
+			PatternType: patternType,
+		}}
+	}
+	return alert
+}
+
+// AlertForQueuedSearch is returned by a search whose estimated cost exceeded
+// the budget of the user, so that it waited for other such searches to finish
+// before running.
+func AlertForQueuedSearch(cost, budget int) *Alert {
+	return &Alert{
+		PrometheusType: "queued_over_cost_budget",
+		Title:          "Search was queued",
+		Description: fmt.Sprintf("This search is estimated to cost %d, which exceeds your budget of %d, so it waited for other expensive searches to finish before running. "+
+			"Narrow the search with repo: or file: filters, search the default branch, or use a simpler pattern to run it right away.", cost, budget),
+	}
+}
+
 func AlertForStalePermissions() *Alert {
 	return &Alert{
 		PrometheusType: "no_resolved_repos__stale_permissions",
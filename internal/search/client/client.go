@@ -97,9 +97,12 @@ func (s *searchClient) Plan(
 		return sc.Query, nil
 	})
 
+	// Expand query macros like @vendored-excludes defined in settings.
+	macros := settings.SearchMacros
+
 	var plan query.Plan
 	plan, err = query.Pipeline(
-		query.Init(searchQuery, searchType),
+		query.InitWithMacros(searchQuery, searchType, macros),
 		query.With(searchContextsQueryEnabled, substituteContextsStep),
 	)
 	if err != nil {
@@ -107,10 +110,19 @@ func (s *searchClient) Plan(
 	}
 	tr.LazyPrintf("parsing done")
 
+	var expandedMacros []string
+	if len(macros) > 0 {
+		// Invariant: the query already parsed successfully above.
+		if nodes, err := query.Parse(searchQuery, searchType); err == nil {
+			expandedMacros = query.ReferencedMacros(nodes, macros)
+		}
+	}
+
 	inputs := &search.Inputs{
 		Plan:                plan,
 		Query:               plan.ToQ(),
 		OriginalQuery:       searchQuery,
+		ExpandedMacros:      expandedMacros,
 		UserSettings:        settings,
 		OnSourcegraphDotCom: sourcegraphDotComMode,
 		Features:            toFeatures(featureflag.FromContext(ctx), s.logger),
@@ -175,6 +187,8 @@ func SearchTypeFromString(patternType string) (query.SearchType, error) {
 		return query.SearchTypeLucky, nil
 	case "keyword":
 		return query.SearchTypeKeyword, nil
+	case "pcre":
+		return query.SearchTypePCRE, nil
 	default:
 		return -1, errors.Errorf("unrecognized patternType %q", patternType)
 	}
@@ -225,6 +239,8 @@ func overrideSearchType(input string, searchType query.SearchType) query.SearchT
 			searchType = query.SearchTypeLucky
 		case "keyword":
 			searchType = query.SearchTypeKeyword
+		case "pcre":
+			searchType = query.SearchTypePCRE
 		}
 	})
 	return searchType
@@ -2,6 +2,7 @@ package commit
 
 import (
 	"context"
+	"strconv"
 	"strings"
 	"time"
 
@@ -327,6 +328,19 @@ func queryParameterToPredicate(parameter query.Parameter, caseSensitive, diff bo
 		newPred = &gitprotocol.DiffModifiesFile{Expr: parameter.Value, IgnoreCase: !caseSensitive}
 	case query.FieldLang:
 		newPred = &gitprotocol.DiffModifiesFile{Expr: query.LangToFileRegexp(parameter.Value), IgnoreCase: true}
+	case query.FieldRenamed:
+		newPred = &gitprotocol.DiffRenamesFile{Expr: parameter.Value, IgnoreCase: !caseSensitive}
+	case query.FieldDeleted:
+		newPred = &gitprotocol.DiffDeletesFile{Expr: parameter.Value, IgnoreCase: !caseSensitive}
+	case query.FieldLinesAdded:
+		n, _ := strconv.Atoi(parameter.Value) // field already validated
+		newPred = &gitprotocol.DiffLinesAdded{GreaterThan: n}
+	case query.FieldLinesRemoved:
+		n, _ := strconv.Atoi(parameter.Value) // field already validated
+		newPred = &gitprotocol.DiffLinesRemoved{GreaterThan: n}
+	case query.FieldFilesChanged:
+		n, _ := strconv.Atoi(parameter.Value) // field already validated
+		newPred = &gitprotocol.DiffFilesChanged{GreaterThan: n}
 	}
 
 	if parameter.Negated && newPred != nil {
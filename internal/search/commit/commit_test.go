@@ -63,6 +63,21 @@ func TestQueryToGitQuery(t *testing.T) {
 			&protocol.MessageMatches{Expr: "message2", IgnoreCase: true},
 			&protocol.DiffModifiesFile{Expr: "file", IgnoreCase: true},
 		),
+	}, {
+		name: "diff shape nodes are converted",
+		input: query.Basic{
+			Parameters: []query.Parameter{
+				{Field: query.FieldLinesAdded, Value: "100"},
+				{Field: query.FieldFilesChanged, Value: "10", Negated: true},
+				{Field: query.FieldRenamed, Value: "renamed"},
+			},
+		},
+		diff: true,
+		output: protocol.NewAnd(
+			&protocol.DiffRenamesFile{Expr: "renamed", IgnoreCase: true},
+			&protocol.DiffLinesAdded{GreaterThan: 100},
+			protocol.NewNot(&protocol.DiffFilesChanged{GreaterThan: 10}),
+		),
 	}}
 
 	for _, tc := range cases {
@@ -0,0 +1,150 @@
+package jobutil
+
+import (
+	"context"
+	"sync"
+
+	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/limits"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+)
+
+// overCostSearches queues the searches over their cost budget on this
+// frontend instance when search.limits.overCostAction is "queue".
+var overCostSearches = newOverCostQueue()
+
+// overCostQueue runs searches over their cost budget one at a time.
+type overCostQueue struct {
+	running chan struct{}
+
+	mu      sync.Mutex
+	waiting int
+}
+
+func newOverCostQueue() *overCostQueue {
+	return &overCostQueue{running: make(chan struct{}, 1)}
+}
+
+// acquire blocks until no other search of the queue is running. It returns
+// false without blocking if size searches are already waiting. If acquire
+// returns true, release must be called once the search is done.
+func (q *overCostQueue) acquire(ctx context.Context, size int) (bool, error) {
+	q.mu.Lock()
+	if q.waiting >= size {
+		q.mu.Unlock()
+		return false, nil
+	}
+	q.waiting++
+	q.mu.Unlock()
+
+	defer func() {
+		q.mu.Lock()
+		q.waiting--
+		q.mu.Unlock()
+	}()
+
+	select {
+	case q.running <- struct{}{}:
+		return true, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+func (q *overCostQueue) release() {
+	<-q.running
+}
+
+// NewAdmissionJob creates a job which estimates the cost of running child
+// before running it. If the cost exceeds the budget of the user configured in
+// search.limits, the search is either rejected with an alert or queued until
+// no other search over its budget is running. The first page of repositories
+// resolved for the estimate is reused by the search.
+func NewAdmissionJob(inputs *search.Inputs, child job.Job) job.Job {
+	return &admissionJob{
+		inputs:   inputs,
+		child:    child,
+		estimate: estimateCost,
+		queue:    overCostSearches,
+	}
+}
+
+type admissionJob struct {
+	inputs   *search.Inputs
+	child    job.Job
+	estimate func(context.Context, job.RuntimeClients, job.Job) (Cost, job.Job, error)
+	queue    *overCostQueue
+}
+
+func (j *admissionJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer func() { finish(alert, err) }()
+
+	searchLimits := limits.SearchLimits(conf.Get())
+	budget := limits.CostBudget(searchLimits, currentUsername(ctx, clients))
+	if budget == 0 {
+		return j.child.Run(ctx, clients, stream)
+	}
+
+	cost, child, err := j.estimate(ctx, clients, j.child)
+	if err != nil {
+		// Don't fail searches because we couldn't estimate their cost.
+		clients.Logger.Warn("failed to estimate search cost", log.Error(err))
+		return j.child.Run(ctx, clients, stream)
+	}
+	if cost.Total <= budget {
+		return child.Run(ctx, clients, stream)
+	}
+
+	if searchLimits.OverCostAction != "queue" {
+		return search.AlertForCostBudget(cost.Total, budget, j.inputs.OriginalQuery, j.inputs.PatternType), nil
+	}
+
+	ok, err := j.queue.acquire(ctx, searchLimits.OverCostQueueSize)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return search.AlertForCostBudget(cost.Total, budget, j.inputs.OriginalQuery, j.inputs.PatternType), nil
+	}
+	defer j.queue.release()
+
+	alert, err = child.Run(ctx, clients, stream)
+	return search.MaxPriorityAlert(alert, search.AlertForQueuedSearch(cost.Total, budget)), err
+}
+
+// currentUsername returns the username of the user running the search, or
+// the empty string if it is run anonymously or the user cannot be found.
+func currentUsername(ctx context.Context, clients job.RuntimeClients) string {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() || clients.DB == nil {
+		return ""
+	}
+	user, err := a.User(ctx, clients.DB.Users())
+	if err != nil || user == nil {
+		return ""
+	}
+	return user.Username
+}
+
+func (j *admissionJob) Name() string {
+	return "AdmissionJob"
+}
+
+func (j *admissionJob) Fields(job.Verbosity) []otlog.Field { return nil }
+
+func (j *admissionJob) Children() []job.Describer {
+	return []job.Describer{j.child}
+}
+
+func (j *admissionJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *j
+	cp.child = job.Map(j.child, fn)
+	return &cp
+}
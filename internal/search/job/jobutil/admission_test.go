@@ -0,0 +1,173 @@
+package jobutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/repos"
+	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestAdmissionJob(t *testing.T) {
+	mockLimits := func(limits *schema.SearchLimits) {
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{SearchLimits: limits}})
+	}
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	newChild := func(ran *bool) job.Job {
+		child := mockjob.NewMockJob()
+		child.RunFunc.SetDefaultHook(func(context.Context, job.RuntimeClients, streaming.Sender) (*search.Alert, error) {
+			*ran = true
+			return nil, nil
+		})
+		return child
+	}
+
+	runQueue := func(ctx context.Context, queue *overCostQueue, cost Cost, estimateErr error) (bool, *search.Alert, error) {
+		ran := false
+		j := NewAdmissionJob(&search.Inputs{OriginalQuery: "repo:foo a.*b", PatternType: query.SearchTypeRegex}, newChild(&ran)).(*admissionJob)
+		j.queue = queue
+		j.estimate = func(_ context.Context, _ job.RuntimeClients, child job.Job) (Cost, job.Job, error) {
+			return cost, child, estimateErr
+		}
+		alert, err := j.Run(ctx, job.RuntimeClients{Logger: logtest.Scoped(t)}, streaming.NewAggregatingStream())
+		return ran, alert, err
+	}
+
+	run := func(ctx context.Context, cost Cost, estimateErr error) (bool, *search.Alert, error) {
+		return runQueue(ctx, newOverCostQueue(), cost, estimateErr)
+	}
+
+	t.Run("no budget", func(t *testing.T) {
+		mockLimits(&schema.SearchLimits{MaxCost: -1})
+		ran, alert, err := run(context.Background(), Cost{Total: 1000}, nil)
+		require.NoError(t, err)
+		require.Nil(t, alert)
+		require.True(t, ran)
+	})
+
+	t.Run("under budget", func(t *testing.T) {
+		mockLimits(&schema.SearchLimits{MaxCost: 100})
+		ran, alert, err := run(context.Background(), Cost{Total: 100}, nil)
+		require.NoError(t, err)
+		require.Nil(t, alert)
+		require.True(t, ran)
+	})
+
+	t.Run("estimate error fails open", func(t *testing.T) {
+		mockLimits(&schema.SearchLimits{MaxCost: 100})
+		ran, alert, err := run(context.Background(), Cost{}, errors.New("boom"))
+		require.NoError(t, err)
+		require.Nil(t, alert)
+		require.True(t, ran)
+	})
+
+	t.Run("over budget rejects", func(t *testing.T) {
+		mockLimits(&schema.SearchLimits{MaxCost: 100})
+		ran, alert, err := run(context.Background(), Cost{Total: 101}, nil)
+		require.NoError(t, err)
+		require.False(t, ran)
+		require.Equal(t, "over_cost_budget", alert.PrometheusType)
+		require.Contains(t, alert.Description, "cost 101")
+		require.Equal(t, "index:only repo:foo a.*b", alert.ProposedQueries[0].Query)
+	})
+
+	t.Run("estimated job runs", func(t *testing.T) {
+		mockLimits(&schema.SearchLimits{MaxCost: 100})
+		ran, resolvedRan := false, false
+		j := NewAdmissionJob(&search.Inputs{}, newChild(&ran)).(*admissionJob)
+		j.estimate = func(context.Context, job.RuntimeClients, job.Job) (Cost, job.Job, error) {
+			return Cost{Total: 10}, newChild(&resolvedRan), nil
+		}
+		_, err := j.Run(context.Background(), job.RuntimeClients{Logger: logtest.Scoped(t)}, streaming.NewAggregatingStream())
+		require.NoError(t, err)
+		require.False(t, ran)
+		require.True(t, resolvedRan)
+	})
+
+	t.Run("over budget queues", func(t *testing.T) {
+		mockLimits(&schema.SearchLimits{MaxCost: 100, OverCostAction: "queue", OverCostQueueSize: 1})
+		queue := newOverCostQueue()
+		ran, alert, err := runQueue(context.Background(), queue, Cost{Total: 101}, nil)
+		require.NoError(t, err)
+		require.True(t, ran)
+		require.Equal(t, "queued_over_cost_budget", alert.PrometheusType)
+
+		// Searches wait while another search over its budget is running.
+		ok, err := queue.acquire(context.Background(), 1)
+		require.NoError(t, err)
+		require.True(t, ok)
+		defer queue.release()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		ran, _, err = runQueue(ctx, queue, Cost{Total: 101}, nil)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.False(t, ran)
+	})
+
+	t.Run("over budget rejects when queue is full", func(t *testing.T) {
+		mockLimits(&schema.SearchLimits{MaxCost: 100, OverCostAction: "queue", OverCostQueueSize: 1})
+		queue := newOverCostQueue()
+		queue.waiting = 1
+		ran, alert, err := runQueue(context.Background(), queue, Cost{Total: 101}, nil)
+		require.NoError(t, err)
+		require.False(t, ran)
+		require.Equal(t, "over_cost_budget", alert.PrometheusType)
+	})
+}
+
+func TestAdmissionJobMissingRevisions(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{SearchLimits: &schema.SearchLimits{MaxCost: 1}}})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	gitserver.Mocks.ResolveRevision = func(spec string, _ gitserver.ResolveRevisionOptions) (api.CommitID, error) {
+		if spec == "missing" {
+			return "", &gitdomain.RevisionNotFoundError{Spec: spec}
+		}
+		return "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef", nil
+	}
+	t.Cleanup(gitserver.ResetMocks)
+
+	repoStore := database.NewMockRepoStore()
+	repoStore.ListMinimalReposFunc.SetDefaultReturn([]types.MinimalRepo{{ID: 1, Name: "foo"}, {ID: 2, Name: "bar"}}, nil)
+	db := database.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(repoStore)
+	clients := job.RuntimeClients{Logger: logtest.Scoped(t), DB: db}
+
+	pager := &repoPagerJob{
+		child: &reposPartialJob{&searcher.TextSearchJob{PatternInfo: &search.TextPatternInfo{Pattern: "a"}}},
+		repoOpts: search.RepoOptions{
+			RepoFilters: []string{"@main:missing"},
+			UseIndex:    query.No,
+		},
+	}
+
+	// The missing revision is reported by the resolved pager job instead of
+	// failing the estimate.
+	cost, resolved, err := estimateCost(context.Background(), clients, pager)
+	require.NoError(t, err)
+	require.Equal(t, 2, cost.Total)
+	require.True(t, errors.HasType(resolved.(*repoPagerJob).resolvedMissing, &repos.MissingRepoRevsError{}))
+
+	// The budget still applies to the search.
+	alert, err := NewAdmissionJob(&search.Inputs{}, pager).Run(context.Background(), clients, streaming.NewAggregatingStream())
+	require.NoError(t, err)
+	require.Equal(t, "over_cost_budget", alert.PrometheusType)
+}
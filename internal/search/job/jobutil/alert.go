@@ -69,7 +69,10 @@ func (j *alertJob) Run(ctx context.Context, clients job.RuntimeClients, stream s
 		}
 	}
 
-	return search.MaxPriorityAlert(jobAlert, observerAlert), err
+	// The expanded macros alert is purely informational, so it comes last
+	// and is only shown when there is no other alert of the same priority.
+	macrosAlert := search.AlertForExpandedMacros(j.inputs.ExpandedMacros, j.inputs.Query, j.inputs.PatternType)
+	return search.MaxPriorityAlert(jobAlert, observerAlert, macrosAlert), err
 }
 
 func (j *alertJob) Name() string {
@@ -0,0 +1,120 @@
+package jobutil
+
+import (
+	"context"
+	"sync"
+
+	otlog "github.com/opentracing/opentracing-go/log"
+	"go.uber.org/atomic"
+
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// NewRevisionCompareJob creates a job for searches with rev:base...head. It
+// runs baseJob and headJob, the same search at the base and head revision,
+// and streams a result.CompareMatch for every file whose matched lines differ
+// between them.
+//
+// Matches can only be compared once both searches are complete, so results
+// are sent at the end. Stats are forwarded as they arrive. If either search
+// hits its result limit the comparison is incomplete, which is reported with
+// an alert.
+func NewRevisionCompareJob(base, head string, baseJob, headJob job.Job) job.Job {
+	return &revisionCompareJob{
+		base:    base,
+		head:    head,
+		baseJob: baseJob,
+		headJob: headJob,
+	}
+}
+
+type revisionCompareJob struct {
+	base, head       string
+	baseJob, headJob job.Job
+}
+
+func (j *revisionCompareJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer func() { finish(alert, err) }()
+
+	var (
+		g          errors.Group
+		maxAlerter search.MaxAlerter
+		limitHit   atomic.Bool
+	)
+	run := func(child job.Job) *[]*result.FileMatch {
+		var (
+			mu      sync.Mutex
+			matches []*result.FileMatch
+		)
+		collector := streaming.StreamFunc(func(event streaming.SearchEvent) {
+			mu.Lock()
+			for _, match := range event.Results {
+				// Only file contents can be compared
+				if fm, ok := match.(*result.FileMatch); ok {
+					matches = append(matches, fm)
+				}
+			}
+			mu.Unlock()
+			if event.Stats.IsLimitHit {
+				limitHit.Store(true)
+			}
+			stream.Send(streaming.SearchEvent{Stats: event.Stats})
+		})
+		g.Go(func() error {
+			alert, err := child.Run(ctx, clients, collector)
+			maxAlerter.Add(alert)
+			return err
+		})
+		return &matches
+	}
+	baseMatches := run(j.baseJob)
+	headMatches := run(j.headJob)
+	err = g.Wait()
+
+	if limitHit.Load() {
+		maxAlerter.Add(search.AlertForTruncatedCompare(j.base, j.head))
+	}
+
+	compared := result.CompareFileMatches(j.base, j.head, *baseMatches, *headMatches)
+	if len(compared) > 0 {
+		results := make(result.Matches, 0, len(compared))
+		for _, m := range compared {
+			results = append(results, m)
+		}
+		stream.Send(streaming.SearchEvent{Results: results})
+	}
+	return maxAlerter.Alert, err
+}
+
+func (j *revisionCompareJob) Name() string {
+	return "RevisionCompareJob"
+}
+
+func (j *revisionCompareJob) Fields(v job.Verbosity) (res []otlog.Field) {
+	switch v {
+	case job.VerbosityMax:
+		fallthrough
+	case job.VerbosityBasic:
+		res = append(res,
+			otlog.String("base", j.base),
+			otlog.String("head", j.head),
+		)
+	}
+	return res
+}
+
+func (j *revisionCompareJob) Children() []job.Describer {
+	return []job.Describer{j.baseJob, j.headJob}
+}
+
+func (j *revisionCompareJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *j
+	cp.baseJob = job.Map(j.baseJob, fn)
+	cp.headJob = job.Map(j.headJob, fn)
+	return &cp
+}
@@ -0,0 +1,74 @@
+package jobutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestRevisionCompareJob(t *testing.T) {
+	repo := types.MinimalRepo{ID: 1, Name: "foo"}
+	fm := func(commit api.CommitID, line int, content string) *result.FileMatch {
+		return &result.FileMatch{
+			File: result.File{Repo: repo, Path: "a.go", CommitID: commit},
+			ChunkMatches: result.ChunkMatches{{
+				Content:      content,
+				ContentStart: result.Location{Line: line},
+				Ranges:       result.Ranges{{Start: result.Location{Line: line}, End: result.Location{Line: line, Column: len(content)}}},
+			}},
+		}
+	}
+	mockJob := func(limitHit bool, matches ...result.Match) job.Job {
+		j := mockjob.NewMockJob()
+		j.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+			s.Send(streaming.SearchEvent{
+				Results: matches,
+				Stats:   streaming.Stats{IsLimitHit: limitHit},
+			})
+			return nil, nil
+		})
+		return j
+	}
+
+	baseJob := mockJob(false, fm("b1", 1, "ioutil.ReadAll(r)"), &result.RepoMatch{Name: "foo"})
+	headJob := mockJob(false, fm("h1", 1, "ioutil.ReadAll(r)"), fm("h1", 9, "ioutil.ReadFile(p)"))
+
+	t.Run("complete", func(t *testing.T) {
+		agg := streaming.NewAggregatingStream()
+		j := NewRevisionCompareJob("main", "dev", baseJob, headJob)
+		alert, err := j.Run(context.Background(), job.RuntimeClients{}, agg)
+		require.Nil(t, alert)
+		require.NoError(t, err)
+
+		require.False(t, agg.Stats.IsLimitHit)
+		require.Equal(t, result.Matches{&result.CompareMatch{
+			Repo:       repo,
+			Path:       "a.go",
+			BaseRev:    "main",
+			HeadRev:    "dev",
+			BaseCommit: "b1",
+			HeadCommit: "h1",
+			Added:      []result.CompareLine{{LineNumber: 9, Content: "ioutil.ReadFile(p)"}},
+		}}, agg.Results)
+	})
+
+	t.Run("limit hit", func(t *testing.T) {
+		truncatedHeadJob := mockJob(true, fm("h1", 9, "ioutil.ReadFile(p)"))
+
+		agg := streaming.NewAggregatingStream()
+		j := NewRevisionCompareJob("main", "dev", baseJob, truncatedHeadJob)
+		alert, err := j.Run(context.Background(), job.RuntimeClients{}, agg)
+		require.NoError(t, err)
+		require.Equal(t, search.AlertForTruncatedCompare("main", "dev"), alert)
+		require.True(t, agg.Stats.IsLimitHit)
+	})
+}
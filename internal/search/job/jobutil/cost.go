@@ -0,0 +1,301 @@
+package jobutil
+
+import (
+	"context"
+	"regexp/syntax"
+
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/repos"
+	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
+	"github.com/sourcegraph/sourcegraph/internal/search/structural"
+	"github.com/sourcegraph/sourcegraph/internal/search/zoekt"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// structuralComplexity is the pattern complexity of a structural search.
+// Structural search runs comby over every file that may match, which is much
+// more expensive than the most complex regexp.
+const structuralComplexity = 10
+
+// Cost is an estimate of how expensive a search is for searcher. Searches
+// served by zoekt are cheap and not counted.
+type Cost struct {
+	// UnindexedRepos is the number of repositories searched by searcher.
+	UnindexedRepos int `json:"unindexedRepos"`
+
+	// UnindexedRevisions is the number of revisions searched by searcher.
+	UnindexedRevisions int `json:"unindexedRevisions"`
+
+	// PatternComplexity is the highest complexity of the patterns searched
+	// by searcher.
+	PatternComplexity int `json:"patternComplexity"`
+
+	// Total is the sum over every searcher search of the number of revisions
+	// searched times the complexity of its pattern.
+	Total int `json:"total"`
+}
+
+func (c *Cost) add(revisions int, complexity int) {
+	c.UnindexedRevisions += revisions
+	c.Total += revisions * complexity
+	if complexity > c.PatternComplexity {
+		c.PatternComplexity = complexity
+	}
+}
+
+func (c *Cost) merge(o Cost) {
+	c.UnindexedRepos += o.UnindexedRepos
+	c.UnindexedRevisions += o.UnindexedRevisions
+	c.Total += o.Total
+	if o.PatternComplexity > c.PatternComplexity {
+		c.PatternComplexity = o.PatternComplexity
+	}
+}
+
+// scaled returns c, the cost of searching n repositories, scaled to the cost
+// of searching total repositories at the same cost per repository.
+func (c Cost) scaled(n, total int) Cost {
+	if n == 0 || total <= n {
+		return c
+	}
+	return Cost{
+		UnindexedRepos:     c.UnindexedRepos * total / n,
+		UnindexedRevisions: c.UnindexedRevisions * total / n,
+		PatternComplexity:  c.PatternComplexity,
+		Total:              c.Total * total / n,
+	}
+}
+
+// EstimateCost estimates the cost of running j. It resolves the first page of
+// repositories of every search in j which may use searcher, but does not run
+// any search.
+func EstimateCost(ctx context.Context, clients job.RuntimeClients, j job.Job) (Cost, error) {
+	cost, _, err := estimateCost(ctx, clients, j)
+	return cost, err
+}
+
+// estimateCost is like EstimateCost, but also returns j with the first page
+// of repositories resolved for the estimate attached to its searches, so that
+// running it does not resolve them again.
+//
+// Only the first page of repositories is resolved, so that searches over
+// many repositories don't wait for all of them to be resolved before they
+// start streaming results. If there are more pages, the repositories left are
+// counted and assumed to cost as much per repository as the first page.
+func estimateCost(ctx context.Context, clients job.RuntimeClients, j job.Job) (Cost, job.Job, error) {
+	var (
+		cost Cost
+		errs error
+	)
+	resolved := job.Map(j, func(j job.Job) job.Job {
+		if errs != nil {
+			return j
+		}
+
+		switch v := j.(type) {
+		case *repoPagerJob:
+			complexity := unindexedComplexity(v.child)
+			if complexity == 0 {
+				return j
+			}
+			page, err := resolveFirstPage(ctx, clients, v.repoOpts, v.repoOpts.UseIndex, v.containsRefGlobs)
+			if err != nil {
+				errs = err
+				return j
+			}
+			var pageCost Cost
+			for _, repoRevs := range page.unindexed {
+				pageCost.UnindexedRepos++
+				pageCost.add(revisionCount(repoRevs), complexity)
+			}
+			cost.merge(pageCost.scaled(page.repos, page.repos+page.remaining))
+
+			cp := *v
+			cp.resolved = &page.resolvedRepos
+			cp.resolvedNext = page.next
+			cp.resolvedMissing = page.missing
+			return &cp
+
+		case *structural.SearchJob:
+			page, err := resolveFirstPage(ctx, clients, v.RepoOpts, v.UseIndex, v.ContainsRefGlobs)
+			if err != nil {
+				errs = err
+				return j
+			}
+			// Structural search uses searcher for both indexed and
+			// unindexed repositories.
+			var pageCost Cost
+			for _, repoRevs := range page.unindexed {
+				pageCost.UnindexedRepos++
+				pageCost.add(revisionCount(repoRevs), structuralComplexity)
+			}
+			if page.indexed != nil {
+				for _, repoRevs := range page.indexed.RepoRevs {
+					pageCost.UnindexedRepos++
+					pageCost.add(revisionCount(repoRevs), structuralComplexity)
+				}
+			}
+			cost.merge(pageCost.scaled(page.repos, page.repos+page.remaining))
+
+			cp := *v
+			cp.Resolved = &structural.ResolvedRepos{
+				Indexed:   page.indexed,
+				Unindexed: page.unindexed,
+			}
+			cp.ResolvedNext = page.next
+			cp.ResolvedMissing = page.missing
+			return &cp
+		}
+		return j
+	})
+	return cost, resolved, errs
+}
+
+// firstPage is the first page of repositories resolved for a search.
+type firstPage struct {
+	resolvedRepos
+
+	// repos is the number of repositories of the page.
+	repos int
+
+	// next is the cursor of the next page, or nil if there are no more
+	// pages.
+	next types.MultiCursor
+
+	// remaining is an upper bound of the number of repositories in the
+	// next pages.
+	remaining int
+
+	// missing is the non-fatal error reporting the revisions of the page
+	// which don't exist, if any.
+	missing error
+}
+
+// errFirstPageResolved stops the pagination of repositories after the first
+// page.
+var errFirstPageResolved = errors.New("first page resolved")
+
+// resolveFirstPage resolves the first page of repositories of opts like
+// repoPagerJob, and counts the repositories left to resolve. Revisions which
+// don't exist do not fail the resolution: they are reported by a
+// *repos.MissingRepoRevsError in missing, so that the search can still alert
+// on them.
+func resolveFirstPage(ctx context.Context, clients job.RuntimeClients, opts search.RepoOptions, useIndex query.YesNoOnly, containsRefGlobs bool) (firstPage, error) {
+	repoResolver := repos.NewResolver(clients.Logger, clients.DB, clients.SearcherURLs, clients.Zoekt)
+	var page repos.Resolved
+	err := repoResolver.Paginate(ctx, opts, func(p *repos.Resolved) error {
+		page = *p
+		return errFirstPageResolved
+	})
+	var missing error
+	err = errors.Ignore(err, func(err error) bool {
+		var e *repos.MissingRepoRevsError
+		if errors.As(err, &e) {
+			missing = e
+			return true
+		}
+		return errors.Is(err, errFirstPageResolved)
+	})
+	if err != nil {
+		return firstPage{}, err
+	}
+
+	indexed, unindexed, err := zoekt.PartitionRepos(
+		ctx,
+		clients.Logger,
+		page.RepoRevs,
+		clients.Zoekt,
+		search.TextRequest,
+		useIndex,
+		containsRefGlobs,
+	)
+	if err != nil {
+		return firstPage{}, err
+	}
+
+	remaining := 0
+	if page.Next != nil {
+		nextOpts := opts
+		nextOpts.Cursors = page.Next
+		remaining, err = repoResolver.Count(ctx, nextOpts)
+		if err != nil {
+			return firstPage{}, err
+		}
+	}
+
+	return firstPage{
+		resolvedRepos: resolvedRepos{indexed, unindexed},
+		repos:         len(page.RepoRevs),
+		next:          page.Next,
+		remaining:     remaining,
+		missing:       missing,
+	}, nil
+}
+
+// unindexedComplexity returns the summed pattern complexity of the searcher
+// jobs in d, or 0 if there are none.
+func unindexedComplexity(d job.Describer) int {
+	complexity := 0
+	switch v := d.(type) {
+	case *searcher.TextSearchJob:
+		complexity += patternComplexity(v.PatternInfo)
+	case *searcher.SymbolSearchJob:
+		complexity += patternComplexity(v.PatternInfo)
+	}
+	for _, child := range d.Children() {
+		complexity += unindexedComplexity(child)
+	}
+	return complexity
+}
+
+// patternComplexity estimates how expensive p is to match. Literal patterns
+// have complexity 1. Regexp patterns additionally count every unbounded
+// repetition and every extra alternative, which make matching slower and
+// prevent searcher from using its literal prefilter.
+func patternComplexity(p *search.TextPatternInfo) int {
+	if p == nil {
+		return 1
+	}
+	if p.IsStructuralPat {
+		return structuralComplexity
+	}
+	if !p.IsRegExp {
+		return 1
+	}
+	re, err := syntax.Parse(p.Pattern, syntax.Perl)
+	if err != nil {
+		return 1
+	}
+
+	complexity := 1
+	var walk func(*syntax.Regexp)
+	walk = func(re *syntax.Regexp) {
+		switch re.Op {
+		case syntax.OpStar, syntax.OpPlus:
+			complexity++
+		case syntax.OpRepeat:
+			if re.Max == -1 {
+				complexity++
+			}
+		case syntax.OpAlternate:
+			complexity += len(re.Sub) - 1
+		}
+		for _, sub := range re.Sub {
+			walk(sub)
+		}
+	}
+	walk(re)
+	return complexity
+}
+
+// revisionCount returns the number of revisions searched in repoRevs. An empty
+// list of revisions searches the default branch.
+func revisionCount(repoRevs *search.RepositoryRevisions) int {
+	if len(repoRevs.Revs) == 0 {
+		return 1
+	}
+	return len(repoRevs.Revs)
+}
@@ -0,0 +1,88 @@
+package jobutil
+
+import (
+	"context"
+	"testing"
+
+	mockrequire "github.com/derision-test/go-mockgen/testutil/require"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestPatternComplexity(t *testing.T) {
+	cases := []struct {
+		pattern    *search.TextPatternInfo
+		complexity int
+	}{
+		{&search.TextPatternInfo{Pattern: "foo.*bar"}, 1},
+		{&search.TextPatternInfo{Pattern: "foo", IsRegExp: true}, 1},
+		{&search.TextPatternInfo{Pattern: "foo.*bar", IsRegExp: true}, 2},
+		{&search.TextPatternInfo{Pattern: `a+b{2,}c{1,3}`, IsRegExp: true}, 3},
+		{&search.TextPatternInfo{Pattern: "(foo|bar|qux).*", IsRegExp: true}, 4},
+		{&search.TextPatternInfo{Pattern: "(", IsRegExp: true}, 1},
+		{&search.TextPatternInfo{Pattern: "foo(:[x])", IsStructuralPat: true}, structuralComplexity},
+	}
+	for _, tc := range cases {
+		t.Run(tc.pattern.Pattern, func(t *testing.T) {
+			require.Equal(t, tc.complexity, patternComplexity(tc.pattern))
+		})
+	}
+}
+
+func TestEstimateCostFirstPage(t *testing.T) {
+	gitserver.Mocks.ResolveRevision = func(string, gitserver.ResolveRevisionOptions) (api.CommitID, error) {
+		return "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef", nil
+	}
+	t.Cleanup(gitserver.ResetMocks)
+
+	// The first page has two repositories, and there are more.
+	repoStore := database.NewMockRepoStore()
+	repoStore.ListMinimalReposFunc.SetDefaultReturn([]types.MinimalRepo{
+		{ID: 3, Name: "foo", Stars: 3},
+		{ID: 2, Name: "bar", Stars: 2},
+		{ID: 1, Name: "baz", Stars: 1},
+	}, nil)
+	repoStore.CountFunc.SetDefaultReturn(6, nil)
+	db := database.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(repoStore)
+	clients := job.RuntimeClients{Logger: logtest.Scoped(t), DB: db}
+
+	pager := &repoPagerJob{
+		child: &reposPartialJob{&searcher.TextSearchJob{PatternInfo: &search.TextPatternInfo{Pattern: "a.*b", IsRegExp: true}}},
+		repoOpts: search.RepoOptions{
+			UseIndex: query.No,
+			Limit:    2,
+		},
+	}
+
+	cost, resolved, err := estimateCost(context.Background(), clients, pager)
+	require.NoError(t, err)
+
+	// Only the first page is resolved, and the repositories left are
+	// counted from its cursor.
+	mockrequire.CalledOnce(t, repoStore.ListMinimalReposFunc)
+	mockrequire.CalledOnce(t, repoStore.CountFunc)
+	require.NotEmpty(t, repoStore.CountFunc.History()[0].Arg1.Cursors)
+
+	// The 6 repositories left cost as much per repository as the 2 of the
+	// first page.
+	require.Equal(t, Cost{
+		UnindexedRepos:     8,
+		UnindexedRevisions: 8,
+		PatternComplexity:  2,
+		Total:              16,
+	}, cost)
+
+	resolvedPager := resolved.(*repoPagerJob)
+	require.Len(t, resolvedPager.resolved.unindexed, 2)
+	require.NotNil(t, resolvedPager.resolvedNext)
+}
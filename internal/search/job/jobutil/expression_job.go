@@ -2,6 +2,7 @@ package jobutil
 
 import (
 	"context"
+	"sync"
 
 	"github.com/opentracing/opentracing-go/log"
 	"go.uber.org/atomic"
@@ -99,6 +100,155 @@ func (a *AndJob) MapChildren(fn job.MapFunc) job.Job {
 	return &cp
 }
 
+// NewAndNotJob creates a job that streams the matches of child, except for
+// the matched ranges that overlap with a range matched by any of the exclude
+// jobs in the same file. Unlike negated operands of an AndJob, which exclude
+// whole files, this filters at the range level: a file is only dropped once
+// none of its ranges remain. It is used for negated structural patterns, e.g.
+// `foo(...) and not foo(nil)`.
+//
+// If an exclude job hits its result limit, some ranges may not be excluded,
+// which is reported with an alert instead of the stats of the search.
+func NewAndNotJob(child job.Job, exclude ...job.Job) job.Job {
+	if len(exclude) == 0 {
+		return child
+	}
+	return &AndNotJob{child: child, exclude: exclude}
+}
+
+type AndNotJob struct {
+	child   job.Job
+	exclude []job.Job
+}
+
+func (a *AndNotJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, stream, a)
+	defer func() { finish(alert, err) }()
+
+	var (
+		g          errors.Group
+		maxAlerter search.MaxAlerter
+		mu         sync.Mutex
+		excluded   = make(map[result.Key]result.Ranges)
+		limitHit   atomic.Bool
+	)
+
+	// We need every excluded range before we can decide which ranges of
+	// the child to keep, so the exclude jobs run to completion first.
+	for _, exclude := range a.exclude {
+		exclude := exclude
+		g.Go(func() error {
+			collectingStream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+				mu.Lock()
+				for _, match := range event.Results {
+					if fm, ok := match.(*result.FileMatch); ok {
+						for _, cm := range fm.ChunkMatches {
+							excluded[fm.Key()] = append(excluded[fm.Key()], cm.Ranges...)
+						}
+					}
+				}
+				mu.Unlock()
+
+				// The stats of the exclude jobs are not sent: their limit
+				// is internal and reported with an alert, and the child
+				// searches the same repositories.
+				if event.Stats.IsLimitHit {
+					limitHit.Store(true)
+				}
+			})
+
+			alert, err := exclude.Run(ctx, clients, collectingStream)
+			maxAlerter.Add(alert)
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return maxAlerter.Alert, err
+	}
+	if limitHit.Load() {
+		maxAlerter.Add(search.AlertForTruncatedExclusion())
+	}
+
+	filteringStream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+		filtered := event.Results[:0]
+		for _, match := range event.Results {
+			if fm, ok := match.(*result.FileMatch); ok {
+				if ranges, ok := excluded[fm.Key()]; ok && !excludeRanges(fm, ranges) {
+					continue
+				}
+			}
+			filtered = append(filtered, match)
+		}
+		event.Results = filtered
+		if len(event.Results) > 0 || !event.Stats.Zero() {
+			stream.Send(event)
+		}
+	})
+
+	alert, err = a.child.Run(ctx, clients, filteringStream)
+	maxAlerter.Add(alert)
+	return maxAlerter.Alert, err
+}
+
+// excludeRanges removes the ranges of fm that overlap with any of excluded.
+// It returns false if fm had matched ranges and none of them remain.
+func excludeRanges(fm *result.FileMatch, excluded result.Ranges) bool {
+	if len(fm.ChunkMatches) == 0 {
+		return true
+	}
+
+	overlaps := func(r result.Range) bool {
+		for _, e := range excluded {
+			if r.Start.Offset < e.End.Offset && e.Start.Offset < r.End.Offset {
+				return true
+			}
+		}
+		return false
+	}
+
+	filteredChunks := fm.ChunkMatches[:0]
+	for _, chunk := range fm.ChunkMatches {
+		filteredRanges := chunk.Ranges[:0]
+		for _, r := range chunk.Ranges {
+			if !overlaps(r) {
+				filteredRanges = append(filteredRanges, r)
+			}
+		}
+		if len(filteredRanges) == 0 {
+			continue
+		}
+		chunk.Ranges = filteredRanges
+		filteredChunks = append(filteredChunks, chunk)
+	}
+	fm.ChunkMatches = filteredChunks
+	return len(fm.ChunkMatches) > 0
+}
+
+func (a *AndNotJob) Name() string {
+	return "AndNotJob"
+}
+
+func (a *AndNotJob) Fields(job.Verbosity) []log.Field { return nil }
+
+func (a *AndNotJob) Children() []job.Describer {
+	res := make([]job.Describer, 0, len(a.exclude)+1)
+	res = append(res, a.child)
+	for i := range a.exclude {
+		res = append(res, a.exclude[i])
+	}
+	return res
+}
+
+func (a *AndNotJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *a
+	cp.child = job.Map(a.child, fn)
+	cp.exclude = make([]job.Job, len(a.exclude))
+	for i := range a.exclude {
+		cp.exclude[i] = job.Map(a.exclude[i], fn)
+	}
+	return &cp
+}
+
 // NewAndJob creates a job that will run each of its child jobs and stream
 // deduplicated matches that were streamed by at least one of the jobs.
 func NewOrJob(children ...job.Job) job.Job {
@@ -236,3 +236,73 @@ func TestOrJob(t *testing.T) {
 		require.Len(t, stream.Results, 1)
 	})
 }
+
+func TestAndNotJob(t *testing.T) {
+	fileMatch := func(path string, ranges ...result.Range) *result.FileMatch {
+		return &result.FileMatch{
+			File:         result.File{Path: path},
+			ChunkMatches: result.ChunkMatches{{Ranges: ranges}},
+		}
+	}
+	span := func(start, end int) result.Range {
+		return result.Range{Start: result.Location{Offset: start}, End: result.Location{Offset: end}}
+	}
+	jobWithMatches := func(matches ...result.Match) job.Job {
+		j := mockjob.NewMockJob()
+		j.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+			s.Send(streaming.SearchEvent{Results: matches})
+			return nil, nil
+		})
+		return j
+	}
+
+	child := jobWithMatches(
+		fileMatch("a", span(0, 5), span(10, 15)),
+		fileMatch("b", span(0, 5)),
+		fileMatch("c", span(0, 5)),
+	)
+	exclude := jobWithMatches(
+		fileMatch("a", span(12, 14)),
+		fileMatch("b", span(0, 5)),
+		fileMatch("c", span(5, 10)),
+	)
+
+	stream := streaming.NewAggregatingStream()
+	alert, err := NewAndNotJob(child, exclude).Run(context.Background(), job.RuntimeClients{}, stream)
+	require.NoError(t, err)
+	require.Nil(t, alert)
+
+	got := map[string]result.Ranges{}
+	for _, m := range stream.Results {
+		fm := m.(*result.FileMatch)
+		for _, cm := range fm.ChunkMatches {
+			got[fm.Path] = append(got[fm.Path], cm.Ranges...)
+		}
+	}
+	require.Equal(t, map[string]result.Ranges{
+		// The range overlapping an excluded range is removed.
+		"a": {span(0, 5)},
+		// "b" has no ranges left and is dropped.
+		// Adjacent ranges do not overlap.
+		"c": {span(0, 5)},
+	}, got)
+
+	t.Run("exclude limit hit", func(t *testing.T) {
+		truncated := mockjob.NewMockJob()
+		truncated.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+			s.Send(streaming.SearchEvent{
+				Results: result.Matches{fileMatch("a", span(12, 14))},
+				Stats:   streaming.Stats{IsLimitHit: true},
+			})
+			return nil, nil
+		})
+
+		agg := streaming.NewAggregatingStream()
+		alert, err := NewAndNotJob(jobWithMatches(), truncated).Run(context.Background(), job.RuntimeClients{}, agg)
+		require.NoError(t, err)
+		require.Equal(t, search.AlertForTruncatedExclusion(), alert)
+		// The limit of the exclude job is not reported as the limit of the
+		// search.
+		require.False(t, agg.Stats.IsLimitHit)
+	})
+}
@@ -0,0 +1,183 @@
+package jobutil
+
+import (
+	"context"
+	"strings"
+
+	"github.com/grafana/regexp"
+	otlog "github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+// NewNearFilterJob creates a filter job to post-filter results for `near:`
+// and NEAR/n.
+//
+// The child is expected to search for the and-expression in pattern, so that
+// file results contain matched ranges for each of its operands. This job
+// attributes every range to the operands it matches and only keeps ranges
+// which are within distance lines of a range of every other operand. Files
+// without such ranges, and results which are not file content matches, are
+// dropped.
+//
+// The operands are pushed down to Zoekt and searcher as the and-expression of
+// their terms, with the usual file match limit, and the distance is applied
+// to the files they return. The limit job above this job only counts the
+// results which satisfy the distance, but files dropped by the filter still
+// count towards the file match limit of the backends.
+func NewNearFilterJob(distance int, pattern query.Node, caseSensitive bool, child job.Job) job.Job {
+	var operands []query.Node
+	if op, ok := pattern.(query.Operator); ok && op.Kind == query.And {
+		operands = op.Operands
+	}
+
+	matchers := make([]*regexp.Regexp, 0, len(operands))
+	for _, operand := range operands {
+		patterns := nearOperandPatterns(operand)
+		if len(patterns) == 0 {
+			continue
+		}
+		re := "^(?:" + strings.Join(patterns, "|") + ")$"
+		if !caseSensitive {
+			re = "(?i:" + re + ")"
+		}
+		matchers = append(matchers, regexp.MustCompile(re))
+	}
+
+	return &nearFilterJob{
+		distance: distance,
+		matchers: matchers,
+		child:    child,
+	}
+}
+
+// nearOperandPatterns returns the regular expressions of the non-negated
+// patterns in node.
+func nearOperandPatterns(node query.Node) (res []string) {
+	switch v := node.(type) {
+	case query.Operator:
+		for _, operand := range v.Operands {
+			res = append(res, nearOperandPatterns(operand)...)
+		}
+	case query.Pattern:
+		if v.Negated {
+			return nil
+		}
+		if v.Annotation.Labels.IsSet(query.Literal) {
+			return []string{regexp.QuoteMeta(v.Value)}
+		}
+		return []string{v.Value}
+	}
+	return res
+}
+
+type nearFilterJob struct {
+	distance int
+
+	// Each matcher matches the full content of ranges matched by one operand
+	// of the and-expression.
+	matchers []*regexp.Regexp
+
+	child job.Job
+}
+
+func (j *nearFilterJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer func() { finish(alert, err) }()
+
+	filteredStream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+		event = j.filterEvent(event)
+		stream.Send(event)
+	})
+
+	return j.child.Run(ctx, clients, filteredStream)
+}
+
+func (j *nearFilterJob) filterEvent(event streaming.SearchEvent) streaming.SearchEvent {
+	filtered := event.Results[:0]
+	for _, res := range event.Results {
+		// Filter out any results that are not file content matches
+		if fm, ok := res.(*result.FileMatch); ok && j.filterFileMatch(fm) {
+			filtered = append(filtered, fm)
+		}
+	}
+	event.Results = filtered
+	return event
+}
+
+// filterFileMatch removes the ranges of fm which do not satisfy the proximity
+// constraint and reports whether any remain.
+func (j *nearFilterJob) filterFileMatch(fm *result.FileMatch) bool {
+	operands := make([]result.Ranges, len(j.matchers))
+	for _, chunk := range fm.ChunkMatches {
+		for i, val := range chunk.MatchedContent() {
+			for k, re := range j.matchers {
+				if re.MatchString(val) {
+					operands[k] = append(operands[k], chunk.Ranges[i])
+				}
+			}
+		}
+	}
+
+	keep := make(map[result.Range]struct{})
+	for _, ranges := range result.Near(j.distance, operands...) {
+		for _, r := range ranges {
+			keep[r] = struct{}{}
+		}
+	}
+	if len(keep) == 0 {
+		return false
+	}
+
+	filteredChunks := fm.ChunkMatches[:0]
+	for _, chunk := range fm.ChunkMatches {
+		filteredRanges := chunk.Ranges[:0]
+		for _, r := range chunk.Ranges {
+			if _, ok := keep[r]; ok {
+				filteredRanges = append(filteredRanges, r)
+			}
+		}
+		if len(filteredRanges) == 0 {
+			continue
+		}
+		chunk.Ranges = filteredRanges
+		filteredChunks = append(filteredChunks, chunk)
+	}
+	fm.ChunkMatches = filteredChunks
+	return true
+}
+
+func (j *nearFilterJob) MapChildren(f job.MapFunc) job.Job {
+	cp := *j
+	cp.child = job.Map(j.child, f)
+	return &cp
+}
+
+func (j *nearFilterJob) Children() []job.Describer {
+	return []job.Describer{j.child}
+}
+
+func (j *nearFilterJob) Fields(v job.Verbosity) (res []otlog.Field) {
+	switch v {
+	case job.VerbosityMax:
+		fallthrough
+	case job.VerbosityBasic:
+		res = append(res, otlog.Int("distance", j.distance))
+
+		operandStrings := make([]string, 0, len(j.matchers))
+		for _, re := range j.matchers {
+			operandStrings = append(operandStrings, re.String())
+		}
+		res = append(res, trace.Strings("operandPatterns", operandStrings))
+	}
+	return res
+}
+
+func (j *nearFilterJob) Name() string {
+	return "NearFilterJob"
+}
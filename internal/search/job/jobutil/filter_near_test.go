@@ -0,0 +1,143 @@
+package jobutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+)
+
+func TestNearFilterJob(t *testing.T) {
+	// cm returns a chunk match on line with a single range covering content.
+	cm := func(line int, content string) result.ChunkMatch {
+		return result.ChunkMatch{
+			Content:      content,
+			ContentStart: result.Location{Offset: line * 100, Line: line},
+			Ranges: result.Ranges{{
+				Start: result.Location{Offset: line * 100, Line: line},
+				End:   result.Location{Offset: line*100 + len(content), Line: line, Column: len(content)},
+			}},
+		}
+	}
+	fm := func(path string, cms ...result.ChunkMatch) *result.FileMatch {
+		return &result.FileMatch{File: result.File{Path: path}, ChunkMatches: cms}
+	}
+	literal := func(value string) query.Pattern {
+		return query.Pattern{Value: value, Annotation: query.Annotation{Labels: query.Literal}}
+	}
+	pattern := query.Operator{Kind: query.And, Operands: []query.Node{literal("lock()"), literal("defer unlock()")}}
+
+	cases := []struct {
+		name          string
+		distance      int
+		caseSensitive bool
+		input         result.Matches
+		want          result.Matches
+	}{{
+		name:     "keeps nearby ranges",
+		distance: 5,
+		input:    result.Matches{fm("a", cm(1, "lock()"), cm(3, "defer unlock()"), cm(20, "lock()"))},
+		want:     result.Matches{fm("a", cm(1, "lock()"), cm(3, "defer unlock()"))},
+	}, {
+		name:     "drops files without nearby ranges",
+		distance: 5,
+		input:    result.Matches{fm("a", cm(1, "lock()"), cm(10, "defer unlock()")), fm("b", cm(1, "lock()"))},
+		want:     result.Matches{},
+	}, {
+		name:     "case insensitive",
+		distance: 1,
+		input:    result.Matches{fm("a", cm(1, "LOCK()"), cm(2, "Defer Unlock()"))},
+		want:     result.Matches{fm("a", cm(1, "LOCK()"), cm(2, "Defer Unlock()"))},
+	}, {
+		name:          "case sensitive",
+		distance:      1,
+		caseSensitive: true,
+		input:         result.Matches{fm("a", cm(1, "LOCK()"), cm(2, "defer unlock()"))},
+		want:          result.Matches{},
+	}, {
+		name:     "drops non-file results",
+		distance: 1,
+		input:    result.Matches{&result.RepoMatch{Name: "foo"}},
+		want:     result.Matches{},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			childJob := mockjob.NewMockJob()
+			childJob.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+				s.Send(streaming.SearchEvent{Results: tc.input})
+				return nil, nil
+			})
+			var got result.Matches
+			streamCollector := streaming.StreamFunc(func(ev streaming.SearchEvent) {
+				got = append(got, ev.Results...)
+			})
+			j := NewNearFilterJob(tc.distance, pattern, tc.caseSensitive, childJob)
+			alert, err := j.Run(context.Background(), job.RuntimeClients{}, streamCollector)
+			require.Nil(t, alert)
+			require.NoError(t, err)
+			if len(tc.want) == 0 {
+				require.Empty(t, got)
+				return
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestNearFilterJobLimit(t *testing.T) {
+	plan, err := query.Pipeline(query.Init(`lock() NEAR/1 unlock() count:4`, query.SearchTypeStandard))
+	require.NoError(t, err)
+	b := plan[0]
+	// The backends search for the and-expression with the usual limit.
+	fileMatchLimit := computeFileMatchLimit(b, search.Streaming)
+	require.Equal(t, *b.Count(), fileMatchLimit)
+
+	cm := func(line int, content string) result.ChunkMatch {
+		return result.ChunkMatch{
+			Content:      content,
+			ContentStart: result.Location{Offset: line * 100, Line: line},
+			Ranges: result.Ranges{{
+				Start: result.Location{Offset: line * 100, Line: line},
+				End:   result.Location{Offset: line*100 + len(content), Line: line, Column: len(content)},
+			}},
+		}
+	}
+	far := func(path string) *result.FileMatch {
+		return &result.FileMatch{File: result.File{Path: path}, ChunkMatches: result.ChunkMatches{cm(1, "lock()"), cm(10, "unlock()")}}
+	}
+	near := func(path string) *result.FileMatch {
+		return &result.FileMatch{File: result.File{Path: path}, ChunkMatches: result.ChunkMatches{cm(1, "lock()"), cm(2, "unlock()")}}
+	}
+
+	// The child returns files matching the and-expression up to its file
+	// match limit, like Zoekt and searcher. Files which match the terms but
+	// not the distance don't count towards count: results.
+	childJob := mockjob.NewMockJob()
+	childJob.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+		files := result.Matches{far("a"), near("b"), far("c"), near("d"), near("e")}
+		if len(files) > fileMatchLimit {
+			files = files[:fileMatchLimit]
+		}
+		for _, file := range files {
+			s.Send(streaming.SearchEvent{Results: result.Matches{file}})
+		}
+		return nil, nil
+	})
+
+	agg := streaming.NewAggregatingStream()
+	j := NewLimitJob(*b.Count(), NewNearFilterJob(*b.Near(), b.Pattern, false, childJob))
+	_, err = j.Run(context.Background(), job.RuntimeClients{}, agg)
+	require.NoError(t, err)
+	// Every file has two matched ranges, which count as two results.
+	require.Len(t, agg.Results, 2)
+	require.Equal(t, "b", agg.Results[0].(*result.FileMatch).Path)
+	require.Equal(t, "d", agg.Results[1].(*result.FileMatch).Path)
+}
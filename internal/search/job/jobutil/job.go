@@ -54,11 +54,19 @@ func NewPlanJob(inputs *search.Inputs, plan query.Plan) (job.Job, error) {
 		}
 	}
 
+	if limits.HasCostBudget(limits.SearchLimits(conf.Get())) {
+		jobTree = NewAdmissionJob(inputs, jobTree)
+	}
+
 	return NewAlertJob(inputs, jobTree), nil
 }
 
 // NewBasicJob converts a query.Basic into its job tree representation.
 func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
+	if base, head, ok := b.RevisionCompare(); ok {
+		return newRevisionCompareJob(inputs, b, base, head)
+	}
+
 	var children []job.Job
 	addJob := func(j job.Job) {
 		children = append(children, j)
@@ -182,12 +190,24 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 		}
 	}
 
+	{ // Apply near: and NEAR/n post-filter
+		if distance := b.Near(); distance != nil {
+			basicJob = NewNearFilterJob(*distance, originalQuery.Pattern, b.IsCaseSensitive(), basicJob)
+		}
+	}
+
 	{ // Apply code ownership post-search filter
 		if includeOwners, excludeOwners := b.FileHasOwner(); inputs.Features.CodeOwnershipFilters == true && (len(includeOwners) > 0 || len(excludeOwners) > 0) {
 			basicJob = codeownershipjob.New(basicJob, includeOwners, excludeOwners)
 		}
 	}
 
+	{ // Apply ranking signals requested with sort:
+		if sortBy := b.SortBy(); sortBy != query.SortByRelevance {
+			basicJob = NewRankingJob(sortBy, basicJob)
+		}
+	}
+
 	{ // Apply selectors
 		if v, _ := b.ToParseTree().StringValue(query.FieldSelect); v != "" {
 			sp, _ := filter.SelectPathFromString(v) // Invariant: select already validated
@@ -281,6 +301,39 @@ func orderSearcherJob(j job.Job) job.Job {
 	return newJob
 }
 
+// newRevisionCompareJob creates the job for a basic query which compares the
+// revisions base and head. The query is searched for file contents at each
+// revision without select:, so that selection applies to the differences
+// rather than to the matches at either revision.
+func newRevisionCompareJob(inputs *search.Inputs, b query.Basic, base, head string) (job.Job, error) {
+	parameters := make([]query.Parameter, 0, len(b.Parameters)+1)
+	for _, p := range b.Parameters {
+		if p.Field != query.FieldSelect && p.Field != query.FieldType {
+			parameters = append(parameters, p)
+		}
+	}
+	// Invariant: type: is absent or type:file for revision compares
+	parameters = append(parameters, query.Parameter{Field: query.FieldType, Value: "file"})
+	unselected := b.MapParameters(parameters)
+
+	baseJob, err := NewBasicJob(inputs, unselected.WithRevision(base))
+	if err != nil {
+		return nil, err
+	}
+	headJob, err := NewBasicJob(inputs, unselected.WithRevision(head))
+	if err != nil {
+		return nil, err
+	}
+	compareJob := NewRevisionCompareJob(base, head, baseJob, headJob)
+
+	if v, _ := b.ToParseTree().StringValue(query.FieldSelect); v != "" {
+		sp, _ := filter.SelectPathFromString(v) // Invariant: select already validated
+		compareJob = NewSelectJob(sp, compareJob)
+	}
+	compareJob = NewLimitJob(b.ToParseTree().MaxResults(inputs.DefaultLimit()), compareJob)
+	return NewTimeoutJob(timeoutDuration(b), compareJob), nil
+}
+
 // NewFlatJob creates all jobs that are built from a query.Flat.
 func NewFlatJob(searchInputs *search.Inputs, f query.Flat) (job.Job, error) {
 	maxResults := f.MaxResults(searchInputs.DefaultLimit())
@@ -289,7 +342,7 @@ func NewFlatJob(searchInputs *search.Inputs, f query.Flat) (job.Job, error) {
 	patternInfo := toTextPatternInfo(f.ToBasic(), resultTypes, searchInputs.Protocol)
 
 	// searcher to use full deadline if timeout: set or we are streaming.
-	useFullDeadline := f.GetTimeout() != nil || f.Count() != nil || searchInputs.Protocol == search.Streaming
+	useFullDeadline := f.GetTimeout() != nil || f.Count() != nil || searchInputs.Protocol != search.Batch
 
 	repoOptions := toRepoOptions(f.ToBasic(), searchInputs.UserSettings)
 
@@ -462,6 +515,8 @@ func computeFileMatchLimit(b query.Basic, p search.Protocol) int {
 		return limits.DefaultMaxSearchResults
 	case search.Streaming:
 		return limits.DefaultMaxSearchResultsStreaming
+	case search.Export:
+		return limits.DefaultMaxSearchResultsExport
 	}
 	panic("unreachable")
 }
@@ -500,6 +555,8 @@ func count(b query.Basic, p search.Protocol) int {
 		return limits.DefaultMaxSearchResults
 	case search.Streaming:
 		return limits.DefaultMaxSearchResultsStreaming
+	case search.Export:
+		return limits.DefaultMaxSearchResultsExport
 	}
 	panic("unreachable")
 }
@@ -538,6 +595,7 @@ func toTextPatternInfo(b query.Basic, resultTypes result.Types, p search.Protoco
 		// Values dependent on pattern atom.
 		IsRegExp:        isRegexp,
 		IsStructuralPat: b.IsStructural(),
+		IsPCRE:          b.IsPCRE(),
 		IsCaseSensitive: b.IsCaseSensitive(),
 		FileMatchLimit:  int32(count),
 		Pattern:         b.PatternString(),
@@ -551,7 +609,7 @@ func toTextPatternInfo(b query.Basic, resultTypes result.Types, p search.Protoco
 		Languages:                    langInclude,
 		PathPatternsAreCaseSensitive: b.IsCaseSensitive(),
 		CombyRule:                    b.FindValue(query.FieldCombyRule),
-		Index:                        b.Index(),
+		Index:                        useIndex(b),
 		Select:                       selector,
 	}
 }
@@ -562,6 +620,9 @@ func computeResultTypes(types []string, b query.Basic, searchType query.SearchTy
 	var rts result.Types
 	if searchType == query.SearchTypeStructural && !b.IsEmptyPattern() {
 		rts = result.TypeStructural
+	} else if b.IsPCRE() && len(types) == 0 {
+		// PCRE patterns only match file contents and paths.
+		rts = result.TypeFile | result.TypePath
 	} else {
 		if len(types) == 0 {
 			rts = result.TypeFile | result.TypePath | result.TypeRepo
@@ -574,6 +635,15 @@ func computeResultTypes(types []string, b query.Basic, searchType query.SearchTy
 	return rts
 }
 
+// useIndex returns the index: value of b. Only searcher evaluates PCRE
+// patterns, so queries with PCRE patterns never use the index.
+func useIndex(b query.Basic) query.YesNoOnly {
+	if b.IsPCRE() {
+		return query.No
+	}
+	return b.Index()
+}
+
 func toRepoOptions(b query.Basic, userSettings *schema.Settings) search.RepoOptions {
 	repoFilters, minusRepoFilters := b.Repositories()
 
@@ -624,8 +694,10 @@ func toRepoOptions(b query.Basic, userSettings *schema.Settings) search.RepoOpti
 		Visibility:          visibility,
 		HasFileContent:      b.RepoHasFileContent(),
 		CommitAfter:         b.RepoContainsCommitAfter(),
-		UseIndex:            b.Index(),
+		UseIndex:            useIndex(b),
 		HasKVPs:             b.RepoHasKVPs(),
+		HasSymbol:           b.RepoContainsSymbol(),
+		HasLanguage:         b.RepoHasLanguage(),
 	}
 }
 
@@ -721,7 +793,7 @@ func jobMode(b query.Basic, repoOptions search.RepoOptions, resultTypes result.T
 	isGlobalSearch := isGlobal(repoOptions) && st != query.SearchTypeStructural
 
 	hasGlobalSearchResultType := resultTypes.Has(result.TypeFile | result.TypePath | result.TypeSymbol)
-	isIndexedSearch := b.Index() != query.No
+	isIndexedSearch := useIndex(b) != query.No
 	noPattern := b.IsEmptyPattern()
 	noFile := !b.Exists(query.FieldFile)
 	noLang := !b.Exists(query.FieldLang)
@@ -747,7 +819,8 @@ func jobMode(b query.Basic, repoOptions search.RepoOptions, resultTypes result.T
 	// we'd be skipping indexed search entirely).
 	// (2) If on Sourcegraph.com, resolve repos unconditionally (we run both global search
 	// and search over resolved repos, and return results from either job).
-	runZoektOverRepos = !repoUniverseSearch || onSourcegraphDotCom
+	// (3) Never for PCRE patterns, which Zoekt cannot evaluate.
+	runZoektOverRepos = (!repoUniverseSearch || onSourcegraphDotCom) && !b.IsPCRE()
 
 	return repoUniverseSearch, skipRepoSubsetSearch, runZoektOverRepos
 }
@@ -767,7 +840,23 @@ func toAndJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 	maxTryCount := 40000
 
 	operands := make([]job.Job, 0, len(queryOperands))
+	var excludeOperands []job.Job
 	for _, queryOperand := range queryOperands {
+		// Negated structural patterns are searched for like regular
+		// patterns, and their matches are then removed from the results
+		// of the other operands at the range level. Their search has the
+		// same fixed limit as the other operands, since all of its
+		// matches are kept until the other operands complete.
+		if p, ok := queryOperand.(query.Pattern); ok && p.Negated && p.Annotation.Labels.IsSet(query.Structural) {
+			p.Negated = false
+			operand, err := toPatternExpressionJob(inputs, b.MapPattern(p))
+			if err != nil {
+				return nil, err
+			}
+			excludeOperands = append(excludeOperands, NewLimitJob(maxTryCount, operand))
+			continue
+		}
+
 		operand, err := toPatternExpressionJob(inputs, b.MapPattern(queryOperand))
 		if err != nil {
 			return nil, err
@@ -775,7 +864,7 @@ func toAndJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 		operands = append(operands, NewLimitJob(maxTryCount, operand))
 	}
 
-	return NewAndJob(operands...), nil
+	return NewAndNotJob(NewAndJob(operands...), excludeOperands...), nil
 }
 
 // toOrJob creates a new job from a basic query whose pattern is an Or operator at the top level
@@ -872,6 +961,12 @@ func isGlobal(op search.RepoOptions) bool {
 		return false
 	}
 
+	// repo:has.language() depends on language statistics computed from the
+	// repository inventory, which Zoekt does not know about.
+	if len(op.HasLanguage) > 0 {
+		return false
+	}
+
 	// There should be no cursors when calling this, but if there are that
 	// means we're already paginating. Cursors should probably not live on this
 	// struct since they are an implementation detail of pagination.
@@ -890,6 +985,7 @@ func isGlobal(op search.RepoOptions) bool {
 	// - MinusRepoFilters
 	// - CaseSensitiveRepoFilters
 	// - HasFileContent
+	// - HasSymbol
 	// - Visibility
 	// - Limit
 	// - ForkSet
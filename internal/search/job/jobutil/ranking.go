@@ -0,0 +1,233 @@
+package jobutil
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/hmarr/codeowners"
+	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/codeownership"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/lib/group"
+)
+
+// rankingWindow is the number of file matches buffered and reordered at a
+// time by a ranking job.
+const rankingWindow = 500
+
+// NewRankingJob creates a job that reorders the file matches streamed by child
+// according to sortBy. To keep streaming, matches are buffered and reordered in
+// windows of up to rankingWindow file matches. Other results and stats are
+// streamed as they arrive.
+func NewRankingJob(sortBy query.SortBy, child job.Job) job.Job {
+	return &rankingJob{
+		sortBy: sortBy,
+		window: rankingWindow,
+		child:  child,
+	}
+}
+
+type rankingJob struct {
+	sortBy query.SortBy
+	window int
+	child  job.Job
+}
+
+func (j *rankingJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer func() { finish(alert, err) }()
+
+	var (
+		mu      sync.Mutex
+		pending []scoredFileMatch
+	)
+
+	scorer := j.newScorer(ctx, clients)
+	send := func(window []scoredFileMatch) {
+		if len(window) == 0 {
+			return
+		}
+		ranked := rankFileMatches(window)
+		results := make(result.Matches, 0, len(ranked))
+		for _, fm := range ranked {
+			results = append(results, fm)
+		}
+		stream.Send(streaming.SearchEvent{Results: results})
+	}
+
+	rankingStream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+		var fms []*result.FileMatch
+		passthrough := event.Results[:0]
+		for _, match := range event.Results {
+			if fm, ok := match.(*result.FileMatch); ok {
+				fms = append(fms, fm)
+			} else {
+				passthrough = append(passthrough, match)
+			}
+		}
+		event.Results = passthrough
+		stream.Send(event)
+
+		// Scoring can be slow, so it happens before taking the lock to not
+		// block other senders.
+		scored := scoreFileMatches(ctx, clients.Logger, scorer, fms)
+
+		var window []scoredFileMatch
+		mu.Lock()
+		pending = append(pending, scored...)
+		if len(pending) >= j.window {
+			window, pending = pending, nil
+		}
+		mu.Unlock()
+
+		send(window)
+	})
+
+	alert, err = j.child.Run(ctx, clients, rankingStream)
+
+	mu.Lock()
+	window := pending
+	pending = nil
+	mu.Unlock()
+	send(window)
+
+	return alert, err
+}
+
+// fileScorer returns the score of a file match. Matches with higher scores
+// are ranked first.
+type fileScorer func(context.Context, *result.FileMatch) (int64, error)
+
+func (j *rankingJob) newScorer(ctx context.Context, clients job.RuntimeClients) fileScorer {
+	switch j.sortBy {
+	case query.SortByRecency:
+		return func(ctx context.Context, fm *result.FileMatch) (int64, error) {
+			return lastModified(ctx, clients.Gitserver, fm)
+		}
+	case query.SortByOwnership:
+		rules := codeownership.NewRulesCache()
+		username := currentUsername(ctx, clients)
+		return func(ctx context.Context, fm *result.FileMatch) (int64, error) {
+			return ownershipScore(ctx, clients.Gitserver, &rules, username, fm)
+		}
+	default:
+		return func(context.Context, *result.FileMatch) (int64, error) {
+			return 0, nil
+		}
+	}
+}
+
+type scoredFileMatch struct {
+	fm    *result.FileMatch
+	score int64
+}
+
+// scoreFileMatches scores matches concurrently. Matches which fail to be scored
+// get a score of 0.
+func scoreFileMatches(ctx context.Context, logger log.Logger, scorer fileScorer, matches []*result.FileMatch) []scoredFileMatch {
+	scored := make([]scoredFileMatch, len(matches))
+	g := group.New().WithMaxConcurrency(16)
+	for i, fm := range matches {
+		i, fm := i, fm
+		scored[i].fm = fm
+		g.Go(func() {
+			score, err := scorer(ctx, fm)
+			if err != nil {
+				logger.Warn("failed to score file match", log.String("repo", string(fm.Repo.Name)), log.String("path", fm.Path), log.Error(err))
+				return
+			}
+			scored[i].score = score
+		})
+	}
+	g.Wait()
+	return scored
+}
+
+// rankFileMatches returns the scored matches ordered by descending score.
+// Matches with the same score keep their order.
+func rankFileMatches(scored []scoredFileMatch) []*result.FileMatch {
+	sort.SliceStable(scored, func(a, b int) bool {
+		return scored[a].score > scored[b].score
+	})
+
+	ranked := make([]*result.FileMatch, len(scored))
+	for i, s := range scored {
+		ranked[i] = s.fm
+	}
+	return ranked
+}
+
+// lastModified returns the Unix time of the last commit modifying the file of
+// fm at or before the commit it was matched at.
+func lastModified(ctx context.Context, client gitserver.Client, fm *result.FileMatch) (int64, error) {
+	commits, err := client.Commits(ctx, fm.Repo.Name, gitserver.CommitsOptions{
+		Range:            string(fm.CommitID),
+		Path:             fm.Path,
+		N:                1,
+		NoEnsureRevision: true,
+	}, authz.DefaultSubRepoPermsChecker)
+	if err != nil || len(commits) == 0 {
+		return 0, err
+	}
+	if committer := commits[0].Committer; committer != nil {
+		return committer.Date.Unix(), nil
+	}
+	return commits[0].Author.Date.Unix(), nil
+}
+
+// ownershipScore returns 2 for files owned by the user with username, 1 for
+// files with any code owner and 0 for files without code owners.
+func ownershipScore(ctx context.Context, client gitserver.Client, rules *codeownership.RulesCache, username string, fm *result.FileMatch) (int64, error) {
+	ruleset, err := rules.GetFromCacheOrFetch(ctx, client, fm.Repo.Name, fm.CommitID)
+	if err != nil {
+		return 0, err
+	}
+	owners, err := ruleset.Match(fm.Path)
+	if err != nil || len(owners) == 0 {
+		return 0, err
+	}
+	for _, owner := range owners {
+		if username != "" && owner.Type == codeowners.UsernameOwner && owner.Value == username {
+			return 2, nil
+		}
+	}
+	return 1, nil
+}
+
+func (j *rankingJob) Name() string {
+	return "RankingJob"
+}
+
+func (j *rankingJob) Fields(v job.Verbosity) (res []otlog.Field) {
+	switch v {
+	case job.VerbosityMax:
+		res = append(res,
+			otlog.Int("window", j.window),
+		)
+		fallthrough
+	case job.VerbosityBasic:
+		res = append(res,
+			otlog.String("sortBy", string(j.sortBy)),
+		)
+	}
+	return res
+}
+
+func (j *rankingJob) Children() []job.Describer {
+	return []job.Describer{j.child}
+}
+
+func (j *rankingJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *j
+	cp.child = job.Map(j.child, fn)
+	return &cp
+}
@@ -0,0 +1,144 @@
+package jobutil
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestRankingJob(t *testing.T) {
+	repo := types.MinimalRepo{ID: 1, Name: "foo"}
+	fm := func(path string) *result.FileMatch {
+		return &result.FileMatch{File: result.File{Repo: repo, Path: path, CommitID: "c1"}}
+	}
+
+	// lastModified is the day of the month each file was last modified on.
+	lastModified := map[string]int{"a.go": 3, "b.go": 1, "c.go": 5, "d.go": 4}
+
+	gs := gitserver.NewMockClient()
+	gs.CommitsFunc.SetDefaultHook(func(_ context.Context, _ api.RepoName, opts gitserver.CommitsOptions, _ authz.SubRepoPermissionChecker) ([]*gitdomain.Commit, error) {
+		date := time.Date(2022, 1, lastModified[opts.Path], 0, 0, 0, 0, time.UTC)
+		return []*gitdomain.Commit{{Committer: &gitdomain.Signature{Date: date}}}, nil
+	})
+	gs.ReadFileFunc.SetDefaultHook(func(_ context.Context, _ api.RepoName, _ api.CommitID, name string, _ authz.SubRepoPermissionChecker) ([]byte, error) {
+		if name == "CODEOWNERS" {
+			return []byte("c.go @alice\nd.go @bob\n"), nil
+		}
+		return nil, nil
+	})
+
+	// run streams the given events from a mock child through a ranking job
+	// with window, and returns the results in the order they were sent.
+	run := func(sortBy query.SortBy, window int, events ...streaming.SearchEvent) []string {
+		child := mockjob.NewMockJob()
+		child.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+			for _, event := range events {
+				s.Send(event)
+			}
+			return nil, nil
+		})
+
+		j := NewRankingJob(sortBy, child).(*rankingJob)
+		j.window = window
+
+		var got []string
+		stream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+			for _, m := range event.Results {
+				switch v := m.(type) {
+				case *result.FileMatch:
+					got = append(got, v.Path)
+				case *result.RepoMatch:
+					got = append(got, "repo:"+string(v.Name))
+				}
+			}
+		})
+		_, err := j.Run(context.Background(), job.RuntimeClients{Logger: logtest.Scoped(t), Gitserver: gs}, stream)
+		require.NoError(t, err)
+		return got
+	}
+
+	t.Run("recency", func(t *testing.T) {
+		got := run(query.SortByRecency, 10,
+			streaming.SearchEvent{Results: result.Matches{fm("a.go"), fm("b.go"), &result.RepoMatch{Name: "foo"}}},
+			streaming.SearchEvent{Results: result.Matches{fm("c.go"), fm("d.go")}},
+		)
+		require.Equal(t, []string{"repo:foo", "c.go", "d.go", "a.go", "b.go"}, got)
+	})
+
+	t.Run("recency within window", func(t *testing.T) {
+		// The first window is sent as soon as it is full, so c.go and d.go
+		// are only reordered with each other.
+		got := run(query.SortByRecency, 2,
+			streaming.SearchEvent{Results: result.Matches{fm("b.go"), fm("a.go")}},
+			streaming.SearchEvent{Results: result.Matches{fm("d.go"), fm("c.go")}},
+		)
+		require.Equal(t, []string{"a.go", "b.go", "c.go", "d.go"}, got)
+	})
+
+	t.Run("concurrent senders", func(t *testing.T) {
+		// b.go is sent while a.go is being scored, and scoring a.go blocks
+		// until b.go has been sent. This deadlocks if senders are serialized
+		// behind scoring.
+		scoringA, sentB := make(chan struct{}), make(chan struct{})
+		gs := gitserver.NewMockClient()
+		gs.CommitsFunc.SetDefaultHook(func(ctx context.Context, _ api.RepoName, opts gitserver.CommitsOptions, _ authz.SubRepoPermissionChecker) ([]*gitdomain.Commit, error) {
+			if opts.Path == "a.go" {
+				close(scoringA)
+				<-sentB
+			}
+			date := time.Date(2022, 1, lastModified[opts.Path], 0, 0, 0, 0, time.UTC)
+			return []*gitdomain.Commit{{Committer: &gitdomain.Signature{Date: date}}}, nil
+		})
+
+		child := mockjob.NewMockJob()
+		child.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				s.Send(streaming.SearchEvent{Results: result.Matches{fm("a.go")}})
+			}()
+			go func() {
+				defer wg.Done()
+				<-scoringA
+				s.Send(streaming.SearchEvent{Results: result.Matches{fm("b.go")}})
+				close(sentB)
+			}()
+			wg.Wait()
+			return nil, nil
+		})
+
+		j := NewRankingJob(query.SortByRecency, child).(*rankingJob)
+		j.window = 1
+
+		agg := streaming.NewAggregatingStream()
+		_, err := j.Run(context.Background(), job.RuntimeClients{Logger: logtest.Scoped(t), Gitserver: gs}, agg)
+		require.NoError(t, err)
+		require.Len(t, agg.Results, 2)
+	})
+
+	t.Run("ownership", func(t *testing.T) {
+		// Searches are anonymous, so no file is owned by the searching user.
+		// Owned files are ranked first and otherwise keep their order.
+		got := run(query.SortByOwnership, 10,
+			streaming.SearchEvent{Results: result.Matches{fm("a.go"), fm("d.go"), fm("b.go"), fm("c.go")}},
+		)
+		require.Equal(t, []string{"d.go", "c.go", "a.go", "b.go"}, got)
+	})
+}
@@ -12,12 +12,27 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/search/zoekt"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 type repoPagerJob struct {
 	repoOpts         search.RepoOptions
 	containsRefGlobs bool                          // whether to include repositories with refs
 	child            job.PartialJob[resolvedRepos] // child job tree that need populating a repos field to run
+
+	// resolved is the first page of repositories to search if it was
+	// resolved in advance by the admission job. If nil, the repositories of
+	// repoOpts are resolved when the job runs.
+	resolved *resolvedRepos
+
+	// resolvedNext is the cursor of the page after resolved, or nil if
+	// resolved is the last page.
+	resolvedNext types.MultiCursor
+
+	// resolvedMissing is the non-fatal error reporting the revisions
+	// missing from resolved, if any.
+	resolvedMissing error
 }
 
 // resolvedRepos is the set of information to complete the partial
@@ -83,6 +98,19 @@ func (p *repoPagerJob) Run(ctx context.Context, clients job.RuntimeClients, stre
 
 	var maxAlerter search.MaxAlerter
 
+	repoOpts := p.repoOpts
+	if p.resolved != nil {
+		alert, err := p.child.Resolve(*p.resolved).Run(ctx, clients, stream)
+		maxAlerter.Add(alert)
+		if err != nil {
+			return maxAlerter.Alert, errors.Append(p.resolvedMissing, err)
+		}
+		if p.resolvedNext == nil {
+			return maxAlerter.Alert, p.resolvedMissing
+		}
+		repoOpts.Cursors = p.resolvedNext
+	}
+
 	repoResolver := repos.NewResolver(clients.Logger, clients.DB, clients.SearcherURLs, clients.Zoekt)
 	pager := func(page *repos.Resolved) error {
 		indexed, unindexed, err := zoekt.PartitionRepos(
@@ -104,7 +132,11 @@ func (p *repoPagerJob) Run(ctx context.Context, clients job.RuntimeClients, stre
 		return err
 	}
 
-	return maxAlerter.Alert, repoResolver.Paginate(ctx, p.repoOpts, pager)
+	err = repoResolver.Paginate(ctx, repoOpts, pager)
+	if p.resolvedMissing != nil {
+		return maxAlerter.Alert, errors.Append(p.resolvedMissing, err)
+	}
+	return maxAlerter.Alert, err
 }
 
 func (p *repoPagerJob) Name() string {
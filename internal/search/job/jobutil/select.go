@@ -2,16 +2,20 @@ package jobutil
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/opentracing/opentracing-go/log"
 
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/codeownership"
 	"github.com/sourcegraph/sourcegraph/internal/search/filter"
 	"github.com/sourcegraph/sourcegraph/internal/search/job"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 // NewSelectJob creates a job that transforms streamed results with
@@ -29,10 +33,119 @@ func (j *selectJob) Run(ctx context.Context, clients job.RuntimeClients, stream
 	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
 	defer func() { finish(alert, err) }()
 
+	if isSelectOwners(j.path) {
+		return j.runSelectOwners(ctx, clients, stream)
+	}
+
 	selectingStream := newSelectingStream(stream, j.path)
 	return j.child.Run(ctx, clients, selectingStream)
 }
 
+func isSelectOwners(path filter.SelectPath) bool {
+	return len(path) == 2 && path[0] == filter.File && path[1] == filter.Owners
+}
+
+// runSelectOwners runs the child job and resolves the code owners of every
+// file it matches. Owners are aggregated over all files, so they are only
+// sent once the child job is done, ordered by the number of files they own.
+// Files the user can't read because of sub-repo permissions are not counted.
+// Like other results, the files are limited by count:, so owners are only
+// counted over the files matched before the search hit its limit.
+func (j *selectJob) runSelectOwners(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (*search.Alert, error) {
+	var (
+		mu     sync.Mutex
+		errs   error
+		files  = map[result.Key]struct{}{}
+		owners = map[string]*result.OwnerMatch{}
+	)
+	rules := codeownership.NewRulesCache()
+	filePath := filter.SelectPath{filter.File}
+	checker := authz.DefaultSubRepoPermsChecker
+
+	ownersStream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+		matches, filterErr := applySubRepoFiltering(ctx, clients.Logger, checker, event.Results)
+
+		// Claim the files we haven't seen yet, so that their owners are
+		// only resolved and counted once.
+		var newFiles []*result.FileMatch
+		mu.Lock()
+		for _, match := range matches {
+			fm, ok := match.Select(filePath).(*result.FileMatch)
+			if !ok {
+				continue
+			}
+			if _, seen := files[fm.Key()]; seen {
+				continue
+			}
+			files[fm.Key()] = struct{}{}
+			newFiles = append(newFiles, fm)
+		}
+		mu.Unlock()
+
+		// Resolve owners without holding the lock, since reading CODEOWNERS
+		// files requires gitserver round-trips.
+		var (
+			fileOwners []codeownership.Owners
+			ownersErr  error
+		)
+		for _, fm := range newFiles {
+			ruleset, err := rules.GetFromCacheOrFetch(ctx, clients.Gitserver, fm.Repo.Name, fm.CommitID)
+			if err != nil {
+				ownersErr = errors.Append(ownersErr, err)
+			}
+			matched, err := ruleset.Match(fm.Path)
+			if err != nil {
+				ownersErr = errors.Append(ownersErr, err)
+			}
+			fileOwners = append(fileOwners, matched)
+		}
+
+		mu.Lock()
+		if filterErr != nil {
+			errs = errors.Append(errs, filterErr)
+		}
+		if ownersErr != nil {
+			errs = errors.Append(errs, ownersErr)
+		}
+		for _, fo := range fileOwners {
+			for _, owner := range fo {
+				om := &result.OwnerMatch{Handle: owner.Value, Type: owner.Type}
+				if existing, ok := owners[om.String()]; ok {
+					om = existing
+				} else {
+					owners[om.String()] = om
+				}
+				om.FileCount++
+			}
+		}
+		mu.Unlock()
+		stream.Send(streaming.SearchEvent{Stats: event.Stats})
+	})
+
+	alert, err := j.child.Run(ctx, clients, ownersStream)
+	if err != nil {
+		errs = errors.Append(errs, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(owners) > 0 {
+		results := make(result.Matches, 0, len(owners))
+		for _, om := range owners {
+			results = append(results, om)
+		}
+		sort.Slice(results, func(i, k int) bool {
+			a, b := results[i].(*result.OwnerMatch), results[k].(*result.OwnerMatch)
+			if a.FileCount != b.FileCount {
+				return a.FileCount > b.FileCount
+			}
+			return a.Key().Less(b.Key())
+		})
+		stream.Send(streaming.SearchEvent{Results: results})
+	}
+	return alert, errs
+}
+
 func (j *selectJob) Name() string {
 	return "SelectJob"
 }
@@ -1,14 +1,25 @@
 package jobutil
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
 	"github.com/hexops/autogold"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
 
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
 func TestWithSelect(t *testing.T) {
@@ -212,3 +223,89 @@ func TestWithSelect(t *testing.T) {
   }
 ]`).Equal(t, test("content"))
 }
+
+func TestSelectOwners(t *testing.T) {
+	repo := types.MinimalRepo{ID: 1, Name: "foo"}
+	fm := func(path string) *result.FileMatch {
+		return &result.FileMatch{
+			File:         result.File{Repo: repo, Path: path, CommitID: "c1"},
+			ChunkMatches: result.ChunkMatches{{Ranges: make(result.Ranges, 1)}},
+		}
+	}
+
+	gs := gitserver.NewMockClient()
+	gs.ReadFileFunc.SetDefaultHook(func(_ context.Context, _ api.RepoName, _ api.CommitID, name string, _ authz.SubRepoPermissionChecker) ([]byte, error) {
+		if name == "CODEOWNERS" {
+			return []byte("*.go @sourcegraph/search\n/enterprise/ alice@example.com @sourcegraph/search\n"), nil
+		}
+		return nil, nil
+	})
+
+	child := mockjob.NewMockJob()
+	child.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+		s.Send(streaming.SearchEvent{Results: result.Matches{fm("main.go"), fm("main.go"), fm("README.md")}})
+		s.Send(streaming.SearchEvent{Results: result.Matches{fm("enterprise/main.go"), &result.RepoMatch{Name: "foo"}}})
+		return nil, nil
+	})
+
+	selectPath, err := filter.SelectPathFromString("file.owners")
+	require.NoError(t, err)
+
+	agg := streaming.NewAggregatingStream()
+	_, err = NewSelectJob(selectPath, child).Run(context.Background(), job.RuntimeClients{Gitserver: gs}, agg)
+	require.NoError(t, err)
+	require.Equal(t, result.Matches{
+		&result.OwnerMatch{Handle: "sourcegraph/search", Type: result.OwnerTypeTeam, FileCount: 2},
+		&result.OwnerMatch{Handle: "alice@example.com", Type: result.OwnerTypeEmail, FileCount: 1},
+	}, agg.Results)
+}
+
+func TestSelectOwnersSubRepoPermissions(t *testing.T) {
+	repo := types.MinimalRepo{ID: 1, Name: "foo"}
+	fm := func(path string) *result.FileMatch {
+		return &result.FileMatch{
+			File:         result.File{Repo: repo, Path: path, CommitID: "c1"},
+			ChunkMatches: result.ChunkMatches{{Ranges: make(result.Ranges, 1)}},
+		}
+	}
+
+	gs := gitserver.NewMockClient()
+	gs.ReadFileFunc.SetDefaultHook(func(_ context.Context, _ api.RepoName, _ api.CommitID, name string, _ authz.SubRepoPermissionChecker) ([]byte, error) {
+		if name == "CODEOWNERS" {
+			return []byte("*.go @sourcegraph/search\n/enterprise/ alice@example.com @sourcegraph/search\n"), nil
+		}
+		return nil, nil
+	})
+
+	checker := authz.NewMockSubRepoPermissionChecker()
+	checker.EnabledFunc.SetDefaultReturn(true)
+	checker.PermissionsFunc.SetDefaultHook(func(_ context.Context, _ int32, rc authz.RepoContent) (authz.Perms, error) {
+		if rc.Path == "enterprise/main.go" {
+			return authz.None, nil
+		}
+		return authz.Read, nil
+	})
+	old := authz.DefaultSubRepoPermsChecker
+	authz.DefaultSubRepoPermsChecker = checker
+	t.Cleanup(func() { authz.DefaultSubRepoPermsChecker = old })
+
+	child := mockjob.NewMockJob()
+	child.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+		s.Send(streaming.SearchEvent{Results: result.Matches{fm("main.go"), fm("enterprise/main.go")}})
+		return nil, nil
+	})
+
+	selectPath, err := filter.SelectPathFromString("file.owners")
+	require.NoError(t, err)
+
+	// The owners of the hidden file are not counted, and the owners
+	// aggregated from the other files are not dropped by the filter job.
+	ctx := actor.WithActor(context.Background(), actor.FromUser(1))
+	agg := streaming.NewAggregatingStream()
+	clients := job.RuntimeClients{Logger: logtest.Scoped(t), Gitserver: gs}
+	_, err = NewFilterJob(NewSelectJob(selectPath, child)).Run(ctx, clients, agg)
+	require.NoError(t, err)
+	require.Equal(t, result.Matches{
+		&result.OwnerMatch{Handle: "sourcegraph/search", Type: result.OwnerTypeTeam, FileCount: 1},
+	}, agg.Results)
+}
@@ -100,6 +100,20 @@ func applySubRepoFiltering(ctx context.Context, logger log.Logger, checker authz
 				continue
 			}
 
+			if perms.Include(authz.Read) {
+				filtered = append(filtered, m)
+			}
+		case *result.CompareMatch:
+			content := authz.RepoContent{
+				Repo: mm.Repo.Name,
+				Path: mm.Path,
+			}
+			perms, err := authz.ActorPermissions(ctx, checker, a, content)
+			if err != nil {
+				errs = errors.Append(errs, err)
+				continue
+			}
+
 			if perms.Include(authz.Read) {
 				filtered = append(filtered, m)
 			}
@@ -117,6 +131,10 @@ func applySubRepoFiltering(ctx context.Context, logger log.Logger, checker authz
 		case *result.RepoMatch:
 			// Repo filtering is taking care of by our usual repo filtering logic
 			filtered = append(filtered, m)
+		case *result.OwnerMatch:
+			// Owners are only aggregated over files the user can read, see
+			// runSelectOwners.
+			filtered = append(filtered, m)
 		}
 
 	}
@@ -112,6 +112,25 @@ func TestApplySubRepoFiltering(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "drop compare match due to auth for user with sub-repo perms",
+			args: args{
+				ctxActor: actor.FromUser(userWithSubRepoPerms),
+				matches: []result.Match{
+					&result.CompareMatch{
+						Path: unauthorizedFileName,
+					},
+					&result.CompareMatch{
+						Path: "random-name.md",
+					},
+				},
+			},
+			wantMatches: []result.Match{
+				&result.CompareMatch{
+					Path: "random-name.md",
+				},
+			},
+		},
 		{
 			name: "drop match due to auth for user with sub-repo perms and error",
 			args: args{
@@ -156,6 +175,18 @@ func TestApplySubRepoFiltering(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "owner matches should be ignored",
+			args: args{
+				ctxActor: actor.FromUser(userWithSubRepoPerms),
+				matches: []result.Match{
+					&result.OwnerMatch{Handle: "alice", Type: result.OwnerTypeUsername},
+				},
+			},
+			wantMatches: []result.Match{
+				&result.OwnerMatch{Handle: "alice", Type: result.OwnerTypeUsername},
+			},
+		},
 		{
 			name: "should filter commit matches where the user doesn't have access to any file in the ModifiedFiles",
 			args: args{
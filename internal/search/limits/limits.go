@@ -11,6 +11,10 @@ import (
 const (
 	DefaultMaxSearchResults          = 30
 	DefaultMaxSearchResultsStreaming = 500
+	// DefaultMaxSearchResultsExport is the default limit of searches which
+	// export their results, which are meant to be processed rather than
+	// displayed.
+	DefaultMaxSearchResultsExport = 10000
 
 	// The default timeout to use for queries.
 	DefaultTimeout = 20 * time.Second
@@ -39,6 +43,34 @@ func SearchLimits(c *conf.Unified) schema.SearchLimits {
 	withDefault(&limits.CommitDiffMaxRepos, 50)
 	withDefault(&limits.CommitDiffWithTimeFilterMaxRepos, 10000)
 	withDefault(&limits.MaxTimeoutSeconds, 60)
+	withDefault(&limits.OverCostQueueSize, 10)
 
 	return limits
 }
+
+// CostBudget returns the maximum estimated cost of a search by the user with
+// username, or zero if their searches are not limited by cost. username is
+// empty for anonymous users, who always get the site-wide budget.
+func CostBudget(limits schema.SearchLimits, username string) int {
+	budget := limits.MaxCost
+	if userBudget, ok := limits.UserMaxCost[username]; ok && username != "" {
+		budget = userBudget
+	}
+	if budget < 0 {
+		return 0
+	}
+	return budget
+}
+
+// HasCostBudget returns whether any searches are limited by cost.
+func HasCostBudget(limits schema.SearchLimits) bool {
+	if limits.MaxCost > 0 {
+		return true
+	}
+	for _, budget := range limits.UserMaxCost {
+		if budget > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package query
+
+import (
+	"strings"
+)
+
+// revisionCompareSeparator separates the base and head of a revision compare,
+// like rev:main...my-branch.
+const revisionCompareSeparator = "..."
+
+// ParseRevisionCompare parses a revision of the form base...head. Searches at
+// such a revision report the matches which differ between base and head,
+// instead of the matches at a single revision. Lists of revisions and ref
+// globs are never revision compares.
+func ParseRevisionCompare(rev string) (base, head string, ok bool) {
+	if strings.ContainsRune(rev, ':') || strings.HasPrefix(rev, "*") {
+		return "", "", false
+	}
+	base, head, ok = strings.Cut(rev, revisionCompareSeparator)
+	if !ok || base == "" || head == "" || strings.Contains(head, revisionCompareSeparator) {
+		return "", "", false
+	}
+	return base, head, true
+}
+
+// revisions returns the revisions specified by rev: and repo:foo@rev.
+func revisions(nodes []Node) (revs []string) {
+	VisitParameter(nodes, func(field, value string, negated bool, _ Annotation) {
+		switch field {
+		case FieldRev:
+			revs = append(revs, value)
+		case FieldRepo:
+			if _, rev, ok := strings.Cut(value, "@"); ok && !negated {
+				revs = append(revs, rev)
+			}
+		}
+	})
+	return revs
+}
+
+// RevisionCompare returns the base and head revision if the query compares
+// the matches at two revisions with rev:base...head or repo:foo@base...head.
+func (p Parameters) RevisionCompare() (base, head string, ok bool) {
+	for _, rev := range revisions(toNodes(p)) {
+		if base, head, ok := ParseRevisionCompare(rev); ok {
+			return base, head, true
+		}
+	}
+	return "", "", false
+}
+
+// WithRevision returns b searching rev in place of a revision compare.
+func (b Basic) WithRevision(rev string) Basic {
+	nodes := MapParameter(toNodes(b.Parameters), func(field, value string, negated bool, annotation Annotation) Node {
+		switch field {
+		case FieldRev:
+			if _, _, ok := ParseRevisionCompare(value); ok {
+				value = rev
+			}
+		case FieldRepo:
+			if repo, repoRev, ok := strings.Cut(value, "@"); ok && !negated {
+				if _, _, ok := ParseRevisionCompare(repoRev); ok {
+					value = repo + "@" + rev
+				}
+			}
+		}
+		return Parameter{Field: field, Value: value, Negated: negated, Annotation: annotation}
+	})
+	return b.MapParameters(toParameters(nodes))
+}
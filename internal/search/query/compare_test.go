@@ -0,0 +1,60 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRevisionCompare(t *testing.T) {
+	cases := []struct {
+		rev        string
+		base, head string
+		ok         bool
+	}{
+		{rev: "main...dev", base: "main", head: "dev", ok: true},
+		{rev: "v1.0.0...HEAD~3", base: "v1.0.0", head: "HEAD~3", ok: true},
+		{rev: "main"},
+		{rev: "main..dev"},
+		{rev: "...dev"},
+		{rev: "main..."},
+		{rev: "a...b...c"},
+		{rev: "a...b:c"},
+		{rev: "*refs/heads/...b"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.rev, func(t *testing.T) {
+			base, head, ok := ParseRevisionCompare(tc.rev)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.base, base)
+			require.Equal(t, tc.head, head)
+		})
+	}
+}
+
+func TestRevisionCompare(t *testing.T) {
+	// test returns the revision compare of input, and the repo: filters
+	// when searching its head revision.
+	test := func(input string) (string, string, bool, []string) {
+		plan, err := Pipeline(Init(input, SearchTypeLiteral))
+		require.NoError(t, err)
+		base, head, ok := plan[0].RevisionCompare()
+		include, exclude := plan[0].WithRevision(head).IncludeExcludeValues(FieldRepo)
+		return base, head, ok, append(include, exclude...)
+	}
+
+	base, head, ok, withHead := test("repo:foo rev:main...dev ioutil.")
+	require.True(t, ok)
+	require.Equal(t, "main", base)
+	require.Equal(t, "dev", head)
+	require.Equal(t, []string{"foo@dev"}, withHead)
+
+	base, head, ok, withHead = test("repo:foo@main...dev -repo:bar ioutil.")
+	require.True(t, ok)
+	require.Equal(t, "main", base)
+	require.Equal(t, "dev", head)
+	require.Equal(t, []string{"foo@dev", "bar"}, withHead)
+
+	_, _, ok, _ = test("repo:foo@main ioutil.")
+	require.False(t, ok)
+}
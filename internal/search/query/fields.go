@@ -19,6 +19,8 @@ const (
 	FieldVisibility         = "visibility"
 	FieldRev                = "rev"
 	FieldContext            = "context"
+	FieldNear               = "near" // Patterns of an and-expression must match within this many lines of each other
+	FieldSort               = "sort" // Reorders file results by recency or ownership, see SortBy
 
 	// For diff and commit search only:
 	FieldBefore    = "before"
@@ -27,6 +29,13 @@ const (
 	FieldCommitter = "committer"
 	FieldMessage   = "message"
 
+	// For diff and commit search only, matching the shape of a change:
+	FieldLinesAdded   = "linesadded"   // Commits adding more than this many lines
+	FieldLinesRemoved = "linesremoved" // Commits removing more than this many lines
+	FieldFilesChanged = "fileschanged" // Commits changing more than this many files
+	FieldRenamed      = "renamed"      // Commits renaming a file from or to a matching path
+	FieldDeleted      = "deleted"      // Commits deleting a file with a matching path
+
 	// Temporary experimental fields:
 	FieldIndex     = "index"
 	FieldCount     = "count" // Searches that specify `count:` will fetch at least that number of results, or the full result set
@@ -64,6 +73,11 @@ var allFields = map[string]struct{}{
 	FieldMessage:            empty,
 	"m":                     empty,
 	"msg":                   empty,
+	FieldLinesAdded:         empty,
+	FieldLinesRemoved:       empty,
+	FieldFilesChanged:       empty,
+	FieldRenamed:            empty,
+	FieldDeleted:            empty,
 	FieldIndex:              empty,
 	FieldCount:              empty,
 	FieldTimeout:            empty,
@@ -71,6 +85,8 @@ var allFields = map[string]struct{}{
 	FieldRev:                empty,
 	"revision":              empty,
 	FieldSelect:             empty,
+	FieldNear:               empty,
+	FieldSort:               empty,
 }
 
 var aliases = map[string]string{
@@ -19,6 +19,9 @@ const (
 	// than canonical form (r: instead of repo:)
 	IsAlias
 	Standard
+	// PCRE flags regexp patterns with backtracking (PCRE-like) syntax, which
+	// are only evaluated by searcher.
+	PCRE
 )
 
 var allLabels = map[labels]string{
@@ -32,6 +35,7 @@ var allLabels = map[labels]string{
 	Structural:                "Structural",
 	IsPredicate:               "IsPredicate",
 	IsAlias:                   "IsAlias",
+	PCRE:                      "PCRE",
 }
 
 func (l *labels) IsSet(label labels) bool {
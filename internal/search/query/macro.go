@@ -0,0 +1,168 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+)
+
+// macroReference matches a search pattern that refers to a query macro, like
+// @vendored-excludes.
+var macroReference = lazyregexp.New(`^@([A-Za-z0-9_][A-Za-z0-9_.-]*)$`)
+
+// maxMacroDepth bounds how deeply macros may refer to other macros.
+const maxMacroDepth = 8
+
+// MacroName returns the name of the macro that a pattern value refers to, and
+// whether the value is a macro reference at all.
+func MacroName(value string) (string, bool) {
+	match := macroReference.FindStringSubmatch(value)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// ExpandMacros substitutes unquoted patterns of the form @name for the query
+// fragment that macros defines for name. Fragments are parsed with the given
+// search type and may themselves refer to other macros. Patterns that look
+// like macro references but have no definition are left untouched, so that
+// searching for, e.g., Java annotations like @Override keeps working.
+//
+// Macros that directly or indirectly refer to themselves are rejected with an
+// UnsupportedError.
+func ExpandMacros(macros map[string]string, searchType SearchType) step {
+	return func(nodes []Node) ([]Node, error) {
+		if len(macros) == 0 {
+			return nodes, nil
+		}
+		return expandMacros(nodes, macros, searchType, nil)
+	}
+}
+
+// ParseWithMacros is Parse, but expands references to the given query macros
+// in the parse tree. Callers that parse user-written queries, which may refer
+// to the user's macros, should use it instead of Parse.
+func ParseWithMacros(in string, searchType SearchType, macros map[string]string) ([]Node, error) {
+	nodes, err := Parse(in, searchType)
+	if err != nil {
+		return nil, err
+	}
+	return ExpandMacros(macros, searchType)(nodes)
+}
+
+func expandMacros(nodes []Node, macros map[string]string, searchType SearchType, stack []string) ([]Node, error) {
+	var expanded []Node
+	for _, node := range nodes {
+		switch v := node.(type) {
+		case Pattern:
+			fragment, err := expandMacro(v, macros, searchType, stack)
+			if err != nil {
+				return nil, err
+			}
+			expanded = append(expanded, fragment...)
+		case Operator:
+			var operands []Node
+			if v.Kind == Or {
+				// Keep each expanded alternative together.
+				for _, operand := range v.Operands {
+					alternative, err := expandMacros([]Node{operand}, macros, searchType, stack)
+					if err != nil {
+						return nil, err
+					}
+					operands = append(operands, NewOperator(alternative, And)...)
+				}
+			} else {
+				var err error
+				operands, err = expandMacros(v.Operands, macros, searchType, stack)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if v.Kind == Concat {
+				// Macros may bring parameters into a concatenation of
+				// patterns. Partition them out again like the parser does
+				// for queries written out in full.
+				expanded = append(expanded, partitionParameters(operands)...)
+				continue
+			}
+			expanded = append(expanded, NewOperator(operands, v.Kind)...)
+		default:
+			expanded = append(expanded, node)
+		}
+	}
+	return expanded, nil
+}
+
+// expandMacro returns the nodes that pattern expands to. These are the
+// operands of the parsed macro definition if it is a conjunction, so that
+// callers splice them into the surrounding query.
+func expandMacro(pattern Pattern, macros map[string]string, searchType SearchType, stack []string) ([]Node, error) {
+	if pattern.Annotation.Labels.IsSet(Quoted) {
+		return []Node{pattern}, nil
+	}
+	name, ok := MacroName(pattern.Value)
+	if !ok {
+		return []Node{pattern}, nil
+	}
+	definition, ok := macros[name]
+	if !ok {
+		return []Node{pattern}, nil
+	}
+
+	if pattern.Negated {
+		return nil, &UnsupportedError{Msg: fmt.Sprintf("the query macro @%s cannot be negated", name), Macro: name}
+	}
+	for i, seen := range stack {
+		if seen == name {
+			cycle := append(append([]string{}, stack[i:]...), name)
+			return nil, &UnsupportedError{
+				Msg:   fmt.Sprintf("the query macro @%s is recursive: %s", name, "@"+strings.Join(cycle, " -> @")),
+				Macro: name,
+			}
+		}
+	}
+	if len(stack) >= maxMacroDepth {
+		return nil, &UnsupportedError{Msg: fmt.Sprintf("the query macro @%s exceeds the maximum nesting depth of %d", name, maxMacroDepth), Macro: name}
+	}
+
+	fragment, err := Parse(definition, searchType)
+	if err != nil {
+		return nil, &UnsupportedError{Msg: fmt.Sprintf("the query macro @%s is invalid: %s", name, err), Macro: name}
+	}
+	fragment, err = expandMacros(fragment, macros, searchType, append(stack, name))
+	if err != nil {
+		return nil, err
+	}
+	if len(fragment) == 1 {
+		if operator, ok := fragment[0].(Operator); ok && operator.Kind == And {
+			return operator.Operands, nil
+		}
+	}
+	return fragment, nil
+}
+
+// ReferencedMacros returns the sorted names of macros defined in macros that
+// nodes refer to directly.
+func ReferencedMacros(nodes []Node, macros map[string]string) []string {
+	seen := map[string]struct{}{}
+	VisitPattern(nodes, func(value string, negated bool, annotation Annotation) {
+		if annotation.Labels.IsSet(Quoted) {
+			return
+		}
+		if name, ok := MacroName(value); ok {
+			if _, ok := macros[name]; ok {
+				seen[name] = struct{}{}
+			}
+		}
+	})
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
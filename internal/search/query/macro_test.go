@@ -0,0 +1,63 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/hexops/autogold"
+)
+
+func TestExpandMacros(t *testing.T) {
+	macros := map[string]string{
+		"vendored-excludes": "-file:vendor/ -file:node_modules/",
+		"go":                "lang:go @vendored-excludes",
+		"self":              "foo @self",
+		"ping":              "repo:a @pong",
+		"pong":              "repo:b @ping",
+	}
+
+	test := func(input string) string {
+		plan, err := Pipeline(InitWithMacros(input, SearchTypeStandard, macros))
+		if err != nil {
+			return err.Error()
+		}
+		return plan.ToQ().String()
+	}
+
+	autogold.Want("single macro", `(and "-file:vendor/" "-file:node_modules/" "foo")`).Equal(t, test("foo @vendored-excludes"))
+	autogold.Want("nested macro", `(and "lang:go" "-file:vendor/" "-file:node_modules/" "foo")`).Equal(t, test("foo @go"))
+	autogold.Want("macro before pattern", `(and "lang:go" "-file:vendor/" "-file:node_modules/" "foo")`).Equal(t, test("@go foo"))
+	autogold.Want("macro in alternative", `(or (and "lang:go" "-file:vendor/" "-file:node_modules/" "foo") "bar")`).Equal(t, test("foo @go or bar"))
+	autogold.Want("undefined macro is a pattern", `"@Override"`).Equal(t, test("@Override"))
+	autogold.Want("quoted macro is a pattern", `"\"@go\""`).Equal(t, test(`"@go"`))
+	autogold.Want("self reference", "the query macro @self is recursive: @self -> @self").Equal(t, test("@self"))
+	autogold.Want("mutual recursion", "the query macro @ping is recursive: @ping -> @pong -> @ping").Equal(t, test("@ping"))
+	autogold.Want("negated macro", "the query macro @go cannot be negated").Equal(t, test("foo NOT @go"))
+}
+
+func TestParseWithMacros(t *testing.T) {
+	macros := map[string]string{"go": "lang:go", "self": "@self"}
+
+	test := func(input string) string {
+		nodes, err := ParseWithMacros(input, SearchTypeRegex, macros)
+		if err != nil {
+			return err.Error()
+		}
+		return toString(nodes)
+	}
+
+	autogold.Want("expands macro", `(and "lang:go" "foo")`).Equal(t, test("foo @go"))
+	autogold.Want("no macros", `"foo"`).Equal(t, test("foo"))
+	autogold.Want("recursive macro", "the query macro @self is recursive: @self -> @self").Equal(t, test("@self"))
+}
+
+func TestReferencedMacros(t *testing.T) {
+	macros := map[string]string{
+		"a": "repo:a",
+		"b": "repo:b",
+	}
+	nodes, err := Parse(`@b foo @a "@a" @c`, SearchTypeStandard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	autogold.Want("referenced macros", []string{"a", "b"}).Equal(t, ReferencedMacros(nodes, macros))
+}
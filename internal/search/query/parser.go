@@ -15,7 +15,7 @@ import (
 Parser implements a parser for the following grammar:
 
 OrTerm     → AndTerm { OR AndTerm }
-AndTerm    → Term { AND Term }
+AndTerm    → Term { (AND | NEAR/n) Term }
 Term       → (OrTerm) | Parameters
 Parameters → Parameter { " " Parameter }
 */
@@ -116,6 +116,7 @@ const (
 	DQUOTE keyword = "\""
 	SLASH  keyword = "/"
 	NOT    keyword = "not"
+	NEAR   keyword = "near/"
 )
 
 func isSpace(buf []byte) bool {
@@ -233,6 +234,41 @@ func (p *parser) matchKeyword(keyword keyword) bool {
 	return strings.EqualFold(v, string(keyword))
 }
 
+// ScanNearKeyword scans a proximity keyword of the form NEAR/n at the start of
+// buf, returning n and the number of bytes scanned. The keyword must be
+// followed by whitespace.
+func ScanNearKeyword(buf []byte) (distance int, count int, ok bool) {
+	if len(buf) < len(NEAR) || !strings.EqualFold(string(buf[:len(NEAR)]), string(NEAR)) {
+		return 0, 0, false
+	}
+	count = len(NEAR)
+	for count < len(buf) && '0' <= buf[count] && buf[count] <= '9' {
+		count++
+	}
+	if count == len(NEAR) || count >= len(buf) || !isSpace(buf[count:count+1]) {
+		return 0, 0, false
+	}
+	distance, err := strconv.Atoi(string(buf[len(NEAR):count]))
+	if err != nil {
+		return 0, 0, false
+	}
+	return distance, count, true
+}
+
+// matchNearKeyword is like matchKeyword, but for the NEAR/n keyword. It
+// returns the distance n and the length of the keyword.
+func (p *parser) matchNearKeyword() (distance int, count int, ok bool) {
+	if p.pos == 0 || p.pos >= len(p.buf) || !isSpace(p.buf[p.pos-1:p.pos]) {
+		return 0, 0, false
+	}
+	return ScanNearKeyword(p.buf[p.pos:])
+}
+
+func (p *parser) matchNear() bool {
+	_, _, ok := p.matchNearKeyword()
+	return ok
+}
+
 // matchUnaryKeyword is like match but expects the keyword to be followed by whitespace.
 func (p *parser) matchUnaryKeyword(keyword keyword) bool {
 	if p.pos != 0 && !(isSpace(p.buf[p.pos-1:p.pos]) || p.buf[p.pos-1] == '(') {
@@ -334,7 +370,8 @@ func ScanBalancedPattern(buf []byte) (scanned string, count int, ok bool) {
 			lookaheadStr := string(buf[:len(v)])
 			return strings.EqualFold(lookaheadStr, v)
 		}
-		if lookahead("and ") ||
+		if _, _, ok := ScanNearKeyword(buf); ok ||
+			lookahead("and ") ||
 			lookahead("or ") ||
 			lookahead("not ") {
 			// This "pattern" contains a recognized keyword, reject it.
@@ -955,6 +992,9 @@ loop:
 		case p.matchKeyword(AND), p.matchKeyword(OR):
 			// Caller advances.
 			break loop
+		case p.matchNear():
+			// Caller advances.
+			break loop
 		case p.matchUnaryKeyword(NOT):
 			start := p.pos
 			_ = p.expect(NOT)
@@ -1071,7 +1111,7 @@ func (p *parser) parseAnd() ([]Node, error) {
 	var left []Node
 	var err error
 	switch p.leafParser {
-	case SearchTypeRegex:
+	case SearchTypeRegex, SearchTypePCRE:
 		left, err = p.parseLeaves(Regexp)
 	case SearchTypeLiteral, SearchTypeStructural:
 		left, err = p.parseLeaves(Literal)
@@ -1086,6 +1126,31 @@ func (p *parser) parseAnd() ([]Node, error) {
 	if left == nil {
 		return nil, &ExpectedOperand{Msg: fmt.Sprintf("expected operand at %d", p.pos)}
 	}
+	if distance, advance, ok := p.matchNearKeyword(); ok {
+		// A proximity expression a NEAR/n b is an and-expression whose
+		// patterns must match within n lines of each other, which we
+		// express with a near:n parameter.
+		near := Parameter{
+			Field:      FieldNear,
+			Value:      strconv.Itoa(distance),
+			Annotation: Annotation{Range: newRange(p.pos, p.pos+advance)},
+		}
+		p.pos += advance
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		// Nested or chained proximity expressions like a NEAR/2 (b NEAR/3
+		// c) would need a distance per pair of patterns, which we can't
+		// express with a single near:n parameter.
+		if isNear := func(node Node) bool {
+			p, ok := node.(Parameter)
+			return ok && p.Field == FieldNear
+		}; Exists(left, isNear) || Exists(right, isNear) {
+			return nil, errors.New("NEAR/n can't be nested or chained. Use near:n to apply a single distance to every pattern, like `lock() and unlock() and mu near:3`")
+		}
+		return NewOperator(append(append(left, right...), near), And), nil
+	}
 	if !p.expect(AND) {
 		return left, nil
 	}
@@ -470,6 +470,13 @@ func TestParse(t *testing.T) {
 		Heuristic: "Same",
 	}).Equal(t, test("(a and b and (z or q)) and (c and d) and (e and f)"))
 
+	autogold.Want("NEAR/n", value{Grammar: `(and "a" "b" "near:5")`, Heuristic: "Same"}).Equal(t, test("a NEAR/5 b"))
+	autogold.Want("NEAR/n lowercase", value{Grammar: `(and "a" "b" "near:5")`, Heuristic: "Same"}).Equal(t, test("a near/5 b"))
+	autogold.Want("NEAR/n without distance is a pattern", value{Grammar: `(concat "a" "NEAR/" "b")`, Heuristic: "Same"}).Equal(t, test("a NEAR/ b"))
+	autogold.Want("NEAR/n at start is a pattern", value{Grammar: `(concat "NEAR/5" "a")`, Heuristic: "Same"}).Equal(t, test("NEAR/5 a"))
+	autogold.Want("chained NEAR/n", value{Grammar: "NEAR/n can't be nested or chained. Use near:n to apply a single distance to every pattern, like `lock() and unlock() and mu near:3`", Heuristic: "Same"}).Equal(t, test("a NEAR/2 b NEAR/3 c"))
+	autogold.Want("nested NEAR/n", value{Grammar: "NEAR/n can't be nested or chained. Use near:n to apply a single distance to every pattern, like `lock() and unlock() and mu near:3`", Heuristic: "Same"}).Equal(t, test("a NEAR/2 (b NEAR/3 c)"))
+
 	autogold.Want("empty paren", value{Grammar: `""`, Heuristic: `"()"`}).Equal(t, test("()"))
 	autogold.Want("paren inside contiguous string", value{Grammar: `(concat "foo" "bar")`, Heuristic: `"foo()bar"`}).Equal(t, test("foo()bar"))
 	autogold.Want("paren inside contiguous string with and", value{
@@ -3,6 +3,7 @@ package query
 import (
 	"strings"
 
+	"github.com/go-enry/go-enry/v2"
 	"github.com/grafana/regexp"
 
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -32,6 +33,9 @@ var DefaultPredicateRegistry = PredicateRegistry{
 		"has.content":           func() Predicate { return &RepoContainsContentPredicate{} },
 		"contains.commit.after": func() Predicate { return &RepoContainsCommitAfterPredicate{} },
 		"has.commit.after":      func() Predicate { return &RepoContainsCommitAfterPredicate{} },
+		"contains.symbol":       func() Predicate { return &RepoContainsSymbolPredicate{} },
+		"has.symbol":            func() Predicate { return &RepoContainsSymbolPredicate{} },
+		"has.language":          func() Predicate { return &RepoHasLanguagePredicate{} },
 		"has.description":       func() Predicate { return &RepoHasDescriptionPredicate{} },
 		"has.tag":               func() Predicate { return &RepoHasTagPredicate{} },
 		"has":                   func() Predicate { return &RepoHasKVPPredicate{} },
@@ -218,6 +222,50 @@ func (f RepoContainsCommitAfterPredicate) Name() string {
 	return "contains.commit.after"
 }
 
+/* repo:contains.symbol(pattern) */
+
+type RepoContainsSymbolPredicate struct {
+	Pattern string
+}
+
+func (f *RepoContainsSymbolPredicate) ParseParams(params string) error {
+	if _, err := regexp.Compile(params); err != nil {
+		return errors.Errorf("contains.symbol argument: %w", err)
+	}
+	if params == "" {
+		return errors.Errorf("contains.symbol argument should not be empty")
+	}
+	f.Pattern = params
+	return nil
+}
+
+func (f *RepoContainsSymbolPredicate) Field() string { return FieldRepo }
+func (f *RepoContainsSymbolPredicate) Name() string  { return "contains.symbol" }
+
+/* repo:has.language(name) */
+
+// RepoHasLanguagePredicate represents the `repo:has.language()` predicate,
+// which filters to repos whose dominant language (by bytes of code) is the
+// given language.
+type RepoHasLanguagePredicate struct {
+	Language string
+}
+
+func (f *RepoHasLanguagePredicate) ParseParams(params string) error {
+	if params == "" {
+		return errors.Errorf("has.language argument should not be empty")
+	}
+	lang, ok := enry.GetLanguageByAlias(params)
+	if !ok {
+		return errors.Errorf("has.language argument: unknown language %q", params)
+	}
+	f.Language = lang
+	return nil
+}
+
+func (f *RepoHasLanguagePredicate) Field() string { return FieldRepo }
+func (f *RepoHasLanguagePredicate) Name() string  { return "has.language" }
+
 /* repo:has.description(...) */
 
 type RepoHasDescriptionPredicate struct {
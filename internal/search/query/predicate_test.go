@@ -168,3 +168,92 @@ func TestFileHasOwnerPredicate(t *testing.T) {
 		}
 	})
 }
+
+func TestRepoContainsSymbolPredicate(t *testing.T) {
+	t.Run("ParseParams", func(t *testing.T) {
+		type test struct {
+			name     string
+			params   string
+			expected *RepoContainsSymbolPredicate
+		}
+
+		valid := []test{
+			{`literal`, `NewClient`, &RepoContainsSymbolPredicate{Pattern: "NewClient"}},
+			{`regexp`, `^New.*Client$`, &RepoContainsSymbolPredicate{Pattern: "^New.*Client$"}},
+		}
+
+		for _, tc := range valid {
+			t.Run(tc.name, func(t *testing.T) {
+				p := &RepoContainsSymbolPredicate{}
+				err := p.ParseParams(tc.params)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if !reflect.DeepEqual(tc.expected, p) {
+					t.Fatalf("expected %#v, got %#v", tc.expected, p)
+				}
+			})
+		}
+
+		invalid := []test{
+			{`empty`, ``, nil},
+			{`catch invalid regexp`, `([)`, nil},
+		}
+
+		for _, tc := range invalid {
+			t.Run(tc.name, func(t *testing.T) {
+				p := &RepoContainsSymbolPredicate{}
+				err := p.ParseParams(tc.params)
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+			})
+		}
+	})
+}
+
+func TestRepoHasLanguagePredicate(t *testing.T) {
+	t.Run("ParseParams", func(t *testing.T) {
+		type test struct {
+			name     string
+			params   string
+			expected *RepoHasLanguagePredicate
+		}
+
+		valid := []test{
+			{`canonical`, `Go`, &RepoHasLanguagePredicate{Language: "Go"}},
+			{`alias`, `golang`, &RepoHasLanguagePredicate{Language: "Go"}},
+			{`lowercase`, `typescript`, &RepoHasLanguagePredicate{Language: "TypeScript"}},
+		}
+
+		for _, tc := range valid {
+			t.Run(tc.name, func(t *testing.T) {
+				p := &RepoHasLanguagePredicate{}
+				err := p.ParseParams(tc.params)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if !reflect.DeepEqual(tc.expected, p) {
+					t.Fatalf("expected %#v, got %#v", tc.expected, p)
+				}
+			})
+		}
+
+		invalid := []test{
+			{`empty`, ``, nil},
+			{`unknown language`, `notalanguage`, nil},
+		}
+
+		for _, tc := range invalid {
+			t.Run(tc.name, func(t *testing.T) {
+				p := &RepoHasLanguagePredicate{}
+				err := p.ParseParams(tc.params)
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+			})
+		}
+	})
+}
@@ -99,6 +99,8 @@ func For(searchType SearchType) step {
 		processType = succeeds(substituteConcat(space))
 	case SearchTypeRegex:
 		processType = succeeds(escapeParensHeuristic, substituteConcat(fuzzyRegexp))
+	case SearchTypePCRE:
+		processType = succeeds(escapeParensHeuristic, substituteConcat(fuzzyRegexp), labelPCRE)
 	case SearchTypeStructural:
 		processType = succeeds(labelStructural, ellipsesForHoles, substituteConcat(space))
 	}
@@ -109,8 +111,14 @@ func For(searchType SearchType) step {
 // Init creates a step from an input string and search type. It parses the
 // initial input string.
 func Init(in string, searchType SearchType) step {
+	return InitWithMacros(in, searchType, nil)
+}
+
+// InitWithMacros is Init, but expands references to the given query macros
+// after parsing the input string. See ParseWithMacros.
+func InitWithMacros(in string, searchType SearchType, macros map[string]string) step {
 	parser := func([]Node) ([]Node, error) {
-		return Parse(in, searchType)
+		return ParseWithMacros(in, searchType, macros)
 	}
 	return Sequence(parser, For(searchType))
 }
@@ -16,7 +16,7 @@ func SubstituteAliases(searchType SearchType) func(nodes []Node) []Node {
 	mapper := func(nodes []Node) []Node {
 		return MapParameter(nodes, func(field, value string, negated bool, annotation Annotation) Node {
 			if field == "content" {
-				if searchType == SearchTypeRegex {
+				if searchType == SearchTypeRegex || searchType == SearchTypePCRE {
 					annotation.Labels.Set(Regexp)
 				} else {
 					annotation.Labels.Set(Literal)
@@ -824,6 +824,20 @@ func labelStructural(nodes []Node) []Node {
 	})
 }
 
+// labelPCRE labels the regexp patterns of a pcre query as PCRE patterns.
+func labelPCRE(nodes []Node) []Node {
+	return MapPattern(nodes, func(value string, negated bool, annotation Annotation) Node {
+		if annotation.Labels.IsSet(Regexp) {
+			annotation.Labels.Set(PCRE)
+		}
+		return Pattern{
+			Value:      value,
+			Negated:    negated,
+			Annotation: annotation,
+		}
+	})
+}
+
 // ellipsesForHoles substitutes ellipses ... for :[_] holes in structural search queries.
 func ellipsesForHoles(nodes []Node) []Node {
 	return MapPattern(nodes, func(value string, negated bool, annotation Annotation) Node {
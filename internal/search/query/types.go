@@ -21,6 +21,9 @@ func (e *ExpectedOperand) Error() string {
 
 type UnsupportedError struct {
 	Msg string
+
+	// Macro is the name of the query macro that caused the error, if any.
+	Macro string
 }
 
 func (e *UnsupportedError) Error() string {
@@ -36,6 +39,7 @@ const (
 	SearchTypeLucky
 	SearchTypeStandard
 	SearchTypeKeyword
+	SearchTypePCRE
 )
 
 func (s SearchType) String() string {
@@ -52,6 +56,8 @@ func (s SearchType) String() string {
 		return "lucky"
 	case SearchTypeKeyword:
 		return "keyword"
+	case SearchTypePCRE:
+		return "pcre"
 	default:
 		return fmt.Sprintf("unknown{%d}", s)
 	}
@@ -292,6 +298,12 @@ func (b Basic) IsStructural() bool {
 	return b.HasPatternLabel(Structural)
 }
 
+// IsPCRE returns whether b contains patterns with PCRE syntax. These patterns
+// are also regexp patterns.
+func (b Basic) IsPCRE() bool {
+	return b.HasPatternLabel(PCRE)
+}
+
 // PatternString returns the simple string pattern of a basic query. It assumes
 // there is only on pattern atom.
 func (b Basic) PatternString() string {
@@ -385,6 +397,40 @@ func (p Parameters) RepoHasFileContent() (res []RepoHasFileContentArgs) {
 	return res
 }
 
+// RepoHasSymbolArgs represents the args of the repo:contains.symbol(pattern)
+// and repo:has.symbol(pattern) predicates.
+type RepoHasSymbolArgs struct {
+	Pattern string
+	Negated bool
+}
+
+func (p Parameters) RepoContainsSymbol() (res []RepoHasSymbolArgs) {
+	VisitTypedPredicate(toNodes(p), func(pred *RepoContainsSymbolPredicate, negated bool) {
+		res = append(res, RepoHasSymbolArgs{
+			Pattern: pred.Pattern,
+			Negated: negated,
+		})
+	})
+	return res
+}
+
+// RepoHasLanguageArgs represents the args of the repo:has.language(name)
+// predicate. Language is the canonical language name as known by enry.
+type RepoHasLanguageArgs struct {
+	Language string
+	Negated  bool
+}
+
+func (p Parameters) RepoHasLanguage() (res []RepoHasLanguageArgs) {
+	VisitTypedPredicate(toNodes(p), func(pred *RepoHasLanguagePredicate, negated bool) {
+		res = append(res, RepoHasLanguageArgs{
+			Language: pred.Language,
+			Negated:  negated,
+		})
+	})
+	return res
+}
+
 func (p Parameters) FileContainsContent() (include []string) {
 	VisitTypedPredicate(toNodes(p), func(pred *FileContainsContentPredicate, negated bool) {
 		include = append(include, pred.Pattern)
@@ -484,6 +530,29 @@ func (p Parameters) Count() (count *int) {
 	return count
 }
 
+// Near returns the number of lines within which the patterns of an
+// and-expression must match, as set by `near:` or NEAR/n. Returns nil if
+// there is no proximity constraint.
+func (p Parameters) Near() (distance *int) {
+	VisitField(toNodes(p), FieldNear, func(value string, _ bool, _ Annotation) {
+		d, err := strconv.Atoi(value)
+		if err != nil {
+			panic(fmt.Sprintf("Value %q for near cannot be parsed as an int", value))
+		}
+		distance = &d
+	})
+	return distance
+}
+
+// SortBy returns the order of results requested with `sort:`, or
+// SortByRelevance if unspecified.
+func (p Parameters) SortBy() SortBy {
+	if v := p.FindValue(FieldSort); v != "" {
+		return parseSortBy(v)
+	}
+	return SortByRelevance
+}
+
 // GetTimeout returns the time.Duration value from the `timeout:` field.
 func (p Parameters) GetTimeout() *time.Duration {
 	var timeout *time.Duration
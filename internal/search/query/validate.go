@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dlclark/regexp2"
 	"github.com/go-enry/go-enry/v2"
 	"github.com/grafana/regexp"
 
@@ -207,6 +208,13 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		return nil
 	}
 
+	isSortBy := func() error {
+		if parseSortBy(value) == "" {
+			return errors.Errorf("invalid value %q for field %q. Valid values are: relevance, recency, ownership", value, field)
+		}
+		return nil
+	}
+
 	isUnrecognizedField := func() error {
 		return errors.Errorf("unrecognized field %q", field)
 	}
@@ -270,15 +278,23 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 	case
 		FieldAuthor,
 		FieldCommitter,
-		FieldMessage:
+		FieldMessage,
+		FieldRenamed,
+		FieldDeleted:
 		return satisfies(isValidRegexp)
+	case
+		FieldLinesAdded,
+		FieldLinesRemoved,
+		FieldFilesChanged:
+		return satisfies(isNumber)
 	case
 		FieldIndex,
 		FieldFork,
 		FieldArchived:
 		return satisfies(isSingular, isNotNegated, isYesNoOnly)
 	case
-		FieldCount:
+		FieldCount,
+		FieldNear:
 		return satisfies(isSingular, isNumber, isNotNegated)
 	case
 		FieldCombyRule:
@@ -292,6 +308,9 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 	case
 		FieldSelect:
 		return satisfies(isSingular, isNotNegated, isValidSelect)
+	case
+		FieldSort:
+		return satisfies(isSingular, isNotNegated, isSortBy)
 	default:
 		return isUnrecognizedField()
 	}
@@ -333,13 +352,44 @@ func validateRepoRevPair(nodes []Node) error {
 	return nil
 }
 
+// A query comparing two revisions with rev:base...head is invalid if it
+// searches other revisions too, or results other than file contents.
+func validateRevisionCompare(nodes []Node) error {
+	revs := revisions(nodes)
+	compare := ""
+	for _, rev := range revs {
+		if _, _, ok := ParseRevisionCompare(rev); ok {
+			compare = rev
+			break
+		}
+	}
+	if compare == "" {
+		return nil
+	}
+	for _, rev := range revs {
+		if rev != compare {
+			return errors.Errorf("invalid syntax. The query compares the revisions %s but also searches %s. Comparing revisions only supports a single `rev:base...head`", compare, rev)
+		}
+	}
+
+	var err error
+	VisitField(nodes, FieldType, func(value string, _ bool, _ Annotation) {
+		if value != "file" && err == nil {
+			err = errors.Errorf("comparing revisions with `rev:%s` only supports searching file contents, remove `type:%s` and try again", compare, value)
+		}
+	})
+	return err
+}
+
 // Queries containing commit parameters without type:diff or type:commit are not
 // valid. cf. https://docs.sourcegraph.com/code_search/reference/language#commit-parameter
 func validateCommitParameters(nodes []Node) error {
 	var seenCommitParam string
 	var typeCommitExists bool
 	VisitParameter(nodes, func(field, value string, _ bool, _ Annotation) {
-		if field == FieldAuthor || field == FieldBefore || field == FieldAfter || field == FieldMessage {
+		switch field {
+		case FieldAuthor, FieldBefore, FieldAfter, FieldMessage,
+			FieldLinesAdded, FieldLinesRemoved, FieldFilesChanged, FieldRenamed, FieldDeleted:
 			seenCommitParam = field
 		}
 		if field == FieldType && (value == "commit" || value == "diff") {
@@ -463,22 +513,137 @@ func validateParameters(nodes []Node) error {
 	return err
 }
 
+// PCREOptions are the options PCRE patterns are compiled with. Searcher
+// compiles them with the same options, so that patterns that validate here
+// also compile there.
+const PCREOptions = regexp2.RegexOptions(regexp2.Multiline)
+
 func validatePattern(nodes []Node) error {
 	var err error
 	VisitPattern(nodes, func(value string, negated bool, annotation Annotation) {
 		if err != nil {
 			return
 		}
-		if annotation.Labels.IsSet(Regexp) {
+		if annotation.Labels.IsSet(PCRE) {
+			_, err = regexp2.Compile(value, PCREOptions)
+		} else if annotation.Labels.IsSet(Regexp) {
 			_, err = regexp.Compile(value)
 		}
-		if annotation.Labels.IsSet(Structural) && negated {
-			err = errors.New("the query contains a negated search pattern. Structural search does not support negated search patterns at the moment")
+	})
+	return err
+}
+
+// validatePCRE checks that PCRE patterns are only used to search file
+// contents and paths without the index, since only searcher evaluates them.
+func validatePCRE(nodes []Node) error {
+	if !Exists(nodes, func(node Node) bool {
+		p, ok := node.(Pattern)
+		return ok && p.Annotation.Labels.IsSet(PCRE)
+	}) {
+		return nil
+	}
+
+	var err error
+	VisitParameter(nodes, func(field, value string, _ bool, _ Annotation) {
+		if err != nil {
+			return
+		}
+		switch field {
+		case FieldIndex:
+			if parseYesNoOnly(value) == Only {
+				err = errors.Errorf("invalid index:%s (patterntype:pcre only supports unindexed search)", value)
+			}
+		case FieldType:
+			if value != "file" && value != "path" {
+				err = errors.Errorf("invalid type:%s (patterntype:pcre only supports searching file contents and paths)", value)
+			}
+		case FieldNear:
+			err = errors.New("proximity search with `near:` or NEAR/n is not supported for patterntype:pcre")
 		}
 	})
 	return err
 }
 
+// validateStructuralNegation checks that negated structural patterns only
+// exclude matches of another pattern in the same and-expression, like
+// `foo(...) and not foo(nil)`. A negated structural pattern on its own would
+// have to match every file that does not contain it.
+func validateStructuralNegation(nodes []Node) error {
+	isNegatedStructural := func(node Node) bool {
+		p, ok := node.(Pattern)
+		return ok && p.Negated && p.Annotation.Labels.IsSet(Structural)
+	}
+
+	var visit func(node Node, excludable bool) bool
+	visit = func(node Node, excludable bool) bool {
+		switch n := node.(type) {
+		case Pattern:
+			return excludable || !isNegatedStructural(n)
+		case Operator:
+			hasPositive := false
+			if n.Kind == And {
+				for _, operand := range n.Operands {
+					if _, ok := operand.(Parameter); !ok && !isNegatedStructural(operand) {
+						hasPositive = true
+					}
+				}
+			}
+			for _, operand := range n.Operands {
+				if !visit(operand, n.Kind == And && hasPositive) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	for _, node := range nodes {
+		if !visit(node, false) {
+			return errors.New("the query contains a negated search pattern. Structural search only supports negated patterns that exclude matches of another pattern, like `foo(...) and not foo(nil)`")
+		}
+	}
+	return nil
+}
+
+// validateNear checks that `near:` and NEAR/n constrain an and-expression of
+// at least two non-negated patterns, since proximity is measured between the
+// matches of its operands.
+func validateNear(nodes []Node) error {
+	if !Exists(nodes, func(node Node) bool {
+		p, ok := node.(Parameter)
+		return ok && p.Field == FieldNear
+	}) {
+		return nil
+	}
+
+	if Exists(nodes, func(node Node) bool {
+		p, ok := node.(Pattern)
+		return ok && p.Annotation.Labels.IsSet(Structural)
+	}) {
+		return errors.New("proximity search with `near:` or NEAR/n is not supported for structural search")
+	}
+
+	operands := 0
+	for _, node := range nodes {
+		if n, ok := node.(Operator); ok && n.Kind == And {
+			for _, operand := range n.Operands {
+				switch o := operand.(type) {
+				case Pattern:
+					if !o.Negated {
+						operands++
+					}
+				case Operator:
+					operands++
+				}
+			}
+		}
+	}
+	if operands < 2 {
+		return errors.New("proximity search with `near:` or NEAR/n requires at least two search patterns, like `lock() NEAR/5 unlock()`")
+	}
+	return nil
+}
+
 func validate(nodes []Node) error {
 	succeeds := func(fns ...func([]Node) error) error {
 		for _, fn := range fns {
@@ -492,7 +657,11 @@ func validate(nodes []Node) error {
 	return succeeds(
 		validateParameters,
 		validatePattern,
+		validatePCRE,
+		validateStructuralNegation,
+		validateNear,
 		validateRepoRevPair,
+		validateRevisionCompare,
 		validateRepoHasFile,
 		validateCommitParameters,
 		validateTypeStructural,
@@ -509,6 +678,34 @@ const (
 	Invalid YesNoOnly = "invalid"
 )
 
+// SortBy is the order of results requested with `sort:`.
+type SortBy string
+
+const (
+	// SortByRelevance keeps the order of the search backends. This is the
+	// default.
+	SortByRelevance SortBy = "relevance"
+	// SortByRecency orders file results by the date of the last commit
+	// modifying the file, most recent first.
+	SortByRecency SortBy = "recency"
+	// SortByOwnership orders file results owned by the searching user
+	// first, then files with any code owner, then files without owners.
+	SortByOwnership SortBy = "ownership"
+)
+
+func parseSortBy(s string) SortBy {
+	switch strings.ToLower(s) {
+	case "relevance":
+		return SortByRelevance
+	case "recency", "recent":
+		return SortByRecency
+	case "ownership", "owner":
+		return SortByOwnership
+	default:
+		return ""
+	}
+}
+
 func parseYesNoOnly(s string) YesNoOnly {
 	switch s {
 	case "y", "Y", "yes", "YES", "Yes":
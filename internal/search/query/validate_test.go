@@ -45,6 +45,31 @@ func TestValidation(t *testing.T) {
 			input: "count:-1",
 			want:  "field count requires a positive number",
 		},
+		{
+			input: "foo near:0 bar",
+			want:  "field near requires a positive number",
+		},
+		{
+			input: "foo near:3",
+			want:  "proximity search with `near:` or NEAR/n requires at least two search patterns, like `lock() NEAR/5 unlock()`",
+		},
+		{
+			input: "foo NEAR/3 not bar",
+			want:  "proximity search with `near:` or NEAR/n requires at least two search patterns, like `lock() NEAR/5 unlock()`",
+		},
+		{
+			input:      "foo(...) NEAR/3 bar(...)",
+			want:       "proximity search with `near:` or NEAR/n is not supported for structural search",
+			searchType: SearchTypeStructural,
+		},
+		{
+			input: "foo sort:newest",
+			want:  `invalid value "newest" for field "sort". Valid values are: relevance, recency, ownership`,
+		},
+		{
+			input: "foo -sort:recency",
+			want:  `field "sort" does not support negation`,
+		},
 		{
 			input: "+",
 			want:  "error parsing regexp: missing argument to repetition operator: `+`",
@@ -55,12 +80,17 @@ func TestValidation(t *testing.T) {
 		},
 		{
 			input:      `-content:"foo"`,
-			want:       "the query contains a negated search pattern. Structural search does not support negated search patterns at the moment",
+			want:       "the query contains a negated search pattern. Structural search only supports negated patterns that exclude matches of another pattern, like `foo(...) and not foo(nil)`",
 			searchType: SearchTypeStructural,
 		},
 		{
 			input:      `NOT foo`,
-			want:       "the query contains a negated search pattern. Structural search does not support negated search patterns at the moment",
+			want:       "the query contains a negated search pattern. Structural search only supports negated patterns that exclude matches of another pattern, like `foo(...) and not foo(nil)`",
+			searchType: SearchTypeStructural,
+		},
+		{
+			input:      `foo(...) or not foo(nil)`,
+			want:       "the query contains a negated search pattern. Structural search only supports negated patterns that exclude matches of another pattern, like `foo(...) and not foo(nil)`",
 			searchType: SearchTypeStructural,
 		},
 		{
@@ -79,10 +109,26 @@ func TestValidation(t *testing.T) {
 			input: `repo:'' rev:bedge`,
 			want:  "invalid syntax. The query contains `rev:` without `repo:`. Add a `repo:` filter and try again",
 		},
+		{
+			input: "repo:foo@a...b repo:bar@c foo",
+			want:  "invalid syntax. The query compares the revisions a...b but also searches c. Comparing revisions only supports a single `rev:base...head`",
+		},
+		{
+			input: "repo:foo rev:main...dev type:symbol foo",
+			want:  "comparing revisions with `rev:main...dev` only supports searching file contents, remove `type:symbol` and try again",
+		},
 		{
 			input: "repo:foo author:rob@saucegraph.com",
 			want:  `your query contains the field 'author', which requires type:commit or type:diff in the query`,
 		},
+		{
+			input: "repo:foo linesadded:1000",
+			want:  `your query contains the field 'linesadded', which requires type:commit or type:diff in the query`,
+		},
+		{
+			input: "repo:foo type:diff fileschanged:many",
+			want:  `field fileschanged has value many, many is not a number`,
+		},
 		{
 			input: "repohasfile:README type:symbol yolo",
 			want:  "repohasfile is not compatible for type:symbol. Subscribe to https://github.com/sourcegraph/sourcegraph/issues/4610 for updates",
@@ -109,6 +155,21 @@ func TestValidation(t *testing.T) {
 			want:       "this structural search query specifies `type:` and is not supported. Structural search syntax only applies to searching file contents and is not currently supported for diff searches",
 			searchType: SearchTypeStructural,
 		},
+		{
+			input:      "foo(?<=bar",
+			want:       "error parsing regexp: missing closing ) in `foo(?<=bar`",
+			searchType: SearchTypePCRE,
+		},
+		{
+			input:      "foo(?=bar) index:only",
+			want:       "invalid index:only (patterntype:pcre only supports unindexed search)",
+			searchType: SearchTypePCRE,
+		},
+		{
+			input:      "foo(?=bar) type:commit",
+			want:       "invalid type:commit (patterntype:pcre only supports searching file contents and paths)",
+			searchType: SearchTypePCRE,
+		},
 	}
 	for _, c := range cases {
 		t.Run("validate and/or query", func(t *testing.T) {
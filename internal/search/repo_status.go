@@ -15,10 +15,11 @@ import (
 type RepoStatus uint8
 
 const (
-	RepoStatusCloning  RepoStatus = 1 << iota // could not be searched because they were still being cloned
-	RepoStatusMissing                         // could not be searched because they do not exist
-	RepoStatusLimitHit                        // searched, but have results that were not returned due to exceeded limits
-	RepoStatusTimedout                        // repos that were not searched due to timeout
+	RepoStatusCloning         RepoStatus = 1 << iota // could not be searched because they were still being cloned
+	RepoStatusMissing                                // could not be searched because they do not exist
+	RepoStatusLimitHit                               // searched, but have results that were not returned due to exceeded limits
+	RepoStatusTimedout                               // repos that were not searched due to timeout
+	RepoStatusPatternTimedout                        // searched, but some files were skipped because matching the pattern exceeded per-file limits
 )
 
 var repoStatusName = []struct {
@@ -29,6 +30,7 @@ var repoStatusName = []struct {
 	{RepoStatusMissing, "missing"},
 	{RepoStatusLimitHit, "limithit"},
 	{RepoStatusTimedout, "timedout"},
+	{RepoStatusPatternTimedout, "patterntimedout"},
 }
 
 func (s RepoStatus) String() string {
@@ -154,6 +156,18 @@ func repoStatusSingleton(id api.RepoID, status RepoStatus) RepoStatusMap {
 	}
 }
 
+// PatternTimeoutError is returned by a search of a repository which skipped
+// files because matching the pattern against them exceeded the per-file time
+// or memory limits of the backend.
+type PatternTimeoutError struct {
+	// SkippedFiles is the number of files which were skipped.
+	SkippedFiles int
+}
+
+func (e *PatternTimeoutError) Error() string {
+	return fmt.Sprintf("skipped %d files which exceeded the pattern matching limits", e.SkippedFiles)
+}
+
 // HandleRepoSearchResult returns information about repository status, whether
 // search limits are hit, and error promotion. If searchErr is a fatal error, it
 // returns a non-nil error; otherwise, if searchErr == nil or a non-fatal error,
@@ -179,6 +193,8 @@ func HandleRepoSearchResult(repoID api.RepoID, revSpecs []string, limitHit, time
 		}
 	} else if errcode.IsNotFound(searchErr) {
 		status |= RepoStatusMissing
+	} else if errors.HasType(searchErr, &PatternTimeoutError{}) {
+		status |= RepoStatusPatternTimedout
 	} else if errcode.IsTimeout(searchErr) || errcode.IsTemporary(searchErr) || timedOut {
 		status |= RepoStatusTimedout
 	} else if searchErr != nil {
@@ -8,6 +8,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 func TestRepoStatusMap(t *testing.T) {
@@ -172,3 +173,17 @@ func assertReposStatusEqual(t *testing.T, want, got RepoStatusMap) {
 		t.Errorf("RepoStatusMap mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestHandleRepoSearchResult_patternTimeout(t *testing.T) {
+	err := errors.Wrap(&PatternTimeoutError{SkippedFiles: 2}, "searcher")
+	status, limitHit, fatalErr := HandleRepoSearchResult(1, nil, true, false, err)
+	if fatalErr != nil {
+		t.Fatalf("unexpected fatal error: %v", fatalErr)
+	}
+	if !limitHit {
+		t.Error("limitHit should be true")
+	}
+	if got, want := status.Get(1), RepoStatusPatternTimedout|RepoStatusLimitHit; got != want {
+		t.Errorf("got %s want %s", got, want)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/golang/groupcache/lru"
 	"github.com/grafana/regexp"
 	regexpsyntax "github.com/grafana/regexp/syntax"
 	otlog "github.com/opentracing/opentracing-go/log"
@@ -17,6 +18,7 @@ import (
 	zoektquery "github.com/sourcegraph/zoekt/query"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -26,6 +28,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/endpoint"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/inventory"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/limits"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
@@ -115,68 +118,17 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (_ Resolv
 		tr.Finish()
 	}()
 
-	excludePatterns := op.MinusRepoFilters
-	includePatterns, includePatternRevs, errs := findPatternRevs(op.RepoFilters)
-	if errs != nil {
-		return Resolved{}, errs
-	}
-
 	limit := op.Limit
 	if limit == 0 {
 		limit = limits.SearchLimits(conf.Get()).MaxRepos
 	}
 
-	searchContext, errs := searchcontexts.ResolveSearchContextSpec(ctx, r.db, op.SearchContextSpec)
+	options, includePatternRevs, searchContext, errs := r.listOptions(ctx, op)
 	if errs != nil {
 		return Resolved{}, errs
 	}
-
-	kvpFilters := make([]database.RepoKVPFilter, 0, len(op.HasKVPs))
-	for _, filter := range op.HasKVPs {
-		kvpFilters = append(kvpFilters, database.RepoKVPFilter{
-			Key:     filter.Key,
-			Value:   filter.Value,
-			Negated: filter.Negated,
-		})
-	}
-
-	options := database.ReposListOptions{
-		IncludePatterns:       includePatterns,
-		ExcludePattern:        query.UnionRegExps(excludePatterns),
-		DescriptionPatterns:   op.DescriptionPatterns,
-		CaseSensitivePatterns: op.CaseSensitiveRepoFilters,
-		KVPFilters:            kvpFilters,
-		Cursors:               op.Cursors,
-		// List N+1 repos so we can see if there are repos omitted due to our repo limit.
-		LimitOffset:  &database.LimitOffset{Limit: limit + 1},
-		NoForks:      op.NoForks,
-		OnlyForks:    op.OnlyForks,
-		NoArchived:   op.NoArchived,
-		OnlyArchived: op.OnlyArchived,
-		NoPrivate:    op.Visibility == query.Public,
-		OnlyPrivate:  op.Visibility == query.Private,
-		OnlyCloned:   op.OnlyCloned,
-		OrderBy: database.RepoListOrderBy{
-			{
-				Field:      database.RepoListStars,
-				Descending: true,
-				Nulls:      "LAST",
-			},
-			{
-				Field:      database.RepoListID,
-				Descending: true,
-			},
-		},
-	}
-
-	// Filter by search context repository revisions only if this search context doesn't have
-	// a query, which replaces the context:foo term at query parsing time.
-	if searchContext.Query == "" {
-		options.SearchContextID = searchContext.ID
-		options.UserID = searchContext.NamespaceUserID
-		options.OrgID = searchContext.NamespaceOrgID
-		options.IncludeUserPublicRepos = searchContext.ID == 0 && searchContext.NamespaceUserID != 0
-	}
+	// List N+1 repos so we can see if there are repos omitted due to our repo limit.
+	options.LimitOffset = &database.LimitOffset{Limit: limit + 1}
 
 	tr.LazyPrintf("Repos.ListMinimalRepos - start")
 	repos, errs := r.db.Repos().ListMinimalRepos(ctx, options)
@@ -261,6 +213,22 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (_ Resolv
 	}
 	tr.LazyPrintf("finished contains filtering")
 
+	tr.LazyPrintf("starting symbol filtering")
+	filteredRepoRevs, missingHasSymbolRevs, err := r.filterRepoHasSymbol(ctx, filteredRepoRevs, op)
+	missingRepoRevs = append(missingRepoRevs, missingHasSymbolRevs...)
+	if err != nil {
+		return Resolved{}, errors.Wrap(err, "filter has symbol")
+	}
+	tr.LazyPrintf("finished symbol filtering")
+
+	tr.LazyPrintf("starting language filtering")
+	filteredRepoRevs, missingHasLanguageRevs, err := r.filterRepoHasLanguage(ctx, filteredRepoRevs, op)
+	missingRepoRevs = append(missingRepoRevs, missingHasLanguageRevs...)
+	if err != nil {
+		return Resolved{}, errors.Wrap(err, "filter has language")
+	}
+	tr.LazyPrintf("finished language filtering")
+
 	if len(missingRepoRevs) > 0 {
 		err = errors.Append(err, &MissingRepoRevsError{Missing: missingRepoRevs})
 	}
@@ -272,6 +240,81 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (_ Resolv
 	}, err
 }
 
+// Count returns the number of repositories matching the repo filters of op,
+// starting at op.Cursors. Unlike Resolve, it doesn't resolve revisions or
+// evaluate repo: predicates, so it is an upper bound of the number of
+// repositories Resolve returns over all pages.
+func (r *Resolver) Count(ctx context.Context, op search.RepoOptions) (int, error) {
+	options, _, _, err := r.listOptions(ctx, op)
+	if err != nil {
+		return 0, err
+	}
+	return r.db.Repos().Count(ctx, options)
+}
+
+// listOptions returns the options to list the repositories matching the repo
+// filters of op, along with the revisions requested by its repo: filters and
+// the search context it is resolved in.
+func (r *Resolver) listOptions(ctx context.Context, op search.RepoOptions) (database.ReposListOptions, []patternRevspec, *types.SearchContext, error) {
+	excludePatterns := op.MinusRepoFilters
+	includePatterns, includePatternRevs, err := findPatternRevs(op.RepoFilters)
+	if err != nil {
+		return database.ReposListOptions{}, nil, nil, err
+	}
+
+	searchContext, err := searchcontexts.ResolveSearchContextSpec(ctx, r.db, op.SearchContextSpec)
+	if err != nil {
+		return database.ReposListOptions{}, nil, nil, err
+	}
+
+	kvpFilters := make([]database.RepoKVPFilter, 0, len(op.HasKVPs))
+	for _, filter := range op.HasKVPs {
+		kvpFilters = append(kvpFilters, database.RepoKVPFilter{
+			Key:     filter.Key,
+			Value:   filter.Value,
+			Negated: filter.Negated,
+		})
+	}
+
+	options := database.ReposListOptions{
+		IncludePatterns:       includePatterns,
+		ExcludePattern:        query.UnionRegExps(excludePatterns),
+		DescriptionPatterns:   op.DescriptionPatterns,
+		CaseSensitivePatterns: op.CaseSensitiveRepoFilters,
+		KVPFilters:            kvpFilters,
+		Cursors:               op.Cursors,
+		NoForks:               op.NoForks,
+		OnlyForks:             op.OnlyForks,
+		NoArchived:            op.NoArchived,
+		OnlyArchived:          op.OnlyArchived,
+		NoPrivate:             op.Visibility == query.Public,
+		OnlyPrivate:           op.Visibility == query.Private,
+		OnlyCloned:            op.OnlyCloned,
+		OrderBy: database.RepoListOrderBy{
+			{
+				Field:      database.RepoListStars,
+				Descending: true,
+				Nulls:      "LAST",
+			},
+			{
+				Field:      database.RepoListID,
+				Descending: true,
+			},
+		},
+	}
+
+	// Filter by search context repository revisions only if this search context doesn't have
+	// a query, which replaces the context:foo term at query parsing time.
+	if searchContext.Query == "" {
+		options.SearchContextID = searchContext.ID
+		options.UserID = searchContext.NamespaceUserID
+		options.OrgID = searchContext.NamespaceOrgID
+		options.IncludeUserPublicRepos = searchContext.ID == 0 && searchContext.NamespaceUserID != 0
+	}
+
+	return options, includePatternRevs, searchContext, nil
+}
+
 // associateReposWithRevs re-associates revisions with the repositories fetched from the db
 func (r *Resolver) associateReposWithRevs(
 	repos []types.MinimalRepo,
@@ -586,10 +629,6 @@ func (r *Resolver) filterRepoHasFileContent(
 
 	{ // Use zoekt for indexed revs
 		g.Go(func(ctx context.Context) error {
-			type repoAndRev struct {
-				id  api.RepoID
-				rev string
-			}
 			var revsMatchingAllPredicates Set[repoAndRev]
 			for i, opt := range op.HasFileContent {
 				q := searchzoekt.QueryForFileContentArgs(opt, op.CaseSensitiveRepoFilters)
@@ -714,6 +753,317 @@ func (r *Resolver) repoHasFileContentAtCommit(ctx context.Context, repo types.Mi
 	return foundMatches, err
 }
 
+// filterRepoHasSymbol filters a page of repos to only those that define a
+// symbol matching each of the predicates in RepoOptions.HasSymbol. Indexed
+// revisions are checked with a single Zoekt list request per predicate, and
+// unindexed revisions are checked against the symbols service.
+func (r *Resolver) filterRepoHasSymbol(
+	ctx context.Context,
+	repoRevs []*search.RepositoryRevisions,
+	op search.RepoOptions,
+) (
+	_ []*search.RepositoryRevisions,
+	_ []RepoRevSpecs,
+	err error,
+) {
+	tr, ctx := trace.New(ctx, "Resolve.FilterHasSymbol", "")
+	tr.LogFields(otlog.Int("inputRevCount", len(repoRevs)))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// Early return if there are no filters
+	if len(op.HasSymbol) == 0 {
+		return repoRevs, nil, nil
+	}
+
+	indexed, unindexed, err := searchzoekt.PartitionRepos(
+		ctx,
+		r.logger,
+		repoRevs,
+		r.zoekt,
+		search.SymbolRequest,
+		op.UseIndex,
+		false,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		mu         sync.Mutex
+		matched    = Set[repoAndRev]{}
+		addRepoRev = func(id api.RepoID, rev string) {
+			mu.Lock()
+			matched.Add(repoAndRev{id: id, rev: rev})
+			mu.Unlock()
+		}
+	)
+
+	var (
+		missingMu  sync.Mutex
+		missing    []RepoRevSpecs
+		addMissing = func(rs RepoRevSpecs) {
+			missingMu.Lock()
+			missing = append(missing, rs)
+			missingMu.Unlock()
+		}
+	)
+
+	g := group.New().WithContext(ctx).WithMaxConcurrency(16)
+
+	{ // Use zoekt for indexed revs
+		g.Go(func(ctx context.Context) error {
+			var revsMatchingAllPredicates Set[repoAndRev]
+			for i, opt := range op.HasSymbol {
+				q := searchzoekt.QueryForSymbolArgs(opt, op.CaseSensitiveRepoFilters)
+				q = zoektquery.NewAnd(&zoektquery.BranchesRepos{List: indexed.BranchRepos()}, q)
+
+				repos, err := r.zoekt.List(ctx, q, &zoekt.ListOptions{Minimal: true})
+				if err != nil {
+					return err
+				}
+
+				foundRevs := Set[repoAndRev]{}
+				for repoID, repo := range repos.Minimal {
+					inputRevs := indexed.RepoRevs[api.RepoID(repoID)].Revs
+					for _, branch := range repo.Branches {
+						for _, inputRev := range inputRevs {
+							if branch.Name == inputRev || (branch.Name == "HEAD" && inputRev == "") {
+								foundRevs.Add(repoAndRev{id: api.RepoID(repoID), rev: inputRev})
+							}
+						}
+					}
+				}
+
+				if i == 0 {
+					revsMatchingAllPredicates = foundRevs
+				} else {
+					revsMatchingAllPredicates.IntersectWith(foundRevs)
+				}
+			}
+
+			for rr := range revsMatchingAllPredicates {
+				addRepoRev(rr.id, rr.rev)
+			}
+			return nil
+		})
+	}
+
+	{ // Use the symbols service for unindexed revs
+		for _, repoRevs := range unindexed {
+			for _, rev := range repoRevs.Revs {
+				repo, rev := repoRevs.Repo, rev
+
+				g.Go(func(ctx context.Context) error {
+					commitID, err := r.gitserver.ResolveRevision(ctx, repo.Name, rev, gitserver.ResolveRevisionOptions{NoEnsureRevision: true})
+					if err != nil {
+						if errors.Is(err, context.DeadlineExceeded) || errors.HasType(err, &gitdomain.BadCommitError{}) {
+							return err
+						}
+						addMissing(RepoRevSpecs{Repo: repo, Revs: []search.RevisionSpecifier{{RevSpec: rev}}})
+						return nil
+					}
+
+					for _, arg := range op.HasSymbol {
+						symbols, err := backend.Symbols.ListTags(ctx, search.SymbolsParameters{
+							Repo:            repo.Name,
+							CommitID:        commitID,
+							Query:           arg.Pattern,
+							IsRegExp:        true,
+							IsCaseSensitive: op.CaseSensitiveRepoFilters,
+							First:           1,
+						})
+						if err != nil {
+							return err
+						}
+						if (len(symbols) > 0) == arg.Negated {
+							return nil
+						}
+					}
+
+					// If we made it here, every symbol predicate is satisfied.
+					addRepoRev(repo.ID, rev)
+					return nil
+				})
+			}
+		}
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	filtered := filterRepoRevsBySet(repoRevs, matched)
+	tr.LogFields(otlog.Int("filteredRevCount", len(filtered)))
+	return filtered, missing, nil
+}
+
+// filterRepoHasLanguage filters a page of repos to only those revisions whose
+// dominant language, as computed from the repository inventory, satisfies
+// each of the predicates in RepoOptions.HasLanguage.
+func (r *Resolver) filterRepoHasLanguage(
+	ctx context.Context,
+	repoRevs []*search.RepositoryRevisions,
+	op search.RepoOptions,
+) (
+	_ []*search.RepositoryRevisions,
+	_ []RepoRevSpecs,
+	err error,
+) {
+	tr, ctx := trace.New(ctx, "Resolve.FilterHasLanguage", "")
+	tr.LogFields(otlog.Int("inputRevCount", len(repoRevs)))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// Early return if there are no filters
+	if len(op.HasLanguage) == 0 {
+		return repoRevs, nil, nil
+	}
+
+	var (
+		mu         sync.Mutex
+		matched    = Set[repoAndRev]{}
+		addRepoRev = func(id api.RepoID, rev string) {
+			mu.Lock()
+			matched.Add(repoAndRev{id: id, rev: rev})
+			mu.Unlock()
+		}
+
+		missing    []RepoRevSpecs
+		addMissing = func(rs RepoRevSpecs) {
+			mu.Lock()
+			missing = append(missing, rs)
+			mu.Unlock()
+		}
+	)
+
+	repoStore := backend.NewRepos(r.logger, r.db)
+	g := group.New().WithContext(ctx).WithMaxConcurrency(16)
+	for _, repoRev := range repoRevs {
+		for _, rev := range repoRev.Revs {
+			repo, rev := repoRev.Repo, rev
+
+			g.Go(func(ctx context.Context) error {
+				commitID, err := r.gitserver.ResolveRevision(ctx, repo.Name, rev, gitserver.ResolveRevisionOptions{NoEnsureRevision: true})
+				if err != nil {
+					if errors.Is(err, context.DeadlineExceeded) || errors.HasType(err, &gitdomain.BadCommitError{}) {
+						return err
+					}
+					addMissing(RepoRevSpecs{Repo: repo, Revs: []search.RevisionSpecifier{{RevSpec: rev}}})
+					return nil
+				}
+
+				dominant, err := cachedDominantLanguage(ctx, repoStore, repo, commitID)
+				if err != nil {
+					return err
+				}
+
+				for _, arg := range op.HasLanguage {
+					if strings.EqualFold(dominant, arg.Language) == arg.Negated {
+						return nil
+					}
+				}
+
+				addRepoRev(repo.ID, rev)
+				return nil
+			})
+		}
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	filtered := filterRepoRevsBySet(repoRevs, matched)
+	tr.LogFields(otlog.Int("filteredRevCount", len(filtered)))
+	return filtered, missing, nil
+}
+
+// dominantLanguageCache caches the dominant language of repositories at a
+// commit, which never changes. Without it, the inventory of every revision is
+// computed again for each page and each job of a search, and for every search.
+var (
+	dominantLanguageCacheMu sync.Mutex
+	dominantLanguageCache   = lru.New(10000)
+)
+
+type repoAndCommit struct {
+	id     api.RepoID
+	commit api.CommitID
+}
+
+type inventoryGetter interface {
+	GetInventory(ctx context.Context, repo *types.Repo, commitID api.CommitID, forceEnhancedLanguageDetection bool) (*inventory.Inventory, error)
+}
+
+// cachedDominantLanguage returns the dominant language of repo at commitID,
+// computing its inventory if it's not cached.
+func cachedDominantLanguage(ctx context.Context, repoStore inventoryGetter, repo types.MinimalRepo, commitID api.CommitID) (string, error) {
+	key := repoAndCommit{id: repo.ID, commit: commitID}
+	dominantLanguageCacheMu.Lock()
+	v, ok := dominantLanguageCache.Get(key)
+	dominantLanguageCacheMu.Unlock()
+	if ok {
+		return v.(string), nil
+	}
+
+	inv, err := repoStore.GetInventory(ctx, repo.ToRepo(), commitID, false)
+	if err != nil {
+		return "", err
+	}
+	dominant := dominantLanguage(inv)
+
+	dominantLanguageCacheMu.Lock()
+	dominantLanguageCache.Add(key, dominant)
+	dominantLanguageCacheMu.Unlock()
+	return dominant, nil
+}
+
+// dominantLanguage returns the name of the language with the most bytes of
+// code in inv, or the empty string if inv has no languages.
+func dominantLanguage(inv *inventory.Inventory) string {
+	if inv == nil {
+		return ""
+	}
+	var dominant inventory.Lang
+	for _, lang := range inv.Languages {
+		if lang.TotalBytes > dominant.TotalBytes {
+			dominant = lang
+		}
+	}
+	return dominant.Name
+}
+
+type repoAndRev struct {
+	id  api.RepoID
+	rev string
+}
+
+// filterRepoRevsBySet returns the repos and revisions of repoRevs which are
+// present in matched, preserving the input order.
+func filterRepoRevsBySet(repoRevs []*search.RepositoryRevisions, matched Set[repoAndRev]) []*search.RepositoryRevisions {
+	filtered := repoRevs[:0]
+	for _, repoRev := range repoRevs {
+		revs := make([]string, 0, len(repoRev.Revs))
+		for _, rev := range repoRev.Revs {
+			if _, ok := matched[repoAndRev{id: repoRev.Repo.ID, rev: rev}]; ok {
+				revs = append(revs, rev)
+			}
+		}
+		if len(revs) > 0 {
+			filtered = append(filtered, &search.RepositoryRevisions{
+				Repo: repoRev.Repo,
+				Revs: revs,
+			})
+		}
+	}
+	return filtered
+}
+
 // computeExcludedRepos computes the ExcludedRepos that the given RepoOptions would not match. This is
 // used to show in the search UI what repos are excluded precisely.
 func computeExcludedRepos(ctx context.Context, db database.DB, op search.RepoOptions) (ex ExcludedRepos, err error) {
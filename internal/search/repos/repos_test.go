@@ -2,8 +2,11 @@ package repos
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"reflect"
 	"sort"
@@ -15,9 +18,11 @@ import (
 	"github.com/grafana/regexp"
 	"github.com/sourcegraph/zoekt"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
 
 	"github.com/sourcegraph/log/logtest"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
@@ -26,9 +31,12 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/endpoint"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/inventory"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
+	"github.com/sourcegraph/sourcegraph/internal/symbols"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -756,3 +764,262 @@ func TestRepoHasCommitAfter(t *testing.T) {
 		})
 	}
 }
+
+func TestRepoHasSymbol(t *testing.T) {
+	repoA := types.MinimalRepo{ID: 1, Name: "example.com/1"}
+	repoB := types.MinimalRepo{ID: 2, Name: "example.com/2"}
+
+	mkHead := func(repo types.MinimalRepo) *search.RepositoryRevisions {
+		return &search.RepositoryRevisions{
+			Repo: repo,
+			Revs: []string{""},
+		}
+	}
+
+	repos := database.NewMockRepoStore()
+	repos.ListMinimalReposFunc.SetDefaultReturn([]types.MinimalRepo{repoA, repoB}, nil)
+
+	db := database.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(repos)
+
+	cases := []struct {
+		name          string
+		filters       []query.RepoHasSymbolArgs
+		matchingRepos map[uint32]*zoekt.MinimalRepoListEntry
+		expected      []*search.RepositoryRevisions
+	}{{
+		name:    "no filters",
+		filters: nil,
+		expected: []*search.RepositoryRevisions{
+			mkHead(repoA),
+			mkHead(repoB),
+		},
+	}, {
+		name: "no matching symbol",
+		filters: []query.RepoHasSymbolArgs{{
+			Pattern: "NewClient",
+		}},
+		matchingRepos: nil,
+		expected:      []*search.RepositoryRevisions{},
+	}, {
+		name: "one matching repo",
+		filters: []query.RepoHasSymbolArgs{{
+			Pattern: "NewClient",
+		}},
+		matchingRepos: map[uint32]*zoekt.MinimalRepoListEntry{
+			2: {
+				Branches: []zoekt.RepositoryBranch{{Name: "HEAD"}},
+			},
+		},
+		expected: []*search.RepositoryRevisions{
+			mkHead(repoB),
+		},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// All repos are indexed
+			mockZoekt := NewMockStreamer()
+			mockZoekt.ListFunc.PushReturn(&zoekt.RepoList{
+				Minimal: map[uint32]*zoekt.MinimalRepoListEntry{
+					uint32(repoA.ID): {
+						Branches:   []zoekt.RepositoryBranch{{Name: "HEAD"}},
+						HasSymbols: true,
+					},
+					uint32(repoB.ID): {
+						Branches:   []zoekt.RepositoryBranch{{Name: "HEAD"}},
+						HasSymbols: true,
+					},
+				},
+			}, nil)
+
+			mockZoekt.ListFunc.PushReturn(&zoekt.RepoList{
+				Minimal: tc.matchingRepos,
+			}, nil)
+
+			res := NewResolver(logtest.Scoped(t), db, endpoint.Static("test"), mockZoekt)
+			resolved, err := res.Resolve(context.Background(), search.RepoOptions{
+				RepoFilters: []string{".*"},
+				HasSymbol:   tc.filters,
+			})
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expected, resolved.RepoRevs)
+		})
+	}
+}
+
+func TestRepoHasSymbolUnindexed(t *testing.T) {
+	repoA := types.MinimalRepo{ID: 1, Name: "example.com/1"}
+	repoB := types.MinimalRepo{ID: 2, Name: "example.com/2"}
+
+	mkHead := func(repo types.MinimalRepo) *search.RepositoryRevisions {
+		return &search.RepositoryRevisions{
+			Repo: repo,
+			Revs: []string{""},
+		}
+	}
+
+	// Only repoB defines NewClient.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var args search.SymbolsParameters
+		if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var res search.SymbolsResponse
+		if args.Repo == repoB.Name && args.CommitID == "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef" && args.Query == "NewClient" {
+			res.Symbols = result.Symbols{{Name: "NewClient", Path: "client.go"}}
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	t.Cleanup(srv.Close)
+	oldURL := symbols.DefaultClient.URL
+	symbols.DefaultClient.URL = srv.URL
+	t.Cleanup(func() { symbols.DefaultClient.URL = oldURL })
+
+	mockGitserver := gitserver.NewMockClient()
+	mockGitserver.ResolveRevisionFunc.SetDefaultReturn("deadbeefdeadbeefdeadbeefdeadbeefdeadbeef", nil)
+
+	repos := database.NewMockRepoStore()
+	repos.ListMinimalReposFunc.SetDefaultReturn([]types.MinimalRepo{repoA, repoB}, nil)
+
+	db := database.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(repos)
+
+	cases := []struct {
+		name     string
+		filters  []query.RepoHasSymbolArgs
+		expected []*search.RepositoryRevisions
+	}{{
+		name:     "matching symbol",
+		filters:  []query.RepoHasSymbolArgs{{Pattern: "NewClient"}},
+		expected: []*search.RepositoryRevisions{mkHead(repoB)},
+	}, {
+		name:     "negated matching symbol",
+		filters:  []query.RepoHasSymbolArgs{{Pattern: "NewClient", Negated: true}},
+		expected: []*search.RepositoryRevisions{mkHead(repoA)},
+	}, {
+		name:     "no matching symbol",
+		filters:  []query.RepoHasSymbolArgs{{Pattern: "Missing"}},
+		expected: []*search.RepositoryRevisions{},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// No repos are indexed, so symbols are looked up with the
+			// symbols service.
+			mockZoekt := NewMockStreamer()
+			mockZoekt.ListFunc.SetDefaultReturn(&zoekt.RepoList{}, nil)
+
+			res := NewResolver(logtest.Scoped(t), db, endpoint.Static("test"), mockZoekt)
+			res.gitserver = mockGitserver
+			resolved, err := res.Resolve(context.Background(), search.RepoOptions{
+				RepoFilters: []string{".*"},
+				HasSymbol:   tc.filters,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, resolved.RepoRevs)
+		})
+	}
+}
+
+func TestRepoHasLanguage(t *testing.T) {
+	repoA := types.MinimalRepo{ID: 1, Name: "example.com/1"}
+	repoB := types.MinimalRepo{ID: 2, Name: "example.com/2"}
+	repoC := types.MinimalRepo{ID: 3, Name: "example.com/3"}
+
+	mkHead := func(repo types.MinimalRepo) *search.RepositoryRevisions {
+		return &search.RepositoryRevisions{
+			Repo: repo,
+			Revs: []string{""},
+		}
+	}
+
+	mockGitserver := gitserver.NewMockClient()
+	mockGitserver.ResolveRevisionFunc.SetDefaultHook(func(_ context.Context, repoName api.RepoName, _ string, _ gitserver.ResolveRevisionOptions) (api.CommitID, error) {
+		if repoName == repoC.Name {
+			return "", &gitdomain.RevisionNotFoundError{Repo: repoName}
+		}
+		return api.CommitID("deadbeefdeadbeefdeadbeefdeadbeefdeadbeef"), nil
+	})
+
+	// Inventories are cached by commit, so each is only computed once.
+	dominantLanguageCache.Clear()
+	var inventoryCalls atomic.Int32
+	t.Cleanup(func() {
+		require.Equal(t, int32(2), inventoryCalls.Load())
+	})
+	backend.Mocks.Repos.GetInventory = func(_ context.Context, repo *types.Repo, _ api.CommitID) (*inventory.Inventory, error) {
+		inventoryCalls.Inc()
+		switch repo.Name {
+		case repoA.Name:
+			return &inventory.Inventory{Languages: []inventory.Lang{
+				{Name: "Markdown", TotalBytes: 10},
+				{Name: "Go", TotalBytes: 100},
+			}}, nil
+		case repoB.Name:
+			return &inventory.Inventory{Languages: []inventory.Lang{
+				{Name: "Go", TotalBytes: 10},
+				{Name: "TypeScript", TotalBytes: 100},
+			}}, nil
+		default:
+			panic("unreachable")
+		}
+	}
+	t.Cleanup(func() { backend.Mocks.Repos.GetInventory = nil })
+
+	repos := database.NewMockRepoStore()
+	repos.ListMinimalReposFunc.SetDefaultReturn([]types.MinimalRepo{repoA, repoB, repoC}, nil)
+
+	db := database.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(repos)
+
+	cases := []struct {
+		name     string
+		filters  []query.RepoHasLanguageArgs
+		expected []*search.RepositoryRevisions
+		missing  int
+	}{{
+		name:    "no filters",
+		filters: nil,
+		expected: []*search.RepositoryRevisions{
+			mkHead(repoA),
+			mkHead(repoB),
+			mkHead(repoC),
+		},
+	}, {
+		name:     "dominant language",
+		filters:  []query.RepoHasLanguageArgs{{Language: "Go"}},
+		expected: []*search.RepositoryRevisions{mkHead(repoA)},
+		missing:  1,
+	}, {
+		name:     "negated dominant language",
+		filters:  []query.RepoHasLanguageArgs{{Language: "Go", Negated: true}},
+		expected: []*search.RepositoryRevisions{mkHead(repoB)},
+		missing:  1,
+	}, {
+		name:     "no dominant language",
+		filters:  []query.RepoHasLanguageArgs{{Language: "Markdown"}},
+		expected: []*search.RepositoryRevisions{},
+		missing:  1,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := NewResolver(logtest.Scoped(t), db, endpoint.Static("test"), nil)
+			res.gitserver = mockGitserver
+			resolved, err := res.Resolve(context.Background(), search.RepoOptions{
+				RepoFilters: []string{".*"},
+				HasLanguage: tc.filters,
+			})
+			if tc.missing > 0 {
+				require.True(t, errors.HasType(err, &MissingRepoRevsError{}))
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expected, resolved.RepoRevs)
+			require.Len(t, resolved.MissingRepoRevs, tc.missing)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package result
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// CompareMatch is a file whose matched lines differ between two revisions of
+// a repository. It is the result of searching with rev:base...head.
+type CompareMatch struct {
+	Repo types.MinimalRepo
+	Path string
+
+	// BaseRev and HeadRev are the revisions as specified in the query.
+	// BaseCommit and HeadCommit are the commits they resolved to, and are
+	// empty if the file has no matches at that revision.
+	BaseRev, HeadRev       string
+	BaseCommit, HeadCommit api.CommitID
+
+	// Added are the lines matched at HeadRev but not at BaseRev. Removed are
+	// the lines matched at BaseRev but not at HeadRev.
+	Added, Removed []CompareLine
+}
+
+// CompareLine is a line which was matched at only one of the revisions of a
+// CompareMatch.
+type CompareLine struct {
+	// LineNumber is the 0-based line number at the revision the line was
+	// matched at.
+	LineNumber int
+	Content    string
+}
+
+func (m *CompareMatch) RepoName() types.MinimalRepo {
+	return m.Repo
+}
+
+// Key implements Match interface's Key() method
+func (m *CompareMatch) Key() Key {
+	return Key{
+		TypeRank: rankCompareMatch,
+		Repo:     m.Repo.Name,
+		Rev:      m.BaseRev + "..." + m.HeadRev,
+		Path:     m.Path,
+	}
+}
+
+func (m *CompareMatch) ResultCount() int {
+	return len(m.Added) + len(m.Removed)
+}
+
+// Limit truncates the added lines before the removed lines.
+func (m *CompareMatch) Limit(limit int) int {
+	if len(m.Added) >= limit {
+		m.Added = m.Added[:limit]
+		m.Removed = nil
+		return 0
+	}
+	limit -= len(m.Added)
+	if len(m.Removed) >= limit {
+		m.Removed = m.Removed[:limit]
+		return 0
+	}
+	return limit - len(m.Removed)
+}
+
+func (m *CompareMatch) Select(path filter.SelectPath) Match {
+	switch path.Root() {
+	case filter.Repository:
+		return &RepoMatch{
+			Name: m.Repo.Name,
+			ID:   m.Repo.ID,
+		}
+	case filter.File:
+		headRev := m.HeadRev
+		return &FileMatch{
+			File: File{
+				Repo:     m.Repo,
+				CommitID: m.HeadCommit,
+				InputRev: &headRev,
+				Path:     m.Path,
+			},
+		}
+	case filter.Content:
+		return m
+	}
+	return nil
+}
+
+func (m *CompareMatch) searchResultMarker() {}
+
+// CompareFileMatches returns the files whose matched lines differ between the
+// file matches of a search at baseRev and the file matches of the same search
+// at headRev. Lines are compared by their content without surrounding
+// whitespace rather than by line number, so matches which only moved are not
+// reported.
+func CompareFileMatches(baseRev, headRev string, base, head []*FileMatch) []*CompareMatch {
+	type fileKey struct {
+		repo api.RepoID
+		path string
+	}
+	matches := map[fileKey]*CompareMatch{}
+	baseLines, headLines := map[fileKey][]CompareLine{}, map[fileKey][]CompareLine{}
+	collect := func(fms []*FileMatch, lines map[fileKey][]CompareLine, isBase bool) {
+		for _, fm := range fms {
+			k := fileKey{repo: fm.Repo.ID, path: fm.Path}
+			m, ok := matches[k]
+			if !ok {
+				m = &CompareMatch{Repo: fm.Repo, Path: fm.Path, BaseRev: baseRev, HeadRev: headRev}
+				matches[k] = m
+			}
+			if isBase {
+				m.BaseCommit = fm.CommitID
+			} else {
+				m.HeadCommit = fm.CommitID
+			}
+			for _, lm := range fm.ChunkMatches.AsLineMatches() {
+				lines[k] = append(lines[k], CompareLine{LineNumber: int(lm.LineNumber), Content: lm.Preview})
+			}
+		}
+	}
+	collect(base, baseLines, true)
+	collect(head, headLines, false)
+
+	res := make([]*CompareMatch, 0, len(matches))
+	for k, m := range matches {
+		m.Added = subtractLines(headLines[k], baseLines[k])
+		m.Removed = subtractLines(baseLines[k], headLines[k])
+		if m.ResultCount() > 0 {
+			res = append(res, m)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Key().Less(res[j].Key())
+	})
+	return res
+}
+
+// subtractLines returns the lines of a which remain after removing one line
+// with the same content for every line in b.
+func subtractLines(a, b []CompareLine) []CompareLine {
+	counts := make(map[string]int, len(b))
+	for _, line := range b {
+		counts[strings.TrimSpace(line.Content)]++
+	}
+	var res []CompareLine
+	for _, line := range a {
+		content := strings.TrimSpace(line.Content)
+		if counts[content] > 0 {
+			counts[content]--
+			continue
+		}
+		res = append(res, line)
+	}
+	return res
+}
@@ -0,0 +1,72 @@
+package result
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestCompareFileMatches(t *testing.T) {
+	repo := types.MinimalRepo{ID: 1, Name: "foo"}
+	// fm returns a file match at commit with one chunk per line. Lines are
+	// given as "<line number>:<content>".
+	fm := func(path string, commit api.CommitID, lines ...string) *FileMatch {
+		var chunks ChunkMatches
+		for _, line := range lines {
+			number, content, _ := strings.Cut(line, ":")
+			n := int(number[0] - '0')
+			chunks = append(chunks, ChunkMatch{
+				Content:      content,
+				ContentStart: Location{Line: n},
+				Ranges:       Ranges{{Start: Location{Line: n}, End: Location{Line: n, Column: len(content)}}},
+			})
+		}
+		return &FileMatch{File: File{Repo: repo, Path: path, CommitID: commit}, ChunkMatches: chunks}
+	}
+
+	base := []*FileMatch{
+		fm("a.go", "b1", "1:ioutil.ReadAll(r)", "5:ioutil.ReadFile(p)"),
+		fm("b.go", "b1", "2:ioutil.Discard"),
+		fm("unchanged.go", "b1", "1:ioutil.ReadAll(r)"),
+	}
+	head := []*FileMatch{
+		// ReadAll only moved and was reindented.
+		fm("a.go", "h1", "3:	ioutil.ReadAll(r)", "7:ioutil.ReadAll(w)"),
+		fm("c.go", "h1", "1:ioutil.TempDir()"),
+		fm("unchanged.go", "h1", "1:ioutil.ReadAll(r)"),
+	}
+
+	got := CompareFileMatches("main", "dev", base, head)
+	require.Equal(t, []*CompareMatch{{
+		Repo: repo, Path: "a.go", BaseRev: "main", HeadRev: "dev", BaseCommit: "b1", HeadCommit: "h1",
+		Added:   []CompareLine{{LineNumber: 7, Content: "ioutil.ReadAll(w)"}},
+		Removed: []CompareLine{{LineNumber: 5, Content: "ioutil.ReadFile(p)"}},
+	}, {
+		Repo: repo, Path: "b.go", BaseRev: "main", HeadRev: "dev", BaseCommit: "b1",
+		Removed: []CompareLine{{LineNumber: 2, Content: "ioutil.Discard"}},
+	}, {
+		Repo: repo, Path: "c.go", BaseRev: "main", HeadRev: "dev", HeadCommit: "h1",
+		Added: []CompareLine{{LineNumber: 1, Content: "ioutil.TempDir()"}},
+	}}, got)
+}
+
+func TestCompareMatchLimit(t *testing.T) {
+	m := func() *CompareMatch {
+		return &CompareMatch{
+			Added:   []CompareLine{{LineNumber: 1}, {LineNumber: 2}},
+			Removed: []CompareLine{{LineNumber: 3}},
+		}
+	}
+
+	full := m()
+	require.Equal(t, 2, full.Limit(5))
+	require.Equal(t, m(), full)
+
+	partial := m()
+	require.Equal(t, 0, partial.Limit(1))
+	require.Equal(t, &CompareMatch{Added: []CompareLine{{LineNumber: 1}}}, partial)
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
-// Match is *FileMatch | *RepoMatch | *CommitMatch. We have a private method
+// Match is *FileMatch | *RepoMatch | *CommitMatch | *CompareMatch | *OwnerMatch. We have a private method
 // to ensure only those types implement Match.
 type Match interface {
 	ResultCount() int
@@ -34,16 +34,20 @@ var (
 	_ Match = (*RepoMatch)(nil)
 	_ Match = (*CommitMatch)(nil)
 	_ Match = (*CommitDiffMatch)(nil)
+	_ Match = (*CompareMatch)(nil)
+	_ Match = (*OwnerMatch)(nil)
 )
 
 // Match ranks are used for sorting the different match types.
 // Match types with lower ranks will be sorted before match types
 // with higher ranks.
 const (
-	rankFileMatch   = 0
-	rankCommitMatch = 1
-	rankDiffMatch   = 2
-	rankRepoMatch   = 3
+	rankFileMatch    = 0
+	rankCommitMatch  = 1
+	rankDiffMatch    = 2
+	rankRepoMatch    = 3
+	rankCompareMatch = 4
+	rankOwnerMatch   = 5
 )
 
 // Key is a sorting or deduplicating key for a Match. It contains all the
@@ -74,6 +78,10 @@ type Key struct {
 	// Empty if there is no file associated with the match (e.g. RepoMatch or CommitMatch)
 	Path string
 
+	// Owner is the code owner of an OwnerMatch.
+	// Empty for all other matches.
+	Owner string
+
 	// TypeRank is the sorting rank of the type this key belongs to.
 	TypeRank int
 }
@@ -100,6 +108,10 @@ func (k Key) Less(other Key) bool {
 		return k.Path < other.Path
 	}
 
+	if k.Owner != other.Owner {
+		return k.Owner < other.Owner
+	}
+
 	return k.TypeRank < other.TypeRank
 }
 
@@ -0,0 +1,65 @@
+package result
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// Code owner types of an OwnerMatch, as in CODEOWNERS files.
+const (
+	OwnerTypeUsername = "username"
+	OwnerTypeTeam     = "team"
+	OwnerTypeEmail    = "email"
+)
+
+// OwnerMatch is a code owner of files matched by a search with
+// select:file.owners.
+type OwnerMatch struct {
+	// Handle is the username or team name of the owner without a leading
+	// "@", or their email address.
+	Handle string
+	Type   string
+
+	// FileCount is the number of matched files owned by this owner.
+	FileCount int
+}
+
+// String returns the owner as written in CODEOWNERS files.
+func (m *OwnerMatch) String() string {
+	if m.Type == OwnerTypeEmail {
+		return m.Handle
+	}
+	return "@" + m.Handle
+}
+
+// RepoName returns an empty repository, since the files of an owner can be
+// in any number of repositories.
+func (m *OwnerMatch) RepoName() types.MinimalRepo {
+	return types.MinimalRepo{}
+}
+
+// Key implements Match interface's Key() method
+func (m *OwnerMatch) Key() Key {
+	return Key{
+		TypeRank: rankOwnerMatch,
+		Owner:    m.String(),
+	}
+}
+
+func (m *OwnerMatch) ResultCount() int {
+	return 1
+}
+
+func (m *OwnerMatch) Limit(limit int) int {
+	// Always represents one result and limit > 0 so we just return limit - 1.
+	return limit - 1
+}
+
+func (m *OwnerMatch) Select(path filter.SelectPath) Match {
+	if path.Root() == filter.File && len(path) > 1 && path[1] == filter.Owners {
+		return m
+	}
+	return nil
+}
+
+func (m *OwnerMatch) searchResultMarker() {}
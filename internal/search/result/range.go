@@ -152,3 +152,47 @@ func (r Ranges) Sub(amount Location) Ranges {
 	}
 	return res
 }
+
+// lineDistance returns the number of lines between r and other. It is zero if
+// the ranges share a line.
+func (r Range) lineDistance(other Range) int {
+	switch {
+	case r.End.Line < other.Start.Line:
+		return other.Start.Line - r.End.Line
+	case other.End.Line < r.Start.Line:
+		return r.Start.Line - other.End.Line
+	default:
+		return 0
+	}
+}
+
+// Near returns the ranges of each operand which are within distance lines of
+// a range of every other operand. If any operand is left without ranges, all
+// operands are empty since the proximity constraint cannot be satisfied.
+func Near(distance int, operands ...Ranges) []Ranges {
+	res := make([]Ranges, len(operands))
+	for i, ranges := range operands {
+	RANGES:
+		for _, r := range ranges {
+			for j, other := range operands {
+				if i != j && !r.nearAny(distance, other) {
+					continue RANGES
+				}
+			}
+			res[i] = append(res[i], r)
+		}
+		if len(res[i]) == 0 {
+			return make([]Ranges, len(operands))
+		}
+	}
+	return res
+}
+
+func (r Range) nearAny(distance int, others Ranges) bool {
+	for _, other := range others {
+		if r.lineDistance(other) <= distance {
+			return true
+		}
+	}
+	return false
+}
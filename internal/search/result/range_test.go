@@ -72,3 +72,47 @@ func Test_searchRangeToHighlights(t *testing.T) {
 		})
 	}
 }
+
+func TestNear(t *testing.T) {
+	line := func(start, end int) Range {
+		return Range{Start: Location{Line: start}, End: Location{Line: end}}
+	}
+
+	cases := []struct {
+		name     string
+		distance int
+		operands []Ranges
+		want     []Ranges
+	}{{
+		name:     "within distance",
+		distance: 2,
+		operands: []Ranges{{line(1, 1), line(10, 10)}, {line(3, 3)}},
+		want:     []Ranges{{line(1, 1)}, {line(3, 3)}},
+	}, {
+		name:     "same line",
+		distance: 0,
+		operands: []Ranges{{line(4, 4)}, {line(4, 4), line(5, 5)}},
+		want:     []Ranges{{line(4, 4)}, {line(4, 4)}},
+	}, {
+		name:     "multiline range",
+		distance: 1,
+		operands: []Ranges{{line(1, 5)}, {line(6, 6)}},
+		want:     []Ranges{{line(1, 5)}, {line(6, 6)}},
+	}, {
+		name:     "too far",
+		distance: 2,
+		operands: []Ranges{{line(1, 1)}, {line(4, 4)}},
+		want:     []Ranges{nil, nil},
+	}, {
+		name:     "every operand must be near",
+		distance: 1,
+		operands: []Ranges{{line(1, 1)}, {line(2, 2)}, {line(3, 3)}},
+		want:     []Ranges{nil, nil, nil},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, Near(tc.distance, tc.operands...))
+		})
+	}
+}
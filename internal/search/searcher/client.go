@@ -70,6 +70,7 @@ func Search(
 			Limit:                        int(p.FileMatchLimit),
 			IsRegExp:                     p.IsRegExp,
 			IsStructuralPat:              p.IsStructuralPat,
+			IsPCRE:                       p.IsPCRE,
 			IsWordMatch:                  p.IsWordMatch,
 			IsCaseSensitive:              p.IsCaseSensitive,
 			PathPatternsAreCaseSensitive: p.PathPatternsAreCaseSensitive,
@@ -109,7 +110,7 @@ func Search(
 
 		tr.LazyPrintf("attempt %d: %s", attempt, url)
 		limitHit, err = textSearchStream(ctx, url, body, onMatches)
-		if err == nil || errcode.IsTimeout(err) {
+		if err == nil || errcode.IsTimeout(err) || errors.HasType(err, &search.PatternTimeoutError{}) {
 			return limitHit, err
 		}
 
@@ -178,6 +179,9 @@ func textSearchStream(ctx context.Context, url string, body []byte, cb func([]*p
 	if ed.Error != "" {
 		return false, errors.New(ed.Error)
 	}
+	if err == nil && ed.SkippedFiles > 0 {
+		err = &search.PatternTimeoutError{SkippedFiles: ed.SkippedFiles}
+	}
 	return ed.LimitHit, err
 }
 
@@ -53,4 +53,8 @@ func (rr StreamDecoder) ReadAll(r io.Reader) error {
 type EventDone struct {
 	LimitHit bool   `json:"limit_hit"`
 	Error    string `json:"error"`
+
+	// SkippedFiles is the number of files skipped because matching the
+	// pattern against them exceeded searcher's per-file limits.
+	SkippedFiles int `json:"skipped_files,omitempty"`
 }
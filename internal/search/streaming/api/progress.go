@@ -41,9 +41,10 @@ type ProgressStats struct {
 	ExcludedArchived    int
 	ExcludedForks       int
 
-	Timedout []api.RepoID
-	Missing  []api.RepoID
-	Cloning  []api.RepoID
+	Timedout        []api.RepoID
+	Missing         []api.RepoID
+	Cloning         []api.RepoID
+	PatternTimedout []api.RepoID
 
 	LimitHit bool
 
@@ -116,6 +117,15 @@ func shardTimeoutHandler(resultsResolver ProgressStats) (Skipped, bool) {
 	})
 }
 
+func patternTimeoutHandler(resultsResolver ProgressStats) (Skipped, bool) {
+	repos := resultsResolver.PatternTimedout
+	messageReason := fmt.Sprintf("%s files which could not be matched within the per-file time and memory limits of patterntype:pcre", plural("has", "have", len(repos)))
+	return skippedReposHandler(repos, resultsResolver.namer, "with skipped files", messageReason, Skipped{
+		Reason:   PatternTimeout,
+		Severity: SeverityWarn,
+	})
+}
+
 func displayLimitHandler(resultsResolver ProgressStats) (Skipped, bool) {
 	if resultsResolver.DisplayLimit >= resultsResolver.MatchCount {
 		return Skipped{}, false
@@ -204,6 +214,7 @@ var skippedHandlers = []func(stats ProgressStats) (Skipped, bool){
 	shardMatchLimitHandler,
 	// repositoryLimitHandler,
 	shardTimeoutHandler,
+	patternTimeoutHandler,
 	excludedForkHandler,
 	excludedArchiveHandler,
 	displayLimitHandler,
@@ -57,6 +57,12 @@ func TestSearchProgress(t *testing.T) {
 		"traced": {
 			Trace: "abcd",
 		},
+		"patterntimeout": {
+			MatchCount:        3,
+			RepositoriesCount: intPtr(2),
+			PatternTimedout:   []api.RepoID{1, 2},
+			DisplayLimit:      math.MaxInt32,
+		},
 	}
 
 	for name, c := range cases {
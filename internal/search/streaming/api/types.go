@@ -80,6 +80,10 @@ const (
 	// ExcludedArchive is when we did not search a repository because it is
 	// archived.
 	ExcludedArchive SkippedReason = "excluded-archive"
+	// PatternTimeout is when we skipped files in a repository because
+	// matching the pattern against them exceeded the per-file time or memory
+	// limits. This only happens for patterntype:pcre.
+	PatternTimeout SkippedReason = "pattern-timeout"
 )
 
 // SkippedSeverity is an enum for Skipped.Severity.
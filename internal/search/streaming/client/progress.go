@@ -63,6 +63,7 @@ func (p *ProgressAggregator) currentStats() api.ProgressStats {
 		Timedout:            getRepos(p.Stats, searchshared.RepoStatusTimedout),
 		Missing:             getRepos(p.Stats, searchshared.RepoStatusMissing),
 		Cloning:             getRepos(p.Stats, searchshared.RepoStatusCloning),
+		PatternTimedout:     getRepos(p.Stats, searchshared.RepoStatusPatternTimedout),
 		LimitHit:            p.Stats.IsLimitHit,
 		SuggestedLimit:      suggestedLimit,
 		Trace:               p.Trace,
@@ -127,6 +127,10 @@ func (r *eventMatchUnmarshaller) UnmarshalJSON(b []byte) error {
 		r.EventMatch = &EventSymbolMatch{}
 	case CommitMatchType:
 		r.EventMatch = &EventCommitMatch{}
+	case CompareMatchType:
+		r.EventMatch = &EventCompareMatch{}
+	case OwnerMatchType:
+		r.EventMatch = &EventOwnerMatch{}
 	default:
 		return errors.Errorf("unknown MatchType %v", typeU.Type)
 	}
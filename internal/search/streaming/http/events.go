@@ -160,6 +160,45 @@ type EventCommitMatch struct {
 
 func (e *EventCommitMatch) eventMatch() {}
 
+// EventCompareMatch is a file whose matched lines differ between the base and
+// head revision of a search with rev:base...head.
+type EventCompareMatch struct {
+	// Type is always CompareMatchType. Included here for marshalling.
+	Type MatchType `json:"type"`
+
+	Path            string     `json:"path"`
+	RepositoryID    int32      `json:"repositoryID"`
+	Repository      string     `json:"repository"`
+	RepoStars       int        `json:"repoStars,omitempty"`
+	RepoLastFetched *time.Time `json:"repoLastFetched,omitempty"`
+	Base            string     `json:"base"`
+	Head            string     `json:"head"`
+	BaseCommit      string     `json:"baseCommit,omitempty"`
+	HeadCommit      string     `json:"headCommit,omitempty"`
+
+	// Added are the lines matched at Head but not at Base, Removed the lines
+	// matched at Base but not at Head. Line numbers are 0-based.
+	Added   []EventLineMatch `json:"added"`
+	Removed []EventLineMatch `json:"removed"`
+}
+
+func (e *EventCompareMatch) eventMatch() {}
+
+// EventOwnerMatch is a code owner of the files matched by a search with
+// select:file.owners.
+type EventOwnerMatch struct {
+	// Type is always OwnerMatchType. Included here for marshalling.
+	Type MatchType `json:"type"`
+
+	// Handle is a username or team name without the leading "@", or an
+	// email address, depending on OwnerType.
+	Handle    string `json:"handle"`
+	OwnerType string `json:"ownerType"`
+	FileCount int    `json:"fileCount"`
+}
+
+func (e *EventOwnerMatch) eventMatch() {}
+
 // EventFilter is a suggestion for a search filter. Currently has a 1-1
 // correspondance with the SearchFilter graphql type.
 type EventFilter struct {
@@ -205,6 +244,8 @@ const (
 	SymbolMatchType
 	CommitMatchType
 	PathMatchType
+	CompareMatchType
+	OwnerMatchType
 )
 
 func (t MatchType) MarshalJSON() ([]byte, error) {
@@ -219,6 +260,10 @@ func (t MatchType) MarshalJSON() ([]byte, error) {
 		return []byte(`"commit"`), nil
 	case PathMatchType:
 		return []byte(`"path"`), nil
+	case CompareMatchType:
+		return []byte(`"compare"`), nil
+	case OwnerMatchType:
+		return []byte(`"owner"`), nil
 	default:
 		return nil, errors.Errorf("unknown MatchType: %d", t)
 	}
@@ -235,6 +280,10 @@ func (t *MatchType) UnmarshalJSON(b []byte) error {
 		*t = CommitMatchType
 	} else if bytes.Equal(b, []byte(`"path"`)) {
 		*t = PathMatchType
+	} else if bytes.Equal(b, []byte(`"compare"`)) {
+		*t = CompareMatchType
+	} else if bytes.Equal(b, []byte(`"owner"`)) {
+		*t = OwnerMatchType
 	} else {
 		return errors.Errorf("unknown MatchType: %s", b)
 	}
@@ -0,0 +1,255 @@
+package http
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming/api"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ExportFormat is the serialization used when exporting search results.
+type ExportFormat string
+
+const (
+	// ExportJSONL writes one JSON encoded EventMatch per line.
+	ExportJSONL ExportFormat = "jsonl"
+	// ExportCSV writes one row per matched line, symbol, path, repository
+	// or commit.
+	ExportCSV ExportFormat = "csv"
+)
+
+// ParseExportFormat returns the ExportFormat named by s. It is case
+// insensitive.
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch f := ExportFormat(strings.ToLower(s)); f {
+	case ExportJSONL, ExportCSV:
+		return f, nil
+	default:
+		return "", errors.Errorf("unsupported export format %q, expected %q or %q", s, ExportJSONL, ExportCSV)
+	}
+}
+
+// ExportTrailerType is the type of the final record of an export.
+const ExportTrailerType = "trailer"
+
+// EventExportTrailer is the last record written by an ExportWriter. It
+// summarizes the search which produced the preceding records, including the
+// repositories which were skipped.
+type EventExportTrailer struct {
+	// Type is always ExportTrailerType. Included here for marshalling.
+	Type string `json:"type"`
+
+	Progress api.Progress `json:"progress"`
+	Alert    *EventAlert  `json:"alert,omitempty"`
+	Error    string       `json:"error,omitempty"`
+}
+
+// exportCSVHeader is the header row of CSV exports. Columns which do not
+// apply to a match type are left empty.
+var exportCSVHeader = []string{"type", "repository", "commit", "path", "line", "content", "kind", "author", "date", "url"}
+
+// ExportWriter writes search results to an HTTP response as JSONL or CSV.
+// Unlike Writer it does not frame records as events, so the response can be
+// consumed by standard tooling.
+type ExportWriter struct {
+	format ExportFormat
+	w      *bufio.Writer
+	csv    *csv.Writer
+	flush  func()
+
+	wroteHeader bool
+}
+
+func NewExportWriter(w http.ResponseWriter, format ExportFormat) (*ExportWriter, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, errors.New("http flushing not supported")
+	}
+
+	switch format {
+	case ExportJSONL:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	case ExportCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	default:
+		return nil, errors.Errorf("unsupported export format %q", format)
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename="search-results.`+string(format)+`"`)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	bw := bufio.NewWriter(w)
+	return &ExportWriter{
+		format: format,
+		w:      bw,
+		csv:    csv.NewWriter(bw),
+		flush:  flusher.Flush,
+	}, nil
+}
+
+// Match writes the records for match.
+func (e *ExportWriter) Match(match EventMatch) error {
+	if e.format == ExportJSONL {
+		return e.writeJSON(match)
+	}
+	for _, row := range csvRows(match) {
+		if err := e.writeRow(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Trailer writes the final record of the export and flushes the response.
+// For CSV exports the trailer is written as JSON in the content column.
+func (e *ExportWriter) Trailer(trailer EventExportTrailer) error {
+	trailer.Type = ExportTrailerType
+	if e.format == ExportJSONL {
+		if err := e.writeJSON(trailer); err != nil {
+			return err
+		}
+	} else {
+		encoded, err := json.Marshal(trailer)
+		if err != nil {
+			return err
+		}
+		if err := e.writeRow(exportRow{typ: ExportTrailerType, content: string(encoded)}); err != nil {
+			return err
+		}
+	}
+	return e.Flush()
+}
+
+// Flush writes any buffered records to the response.
+func (e *ExportWriter) Flush() error {
+	e.csv.Flush()
+	if err := e.csv.Error(); err != nil {
+		return err
+	}
+	if err := e.w.Flush(); err != nil {
+		return err
+	}
+	e.flush()
+	return nil
+}
+
+func (e *ExportWriter) writeJSON(v any) error {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := e.w.Write(encoded); err != nil {
+		return err
+	}
+	_, err = e.w.Write([]byte("\n"))
+	return err
+}
+
+func (e *ExportWriter) writeRow(row exportRow) error {
+	if !e.wroteHeader {
+		e.wroteHeader = true
+		if err := e.csv.Write(exportCSVHeader); err != nil {
+			return err
+		}
+	}
+	return e.csv.Write(row.fields())
+}
+
+// exportRow is a single CSV record. Its fields correspond to
+// exportCSVHeader.
+type exportRow struct {
+	typ        string
+	repository string
+	commit     string
+	path       string
+	line       int32 // 1-based, 0 if not applicable
+	content    string
+	kind       string
+	author     string
+	date       time.Time
+	url        string
+}
+
+func (r exportRow) fields() []string {
+	var line, date string
+	if r.line > 0 {
+		line = strconv.Itoa(int(r.line))
+	}
+	if !r.date.IsZero() {
+		date = r.date.UTC().Format(time.RFC3339)
+	}
+	return []string{r.typ, r.repository, r.commit, r.path, line, r.content, r.kind, r.author, date, r.url}
+}
+
+func csvRows(match EventMatch) []exportRow {
+	switch v := match.(type) {
+	case *EventContentMatch:
+		base := exportRow{typ: "content", repository: v.Repository, commit: v.Commit, path: v.Path}
+		rows := make([]exportRow, 0, len(v.LineMatches)+len(v.ChunkMatches))
+		for _, lm := range v.LineMatches {
+			row := base
+			row.line = lm.LineNumber + 1
+			row.content = lm.Line
+			rows = append(rows, row)
+		}
+		for _, cm := range v.ChunkMatches {
+			row := base
+			row.line = int32(cm.ContentStart.Line) + 1
+			row.content = cm.Content
+			rows = append(rows, row)
+		}
+		return rows
+	case *EventPathMatch:
+		return []exportRow{{typ: "path", repository: v.Repository, commit: v.Commit, path: v.Path}}
+	case *EventSymbolMatch:
+		rows := make([]exportRow, 0, len(v.Symbols))
+		for _, sym := range v.Symbols {
+			rows = append(rows, exportRow{
+				typ:        "symbol",
+				repository: v.Repository,
+				commit:     v.Commit,
+				path:       v.Path,
+				line:       sym.Line,
+				content:    sym.Name,
+				kind:       sym.Kind,
+				url:        sym.URL,
+			})
+		}
+		return rows
+	case *EventRepoMatch:
+		return []exportRow{{typ: "repo", repository: v.Repository, content: v.Description}}
+	case *EventCompareMatch:
+		rows := make([]exportRow, 0, len(v.Added)+len(v.Removed))
+		for _, lm := range v.Added {
+			rows = append(rows, exportRow{typ: "compare", repository: v.Repository, commit: v.HeadCommit, path: v.Path, line: lm.LineNumber + 1, content: lm.Line, kind: "added"})
+		}
+		for _, lm := range v.Removed {
+			rows = append(rows, exportRow{typ: "compare", repository: v.Repository, commit: v.BaseCommit, path: v.Path, line: lm.LineNumber + 1, content: lm.Line, kind: "removed"})
+		}
+		return rows
+	case *EventOwnerMatch:
+		owner := "@" + v.Handle
+		if v.OwnerType == "email" {
+			owner = v.Handle
+		}
+		return []exportRow{{typ: "owner", content: owner, kind: v.OwnerType}}
+	case *EventCommitMatch:
+		return []exportRow{{
+			typ:        "commit",
+			repository: v.Repository,
+			commit:     v.OID,
+			content:    v.Message,
+			author:     v.AuthorName,
+			date:       v.AuthorDate,
+			url:        v.URL,
+		}}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming/api"
+)
+
+func TestParseExportFormat(t *testing.T) {
+	for in, want := range map[string]ExportFormat{
+		"jsonl": ExportJSONL,
+		"CSV":   ExportCSV,
+	} {
+		got, err := ParseExportFormat(in)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+
+	_, err := ParseExportFormat("xml")
+	require.Error(t, err)
+}
+
+func TestExportWriter(t *testing.T) {
+	matches := []EventMatch{
+		&EventContentMatch{
+			Type:       ContentMatchType,
+			Repository: "github.com/foo/bar",
+			Commit:     "deadbeef",
+			Path:       "main.go",
+			LineMatches: []EventLineMatch{
+				{Line: "func main() {", LineNumber: 2},
+				{Line: `fmt.Println("a, b")`, LineNumber: 3},
+			},
+		},
+		&EventSymbolMatch{
+			Type:       SymbolMatchType,
+			Repository: "github.com/foo/bar",
+			Path:       "main.go",
+			Symbols:    []Symbol{{Name: "main", Kind: "FUNCTION", Line: 3, URL: "/github.com/foo/bar/-/blob/main.go#L3"}},
+		},
+		&EventCommitMatch{
+			Type:       CommitMatchType,
+			Repository: "github.com/foo/bar",
+			OID:        "deadbeef",
+			Message:    "initial commit",
+			AuthorName: "alice",
+			AuthorDate: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+			URL:        "/github.com/foo/bar/-/commit/deadbeef",
+		},
+		&EventRepoMatch{Type: RepoMatchType, Repository: "github.com/foo/baz"},
+		&EventCompareMatch{
+			Type:       CompareMatchType,
+			Repository: "github.com/foo/bar",
+			Path:       "main.go",
+			Base:       "main",
+			Head:       "dev",
+			BaseCommit: "b1",
+			HeadCommit: "h1",
+			Added:      []EventLineMatch{{Line: "ioutil.ReadAll(r)", LineNumber: 4}},
+			Removed:    []EventLineMatch{{Line: "io.ReadAll(r)", LineNumber: 6}},
+		},
+		&EventOwnerMatch{Type: OwnerMatchType, Handle: "sourcegraph/search", OwnerType: "team", FileCount: 3},
+	}
+	trailer := EventExportTrailer{
+		Progress: api.Progress{
+			MatchCount: 4,
+			Skipped:    []api.Skipped{{Reason: api.ShardTimeout, Title: "1 timed out"}},
+		},
+	}
+
+	write := func(format ExportFormat) string {
+		rec := httptest.NewRecorder()
+		w, err := NewExportWriter(rec, format)
+		require.NoError(t, err)
+		for _, m := range matches {
+			require.NoError(t, w.Match(m))
+		}
+		require.NoError(t, w.Trailer(trailer))
+		return rec.Body.String()
+	}
+
+	t.Run("jsonl", func(t *testing.T) {
+		want := `{"type":"content","path":"main.go","repositoryID":0,"repository":"github.com/foo/bar","commit":"deadbeef","hunks":null,"lineMatches":[{"line":"func main() {","lineNumber":2,"offsetAndLengths":null},{"line":"fmt.Println(\"a, b\")","lineNumber":3,"offsetAndLengths":null}]}
+{"type":"symbol","path":"main.go","repositoryID":0,"repository":"github.com/foo/bar","symbols":[{"url":"/github.com/foo/bar/-/blob/main.go#L3","name":"main","containerName":"","kind":"FUNCTION","line":3}]}
+{"type":"commit","label":"","url":"/github.com/foo/bar/-/commit/deadbeef","detail":"","repositoryID":0,"repository":"github.com/foo/bar","oid":"deadbeef","message":"initial commit","authorName":"alice","authorDate":"2022-01-02T03:04:05Z","content":"","ranges":null}
+{"type":"repo","repositoryID":0,"repository":"github.com/foo/baz"}
+{"type":"compare","path":"main.go","repositoryID":0,"repository":"github.com/foo/bar","base":"main","head":"dev","baseCommit":"b1","headCommit":"h1","added":[{"line":"ioutil.ReadAll(r)","lineNumber":4,"offsetAndLengths":null}],"removed":[{"line":"io.ReadAll(r)","lineNumber":6,"offsetAndLengths":null}]}
+{"type":"owner","handle":"sourcegraph/search","ownerType":"team","fileCount":3}
+{"type":"trailer","progress":{"done":false,"matchCount":4,"durationMs":0,"skipped":[{"reason":"shard-timeout","title":"1 timed out","message":"","severity":""}]}}
+`
+		require.Equal(t, want, write(ExportJSONL))
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		want := `type,repository,commit,path,line,content,kind,author,date,url
+content,github.com/foo/bar,deadbeef,main.go,3,func main() {,,,,
+content,github.com/foo/bar,deadbeef,main.go,4,"fmt.Println(""a, b"")",,,,
+symbol,github.com/foo/bar,,main.go,3,main,FUNCTION,,,/github.com/foo/bar/-/blob/main.go#L3
+commit,github.com/foo/bar,deadbeef,,,initial commit,,alice,2022-01-02T03:04:05Z,/github.com/foo/bar/-/commit/deadbeef
+repo,github.com/foo/baz,,,,,,,,
+compare,github.com/foo/bar,h1,main.go,5,ioutil.ReadAll(r),added,,,
+compare,github.com/foo/bar,b1,main.go,7,io.ReadAll(r),removed,,,
+owner,,,,,@sourcegraph/search,team,,,
+trailer,,,,,"{""type"":""trailer"",""progress"":{""done"":false,""matchCount"":4,""durationMs"":0,""skipped"":[{""reason"":""shard-timeout"",""title"":""1 timed out"",""message"":"""",""severity"":""""}]}}",,,,
+`
+		require.Equal(t, want, write(ExportCSV))
+	})
+}
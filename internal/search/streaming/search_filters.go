@@ -136,6 +136,11 @@ func (s *SearchFilters) Update(event SearchEvent) {
 			// We leave "rev" empty, instead of using "CommitMatch.Commit.ID". This way we
 			// get 1 filter per repo instead of 1 filter per sha in the side-bar.
 			addRepoFilter(v.Repo.Name, v.Repo.ID, "", int32(v.ResultCount()))
+		case *result.CompareMatch:
+			lines := int32(v.ResultCount())
+			addRepoFilter(v.Repo.Name, v.Repo.ID, v.BaseRev+"..."+v.HeadRev, lines)
+			addLangFilter(v.Path, lines, false)
+			addFileFilter(v.Path, lines, false)
 		}
 	}
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	zoektutil "github.com/sourcegraph/sourcegraph/internal/search/zoekt"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -156,14 +157,53 @@ type SearchJob struct {
 	BatchRetry       bool
 
 	RepoOpts search.RepoOptions
+
+	// Resolved is the first page of repositories to search if it was
+	// resolved in advance, e.g. while estimating the cost of the search. If
+	// nil, the repositories of RepoOpts are resolved when the job runs.
+	Resolved *ResolvedRepos
+
+	// ResolvedNext is the cursor of the page after Resolved, or nil if
+	// Resolved is the last page.
+	ResolvedNext types.MultiCursor
+
+	// ResolvedMissing is the non-fatal error reporting the revisions missing
+	// from Resolved, if any.
+	ResolvedMissing error
+}
+
+// ResolvedRepos is a page of repositories searched by a SearchJob,
+// partitioned into the repositories indexed by zoekt and the others.
+type ResolvedRepos struct {
+	Indexed   *zoektutil.IndexedRepoRevs
+	Unindexed []*search.RepositoryRevisions
 }
 
 func (s *SearchJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
 	_, ctx, stream, finish := job.StartSpan(ctx, stream, s)
 	defer func() { finish(alert, err) }()
 
+	searchPage := func(indexed *zoektutil.IndexedRepoRevs, unindexed []*search.RepositoryRevisions) error {
+		repoSet := []repoData{UnindexedList(unindexed)}
+		if indexed != nil {
+			repoSet = append(repoSet, IndexedMap(indexed.RepoRevs))
+		}
+		return runStructuralSearch(ctx, clients, s.SearcherArgs, s.BatchRetry, repoSet, stream)
+	}
+
+	repoOpts := s.RepoOpts
+	if s.Resolved != nil {
+		if err := searchPage(s.Resolved.Indexed, s.Resolved.Unindexed); err != nil {
+			return nil, errors.Append(s.ResolvedMissing, err)
+		}
+		if s.ResolvedNext == nil {
+			return nil, s.ResolvedMissing
+		}
+		repoOpts.Cursors = s.ResolvedNext
+	}
+
 	repos := searchrepos.NewResolver(clients.Logger, clients.DB, clients.SearcherURLs, clients.Zoekt)
-	return nil, repos.Paginate(ctx, s.RepoOpts, func(page *searchrepos.Resolved) error {
+	err = repos.Paginate(ctx, repoOpts, func(page *searchrepos.Resolved) error {
 		indexed, unindexed, err := zoektutil.PartitionRepos(
 			ctx,
 			clients.Logger,
@@ -176,13 +216,12 @@ func (s *SearchJob) Run(ctx context.Context, clients job.RuntimeClients, stream
 		if err != nil {
 			return err
 		}
-
-		repoSet := []repoData{UnindexedList(unindexed)}
-		if indexed != nil {
-			repoSet = append(repoSet, IndexedMap(indexed.RepoRevs))
-		}
-		return runStructuralSearch(ctx, clients, s.SearcherArgs, s.BatchRetry, repoSet, stream)
+		return searchPage(indexed, unindexed)
 	})
+	if s.ResolvedMissing != nil {
+		return nil, errors.Append(s.ResolvedMissing, err)
+	}
+	return nil, err
 }
 
 func (*SearchJob) Name() string {
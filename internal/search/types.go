@@ -24,6 +24,7 @@ type Inputs struct {
 	Plan                query.Plan // the comprehensive query plan
 	Query               query.Q    // the current basic query being evaluated, one part of query.Plan
 	OriginalQuery       string     // the raw string of the original search query
+	ExpandedMacros      []string   // the names of query macros expanded in OriginalQuery
 	PatternType         query.SearchType
 	UserSettings        *schema.Settings
 	OnSourcegraphDotCom bool
@@ -38,8 +39,11 @@ func (inputs Inputs) MaxResults() int {
 
 // DefaultLimit is the default limit to use if not specified in query.
 func (inputs Inputs) DefaultLimit() int {
-	if inputs.Protocol == Batch {
+	switch inputs.Protocol {
+	case Batch:
 		return limits.DefaultMaxSearchResults
+	case Export:
+		return limits.DefaultMaxSearchResultsExport
 	}
 	return limits.DefaultMaxSearchResultsStreaming
 }
@@ -49,6 +53,9 @@ type Protocol int
 const (
 	Streaming Protocol = iota
 	Batch
+	// Export streams results which are written to a file rather than
+	// displayed.
+	Export
 )
 
 func (p Protocol) String() string {
@@ -57,6 +64,8 @@ func (p Protocol) String() string {
 		return "Streaming"
 	case Batch:
 		return "Batch"
+	case Export:
+		return "Export"
 	default:
 		return fmt.Sprintf("unknown{%d}", p)
 	}
@@ -185,6 +194,7 @@ type TextPatternInfo struct {
 	IsNegated       bool
 	IsRegExp        bool
 	IsStructuralPat bool
+	IsPCRE          bool
 	CombyRule       string
 	IsWordMatch     bool
 	IsCaseSensitive bool
@@ -222,6 +232,9 @@ func (p *TextPatternInfo) Fields() []otlog.Field {
 	if p.IsStructuralPat {
 		add(otlog.Bool("isStructural", p.IsStructuralPat))
 	}
+	if p.IsPCRE {
+		add(otlog.Bool("isPCRE", p.IsPCRE))
+	}
 	if p.CombyRule != "" {
 		add(otlog.String("combyRule", p.CombyRule))
 	}
@@ -261,6 +274,9 @@ func (p *TextPatternInfo) String() string {
 	if p.IsRegExp {
 		args = append(args, "re")
 	}
+	if p.IsPCRE {
+		args = append(args, "pcre")
+	}
 	if p.IsStructuralPat {
 		if p.CombyRule != "" {
 			args = append(args, fmt.Sprintf("comby:%s", p.CombyRule))
@@ -365,6 +381,8 @@ type RepoOptions struct {
 	UseIndex       query.YesNoOnly
 	HasFileContent []query.RepoHasFileContentArgs
 	HasKVPs        []query.RepoKVPFilter
+	HasSymbol      []query.RepoHasSymbolArgs
+	HasLanguage    []query.RepoHasLanguageArgs
 
 	// ForkSet indicates whether `fork:` was set explicitly in the query,
 	// or whether the values were set from defaults.
@@ -447,6 +465,24 @@ func (op *RepoOptions) Tags() []otlog.Field {
 			add(trace.Scoped(fmt.Sprintf("hasKVPs[%d]", i), nondefault...))
 		}
 	}
+	if len(op.HasSymbol) > 0 {
+		for i, arg := range op.HasSymbol {
+			nondefault := []otlog.Field{otlog.String("pattern", arg.Pattern)}
+			if arg.Negated {
+				nondefault = append(nondefault, otlog.Bool("negated", arg.Negated))
+			}
+			add(trace.Scoped(fmt.Sprintf("hasSymbol[%d]", i), nondefault...))
+		}
+	}
+	if len(op.HasLanguage) > 0 {
+		for i, arg := range op.HasLanguage {
+			nondefault := []otlog.Field{otlog.String("language", arg.Language)}
+			if arg.Negated {
+				nondefault = append(nondefault, otlog.Bool("negated", arg.Negated))
+			}
+			add(trace.Scoped(fmt.Sprintf("hasLanguage[%d]", i), nondefault...))
+		}
+	}
 	if op.ForkSet {
 		add(otlog.Bool("forkSet", op.ForkSet))
 	}
@@ -521,6 +557,22 @@ func (op *RepoOptions) String() string {
 			}
 		}
 	}
+	if len(op.HasSymbol) > 0 {
+		for i, arg := range op.HasSymbol {
+			fmt.Fprintf(&b, "HasSymbol[%d].pattern: %s\n", i, arg.Pattern)
+			if arg.Negated {
+				fmt.Fprintf(&b, "HasSymbol[%d].negated: %t\n", i, arg.Negated)
+			}
+		}
+	}
+	if len(op.HasLanguage) > 0 {
+		for i, arg := range op.HasLanguage {
+			fmt.Fprintf(&b, "HasLanguage[%d].language: %s\n", i, arg.Language)
+			if arg.Negated {
+				fmt.Fprintf(&b, "HasLanguage[%d].negated: %t\n", i, arg.Negated)
+			}
+		}
+	}
 
 	if op.CaseSensitiveRepoFilters {
 		fmt.Fprintf(&b, "CaseSensitiveRepoFilters: %t\n", op.CaseSensitiveRepoFilters)
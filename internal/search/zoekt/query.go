@@ -64,6 +64,9 @@ func QueryToZoektQuery(b query.Basic, resultTypes result.Types, feat *search.Fea
 	for _, filter := range b.RepoHasFileContent() {
 		repoHasFilters = append(repoHasFilters, QueryForFileContentArgs(filter, isCaseSensitive))
 	}
+	for _, filter := range b.RepoContainsSymbol() {
+		repoHasFilters = append(repoHasFilters, QueryForSymbolArgs(filter, isCaseSensitive))
+	}
 	if len(repoHasFilters) > 0 {
 		and = append(and, zoekt.NewAnd(repoHasFilters...))
 	}
@@ -107,6 +110,18 @@ func QueryForFileContentArgs(opt query.RepoHasFileContentArgs, caseSensitive boo
 	return q
 }
 
+// QueryForSymbolArgs returns a query matching repositories that define a
+// symbol matching opt.Pattern.
+func QueryForSymbolArgs(opt query.RepoHasSymbolArgs, caseSensitive bool) zoekt.Q {
+	re, _ := syntax.Parse(opt.Pattern, syntax.Perl)
+	var q zoekt.Q = &zoekt.Symbol{Expr: &zoekt.Regexp{Regexp: re, Content: true, CaseSensitive: caseSensitive}}
+	q = &zoekt.Type{Type: zoekt.TypeRepo, Child: q}
+	if opt.Negated {
+		q = &zoekt.Not{Child: q}
+	}
+	return zoekt.Simplify(q)
+}
+
 func toZoektPattern(
 	expression query.Node, isCaseSensitive, patternMatchesContent, patternMatchesPath bool, typ search.IndexedRequestType) (zoekt.Q, error) {
 	var fold func(node query.Node) (zoekt.Q, error)
@@ -10,6 +10,12 @@ type BitbucketServerConnection struct {
 	*schema.BitbucketServerConnection
 }
 
+type BitbucketCloudConnection struct {
+	// The unique resource identifier of the external service.
+	URN string
+	*schema.BitbucketCloudConnection
+}
+
 type GitHubConnection struct {
 	// The unique resource identifier of the external service.
 	URN string
@@ -33,3 +39,15 @@ type GerritConnection struct {
 	URN string
 	*schema.GerritConnection
 }
+
+type AzureDevOpsConnection struct {
+	// The unique resource identifier of the external service.
+	URN string
+	*schema.AzureDevOpsConnection
+}
+
+type GiteaConnection struct {
+	// The unique resource identifier of the external service.
+	URN string
+	*schema.GiteaConnection
+}
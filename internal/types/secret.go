@@ -12,6 +12,7 @@ import (
 	"context"
 	"net/url"
 	"reflect"
+	"strings"
 
 	"github.com/sourcegraph/jsonx"
 
@@ -42,14 +43,32 @@ func (e *ExternalService) RedactedConfig(ctx context.Context) (string, error) {
 	switch c := cfg.(type) {
 	case *schema.GitHubConnection:
 		es.redactString(c.Token, "token")
+		if c.MirrorTo != nil {
+			err = es.redactURL(c.MirrorTo.Url, "mirrorTo", "url")
+			if err != nil {
+				return "", err
+			}
+		}
 	case *schema.GitLabConnection:
 		es.redactString(c.Token, "token")
 		es.redactString(c.TokenOauthRefresh, "token.oauth.refresh")
+		if c.MirrorTo != nil {
+			err = es.redactURL(c.MirrorTo.Url, "mirrorTo", "url")
+			if err != nil {
+				return "", err
+			}
+		}
 	case *schema.GerritConnection:
 		es.redactString(c.Password, "password")
 	case *schema.BitbucketServerConnection:
 		es.redactString(c.Password, "password")
 		es.redactString(c.Token, "token")
+		if c.MirrorTo != nil {
+			err = es.redactURL(c.MirrorTo.Url, "mirrorTo", "url")
+			if err != nil {
+				return "", err
+			}
+		}
 	case *schema.BitbucketCloudConnection:
 		es.redactString(c.AppPassword, "appPassword")
 	case *schema.AWSCodeCommitConnection:
@@ -59,6 +78,8 @@ func (e *ExternalService) RedactedConfig(ctx context.Context) (string, error) {
 		es.redactString(c.Token, "token")
 	case *schema.PerforceConnection:
 		es.redactString(c.P4Passwd, "p4.passwd")
+	case *schema.SubversionConnection:
+		es.redactString(c.Password, "password")
 	case *schema.GitoliteConnection:
 		// Nothing to redact
 	case *schema.GoModulesConnection:
@@ -83,6 +104,10 @@ func (e *ExternalService) RedactedConfig(ctx context.Context) (string, error) {
 		}
 	case *schema.PagureConnection:
 		es.redactString(c.Token, "token")
+	case *schema.GiteaConnection:
+		es.redactString(c.Token, "token")
+	case *schema.AzureDevOpsConnection:
+		es.redactString(c.Token, "token")
 	case *schema.NpmPackagesConnection:
 		es.redactString(c.Credentials, "credentials")
 	case *schema.OtherExternalServiceConnection:
@@ -90,6 +115,17 @@ func (e *ExternalService) RedactedConfig(ctx context.Context) (string, error) {
 		if err != nil {
 			return "", err
 		}
+		if c.MirrorTo != nil {
+			err = es.redactURL(c.MirrorTo.Url, "mirrorTo", "url")
+			if err != nil {
+				return "", err
+			}
+		}
+	case *schema.MercurialConnection:
+		err = es.redactURL(c.Url, "url")
+		if err != nil {
+			return "", err
+		}
 	default:
 		// return an error; it's safer to fail than to incorrectly return unsafe data.
 		return "", errors.Errorf("Unrecognized ExternalServiceConfig for redaction: kind %+v not implemented", reflect.TypeOf(cfg))
@@ -139,14 +175,32 @@ func (e *ExternalService) UnredactConfig(ctx context.Context, old *ExternalServi
 	case *schema.GitHubConnection:
 		o := oldCfg.(*schema.GitHubConnection)
 		es.unredactString(c.Token, o.Token, "token")
+		if c.MirrorTo != nil && o.MirrorTo != nil {
+			err = es.unredactURL(c.MirrorTo.Url, o.MirrorTo.Url, "mirrorTo", "url")
+			if err != nil {
+				return err
+			}
+		}
 	case *schema.GitLabConnection:
 		o := oldCfg.(*schema.GitLabConnection)
 		es.unredactString(c.Token, o.Token, "token")
 		es.unredactString(c.TokenOauthRefresh, o.TokenOauthRefresh, "token.oauth.refresh")
+		if c.MirrorTo != nil && o.MirrorTo != nil {
+			err = es.unredactURL(c.MirrorTo.Url, o.MirrorTo.Url, "mirrorTo", "url")
+			if err != nil {
+				return err
+			}
+		}
 	case *schema.BitbucketServerConnection:
 		o := oldCfg.(*schema.BitbucketServerConnection)
 		es.unredactString(c.Password, o.Password, "password")
 		es.unredactString(c.Token, o.Token, "token")
+		if c.MirrorTo != nil && o.MirrorTo != nil {
+			err = es.unredactURL(c.MirrorTo.Url, o.MirrorTo.Url, "mirrorTo", "url")
+			if err != nil {
+				return err
+			}
+		}
 	case *schema.BitbucketCloudConnection:
 		o := oldCfg.(*schema.BitbucketCloudConnection)
 		es.unredactString(c.AppPassword, o.AppPassword, "appPassword")
@@ -160,6 +214,9 @@ func (e *ExternalService) UnredactConfig(ctx context.Context, old *ExternalServi
 	case *schema.PerforceConnection:
 		o := oldCfg.(*schema.PerforceConnection)
 		es.unredactString(c.P4Passwd, o.P4Passwd, "p4.passwd")
+	case *schema.SubversionConnection:
+		o := oldCfg.(*schema.SubversionConnection)
+		es.unredactString(c.Password, o.Password, "password")
 	case *schema.GitoliteConnection:
 		// Nothing to redact
 	case *schema.GoModulesConnection:
@@ -182,6 +239,12 @@ func (e *ExternalService) UnredactConfig(ctx context.Context, old *ExternalServi
 	case *schema.PagureConnection:
 		o := oldCfg.(*schema.PagureConnection)
 		es.unredactString(c.Token, o.Token, "token")
+	case *schema.GiteaConnection:
+		o := oldCfg.(*schema.GiteaConnection)
+		es.unredactString(c.Token, o.Token, "token")
+	case *schema.AzureDevOpsConnection:
+		o := oldCfg.(*schema.AzureDevOpsConnection)
+		es.unredactString(c.Token, o.Token, "token")
 	case *schema.NpmPackagesConnection:
 		o := oldCfg.(*schema.NpmPackagesConnection)
 		es.unredactString(c.Credentials, o.Credentials, "credentials")
@@ -191,6 +254,18 @@ func (e *ExternalService) UnredactConfig(ctx context.Context, old *ExternalServi
 		if err != nil {
 			return err
 		}
+		if c.MirrorTo != nil && o.MirrorTo != nil {
+			err = es.unredactURL(c.MirrorTo.Url, o.MirrorTo.Url, "mirrorTo", "url")
+			if err != nil {
+				return err
+			}
+		}
+	case *schema.MercurialConnection:
+		o := oldCfg.(*schema.MercurialConnection)
+		err = es.unredactURL(c.Url, o.Url, "url")
+		if err != nil {
+			return err
+		}
 	default:
 		// return an error; it's safer to fail than to incorrectly return unsafe data.
 		return errors.Errorf("Unrecognized ExternalServiceConfig for redaction: kind %+v not implemented", reflect.TypeOf(newCfg))
@@ -329,8 +404,7 @@ func redactedURL(rawURL string) (string, error) {
 		return rawURL, nil
 	}
 
-	redacted.User = url.UserPassword(redacted.User.Username(), RedactedSecret)
-	return redacted.String(), nil
+	return replaceUserinfo(rawURL, url.UserPassword(redacted.User.Username(), RedactedSecret)), nil
 }
 
 func unredactedURL(new, old string) (string, error) {
@@ -351,10 +425,26 @@ func unredactedURL(new, old string) (string, error) {
 
 	oldPasswd, _ := oldURL.User.Password()
 	if oldPasswd != "" {
-		newURL.User = url.UserPassword(newURL.User.Username(), oldPasswd)
-	} else {
-		newURL.User = url.User(newURL.User.Username())
+		return replaceUserinfo(new, url.UserPassword(newURL.User.Username(), oldPasswd)), nil
 	}
+	return replaceUserinfo(new, url.User(newURL.User.Username())), nil
+}
 
-	return newURL.String(), nil
+// replaceUserinfo replaces the userinfo of rawURL, which must have one, with
+// u. The rest of rawURL is kept as written, because re-encoding it would
+// escape placeholders such as the {repo} in mirrorTo URLs.
+func replaceUserinfo(rawURL string, u *url.Userinfo) string {
+	scheme, rest, ok := strings.Cut(rawURL, "://")
+	if !ok {
+		return rawURL
+	}
+	authority := rest
+	if i := strings.IndexAny(rest, "/?#"); i >= 0 {
+		authority = rest[:i]
+	}
+	at := strings.LastIndex(authority, "@")
+	if at < 0 {
+		return rawURL
+	}
+	return scheme + "://" + u.String() + rest[at:]
 }
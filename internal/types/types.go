@@ -554,7 +554,16 @@ type GitserverRepo struct {
 	LastChanged time.Time
 	// Size of the repository in bytes.
 	RepoSizeBytes int64
-	UpdatedAt     time.Time
+	// The key of the bundle in object storage the repository was offloaded to, or
+	// empty if it was not offloaded.
+	OffloadKey string
+	// The gitserver shards holding an in-sync replica of the repository.
+	ReplicaShardIDs []string
+	// The last time the repository was pushed to its push mirror.
+	PushMirrorLastPushedAt time.Time
+	// The error of the last push to the push mirror, or empty if it succeeded.
+	PushMirrorLastError string
+	UpdatedAt           time.Time
 }
 
 // ExternalService is a connection to an external service.
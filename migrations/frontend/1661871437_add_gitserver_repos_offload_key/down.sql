@@ -0,0 +1 @@
+ALTER TABLE gitserver_repos DROP COLUMN IF EXISTS offload_key;
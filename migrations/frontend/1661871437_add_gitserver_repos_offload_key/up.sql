@@ -0,0 +1,3 @@
+ALTER TABLE gitserver_repos ADD COLUMN IF NOT EXISTS offload_key text;
+
+COMMENT ON COLUMN gitserver_repos.offload_key IS 'Key of the bundle in object storage the repository was offloaded to when it was removed from disk. NULL if the repository has not been offloaded.';
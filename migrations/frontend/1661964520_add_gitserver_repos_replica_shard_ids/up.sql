@@ -0,0 +1,3 @@
+ALTER TABLE gitserver_repos ADD COLUMN IF NOT EXISTS replica_shard_ids text[];
+
+COMMENT ON COLUMN gitserver_repos.replica_shard_ids IS 'The gitserver shards holding an in-sync replica of the repository, in addition to the shard in shard_id. NULL if the repository has no replicas.';
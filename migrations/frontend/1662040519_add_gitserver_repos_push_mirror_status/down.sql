@@ -0,0 +1,2 @@
+ALTER TABLE gitserver_repos DROP COLUMN IF EXISTS push_mirror_last_pushed_at;
+ALTER TABLE gitserver_repos DROP COLUMN IF EXISTS push_mirror_last_error;
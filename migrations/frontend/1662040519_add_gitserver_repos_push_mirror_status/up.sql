@@ -0,0 +1,5 @@
+ALTER TABLE gitserver_repos ADD COLUMN IF NOT EXISTS push_mirror_last_pushed_at timestamp with time zone;
+ALTER TABLE gitserver_repos ADD COLUMN IF NOT EXISTS push_mirror_last_error text;
+
+COMMENT ON COLUMN gitserver_repos.push_mirror_last_pushed_at IS 'When the repository was last successfully pushed to the secondary remote configured with mirrorTo on its code host. NULL if it was never pushed.';
+COMMENT ON COLUMN gitserver_repos.push_mirror_last_error IS 'The error of the last failed push of the repository to the secondary remote configured with mirrorTo on its code host. NULL if the last push succeeded.';
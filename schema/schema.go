@@ -100,12 +100,13 @@ type AuthProviderCommon struct {
 	DisplayName string `json:"displayName,omitempty"`
 }
 type AuthProviders struct {
-	Builtin       *BuiltinAuthProvider
-	Saml          *SAMLAuthProvider
-	Openidconnect *OpenIDConnectAuthProvider
-	HttpHeader    *HTTPHeaderAuthProvider
-	Github        *GitHubAuthProvider
-	Gitlab        *GitLabAuthProvider
+	Builtin        *BuiltinAuthProvider
+	Saml           *SAMLAuthProvider
+	Openidconnect  *OpenIDConnectAuthProvider
+	HttpHeader     *HTTPHeaderAuthProvider
+	Github         *GitHubAuthProvider
+	Gitlab         *GitLabAuthProvider
+	Bitbucketcloud *BitbucketCloudAuthProvider
 }
 
 func (v AuthProviders) MarshalJSON() ([]byte, error) {
@@ -127,6 +128,9 @@ func (v AuthProviders) MarshalJSON() ([]byte, error) {
 	if v.Gitlab != nil {
 		return json.Marshal(v.Gitlab)
 	}
+	if v.Bitbucketcloud != nil {
+		return json.Marshal(v.Bitbucketcloud)
+	}
 	return nil, errors.New("tagged union type must have exactly 1 non-nil field value")
 }
 func (v *AuthProviders) UnmarshalJSON(data []byte) error {
@@ -137,6 +141,8 @@ func (v *AuthProviders) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	switch d.DiscriminantProperty {
+	case "bitbucketcloud":
+		return json.Unmarshal(data, &v.Bitbucketcloud)
 	case "builtin":
 		return json.Unmarshal(data, &v.Builtin)
 	case "github":
@@ -150,9 +156,56 @@ func (v *AuthProviders) UnmarshalJSON(data []byte) error {
 	case "saml":
 		return json.Unmarshal(data, &v.Saml)
 	}
-	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "github", "gitlab"})
+	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "github", "gitlab", "bitbucketcloud"})
+}
+
+// AzureDevOpsAuthorization description: If non-null, enforces Azure DevOps repository permissions. Sourcegraph users are mapped to the Azure DevOps identity with the same verified email address, and can access the repositories of every project they are a member of.
+type AzureDevOpsAuthorization struct {
 }
 
+// AzureDevOpsConnection description: Configuration for a connection to Azure DevOps Services or Azure DevOps Server.
+type AzureDevOpsConnection struct {
+	// Authorization description: If non-null, enforces Azure DevOps repository permissions. Sourcegraph users are mapped to the Azure DevOps identity with the same verified email address, and can access the repositories of every project they are a member of.
+	Authorization *AzureDevOpsAuthorization `json:"authorization,omitempty"`
+	// Exclude description: A list of repositories to never mirror from Azure DevOps. Takes precedence over the "orgs" and "projects" configuration.
+	//
+	// Supports excluding by name ({"name": "myorg/myproject/myrepo"}), by ID ({"id": "a1b2c3d4-..."}) or by pattern ({"pattern": "^myorg/myproject/archive-.*"}).
+	Exclude []*ExcludedAzureDevOpsRepo `json:"exclude,omitempty"`
+	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on Azure DevOps.
+	//
+	// If "http", Sourcegraph will access Azure DevOps repositories using Git URLs of the form https://dev.azure.com/myorg/myproject/_git/myrepo.
+	//
+	// If "ssh", Sourcegraph will access Azure DevOps repositories using Git URLs of the form git@ssh.dev.azure.com:v3/myorg/myproject/myrepo. See the documentation for how to provide SSH private keys and known_hosts: https://docs.sourcegraph.com/admin/repo/auth#repositories-that-need-http-s-or-ssh-authentication.
+	GitURLType string `json:"gitURLType,omitempty"`
+	// Orgs description: An array of organization names (or collection names on Azure DevOps Server) whose repositories in all projects should be mirrored on Sourcegraph.
+	Orgs []string `json:"orgs,omitempty"`
+	// Projects description: An array of projects, in the form "org/project", whose repositories should be mirrored on Sourcegraph.
+	Projects []string `json:"projects,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to Azure DevOps.
+	RateLimit *AzureDevOpsRateLimit `json:"rateLimit,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for an Azure DevOps repository.
+	//
+	//  - "{host}" is replaced with the Azure DevOps URL's host (such as dev.azure.com), "{org}" with the organization (or collection), "{project}" with the project and "{repo}" with the repository name.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Token description: A personal access token with the "Code (Read)" and "Project and Team (Read)" scopes. If "authorization" is set, the token also needs the "Identity (Read)" scope.
+	Token string `json:"token"`
+	// Url description: URL of Azure DevOps. Use https://dev.azure.com for Azure DevOps Services, or the URL of the server without the collection for Azure DevOps Server, such as https://azuredevops.example.com/tfs.
+	Url string `json:"url"`
+	// Username description: The username sent along with the personal access token. Azure DevOps ignores it, but some proxies in front of Azure DevOps Server require it.
+	Username string `json:"username,omitempty"`
+	// WebhookSecret description: A shared secret used to authenticate incoming webhooks (minimum 12 characters). It is sent as the basic authentication password of the Azure DevOps service hooks.
+	WebhookSecret string `json:"webhookSecret,omitempty"`
+}
+
+// AzureDevOpsRateLimit description: Rate limit applied when making background API requests to Azure DevOps.
+type AzureDevOpsRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 500, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 500 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
 type BackendInsight struct {
 	// Description description: The description of this insight
 	Description string          `json:"description,omitempty"`
@@ -201,8 +254,29 @@ type BatchSpec struct {
 	Workspaces []*WorkspaceConfiguration `json:"workspaces,omitempty"`
 }
 
+// BitbucketCloudAuthProvider description: Configures the Bitbucket Cloud OAuth authentication provider for SSO. In addition to specifying this configuration object, you must also create an OAuth consumer in the settings of your Bitbucket Cloud workspace: https://support.atlassian.com/bitbucket-cloud/docs/use-oauth-on-bitbucket-cloud/. The consumer should have the `account`, `email`, `repository` and `team` permissions and the callback URL set to the concatenation of your Sourcegraph instance URL and "/.auth/bitbucketcloud/callback".
+type BitbucketCloudAuthProvider struct {
+	// AllowSignup description: Allows new visitors to sign up for accounts via Bitbucket Cloud authentication. If false, users signing in via Bitbucket Cloud must have an existing Sourcegraph account, which will be linked to their Bitbucket Cloud identity after sign-in.
+	AllowSignup *bool `json:"allowSignup,omitempty"`
+	// ClientKey description: The Key of the Bitbucket Cloud OAuth consumer.
+	ClientKey string `json:"clientKey"`
+	// ClientSecret description: The Secret of the Bitbucket Cloud OAuth consumer.
+	ClientSecret string `json:"clientSecret"`
+	// DisplayName description: The name to use when displaying this authentication provider in the UI. Defaults to an auto-generated name with the type of authentication provider and other relevant identifiers (such as a hostname).
+	DisplayName string `json:"displayName,omitempty"`
+	Type        string `json:"type"`
+	// Url description: URL of Bitbucket Cloud, such as https://bitbucket.org. Generally, admin should not modify the value of this option because Bitbucket Cloud is a public hosting platform.
+	Url string `json:"url,omitempty"`
+}
+
+// BitbucketCloudAuthorization description: If non-null, enforces Bitbucket Cloud repository permissions. This requires that there is an item in the [site configuration json](https://docs.sourcegraph.com/admin/config/site_config#auth-providers) `auth.providers` field, of type "bitbucketcloud" with the same `url` field as specified in this `BitbucketCloudConnection`.
+type BitbucketCloudAuthorization struct {
+}
+
 // BitbucketCloudConnection description: Configuration for a connection to Bitbucket Cloud.
 type BitbucketCloudConnection struct {
+	// Authorization description: If non-null, enforces Bitbucket Cloud repository permissions. This requires that there is an item in the [site configuration json](https://docs.sourcegraph.com/admin/config/site_config#auth-providers) `auth.providers` field, of type "bitbucketcloud" with the same `url` field as specified in this `BitbucketCloudConnection`.
+	Authorization *BitbucketCloudAuthorization `json:"authorization,omitempty"`
 	// ApiURL description: The API URL of Bitbucket Cloud, such as https://api.bitbucket.org. Generally, admin should not modify the value of this option because Bitbucket Cloud is a public hosting platform.
 	ApiURL string `json:"apiURL,omitempty"`
 	// AppPassword description: The app password to use when authenticating to the Bitbucket Cloud. Also set the corresponding "username" field.
@@ -273,6 +347,10 @@ type BitbucketServerConnection struct {
 	GitURLType string `json:"gitURLType,omitempty"`
 	// InitialRepositoryEnablement description: Deprecated and ignored field which will be removed entirely in the next release. BitBucket repositories can no longer be enabled or disabled explicitly.
 	InitialRepositoryEnablement bool `json:"initialRepositoryEnablement,omitempty"`
+	// MirrorTo description: EXPERIMENTAL: Push the branches and tags of the repositories from this code host to a secondary remote after each successful fetch, deleting the ones which no longer exist, for example to keep a backup copy of them on a local Git host. Failed pushes are retried with backoff, and their errors are shown on the repository's mirroring settings page.
+	MirrorTo *BitbucketServerMirrorTo `json:"mirrorTo,omitempty"`
+	// PartialClone description: EXPERIMENTAL: Clone repositories from this code host partially, to reduce the disk usage and clone time of very large monorepos. Blobs omitted from the clone are fetched from the code host on demand, e.g. when a file is read or searched. The code host must support partial clones.
+	PartialClone *BitbucketServerPartialClone `json:"partialClone,omitempty"`
 	// Password description: The password to use when authenticating to the Bitbucket Server / Bitbucket Data Center instance. Also set the corresponding "username" field.
 	//
 	// For Bitbucket Server / Bitbucket Data Center instances that support personal access tokens (Bitbucket Server / Bitbucket Data Center version 5.5 and newer), it is recommended to provide a token instead (in the "token" field).
@@ -336,6 +414,12 @@ func (v *BitbucketServerIdentityProvider) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"username"})
 }
 
+// BitbucketServerMirrorTo description: EXPERIMENTAL: Push the branches and tags of the repositories from this code host to a secondary remote after each successful fetch, deleting the ones which no longer exist, for example to keep a backup copy of them on a local Git host. Failed pushes are retried with backoff, and their errors are shown on the repository's mirroring settings page.
+type BitbucketServerMirrorTo struct {
+	// Url description: The URL of the remote to push a repository to. {repo} is replaced with the name of the repository on Sourcegraph, for example "github.com/foo/bar". Credentials may be included in the URL.
+	Url string `json:"url"`
+}
+
 // BitbucketServerOAuth description: OAuth configuration specified when creating the Bitbucket Server / Bitbucket Data Center Application Link with incoming authentication. Two Legged OAuth with 'ExecuteAs=admin' must be enabled as well as user impersonation.
 type BitbucketServerOAuth struct {
 	// ConsumerKey description: The OAuth consumer key specified when creating the Bitbucket Server / Bitbucket Data Center Application Link with incoming authentication.
@@ -344,6 +428,14 @@ type BitbucketServerOAuth struct {
 	SigningKey string `json:"signingKey"`
 }
 
+// BitbucketServerPartialClone description: EXPERIMENTAL: Clone repositories from this code host partially, to reduce the disk usage and clone time of very large monorepos. Blobs omitted from the clone are fetched from the code host on demand, e.g. when a file is read or searched. The code host must support partial clones.
+type BitbucketServerPartialClone struct {
+	// BlobSizeLimit description: Omit blobs larger than this number of bytes from clones (--filter=blob:limit=N). 0 omits all blobs.
+	BlobSizeLimit int `json:"blobSizeLimit,omitempty"`
+	// SparseSpec description: Only include the blobs of paths matching the sparse-checkout patterns stored in this blob of the repository on the code host (--filter=sparse:oid=<sparseSpec>), for example "HEAD:.sourcegraph/sparse". The code host must allow sparse filters.
+	SparseSpec string `json:"sparseSpec,omitempty"`
+}
+
 // BitbucketServerPlugin description: Configuration for Bitbucket Server / Bitbucket Data Center Sourcegraph plugin
 type BitbucketServerPlugin struct {
 	// Permissions description: Enables fetching Bitbucket Server / Bitbucket Data Center permissions through the roaring bitmap endpoint. Warning: there may be performance degradation under significant load.
@@ -534,6 +626,14 @@ type ExcludedAWSCodeCommitRepo struct {
 	// Name description: The name of an AWS CodeCommit repository ("repo-name") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
 }
+type ExcludedAzureDevOpsRepo struct {
+	// Id description: The ID of an Azure DevOps repository (as returned by the Azure DevOps API) to exclude from mirroring.
+	Id string `json:"id,omitempty"`
+	// Name description: The name of an Azure DevOps repository ("org/project/repo") to exclude from mirroring.
+	Name string `json:"name,omitempty"`
+	// Pattern description: Regular expression which matches against the name of an Azure DevOps repository ("org/project/repo").
+	Pattern string `json:"pattern,omitempty"`
+}
 type ExcludedBitbucketCloudRepo struct {
 	// Name description: The name of a Bitbucket Cloud repo ("myorg/myrepo") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
@@ -568,6 +668,14 @@ type ExcludedGitLabProject struct {
 	// Name description: The name of a GitLab project ("group/name") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
 }
+type ExcludedGiteaRepo struct {
+	// Id description: The ID of a Gitea repository (as returned by the Gitea API) to exclude from mirroring.
+	Id int `json:"id,omitempty"`
+	// Name description: The name of a Gitea repository ("owner/name") to exclude from mirroring.
+	Name string `json:"name,omitempty"`
+	// Pattern description: Regular expression which matches against the name of a Gitea repository ("owner/name").
+	Pattern string `json:"pattern,omitempty"`
+}
 type ExcludedGitoliteRepo struct {
 	// Name description: The name of a Gitolite repo ("my-repo") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
@@ -619,6 +727,8 @@ type ExperimentalFeatures struct {
 	Gerrit string `json:"gerrit,omitempty"`
 	// GitServerPinnedRepos description: List of repositories pinned to specific gitserver instances. The specified repositories will remain at their pinned servers on scaling the cluster. If the specified pinned server differs from the current server that stores the repository, then it must be re-cloned to the specified server.
 	GitServerPinnedRepos map[string]string `json:"gitServerPinnedRepos,omitempty"`
+	// GitServerReplicationFactor description: The number of gitserver instances each repository is stored on. Replicas are synced from the primary instance after each update, and reads fail over to a replica when the primary instance is unreachable. Values greater than the number of gitserver instances are capped.
+	GitServerReplicationFactor int `json:"gitServerReplicationFactor,omitempty"`
 	// GoPackages description: Allow adding Go package host connections
 	GoPackages string `json:"goPackages,omitempty"`
 	// JvmPackages description: Allow adding JVM package host connections
@@ -813,8 +923,12 @@ type GitHubConnection struct {
 	GithubAppInstallationID string `json:"githubAppInstallationID,omitempty"`
 	// InitialRepositoryEnablement description: Deprecated and ignored field which will be removed entirely in the next release. GitHub repositories can no longer be enabled or disabled explicitly. Configure repositories to be mirrored via "repos", "exclude" and "repositoryQuery" instead.
 	InitialRepositoryEnablement bool `json:"initialRepositoryEnablement,omitempty"`
+	// MirrorTo description: EXPERIMENTAL: Push the branches and tags of the repositories from this code host to a secondary remote after each successful fetch, deleting the ones which no longer exist, for example to keep a backup copy of them on a local Git host. Failed pushes are retried with backoff, and their errors are shown on the repository's mirroring settings page.
+	MirrorTo *GitHubMirrorTo `json:"mirrorTo,omitempty"`
 	// Orgs description: An array of organization names identifying GitHub organizations whose repositories should be mirrored on Sourcegraph.
 	Orgs []string `json:"orgs,omitempty"`
+	// PartialClone description: EXPERIMENTAL: Clone repositories from this code host partially, to reduce the disk usage and clone time of very large monorepos. Blobs omitted from the clone are fetched from the code host on demand, e.g. when a file is read or searched. The code host must support partial clones.
+	PartialClone *GitHubPartialClone `json:"partialClone,omitempty"`
 	// Pending description: Whether the code host connection is in a pending state.
 	Pending bool `json:"pending,omitempty"`
 	// RateLimit description: Rate limit applied when making background API requests to GitHub.
@@ -852,6 +966,20 @@ type GitHubConnection struct {
 	Webhooks []*GitHubWebhook `json:"webhooks,omitempty"`
 }
 
+// GitHubMirrorTo description: EXPERIMENTAL: Push the branches and tags of the repositories from this code host to a secondary remote after each successful fetch, deleting the ones which no longer exist, for example to keep a backup copy of them on a local Git host. Failed pushes are retried with backoff, and their errors are shown on the repository's mirroring settings page.
+type GitHubMirrorTo struct {
+	// Url description: The URL of the remote to push a repository to. {repo} is replaced with the name of the repository on Sourcegraph, for example "github.com/foo/bar". Credentials may be included in the URL.
+	Url string `json:"url"`
+}
+
+// GitHubPartialClone description: EXPERIMENTAL: Clone repositories from this code host partially, to reduce the disk usage and clone time of very large monorepos. Blobs omitted from the clone are fetched from the code host on demand, e.g. when a file is read or searched. The code host must support partial clones.
+type GitHubPartialClone struct {
+	// BlobSizeLimit description: Omit blobs larger than this number of bytes from clones (--filter=blob:limit=N). 0 omits all blobs.
+	BlobSizeLimit int `json:"blobSizeLimit,omitempty"`
+	// SparseSpec description: Only include the blobs of paths matching the sparse-checkout patterns stored in this blob of the repository on the code host (--filter=sparse:oid=<sparseSpec>), for example "HEAD:.sourcegraph/sparse". The code host must allow sparse filters.
+	SparseSpec string `json:"sparseSpec,omitempty"`
+}
+
 // GitHubRateLimit description: Rate limit applied when making background API requests to GitHub.
 type GitHubRateLimit struct {
 	// Enabled description: true if rate limiting is enabled.
@@ -912,8 +1040,12 @@ type GitLabConnection struct {
 	GitURLType string `json:"gitURLType,omitempty"`
 	// InitialRepositoryEnablement description: Deprecated and ignored field which will be removed entirely in the next release. GitLab repositories can no longer be enabled or disabled explicitly.
 	InitialRepositoryEnablement bool `json:"initialRepositoryEnablement,omitempty"`
+	// MirrorTo description: EXPERIMENTAL: Push the branches and tags of the repositories from this code host to a secondary remote after each successful fetch, deleting the ones which no longer exist, for example to keep a backup copy of them on a local Git host. Failed pushes are retried with backoff, and their errors are shown on the repository's mirroring settings page.
+	MirrorTo *GitLabMirrorTo `json:"mirrorTo,omitempty"`
 	// NameTransformations description: An array of transformations will apply to the repository name. Currently, only regex replacement is supported. All transformations happen after "repositoryPathPattern" is processed.
 	NameTransformations []*GitLabNameTransformation `json:"nameTransformations,omitempty"`
+	// PartialClone description: EXPERIMENTAL: Clone repositories from this code host partially, to reduce the disk usage and clone time of very large monorepos. Blobs omitted from the clone are fetched from the code host on demand, e.g. when a file is read or searched. The code host must support partial clones.
+	PartialClone *GitLabPartialClone `json:"partialClone,omitempty"`
 	// ProjectQuery description: An array of strings specifying which GitLab projects to mirror on Sourcegraph. Each string is a URL path and query that targets a GitLab API endpoint returning a list of projects. If the string only contains a query, then "projects" is used as the path. Examples: "?membership=true&search=foo", "groups/mygroup/projects".
 	//
 	// The special string "none" can be used as the only element to disable this feature. Projects matched by multiple query strings are only imported once. Here are a few endpoints that return a list of projects: https://docs.gitlab.com/ee/api/projects.html#list-all-projects, https://docs.gitlab.com/ee/api/groups.html#list-a-groups-projects, https://docs.gitlab.com/ee/api/search.html#scope-projects.
@@ -941,12 +1073,26 @@ type GitLabConnection struct {
 	// Webhooks description: An array of webhook configurations
 	Webhooks []*GitLabWebhook `json:"webhooks,omitempty"`
 }
+
+// GitLabMirrorTo description: EXPERIMENTAL: Push the branches and tags of the repositories from this code host to a secondary remote after each successful fetch, deleting the ones which no longer exist, for example to keep a backup copy of them on a local Git host. Failed pushes are retried with backoff, and their errors are shown on the repository's mirroring settings page.
+type GitLabMirrorTo struct {
+	// Url description: The URL of the remote to push a repository to. {repo} is replaced with the name of the repository on Sourcegraph, for example "github.com/foo/bar". Credentials may be included in the URL.
+	Url string `json:"url"`
+}
 type GitLabNameTransformation struct {
 	// Regex description: The regex to match for the occurrences of its replacement.
 	Regex string `json:"regex,omitempty"`
 	// Replacement description: The replacement used to replace all matched occurrences by the regex.
 	Replacement string `json:"replacement,omitempty"`
 }
+
+// GitLabPartialClone description: EXPERIMENTAL: Clone repositories from this code host partially, to reduce the disk usage and clone time of very large monorepos. Blobs omitted from the clone are fetched from the code host on demand, e.g. when a file is read or searched. The code host must support partial clones.
+type GitLabPartialClone struct {
+	// BlobSizeLimit description: Omit blobs larger than this number of bytes from clones (--filter=blob:limit=N). 0 omits all blobs.
+	BlobSizeLimit int `json:"blobSizeLimit,omitempty"`
+	// SparseSpec description: Only include the blobs of paths matching the sparse-checkout patterns stored in this blob of the repository on the code host (--filter=sparse:oid=<sparseSpec>), for example "HEAD:.sourcegraph/sparse". The code host must allow sparse filters.
+	SparseSpec string `json:"sparseSpec,omitempty"`
+}
 type GitLabProject struct {
 	// Id description: The ID of a GitLab project (as returned by the GitLab instance's API) to mirror.
 	Id int `json:"id,omitempty"`
@@ -966,6 +1112,50 @@ type GitLabWebhook struct {
 	Secret string `json:"secret"`
 }
 
+// GiteaAuthorization description: If non-null, enforces Gitea repository permissions. Sourcegraph users are mapped to the Gitea user with the same username, and the permissions of each user are fetched by impersonating them with the site admin token of this connection.
+type GiteaAuthorization struct {
+}
+
+// GiteaConnection description: Configuration for a connection to Gitea or Forgejo.
+type GiteaConnection struct {
+	// Authorization description: If non-null, enforces Gitea repository permissions. Sourcegraph users are mapped to the Gitea user with the same username, and the permissions of each user are fetched by impersonating them with the site admin token of this connection.
+	Authorization *GiteaAuthorization `json:"authorization,omitempty"`
+	// Exclude description: A list of repositories to never mirror from Gitea. Takes precedence over the "orgs" and "users" configuration.
+	//
+	// Supports excluding by name ({"name": "myorg/myrepo"}), by ID ({"id": 42}) or by pattern ({"pattern": "^myorg/archive-.*"}).
+	Exclude []*ExcludedGiteaRepo `json:"exclude,omitempty"`
+	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this Gitea instance.
+	//
+	// If "http", Sourcegraph will access Gitea repositories using Git URLs of the form https://gitea.example.com/myorg/myrepo.git.
+	//
+	// If "ssh", Sourcegraph will access Gitea repositories using Git URLs of the form git@gitea.example.com:myorg/myrepo.git. See the documentation for how to provide SSH private keys and known_hosts: https://docs.sourcegraph.com/admin/repo/auth#repositories-that-need-http-s-or-ssh-authentication.
+	GitURLType string `json:"gitURLType,omitempty"`
+	// Orgs description: An array of Gitea organization names whose repositories should be mirrored on Sourcegraph.
+	Orgs []string `json:"orgs,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to Gitea.
+	RateLimit *GiteaRateLimit `json:"rateLimit,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Gitea repository.
+	//
+	//  - "{host}" is replaced with the Gitea URL's host (such as gitea.example.com), and "{nameWithOwner}" is replaced with the Gitea repository's "owner/name" (such as "myorg/myrepo").
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Token description: An access token for the Gitea instance with read access to the repositories to mirror. If "authorization" is set, the token must belong to a site admin.
+	Token string `json:"token"`
+	// Url description: URL of a Gitea or Forgejo instance, such as https://gitea.example.com.
+	Url string `json:"url"`
+	// Users description: An array of Gitea usernames whose repositories should be mirrored on Sourcegraph. Only the repositories owned by each user are mirrored.
+	Users []string `json:"users,omitempty"`
+}
+
+// GiteaRateLimit description: Rate limit applied when making background API requests to Gitea.
+type GiteaRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 500, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 500 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
+
 // Github description: GitHub configuration, both for queries and receiving release webhooks.
 type Github struct {
 	// Repository description: The repository to get the latest version of.
@@ -1134,6 +1324,19 @@ type MavenRateLimit struct {
 	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 100, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 100 requests immediately, provided that the complexity cost of each request is 1.
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
+
+// MercurialConnection description: Configuration for a connection to Mercurial repositories. Sourcegraph converts each repository into a Git mirror.
+type MercurialConnection struct {
+	Repos []string `json:"repos"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for the repositories. In the pattern, the variable "{base}" is replaced with the Mercurial base URL host and path, and "{repo}" is replaced with the repository path taken from the `repos` field.
+	//
+	// For example, if your Mercurial base URL is https://hg.example.com/repos and `repos` contains the value "my/repo", then a repositoryPathPattern of "{base}/{repo}" would mean that a repository at https://hg.example.com/repos/my/repo is available on Sourcegraph at https://sourcegraph.example.com/hg.example.com/repos/my/repo.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Url description: The base URL of the Mercurial server. Credentials for HTTP basic authentication can be included in the URL.
+	Url string `json:"url"`
+}
 type Mount struct {
 	// Mountpoint description: The path in the container to mount the path on the local machine to.
 	Mountpoint string `json:"mountpoint"`
@@ -1375,7 +1578,11 @@ type OrganizationInvitations struct {
 
 // OtherExternalServiceConnection description: Configuration for a Connection to Git repositories for which an external service integration isn't yet available.
 type OtherExternalServiceConnection struct {
-	Repos []string `json:"repos"`
+	// MirrorTo description: EXPERIMENTAL: Push the branches and tags of the repositories from this code host to a secondary remote after each successful fetch, deleting the ones which no longer exist, for example to keep a backup copy of them on a local Git host. Failed pushes are retried with backoff, and their errors are shown on the repository's mirroring settings page.
+	MirrorTo *OtherMirrorTo `json:"mirrorTo,omitempty"`
+	// PartialClone description: EXPERIMENTAL: Clone repositories from this code host partially, to reduce the disk usage and clone time of very large monorepos. Blobs omitted from the clone are fetched from the code host on demand, e.g. when a file is read or searched. The code host must support partial clones.
+	PartialClone *OtherPartialClone `json:"partialClone,omitempty"`
+	Repos        []string           `json:"repos"`
 	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for the repositories. In the pattern, the variable "{base}" is replaced with the Git clone base URL host and path, and "{repo}" is replaced with the repository path taken from the `repos` field.
 	//
 	// For example, if your Git clone base URL is https://git.example.com/repos and `repos` contains the value "my/repo", then a repositoryPathPattern of "{base}/{repo}" would mean that a repository at https://git.example.com/repos/my/repo is available on Sourcegraph at https://sourcegraph.example.com/git.example.com/repos/my/repo.
@@ -1384,6 +1591,20 @@ type OtherExternalServiceConnection struct {
 	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
 	Url                   string `json:"url,omitempty"`
 }
+
+// OtherMirrorTo description: EXPERIMENTAL: Push the branches and tags of the repositories from this code host to a secondary remote after each successful fetch, deleting the ones which no longer exist, for example to keep a backup copy of them on a local Git host. Failed pushes are retried with backoff, and their errors are shown on the repository's mirroring settings page.
+type OtherMirrorTo struct {
+	// Url description: The URL of the remote to push a repository to. {repo} is replaced with the name of the repository on Sourcegraph, for example "github.com/foo/bar". Credentials may be included in the URL.
+	Url string `json:"url"`
+}
+
+// OtherPartialClone description: EXPERIMENTAL: Clone repositories from this code host partially, to reduce the disk usage and clone time of very large monorepos. Blobs omitted from the clone are fetched from the code host on demand, e.g. when a file is read or searched. The code host must support partial clones.
+type OtherPartialClone struct {
+	// BlobSizeLimit description: Omit blobs larger than this number of bytes from clones (--filter=blob:limit=N). 0 omits all blobs.
+	BlobSizeLimit int `json:"blobSizeLimit,omitempty"`
+	// SparseSpec description: Only include the blobs of paths matching the sparse-checkout patterns stored in this blob of the repository on the code host (--filter=sparse:oid=<sparseSpec>), for example "HEAD:.sourcegraph/sparse". The code host must allow sparse filters.
+	SparseSpec string `json:"sparseSpec,omitempty"`
+}
 type OutputVariable struct {
 	// Format description: The expected format of the output. If set, the output is being parsed in that format before being stored in the var. If not set, 'text' is assumed to the format.
 	Format string `json:"format,omitempty"`
@@ -1647,10 +1868,18 @@ type SearchLimits struct {
 	CommitDiffMaxRepos int `json:"commitDiffMaxRepos,omitempty"`
 	// CommitDiffWithTimeFilterMaxRepos description: The maximum number of repositories to search across when doing a "type:diff" or "type:commit" with a "after:" or "before:" filter. The user is prompted to narrow their query if the limit is exceeded. There is a separate limit (commitDiffMaxRepos) when "after:" or "before:" is not specified because those queries are slower. Defaults to 10000.
 	CommitDiffWithTimeFilterMaxRepos int `json:"commitDiffWithTimeFilterMaxRepos,omitempty"`
+	// MaxCost description: The maximum estimated cost of a search. The cost estimates the load a search puts on searcher: the number of repository revisions searched without an index, weighted by the complexity of the search pattern. Searches above the budget are handled according to overCostAction. Any value less than or equal to zero means unlimited.
+	MaxCost int `json:"maxCost,omitempty"`
 	// MaxRepos description: The maximum number of repositories to search across. The user is prompted to narrow their query if exceeded. Any value less than or equal to zero means unlimited.
 	MaxRepos int `json:"maxRepos,omitempty"`
 	// MaxTimeoutSeconds description: The maximum value for "timeout:" that search will respect. "timeout:" values larger than maxTimeoutSeconds are capped at maxTimeoutSeconds. Note: You need to ensure your load balancer / reverse proxy in front of Sourcegraph won't timeout the request for larger values. Note: Too many large rearch requests may harm Soucregraph for other users. Defaults to 1 minute.
 	MaxTimeoutSeconds int `json:"maxTimeoutSeconds,omitempty"`
+	// OverCostAction description: What to do with searches whose estimated cost exceeds the budget of the user. "reject" returns an alert asking the user to narrow the search. "queue" runs such searches one at a time per frontend instance. Defaults to "reject".
+	OverCostAction string `json:"overCostAction,omitempty"`
+	// OverCostQueueSize description: The maximum number of searches over their cost budget that wait to run on each frontend instance when overCostAction is "queue". Searches over their budget are rejected while the queue is full. Defaults to 10.
+	OverCostQueueSize int `json:"overCostQueueSize,omitempty"`
+	// UserMaxCost description: Per-user overrides of maxCost, keyed by username. Any value less than or equal to zero means unlimited for that user.
+	UserMaxCost map[string]int `json:"userMaxCost,omitempty"`
 }
 type SearchSavedQueries struct {
 	// Description description: Description of this saved query
@@ -1750,6 +1979,8 @@ type Settings struct {
 	SearchIncludeArchived *bool `json:"search.includeArchived,omitempty"`
 	// SearchIncludeForks description: Whether searches should include searching forked repositories.
 	SearchIncludeForks *bool `json:"search.includeForks,omitempty"`
+	// SearchMacros description: Named query fragments that can be referenced in a search query as `@name`. For example, defining `"vendored-excludes": "-file:vendor/ -file:node_modules/"` lets you write `foo @vendored-excludes`. Macros may refer to other macros, but not to themselves. Macros defined in user settings override organization and global macros of the same name.
+	SearchMacros map[string]string `json:"search.macros,omitempty"`
 	// SearchMigrateParser description: REMOVED. Previously, a flag to enable and/or-expressions in queries as an aid transition to new language features in versions <= 3.24.0.
 	SearchMigrateParser *bool `json:"search.migrateParser,omitempty"`
 	// SearchRepositoryGroups description: DEPRECATED: Use search contexts instead.
@@ -2124,6 +2355,38 @@ type SubRepoPermissions struct {
 	UserCacheTTLSeconds int `json:"userCacheTTLSeconds,omitempty"`
 }
 
+// SubversionConnection description: Configuration for a connection to a Subversion server. Sourcegraph imports each repository into a Git mirror with git svn.
+type SubversionConnection struct {
+	// Authors description: Maps Subversion usernames to Git authors of the form "Name <email>". Commits of users who are not listed are attributed to "username <username>".
+	Authors map[string]string `json:"authors,omitempty"`
+	// Layout description: The paths of trunk, branches and tags relative to the root of each repository. If omitted, the standard layout with trunk, branches and tags directories is used. Paths which are not set are not imported, so `{}` imports the whole repository as a single branch.
+	Layout *SubversionLayout `json:"layout,omitempty"`
+	// Password description: The password used to authenticate to the Subversion server.
+	Password string `json:"password,omitempty"`
+	// Repos description: The paths of the repositories relative to the base URL. If omitted, every directory directly below the base URL is synced as a repository.
+	Repos []string `json:"repos,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for the repositories. In the pattern, the variable "{base}" is replaced with the Subversion base URL host and path, and "{repo}" is replaced with the repository path relative to the base URL.
+	//
+	// For example, if your Subversion base URL is https://svn.example.com/repos and a repository is at "my/repo", then a repositoryPathPattern of "{base}/{repo}" would mean that the repository is available on Sourcegraph at https://sourcegraph.example.com/svn.example.com/repos/my/repo.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Url description: The base URL of the Subversion server. Repositories are listed relative to this URL.
+	Url string `json:"url"`
+	// Username description: The username used to authenticate to the Subversion server.
+	Username string `json:"username,omitempty"`
+}
+
+// SubversionLayout description: The paths of trunk, branches and tags relative to the root of each repository. If omitted, the standard layout with trunk, branches and tags directories is used. Paths which are not set are not imported, so `{}` imports the whole repository as a single branch.
+type SubversionLayout struct {
+	// Branches description: The path of the directory containing branches.
+	Branches string `json:"branches,omitempty"`
+	// Tags description: The path of the directory containing tags.
+	Tags string `json:"tags,omitempty"`
+	// Trunk description: The path of the main line of development, which is imported as the master branch.
+	Trunk string `json:"trunk,omitempty"`
+}
+
 // SyntaxHighlighting description: Syntax highlighting configuration
 type SyntaxHighlighting struct {
 	Engine    SyntaxHighlightingEngine   `json:"engine"`
@@ -6,6 +6,10 @@ import _ "embed"
 //go:embed aws_codecommit.schema.json
 var AWSCodeCommitSchemaJSON string
 
+// AzureDevOpsSchemaJSON is the content of the file "azuredevops.schema.json".
+//go:embed azuredevops.schema.json
+var AzureDevOpsSchemaJSON string
+
 // BatchSpecSchemaJSON is the content of the file "batch_spec.schema.json".
 //go:embed batch_spec.schema.json
 var BatchSpecSchemaJSON string
@@ -26,6 +30,10 @@ var ChangesetSpecSchemaJSON string
 //go:embed gerrit.schema.json
 var GerritSchemaJSON string
 
+// GiteaSchemaJSON is the content of the file "gitea.schema.json".
+//go:embed gitea.schema.json
+var GiteaSchemaJSON string
+
 // GitHubSchemaJSON is the content of the file "github.schema.json".
 //go:embed github.schema.json
 var GitHubSchemaJSON string
@@ -46,6 +54,10 @@ var GoModulesSchemaJSON string
 //go:embed jvm-packages.schema.json
 var JVMPackagesSchemaJSON string
 
+// MercurialSchemaJSON is the content of the file "mercurial.schema.json".
+//go:embed mercurial.schema.json
+var MercurialSchemaJSON string
+
 // NpmPackagesSchemaJSON is the content of the file "npm-packages.schema.json".
 //go:embed npm-packages.schema.json
 var NpmPackagesSchemaJSON string
@@ -74,6 +86,10 @@ var PhabricatorSchemaJSON string
 //go:embed pagure.schema.json
 var PagureSchemaJSON string
 
+// SubversionSchemaJSON is the content of the file "subversion.schema.json".
+//go:embed subversion.schema.json
+var SubversionSchemaJSON string
+
 // SettingsSchemaJSON is the content of the file "settings.schema.json".
 //go:embed settings.schema.json
 var SettingsSchemaJSON string